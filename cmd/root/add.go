@@ -0,0 +1,164 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <task> <duration> [note]",
+	Short: "Add an ad-hoc entry",
+	Long: `
+Add an entry of work done outside any tracker, described by a one-liner: the
+task and the spent duration in any order, followed by the note. The entry ends
+at the time of adding it.
+
+The entry is staged and uploaded by the next sync, together with the fetched
+entries, or by the server mode. When --now is set, the entry is uploaded
+immediately instead.
+
+Example:
+
+  minutes add "CPT-2014 1h30m fixed the flaky test"`,
+	Args:   cobra.MinimumNArgs(1),
+	PreRun: bindCmdFlags,
+	Run:    runAddCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().BoolP("now", "", false, "upload the entry immediately instead of staging it")
+	addCmd.Flags().StringP("add-project", "", "", "set the project of the entry")
+	addCmd.Flags().StringP("add-client", "", "", "set the client of the entry")
+}
+
+// fetchEntriesWithStaged fetches the entries from the configured source and
+// transforms them together with the staged entries starting within the
+// period. The fetch and the transformation are limited by the deadlines of
+// their phases.
+func fetchEntriesWithStaged(ctx context.Context, stage *staging.Stage, start time.Time, end time.Time) (worklog.Entries, error) {
+	fetchCtx, cancelFetch := withDeadline(ctx, "fetch-deadline")
+	defer cancelFetch()
+
+	entries, err := fetchRawEntries(fetchCtx, start, end)
+	if err != nil {
+		return nil, checkDeadline(fetchCtx, "fetch-deadline", err)
+	}
+
+	stagedEntries, err := stage.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchOpts := &client.FetchOpts{Start: start, End: end}
+	entries = append(entries, fetchOpts.FilterEntries(stagedEntries)...)
+
+	resolveCtx, cancelResolve := withDeadline(ctx, "resolve-deadline")
+	defer cancelResolve()
+
+	entries, err = transformEntries(resolveCtx, entries)
+	if err != nil {
+		return nil, checkDeadline(resolveCtx, "resolve-deadline", err)
+	}
+
+	return entries, nil
+}
+
+// uploadAddedEntry transforms and uploads the added entry without staging it.
+func uploadAddedEntry(cmd *cobra.Command, entry *worklog.Entry) {
+	validateFlags()
+
+	ctx := context.Background()
+
+	uploader, err := getUploader()
+	cobra.CheckErr(err)
+	cobra.CheckErr(warmUpCredentials(ctx, uploader))
+
+	resolveCtx, cancelResolve := withDeadline(ctx, "resolve-deadline")
+	defer cancelResolve()
+
+	entries, err := transformEntries(resolveCtx, worklog.Entries{*entry})
+	cobra.CheckErr(checkDeadline(resolveCtx, "resolve-deadline", err))
+
+	start := entry.Start
+	end := entry.Start.Add(entry.BillableDuration)
+
+	wl := newWorklog(entries)
+	completeEntries := wl.CompleteEntries()
+
+	printEntries(start, end, completeEntries, wl.IncompleteEntries())
+
+	if len(wl.IncompleteEntries()) != 0 {
+		cobra.CheckErr(tr("the entry is incomplete, set its project and client or stage it"))
+	}
+
+	cobra.CheckErr(checkLimits(uploader, completeEntries))
+
+	if viper.GetBool("dry-run") {
+		recordRun(start, end, completeEntries, nil)
+		reportUsage(cmd, len(completeEntries))
+		return
+	}
+
+	uploadCtx, cancelUpload := withDeadline(ctx, "upload-deadline")
+	defer cancelUpload()
+
+	uploadErrors := uploadEntries(uploadCtx, uploader, completeEntries, getUploadOpts())
+	for i := range uploadErrors {
+		uploadErrors[i] = checkDeadline(uploadCtx, "upload-deadline", uploadErrors[i])
+	}
+
+	recordRun(start, end, completeEntries, uploadErrors)
+
+	if len(uploadErrors) != 0 {
+		printUploadErrors(uploadErrors)
+		reportUsage(cmd, len(completeEntries), uploadErrors...)
+		os.Exit(1)
+	}
+
+	fmt.Print(tr("\nSuccessfully uploaded %d worklog entries!\n", len(completeEntries)))
+
+	if err = writeCalendarEvents(ctx, completeEntries); err != nil {
+		fmt.Print(tr("\nFailed to write the uploaded entries to the calendar: %v\n", err))
+	}
+
+	reportUsage(cmd, len(completeEntries))
+}
+
+func runAddCmd(cmd *cobra.Command, args []string) {
+	request, err := staging.ParseOneLiner(strings.Join(args, " "))
+	cobra.CheckErr(err)
+
+	request.Project = viper.GetString("add-project")
+	request.Client = viper.GetString("add-client")
+
+	entry, err := request.Entry(time.Now())
+	cobra.CheckErr(err)
+
+	if viper.GetBool("now") {
+		uploadAddedEntry(cmd, entry)
+		return
+	}
+
+	stage, err := getStage()
+	cobra.CheckErr(err)
+	cobra.CheckErr(stage.Add(context.Background(), *entry))
+
+	fmt.Print(tr(
+		"Staged entry #%s: %s %s %s\nThe entry is uploaded by the next sync.\n",
+		entry.Provenance.SourceIDs[0],
+		entry.Task.Name,
+		entry.BillableDuration,
+		entry.Summary,
+	))
+}
@@ -0,0 +1,105 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// loadHistoryTotals returns the daily totals of the syncs in the history since
+// the date. The days synced multiple times are set by their latest sync. Dry
+// runs are skipped, as those may be experiments.
+func loadHistoryTotals(ctx context.Context, store storage.Store, since time.Time) (worklog.DailyTotals, error) {
+	totals := worklog.DailyTotals{}
+
+	objects, err := store.List(ctx, storage.PrefixHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history is named by the time of the syncs, so the older syncs are
+	// skipped without reading them
+	from := since.UTC().Format(summaryKeyFormat)
+
+	for _, object := range objects {
+		if strings.TrimSuffix(path.Base(object.Key), ".json") < from {
+			continue
+		}
+
+		data, err := store.Get(ctx, object.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		var summary runSummary
+		if err = json.Unmarshal(data, &summary); err != nil {
+			return nil, fmt.Errorf("invalid history %s: %v", object.Key, err)
+		}
+
+		if !summary.DryRun {
+			totals.Update(summary.Entries, summary.Start, summary.End)
+		}
+	}
+
+	return totals.Since(since), nil
+}
+
+// warnAnomalies prints a warning for every day of the period which total
+// differs wildly from the typical total of the history, like when the tracking
+// was forgotten or a timer was left running. The days of the period are not
+// part of the typical total, even if those were synced before.
+func warnAnomalies(entries worklog.Entries, start time.Time, end time.Time) {
+	ctx := context.Background()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	today, err := utils.GetTime("", "")
+	cobra.CheckErr(err)
+
+	history, err := loadHistoryTotals(ctx, store, today.AddDate(0, 0, -viper.GetInt("anomaly-history-days")))
+	cobra.CheckErr(err)
+	history.Update(nil, start, end)
+
+	totals := worklog.DailyTotals{}
+	totals.Update(entries, start, end)
+
+	anomalies := worklog.DetectAnomalies(totals, history, &worklog.AnomalyOpts{
+		Factor:  viper.GetFloat64("anomaly-factor"),
+		MinDays: viper.GetInt("anomaly-min-days"),
+		Today:   today,
+	})
+
+	reportLocale := getLocale()
+
+	for _, anomaly := range anomalies {
+		date, err := time.ParseInLocation("2006-01-02", anomaly.Date, time.Local)
+		cobra.CheckErr(err)
+
+		reason := "was the tracking forgotten?"
+		if anomaly.IsHigh() {
+			reason = "was a timer left running?"
+		}
+
+		fmt.Printf(
+			"Unusual day %s: %s spent, while a typical day is %s; %s\n",
+			reportLocale.FormatDate(date),
+			anomaly.Total,
+			anomaly.Typical,
+			reason,
+		)
+	}
+
+	if len(anomalies) != 0 {
+		fmt.Println()
+	}
+}
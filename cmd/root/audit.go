@@ -0,0 +1,46 @@
+package root
+
+import (
+	"os"
+	"sync"
+
+	"github.com/gabor-boros/minutes/internal/pkg/audit"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	auditLoggerOnce sync.Once
+	auditLogger     *audit.Logger
+)
+
+// getAuditLoggerOpts returns the audit log options set by flags.
+func getAuditLoggerOpts() *audit.LoggerOpts {
+	return &audit.LoggerOpts{
+		Path:        viper.GetString("audit-log"),
+		MaxSize:     viper.GetInt64("audit-log-max-size") * 1024 * 1024,
+		MaxBackups:  viper.GetInt("audit-log-max-backups"),
+		SyslogURL:   viper.GetString("audit-log-syslog"),
+		Target:      viper.GetString("target"),
+		ErrorOutput: os.Stderr,
+	}
+}
+
+// getMutationRecorder returns the audit logger recording the mutating calls of
+// the uploaders. The audit log is opened once and kept open, so the long
+// running commands append to the same log. If no audit log is set, nil
+// returns.
+func getMutationRecorder() client.MutationRecorder {
+	if viper.GetString("audit-log") == "" {
+		return nil
+	}
+
+	auditLoggerOnce.Do(func() {
+		var err error
+		auditLogger, err = audit.NewLogger(getAuditLoggerOpts())
+		cobra.CheckErr(err)
+	})
+
+	return auditLogger
+}
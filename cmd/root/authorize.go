@@ -0,0 +1,145 @@
+package root
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/googlecalendar"
+	"github.com/gabor-boros/minutes/internal/pkg/client/outlook"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var authorizeGoogleCalendarCmd = &cobra.Command{
+	Use:   "authorize-googlecalendar",
+	Short: "Authorize reading the Google Calendar events",
+	Long: `
+Authorize the OAuth client set by --googlecalendar-client-id to read the
+calendar events of the user, using the device authorization flow.
+
+Visit the printed URL on any device and enter the printed code. After the
+authorization is granted, the refresh token is printed, which must be set by
+--googlecalendar-refresh-token.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		runDeviceAuthorization("googlecalendar", googlecalendar.Scope)
+	},
+}
+
+var authorizeOutlookCmd = &cobra.Command{
+	Use:   "authorize-outlook",
+	Short: "Authorize reading the Outlook calendar events",
+	Long: `
+Authorize the OAuth client set by --outlook-client-id to read the calendar
+events of the user through Microsoft Graph, using the device authorization
+flow.
+
+Visit the printed URL on any device and enter the printed code. After the
+authorization is granted, the refresh token is printed, which must be set by
+--outlook-refresh-token.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		runDeviceAuthorization("outlook", outlook.Scope)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authorizeGoogleCalendarCmd)
+	rootCmd.AddCommand(authorizeOutlookCmd)
+}
+
+// runDeviceAuthorization authorizes the OAuth client of the source by the
+// device authorization flow, then prints the issued refresh token. The OAuth
+// settings are read from the "<source>-" prefixed flags.
+func runDeviceAuthorization(source string, scope string) {
+	if viper.GetString(source+"-client-id") == "" {
+		cobra.CheckErr(fmt.Sprintf("%s client id must be set", source))
+	}
+
+	ctx := context.Background()
+	httpClient := &client.HTTPClient{}
+
+	oauthOpts := &client.OAuthOpts{
+		TokenURL:      viper.GetString(source + "-token-url"),
+		DeviceCodeURL: viper.GetString(source + "-device-code-url"),
+		ClientID:      viper.GetString(source + "-client-id"),
+		ClientSecret:  viper.GetString(source + "-client-secret"),
+		Scope:         scope,
+		Timeout:       client.DefaultRequestTimeout,
+	}
+
+	deviceCode, err := client.RequestDeviceCode(ctx, httpClient, oauthOpts)
+	cobra.CheckErr(err)
+
+	fmt.Printf("Visit %s and enter the code %s\n", deviceCode.Verification(), deviceCode.UserCode)
+	fmt.Println("Waiting for the authorization...")
+
+	token, err := client.PollDeviceToken(ctx, httpClient, oauthOpts, deviceCode)
+	cobra.CheckErr(err)
+
+	if token.RefreshToken == "" {
+		cobra.CheckErr("no refresh token was issued")
+	}
+
+	fmt.Println()
+	fmt.Println("Authorization granted. Set the refresh token in the config:")
+	fmt.Println()
+	fmt.Printf("%s-refresh-token = %q\n", source, token.RefreshToken)
+}
+
+// rotatedTokenState represents the latest refresh token of an OAuth client,
+// and the hash of the configured refresh token it was rotated from. If the
+// configured refresh token changes, like after authorizing the client again,
+// the kept refresh token is discarded.
+type rotatedTokenState struct {
+	RotatedFrom  string `json:"rotated_from"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// hashRefreshToken returns the hash of the refresh token, so the configured
+// token is not persisted.
+func hashRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(hash[:])
+}
+
+// saveRefreshToken persists the latest refresh token, rotated from the
+// configured one, by the storage key.
+func saveRefreshToken(ctx context.Context, store storage.Store, key string, configured string, refreshToken string) error {
+	data, err := json.Marshal(&rotatedTokenState{
+		RotatedFrom:  hashRefreshToken(configured),
+		RefreshToken: refreshToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, key, data)
+}
+
+// loadRefreshToken returns the latest refresh token rotated from the
+// configured one, persisted by the storage key. If no token was rotated yet,
+// the configured one returns.
+func loadRefreshToken(ctx context.Context, store storage.Store, key string, configured string) (string, error) {
+	data, err := store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return configured, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var state rotatedTokenState
+	if err = json.Unmarshal(data, &state); err != nil {
+		return "", err
+	}
+
+	if state.RotatedFrom != hashRefreshToken(configured) || state.RefreshToken == "" {
+		return configured, nil
+	}
+
+	return state.RefreshToken, nil
+}
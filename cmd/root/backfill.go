@@ -0,0 +1,351 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/backfill"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Sync a large date range chunk by chunk",
+	Long: `
+Sync the entries of a large date range, like a year of history, by splitting
+the range into chunks of a week and syncing them sequentially. The entries are
+uploaded without confirmation per chunk, after confirming the backfill once.
+
+The progress is saved to a progress file after every uploaded entry. If the
+backfill is interrupted, running the same command again resumes it: the
+completed chunks are skipped and the already uploaded entries of the
+interrupted chunk are not uploaded again.
+
+When --validate-remote is set, the entries of the whole range are fetched and
+the issues, accounts and attributes referenced by them are validated against
+the target before any upload begins. If the target would reject any of them,
+the backfill is not started.
+
+Example:
+
+  minutes backfill --start 2023-01-01 --end 2023-12-31 --range-end inclusive`,
+	PreRun: bindCmdFlags,
+	Run:    runBackfillCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(backfillCmd)
+
+	backfillCmd.Flags().IntP("backfill-chunk-days", "", backfill.DefaultChunkDays, "set the number of days synced at once")
+	backfillCmd.Flags().DurationP("backfill-chunk-delay", "", time.Second*5, "set the time waited between the chunks to throttle the requests")
+	backfillCmd.Flags().StringP("backfill-progress-file", "", "", "set the path of the progress file; defaults to a file per range in the config directory")
+	backfillCmd.Flags().BoolP("validate-remote", "", false, "validate the entries of the whole range against the target before uploading")
+}
+
+// getBackfillProgressPath returns the path of the progress file. If not set,
+// the progress file is named after the range, so the backfills of different
+// ranges do not mix.
+func getBackfillProgressPath(start time.Time, end time.Time) string {
+	if path := viper.GetString("backfill-progress-file"); path != "" {
+		return path
+	}
+
+	name := fmt.Sprintf("%s_%s.json", start.Format("20060102T150405"), end.Format("20060102T150405"))
+	return filepath.Join(getDefaultStoragePath(), "backfill", name)
+}
+
+// loadBackfillProgress returns the progress of the backfill saved to the
+// progress file. If the file does not exist, a new progress returns.
+func loadBackfillProgress(path string, start time.Time, end time.Time, chunkDays int) (*backfill.Progress, error) {
+	progress, err := backfill.LoadProgress(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return backfill.NewProgress(start, end, chunkDays), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = progress.Check(start, end, chunkDays); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+
+	return progress, nil
+}
+
+// backfillResult represents the outcome of syncing a chunk.
+type backfillResult struct {
+	// synced is the number of the entries uploaded, or in case of dry-run,
+	// the number of the entries that would be uploaded.
+	synced       int
+	incomplete   int
+	uploadErrors []error
+}
+
+// validateBackfill fetches the entries of the pending chunks and validates
+// the entries not uploaded yet against the target. The fetched entries are
+// returned by the keys of the chunks, so they are not fetched again.
+func validateBackfill(ctx context.Context, uploader client.Uploader, progress *backfill.Progress, chunks []backfill.Chunk, chunkDelay time.Duration) (map[string]worklog.Entries, *client.RemoteReport, error) {
+	chunkEntries := map[string]worklog.Entries{}
+	var pendingEntries worklog.Entries
+
+	for i, chunk := range chunks {
+		if progress.IsCompleted(chunk) {
+			continue
+		}
+
+		if len(chunkEntries) != 0 {
+			time.Sleep(chunkDelay)
+		}
+
+		fmt.Print(tr("Fetching chunk %d of %d for validation...\n", i+1, len(chunks)))
+
+		entries, err := fetchEntries(ctx, chunk.Start, chunk.End)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		chunkEntries[chunk.Key()] = entries
+
+		wl := newWorklog(entries)
+		pendingEntries = append(pendingEntries, progress.Ledger.Pending(wl.CompleteEntries())...)
+	}
+
+	if err := warmUpCredentials(ctx, uploader); err != nil {
+		return nil, nil, err
+	}
+
+	report, err := client.ValidateRemote(ctx, uploader, pendingEntries, getUploadOpts())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return chunkEntries, report, nil
+}
+
+// printRemoteReport prints the number of checked values per kind and the
+// values the target would reject, followed by the go/no-go decision.
+func printRemoteReport(report *client.RemoteReport) {
+	checkedValues := map[string]int{}
+	for _, check := range report.Checks {
+		checkedValues[check.Kind]++
+	}
+
+	fmt.Print(tr(
+		"\nValidated %d issues, %d accounts and %d attributes against the %s target.\n\n",
+		checkedValues[client.RemoteCheckIssue],
+		checkedValues[client.RemoteCheckAccount],
+		checkedValues[client.RemoteCheckAttribute],
+		viper.GetString("target"),
+	))
+
+	if problems := report.Problems(); len(problems) != 0 {
+		writer := newTableWriter()
+		writer.SetOutputMirror(os.Stdout)
+		writer.SetStyle(getTableStyle())
+		writer.SetTitle(tr("Remote validation problems"))
+		writer.AppendHeader(table.Row{tr("Kind"), tr("Value"), tr("Entries"), tr("Problem")})
+
+		for _, problem := range problems {
+			writer.AppendRow(table.Row{problem.Kind, problem.Value, problem.Entries, problem.Problem})
+		}
+
+		writer.Render()
+		fmt.Println()
+	}
+
+	if report.IsGo() {
+		fmt.Print(tr("GO: the target accepts every issue, account and attribute.\n\n"))
+	} else {
+		fmt.Print(tr("NO-GO: the target would reject %d values, fix them before the backfill.\n\n", len(report.Problems())))
+	}
+}
+
+// backfillChunk uploads the complete entries of the chunk not uploaded yet one
+// by one, saving the progress after every uploaded entry. The chunk is marked
+// as completed only if every entry was uploaded.
+func backfillChunk(ctx context.Context, uploader client.Uploader, progress *backfill.Progress, progressPath string, chunk backfill.Chunk, entries worklog.Entries) (*backfillResult, error) {
+	if err := warmUpCredentials(ctx, uploader); err != nil {
+		return nil, err
+	}
+
+	wl := newWorklog(entries)
+	pendingEntries := progress.Ledger.Pending(wl.CompleteEntries())
+
+	if err := checkLimits(uploader, pendingEntries); err != nil {
+		return nil, err
+	}
+
+	result := &backfillResult{incomplete: len(wl.IncompleteEntries())}
+
+	if viper.GetBool("dry-run") {
+		result.synced = len(pendingEntries)
+		return result, nil
+	}
+
+	uploadOpts := getUploadOpts()
+
+	for _, entry := range pendingEntries {
+		if errs := uploadEntries(ctx, uploader, worklog.Entries{entry}, uploadOpts); len(errs) != 0 {
+			result.uploadErrors = append(result.uploadErrors, errs...)
+			continue
+		}
+
+		result.synced++
+
+		progress.Ledger.Record(entry)
+		if err := progress.Save(progressPath); err != nil {
+			return nil, err
+		}
+	}
+
+	recordRun(chunk.Start, chunk.End, pendingEntries, result.uploadErrors)
+
+	if len(result.uploadErrors) == 0 {
+		progress.Complete(chunk, time.Now())
+		if err := progress.Save(progressPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func runBackfillCmd(cmd *cobra.Command, _ []string) {
+	validateFlags()
+
+	if viper.GetString("start") == "" || viper.GetString("end") == "" {
+		cobra.CheckErr(tr("backfill start and end must be set"))
+	}
+
+	chunkDays := viper.GetInt("backfill-chunk-days")
+	if chunkDays <= 0 {
+		cobra.CheckErr(tr("backfill chunk days must be positive"))
+	}
+
+	chunkDelay := viper.GetDuration("backfill-chunk-delay")
+	if chunkDelay < 0 {
+		cobra.CheckErr(tr("backfill chunk delay must not be negative"))
+	}
+
+	start, end := getTimeRange()
+	chunks := backfill.SplitRange(start, end, chunkDays)
+	progressPath := getBackfillProgressPath(start, end)
+
+	progress, err := loadBackfillProgress(progressPath, start, end, chunkDays)
+	cobra.CheckErr(err)
+
+	completedChunks := 0
+	for _, chunk := range chunks {
+		if progress.IsCompleted(chunk) {
+			completedChunks++
+		}
+	}
+
+	uploader, err := getUploader()
+	cobra.CheckErr(err)
+
+	reportLocale := getLocale()
+
+	fmt.Print(tr(
+		"Backfilling %s - %s in %d chunks, %d of them are already completed.\nThe progress is saved to %s.\n\n",
+		reportLocale.FormatDateTime(start.Local()),
+		reportLocale.FormatDateTime(end.Local()),
+		len(chunks),
+		completedChunks,
+		progressPath,
+	))
+
+	if completedChunks == len(chunks) {
+		fmt.Println(tr("The backfill is already completed."))
+		return
+	}
+
+	ctx := context.Background()
+
+	var chunkEntries map[string]worklog.Entries
+	if viper.GetBool("validate-remote") {
+		var report *client.RemoteReport
+
+		chunkEntries, report, err = validateBackfill(ctx, uploader, progress, chunks, chunkDelay)
+		cobra.CheckErr(err)
+
+		printRemoteReport(report)
+		if !report.IsGo() {
+			reportUsage(cmd, 0)
+			os.Exit(1)
+		}
+	}
+
+	if strings.ToLower(utils.Prompt(tr("Continue? [y/n]: "))) != "y" {
+		fmt.Println(tr("User interruption. Aborting."))
+		os.Exit(0)
+	}
+
+	uploadedEntries := 0
+	isFirstChunk := true
+
+	for i, chunk := range chunks {
+		if progress.IsCompleted(chunk) {
+			continue
+		}
+
+		if !isFirstChunk {
+			time.Sleep(chunkDelay)
+		}
+		isFirstChunk = false
+
+		fmt.Printf(
+			"[%d/%d] %s - %s: ",
+			i+1,
+			len(chunks),
+			reportLocale.FormatDateTime(chunk.Start.Local()),
+			reportLocale.FormatDateTime(chunk.End.Local()),
+		)
+
+		// The chunks fetched for validation are not fetched again
+		entries, isFetched := chunkEntries[chunk.Key()]
+		if !isFetched {
+			entries, err = fetchEntries(ctx, chunk.Start, chunk.End)
+		}
+
+		var result *backfillResult
+		if err == nil {
+			result, err = backfillChunk(ctx, uploader, progress, progressPath, chunk, entries)
+		}
+
+		if err != nil {
+			fmt.Print(tr("failed\n\n%v\n\nRun the same command again to resume the backfill.\n", err))
+			reportUsage(cmd, uploadedEntries, err)
+			os.Exit(1)
+		}
+
+		uploadedEntries += result.synced
+
+		fmt.Print(tr("%d entries synced, %d incomplete\n", result.synced, result.incomplete))
+
+		if len(result.uploadErrors) != 0 {
+			printUploadErrors(result.uploadErrors)
+			fmt.Println(tr("\nRun the same command again to resume the backfill."))
+			reportUsage(cmd, uploadedEntries, result.uploadErrors...)
+			os.Exit(1)
+		}
+	}
+
+	if viper.GetBool("dry-run") {
+		fmt.Print(tr("\nDry run completed, %d worklog entries would be synced.\n", uploadedEntries))
+		reportUsage(cmd, uploadedEntries)
+		return
+	}
+
+	fmt.Print(tr("\nSuccessfully backfilled %d worklog entries!\n", uploadedEntries))
+	reportUsage(cmd, uploadedEntries)
+}
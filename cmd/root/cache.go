@@ -0,0 +1,170 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// rawCacheKey is the storage key of the cached raw entries.
+	rawCacheKey string = storage.PrefixCache + "raw.json"
+)
+
+// rawCache represents the entries fetched from the source before transforming
+// them, and the parameters of the fetch. Since the tasks are extracted by the
+// pipeline, the cached entries keep their task hints.
+type rawCache struct {
+	Source    string          `json:"source"`
+	User      string          `json:"user,omitempty"`
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Entries   worklog.Entries `json:"entries"`
+}
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch and print the entries without uploading them",
+	Long: `
+Fetch the entries from the source, transform them the same way as during the
+sync and print them without uploading.
+
+When --cache-raw is set, the fetched entries are stored in the storage before
+transforming them, so the transformation can be re-run by the transform
+command without fetching the entries again.`,
+	PreRun: bindCmdFlags,
+	Run:    runFetchCmd,
+}
+
+var transformCmd = &cobra.Command{
+	Use:   "transform",
+	Short: "Transform and print the entries without uploading them",
+	Long: `
+Transform the entries and print them without uploading. The entries are
+mapped, looked up in Jira and reallocated using the current configuration.
+
+When --from-cache is set, the entries cached by "fetch --cache-raw" are
+transformed instead of fetching them from the source, which allows fast
+iteration on the configuration against real data.`,
+	PreRun: bindCmdFlags,
+	Run:    runTransformCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(transformCmd)
+
+	fetchCmd.Flags().BoolP("cache-raw", "", false, "store the fetched entries in the storage before transforming them")
+	transformCmd.Flags().BoolP("from-cache", "", false, "transform the entries cached by \"fetch --cache-raw\" instead of fetching them")
+}
+
+// loadRawCache returns the raw entries cached in the store.
+func loadRawCache(ctx context.Context, store storage.Store) (*rawCache, error) {
+	data, err := store.Get(ctx, rawCacheKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errors.New("no entries cached, run \"fetch --cache-raw\" first")
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cache rawCache
+	if err = json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+// saveRawCache persists the raw entries in the store.
+func saveRawCache(ctx context.Context, store storage.Store, cache *rawCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, rawCacheKey, data)
+}
+
+// printTransformedEntries transforms the raw entries and prints them.
+func printTransformedEntries(entries worklog.Entries, start time.Time, end time.Time) {
+	entries, err := transformEntries(context.Background(), entries)
+	cobra.CheckErr(err)
+
+	wl := newWorklog(entries)
+	printEntries(start, end, wl.CompleteEntries(), wl.IncompleteEntries())
+
+	if viper.GetBool("verbose") {
+		printProvenance(wl.CompleteEntries())
+	}
+}
+
+func runFetchCmd(_ *cobra.Command, _ []string) {
+	validateSourceFlags()
+
+	isCacheEnabled := viper.GetBool("cache-raw")
+	if isCacheEnabled {
+		validateStorageFlags()
+	}
+
+	start, end := getTimeRange()
+
+	entries, err := fetchRawEntries(context.Background(), start, end)
+	cobra.CheckErr(err)
+
+	if isCacheEnabled {
+		store, err := getStore()
+		cobra.CheckErr(err)
+
+		err = saveRawCache(context.Background(), store, &rawCache{
+			Source:    viper.GetString("source"),
+			User:      viper.GetString("source-user"),
+			Start:     start,
+			End:       end,
+			FetchedAt: time.Now(),
+			Entries:   entries,
+		})
+		cobra.CheckErr(err)
+
+		fmt.Print(tr("Cached %d raw entries.\n", len(entries)))
+	}
+
+	printTransformedEntries(entries, start, end)
+}
+
+func runTransformCmd(_ *cobra.Command, _ []string) {
+	if !viper.GetBool("from-cache") {
+		validateSourceFlags()
+
+		start, end := getTimeRange()
+
+		entries, err := fetchRawEntries(context.Background(), start, end)
+		cobra.CheckErr(err)
+
+		printTransformedEntries(entries, start, end)
+		return
+	}
+
+	validateStorageFlags()
+
+	if isJiraLookupEnabled() {
+		validateJiraFlags()
+	}
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	cache, err := loadRawCache(context.Background(), store)
+	cobra.CheckErr(err)
+
+	fmt.Print(tr("Using %d entries fetched from %s at %s.\n", len(cache.Entries), cache.Source, getLocale().FormatDateTime(cache.FetchedAt.Local())))
+
+	printTransformedEntries(cache.Entries, cache.Start, cache.End)
+}
@@ -0,0 +1,47 @@
+package root
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/calendar"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+// getCalendarWriter returns the calendar writer set by the flags.
+func getCalendarWriter() (calendar.Writer, error) {
+	return calendar.NewWriter(&calendar.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Provider:     viper.GetString("calendar"),
+		BaseURL:      viper.GetString("calendar-url"),
+		TokenURL:     viper.GetString("calendar-token-url"),
+		CalendarID:   viper.GetString("calendar-id"),
+		ClientID:     viper.GetString("calendar-client-id"),
+		ClientSecret: viper.GetString("calendar-client-secret"),
+		RefreshToken: viper.GetString("calendar-refresh-token"),
+	})
+}
+
+// writeCalendarEvents writes the uploaded entries back to the calendar, if
+// set. The entries are already uploaded, so failing to write the events does
+// not fail the sync, but the errors are returned to be reported.
+func writeCalendarEvents(ctx context.Context, entries worklog.Entries) error {
+	if viper.GetString("calendar") == "" || len(entries) == 0 {
+		return nil
+	}
+
+	writer, err := getCalendarWriter()
+	if err != nil {
+		return err
+	}
+
+	if err = warmUpCredentials(ctx, writer); err != nil {
+		return fmt.Errorf("%w: %w", calendar.ErrWriteEvents, err)
+	}
+
+	return writer.WriteEvents(ctx, entries)
+}
@@ -1,19 +1,23 @@
 package root
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/cmd/utils"
 
 	"github.com/jedib0t/go-pretty/v6/progress"
-	"github.com/jedib0t/go-pretty/v6/table"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,12 +26,24 @@ import (
 const (
 	program           string = "minutes"
 	defaultDateFormat string = "2006-01-02 15:04:05"
+
+	// maxFetchAttempts is the number of attempts to fetch the entries when
+	// fetching failed with a retryable error.
+	maxFetchAttempts int = 3
+
+	// rangeEndExclusive excludes the entries starting at the end of the range.
+	rangeEndExclusive string = "exclusive"
+	// rangeEndInclusive includes the entries starting at the end of the range.
+	// If the end is a midnight, the whole day of the end is included.
+	rangeEndInclusive string = "inclusive"
 )
 
 var (
 	configFile string
 	envPrefix  string
 
+	rangeEnds = []string{rangeEndExclusive, rangeEndInclusive}
+
 	version string
 	commit  string
 	date    string
@@ -59,29 +75,50 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	initCommonFlags()
+	initActivityWatchFlags()
+	initBambooHRFlags()
+	initCalendarFlags()
 	initClockifyFlags()
+	initCSVFileFlags()
+	initGitFlags()
+	initGitHubFlags()
+	initGitLabFlags()
+	initGoogleCalendarFlags()
+	initHamsterFlags()
 	initHarvestFlags()
+	initHubstaffFlags()
+	initICSFileFlags()
+	initJiraFlags()
+	initJSONFileFlags()
+	initKimaiFlags()
+	initOutlookFlags()
+	initPersonioFlags()
+	initRedmineFlags()
+	initRescueTimeFlags()
 	initTempoFlags()
+	initTimeCampFlags()
 	initTimewarriorFlags()
 	initTogglFlags()
+	initWakaTimeFlags()
+	initWatsonFlags()
+	initXLSXFileFlags()
+	initYouTrackFlags()
 }
 
 func initConfig() {
-	if configFile != "" {
-		viper.SetConfigName(configFile)
-	} else {
+	var configPaths []string
+	if configFile == "" {
 		homeDir, err := os.UserHomeDir()
 		cobra.CheckErr(err)
 
 		configDir, err := os.UserConfigDir()
 		cobra.CheckErr(err)
 
-		viper.AddConfigPath(homeDir)
-		viper.AddConfigPath(configDir)
-		viper.SetConfigName("." + program)
-		viper.SetConfigType("toml")
+		configPaths = []string{homeDir, configDir}
 	}
 
+	utils.SetConfigFile(viper.GetViper(), configFile, "."+program, configPaths...)
+
 	viper.SetEnvPrefix(envPrefix)
 	viper.AutomaticEnv()
 
@@ -94,12 +131,13 @@ func initConfig() {
 	}
 
 	// Bind flags to config value
+	cobra.CheckErr(viper.BindPFlags(rootCmd.PersistentFlags()))
 	cobra.CheckErr(viper.BindPFlags(rootCmd.Flags()))
-}
 
-func runRootCmd(_ *cobra.Command, _ []string) {
-	var err error
+	utils.ConfigureOutput(isPlainOutput())
+}
 
+func runRootCmd(cmd *cobra.Command, _ []string) {
 	if viper.GetBool("version") {
 		if version == "" || len(commit) < 7 || date == "" {
 			fmt.Println("dirty build")
@@ -111,118 +149,628 @@ func runRootCmd(_ *cobra.Command, _ []string) {
 
 	validateFlags()
 
-	dateFormat := viper.GetString("date-format")
+	start, end := getTimeRange()
 
-	start, err := utils.GetTime(viper.GetString("start"), dateFormat)
+	uploader, err := getUploader()
 	cobra.CheckErr(err)
+	cobra.CheckErr(warmUpCredentials(context.Background(), uploader))
+	checkVerifySample(uploader)
 
-	rawEnd := viper.GetString("end")
-	end, err := utils.GetTime(rawEnd, dateFormat)
+	stage, err := getStage()
 	cobra.CheckErr(err)
 
-	// No end date was set, hence we are setting the end date to next day midnight
-	if rawEnd == "" {
-		end = end.Add(time.Hour * 24)
+	entries, err := fetchEntriesWithStaged(context.Background(), stage, start, end)
+	if err != nil {
+		reportUsage(cmd, 0, err)
 	}
-
-	fetcher, err := getFetcher()
 	cobra.CheckErr(err)
 
-	uploader, err := getUploader()
-	cobra.CheckErr(err)
+	wl := newWorklog(entries)
+	completeEntries := wl.CompleteEntries()
 
-	tagsAsTasksRegex, err := regexp.Compile(viper.GetString("tags-as-tasks-regex"))
-	cobra.CheckErr(err)
+	printEntries(start, end, completeEntries, wl.IncompleteEntries())
 
-	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
-		End:              end,
-		Start:            start,
-		User:             viper.GetString("source-user"),
-		TagsAsTasksRegex: tagsAsTasksRegex,
-	})
-	cobra.CheckErr(err)
+	if viper.GetBool("verbose") {
+		printProvenance(completeEntries)
+	}
 
-	// It is safe to use MustCompile when compiling regex as we already
-	// validated its correctness
-	wl := worklog.NewWorklog(entries, &worklog.FilterOpts{
-		Client:  regexp.MustCompile(viper.GetString("filter-client")),
-		Project: regexp.MustCompile(viper.GetString("filter-project")),
-	})
+	if viper.GetBool("show-payloads") {
+		printPayloads(uploader, completeEntries)
+	}
 
-	completeEntries := wl.CompleteEntries()
-	incompleteEntries := wl.IncompleteEntries()
+	cobra.CheckErr(checkLimits(uploader, completeEntries))
+
+	if viper.GetBool("history") && viper.GetFloat64("anomaly-factor") > 0 {
+		warnAnomalies(entries, start, end)
+	}
+
+	if strings.ToLower(utils.Prompt(tr("Continue? [y/n]: "))) != "y" {
+		fmt.Println(tr("User interruption. Aborting."))
+		os.Exit(0)
+	}
+
+	if viper.GetBool("dry-run") {
+		recordRun(start, end, completeEntries, nil)
+		reportUsage(cmd, len(completeEntries))
+		return
+	}
+
+	fmt.Print(tr("\nUploading worklog entries:\n\n"))
+
+	progressUpdateFrequency := progress.DefaultUpdateFrequency
+	progressWriter := newProgressWriter(progressUpdateFrequency)
+
+	// Intentionally called as a goroutine
+	go progressWriter.Render()
+
+	uploadOpts := getUploadOpts()
+	uploadOpts.ProgressWriter = progressWriter
+	verifyCollector := newVerifyCollector(uploadOpts)
+
+	uploadCtx, cancelUpload := withDeadline(context.Background(), "upload-deadline")
+	defer cancelUpload()
+
+	uploadErrors := uploadEntries(uploadCtx, uploader, completeEntries, uploadOpts)
+	for i := range uploadErrors {
+		uploadErrors[i] = checkDeadline(uploadCtx, "upload-deadline", uploadErrors[i])
+	}
+
+	// Wait for at least one tracker to appear and while the rendering is in progress,
+	// wait for the remaining updates to render.
+	time.Sleep(time.Second)
+	for progressWriter.IsRenderInProgress() {
+		time.Sleep(progressUpdateFrequency)
+	}
+
+	recordRun(start, end, completeEntries, uploadErrors)
+
+	if len(uploadErrors) != 0 {
+		printUploadErrors(uploadErrors)
+		reportUsage(cmd, len(completeEntries), uploadErrors...)
+		os.Exit(1)
+	}
+
+	// The staged entries are kept if any upload failed, since the failed
+	// entries are not known
+	cobra.CheckErr(unstageEntries(context.Background(), stage, completeEntries, nil))
+
+	fmt.Print(tr("\nSuccessfully uploaded %d worklog entries!\n", len(completeEntries)))
+
+	// The balance is updated only when the entries are uploaded, so dry runs
+	// and aborted syncs do not change it
+	if viper.GetBool("overtime") {
+		updateOvertime(entries, start, end)
+	}
+
+	if err = writeCalendarEvents(context.Background(), completeEntries); err != nil {
+		fmt.Print(tr("\nFailed to write the uploaded entries to the calendar: %v\n", err))
+	}
+
+	reportUsage(cmd, len(completeEntries))
+
+	if verifyCollector != nil {
+		failed, err := verifyUploads(context.Background(), uploader, verifyCollector, uploadOpts)
+		cobra.CheckErr(err)
+
+		if failed != 0 {
+			cobra.CheckErr(tr("%d sampled worklogs do not match the uploaded entries", failed))
+		}
+	}
+}
 
+// printEntries prints the complete and incomplete entries of the period as a
+// table, configured by the table flags.
+func printEntries(start time.Time, end time.Time, completeEntries worklog.Entries, incompleteEntries worklog.Entries) {
 	columnTruncates := map[string]int{}
-	err = viper.UnmarshalKey("table-column-truncates", &columnTruncates)
+	err := viper.UnmarshalKey("table-column-truncates", &columnTruncates)
 	cobra.CheckErr(err)
 
+	reportLocale := getLocale()
+
 	tablePrinter := utils.NewTablePrinter(&utils.TablePrinterOpts{
 		BasePrinterOpts: utils.BasePrinterOpts{
 			Output:        os.Stdout,
 			AutoIndex:     true,
-			Title:         fmt.Sprintf("Worklog entries (%s - %s)", start.Local().String(), end.Local().String()),
+			Title:         tr("Worklog entries (%s - %s)", reportLocale.FormatDateTime(start.Local()), reportLocale.FormatDateTime(end.Local())),
 			SortBy:        viper.GetStringSlice("table-sort-by"),
 			HiddenColumns: viper.GetStringSlice("table-hide-column"),
+			Locale:        reportLocale,
+			Translator:    getTranslator(),
 		},
-		Style: table.StyleLight,
+		Style: getTableStyle(),
 		ColumnConfig: utils.ParseColumnConfigs(
 			"table-column-config.%s",
 			viper.GetStringSlice("table-hide-column"),
 		),
 		ColumnTruncates: columnTruncates,
+		MaxWidth:        utils.TerminalWidth(),
+		Linear:          isLinearOutput(),
 	})
 
 	err = tablePrinter.Print(completeEntries, incompleteEntries)
 	cobra.CheckErr(err)
+}
 
-	if strings.ToLower(utils.Prompt("Continue? [y/n]: ")) != "y" {
-		fmt.Println("User interruption. Aborting.")
-		os.Exit(0)
+// printPayloads prints the serialized payload the uploader sends for each
+// entry. If the uploader cannot preview its payloads, a notice is printed.
+func printPayloads(uploader client.Uploader, entries worklog.Entries) {
+	previewer, ok := uploader.(client.PayloadPreviewer)
+	if !ok {
+		fmt.Print(tr("The %s target does not support payload preview.\n\n", viper.GetString("target")))
+		return
 	}
 
-	// In worst case, the maximum number of errors will match the number of entries
-	uploadErrChan := make(chan error, len(completeEntries))
+	payloads, err := previewer.PreviewPayloads(entries, getUploadOpts())
+	cobra.CheckErr(err)
+
+	fmt.Print(tr("Payloads of the %s target:\n\n", viper.GetString("target")))
+
+	for _, payload := range payloads {
+		fmt.Printf("# %s (%s)\n", payload.Entry.Summary, payload.Entry.Key())
+		if payload.Method != "" {
+			fmt.Printf("%s %s\n", payload.Method, payload.URL)
+		}
 
-	fmt.Printf("\nUploading worklog entries:\n\n")
-	if !viper.GetBool("dry-run") {
-		progressUpdateFrequency := progress.DefaultUpdateFrequency
-		progressWriter := utils.NewProgressWriter(progressUpdateFrequency)
+		fmt.Printf("%s\n\n", bytes.TrimSpace(payload.Body))
+	}
+}
 
-		// Intentionally called as a goroutine
-		go progressWriter.Render()
+// checkLimits validates the entries against the limits of the target by the
+// limit policy and prints the violations transformed before uploading.
+func checkLimits(uploader client.Uploader, entries worklog.Entries) error {
+	transformed, err := client.CheckLimits(uploader, entries, getUploadOpts())
 
-		uploader.UploadEntries(context.Background(), completeEntries, uploadErrChan, &client.UploadOpts{
-			RoundToClosestMinute:   viper.GetBool("round-to-closest-minute"),
-			TreatDurationAsBilled:  viper.GetBool("force-billed-duration"),
-			CreateMissingResources: false,
-			User:                   viper.GetString("target-user"),
-			ProgressWriter:         progressWriter,
-		})
+	for i := range transformed {
+		fmt.Printf("Transforming %v\n", &transformed[i])
+	}
 
-		// Wait for at least one tracker to appear and while the rendering is in progress,
-		// wait for the remaining updates to render.
-		time.Sleep(time.Second)
-		for progressWriter.IsRenderInProgress() {
-			time.Sleep(progressUpdateFrequency)
-		}
+	if len(transformed) != 0 {
+		fmt.Println()
+	}
+
+	return err
+}
+
+// newWorklog creates a new worklog from the transformed entries. The entries
+// are already filtered by the transformation pipeline.
+func newWorklog(entries worklog.Entries) worklog.Worklog {
+	return worklog.NewWorklog(entries, &worklog.FilterOpts{})
+}
+
+// getLocale returns the locale used to format the reports. The locale is
+// already validated, getting it again cannot fail.
+func getLocale() *locale.Locale {
+	reportLocale, err := locale.Get(viper.GetString("locale"))
+	cobra.CheckErr(err)
+
+	return reportLocale
+}
+
+// getTranslator returns the translator of the user-facing messages. If the
+// language is not set, it is detected from the environment. Since the
+// messages of the flag validation are translated too, an unknown language
+// falls back to the default language instead of failing.
+func getTranslator() *i18n.Translator {
+	name := viper.GetString("language")
+	if name == "" {
+		name = i18n.Detect()
+	}
+
+	translator, err := i18n.Get(name)
+	if err != nil {
+		return i18n.Default()
 	}
 
+	return translator
+}
+
+// tr translates the user-facing message to the language of the messages and
+// formats it like fmt.Sprintf.
+func tr(key string, args ...interface{}) string {
+	return getTranslator().Sprintf(key, args...)
+}
+
+// getAuthorOpts returns the options used to resolve the author of the uploaded
+// entries.
+func getAuthorOpts() *client.AuthorOpts {
+	return &client.AuthorOpts{
+		Strategy:  viper.GetString("author-strategy"),
+		FixedUser: viper.GetString("author-fixed-user"),
+		UserMap:   viper.GetStringMapString("author-map"),
+	}
+}
+
+// getUploadOpts returns the upload options set by flags. The progress writer
+// is not set, hence the progress is not tracked by default.
+func getUploadOpts() *client.UploadOpts {
+	var commentTemplate *template.Template
+	var err error
+
+	if rawTemplate := viper.GetString("comment-template"); rawTemplate != "" {
+		// The template is already validated, parsing it again cannot fail
+		commentTemplate, err = client.NewCommentTemplate(rawTemplate)
+		cobra.CheckErr(err)
+	}
+
+	return &client.UploadOpts{
+		CreateMissingResources: false,
+		User:                   viper.GetString("target-user"),
+		Author:                 getAuthorOpts(),
+		CommentTemplate:        commentTemplate,
+		MutationRecorder:       getMutationRecorder(),
+		LimitPolicy:            viper.GetString("limit-policy"),
+	}
+}
+
+// uploadEntries uploads the entries using the uploader, applying the
+// corrections among them, and returns the list of errors occurred during the
+// upload.
+func uploadEntries(ctx context.Context, uploader client.Uploader, entries worklog.Entries, opts *client.UploadOpts) []error {
+	// In worst case, the maximum number of errors will match the number of entries
+	uploadErrChan := make(chan error, len(entries))
+
+	client.UploadWithCorrections(ctx, uploader, entries, uploadErrChan, opts)
+
 	var uploadErrors []error
-	for i := 0; i < len(completeEntries); i++ {
+	for i := 0; i < len(entries); i++ {
 		if err := <-uploadErrChan; err != nil {
 			uploadErrors = append(uploadErrors, err)
 		}
 	}
 
-	if errCount := len(uploadErrors); errCount != 0 {
-		fmt.Printf("\nFailed to upload %d worklog entries!\n\n", errCount)
-		for _, err := range uploadErrors {
-			fmt.Println(err)
+	return uploadErrors
+}
+
+// printUploadErrors prints the upload errors. If the failed entry is known,
+// the links to review its source entries are printed too, so the origin of
+// the entry can be fixed before uploading it again.
+func printUploadErrors(uploadErrors []error) {
+	fmt.Print(tr("\nFailed to upload %d worklog entries!\n\n", len(uploadErrors)))
+
+	for _, err := range uploadErrors {
+		fmt.Printf("[%s] %v\n", client.KindOf(err), err)
+
+		if entry := client.EntryOf(err); entry != nil {
+			for _, sourceURL := range entry.Provenance.SourceURLs {
+				fmt.Print(tr("    review: %s\n", sourceURL))
+			}
 		}
-		os.Exit(1)
 	}
+}
+
+// getRangeLocation returns the location of the dates of the range, like the
+// midnight of the days. The timezone is already validated, loading it again
+// cannot fail.
+func getRangeLocation() *time.Location {
+	loc, err := time.LoadLocation(viper.GetString("range-timezone"))
+	cobra.CheckErr(err)
+
+	return loc
+}
+
+// getTimeRange returns the start and end date of the sync based on the
+// start, end, date-format and range flags. The returned range is half open,
+// hence the entries starting at the end are not part of the range.
+func getTimeRange() (time.Time, time.Time) {
+	dateFormat := viper.GetString("date-format")
+	loc := getRangeLocation()
+
+	start, err := utils.GetTimeInLocation(viper.GetString("start"), dateFormat, loc)
+	cobra.CheckErr(err)
+
+	rawEnd := viper.GetString("end")
+	end, err := utils.GetTimeInLocation(rawEnd, dateFormat, loc)
+	cobra.CheckErr(err)
+
+	switch {
+	case rawEnd == "":
+		// No end date was set, hence we are setting the end date to next day midnight
+		end = end.AddDate(0, 0, 1)
+	case viper.GetString("range-end") != rangeEndInclusive:
+		break
+	case end.Equal(time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)):
+		// The whole day of the inclusive end is part of the range
+		end = end.AddDate(0, 0, 1)
+	default:
+		// The times of the sources have second precision at most
+		end = end.Add(time.Second)
+	}
+
+	return start, end
+}
+
+// fetchEntries fetches the entries from the configured source and transforms
+// them.
+func fetchEntries(ctx context.Context, start time.Time, end time.Time) (worklog.Entries, error) {
+	entries, err := fetchRawEntries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return transformEntries(ctx, entries)
+}
+
+// withDeadline returns the context of a phase of the sync, which is cancelled
+// when the deadline set by the flag passes. The deadline is counted from the
+// start of the phase; if it is not set, only the requests of the phase time
+// out.
+func withDeadline(ctx context.Context, flag string) (context.Context, context.CancelFunc) {
+	deadline := viper.GetDuration(flag)
+	if deadline == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, deadline)
+}
+
+// checkDeadline annotates the error of a phase if the phase failed because its
+// deadline set by the flag passed, since the error of the cancelled request
+// does not tell which deadline cancelled it.
+func checkDeadline(ctx context.Context, flag string, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	return fmt.Errorf("%s: %w", tr("%s of %s exceeded", flag, viper.GetDuration(flag)), err)
+}
+
+// warmUpCredentials refreshes the short-living credentials of the fetcher or
+// uploader, like OAuth access tokens, which would expire within the expected
+// duration of the run. The credentials expiring anyway are refreshed when the
+// service rejects them.
+func warmUpCredentials(ctx context.Context, c interface{}) error {
+	warmer, ok := c.(client.CredentialWarmer)
+	if !ok {
+		return nil
+	}
+
+	return warmer.WarmUpCredentials(ctx, viper.GetDuration("expected-run-duration"))
+}
+
+// sourceResult represents the outcome of fetching the entries of a source.
+type sourceResult struct {
+	source    string
+	entries   worklog.Entries
+	rowErrors client.RowErrors
+	err       error
+}
+
+// fetchRawEntries fetches the entries from the configured sources without
+// transforming them. Multiple sources are fetched concurrently, and their
+// entries are merged only if every source succeeded, unless
+// continue-on-source-error is set.
+func fetchRawEntries(ctx context.Context, start time.Time, end time.Time) (worklog.Entries, error) {
+	sourceNames := getSourceNames()
+
+	fetchers := make([]client.Fetcher, len(sourceNames))
+	for i, source := range sourceNames {
+		fetcher, err := getFetcher(source)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = warmUpCredentials(ctx, fetcher); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+
+		fetchers[i] = fetcher
+	}
+
+	fetchOpts := &client.FetchOpts{
+		End:   end,
+		Start: start,
+		User:  viper.GetString("source-user"),
+	}
+
+	var results []*sourceResult
+	if len(sourceNames) == 1 {
+		results = []*sourceResult{fetchSourceEntries(ctx, sourceNames[0], fetchers[0], fetchOpts)}
+	} else {
+		results = fetchSourcesConcurrently(ctx, sourceNames, fetchers, fetchOpts)
+	}
+
+	var entries worklog.Entries
+	var sourceErrors []error
+
+	for _, result := range results {
+		if result.rowErrors != nil {
+			printRowErrors(result.rowErrors)
+		}
+
+		if result.err != nil {
+			sourceErrors = append(sourceErrors, result.err)
+			continue
+		}
+
+		entries = append(entries, result.entries...)
+	}
+
+	if len(sourceErrors) == 0 {
+		return entries, nil
+	}
+
+	err := errors.Join(sourceErrors...)
+	if len(sourceErrors) == len(results) || !viper.GetBool("continue-on-source-error") {
+		return nil, err
+	}
+
+	fmt.Print(tr("Continuing without the failed sources:\n%v\n\n", err))
+
+	return entries, nil
+}
+
+// fetchSourcesConcurrently fetches the entries of the sources concurrently,
+// tracking the progress of every source separately. The errors of the
+// sources are prefixed by the name of the source.
+func fetchSourcesConcurrently(ctx context.Context, sourceNames []string, fetchers []client.Fetcher, opts *client.FetchOpts) []*sourceResult {
+	progressUpdateFrequency := progress.DefaultUpdateFrequency
+	progressWriter := newProgressWriter(progressUpdateFrequency)
+	progressWriter.Style().Options.DoneString = "fetched! "
+
+	// Intentionally called as a goroutine
+	go progressWriter.Render()
+
+	results := make([]*sourceResult, len(sourceNames))
+
+	var wg sync.WaitGroup
+	for i := range sourceNames {
+		tracker := &progress.Tracker{
+			Message: fmt.Sprintf("Fetching entries from %s", sourceNames[i]),
+			Total:   1,
+			Units:   progress.UnitsDefault,
+		}
+
+		progressWriter.AppendTracker(tracker)
+
+		wg.Add(1)
+		go func(i int, tracker *progress.Tracker) {
+			defer wg.Done()
+
+			result := fetchSourceEntries(ctx, sourceNames[i], fetchers[i], opts)
+			if result.err != nil {
+				result.err = fmt.Errorf("%s: %w", result.source, result.err)
+				tracker.MarkAsErrored()
+			} else {
+				tracker.MarkAsDone()
+			}
+
+			results[i] = result
+		}(i, tracker)
+	}
+
+	wg.Wait()
+
+	// Wait for the trackers to appear and while the rendering is in progress,
+	// wait for the remaining updates to render.
+	time.Sleep(time.Second)
+	for progressWriter.IsRenderInProgress() {
+		time.Sleep(progressUpdateFrequency)
+	}
+
+	fmt.Println()
+
+	return results
+}
+
+// fetchSourceEntries fetches the entries of the source using the fetcher,
+// retrying the fetch if it failed with a retryable error.
+func fetchSourceEntries(ctx context.Context, source string, fetcher client.Fetcher, opts *client.FetchOpts) *sourceResult {
+	result := &sourceResult{source: source}
+
+	var entries worklog.Entries
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		entries, err = client.FetchChunked(ctx, fetcher, opts)
+		// The requests cancelled by the deadline of the fetch are not retried
+		if err == nil || ctx.Err() != nil || !client.IsRetryable(err) || attempt == maxFetchAttempts {
+			break
+		}
+
+		wait := client.RetryAfter(err)
+		if wait == 0 {
+			wait = time.Second * time.Duration(attempt)
+		}
+
+		fmt.Print(tr("Fetching from %s failed (%s), retrying in %s...\n", source, client.KindOf(err), wait))
+
+		// The retry must not outlive the deadline of the fetch
+		select {
+		case <-ctx.Done():
+			result.err = fmt.Errorf("%w: %w", err, ctx.Err())
+			return result
+		case <-time.After(wait):
+		}
+	}
+
+	// File sources return the entries of the valid rows and the errors of the
+	// invalid rows, unless they are strict
+	var rowErrors client.RowErrors
+	if errors.As(err, &rowErrors) && entries != nil {
+		result.rowErrors = rowErrors
+		err = nil
+	}
+
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	fetchedAt := time.Now()
+
+	for i := range entries {
+		entries[i].Provenance.Source = source
+		entries[i].Provenance.FetchedAt = fetchedAt
+
+		entries[i].AddMentionedLinks()
+	}
+
+	result.entries = entries
+
+	return result
+}
+
+// printRowErrors prints the errors of the invalid rows skipped by file
+// sources.
+func printRowErrors(rowErrors client.RowErrors) {
+	fmt.Print(tr("Skipped %d invalid rows:\n", len(rowErrors)))
+	for _, rowErr := range rowErrors {
+		fmt.Printf("  %v\n", rowErr)
+	}
+
+	if path := viper.GetString("rejects-file"); path != "" {
+		fmt.Print(tr("The invalid rows are written to %s\n", path))
+	}
+
+	fmt.Println()
+}
+
+// transformEntries runs the transformation pipeline on the fetched entries.
+func transformEntries(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	transformPipeline, err := newPipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	return transformPipeline.Run(ctx, entries)
+}
+
+// getReallocations returns the compiled reallocations set in the config.
+func getReallocations() ([]worklog.Reallocation, error) {
+	var reallocations []worklog.Reallocation
+	if err := viper.UnmarshalKey("reallocations", &reallocations); err != nil {
+		return nil, err
+	}
+
+	for i := range reallocations {
+		if err := reallocations[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return reallocations, nil
+}
+
+// getCostCenters returns the compiled cost centers set in the config.
+func getCostCenters() ([]worklog.CostCenter, error) {
+	var costCenters []worklog.CostCenter
+	if err := viper.UnmarshalKey("cost-centers", &costCenters); err != nil {
+		return nil, err
+	}
+
+	for i := range costCenters {
+		if err := costCenters[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return costCenters, nil
+}
 
-	fmt.Printf("\nSuccessfully uploaded %d worklog entries!\n", len(completeEntries))
+// bindCmdFlags binds the flags of a subcommand to the config values.
+// It must be set as the `PreRun` of every subcommand defining local flags.
+func bindCmdFlags(cmd *cobra.Command, _ []string) {
+	cobra.CheckErr(viper.BindPFlags(cmd.LocalFlags()))
 }
 
 func Execute(buildVersion string, buildCommit string, buildDate string) {
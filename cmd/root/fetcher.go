@@ -1,15 +1,40 @@
 package root
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os/exec"
+	"strings"
 
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
 	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/activitywatch"
+	"github.com/gabor-boros/minutes/internal/pkg/client/bamboohr"
 	"github.com/gabor-boros/minutes/internal/pkg/client/clockify"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/git"
+	"github.com/gabor-boros/minutes/internal/pkg/client/github"
+	"github.com/gabor-boros/minutes/internal/pkg/client/gitlab"
+	"github.com/gabor-boros/minutes/internal/pkg/client/googlecalendar"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hamster"
 	"github.com/gabor-boros/minutes/internal/pkg/client/harvest"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hubstaff"
+	"github.com/gabor-boros/minutes/internal/pkg/client/icsfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jsonfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/kimai"
+	"github.com/gabor-boros/minutes/internal/pkg/client/outlook"
+	"github.com/gabor-boros/minutes/internal/pkg/client/personio"
+	"github.com/gabor-boros/minutes/internal/pkg/client/redmine"
+	"github.com/gabor-boros/minutes/internal/pkg/client/rescuetime"
 	"github.com/gabor-boros/minutes/internal/pkg/client/tempo"
+	"github.com/gabor-boros/minutes/internal/pkg/client/timecamp"
 	"github.com/gabor-boros/minutes/internal/pkg/client/timewarrior"
 	"github.com/gabor-boros/minutes/internal/pkg/client/toggl"
+	"github.com/gabor-boros/minutes/internal/pkg/client/wakatime"
+	"github.com/gabor-boros/minutes/internal/pkg/client/watson"
+	"github.com/gabor-boros/minutes/internal/pkg/client/xlsxfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/youtrack"
 	"github.com/spf13/viper"
 )
 
@@ -17,7 +42,41 @@ var (
 	ErrNoSourceImplementation = errors.New("no source implementation found")
 )
 
+func getActivityWatchFetcher() (client.Fetcher, error) {
+	return activitywatch.NewFetcher(&activitywatch.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:     viper.GetString("activitywatch-url"),
+		Buckets:     viper.GetStringSlice("activitywatch-buckets"),
+		MinDuration: viper.GetDuration("activitywatch-min-duration"),
+		MergeGap:    viper.GetDuration("activitywatch-merge-gap"),
+	})
+}
+
+func getBambooHRFetcher() (client.Fetcher, error) {
+	return bamboohr.NewFetcher(&bamboohr.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		APIKey:          viper.GetString("bamboohr-api-key"),
+		BaseURL:         viper.GetString("bamboohr-url"),
+		Company:         viper.GetString("bamboohr-company"),
+		AbsenceDuration: viper.GetDuration("absence-duration"),
+	})
+}
+
 func getClockifyFetcher() (client.Fetcher, error) {
+	var timeOffURL string
+	if viper.GetBool("clockify-fetch-time-off") {
+		timeOffURL = viper.GetString("clockify-time-off-url")
+	}
+
+	var reportsURL string
+	if viper.GetBool("clockify-fetch-report") {
+		reportsURL = viper.GetString("clockify-reports-url")
+	}
+
 	return clockify.NewFetcher(&clockify.ClientOpts{
 		BaseClientOpts: client.BaseClientOpts{
 			Timeout: client.DefaultRequestTimeout,
@@ -26,8 +85,140 @@ func getClockifyFetcher() (client.Fetcher, error) {
 			Header: "X-Api-Key",
 			Token:  viper.GetString("clockify-api-key"),
 		},
-		BaseURL:   viper.GetString("clockify-url"),
-		Workspace: viper.GetString("clockify-workspace"),
+		BaseURL:         viper.GetString("clockify-url"),
+		Workspace:       viper.GetString("clockify-workspace"),
+		TimeOffURL:      timeOffURL,
+		AbsenceDuration: viper.GetDuration("absence-duration"),
+		ReportsURL:      reportsURL,
+		AppURL:          viper.GetString("clockify-app-url"),
+	})
+}
+
+func getCSVFileFetcher() (client.Fetcher, error) {
+	columns, err := csvfile.ParseColumns(viper.GetStringSlice("csvfile-columns"))
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter, err := csvfile.ParseDelimiter(viper.GetString("csvfile-delimiter"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &csvfile.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path:           viper.GetString("csvfile-path"),
+		Columns:        columns,
+		OmitHeader:     viper.GetBool("csvfile-omit-header"),
+		Delimiter:      delimiter,
+		Locale:         getLocale(),
+		DateFormat:     viper.GetString("csvfile-date-format"),
+		DateTimeFormat: viper.GetString("csvfile-datetime-format"),
+		DurationUnit:   viper.GetString("csvfile-duration-unit"),
+		Strict:         viper.GetBool("strict"),
+		RejectsPath:    viper.GetString("rejects-file"),
+	}
+
+	if viper.GetBool("infer-mapping") {
+		inferred, err := csvfile.InferColumns(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Columns = acceptInferredColumns("csvfile-columns", opts.Path, inferred, opts.Columns)
+	}
+
+	return csvfile.NewFetcher(opts)
+}
+
+// acceptInferredColumns prints the columns inferred for the read file and asks
+// the user to accept them. The proposal is printed in configuration format, so
+// it can be saved as the columns flag for the next imports. If the proposal is
+// declined, the configured columns return.
+func acceptInferredColumns(flag string, path string, inferred []csvfile.Column, configured []csvfile.Column) []csvfile.Column {
+	rawColumns := make([]string, 0, len(inferred))
+	for _, column := range inferred {
+		rawColumns = append(rawColumns, fmt.Sprintf("%q", column.String()))
+	}
+
+	fmt.Print(tr("Inferred columns of %s:\n\n  %s = [%s]\n\n", path, flag, strings.Join(rawColumns, ", ")))
+
+	if strings.ToLower(utils.Prompt(tr("Use the inferred columns? [y/n]: "))) != "y" {
+		return configured
+	}
+
+	return inferred
+}
+
+func getGitFetcher() (client.Fetcher, error) {
+	return git.NewFetcher(&git.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		CLIClient: client.CLIClient{
+			Command:            viper.GetString("git-command"),
+			CommandArguments:   viper.GetStringSlice("git-arguments"),
+			CommandCtxExecutor: exec.CommandContext,
+		},
+		Repositories:     viper.GetStringSlice("git-repositories"),
+		DurationStrategy: viper.GetString("git-duration-strategy"),
+		AssumedDuration:  viper.GetDuration("git-assumed-duration"),
+		MaxGap:           viper.GetDuration("git-max-gap"),
+	})
+}
+
+func getGitHubFetcher() (client.Fetcher, error) {
+	return github.NewFetcher(&github.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:         viper.GetString("github-url"),
+		Token:           viper.GetString("github-token"),
+		User:            viper.GetString("github-user"),
+		CommitDuration:  viper.GetDuration("github-commit-duration"),
+		ReviewDuration:  viper.GetDuration("github-review-duration"),
+		CommentDuration: viper.GetDuration("github-comment-duration"),
+	})
+}
+
+func getGitLabFetcher() (client.Fetcher, error) {
+	return gitlab.NewFetcher(&gitlab.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: viper.GetString("gitlab-url"),
+		Token:   viper.GetString("gitlab-token"),
+		User:    viper.GetString("gitlab-user"),
+	})
+}
+
+func getGoogleCalendarFetcher() (client.Fetcher, error) {
+	return googlecalendar.NewFetcher(&googlecalendar.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      viper.GetString("googlecalendar-url"),
+		TokenURL:     viper.GetString("googlecalendar-token-url"),
+		CalendarIDs:  viper.GetStringSlice("googlecalendar-calendars"),
+		ClientID:     viper.GetString("googlecalendar-client-id"),
+		ClientSecret: viper.GetString("googlecalendar-client-secret"),
+		RefreshToken: viper.GetString("googlecalendar-refresh-token"),
+	})
+}
+
+func getHamsterFetcher() (client.Fetcher, error) {
+	return hamster.NewFetcher(&hamster.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		CLIClient: client.CLIClient{
+			Command:            viper.GetString("hamster-sqlite-command"),
+			CommandCtxExecutor: exec.CommandContext,
+		},
+		Path:          viper.GetString("hamster-database"),
+		UnbillableTag: viper.GetString("hamster-unbillable-tag"),
 	})
 }
 
@@ -45,7 +236,120 @@ func getHarvestFetcher() (client.Fetcher, error) {
 	})
 }
 
+func getHubstaffFetcher() (client.Fetcher, error) {
+	store, err := getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	configured := viper.GetString("hubstaff-token")
+
+	refreshToken, err := loadRefreshToken(context.Background(), store, hubstaffTokenKey, configured)
+	if err != nil {
+		return nil, err
+	}
+
+	return hubstaff.NewFetcher(&hubstaff.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      viper.GetString("hubstaff-url"),
+		TokenURL:     viper.GetString("hubstaff-token-url"),
+		RefreshToken: refreshToken,
+		RotateRefreshToken: func(ctx context.Context, refreshToken string) error {
+			return saveRefreshToken(ctx, store, hubstaffTokenKey, configured, refreshToken)
+		},
+		OrganizationID: viper.GetString("hubstaff-organization"),
+		IdleMode:       viper.GetString("hubstaff-idle"),
+		IncludeBreaks:  viper.GetBool("hubstaff-include-breaks"),
+	})
+}
+
+func getICSFileFetcher() (client.Fetcher, error) {
+	return icsfile.NewFetcher(&icsfile.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path: viper.GetString("icsfile-path"),
+	})
+}
+
+func getJSONFileFetcher() (client.Fetcher, error) {
+	return jsonfile.NewFetcher(&jsonfile.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path:        viper.GetString("jsonfile-path"),
+		Strict:      viper.GetBool("strict"),
+		RejectsPath: viper.GetString("rejects-file"),
+	})
+}
+
+func getKimaiFetcher() (client.Fetcher, error) {
+	return kimai.NewFetcher(&kimai.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:         viper.GetString("kimai-url"),
+		Token:           viper.GetString("kimai-token"),
+		IncludeExported: viper.GetBool("kimai-include-exported"),
+	})
+}
+
+func getOutlookFetcher() (client.Fetcher, error) {
+	return outlook.NewFetcher(&outlook.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      viper.GetString("outlook-url"),
+		TokenURL:     viper.GetString("outlook-token-url"),
+		CalendarIDs:  viper.GetStringSlice("outlook-calendars"),
+		ClientID:     viper.GetString("outlook-client-id"),
+		ClientSecret: viper.GetString("outlook-client-secret"),
+		RefreshToken: viper.GetString("outlook-refresh-token"),
+	})
+}
+
+func getPersonioFetcher() (client.Fetcher, error) {
+	return personio.NewFetcher(&personio.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:         viper.GetString("personio-url"),
+		ClientID:        viper.GetString("personio-client-id"),
+		ClientSecret:    viper.GetString("personio-client-secret"),
+		AbsenceDuration: viper.GetDuration("absence-duration"),
+	})
+}
+
+func getRedmineFetcher() (client.Fetcher, error) {
+	return redmine.NewFetcher(&redmine.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:              viper.GetString("redmine-url"),
+		APIKey:               viper.GetString("redmine-api-key"),
+		UnbillableActivities: viper.GetStringSlice("redmine-unbillable-activities"),
+	})
+}
+
+func getRescueTimeFetcher() (client.Fetcher, error) {
+	return rescuetime.NewFetcher(&rescuetime.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:     viper.GetString("rescuetime-url"),
+		APIKey:      viper.GetString("rescuetime-api-key"),
+		Granularity: viper.GetString("rescuetime-granularity"),
+	})
+}
+
 func getTempoFetcher() (client.Fetcher, error) {
+	oauthOpts, refreshToken, err := getTempoOAuth()
+	if err != nil {
+		return nil, err
+	}
+
 	return tempo.NewFetcher(&tempo.ClientOpts{
 		BaseClientOpts: client.BaseClientOpts{
 			Timeout: client.DefaultRequestTimeout,
@@ -54,7 +358,19 @@ func getTempoFetcher() (client.Fetcher, error) {
 			Username: viper.GetString("tempo-username"),
 			Password: viper.GetString("tempo-password"),
 		},
-		BaseURL: viper.GetString("tempo-url"),
+		BaseURL:      viper.GetString("tempo-url"),
+		OAuth:        oauthOpts,
+		RefreshToken: refreshToken,
+	})
+}
+
+func getTimeCampFetcher() (client.Fetcher, error) {
+	return timecamp.NewFetcher(&timecamp.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: viper.GetString("timecamp-url"),
+		Token:   viper.GetString("timecamp-token"),
 	})
 }
 
@@ -68,6 +384,7 @@ func getTimeWarriorFetcher() (client.Fetcher, error) {
 			CommandArguments:   viper.GetStringSlice("timewarrior-arguments"),
 			CommandCtxExecutor: exec.CommandContext,
 		},
+		DataDir:         viper.GetString("timewarrior-data-dir"),
 		UnbillableTag:   viper.GetString("timewarrior-unbillable-tag"),
 		ClientTagRegex:  viper.GetString("timewarrior-client-tag-regex"),
 		ProjectTagRegex: viper.GetString("timewarrior-project-tag-regex"),
@@ -85,25 +402,150 @@ func getTogglFetcher() (client.Fetcher, error) {
 		},
 		BaseURL:   "https://api.track.toggl.com",
 		Workspace: viper.GetInt("toggl-workspace"),
+		AppURL:    toggl.DefaultAppURL,
+	})
+}
+
+func getWakaTimeFetcher() (client.Fetcher, error) {
+	return wakatime.NewFetcher(&wakatime.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: viper.GetString("wakatime-url"),
+		APIKey:  viper.GetString("wakatime-api-key"),
+		User:    viper.GetString("wakatime-user"),
+	})
+}
+
+func getWatsonFetcher() (client.Fetcher, error) {
+	return watson.NewFetcher(&watson.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path:          viper.GetString("watson-frames-file"),
+		UnbillableTag: viper.GetString("watson-unbillable-tag"),
+	})
+}
+
+func getXLSXFileFetcher() (client.Fetcher, error) {
+	columns, err := csvfile.ParseColumns(viper.GetStringSlice("xlsxfile-columns"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &xlsxfile.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path:  viper.GetString("xlsxfile-path"),
+		Sheet: viper.GetString("xlsxfile-sheet"),
+		Mapping: csvfile.ClientOpts{
+			Columns:        columns,
+			OmitHeader:     viper.GetBool("xlsxfile-omit-header"),
+			Locale:         getLocale(),
+			DateFormat:     viper.GetString("xlsxfile-date-format"),
+			DateTimeFormat: viper.GetString("xlsxfile-datetime-format"),
+			DurationUnit:   viper.GetString("xlsxfile-duration-unit"),
+			Strict:         viper.GetBool("strict"),
+			RejectsPath:    viper.GetString("rejects-file"),
+		},
+	}
+
+	if viper.GetBool("infer-mapping") {
+		inferred, err := xlsxfile.InferColumns(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Mapping.Columns = acceptInferredColumns("xlsxfile-columns", opts.Path, inferred, opts.Mapping.Columns)
+	}
+
+	return xlsxfile.NewFetcher(opts)
+}
+
+func getYouTrackFetcher() (client.Fetcher, error) {
+	return youtrack.NewFetcher(&youtrack.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:             viper.GetString("youtrack-url"),
+		Token:               viper.GetString("youtrack-token"),
+		UnbillableWorkTypes: viper.GetStringSlice("youtrack-unbillable-work-types"),
 	})
 }
 
-func getFetcher() (client.Fetcher, error) {
+// getSourceNames returns the names of the sources. Multiple sources are set
+// separated by commas, like "clockify,bamboohr".
+func getSourceNames() []string {
+	var names []string
+
+	for _, name := range strings.Split(viper.GetString("source"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func getFetcher(source string) (client.Fetcher, error) {
 
 	var fetcher client.Fetcher
 	var err error
 
-	switch viper.GetString("source") {
+	switch source {
+	case "activitywatch":
+		fetcher, err = getActivityWatchFetcher()
+	case "bamboohr":
+		fetcher, err = getBambooHRFetcher()
 	case "clockify":
 		fetcher, err = getClockifyFetcher()
+	case "csvfile":
+		fetcher, err = getCSVFileFetcher()
+	case "git":
+		fetcher, err = getGitFetcher()
+	case "github":
+		fetcher, err = getGitHubFetcher()
+	case "gitlab":
+		fetcher, err = getGitLabFetcher()
+	case "googlecalendar":
+		fetcher, err = getGoogleCalendarFetcher()
+	case "hamster":
+		fetcher, err = getHamsterFetcher()
 	case "harvest":
 		fetcher, err = getHarvestFetcher()
+	case "hubstaff":
+		fetcher, err = getHubstaffFetcher()
+	case "icsfile":
+		fetcher, err = getICSFileFetcher()
+	case "jsonfile":
+		fetcher, err = getJSONFileFetcher()
+	case "kimai":
+		fetcher, err = getKimaiFetcher()
+	case "outlook":
+		fetcher, err = getOutlookFetcher()
+	case "personio":
+		fetcher, err = getPersonioFetcher()
+	case "redmine":
+		fetcher, err = getRedmineFetcher()
+	case "rescuetime":
+		fetcher, err = getRescueTimeFetcher()
 	case "tempo":
 		fetcher, err = getTempoFetcher()
+	case "timecamp":
+		fetcher, err = getTimeCampFetcher()
 	case "timewarrior":
 		fetcher, err = getTimeWarriorFetcher()
 	case "toggl":
 		fetcher, err = getTogglFetcher()
+	case "wakatime":
+		fetcher, err = getWakaTimeFetcher()
+	case "watson":
+		fetcher, err = getWatsonFetcher()
+	case "xlsxfile":
+		fetcher, err = getXLSXFileFetcher()
+	case "youtrack":
+		fetcher, err = getYouTrackFetcher()
 	default:
 		fetcher, err = nil, ErrNoSourceImplementation
 	}
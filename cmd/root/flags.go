@@ -4,103 +4,449 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/audit"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/activitywatch"
+	"github.com/gabor-boros/minutes/internal/pkg/client/calendar"
+	"github.com/gabor-boros/minutes/internal/pkg/client/clockify"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/git"
+	"github.com/gabor-boros/minutes/internal/pkg/client/github"
+	"github.com/gabor-boros/minutes/internal/pkg/client/gitlab"
+	"github.com/gabor-boros/minutes/internal/pkg/client/googlecalendar"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hamster"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hubstaff"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jira"
+	"github.com/gabor-boros/minutes/internal/pkg/client/outlook"
+	"github.com/gabor-boros/minutes/internal/pkg/client/rescuetime"
+	"github.com/gabor-boros/minutes/internal/pkg/client/tempo"
+	"github.com/gabor-boros/minutes/internal/pkg/client/timecamp"
+	"github.com/gabor-boros/minutes/internal/pkg/client/wakatime"
+	"github.com/gabor-boros/minutes/internal/pkg/client/watson"
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/pipeline"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/telemetry"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	sources = []string{"clockify", "harvest", "tempo", "timewarrior", "toggl"}
-	targets = []string{"tempo"}
+	sources = []string{"activitywatch", "bamboohr", "clockify", "csvfile", "git", "github", "gitlab", "googlecalendar", "hamster", "harvest", "hubstaff", "icsfile", "jsonfile", "kimai", "outlook", "personio", "redmine", "rescuetime", "tempo", "timecamp", "timewarrior", "toggl", "wakatime", "watson", "xlsxfile", "youtrack"}
+	targets = []string{"csvfile", "icsfile", "jira", "tempo", "xlsxfile"}
 )
 
 func initCommonFlags() {
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", fmt.Sprintf("config file (default is $HOME/.%s.yaml)", program))
 
-	rootCmd.Flags().StringP("start", "", "", "set the start date (defaults to 00:00:00)")
-	rootCmd.Flags().StringP("end", "", "", "set the end date (defaults to now)")
-	rootCmd.Flags().StringP("date-format", "", defaultDateFormat, "set start and end date format (in Go style)")
+	rootCmd.PersistentFlags().StringP("start", "", "", "set the start date (defaults to 00:00:00)")
+	rootCmd.PersistentFlags().StringP("end", "", "", "set the end date (defaults to now)")
+	rootCmd.PersistentFlags().StringP("date-format", "", defaultDateFormat, "set start and end date format (in Go style)")
+	rootCmd.PersistentFlags().StringP("range-end", "", rangeEndExclusive, fmt.Sprintf("set whether the entries starting at the end date are fetched %v", rangeEnds))
+	rootCmd.PersistentFlags().StringP("range-timezone", "", "Local", "set the IANA timezone of the start and end date, like \"Europe/Budapest\"")
+	rootCmd.PersistentFlags().StringP("future-entries", "", pipeline.FutureEntriesBlock, fmt.Sprintf("set how the entries starting in the future are handled %v", pipeline.FutureEntriesPolicies))
+	rootCmd.PersistentFlags().DurationP("future-tolerance", "", time.Minute*5, "set the tolerated clock skew of the entries starting in the future")
+
+	rootCmd.PersistentFlags().StringP("source-user", "", "", "set the source user ID")
+	rootCmd.PersistentFlags().StringP("source", "s", "", fmt.Sprintf("set the source of the sync, or multiple sources separated by commas %v", sources))
+	rootCmd.PersistentFlags().BoolP("continue-on-source-error", "", false, "upload the entries of the succeeded sources if fetching from other sources failed")
+
+	rootCmd.PersistentFlags().StringP("target-user", "", "", "set the source user ID")
+	rootCmd.PersistentFlags().StringP("target", "t", "", fmt.Sprintf("set the target of the sync %v", targets))
+	rootCmd.PersistentFlags().StringSliceP("route-targets", "", []string{}, "set the targets the entries can be routed to by a route tag in their summary")
+	rootCmd.PersistentFlags().StringP("route-tag-prefix", "", client.DefaultRouteTagPrefix, "set the prefix of the route tags, followed by the name of the target")
+
+	rootCmd.PersistentFlags().StringSliceP("table-sort-by", "", []string{utils.ColumnStart, utils.ColumnProject, utils.ColumnTask, utils.ColumnSummary}, fmt.Sprintf("sort table by column %v", utils.Columns))
+	rootCmd.PersistentFlags().StringSliceP("table-hide-column", "", []string{}, fmt.Sprintf("hide table column %v", utils.HideableColumns))
+	rootCmd.PersistentFlags().BoolP("plain", "", false, "print plain text without colors, box-drawing characters and animations")
+	rootCmd.PersistentFlags().BoolP("a11y", "", false, "print linear status lines and summaries instead of tables and progress bars, for screen readers")
+
+	rootCmd.PersistentFlags().StringP("locale", "", locale.DefaultName, fmt.Sprintf("set the number and date format of the reports %v", locale.Names()))
+	rootCmd.PersistentFlags().StringP("language", "", "", fmt.Sprintf("set the language of the messages and report labels %v; if not set, it is detected from the environment", i18n.Languages()))
+	rootCmd.PersistentFlags().BoolP("pseudonymize", "", false, "replace the client and project names of the reports with stable pseudonyms")
+
+	rootCmd.PersistentFlags().StringP("tags-as-tasks-regex", "", "", "regex of the task pattern")
+	rootCmd.PersistentFlags().StringP("task-extraction", "", worklog.TaskExtractionTags, fmt.Sprintf("set where the tasks are extracted from by the task pattern %v", worklog.TaskExtractionModes))
+
+	rootCmd.PersistentFlags().BoolP("round-to-closest-minute", "", false, "round time to closest minute")
+	rootCmd.PersistentFlags().BoolP("force-billed-duration", "", false, "treat every second spent as billed")
+	rootCmd.PersistentFlags().DurationP("round-increment", "", 0, "round time to a multiple of the increment, like 15m")
+	rootCmd.PersistentFlags().StringP("round-mode", "", pipeline.RoundNearest, fmt.Sprintf("set the direction of rounding to the increment %v", pipeline.RoundModes))
+	rootCmd.PersistentFlags().StringP("comment-template", "", "", "set the Go template used to render the uploaded comments")
+
+	rootCmd.PersistentFlags().StringP("filter-client", "", "", "filter for client name after fetching")
+	rootCmd.PersistentFlags().StringP("filter-project", "", "", "filter for project name after fetching")
+
+	rootCmd.PersistentFlags().StringP("mapping-file", "", "", "set the file containing the entry mappings")
+	rootCmd.PersistentFlags().StringSliceP("pipeline-stages", "", defaultPipelineStages, "set the order of the transformation pipeline stages")
+	rootCmd.PersistentFlags().DurationP("absence-duration", "", worklog.DefaultAbsenceDuration, "set the duration of a full day absence")
+
+	rootCmd.PersistentFlags().BoolP("overtime", "", false, "track the overtime balance across syncs")
+	rootCmd.PersistentFlags().DurationP("overtime-daily-duration", "", worklog.DefaultOvertimeDailyDuration, "set the expected time spent on a working day")
+	rootCmd.PersistentFlags().StringSliceP("overtime-working-days", "", []string{"monday", "tuesday", "wednesday", "thursday", "friday"}, "set the working days of the week")
+	rootCmd.PersistentFlags().StringToStringP("overtime-weekday-durations", "", map[string]string{}, "set the expected time spent per day of the week, like fri=4h, overriding the daily duration")
+	rootCmd.PersistentFlags().StringSliceP("overtime-holidays", "", []string{}, "set the holidays in YYYY-MM-DD format")
+	rootCmd.PersistentFlags().StringP("overtime-employment-start", "", "", "set the first day of the employment in YYYY-MM-DD format")
+	rootCmd.PersistentFlags().StringP("overtime-employment-end", "", "", "set the last day of the employment in YYYY-MM-DD format")
+
+	rootCmd.PersistentFlags().Float64P("anomaly-factor", "", worklog.DefaultAnomalyFactor, "set how many times a day's total may differ from the typical total in the history, 0 disables the warnings")
+	rootCmd.PersistentFlags().IntP("anomaly-min-days", "", worklog.DefaultAnomalyMinDays, "set the number of days in the history needed to tell the typical total")
+	rootCmd.PersistentFlags().IntP("anomaly-history-days", "", 90, "set the number of past days used to tell the typical total")
+
+	rootCmd.PersistentFlags().StringP("distribution-strategy", "", worklog.DistributionNone, fmt.Sprintf("set how the daily totals are distributed across the working hours %v", worklog.DistributionStrategies))
+	rootCmd.PersistentFlags().StringP("distribution-day-start", "", "09:00", "set the start of the working hours in 15:04 format")
+	rootCmd.PersistentFlags().StringP("distribution-day-end", "", "17:00", "set the end of the working hours in 15:04 format")
+
+	rootCmd.PersistentFlags().StringP("aggregation-mode", "", worklog.AggregationNone, fmt.Sprintf("set how the consecutive short entries are aggregated %v", worklog.AggregationModes))
+	rootCmd.PersistentFlags().DurationP("pomodoro-max-duration", "", worklog.DefaultPomodoroMaxDuration, "set the longest entry aggregated as a pomodoro")
+	rootCmd.PersistentFlags().DurationP("pomodoro-max-break", "", worklog.DefaultPomodoroMaxBreak, "set the longest break between consecutive pomodoros")
+
+	rootCmd.PersistentFlags().StringP("storage", "", "file", fmt.Sprintf("set the storage of the state and history %v", storages))
+	rootCmd.PersistentFlags().StringP("storage-path", "", "", "set the storage directory or SQLite database (defaults to the user config dir)")
+	rootCmd.PersistentFlags().StringP("storage-sqlite-command", "", "sqlite3", "set the SQLite executable name")
+	rootCmd.PersistentFlags().StringP("storage-s3-endpoint", "", storage.DefaultS3Endpoint, "set the S3 compatible endpoint")
+	rootCmd.PersistentFlags().StringP("storage-s3-bucket", "", "", "set the S3 bucket")
+	rootCmd.PersistentFlags().StringP("storage-s3-region", "", "us-east-1", "set the S3 region")
+	rootCmd.PersistentFlags().StringP("storage-s3-access-key", "", "", "set the S3 access key")
+	rootCmd.PersistentFlags().StringP("storage-s3-secret-key", "", "", "set the S3 secret key")
+	rootCmd.PersistentFlags().StringP("storage-s3-prefix", "", "", "set the prefix of the S3 object keys")
+
+	rootCmd.PersistentFlags().StringP("telemetry", "", telemetry.ModeOff, fmt.Sprintf("set the anonymous usage reporting mode %v", telemetry.Modes))
+	rootCmd.PersistentFlags().StringP("telemetry-url", "", telemetry.DefaultEndpoint, "set the endpoint receiving the usage reports")
+
+	rootCmd.PersistentFlags().StringP("audit-log", "", "", "append every call changing the target's data to the audit log file")
+	rootCmd.PersistentFlags().Int64P("audit-log-max-size", "", audit.DefaultMaxSize/1024/1024, "set the size of the audit log in megabytes, after which it is rotated")
+	rootCmd.PersistentFlags().IntP("audit-log-max-backups", "", 0, "set the number of rotated audit logs kept (0 keeps every log)")
+	rootCmd.PersistentFlags().StringP("audit-log-syslog", "", "", "ship the audit log to syslog, like udp://localhost:514 or unixgram:///dev/log")
+	rootCmd.PersistentFlags().StringP("author-strategy", "", client.AuthorStrategySource, fmt.Sprintf("set how the author of the uploaded entries is resolved %v", client.AuthorStrategies))
+	rootCmd.PersistentFlags().StringP("author-fixed-user", "", "", "set the author of every uploaded entry when using the fixed author strategy")
+	rootCmd.PersistentFlags().StringToStringP("author-map", "", map[string]string{}, "map the worker of the entries to the author when using the mapped author strategy, like jdoe=john.doe")
+	rootCmd.PersistentFlags().BoolP("impersonate", "", false, "upload the entries having the worker attribute on behalf of the worker, if listed by the target")
+
+	rootCmd.PersistentFlags().BoolP("strict", "", false, "stop importing file sources if any row is invalid")
+	rootCmd.PersistentFlags().StringP("rejects-file", "", "", "write the invalid rows of file sources to the file")
+	rootCmd.PersistentFlags().BoolP("infer-mapping", "", false, "infer the columns of file sources from the header and sample values")
+
+	rootCmd.PersistentFlags().BoolP("dry-run", "", false, "fetch entries, but do not sync them")
+	rootCmd.PersistentFlags().BoolP("verbose", "", false, "print the provenance of the entries")
+	rootCmd.PersistentFlags().DurationP("expected-run-duration", "", time.Hour, "refresh the credentials expiring within the expected duration of the run before it starts")
+	rootCmd.PersistentFlags().DurationP("fetch-deadline", "", 0, "set the time limit of fetching the entries from every source; no limit by default")
+	rootCmd.PersistentFlags().DurationP("resolve-deadline", "", 0, "set the time limit of transforming the fetched entries, like looking up their issues; no limit by default")
+	rootCmd.PersistentFlags().DurationP("upload-deadline", "", 0, "set the time limit of uploading every entry; no limit by default")
+	rootCmd.PersistentFlags().BoolP("show-payloads", "", false, "print the serialized payload the target sends for each entry")
+	rootCmd.PersistentFlags().StringP("limit-policy", "", client.LimitPolicyFail, fmt.Sprintf("set how the entries exceeding the limits of the target are handled %v", client.LimitPolicies))
+	rootCmd.PersistentFlags().IntP("verify-sample", "", 0, "fetch the given number of random uploaded worklogs from the target after the upload and compare them with the sent ones")
+	rootCmd.PersistentFlags().StringP("summary-file", "", "", "write the JSON summary of the sync to the file")
+	rootCmd.PersistentFlags().StringP("hook-command", "", "", "run the shell command after the sync, passing the JSON summary on its standard input")
+	rootCmd.PersistentFlags().StringP("hook-url", "", "", "post the JSON summary of the sync to the webhook URL")
+	rootCmd.PersistentFlags().DurationP("hook-timeout", "", time.Minute, "set the time limit of running the hook command and calling the webhook")
+	rootCmd.PersistentFlags().StringP("notify-teams-url", "", "", "post the summary card of the sync to the Microsoft Teams incoming webhook URL")
+	rootCmd.PersistentFlags().StringP("notify-discord-url", "", "", "post the summary card of the sync to the Discord webhook URL")
+	rootCmd.PersistentFlags().BoolP("history", "", false, "store the summary of the syncs and the upload receipts in the storage")
+	rootCmd.PersistentFlags().StringP("receipt-secret-key", "", "", "sign the upload receipts by the minisign secret key file")
+	rootCmd.PersistentFlags().StringP("receipt-public-key", "", "", "set the minisign public key file used to verify the upload receipts")
+	rootCmd.Flags().BoolP("version", "", false, "show command version")
+}
 
-	rootCmd.Flags().StringP("source-user", "", "", "set the source user ID")
-	rootCmd.Flags().StringP("source", "s", "", fmt.Sprintf("set the source of the sync %v", sources))
+func initActivityWatchFlags() {
+	rootCmd.PersistentFlags().StringP("activitywatch-url", "", activitywatch.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringSliceP("activitywatch-buckets", "", []string{}, "set the buckets the activities are fetched from; defaults to every window and browser bucket")
+	rootCmd.PersistentFlags().DurationP("activitywatch-min-duration", "", activitywatch.DefaultMinDuration, "set the shortest aggregated activity kept")
+	rootCmd.PersistentFlags().DurationP("activitywatch-merge-gap", "", activitywatch.DefaultMergeGap, "set the longest gap between the events of the same activity merged into one entry")
+}
 
-	rootCmd.Flags().StringP("target-user", "", "", "set the source user ID")
-	rootCmd.Flags().StringP("target", "t", "", fmt.Sprintf("set the target of the sync %v", targets))
+func initBambooHRFlags() {
+	rootCmd.PersistentFlags().StringP("bamboohr-url", "", "https://api.bamboohr.com", "set the base URL")
+	rootCmd.PersistentFlags().StringP("bamboohr-api-key", "", "", "set the API key")
+	rootCmd.PersistentFlags().StringP("bamboohr-company", "", "", "set the company subdomain")
+}
 
-	rootCmd.Flags().StringSliceP("table-sort-by", "", []string{utils.ColumnStart, utils.ColumnProject, utils.ColumnTask, utils.ColumnSummary}, fmt.Sprintf("sort table by column %v", utils.Columns))
-	rootCmd.Flags().StringSliceP("table-hide-column", "", []string{}, fmt.Sprintf("hide table column %v", utils.HideableColumns))
+func initCalendarFlags() {
+	rootCmd.PersistentFlags().StringP("calendar", "", "", fmt.Sprintf("write the uploaded entries back to the calendar as events %v", calendar.Providers))
+	rootCmd.PersistentFlags().StringP("calendar-id", "", "", "set the calendar ID (defaults to the primary calendar)")
+	rootCmd.PersistentFlags().StringP("calendar-url", "", "", "set the base URL of the calendar API (defaults to the provider's URL)")
+	rootCmd.PersistentFlags().StringP("calendar-token-url", "", "", "set the OAuth token endpoint (defaults to the provider's endpoint)")
+	rootCmd.PersistentFlags().StringP("calendar-client-id", "", "", "set the OAuth client ID")
+	rootCmd.PersistentFlags().StringP("calendar-client-secret", "", "", "set the OAuth client secret")
+	rootCmd.PersistentFlags().StringP("calendar-refresh-token", "", "", "set the OAuth refresh token")
+}
 
-	rootCmd.Flags().StringP("tags-as-tasks-regex", "", "", "regex of the task pattern")
+func initCSVFileFlags() {
+	var defaultColumns []string
+	for _, column := range csvfile.DefaultColumns {
+		defaultColumns = append(defaultColumns, column.Name)
+	}
 
-	rootCmd.Flags().BoolP("round-to-closest-minute", "", false, "round time to closest minute")
-	rootCmd.Flags().BoolP("force-billed-duration", "", false, "treat every second spent as billed")
+	rootCmd.PersistentFlags().StringP("csvfile-path", "", "", "set the path of the read or written CSV file")
+	rootCmd.PersistentFlags().StringSliceP("csvfile-columns", "", defaultColumns, fmt.Sprintf("set the columns in \"name\" or \"name:header\" format %v", csvfile.Columns))
+	rootCmd.PersistentFlags().StringP("csvfile-delimiter", "", string(csvfile.DefaultDelimiter), "set the field delimiter")
+	rootCmd.PersistentFlags().StringP("csvfile-duration-format", "", csvfile.DurationFormatDecimal, fmt.Sprintf("set the duration format %v", csvfile.DurationFormats))
+	rootCmd.PersistentFlags().IntP("csvfile-decimal-precision", "", csvfile.DefaultDecimalPrecision, "set the number of decimals of decimal durations")
+	rootCmd.PersistentFlags().StringP("csvfile-duration-unit", "", csvfile.DurationUnitHours, fmt.Sprintf("set the unit of the read durations given as plain numbers %v", csvfile.DurationUnits))
+	rootCmd.PersistentFlags().StringP("csvfile-date-format", "", "", "set the date format (in Go style), overriding the format of the locale")
+	rootCmd.PersistentFlags().StringP("csvfile-datetime-format", "", "", "set the date time format (in Go style), overriding the format of the locale")
+	rootCmd.PersistentFlags().BoolP("csvfile-omit-header", "", false, "do not read or write the header row")
+	rootCmd.PersistentFlags().BoolP("csvfile-split-by-cost-center", "", false, "write one file per cost center")
+}
 
-	rootCmd.Flags().StringP("filter-client", "", "", "filter for client name after fetching")
-	rootCmd.Flags().StringP("filter-project", "", "", "filter for project name after fetching")
+func initClockifyFlags() {
+	rootCmd.PersistentFlags().StringP("clockify-url", "", "https://api.clockify.me", "set the base URL")
+	rootCmd.PersistentFlags().StringP("clockify-api-key", "", "", "set the API key")
+	rootCmd.PersistentFlags().StringP("clockify-workspace", "", "", "set the workspace ID")
+	rootCmd.PersistentFlags().BoolP("clockify-fetch-time-off", "", false, "fetch approved time off as absence entries")
+	rootCmd.PersistentFlags().StringP("clockify-time-off-url", "", "https://pto.api.clockify.me", "set the base URL of the time off API")
+	rootCmd.PersistentFlags().BoolP("clockify-fetch-report", "", false, "fetch the entries from the detailed report of the workspace")
+	rootCmd.PersistentFlags().StringP("clockify-reports-url", "", "https://reports.api.clockify.me", "set the base URL of the reports API")
+	rootCmd.PersistentFlags().StringP("clockify-app-url", "", clockify.DefaultAppURL, "set the base URL of the web app linked from the fetched entries")
+}
 
-	rootCmd.Flags().BoolP("dry-run", "", false, "fetch entries, but do not sync them")
-	rootCmd.Flags().BoolP("version", "", false, "show command version")
+func initGitFlags() {
+	rootCmd.PersistentFlags().StringP("git-command", "", "git", "set the executable name")
+	rootCmd.PersistentFlags().StringSliceP("git-arguments", "", []string{}, "set additional arguments of git log")
+	rootCmd.PersistentFlags().StringSliceP("git-repositories", "", []string{"."}, "set the paths of the repositories")
+	rootCmd.PersistentFlags().StringP("git-duration-strategy", "", git.DurationStrategyTrailer, fmt.Sprintf("set how the time spent on the commits without Time-Spent trailer is set %v", git.DurationStrategies))
+	rootCmd.PersistentFlags().DurationP("git-assumed-duration", "", git.DefaultAssumedDuration, "set the time spent on the commits without Time-Spent trailer, or after a long gap")
+	rootCmd.PersistentFlags().DurationP("git-max-gap", "", git.DefaultMaxGap, "set the longest gap between two commits considered as continuous work")
 }
 
-func initClockifyFlags() {
-	rootCmd.Flags().StringP("clockify-url", "", "https://api.clockify.me", "set the base URL")
-	rootCmd.Flags().StringP("clockify-api-key", "", "", "set the API key")
-	rootCmd.Flags().StringP("clockify-workspace", "", "", "set the workspace ID")
+func initGitHubFlags() {
+	rootCmd.PersistentFlags().StringP("github-url", "", github.DefaultURL, "set the base URL of the API")
+	rootCmd.PersistentFlags().StringP("github-token", "", "", "set the personal access token")
+	rootCmd.PersistentFlags().StringP("github-user", "", "", "set the login of the user the activity is fetched for, defaults to the owner of the token")
+	rootCmd.PersistentFlags().DurationP("github-commit-duration", "", github.DefaultCommitDuration, "set the time accounted for the commits of a repository on a day")
+	rootCmd.PersistentFlags().DurationP("github-review-duration", "", github.DefaultReviewDuration, "set the time accounted for a pull request review")
+	rootCmd.PersistentFlags().DurationP("github-comment-duration", "", github.DefaultCommentDuration, "set the time accounted for a comment")
+}
+
+func initGitLabFlags() {
+	rootCmd.PersistentFlags().StringP("gitlab-url", "", gitlab.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("gitlab-token", "", "", "set the personal access token")
+	rootCmd.PersistentFlags().StringP("gitlab-user", "", "", "set the username of the user the timelogs are fetched for, defaults to the owner of the token")
+}
+
+func initGoogleCalendarFlags() {
+	rootCmd.PersistentFlags().StringP("googlecalendar-url", "", googlecalendar.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("googlecalendar-token-url", "", googlecalendar.DefaultTokenURL, "set the OAuth token endpoint")
+	rootCmd.PersistentFlags().StringP("googlecalendar-device-code-url", "", googlecalendar.DefaultDeviceCodeURL, "set the OAuth device authorization endpoint")
+	rootCmd.PersistentFlags().StringP("googlecalendar-client-id", "", "", "set the OAuth client ID")
+	rootCmd.PersistentFlags().StringP("googlecalendar-client-secret", "", "", "set the OAuth client secret")
+	rootCmd.PersistentFlags().StringP("googlecalendar-refresh-token", "", "", "set the OAuth refresh token obtained by authorize-googlecalendar")
+	rootCmd.PersistentFlags().StringSliceP("googlecalendar-calendars", "", []string{googlecalendar.DefaultCalendarID}, "set the IDs of the calendars the events are read from")
+}
+
+func initHamsterFlags() {
+	rootCmd.PersistentFlags().StringP("hamster-database", "", hamster.DefaultDatabasePath(), "set the path of the Hamster database")
+	rootCmd.PersistentFlags().StringP("hamster-sqlite-command", "", "sqlite3", "set the SQLite executable name")
+	rootCmd.PersistentFlags().StringP("hamster-unbillable-tag", "", "unbillable", "set the unbillable tag")
 }
 
 func initHarvestFlags() {
-	rootCmd.Flags().StringP("harvest-api-key", "", "", "set the API key")
-	rootCmd.Flags().IntP("harvest-account", "", 0, "set the Account ID")
+	rootCmd.PersistentFlags().StringP("harvest-api-key", "", "", "set the API key")
+	rootCmd.PersistentFlags().IntP("harvest-account", "", 0, "set the Account ID")
+}
+
+func initHubstaffFlags() {
+	rootCmd.PersistentFlags().StringP("hubstaff-url", "", hubstaff.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("hubstaff-token-url", "", hubstaff.DefaultTokenURL, "set the endpoint exchanging the personal access token")
+	rootCmd.PersistentFlags().StringP("hubstaff-token", "", "", "set the personal access token")
+	rootCmd.PersistentFlags().StringP("hubstaff-organization", "", "", "set the organization ID")
+	rootCmd.PersistentFlags().StringP("hubstaff-idle", "", hubstaff.IdleModeKeep, fmt.Sprintf("set the handling of the idle time; options: %s", strings.Join(hubstaff.IdleModes, ", ")))
+	rootCmd.PersistentFlags().BoolP("hubstaff-include-breaks", "", false, "fetch the paid work breaks as unbillable entries")
+}
+
+func initICSFileFlags() {
+	rootCmd.PersistentFlags().StringP("icsfile-path", "", "", "set the path of the read or written ICS file, or the URL of the read calendar")
+}
+
+func initJiraFlags() {
+	rootCmd.PersistentFlags().StringP("jira-url", "", "", "set the base URL (defaults to the Tempo URL)")
+	rootCmd.PersistentFlags().StringP("jira-username", "", "", "set the login user ID (defaults to the Tempo username)")
+	rootCmd.PersistentFlags().StringP("jira-password", "", "", "set the login password or API token (defaults to the Tempo password)")
+	rootCmd.PersistentFlags().BoolP("jira-service-desk", "", false, "set the request type and SLA status of Jira Service Management issues as entry attributes")
+	rootCmd.PersistentFlags().BoolP("jira-classification", "", false, "classify the entries by the classification rules, like CAPEX or OPEX")
+	rootCmd.PersistentFlags().StringP("jira-classification-default", "", "", "set the classification of the entries not matching any rule")
+	rootCmd.PersistentFlags().BoolP("jira-rollup", "", false, "log time against the ancestor issue, like the epic, instead of the individual issue")
+	rootCmd.PersistentFlags().IntP("jira-rollup-level", "", jira.HierarchyLevelEpic, "set the hierarchy level of the ancestor issue (0 for standard issues, 1 for epics)")
+}
+
+func initJSONFileFlags() {
+	rootCmd.PersistentFlags().StringP("jsonfile-path", "", "", "set the path of the read JSON or newline-delimited JSON file")
+}
+
+func initKimaiFlags() {
+	rootCmd.PersistentFlags().StringP("kimai-url", "", "", "set the base URL")
+	rootCmd.PersistentFlags().StringP("kimai-token", "", "", "set the API token")
+	rootCmd.PersistentFlags().BoolP("kimai-include-exported", "", false, "fetch the exported timesheets too")
+}
+
+func initOutlookFlags() {
+	rootCmd.PersistentFlags().StringP("outlook-url", "", outlook.DefaultURL, "set the base URL of Microsoft Graph")
+	rootCmd.PersistentFlags().StringP("outlook-token-url", "", outlook.DefaultTokenURL, "set the OAuth token endpoint")
+	rootCmd.PersistentFlags().StringP("outlook-device-code-url", "", outlook.DefaultDeviceCodeURL, "set the OAuth device authorization endpoint")
+	rootCmd.PersistentFlags().StringP("outlook-client-id", "", "", "set the OAuth client (application) ID")
+	rootCmd.PersistentFlags().StringP("outlook-client-secret", "", "", "set the OAuth client secret")
+	rootCmd.PersistentFlags().StringP("outlook-refresh-token", "", "", "set the OAuth refresh token obtained by authorize-outlook")
+	rootCmd.PersistentFlags().StringSliceP("outlook-calendars", "", []string{}, "set the IDs of the calendars the events are read from (defaults to the default calendar)")
+}
+
+func initPersonioFlags() {
+	rootCmd.PersistentFlags().StringP("personio-url", "", "https://api.personio.de", "set the base URL")
+	rootCmd.PersistentFlags().StringP("personio-client-id", "", "", "set the API client ID")
+	rootCmd.PersistentFlags().StringP("personio-client-secret", "", "", "set the API client secret")
+}
+
+func initRedmineFlags() {
+	rootCmd.PersistentFlags().StringP("redmine-url", "", "", "set the base URL")
+	rootCmd.PersistentFlags().StringP("redmine-api-key", "", "", "set the API access key")
+	rootCmd.PersistentFlags().StringSliceP("redmine-unbillable-activities", "", []string{}, "set the activities the time entries of which are unbillable")
+}
+
+func initRescueTimeFlags() {
+	rootCmd.PersistentFlags().StringP("rescuetime-url", "", rescuetime.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("rescuetime-api-key", "", "", "set the Analytic Data API key")
+	rootCmd.PersistentFlags().StringP("rescuetime-granularity", "", rescuetime.GranularityHour, fmt.Sprintf("set the interval the activities are grouped by; options: %s", strings.Join(rescuetime.Granularities, ", ")))
 }
 
 func initTempoFlags() {
-	rootCmd.Flags().StringP("tempo-url", "", "", "set the base URL")
-	rootCmd.Flags().StringP("tempo-username", "", "", "set the login user ID")
-	rootCmd.Flags().StringP("tempo-password", "", "", "set the login password")
+	rootCmd.PersistentFlags().StringP("tempo-url", "", "", "set the base URL")
+	rootCmd.PersistentFlags().StringP("tempo-username", "", "", "set the login user ID")
+	rootCmd.PersistentFlags().StringP("tempo-password", "", "", "set the login password")
+	rootCmd.PersistentFlags().StringP("tempo-team-attribute", "", tempo.DefaultTeamAttribute, "set the work attribute key of the team")
+	rootCmd.PersistentFlags().StringP("tempo-role-attribute", "", tempo.DefaultRoleAttribute, "set the work attribute key of the role")
+	rootCmd.PersistentFlags().StringP("tempo-classification-attribute", "", tempo.DefaultClassificationAttribute, "set the work attribute key of the classification")
+	rootCmd.PersistentFlags().IntP("tempo-max-comment-length", "", tempo.DefaultMaxCommentLength, "set the maximum length of the worklog comments")
+	rootCmd.PersistentFlags().StringP("tempo-issue-comment-template", "", "", "set the Go template of the comment posted on the Jira issue of the uploaded worklogs")
+	rootCmd.PersistentFlags().DurationP("tempo-issue-comment-min-duration", "", 0, "set the minimum duration of the worklogs commented on their Jira issue")
+	rootCmd.PersistentFlags().StringSliceP("tempo-impersonate-users", "", []string{}, "set the users the worklogs can be uploaded on behalf of when impersonating")
+	rootCmd.PersistentFlags().StringP("tempo-oauth-client-id", "", "", "set the client ID of the Tempo Cloud OAuth app, used instead of the username and password")
+	rootCmd.PersistentFlags().StringP("tempo-oauth-client-secret", "", "", "set the client secret of the Tempo Cloud OAuth app")
+	rootCmd.PersistentFlags().StringP("tempo-oauth-redirect-url", "", "", "set the redirect URL of the Tempo Cloud OAuth app")
+	rootCmd.PersistentFlags().StringP("tempo-oauth-refresh-token", "", "", "set the OAuth refresh token obtained by authorize-tempo")
+	rootCmd.PersistentFlags().StringP("tempo-oauth-token-url", "", tempo.DefaultOAuthTokenURL, "set the OAuth token endpoint of Tempo Cloud")
+}
+
+func initTimeCampFlags() {
+	rootCmd.PersistentFlags().StringP("timecamp-url", "", timecamp.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("timecamp-token", "", "", "set the API token")
 }
 
 func initTimewarriorFlags() {
-	rootCmd.Flags().StringP("timewarrior-command", "", "timew", "set the executable name")
-	rootCmd.Flags().StringSliceP("timewarrior-arguments", "", []string{}, "set additional arguments")
+	rootCmd.PersistentFlags().StringP("timewarrior-command", "", "timew", "set the executable name")
+	rootCmd.PersistentFlags().StringSliceP("timewarrior-arguments", "", []string{}, "set additional arguments")
+	rootCmd.PersistentFlags().StringP("timewarrior-data-dir", "", "", "set the data directory to read instead of executing the command")
 
-	rootCmd.Flags().StringP("timewarrior-unbillable-tag", "", "unbillable", "set the unbillable tag")
-	rootCmd.Flags().StringP("timewarrior-client-tag-regex", "", "", "regex of client tag pattern")
-	rootCmd.Flags().StringP("timewarrior-project-tag-regex", "", "", "regex of project tag pattern")
+	rootCmd.PersistentFlags().StringP("timewarrior-unbillable-tag", "", "unbillable", "set the unbillable tag")
+	rootCmd.PersistentFlags().StringP("timewarrior-client-tag-regex", "", "", "regex of client tag pattern")
+	rootCmd.PersistentFlags().StringP("timewarrior-project-tag-regex", "", "", "regex of project tag pattern")
 }
 
 func initTogglFlags() {
-	rootCmd.Flags().StringP("toggl-api-key", "", "", "set the API key")
-	rootCmd.Flags().IntP("toggl-workspace", "", 0, "set the workspace ID")
+	rootCmd.PersistentFlags().StringP("toggl-api-key", "", "", "set the API key")
+	rootCmd.PersistentFlags().IntP("toggl-workspace", "", 0, "set the workspace ID")
+}
+
+func initWakaTimeFlags() {
+	rootCmd.PersistentFlags().StringP("wakatime-url", "", wakatime.DefaultURL, "set the base URL")
+	rootCmd.PersistentFlags().StringP("wakatime-api-key", "", "", "set the API key")
+	rootCmd.PersistentFlags().StringP("wakatime-user", "", wakatime.DefaultUser, "set the ID or username of the user the durations are fetched for")
+}
+
+func initWatsonFlags() {
+	rootCmd.PersistentFlags().StringP("watson-frames-file", "", watson.DefaultFramesPath(), "set the path of the Watson frames file")
+	rootCmd.PersistentFlags().StringP("watson-unbillable-tag", "", "unbillable", "set the unbillable tag")
+}
+
+func initXLSXFileFlags() {
+	var defaultColumns []string
+	for _, column := range csvfile.DefaultColumns {
+		defaultColumns = append(defaultColumns, column.Name)
+	}
+
+	rootCmd.PersistentFlags().StringP("xlsxfile-path", "", "", "set the path of the read or written XLSX file")
+	rootCmd.PersistentFlags().StringP("xlsxfile-sheet", "", "", "set the name of the read sheet (defaults to the first sheet)")
+	rootCmd.PersistentFlags().StringSliceP("xlsxfile-columns", "", defaultColumns, fmt.Sprintf("set the read columns in \"name\" or \"name:header\" format %v", csvfile.Columns))
+	rootCmd.PersistentFlags().StringP("xlsxfile-duration-unit", "", csvfile.DurationUnitHours, fmt.Sprintf("set the unit of the read durations given as plain numbers %v", csvfile.DurationUnits))
+	rootCmd.PersistentFlags().StringP("xlsxfile-date-format", "", "", "set the date format (in Go style) of the dates stored as text, overriding the format of the locale")
+	rootCmd.PersistentFlags().StringP("xlsxfile-datetime-format", "", "", "set the date time format (in Go style) of the date times stored as text, overriding the format of the locale")
+	rootCmd.PersistentFlags().BoolP("xlsxfile-omit-header", "", false, "do not read the header row")
+}
+
+func initYouTrackFlags() {
+	rootCmd.PersistentFlags().StringP("youtrack-url", "", "", "set the base URL")
+	rootCmd.PersistentFlags().StringP("youtrack-token", "", "", "set the permanent token")
+	rootCmd.PersistentFlags().StringSliceP("youtrack-unbillable-work-types", "", []string{}, "set the work types the work items of which are unbillable")
 }
 
 func validateFlags() {
-	var err error
-	source := viper.GetString("source")
+	validateSourceFlags()
+
 	target := viper.GetString("target")
 
-	if source == "" {
-		cobra.CheckErr("sync source must be set")
+	if target == "" {
+		cobra.CheckErr(tr("sync target must be set"))
 	}
 
-	if target == "" {
-		cobra.CheckErr("sync target must be set")
+	if utils.IsSliceContains(target, getSourceNames()) {
+		cobra.CheckErr(tr("sync source cannot match the target"))
 	}
 
-	if source == target {
-		cobra.CheckErr("sync source cannot match the target")
+	if !utils.IsSliceContains(target, targets) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported targets %v\n", target, targets))
 	}
 
-	if !utils.IsSliceContains(source, sources) {
-		cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the supported sources %v\n", source, sources))
+	validateTargetSpecificFlags(target)
+
+	if len(viper.GetStringSlice("route-targets")) != 0 {
+		validateRouteFlags()
 	}
 
-	if !utils.IsSliceContains(target, targets) {
-		cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the supported targets %v\n", target, targets))
+	_, err := client.NewCommentTemplate(viper.GetString("comment-template"))
+	cobra.CheckErr(err)
+
+	cobra.CheckErr(getReporterOpts().Validate())
+
+	if limitPolicy := viper.GetString("limit-policy"); !utils.IsSliceContains(limitPolicy, client.LimitPolicies) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported limit policies %v\n", limitPolicy, client.LimitPolicies))
 	}
 
-	tagsAsTasksRegex := viper.GetString("tags-as-tasks-regex")
-	_, err = regexp.Compile(tagsAsTasksRegex)
+	if viper.GetInt("verify-sample") < 0 {
+		cobra.CheckErr(tr("verify sample must not be negative"))
+	}
+
+	if viper.GetInt("tempo-max-comment-length") <= 0 {
+		cobra.CheckErr(tr("tempo max comment length must be positive"))
+	}
+
+	_, err = client.NewCommentTemplate(viper.GetString("tempo-issue-comment-template"))
+	cobra.CheckErr(err)
+
+	if viper.GetDuration("tempo-issue-comment-min-duration") < 0 {
+		cobra.CheckErr(tr("tempo issue comment min duration must not be negative"))
+	}
+
+	if viper.GetString("audit-log") != "" {
+		validateAuditLogFlags()
+	}
+
+	validateAuthorFlags()
+
+	if viper.GetBool("impersonate") {
+		validateImpersonationFlags()
+	}
+
+	if getHookOpts().IsEnabled() && viper.GetDuration("hook-timeout") <= 0 {
+		cobra.CheckErr(tr("hook timeout must be positive"))
+	}
+
+	_, err = locale.Get(viper.GetString("locale"))
+	cobra.CheckErr(err)
+
+	_, err = i18n.Get(viper.GetString("language"))
 	cobra.CheckErr(err)
 
 	for _, sortBy := range viper.GetStringSlice("table-sort-by") {
@@ -111,38 +457,556 @@ func validateFlags() {
 		}
 
 		if !utils.IsSliceContains(column, utils.Columns) {
-			cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the sortable columns %v\n", column, utils.Columns))
+			cobra.CheckErr(tr("\"%s\" is not part of the sortable columns %v\n", column, utils.Columns))
 		}
 	}
 
 	for _, column := range viper.GetStringSlice("table-hide-column") {
 		if !utils.IsSliceContains(column, utils.HideableColumns) {
-			cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the hideable columns %v\n", column, utils.HideableColumns))
+			cobra.CheckErr(tr("\"%s\" is not part of the hideable columns %v\n", column, utils.HideableColumns))
+		}
+	}
+
+	if viper.GetBool("overtime") {
+		validateOvertimeFlags()
+		validateStorageFlags()
+	}
+
+	if viper.GetBool("history") {
+		validateAnomalyFlags()
+		validateStorageFlags()
+	}
+
+	if viper.GetString("receipt-secret-key") != "" {
+		if !viper.GetBool("history") {
+			cobra.CheckErr(tr("history must be enabled to sign the upload receipts"))
+		}
+
+		_, err = getReceiptSecretKey()
+		cobra.CheckErr(err)
+	}
+}
+
+// validateAuditLogFlags validates the flags used to write the audit log.
+func validateAuditLogFlags() {
+	if viper.GetInt64("audit-log-max-size") <= 0 {
+		cobra.CheckErr(tr("audit log max size must be positive"))
+	}
+
+	if viper.GetInt("audit-log-max-backups") < 0 {
+		cobra.CheckErr(tr("audit log max backups must not be negative"))
+	}
+
+	cobra.CheckErr(getAuditLoggerOpts().Validate())
+}
+
+// validateAuthorFlags validates the flags used to resolve the author of the
+// uploaded entries. Only the targets setting the author per entry support
+// other authors than the target user.
+func validateAuthorFlags() {
+	authorOpts := getAuthorOpts()
+	cobra.CheckErr(authorOpts.Validate())
+
+	switch authorOpts.Strategy {
+	case client.AuthorStrategyFixed, client.AuthorStrategyMapped:
+		if target := viper.GetString("target"); target != "tempo" && target != "csvfile" {
+			cobra.CheckErr(tr("the %s author strategy is not supported by the %s target", authorOpts.Strategy, target))
+		}
+	}
+}
+
+// validateImpersonationFlags validates the flags used to upload the entries on
+// behalf of other users. Every impersonated upload must be audited.
+func validateImpersonationFlags() {
+	if target := viper.GetString("target"); target != "tempo" {
+		cobra.CheckErr(tr("impersonation is not supported by the %s target", target))
+	}
+
+	if viper.GetString("audit-log") == "" {
+		cobra.CheckErr(tr("audit log must be set to impersonate users"))
+	}
+
+	if len(viper.GetStringSlice("tempo-impersonate-users")) == 0 {
+		cobra.CheckErr(tr("tempo impersonate users must be set to impersonate users"))
+	}
+}
+
+// validateAnomalyFlags validates the flags used to detect the days differing
+// from the typical total.
+func validateAnomalyFlags() {
+	if factor := viper.GetFloat64("anomaly-factor"); factor != 0 && factor <= 1 {
+		cobra.CheckErr(tr("anomaly factor must be greater than 1, or 0 to disable the warnings"))
+	}
+
+	if viper.GetInt("anomaly-min-days") <= 0 {
+		cobra.CheckErr(tr("anomaly min days must be positive"))
+	}
+
+	if viper.GetInt("anomaly-history-days") <= 0 {
+		cobra.CheckErr(tr("anomaly history days must be positive"))
+	}
+}
+
+// validateOvertimeFlags validates the flags used to calculate the overtime
+// balance.
+func validateOvertimeFlags() {
+	if viper.GetDuration("overtime-daily-duration") <= 0 {
+		cobra.CheckErr(tr("overtime daily duration must be positive"))
+	}
+
+	for _, name := range viper.GetStringSlice("overtime-working-days") {
+		_, err := utils.ParseWeekday(name)
+		cobra.CheckErr(err)
+	}
+
+	_, err := getWeekdayDurations()
+	cobra.CheckErr(err)
+
+	for _, holiday := range viper.GetStringSlice("overtime-holidays") {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			cobra.CheckErr(tr("\"%s\" is not a valid holiday date in YYYY-MM-DD format\n", holiday))
+		}
+	}
+
+	employmentStart := viper.GetString("overtime-employment-start")
+	employmentEnd := viper.GetString("overtime-employment-end")
+
+	for _, date := range []string{employmentStart, employmentEnd} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			cobra.CheckErr(tr("\"%s\" is not a valid employment date in YYYY-MM-DD format\n", date))
+		}
+	}
+
+	if employmentStart != "" && employmentEnd != "" && employmentEnd < employmentStart {
+		cobra.CheckErr(tr("overtime employment end must not be before the start"))
+	}
+
+	_, err = getPartTimePeriods()
+	cobra.CheckErr(err)
+}
+
+// validateStorageFlags validates the flags required to access the storage.
+// Only subcommands using the storage should call it.
+func validateStorageFlags() {
+	storageName := viper.GetString("storage")
+
+	if !utils.IsSliceContains(storageName, storages) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported storages %v\n", storageName, storages))
+	}
+
+	switch storageName {
+	case "sqlite":
+		if viper.GetString("storage-sqlite-command") == "" {
+			cobra.CheckErr(tr("SQLite command must be set"))
+		}
+	case "s3":
+		if viper.GetString("storage-s3-bucket") == "" {
+			cobra.CheckErr(tr("S3 bucket must be set"))
+		}
+
+		if viper.GetString("storage-s3-access-key") == "" || viper.GetString("storage-s3-secret-key") == "" {
+			cobra.CheckErr(tr("S3 access key and secret key must be set"))
+		}
+	}
+}
+
+// validatePurgeFlags validates the flags used to purge the local data.
+func validatePurgeFlags() {
+	validateStorageFlags()
+
+	for _, kind := range viper.GetStringSlice("purge-data") {
+		if !utils.IsSliceContains(kind, purgeDataKinds) {
+			cobra.CheckErr(tr("\"%s\" is not part of the purgeable data %v\n", kind, purgeDataKinds))
+		}
+	}
+
+	if viper.GetInt("retention-days") < 0 {
+		cobra.CheckErr(tr("retention days must not be negative"))
+	}
+}
+
+// validateJiraFlags validates the flags required to look up issues in Jira or
+// upload worklogs to Jira.
+func validateJiraFlags() {
+	if getJiraOption("url") == "" {
+		cobra.CheckErr(tr("jira URL must be set"))
+	}
+
+	if getJiraOption("username") == "" || getJiraOption("password") == "" {
+		cobra.CheckErr(tr("jira username and password must be set"))
+	}
+}
+
+// validateSourceFlags validates the flags required to fetch entries from the
+// source. Subcommands that only fetch entries should call this instead of
+// validateFlags.
+// validateTargetSpecificFlags validates the flags of the target.
+func validateTargetSpecificFlags(target string) {
+	switch target {
+	case "csvfile":
+		if viper.GetString("csvfile-path") == "" {
+			cobra.CheckErr(tr("csvfile path must be set"))
+		}
+
+		_, err := csvfile.ParseColumns(viper.GetStringSlice("csvfile-columns"))
+		cobra.CheckErr(err)
+
+		_, err = csvfile.ParseDelimiter(viper.GetString("csvfile-delimiter"))
+		cobra.CheckErr(err)
+
+		if !utils.IsSliceContains(viper.GetString("csvfile-duration-format"), csvfile.DurationFormats) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported duration formats %v\n", viper.GetString("csvfile-duration-format"), csvfile.DurationFormats))
+		}
+
+		if viper.GetInt("csvfile-decimal-precision") <= 0 {
+			cobra.CheckErr(tr("csvfile decimal precision must be positive"))
+		}
+
+		costCenters, err := getCostCenters()
+		cobra.CheckErr(err)
+
+		if viper.GetBool("csvfile-split-by-cost-center") && len(costCenters) == 0 {
+			cobra.CheckErr(tr("cost centers must be set to split the csvfile by cost center"))
+		}
+	case "icsfile":
+		if viper.GetString("icsfile-path") == "" {
+			cobra.CheckErr(tr("icsfile path must be set"))
+		}
+	case "jira":
+		validateJiraFlags()
+	case "xlsxfile":
+		if viper.GetString("xlsxfile-path") == "" {
+			cobra.CheckErr(tr("xlsxfile path must be set"))
+		}
+
+		// The daily target and the working days are set by the overtime flags
+		validateOvertimeFlags()
+	}
+}
+
+// validateRouteFlags validates the targets the entries can be routed to. The
+// route targets are validated the same way as the target.
+func validateRouteFlags() {
+	target := viper.GetString("target")
+	routeTargets := viper.GetStringSlice("route-targets")
+
+	for i, routeTarget := range routeTargets {
+		if !utils.IsSliceContains(routeTarget, targets) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported targets %v\n", routeTarget, targets))
+		}
+
+		if routeTarget == target || utils.IsSliceContains(routeTarget, routeTargets[:i]) {
+			cobra.CheckErr(tr("\"%s\" route target is set multiple times\n", routeTarget))
+		}
+
+		if utils.IsSliceContains(routeTarget, getSourceNames()) {
+			cobra.CheckErr(tr("sync source cannot match the route targets"))
+		}
+
+		validateTargetSpecificFlags(routeTarget)
+	}
+
+	if strings.TrimSpace(viper.GetString("route-tag-prefix")) == "" {
+		cobra.CheckErr(tr("route tag prefix must be set"))
+	}
+}
+
+func validateSourceFlags() {
+	var err error
+	sourceNames := getSourceNames()
+
+	if len(sourceNames) == 0 {
+		cobra.CheckErr(tr("sync source must be set"))
+	}
+
+	for i, source := range sourceNames {
+		if !utils.IsSliceContains(source, sources) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported sources %v\n", source, sources))
+		}
+
+		if utils.IsSliceContains(source, sourceNames[:i]) {
+			cobra.CheckErr(tr("\"%s\" source is set multiple times\n", source))
 		}
 	}
 
+	if rangeEnd := viper.GetString("range-end"); !utils.IsSliceContains(rangeEnd, rangeEnds) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported range ends %v\n", rangeEnd, rangeEnds))
+	}
+
+	_, err = time.LoadLocation(viper.GetString("range-timezone"))
+	cobra.CheckErr(err)
+
+	tagsAsTasksRegex := viper.GetString("tags-as-tasks-regex")
+	_, err = regexp.Compile(tagsAsTasksRegex)
+	cobra.CheckErr(err)
+
+	if taskExtraction := viper.GetString("task-extraction"); !utils.IsSliceContains(taskExtraction, worklog.TaskExtractionModes) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported task extraction modes %v\n", taskExtraction, worklog.TaskExtractionModes))
+	}
+
 	_, err = regexp.Compile(viper.GetString("filter-client"))
 	cobra.CheckErr(err)
 
 	_, err = regexp.Compile(viper.GetString("filter-project"))
 	cobra.CheckErr(err)
 
+	for _, stage := range viper.GetStringSlice("pipeline-stages") {
+		if !utils.IsSliceContains(stage, defaultPipelineStages) {
+			cobra.CheckErr(tr("\"%s\" is not part of the pipeline stages %v\n", stage, defaultPipelineStages))
+		}
+	}
+
+	if futureEntries := viper.GetString("future-entries"); !utils.IsSliceContains(futureEntries, pipeline.FutureEntriesPolicies) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported future entries policies %v\n", futureEntries, pipeline.FutureEntriesPolicies))
+	}
+
+	if viper.GetDuration("future-tolerance") < 0 {
+		cobra.CheckErr(tr("future tolerance must not be negative"))
+	}
+
+	if viper.GetDuration("round-increment") < 0 {
+		cobra.CheckErr(tr("round increment must not be negative"))
+	}
+
+	if roundMode := viper.GetString("round-mode"); !utils.IsSliceContains(roundMode, pipeline.RoundModes) {
+		cobra.CheckErr(tr("\"%s\" is not part of the supported round modes %v\n", roundMode, pipeline.RoundModes))
+	}
+
+	if viper.GetDuration("absence-duration") <= 0 {
+		cobra.CheckErr(tr("absence duration must be positive"))
+	}
+
+	if viper.GetDuration("expected-run-duration") < 0 {
+		cobra.CheckErr(tr("expected run duration must not be negative"))
+	}
+
+	if viper.GetDuration("fetch-deadline") < 0 {
+		cobra.CheckErr(tr("fetch deadline must not be negative"))
+	}
+
+	if viper.GetDuration("resolve-deadline") < 0 {
+		cobra.CheckErr(tr("resolve deadline must not be negative"))
+	}
+
+	if viper.GetDuration("upload-deadline") < 0 {
+		cobra.CheckErr(tr("upload deadline must not be negative"))
+	}
+
+	_, err = getDistributionOpts()
+	cobra.CheckErr(err)
+
+	_, err = getAggregationOpts()
+	cobra.CheckErr(err)
+
+	if isJiraLookupEnabled() {
+		validateJiraFlags()
+	}
+
+	if viper.GetString("calendar") != "" {
+		_, err = getCalendarWriter()
+		cobra.CheckErr(err)
+	}
+
+	for _, source := range sourceNames {
+		validateSourceSpecificFlags(source)
+	}
+}
+
+// validateSourceSpecificFlags validates the flags of the source.
+func validateSourceSpecificFlags(source string) {
 	switch source {
+	case "activitywatch":
+		if viper.GetString("activitywatch-url") == "" {
+			cobra.CheckErr(tr("activitywatch url must be set"))
+		}
+
+		if viper.GetDuration("activitywatch-min-duration") < 0 {
+			cobra.CheckErr(tr("activitywatch min duration must not be negative"))
+		}
+
+		if viper.GetDuration("activitywatch-merge-gap") < 0 {
+			cobra.CheckErr(tr("activitywatch merge gap must not be negative"))
+		}
+	case "csvfile":
+		if viper.GetString("csvfile-path") == "" {
+			cobra.CheckErr(tr("csvfile path must be set"))
+		}
+
+		_, err := csvfile.ParseColumns(viper.GetStringSlice("csvfile-columns"))
+		cobra.CheckErr(err)
+
+		_, err = csvfile.ParseDelimiter(viper.GetString("csvfile-delimiter"))
+		cobra.CheckErr(err)
+
+		if durationUnit := viper.GetString("csvfile-duration-unit"); !utils.IsSliceContains(durationUnit, csvfile.DurationUnits) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported duration units %v\n", durationUnit, csvfile.DurationUnits))
+		}
+	case "xlsxfile":
+		if viper.GetString("xlsxfile-path") == "" {
+			cobra.CheckErr(tr("xlsxfile path must be set"))
+		}
+
+		_, err := csvfile.ParseColumns(viper.GetStringSlice("xlsxfile-columns"))
+		cobra.CheckErr(err)
+
+		if durationUnit := viper.GetString("xlsxfile-duration-unit"); !utils.IsSliceContains(durationUnit, csvfile.DurationUnits) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported duration units %v\n", durationUnit, csvfile.DurationUnits))
+		}
+	case "bamboohr":
+		if viper.GetString("bamboohr-company") == "" {
+			cobra.CheckErr(tr("bamboohr company must be set"))
+		}
+	case "git":
+		if viper.GetString("git-command") == "" {
+			cobra.CheckErr(tr("git command must be set"))
+		}
+
+		if len(viper.GetStringSlice("git-repositories")) == 0 {
+			cobra.CheckErr(tr("git repositories must be set"))
+		}
+
+		if durationStrategy := viper.GetString("git-duration-strategy"); !utils.IsSliceContains(durationStrategy, git.DurationStrategies) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported duration strategies %v\n", durationStrategy, git.DurationStrategies))
+		}
+
+		if viper.GetDuration("git-assumed-duration") <= 0 {
+			cobra.CheckErr(tr("git assumed duration must be positive"))
+		}
+
+		if viper.GetDuration("git-max-gap") <= 0 {
+			cobra.CheckErr(tr("git max gap must be positive"))
+		}
+	case "github":
+		if viper.GetString("github-token") == "" {
+			cobra.CheckErr(tr("github token must be set"))
+		}
+
+		if viper.GetDuration("github-commit-duration") <= 0 {
+			cobra.CheckErr(tr("github commit duration must be positive"))
+		}
+
+		if viper.GetDuration("github-review-duration") <= 0 {
+			cobra.CheckErr(tr("github review duration must be positive"))
+		}
+
+		if viper.GetDuration("github-comment-duration") <= 0 {
+			cobra.CheckErr(tr("github comment duration must be positive"))
+		}
+	case "gitlab":
+		if viper.GetString("gitlab-token") == "" {
+			cobra.CheckErr(tr("gitlab token must be set"))
+		}
+	case "googlecalendar":
+		if viper.GetString("googlecalendar-client-id") == "" {
+			cobra.CheckErr(tr("googlecalendar client id must be set"))
+		}
+
+		if viper.GetString("googlecalendar-refresh-token") == "" {
+			cobra.CheckErr(tr("googlecalendar refresh token must be set; run authorize-googlecalendar to obtain it"))
+		}
+
+		if len(viper.GetStringSlice("googlecalendar-calendars")) == 0 {
+			cobra.CheckErr(tr("googlecalendar calendars must be set"))
+		}
+	case "hamster":
+		if viper.GetString("hamster-database") == "" {
+			cobra.CheckErr(tr("hamster database must be set"))
+		}
+
+		if viper.GetString("hamster-sqlite-command") == "" {
+			cobra.CheckErr(tr("hamster sqlite command must be set"))
+		}
+	case "hubstaff":
+		if viper.GetString("hubstaff-token") == "" {
+			cobra.CheckErr(tr("hubstaff token must be set"))
+		}
+
+		if viper.GetString("hubstaff-organization") == "" {
+			cobra.CheckErr(tr("hubstaff organization must be set"))
+		}
+
+		if idleMode := viper.GetString("hubstaff-idle"); !utils.IsSliceContains(idleMode, hubstaff.IdleModes) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported hubstaff idle modes %v\n", idleMode, hubstaff.IdleModes))
+		}
+	case "icsfile":
+		if viper.GetString("icsfile-path") == "" {
+			cobra.CheckErr(tr("icsfile path must be set"))
+		}
+	case "jsonfile":
+		if viper.GetString("jsonfile-path") == "" {
+			cobra.CheckErr(tr("jsonfile path must be set"))
+		}
+	case "kimai":
+		if viper.GetString("kimai-url") == "" {
+			cobra.CheckErr(tr("kimai url must be set"))
+		}
+
+		if viper.GetString("kimai-token") == "" {
+			cobra.CheckErr(tr("kimai token must be set"))
+		}
+	case "outlook":
+		if viper.GetString("outlook-client-id") == "" {
+			cobra.CheckErr(tr("outlook client id must be set"))
+		}
+
+		if viper.GetString("outlook-refresh-token") == "" {
+			cobra.CheckErr(tr("outlook refresh token must be set; run authorize-outlook to obtain it"))
+		}
+	case "redmine":
+		if viper.GetString("redmine-url") == "" {
+			cobra.CheckErr(tr("redmine url must be set"))
+		}
+
+		if viper.GetString("redmine-api-key") == "" {
+			cobra.CheckErr(tr("redmine api key must be set"))
+		}
+	case "rescuetime":
+		if viper.GetString("rescuetime-api-key") == "" {
+			cobra.CheckErr(tr("rescuetime api key must be set"))
+		}
+
+		if granularity := viper.GetString("rescuetime-granularity"); !utils.IsSliceContains(granularity, rescuetime.Granularities) {
+			cobra.CheckErr(tr("\"%s\" is not part of the supported rescuetime granularities %v\n", granularity, rescuetime.Granularities))
+		}
+	case "timecamp":
+		if viper.GetString("timecamp-token") == "" {
+			cobra.CheckErr(tr("timecamp token must be set"))
+		}
 	case "timewarrior":
 		if viper.GetString("timewarrior-command") == "" {
-			cobra.CheckErr("timewarrior command must be set")
+			cobra.CheckErr(tr("timewarrior command must be set"))
 		}
 
 		if viper.GetString("timewarrior-unbillable-tag") == "" {
-			cobra.CheckErr("timewarrior unbillable tag must be set")
+			cobra.CheckErr(tr("timewarrior unbillable tag must be set"))
 		}
 
 		if viper.GetString("timewarrior-client-tag-regex") == "" {
-			cobra.CheckErr("timewarrior client tag regex must be set")
+			cobra.CheckErr(tr("timewarrior client tag regex must be set"))
 		}
 
 		if viper.GetString("timewarrior-project-tag-regex") == "" {
-			cobra.CheckErr("timewarrior project tag regex must be set")
+			cobra.CheckErr(tr("timewarrior project tag regex must be set"))
+		}
+	case "wakatime":
+		if viper.GetString("wakatime-api-key") == "" {
+			cobra.CheckErr(tr("wakatime api key must be set"))
+		}
+
+		if viper.GetString("wakatime-user") == "" {
+			cobra.CheckErr(tr("wakatime user must be set"))
+		}
+	case "watson":
+		if viper.GetString("watson-frames-file") == "" {
+			cobra.CheckErr(tr("watson frames file must be set"))
+		}
+	case "youtrack":
+		if viper.GetString("youtrack-url") == "" {
+			cobra.CheckErr(tr("youtrack url must be set"))
+		}
+
+		if viper.GetString("youtrack-token") == "" {
+			cobra.CheckErr(tr("youtrack token must be set"))
 		}
 	}
 }
@@ -0,0 +1,38 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gabor-boros/minutes/internal/pkg/hook"
+	"github.com/spf13/viper"
+)
+
+func getHookOpts() *hook.Opts {
+	return &hook.Opts{
+		Command: viper.GetString("hook-command"),
+		URL:     viper.GetString("hook-url"),
+		Output:  os.Stderr,
+		Timeout: viper.GetDuration("hook-timeout"),
+	}
+}
+
+// runHook calls the post-run hook with the JSON summary of the sync, so custom
+// notifications can be sent. Failing to run the hook does not fail the sync.
+func runHook(ctx context.Context, summary *runSummary) {
+	opts := getHookOpts()
+	if !opts.IsEnabled() {
+		return
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		err = hook.Run(ctx, data, opts)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to run the post-run hook: %v\n", err)
+	}
+}
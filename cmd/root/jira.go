@@ -0,0 +1,91 @@
+package root
+
+import (
+	"context"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jira"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+// getJiraOption returns the Jira option, falling back to the matching Tempo
+// option, since Tempo is running on the same Jira instance.
+func getJiraOption(name string) string {
+	if value := viper.GetString("jira-" + name); value != "" {
+		return value
+	}
+
+	return viper.GetString("tempo-" + name)
+}
+
+func getJiraClient() (*jira.Client, error) {
+	return jira.NewClient(&jira.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: getJiraOption("username"),
+			Password: getJiraOption("password"),
+		},
+		BaseURL: getJiraOption("url"),
+	})
+}
+
+// isJiraLookupEnabled returns true if any of the Jira lookups is enabled.
+func isJiraLookupEnabled() bool {
+	return viper.GetBool("jira-service-desk") || viper.GetBool("jira-classification") || viper.GetBool("jira-rollup")
+}
+
+// getClassificationRules returns the classification rules set in the config.
+func getClassificationRules() ([]jira.ClassificationRule, error) {
+	var rules []jira.ClassificationRule
+	if err := viper.UnmarshalKey("jira-classification-rules", &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// enrichEntries sets the attributes of the entries looked up in Jira, if
+// enabled by the flags.
+func enrichEntries(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	isServiceDeskEnabled := viper.GetBool("jira-service-desk")
+	isClassificationEnabled := viper.GetBool("jira-classification")
+	isRollupEnabled := viper.GetBool("jira-rollup")
+
+	if !isServiceDeskEnabled && !isClassificationEnabled && !isRollupEnabled {
+		return entries, nil
+	}
+
+	jiraClient, err := getJiraClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if isServiceDeskEnabled {
+		if entries, err = jiraClient.EnrichWithServiceDesk(ctx, entries); err != nil {
+			return nil, err
+		}
+	}
+
+	if isClassificationEnabled {
+		rules, err := getClassificationRules()
+		if err != nil {
+			return nil, err
+		}
+
+		if entries, err = jiraClient.Classify(ctx, entries, rules, viper.GetString("jira-classification-default")); err != nil {
+			return nil, err
+		}
+	}
+
+	// Rolling up must be the last, so the other lookups use the original issue
+	if isRollupEnabled {
+		if entries, err = jiraClient.Rollup(ctx, entries, viper.GetInt("jira-rollup-level")); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
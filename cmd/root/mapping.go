@@ -0,0 +1,72 @@
+package root
+
+import (
+	"errors"
+	"os"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+const mappingsKey string = "mappings"
+
+// loadMappings reads and compiles the mappings from the given mapping file.
+// If no mapping file is set or the file does not exist, no mappings return.
+func loadMappings(path string) ([]worklog.Mapping, error) {
+	var mappings []worklog.Mapping
+
+	if path == "" {
+		return mappings, nil
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return mappings, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	if err := v.UnmarshalKey(mappingsKey, &mappings); err != nil {
+		return nil, err
+	}
+
+	for i := range mappings {
+		if err := mappings[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return mappings, nil
+}
+
+// saveMappings writes the mappings to the given mapping file, overriding its
+// content.
+func saveMappings(path string, mappings []worklog.Mapping) error {
+	var rawMappings []map[string]interface{}
+
+	for _, mapping := range mappings {
+		rawMapping := map[string]interface{}{
+			"summary": mapping.Summary,
+			"client":  mapping.Client,
+			"project": mapping.Project,
+			"task":    mapping.Task,
+		}
+
+		if mapping.Absence != "" {
+			rawMapping["absence"] = string(mapping.Absence)
+		}
+
+		rawMappings = append(rawMappings, rawMapping)
+	}
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	v.Set(mappingsKey, rawMappings)
+
+	return v.WriteConfigAs(path)
+}
@@ -0,0 +1,56 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/notify"
+	"github.com/spf13/viper"
+)
+
+func getNotifyOpts() *notify.Opts {
+	return &notify.Opts{
+		TeamsURL:   viper.GetString("notify-teams-url"),
+		DiscordURL: viper.GetString("notify-discord-url"),
+		Timeout:    client.DefaultRequestTimeout,
+	}
+}
+
+// newNotifySummary returns the summary of the notifications about the sync.
+func newNotifySummary(summary *runSummary) *notify.Summary {
+	notifySummary := &notify.Summary{
+		Source:   summary.Source,
+		Target:   summary.Target,
+		Start:    summary.Start,
+		End:      summary.End,
+		DryRun:   summary.DryRun,
+		Entries:  len(summary.Entries),
+		Uploaded: summary.Uploaded,
+		Failed:   summary.Failed,
+	}
+
+	for _, entry := range summary.Entries {
+		notifySummary.TimeSpent += entry.BillableDuration + entry.UnbillableDuration
+	}
+
+	for _, err := range summary.Errors {
+		notifySummary.Errors = append(notifySummary.Errors, err.Message)
+	}
+
+	return notifySummary
+}
+
+// sendNotifications posts the summary card of the sync to the Teams and
+// Discord webhooks. Failing to notify does not fail the sync.
+func sendNotifications(ctx context.Context, summary *runSummary) {
+	opts := getNotifyOpts()
+	if !opts.IsEnabled() {
+		return
+	}
+
+	if err := notify.Send(ctx, newNotifySummary(summary), opts); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+}
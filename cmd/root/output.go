@@ -0,0 +1,56 @@
+package root
+
+import (
+	"os"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/viper"
+)
+
+// isLinearOutput returns true if the tables and progress bars must be replaced
+// by linear status lines, suitable for screen readers and log files.
+func isLinearOutput() bool {
+	return viper.GetBool("a11y")
+}
+
+// isPlainOutput returns true if the output must be plain text, either because
+// it was requested or the terminal cannot render anything else. The linear
+// output is plain text as well.
+func isPlainOutput() bool {
+	return viper.GetBool("plain") || isLinearOutput() || utils.IsDumbTerminal()
+}
+
+// getTableStyle returns the style of the printed tables. Plain output uses
+// ASCII characters only, since box-drawing characters are not rendered
+// correctly by every terminal.
+func getTableStyle() table.Style {
+	if isPlainOutput() {
+		return table.StyleDefault
+	}
+
+	return table.StyleLight
+}
+
+// newTableWriter returns a table writer, which renders linear text instead of
+// a table if linear output is requested.
+func newTableWriter() table.Writer {
+	if isLinearOutput() {
+		return utils.NewLinearTableWriter()
+	}
+
+	return table.NewWriter()
+}
+
+// newProgressWriter returns the progress writer fitting the terminal.
+func newProgressWriter(updateFrequency time.Duration) progress.Writer {
+	if isLinearOutput() {
+		return utils.NewLinearProgressWriter(updateFrequency, os.Stdout)
+	} else if isPlainOutput() {
+		return utils.NewPlainProgressWriter(updateFrequency, os.Stdout)
+	}
+
+	return utils.NewProgressWriter(updateFrequency)
+}
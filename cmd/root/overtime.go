@@ -0,0 +1,287 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// overtimeKey is the storage key of the overtime balance.
+	overtimeKey string = storage.PrefixState + "overtime.json"
+)
+
+var overtimeCmd = &cobra.Command{
+	Use:   "overtime",
+	Short: "Show the overtime balance carried over the previous syncs",
+	Long: `
+Show the expected and actual time spent per day, tracked by the previous syncs
+when the overtime tracking was enabled, and the overtime balance carried over
+all tracked days.
+
+With --overtime-by-month, the time spent per month is compared to the target
+hours of the month instead, prorated by the employment dates and part-time
+percentages.`,
+	PreRun: bindCmdFlags,
+	Run:    runOvertimeCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(overtimeCmd)
+
+	overtimeCmd.Flags().BoolP("overtime-by-month", "", false, "compare the time spent per month to the target hours of the month")
+}
+
+// getPartTimePeriods returns the part-time periods set in the config.
+func getPartTimePeriods() ([]worklog.PartTimePeriod, error) {
+	var periods []worklog.PartTimePeriod
+	if err := viper.UnmarshalKey("overtime-part-time", &periods); err != nil {
+		return nil, err
+	}
+
+	for _, period := range periods {
+		if _, err := time.Parse("2006-01-02", period.From); err != nil {
+			return nil, fmt.Errorf("\"%s\" is not a valid part-time start date in YYYY-MM-DD format", period.From)
+		}
+
+		if period.Percentage <= 0 || period.Percentage > 100 {
+			return nil, fmt.Errorf("part-time percentage from %s must be between 0 and 100", period.From)
+		}
+	}
+
+	return periods, nil
+}
+
+// getWeekdayDurations returns the expected time spent per day of the week,
+// keyed by the names of the weekdays, like "fri" or "friday".
+func getWeekdayDurations() (map[time.Weekday]time.Duration, error) {
+	durations := map[time.Weekday]time.Duration{}
+
+	for name, rawDuration := range viper.GetStringMapString("overtime-weekday-durations") {
+		weekday, err := utils.ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+
+		duration, err := time.ParseDuration(rawDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration of %s: %v", name, err)
+		}
+
+		if duration < 0 {
+			return nil, fmt.Errorf("duration of %s must not be negative", name)
+		}
+
+		durations[weekday] = duration
+	}
+
+	return durations, nil
+}
+
+// getOvertimeOpts returns the overtime options set by flags. The working days
+// are already validated, parsing them again cannot fail.
+func getOvertimeOpts() *worklog.OvertimeOpts {
+	var workingDays []time.Weekday
+	for _, name := range viper.GetStringSlice("overtime-working-days") {
+		weekday, err := utils.ParseWeekday(name)
+		cobra.CheckErr(err)
+
+		workingDays = append(workingDays, weekday)
+	}
+
+	weekdayDurations, err := getWeekdayDurations()
+	cobra.CheckErr(err)
+
+	partTime, err := getPartTimePeriods()
+	cobra.CheckErr(err)
+
+	return &worklog.OvertimeOpts{
+		DailyDuration:    viper.GetDuration("overtime-daily-duration"),
+		WorkingDays:      workingDays,
+		WeekdayDurations: weekdayDurations,
+		Holidays:         viper.GetStringSlice("overtime-holidays"),
+		EmploymentStart:  viper.GetString("overtime-employment-start"),
+		EmploymentEnd:    viper.GetString("overtime-employment-end"),
+		PartTime:         partTime,
+	}
+}
+
+// loadOvertime returns the overtime balance persisted in the store. If no
+// balance was persisted yet, an empty balance returns.
+func loadOvertime(ctx context.Context, store storage.Store) (worklog.OvertimeBalance, error) {
+	balance := worklog.OvertimeBalance{}
+
+	data, err := store.Get(ctx, overtimeKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return balance, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &balance); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// saveOvertime persists the overtime balance in the store.
+func saveOvertime(ctx context.Context, store storage.Store, balance worklog.OvertimeBalance) error {
+	data, err := json.Marshal(balance)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, overtimeKey, data)
+}
+
+// updateOvertime updates the persisted overtime balance with the entries of
+// the period, then prints the overtime of the period and the carried over
+// balance. Days after today are not tracked, as those are not over yet.
+func updateOvertime(entries worklog.Entries, start time.Time, end time.Time) {
+	ctx := context.Background()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	balance, err := loadOvertime(ctx, store)
+	cobra.CheckErr(err)
+
+	tomorrow, err := utils.GetTime("", "")
+	cobra.CheckErr(err)
+	tomorrow = tomorrow.AddDate(0, 0, 1)
+
+	if end.After(tomorrow) {
+		end = tomorrow
+	}
+
+	balance.Update(entries, start, end, getOvertimeOpts())
+	cobra.CheckErr(saveOvertime(ctx, store, balance))
+
+	fmt.Print(tr(
+		"Overtime of the period: %s, balance: %s\n",
+		utils.FormatBalance(balance.Between(start, end)),
+		utils.FormatBalance(balance.Total()),
+	))
+}
+
+func runOvertimeCmd(_ *cobra.Command, _ []string) {
+	validateStorageFlags()
+
+	reportLocale, err := locale.Get(viper.GetString("locale"))
+	cobra.CheckErr(err)
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	balance, err := loadOvertime(context.Background(), store)
+	cobra.CheckErr(err)
+
+	if len(balance) == 0 {
+		fmt.Println(tr("No overtime tracked yet."))
+		return
+	}
+
+	if viper.GetBool("overtime-by-month") {
+		validateOvertimeFlags()
+		printMonthlyOvertime(balance)
+		return
+	}
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.Style().Format.Footer = text.FormatDefault
+	writer.SetTitle(tr("Overtime balance"))
+	writer.AppendHeader(table.Row{tr("Date"), tr("Expected"), tr("Actual"), tr("Overtime"), tr("Balance")})
+
+	var runningBalance time.Duration
+	var week time.Time
+
+	for _, rawDate := range balance.Dates() {
+		day := balance[rawDate]
+		runningBalance += day.Balance()
+
+		date, err := time.ParseInLocation("2006-01-02", rawDate, time.Local)
+		cobra.CheckErr(err)
+
+		// Separate the weeks, starting on the first day of the week of the locale
+		if startOfWeek := reportLocale.StartOfWeek(date); !startOfWeek.Equal(week) {
+			if !week.IsZero() {
+				writer.AppendSeparator()
+			}
+
+			week = startOfWeek
+		}
+
+		writer.AppendRow(table.Row{
+			reportLocale.FormatDate(date),
+			day.Expected.String(),
+			day.Actual.String(),
+			utils.FormatBalance(day.Balance()),
+			utils.FormatBalance(runningBalance),
+		})
+	}
+
+	writer.AppendFooter(table.Row{"", "", "", tr("Total"), utils.FormatBalance(balance.Total())})
+	writer.Render()
+}
+
+// printMonthlyOvertime prints the time spent per tracked month compared to the
+// target hours of the month. The targets are calculated by the current
+// options, so changing the part-time percentages or the employment dates
+// applies to the past months too.
+func printMonthlyOvertime(balance worklog.OvertimeBalance) {
+	opts := getOvertimeOpts()
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.Style().Format.Footer = text.FormatDefault
+	writer.SetTitle(tr("Monthly targets"))
+	writer.AppendHeader(table.Row{tr("Month"), tr("Target"), tr("Actual"), tr("Difference")})
+
+	var totalTarget, totalActual time.Duration
+	var month time.Time
+
+	for _, rawDate := range balance.Dates() {
+		date, err := time.ParseInLocation("2006-01-02", rawDate, time.Local)
+		cobra.CheckErr(err)
+
+		// Every tracked month is listed once, by its first tracked day
+		startOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+		if startOfMonth.Equal(month) {
+			continue
+		}
+
+		month = startOfMonth
+		endOfMonth := month.AddDate(0, 1, 0)
+		target := opts.Target(month, endOfMonth)
+		actual := balance.Actual(month, endOfMonth)
+
+		totalTarget += target
+		totalActual += actual
+
+		writer.AppendRow(table.Row{
+			month.Format("2006-01"),
+			target.String(),
+			actual.String(),
+			utils.FormatBalance(actual - target),
+		})
+	}
+
+	writer.AppendFooter(table.Row{tr("Total"), totalTarget.String(), totalActual.String(), utils.FormatBalance(totalActual - totalTarget)})
+	writer.Render()
+}
@@ -0,0 +1,135 @@
+package root
+
+import (
+	"regexp"
+
+	"github.com/gabor-boros/minutes/internal/pkg/pipeline"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+var (
+	// defaultPipelineStages lists the stages of the transformation pipeline
+	// in the default order.
+	defaultPipelineStages = []string{
+		pipeline.StageExtract,
+		pipeline.StageMap,
+		pipeline.StageEnrich,
+		pipeline.StageSplit,
+		pipeline.StageFilter,
+		pipeline.StageAggregate,
+		pipeline.StageMerge,
+		pipeline.StageDistribute,
+		pipeline.StageRound,
+		pipeline.StageValidate,
+	}
+)
+
+// newPipeline returns the transformation pipeline set by the flags. If the
+// pipeline stages are set, the stages are reordered accordingly and the
+// missing stages are skipped.
+func newPipeline() (*pipeline.Pipeline, error) {
+	mappings, err := loadMappings(viper.GetString("mapping-file"))
+	if err != nil {
+		return nil, err
+	}
+
+	reallocations, err := getReallocations()
+	if err != nil {
+		return nil, err
+	}
+
+	distributionOpts, err := getDistributionOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationOpts, err := getAggregationOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	clientRegex, err := regexp.Compile(viper.GetString("filter-client"))
+	if err != nil {
+		return nil, err
+	}
+
+	projectRegex, err := regexp.Compile(viper.GetString("filter-project"))
+	if err != nil {
+		return nil, err
+	}
+
+	tagsAsTasksRegex, err := regexp.Compile(viper.GetString("tags-as-tasks-regex"))
+	if err != nil {
+		return nil, err
+	}
+
+	transformPipeline, err := pipeline.New(
+		pipeline.Extract(tagsAsTasksRegex, viper.GetString("task-extraction")),
+		pipeline.Map(mappings),
+		pipeline.NewTransformer(pipeline.StageEnrich, enrichEntries),
+		pipeline.Split(reallocations),
+		pipeline.Filter(&worklog.FilterOpts{
+			Client:  clientRegex,
+			Project: projectRegex,
+		}),
+		pipeline.Aggregate(aggregationOpts),
+		pipeline.Merge(),
+		pipeline.Distribute(distributionOpts),
+		pipeline.Round(&pipeline.RoundOpts{
+			RoundToClosestMinute:  viper.GetBool("round-to-closest-minute"),
+			TreatDurationAsBilled: viper.GetBool("force-billed-duration"),
+			Increment:             viper.GetDuration("round-increment"),
+			Mode:                  viper.GetString("round-mode"),
+		}),
+		pipeline.Validate(&pipeline.ValidateOpts{
+			FutureEntries:   viper.GetString("future-entries"),
+			FutureTolerance: viper.GetDuration("future-tolerance"),
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if stages := viper.GetStringSlice("pipeline-stages"); len(stages) != 0 {
+		if err = transformPipeline.Reorder(stages); err != nil {
+			return nil, err
+		}
+	}
+
+	return transformPipeline, nil
+}
+
+// getDistributionOpts returns the options of distributing the daily totals
+// set by the flags. The working hours are given in "15:04" format.
+func getDistributionOpts() (*worklog.DistributionOpts, error) {
+	dayStart, err := worklog.ParseWorkingHour(viper.GetString("distribution-day-start"))
+	if err != nil {
+		return nil, err
+	}
+
+	dayEnd, err := worklog.ParseWorkingHour(viper.GetString("distribution-day-end"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &worklog.DistributionOpts{
+		Strategy: viper.GetString("distribution-strategy"),
+		DayStart: dayStart,
+		DayEnd:   dayEnd,
+	}
+
+	return opts, opts.Validate()
+}
+
+// getAggregationOpts returns the options of aggregating the entries set by the
+// flags.
+func getAggregationOpts() (*worklog.AggregationOpts, error) {
+	opts := &worklog.AggregationOpts{
+		Mode:                viper.GetString("aggregation-mode"),
+		PomodoroMaxDuration: viper.GetDuration("pomodoro-max-duration"),
+		PomodoroMaxBreak:    viper.GetDuration("pomodoro-max-break"),
+	}
+
+	return opts, opts.Validate()
+}
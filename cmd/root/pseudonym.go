@@ -0,0 +1,73 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+const (
+	// pseudonymsKey is the storage key of the pseudonyms of the clients and
+	// projects used by the reports.
+	pseudonymsKey string = storage.PrefixState + "pseudonyms.json"
+)
+
+// loadPseudonyms returns the pseudonyms persisted in the store. If no
+// pseudonyms were persisted yet, empty pseudonyms return.
+func loadPseudonyms(ctx context.Context, store storage.Store) (*worklog.Pseudonyms, error) {
+	pseudonyms := worklog.NewPseudonyms()
+
+	data, err := store.Get(ctx, pseudonymsKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return pseudonyms, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, pseudonyms); err != nil {
+		return nil, err
+	}
+
+	return pseudonyms, nil
+}
+
+// savePseudonyms persists the pseudonyms in the store.
+func savePseudonyms(ctx context.Context, store storage.Store, pseudonyms *worklog.Pseudonyms) error {
+	data, err := json.Marshal(pseudonyms)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, pseudonymsKey, data)
+}
+
+// pseudonymizeReportEntries returns the entries of a report having the clients
+// and projects replaced by their pseudonyms, if pseudonymization is enabled.
+// The newly assigned pseudonyms are persisted, so the same names get the same
+// pseudonyms in every report.
+func pseudonymizeReportEntries(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	if !viper.GetBool("pseudonymize") {
+		return entries, nil
+	}
+
+	store, err := getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	pseudonyms, err := loadPseudonyms(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	pseudonymized := pseudonyms.Pseudonymize(entries)
+	if err = savePseudonyms(ctx, store, pseudonyms); err != nil {
+		return nil, err
+	}
+
+	return pseudonymized, nil
+}
@@ -0,0 +1,97 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// purgeDataPrefixes maps the kinds of the purged data to their storage key
+	// prefixes.
+	purgeDataPrefixes = map[string]string{
+		"cache":    storage.PrefixCache,
+		"history":  storage.PrefixHistory,
+		"receipts": storage.PrefixReceipts,
+		"state":    storage.PrefixState,
+	}
+
+	purgeDataKinds = []string{"cache", "history", "receipts", "state"}
+)
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge-local-data",
+	Short: "Remove the cached entries, history, receipts and state from the storage",
+	Long: `
+Remove the data persisted in the storage, which may contain personal data, like
+the cached entries, the history of the syncs, the upload receipts and the state
+carried over between the syncs, like the ledger and the overtime balance.
+
+When --retention-days is set, only the data not modified in the given number of
+days is removed. Set --dry-run to list the data that would be removed.`,
+	PreRun: bindCmdFlags,
+	Run:    runPurgeCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().StringSliceP("purge-data", "", purgeDataKinds, fmt.Sprintf("set the kinds of the removed data %v", purgeDataKinds))
+	purgeCmd.Flags().IntP("retention-days", "", 0, "remove only the data older than the given number of days (0 removes every data)")
+}
+
+func runPurgeCmd(_ *cobra.Command, _ []string) {
+	validatePurgeFlags()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	opts := &storage.PurgeOpts{DryRun: true}
+
+	for _, kind := range viper.GetStringSlice("purge-data") {
+		opts.Prefixes = append(opts.Prefixes, purgeDataPrefixes[kind])
+	}
+
+	if retentionDays := viper.GetInt("retention-days"); retentionDays > 0 {
+		opts.Before = time.Now().AddDate(0, 0, -retentionDays)
+	}
+
+	ctx := context.Background()
+
+	objects, err := storage.Purge(ctx, store, opts)
+	cobra.CheckErr(err)
+
+	if len(objects) == 0 {
+		fmt.Println(tr("No data to remove."))
+		return
+	}
+
+	reportLocale := getLocale()
+	for _, object := range objects {
+		fmt.Printf("%s (%s)\n", object.Key, reportLocale.FormatDateTime(object.ModTime.Local()))
+	}
+
+	if viper.GetBool("dry-run") {
+		fmt.Print(tr("\n%d objects would be removed.\n", len(objects)))
+		return
+	}
+
+	if strings.ToLower(utils.Prompt(tr("\nRemove %d objects from the storage? [y/n]: ", len(objects)))) != "y" {
+		fmt.Println(tr("User interruption. Aborting."))
+		os.Exit(0)
+	}
+
+	opts.DryRun = false
+
+	objects, err = storage.Purge(ctx, store, opts)
+	cobra.CheckErr(err)
+
+	fmt.Print(tr("Removed %d objects.\n", len(objects)))
+}
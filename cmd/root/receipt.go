@@ -0,0 +1,212 @@
+package root
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/minisign"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// receiptSignatureExt is the extension of the receipt signatures, appended
+	// to the name of the receipt like by minisign.
+	receiptSignatureExt string = ".minisig"
+)
+
+var generateReceiptKeyCmd = &cobra.Command{
+	Use:   "generate-receipt-key",
+	Short: "Generate the key pair used to sign the upload receipts",
+	Long: `
+Generate a minisign compatible key pair. The secret key, set by
+--receipt-secret-key, signs the upload receipts, while the public key, set by
+--receipt-public-key, verifies them. Existing keys are not overwritten.
+
+The secret key is not encrypted by a password, hence it must be kept private.`,
+	Run: runGenerateReceiptKeyCmd,
+}
+
+var verifyReceiptCmd = &cobra.Command{
+	Use:   "verify-receipt <receipt>",
+	Short: "Verify the signature of an upload receipt",
+	Long: `
+Verify that the upload receipt was signed by the secret key of the public key
+set by --receipt-public-key and it was not changed since.
+
+The receipt is either the name of a receipt in the storage, like
+"20211002T090000Z", or the path of a receipt file. The signature is read from
+the receipt's name or path extended by ".minisig".`,
+	Args: cobra.ExactArgs(1),
+	Run:  runVerifyReceiptCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(generateReceiptKeyCmd)
+	rootCmd.AddCommand(verifyReceiptCmd)
+}
+
+// getReceiptSecretKey returns the secret key signing the receipts. If no key
+// is set, nil returns.
+func getReceiptSecretKey() (*minisign.SecretKey, error) {
+	path := viper.GetString("receipt-secret-key")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return minisign.ParseSecretKey(data)
+}
+
+// writeKeyFile writes the key to a new file, so existing keys are never
+// overwritten.
+func writeKeyFile(path string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	return errors.Join(err, file.Close())
+}
+
+// signReceipt signs the receipt by the secret key. The trusted comment follows
+// the format of minisign, so the signature can be verified by minisign too.
+func signReceipt(key *minisign.SecretKey, name string, signedAt time.Time, data []byte) ([]byte, error) {
+	return minisign.Sign(key, data, fmt.Sprintf("timestamp:%d\tfile:%s", signedAt.Unix(), name))
+}
+
+// loadReceipt returns the receipt and its signature from the file if exists,
+// otherwise from the store.
+func loadReceipt(ctx context.Context, receipt string) ([]byte, []byte, error) {
+	if _, err := os.Stat(receipt); err == nil {
+		data, err := os.ReadFile(receipt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signature, err := os.ReadFile(receipt + receiptSignatureExt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return data, signature, nil
+	}
+
+	validateStorageFlags()
+
+	store, err := getStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := storage.PrefixReceipts + strings.TrimSuffix(receipt, ".json") + ".json"
+
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	signature, err := store.Get(ctx, key+receiptSignatureExt)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("%s is not signed", key)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return data, signature, nil
+}
+
+// parseSignedAt returns the signing time of the trusted comment, written by
+// signReceipt or minisign. If the comment has no timestamp, the zero time
+// returns.
+func parseSignedAt(trustedComment string) time.Time {
+	for _, field := range strings.Split(trustedComment, "\t") {
+		if rawTimestamp, found := strings.CutPrefix(field, "timestamp:"); found {
+			if timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64); err == nil {
+				return time.Unix(timestamp, 0)
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+func runGenerateReceiptKeyCmd(_ *cobra.Command, _ []string) {
+	secretKeyPath := viper.GetString("receipt-secret-key")
+	publicKeyPath := viper.GetString("receipt-public-key")
+
+	if secretKeyPath == "" || publicKeyPath == "" {
+		cobra.CheckErr(tr("receipt secret key and public key must be set"))
+	}
+
+	key, err := minisign.GenerateKey(rand.Reader)
+	cobra.CheckErr(err)
+
+	secretKey, err := key.MarshalText()
+	cobra.CheckErr(err)
+
+	publicKey, err := key.Public().MarshalText()
+	cobra.CheckErr(err)
+
+	for _, path := range []string{secretKeyPath, publicKeyPath} {
+		if _, err = os.Stat(path); err == nil {
+			cobra.CheckErr(tr("%s already exists", path))
+		}
+	}
+
+	cobra.CheckErr(writeKeyFile(secretKeyPath, secretKey, 0600))
+	cobra.CheckErr(writeKeyFile(publicKeyPath, publicKey, 0644))
+
+	fmt.Print(tr("Generated receipt key %s.\n", key.Public().KeyID()))
+}
+
+func runVerifyReceiptCmd(_ *cobra.Command, args []string) {
+	publicKeyPath := viper.GetString("receipt-public-key")
+	if publicKeyPath == "" {
+		cobra.CheckErr(tr("receipt public key must be set"))
+	}
+
+	rawPublicKey, err := os.ReadFile(publicKeyPath)
+	cobra.CheckErr(err)
+
+	publicKey, err := minisign.ParsePublicKey(rawPublicKey)
+	cobra.CheckErr(err)
+
+	data, signature, err := loadReceipt(context.Background(), args[0])
+	cobra.CheckErr(err)
+
+	trustedComment, err := minisign.Verify(publicKey, data, signature)
+	cobra.CheckErr(err)
+
+	var summary runSummary
+	cobra.CheckErr(json.Unmarshal(data, &summary))
+
+	reportLocale := getLocale()
+
+	if signedAt := parseSignedAt(trustedComment); !signedAt.IsZero() {
+		fmt.Print(tr("Receipt is signed by key %s at %s.\n", publicKey.KeyID(), reportLocale.FormatDateTime(signedAt.Local())))
+	} else {
+		fmt.Print(tr("Receipt is signed by key %s.\n", publicKey.KeyID()))
+	}
+
+	fmt.Print(tr(
+		"Uploaded %d entries to %s for %s - %s.\n",
+		summary.Uploaded,
+		summary.Target,
+		reportLocale.FormatDateTime(summary.Start.Local()),
+		reportLocale.FormatDateTime(summary.End.Local()),
+	))
+}
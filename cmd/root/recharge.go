@@ -0,0 +1,129 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var rechargeReportCmd = &cobra.Command{
+	Use:   "recharge-report",
+	Short: "Report the billable time to recharge between the legal entities",
+	Long: `
+Fetch the entries of the period from the source, group the billable time by
+client and the legal entity recharging the client, then calculate the
+recharged amounts by the hourly rates of the legal entities.
+
+The legal entities are set in the config file by [[legal-entities]] tables.
+The clients not recharged by any legal entity are listed without amount.`,
+	Run: runRechargeReportCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(rechargeReportCmd)
+}
+
+// getLegalEntities returns the compiled legal entities set in the config.
+func getLegalEntities() ([]worklog.LegalEntity, error) {
+	var legalEntities []worklog.LegalEntity
+	if err := viper.UnmarshalKey("legal-entities", &legalEntities); err != nil {
+		return nil, err
+	}
+
+	for i := range legalEntities {
+		if err := legalEntities[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return legalEntities, nil
+}
+
+func runRechargeReportCmd(_ *cobra.Command, _ []string) {
+	validateSourceFlags()
+
+	legalEntities, err := getLegalEntities()
+	cobra.CheckErr(err)
+
+	if len(legalEntities) == 0 {
+		cobra.CheckErr(tr("legal entities must be set"))
+	}
+
+	reportLocale := getLocale()
+
+	start, end := getTimeRange()
+	entries, err := fetchEntries(context.Background(), start, end)
+	cobra.CheckErr(err)
+
+	recharges := worklog.Recharges(entries, legalEntities)
+	if len(recharges) == 0 {
+		fmt.Println(tr("No billable time to recharge."))
+		return
+	}
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.Style().Format.Footer = text.FormatDefault
+	writer.SetTitle(tr("Recharges (%s - %s)", reportLocale.FormatDate(start), reportLocale.FormatDate(end)))
+	writer.AppendHeader(table.Row{tr("Legal entity"), tr("Client"), tr("Billable hours"), tr("Rate"), tr("Amount")})
+
+	var unassigned []string
+	totalAmounts := map[string]float64{}
+	var totalBillable time.Duration
+
+	for _, recharge := range recharges {
+		totalBillable += recharge.Billable
+
+		if recharge.Client == "" {
+			recharge.Client = "-"
+		}
+
+		if recharge.LegalEntity == "" {
+			unassigned = append(unassigned, recharge.Client)
+			writer.AppendRow(table.Row{"-", recharge.Client, reportLocale.FormatHours(recharge.Billable, 2), "", ""})
+			continue
+		}
+
+		totalAmounts[recharge.Currency] += recharge.Amount
+
+		writer.AppendRow(table.Row{
+			recharge.LegalEntity,
+			recharge.Client,
+			reportLocale.FormatHours(recharge.Billable, 2),
+			strings.TrimSpace(reportLocale.FormatNumber(recharge.Rate, 2) + " " + recharge.Currency),
+			strings.TrimSpace(reportLocale.FormatNumber(recharge.Amount, 2) + " " + recharge.Currency),
+		})
+	}
+
+	// The amounts of different currencies cannot be summed
+	currencies := make([]string, 0, len(totalAmounts))
+	for currency := range totalAmounts {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for i, currency := range currencies {
+		row := table.Row{"", "", "", tr("Total"), strings.TrimSpace(reportLocale.FormatNumber(totalAmounts[currency], 2) + " " + currency)}
+		if i == 0 {
+			row[2] = reportLocale.FormatHours(totalBillable, 2)
+		}
+
+		writer.AppendFooter(row)
+	}
+
+	writer.Render()
+
+	if len(unassigned) != 0 {
+		fmt.Print(tr("No legal entity recharges the clients: %s\n", strings.Join(unassigned, ", ")))
+	}
+}
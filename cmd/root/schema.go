@@ -0,0 +1,150 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gabor-boros/minutes/internal/pkg/schema"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const schemaBaseURL string = "https://gabor-boros.github.io/minutes/schemas/"
+
+// schemas lists the file formats which schema can be exported.
+var schemas = map[string]func() *schema.Schema{
+	"config": func() *schema.Schema {
+		return schema.FromFlags(getConfigFlags())
+	},
+	"entry": func() *schema.Schema {
+		return schema.Reflect(worklog.Entry{})
+	},
+	"state": func() *schema.Schema {
+		return schema.Reflect(map[string]worklog.LedgerRecord{})
+	},
+	"overtime": func() *schema.Schema {
+		return schema.Reflect(worklog.OvertimeBalance{})
+	},
+	"summary": func() *schema.Schema {
+		return schema.Reflect(runSummary{})
+	},
+	"receipt": func() *schema.Schema {
+		receipt := schema.Reflect(runSummary{})
+		receipt.Description = "summary of a sync that uploaded entries, signed by the receipt secret key if set"
+		return receipt
+	},
+	"mapping": func() *schema.Schema {
+		return schema.Reflect(struct {
+			Mappings []worklog.Mapping `json:"mappings"`
+		}{})
+	},
+}
+
+var exportSchemaCmd = &cobra.Command{
+	Use:   "export-schema [name...]",
+	Short: "Export the JSON schema of the file formats",
+	Long: fmt.Sprintf(`
+Export the JSON schema of the file formats used by minutes, so editors and
+validators can work with them. If no schema name is given, all schemas are
+exported.
+
+Available schemas: %v`, getSchemaNames()),
+	PreRun: bindCmdFlags,
+	Run:    runExportSchemaCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(exportSchemaCmd)
+
+	exportSchemaCmd.Flags().StringP("schema-output-dir", "", "", "write the schemas as <name>.schema.json files into the directory")
+}
+
+// getConfigFlags returns the flags that can be set in the config file,
+// including the flags of the subcommands bound by bindCmdFlags.
+func getConfigFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet(program, pflag.ContinueOnError)
+	addConfigFlags(flags, rootCmd)
+	return flags
+}
+
+// addConfigFlags adds the flags of the command and its subcommands to the flag
+// set. Flags already added are kept, so the persistent flags of the root
+// command take precedence.
+func addConfigFlags(flags *pflag.FlagSet, cmd *cobra.Command) {
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		// The help and version flags are not config options
+		if flag.Name == "help" || flag.Name == "version" {
+			return
+		}
+
+		if flags.Lookup(flag.Name) == nil {
+			flags.AddFlag(flag)
+		}
+	})
+
+	for _, subCmd := range cmd.Commands() {
+		addConfigFlags(flags, subCmd)
+	}
+}
+
+func getSchemaNames() []string {
+	var names []string
+
+	for name := range schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func getSchema(name string) (*schema.Schema, error) {
+	schemaFunc, ok := schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("\"%s\" is not part of the available schemas %v", name, getSchemaNames())
+	}
+
+	return schemaFunc().Document(schemaBaseURL+name+".schema.json", name), nil
+}
+
+func runExportSchemaCmd(_ *cobra.Command, args []string) {
+	names := args
+	if len(names) == 0 {
+		names = getSchemaNames()
+	}
+
+	exported := map[string]*schema.Schema{}
+	for _, name := range names {
+		s, err := getSchema(name)
+		cobra.CheckErr(err)
+		exported[name] = s
+	}
+
+	outputDir := viper.GetString("schema-output-dir")
+	if outputDir == "" {
+		var output interface{} = exported
+		if len(exported) == 1 {
+			output = exported[names[0]]
+		}
+
+		data, err := json.MarshalIndent(output, "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Println(string(data))
+		return
+	}
+
+	for name, s := range exported {
+		data, err := json.MarshalIndent(s, "", "  ")
+		cobra.CheckErr(err)
+
+		path := filepath.Join(outputDir, name+".schema.json")
+		cobra.CheckErr(os.WriteFile(path, append(data, '\n'), 0600))
+		fmt.Println("Schema written to", path)
+	}
+}
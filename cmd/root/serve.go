@@ -0,0 +1,581 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/webhook"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// syncQueueSize is the number of days that can wait for sync.
+	syncQueueSize int = 100
+)
+
+var (
+	// serverStartKeys lists the config keys read only when the server starts,
+	// hence changing them requires a restart.
+	serverStartKeys = []string{"listen", "companion-token", "source", "target", "route-targets", "route-tag-prefix", "storage", "impersonate", "infer-mapping", "watch-config"}
+	// serverStartKeyPrefixes lists the prefixes of the config keys read only
+	// when the server starts. The keys of the targets are read only when the
+	// server starts too.
+	serverStartKeyPrefixes = []string{"webhook-", "circuit-breaker-", "storage-", "jira-"}
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run minutes in server mode",
+	Long: `
+Run minutes in server mode and sync entries when a source reports changes
+through webhooks. Every webhook provider is enabled by setting its secret.
+
+The webhooks are accepted on the following paths:
+
+  /webhooks/clockify
+  /webhooks/toggl
+  /webhooks/github
+
+When --companion-token is set, single entries sent by companions, like a
+browser extension or a launcher script, are accepted on /entries. The entries
+are staged and uploaded by the next sync.
+
+When a change is reported, the entries of the affected day are fetched and the
+not yet uploaded durations are uploaded to the target without confirmation.
+The uploaded durations are persisted in the configured storage, so restarting
+the server does not upload them again.
+
+After --circuit-breaker-threshold consecutive failed syncs, the syncs are
+paused for --circuit-breaker-cooldown, so an unavailable source or target is
+not flooded by requests. The reported days are kept queued meanwhile. The
+state of the circuit breaker is shown by "minutes status".
+
+The config file is watched and its changes, like the mappings, filters and
+rounding, are applied without restart, after validating them. The changes of
+the credentials and the keys read only when the server starts, like the
+target, the storage and the webhook secrets, are logged and applied after
+restart.`,
+	PreRun: bindCmdFlags,
+	Run:    runServeCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringP("listen", "", "127.0.0.1:8080", "set the address the server listens on")
+	serveCmd.Flags().StringP("webhook-clockify-secret", "", "", "set the Clockify webhook signing secret")
+	serveCmd.Flags().StringP("webhook-toggl-secret", "", "", "set the Toggl Track webhook signing secret")
+	serveCmd.Flags().StringP("webhook-github-secret", "", "", "set the GitHub webhook signing secret")
+	serveCmd.Flags().StringP("companion-token", "", "", "set the bearer token of the companions staging entries")
+	serveCmd.Flags().IntP("circuit-breaker-threshold", "", 5, "set the number of consecutive failed syncs pausing the syncs, 0 disables the circuit breaker")
+	serveCmd.Flags().DurationP("circuit-breaker-cooldown", "", time.Minute*15, "set the duration the syncs are paused for by the circuit breaker")
+	serveCmd.Flags().BoolP("watch-config", "", true, "apply the changes of the config file without restart")
+}
+
+// daemonState represents the state of the server mode, persisted after every
+// sync, so it can be inspected by "minutes status".
+type daemonState struct {
+	Circuit client.CircuitState `json:"circuit"`
+	// LastRun is the summary of the last sync, without its entries.
+	LastRun *runSummary `json:"last_run,omitempty"`
+	// LastSuccessfulRun is the summary of the last sync without failures,
+	// without its entries.
+	LastSuccessfulRun *runSummary `json:"last_successful_run,omitempty"`
+}
+
+// loadDaemonState returns the state of the server mode persisted in the store.
+// If no state was persisted yet, an empty state returns.
+func loadDaemonState(ctx context.Context, store storage.Store) (*daemonState, error) {
+	state := &daemonState{}
+
+	data, err := store.Get(ctx, daemonStateKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// saveDaemonState persists the state of the server mode in the store.
+func saveDaemonState(ctx context.Context, store storage.Store, state *daemonState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, daemonStateKey, data)
+}
+
+// syncServer syncs the entries of the days reported by webhooks. Each day is
+// queued at most once at a time and synced sequentially.
+type syncServer struct {
+	uploader client.Uploader
+	store    storage.Store
+	ledger   *worklog.Ledger
+	stage    *staging.Stage
+	breaker  *client.CircuitBreaker
+	state    *daemonState
+
+	// mu guards the pending days and the config, which is changed by reloads
+	mu      sync.Mutex
+	pending map[string]bool
+	queue   chan time.Time
+
+	configPath    string
+	configContent []byte
+	pinnedKeys    map[string]bool
+	reloads       chan bool
+}
+
+// enqueue queues the day of the event for sync, unless the event belongs to
+// other user than the configured source user.
+func (s *syncServer) enqueue(event *webhook.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sourceUser := viper.GetString("source-user")
+	if sourceUser != "" && event.User != "" && event.User != sourceUser {
+		log.Printf("ignoring event of user %s\n", event.User)
+		return
+	}
+
+	loc := getRangeLocation()
+	year, month, day := event.Date.In(loc).Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	key := date.Format("2006-01-02")
+
+	if s.pending[key] {
+		return
+	}
+
+	select {
+	case s.queue <- date:
+		s.pending[key] = true
+	default:
+		log.Printf("sync queue is full, dropping sync of %s\n", key)
+	}
+}
+
+// stageEntry stages the entry sent by a companion for the next sync.
+func (s *syncServer) stageEntry(entry worklog.Entry) error {
+	if err := s.stage.Add(context.Background(), entry); err != nil {
+		return err
+	}
+
+	log.Printf("staged entry #%s of %s\n", entry.Provenance.SourceIDs[0], entry.Task.Name)
+	return nil
+}
+
+// requestReload requests reloading the config file. Multiple requests are
+// merged while the server is busy.
+func (s *syncServer) requestReload() {
+	select {
+	case s.reloads <- true:
+	default:
+	}
+}
+
+// isServerStartKey returns true if the config key is read only when the server
+// starts, or holds a credential.
+func isServerStartKey(key string) bool {
+	if utils.IsCredentialKey(key) || utils.IsSliceContains(key, serverStartKeys) {
+		return true
+	}
+
+	prefixes := make([]string, len(serverStartKeyPrefixes))
+	copy(prefixes, serverStartKeyPrefixes)
+
+	for _, target := range append([]string{viper.GetString("target")}, viper.GetStringSlice("route-targets")...) {
+		prefixes = append(prefixes, target+"-")
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseConfig parses the content of the config file.
+func parseConfig(path string, content []byte) (*viper.Viper, error) {
+	config := viper.New()
+	config.SetConfigType(strings.TrimPrefix(filepath.Ext(path), "."))
+
+	if err := config.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// validateReloadedConfig validates the config used by the syncs. Unlike the
+// validation of the flags, the errors are returned, so the server can keep
+// running with the previous config.
+func validateReloadedConfig() error {
+	if _, err := regexp.Compile(viper.GetString("tags-as-tasks-regex")); err != nil {
+		return err
+	}
+
+	if _, err := newPipeline(); err != nil {
+		return err
+	}
+
+	for _, source := range getSourceNames() {
+		if _, err := getFetcher(source); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+
+	return nil
+}
+
+// reloadConfig applies the changes of the config file. The changes of the
+// credentials and the keys read only when the server starts are logged, but
+// their values are kept until restart. If the changed config is invalid, the
+// previous config is kept.
+func (s *syncServer) reloadConfig() {
+	content, err := os.ReadFile(s.configPath)
+	if err != nil {
+		log.Printf("failed to read the config file: %v\n", err)
+		return
+	}
+
+	if bytes.Equal(content, s.configContent) {
+		return
+	}
+
+	previous, err := parseConfig(s.configPath, s.configContent)
+	if err != nil {
+		log.Printf("failed to parse the previous config file: %v\n", err)
+		return
+	}
+
+	current, err := parseConfig(s.configPath, content)
+	if err != nil {
+		log.Printf("ignoring the invalid config file: %v\n", err)
+		return
+	}
+
+	var appliedKeys []string
+	var restartKeys []string
+
+	for _, key := range utils.ChangedConfigKeys(previous, current) {
+		if isServerStartKey(key) {
+			restartKeys = append(restartKeys, key)
+		} else {
+			appliedKeys = append(appliedKeys, key)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The keys requiring a restart are overridden by their current value, so
+	// reading the new config does not change them
+	for _, key := range restartKeys {
+		if !s.pinnedKeys[key] {
+			viper.Set(key, viper.Get(key))
+			s.pinnedKeys[key] = true
+		}
+	}
+
+	err = viper.ReadConfig(bytes.NewReader(content))
+	if err == nil {
+		err = validateReloadedConfig()
+	}
+
+	if err != nil {
+		if restoreErr := viper.ReadConfig(bytes.NewReader(s.configContent)); restoreErr != nil {
+			log.Printf("failed to restore the previous config: %v\n", restoreErr)
+		}
+
+		log.Printf("ignoring the invalid config file, keeping the previous config: %v\n", err)
+		return
+	}
+
+	s.configContent = content
+
+	for _, key := range restartKeys {
+		log.Printf("config %s changed, restart the server to apply it\n", key)
+	}
+
+	if len(appliedKeys) != 0 {
+		log.Printf("config reloaded, changed %s\n", strings.Join(appliedKeys, ", "))
+	}
+}
+
+// watchConfig watches the config file and requests reloading it on changes.
+// If no config file is used, nothing is watched.
+func (s *syncServer) watchConfig(ctx context.Context) error {
+	s.configPath = viper.ConfigFileUsed()
+	if s.configPath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(s.configPath)
+	if err != nil {
+		return err
+	}
+
+	s.configContent = content
+
+	if err = utils.WatchFile(ctx, s.configPath, s.requestReload); err != nil {
+		return err
+	}
+
+	log.Printf("watching config file %s\n", s.configPath)
+	return nil
+}
+
+// run syncs the queued days until the context is canceled. The config is
+// reloaded between the syncs, so a sync is not affected by a reload.
+func (s *syncServer) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.reloads:
+			s.reloadConfig()
+		case date := <-s.queue:
+			if wait := s.breaker.Wait(); wait > 0 {
+				log.Printf("circuit breaker is open, pausing syncs for %s\n", wait.Round(time.Second))
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+
+			s.mu.Lock()
+			delete(s.pending, date.Format("2006-01-02"))
+			s.mu.Unlock()
+
+			err := s.sync(ctx, date, date.AddDate(0, 0, 1))
+			if err != nil {
+				log.Printf("failed to sync %s: %v\n", date.Format("2006-01-02"), err)
+			}
+
+			s.breaker.Record(err)
+			s.state.Circuit = s.breaker.State()
+
+			if err = saveDaemonState(ctx, s.store, s.state); err != nil {
+				log.Printf("failed to save the server state: %v\n", err)
+			}
+		}
+	}
+}
+
+// sync fetches the entries between start and end, then uploads the pending
+// durations of the complete entries, together with the staged entries. The
+// entries are uploaded one by one, so only successful uploads are recorded in
+// the ledger.
+func (s *syncServer) sync(ctx context.Context, start time.Time, end time.Time) error {
+	fetchCtx, cancelFetch := withDeadline(ctx, "fetch-deadline")
+	defer cancelFetch()
+
+	entries, err := fetchRawEntries(fetchCtx, start, end)
+	if err != nil {
+		return checkDeadline(fetchCtx, "fetch-deadline", err)
+	}
+
+	stagedEntries, err := s.stage.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	resolveCtx, cancelResolve := withDeadline(ctx, "resolve-deadline")
+	defer cancelResolve()
+
+	entries, err = transformEntries(resolveCtx, append(entries, stagedEntries...))
+	if err != nil {
+		return checkDeadline(resolveCtx, "resolve-deadline", err)
+	}
+
+	if err = warmUpCredentials(ctx, s.uploader); err != nil {
+		return err
+	}
+
+	wl := newWorklog(entries)
+	pendingEntries := s.ledger.Pending(wl.CompleteEntries())
+	uploadOpts := getUploadOpts()
+
+	if _, err = client.CheckLimits(s.uploader, pendingEntries, uploadOpts); err != nil {
+		return err
+	}
+
+	var uploadErrors []error
+	var uploadedEntries worklog.Entries
+	failedSourceIDs := map[string]bool{}
+
+	// The entries are uploaded one by one, but the deadline covers the upload
+	// of every entry
+	uploadCtx, cancelUpload := withDeadline(ctx, "upload-deadline")
+	defer cancelUpload()
+
+	for _, entry := range pendingEntries {
+		if viper.GetBool("dry-run") {
+			log.Printf("dry-run: skipping upload of %s\n", entry.Key())
+			continue
+		}
+
+		if errs := uploadEntries(uploadCtx, s.uploader, worklog.Entries{entry}, uploadOpts); len(errs) != 0 {
+			for _, err := range errs {
+				uploadErrors = append(uploadErrors, checkDeadline(uploadCtx, "upload-deadline", err))
+			}
+
+			for _, id := range entry.Provenance.SourceIDs {
+				failedSourceIDs[id] = true
+			}
+			continue
+		}
+
+		s.ledger.Record(entry)
+		uploadedEntries = append(uploadedEntries, entry)
+	}
+
+	// The ledger is saved even if some uploads failed, so the successful
+	// uploads are not repeated after a restart
+	if err = saveLedger(ctx, s.store, s.ledger); err != nil {
+		uploadErrors = append(uploadErrors, fmt.Errorf("failed to save ledger: %v", err))
+	}
+
+	if !viper.GetBool("dry-run") {
+		if err = unstageEntries(ctx, s.stage, wl.CompleteEntries(), failedSourceIDs); err != nil {
+			uploadErrors = append(uploadErrors, fmt.Errorf("failed to unstage entries: %v", err))
+		}
+	}
+
+	// The calendar is only a visual record of the uploads, so failing to write
+	// it does not fail the sync
+	if err = writeCalendarEvents(ctx, uploadedEntries); err != nil {
+		log.Printf("failed to write the uploaded entries to the calendar: %v\n", err)
+	}
+
+	summary := newRunSummary(start, end, pendingEntries, uploadErrors)
+	summary.Entries = nil
+
+	s.state.LastRun = summary
+	if len(uploadErrors) == 0 {
+		s.state.LastSuccessfulRun = summary
+	}
+
+	log.Printf(
+		"synced %s: %d entries uploaded, %d failed, %d incomplete\n",
+		start.Format("2006-01-02"),
+		len(pendingEntries)-len(uploadErrors),
+		len(uploadErrors),
+		len(wl.IncompleteEntries()),
+	)
+
+	return errors.Join(uploadErrors...)
+}
+
+func runServeCmd(_ *cobra.Command, _ []string) {
+	validateFlags()
+	validateStorageFlags()
+
+	if viper.GetInt("circuit-breaker-threshold") < 0 {
+		cobra.CheckErr("circuit breaker threshold cannot be negative")
+	}
+
+	if viper.GetDuration("circuit-breaker-cooldown") <= 0 {
+		cobra.CheckErr("circuit breaker cooldown must be positive")
+	}
+
+	uploader, err := getUploader()
+	cobra.CheckErr(err)
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	ledger, err := loadLedger(context.Background(), store)
+	cobra.CheckErr(err)
+
+	state, err := loadDaemonState(context.Background(), store)
+	cobra.CheckErr(err)
+
+	server := &syncServer{
+		uploader: uploader,
+		store:    store,
+		ledger:   ledger,
+		stage:    staging.NewStage(store, stagedEntriesKey),
+		breaker: client.NewCircuitBreaker(
+			viper.GetInt("circuit-breaker-threshold"),
+			viper.GetDuration("circuit-breaker-cooldown"),
+			state.Circuit,
+		),
+		state:      state,
+		pending:    map[string]bool{},
+		queue:      make(chan time.Time, syncQueueSize),
+		pinnedKeys: map[string]bool{},
+		reloads:    make(chan bool, 1),
+	}
+
+	if viper.GetBool("watch-config") {
+		cobra.CheckErr(server.watchConfig(context.Background()))
+	}
+
+	providers := map[string]webhook.Provider{}
+
+	if secret := viper.GetString("webhook-clockify-secret"); secret != "" {
+		providers["clockify"] = &webhook.ClockifyProvider{Secret: secret}
+	}
+
+	if secret := viper.GetString("webhook-toggl-secret"); secret != "" {
+		providers["toggl"] = &webhook.TogglProvider{Secret: secret}
+	}
+
+	if secret := viper.GetString("webhook-github-secret"); secret != "" {
+		providers["github"] = &webhook.GitHubProvider{Secret: secret}
+	}
+
+	mux := http.NewServeMux()
+	for name, provider := range providers {
+		mux.Handle(fmt.Sprintf("/webhooks/%s", name), webhook.NewHandler(provider, server.enqueue))
+		log.Printf("accepting %s webhooks\n", name)
+	}
+
+	if token := viper.GetString("companion-token"); token != "" {
+		mux.Handle("/entries", staging.NewHandler(token, server.stageEntry))
+		log.Println("accepting companion entries")
+	}
+
+	go server.run(context.Background())
+
+	listen := viper.GetString("listen")
+	log.Printf("listening on %s\n", listen)
+
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	cobra.CheckErr(httpServer.ListenAndServe())
+}
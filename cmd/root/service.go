@@ -0,0 +1,133 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/service"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// serviceCommandTimeout is the timeout of a service manager command.
+	serviceCommandTimeout time.Duration = time.Second * 30
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage minutes running as a background service",
+	Long: `
+Register minutes in server mode as a background service, using the service
+manager of the operating system:
+
+  Linux:   systemd user unit
+  macOS:   launchd user agent
+  Windows: Task Scheduler task started at logon
+
+The service runs "minutes serve" with the current config file.`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install [-- serve flags...]",
+	Short: "Install and start the service",
+	Long: `
+Install and start the service. The service runs "minutes serve" with the
+current config file. Additional flags of the serve command can be passed after
+"--".`,
+	PreRun: bindCmdFlags,
+	Run:    runServiceInstallCmd,
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and uninstall the service",
+	Args:  cobra.NoArgs,
+	Run:   runServiceUninstallCmd,
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the service",
+	Args:  cobra.NoArgs,
+	Run:   runServiceStatusCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+	serviceCmd.AddCommand(serviceInstallCmd, serviceUninstallCmd, serviceStatusCmd)
+
+	serviceInstallCmd.Flags().BoolP("service-print", "", false, "print the service definition without installing it")
+}
+
+// getServiceManager returns the service manager of the current platform,
+// running "minutes serve" with the current config file and the given
+// additional arguments.
+func getServiceManager(args []string) (service.Manager, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	configArgs, err := utils.ConfigFileArgs(viper.ConfigFileUsed())
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := append([]string{serveCmd.Name()}, configArgs...)
+
+	return service.NewManager(runtime.GOOS, &service.Opts{
+		Name:               program,
+		Description:        "Minutes sync server",
+		Executable:         executable,
+		Arguments:          append(arguments, args...),
+		HomeDir:            homeDir,
+		LogDir:             getDefaultStoragePath(),
+		Timeout:            serviceCommandTimeout,
+		CommandCtxExecutor: exec.CommandContext,
+	})
+}
+
+func runServiceInstallCmd(_ *cobra.Command, args []string) {
+	manager, err := getServiceManager(args)
+	cobra.CheckErr(err)
+
+	if viper.GetBool("service-print") {
+		definition, err := manager.Definition()
+		cobra.CheckErr(err)
+
+		fmt.Printf("# %s\n%s", manager.Path(), string(definition))
+		return
+	}
+
+	cobra.CheckErr(manager.Install(context.Background()))
+	fmt.Print(tr("Service installed: %s\n", manager.Path()))
+}
+
+func runServiceUninstallCmd(_ *cobra.Command, _ []string) {
+	manager, err := getServiceManager(nil)
+	cobra.CheckErr(err)
+
+	cobra.CheckErr(manager.Uninstall(context.Background()))
+	fmt.Println(tr("Service uninstalled"))
+}
+
+func runServiceStatusCmd(_ *cobra.Command, _ []string) {
+	manager, err := getServiceManager(nil)
+	cobra.CheckErr(err)
+
+	status, err := manager.Status(context.Background())
+	cobra.CheckErr(err)
+
+	fmt.Print(tr("Service %s\n", status))
+}
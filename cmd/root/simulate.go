@@ -0,0 +1,225 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/pipeline"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// noProjectName is the name of the project of the entries without
+	// project in the simulation report.
+	noProjectName string = "(no project)"
+)
+
+// roundingScenario represents a rounding configuration compared by the
+// simulate-rounding command. The options match the rounding flags.
+type roundingScenario struct {
+	Name                 string        `mapstructure:"name"`
+	RoundToClosestMinute bool          `mapstructure:"round-to-closest-minute"`
+	ForceBilledDuration  bool          `mapstructure:"force-billed-duration"`
+	RoundIncrement       time.Duration `mapstructure:"round-increment"`
+	RoundMode            string        `mapstructure:"round-mode"`
+}
+
+// defaultRoundingScenarios are compared if no scenarios are configured.
+var defaultRoundingScenarios = []roundingScenario{
+	{Name: "closest minute", RoundToClosestMinute: true},
+	{Name: "6m nearest", RoundIncrement: time.Minute * 6, RoundMode: pipeline.RoundNearest},
+	{Name: "15m nearest", RoundIncrement: time.Minute * 15, RoundMode: pipeline.RoundNearest},
+	{Name: "15m up", RoundIncrement: time.Minute * 15, RoundMode: pipeline.RoundUp},
+	{Name: "billed, 15m up", ForceBilledDuration: true, RoundIncrement: time.Minute * 15, RoundMode: pipeline.RoundUp},
+}
+
+var simulateRoundingCmd = &cobra.Command{
+	Use:   "simulate-rounding",
+	Short: "Compare the billed totals of rounding configurations",
+	Long: `
+Fetch the entries from the source once, apply several rounding and billing
+configurations on them and print the billed hours per project, with the
+difference from the unrounded hours.
+
+The entries are transformed the same way as during the sync, except that the
+rounding stage is replaced by the compared configurations. The configurations
+are set by "rounding-scenarios" in the config file; if not set, a few common
+configurations are compared.`,
+	PreRun: bindCmdFlags,
+	Run:    runSimulateRoundingCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(simulateRoundingCmd)
+}
+
+// getRoundingScenarios returns the configured rounding scenarios, or the
+// default scenarios if none are configured.
+func getRoundingScenarios() ([]roundingScenario, error) {
+	var scenarios []roundingScenario
+	if err := viper.UnmarshalKey("rounding-scenarios", &scenarios); err != nil {
+		return nil, err
+	}
+
+	if len(scenarios) == 0 {
+		return defaultRoundingScenarios, nil
+	}
+
+	names := map[string]bool{}
+	for _, scenario := range scenarios {
+		if scenario.Name == "" {
+			return nil, errors.New("rounding scenario name must be set")
+		}
+
+		if names[scenario.Name] {
+			return nil, fmt.Errorf("\"%s\" rounding scenario is set multiple times", scenario.Name)
+		}
+
+		if scenario.RoundIncrement < 0 {
+			return nil, fmt.Errorf("\"%s\" rounding scenario increment must not be negative", scenario.Name)
+		}
+
+		if scenario.RoundMode != "" && !utils.IsSliceContains(scenario.RoundMode, pipeline.RoundModes) {
+			return nil, fmt.Errorf("\"%s\" is not part of the supported round modes %v", scenario.RoundMode, pipeline.RoundModes)
+		}
+
+		names[scenario.Name] = true
+	}
+
+	return scenarios, nil
+}
+
+// fetchUnroundedEntries fetches the entries and transforms them by the
+// pipeline without the rounding stage. Only the complete entries are
+// returned, since only those are uploaded.
+func fetchUnroundedEntries(start time.Time, end time.Time) (worklog.Entries, error) {
+	entries, err := fetchRawEntries(context.Background(), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	transformPipeline, err := newPipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	transformPipeline.Remove(pipeline.StageRound)
+
+	entries, err = transformPipeline.Run(context.Background(), entries)
+	if err != nil {
+		return nil, err
+	}
+
+	wl := newWorklog(entries)
+	return wl.CompleteEntries(), nil
+}
+
+// sumBillableByProject returns the billable duration of the entries per
+// project name.
+func sumBillableByProject(entries worklog.Entries) map[string]time.Duration {
+	totals := map[string]time.Duration{}
+
+	for _, entry := range entries {
+		project := entry.Project.Name
+		if project == "" {
+			project = noProjectName
+		}
+
+		totals[project] += entry.BillableDuration
+	}
+
+	return totals
+}
+
+func runSimulateRoundingCmd(_ *cobra.Command, _ []string) {
+	validateSourceFlags()
+
+	scenarios, err := getRoundingScenarios()
+	cobra.CheckErr(err)
+
+	start, end := getTimeRange()
+
+	entries, err := fetchUnroundedEntries(start, end)
+	cobra.CheckErr(err)
+
+	unroundedTotals := sumBillableByProject(entries)
+
+	scenarioTotals := make([]map[string]time.Duration, len(scenarios))
+	for i, scenario := range scenarios {
+		roundedEntries, err := pipeline.Round(&pipeline.RoundOpts{
+			RoundToClosestMinute:  scenario.RoundToClosestMinute,
+			TreatDurationAsBilled: scenario.ForceBilledDuration,
+			Increment:             scenario.RoundIncrement,
+			Mode:                  scenario.RoundMode,
+		}).Transform(context.Background(), entries)
+		cobra.CheckErr(err)
+
+		scenarioTotals[i] = sumBillableByProject(roundedEntries)
+	}
+
+	projects := make([]string, 0, len(unroundedTotals))
+	for project := range unroundedTotals {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	reportLocale := getLocale()
+
+	// formatTotal returns the billed hours of the scenario and the difference
+	// from the unrounded hours
+	formatTotal := func(total time.Duration, unrounded time.Duration) string {
+		diff := reportLocale.FormatHours(total-unrounded, 2)
+		if total >= unrounded {
+			diff = "+" + diff
+		}
+
+		return fmt.Sprintf("%s (%s)", reportLocale.FormatHours(total, 2), diff)
+	}
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.SetTitle(fmt.Sprintf("Billed hours by rounding (%s - %s)", reportLocale.FormatDateTime(start.Local()), reportLocale.FormatDateTime(end.Local())))
+
+	header := table.Row{"Project", "Unrounded"}
+	columnConfigs := []table.ColumnConfig{{Number: 2, Align: text.AlignRight, AlignFooter: text.AlignRight}}
+	for i, scenario := range scenarios {
+		header = append(header, scenario.Name)
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Number: i + 3, Align: text.AlignRight, AlignFooter: text.AlignRight})
+	}
+
+	writer.AppendHeader(header)
+	writer.SetColumnConfigs(columnConfigs)
+
+	var unroundedTotal time.Duration
+	totals := make([]time.Duration, len(scenarios))
+
+	for _, project := range projects {
+		row := table.Row{project, reportLocale.FormatHours(unroundedTotals[project], 2)}
+		unroundedTotal += unroundedTotals[project]
+
+		for i := range scenarios {
+			row = append(row, formatTotal(scenarioTotals[i][project], unroundedTotals[project]))
+			totals[i] += scenarioTotals[i][project]
+		}
+
+		writer.AppendRow(row)
+	}
+
+	footer := table.Row{"Total", reportLocale.FormatHours(unroundedTotal, 2)}
+	for i := range scenarios {
+		footer = append(footer, formatTotal(totals[i], unroundedTotal))
+	}
+
+	writer.AppendFooter(footer)
+	writer.Render()
+}
@@ -0,0 +1,256 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// statusErrorLength is the maximum length of the errors shown in the
+	// status table.
+	statusErrorLength int = 60
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the health of the configured sources and target",
+	Long: `
+Show per configured source and target the last successful sync, the number of
+entries uploaded by the last sync, the number of staged entries waiting for
+upload, the expiry of the credentials and the state of the server mode's
+circuit breaker.
+
+The syncs are read from the history, if enabled, and from the state of the
+server mode. Dry runs are not considered as syncs.
+
+The short-living credentials, like the Personio access tokens, are obtained to
+check them and show their expiry. API keys and passwords are not checked.`,
+	PreRun: bindCmdFlags,
+	Run:    runStatusCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+// clientStatus represents the health of a configured source or target.
+type clientStatus struct {
+	Kind    string
+	Name    string
+	LastRun *runSummary
+	// LastSuccessfulRun is the last sync of the client without failures.
+	LastSuccessfulRun *runSummary
+}
+
+// involves returns true if the client took part in the sync.
+func (s *clientStatus) involves(summary *runSummary) bool {
+	if s.Kind == "target" {
+		return summary.Target == s.Name
+	}
+
+	for _, source := range strings.Split(summary.Source, ",") {
+		if strings.TrimSpace(source) == s.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// record sets the last syncs of the client if the sync is newer than the
+// known ones.
+func (s *clientStatus) record(summary *runSummary) {
+	if summary == nil || summary.DryRun || !s.involves(summary) {
+		return
+	}
+
+	if s.LastRun == nil || summary.RanAt.After(s.LastRun.RanAt) {
+		s.LastRun = summary
+	}
+
+	if summary.Failed == 0 && (s.LastSuccessfulRun == nil || summary.RanAt.After(s.LastSuccessfulRun.RanAt)) {
+		s.LastSuccessfulRun = summary
+	}
+}
+
+// isComplete returns true if both last syncs of the client are known, so older
+// syncs cannot change them.
+func (s *clientStatus) isComplete() bool {
+	return s.LastRun != nil && s.LastSuccessfulRun != nil
+}
+
+// recordHistory records the syncs of the history, starting from the latest,
+// until the last syncs of every client are known.
+func recordHistory(ctx context.Context, store storage.Store, statuses []*clientStatus) error {
+	objects, err := store.List(ctx, storage.PrefixHistory)
+	if err != nil {
+		return err
+	}
+
+	for i := len(objects) - 1; i >= 0; i-- {
+		isComplete := true
+		for _, status := range statuses {
+			isComplete = isComplete && status.isComplete()
+		}
+
+		if isComplete {
+			return nil
+		}
+
+		data, err := store.Get(ctx, objects[i].Key)
+		if err != nil {
+			return err
+		}
+
+		var summary runSummary
+		if err = json.Unmarshal(data, &summary); err != nil {
+			return fmt.Errorf("invalid history %s: %v", objects[i].Key, err)
+		}
+
+		for _, status := range statuses {
+			status.record(&summary)
+		}
+	}
+
+	return nil
+}
+
+// getCredentialStatus returns the expiry of the client's short-living
+// credentials, obtaining them if needed.
+func getCredentialStatus(ctx context.Context, c interface{}, err error) string {
+	if err != nil {
+		return tr("error: %s", utils.Truncate(err.Error(), statusErrorLength))
+	}
+
+	inspector, ok := c.(client.CredentialInspector)
+	if !ok {
+		return tr("not expiring")
+	}
+
+	if warmer, ok := c.(client.CredentialWarmer); ok {
+		// The errors of obtaining the credentials may contain the secrets,
+		// like the request URL, so only their kind is shown
+		if err = warmer.WarmUpCredentials(ctx, 0); err != nil {
+			return tr("error: cannot obtain credentials (%s)", client.KindOf(err))
+		}
+	}
+
+	expiresAt := inspector.CredentialsExpireAt()
+	if expiresAt.IsZero() {
+		return tr("unknown expiry")
+	}
+
+	return tr("expires %s", getLocale().FormatDateTime(expiresAt.Local()))
+}
+
+// getCircuitStatus returns the state of the server mode's circuit breaker.
+func getCircuitStatus(state *daemonState) string {
+	if state.LastRun == nil && state.Circuit.Failures == 0 {
+		return "-"
+	}
+
+	status := state.Circuit.Status(time.Now())
+
+	switch status {
+	case client.CircuitOpen:
+		status += " until " + getLocale().FormatDateTime(state.Circuit.OpenUntil.Local())
+	case client.CircuitClosed:
+		if state.Circuit.Failures == 0 {
+			return status
+		}
+	}
+
+	return fmt.Sprintf("%s, %d failed: %s", status, state.Circuit.Failures, utils.Truncate(state.Circuit.LastError, statusErrorLength))
+}
+
+func runStatusCmd(_ *cobra.Command, _ []string) {
+	validateStorageFlags()
+
+	ctx := context.Background()
+	reportLocale := getLocale()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	var statuses []*clientStatus
+	for _, source := range getSourceNames() {
+		statuses = append(statuses, &clientStatus{Kind: "source", Name: source})
+	}
+
+	if target := viper.GetString("target"); target != "" {
+		statuses = append(statuses, &clientStatus{Kind: "target", Name: target})
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println(tr("No source or target configured."))
+		return
+	}
+
+	state, err := loadDaemonState(ctx, store)
+	cobra.CheckErr(err)
+
+	for _, status := range statuses {
+		status.record(state.LastRun)
+		status.record(state.LastSuccessfulRun)
+	}
+
+	cobra.CheckErr(recordHistory(ctx, store, statuses))
+
+	stagedEntries, err := staging.NewStage(store, stagedEntriesKey).Entries(ctx)
+	cobra.CheckErr(err)
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.SetTitle(tr("Status"))
+	writer.AppendHeader(table.Row{tr("Kind"), tr("Name"), tr("Last successful sync"), tr("Last uploaded"), tr("Staged"), tr("Credentials"), tr("Circuit breaker")})
+
+	for _, status := range statuses {
+		lastSuccessfulSync := tr("never")
+		if status.LastSuccessfulRun != nil {
+			lastSuccessfulSync = reportLocale.FormatDateTime(status.LastSuccessfulRun.RanAt.Local())
+		}
+
+		lastUploaded := "-"
+		if status.LastRun != nil {
+			lastUploaded = strconv.Itoa(status.LastRun.Uploaded)
+		}
+
+		// The staged entries are waiting for the target, regardless of the
+		// sources
+		staged := "-"
+
+		var c interface{}
+		if status.Kind == "target" {
+			staged = strconv.Itoa(len(stagedEntries))
+			c, err = getUploader()
+		} else {
+			c, err = getFetcher(status.Name)
+		}
+
+		writer.AppendRow(table.Row{
+			status.Kind,
+			status.Name,
+			lastSuccessfulSync,
+			lastUploaded,
+			staged,
+			getCredentialStatus(ctx, c, err),
+			getCircuitStatus(state),
+		})
+	}
+
+	writer.Render()
+}
@@ -0,0 +1,150 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/spf13/viper"
+)
+
+const (
+	// ledgerKey is the storage key of the ledger used by the server mode.
+	ledgerKey string = storage.PrefixState + "ledger.json"
+	// stagedEntriesKey is the storage key of the staged entries, added by
+	// `minutes add` or sent by companions in server mode.
+	stagedEntriesKey string = storage.PrefixState + "staged.json"
+	// timerKey is the storage key of the running timer of `minutes start`.
+	timerKey string = storage.PrefixState + "timer.json"
+	// daemonStateKey is the storage key of the state of the server mode, like
+	// its last runs and circuit breaker.
+	daemonStateKey string = storage.PrefixState + "daemon.json"
+	// hubstaffTokenKey is the storage key of the latest personal access token
+	// of Hubstaff, since Hubstaff rotates the tokens when they are exchanged.
+	hubstaffTokenKey string = storage.PrefixState + "hubstaff-token.json"
+)
+
+var (
+	storages = []string{"file", "sqlite", "s3"}
+
+	ErrNoStorageImplementation = errors.New("no storage implementation found")
+)
+
+// getDefaultStoragePath returns the directory used by the file storage and the
+// parent directory of the SQLite database if no storage path is set.
+func getDefaultStoragePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "." + program
+	}
+
+	return filepath.Join(configDir, program)
+}
+
+func getStore() (storage.Store, error) {
+	path := viper.GetString("storage-path")
+	if path == "" {
+		path = getDefaultStoragePath()
+	}
+
+	switch viper.GetString("storage") {
+	case "file":
+		return storage.NewFileStore(path)
+	case "sqlite":
+		if filepath.Ext(path) == "" {
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return nil, err
+			}
+
+			path = filepath.Join(path, program+".db")
+		}
+
+		return storage.NewSQLiteStore(&storage.SQLiteOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			CLIClient: client.CLIClient{
+				Command:            viper.GetString("storage-sqlite-command"),
+				CommandCtxExecutor: exec.CommandContext,
+			},
+			Path: path,
+		})
+	case "s3":
+		return storage.NewS3Store(&storage.S3Opts{
+			Endpoint:  viper.GetString("storage-s3-endpoint"),
+			Bucket:    viper.GetString("storage-s3-bucket"),
+			Region:    viper.GetString("storage-s3-region"),
+			AccessKey: viper.GetString("storage-s3-access-key"),
+			SecretKey: viper.GetString("storage-s3-secret-key"),
+			Prefix:    viper.GetString("storage-s3-prefix"),
+			Timeout:   client.DefaultRequestTimeout,
+		})
+	default:
+		return nil, ErrNoStorageImplementation
+	}
+}
+
+// getStage returns the stage of the entries waiting for upload.
+func getStage() (*staging.Stage, error) {
+	store, err := getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	return staging.NewStage(store, stagedEntriesKey), nil
+}
+
+// unstageEntries removes the staged entries which are part of the uploaded
+// complete entries, except the ones which upload failed. Since the staged
+// entries may be merged with fetched entries, the staged entries are looked
+// up by the source IDs. The incomplete staged entries are kept, so they can be
+// completed, like by the mappings.
+func unstageEntries(ctx context.Context, stage *staging.Stage, completeEntries worklog.Entries, failedSourceIDs map[string]bool) error {
+	var uploadedSourceIDs []string
+
+	for _, entry := range completeEntries {
+		for _, id := range entry.Provenance.SourceIDs {
+			if !failedSourceIDs[id] {
+				uploadedSourceIDs = append(uploadedSourceIDs, id)
+			}
+		}
+	}
+
+	return stage.Remove(ctx, uploadedSourceIDs)
+}
+
+// loadLedger returns the ledger persisted in the store. If no ledger was
+// persisted yet, an empty ledger returns.
+func loadLedger(ctx context.Context, store storage.Store) (*worklog.Ledger, error) {
+	ledger := worklog.NewLedger()
+
+	data, err := store.Get(ctx, ledgerKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ledger, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, ledger); err != nil {
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+// saveLedger persists the ledger in the store.
+func saveLedger(ctx context.Context, store storage.Store, ledger *worklog.Ledger) error {
+	data, err := json.Marshal(ledger)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, ledgerKey, data)
+}
@@ -0,0 +1,124 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// suggestedMapping is a suggested mapping printed in the format of the mapping
+// file. The empty fields are printed as well, to be filled manually.
+type suggestedMapping struct {
+	Summary string `toml:"summary"`
+	Client  string `toml:"client"`
+	Project string `toml:"project"`
+	Task    string `toml:"task"`
+}
+
+var suggestMappingsCmd = &cobra.Command{
+	Use:   "suggest-mappings",
+	Short: "Suggest mappings for entries without client, project or task",
+	Long: `
+Fetch the entries from the source, cluster the incomplete ones by the
+similarity of their summary and suggest a mapping for every cluster.
+
+If complete entries are matching a cluster, their client, project and task is
+suggested for the mapping. Otherwise, the client, project and task of the
+mapping must be filled manually; these suggestions are not written by --write.`,
+	PreRun: bindCmdFlags,
+	Run:    runSuggestMappingsCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(suggestMappingsCmd)
+
+	suggestMappingsCmd.Flags().Float64P("similarity", "", worklog.DefaultClusterSimilarity, "set the minimum summary similarity between 0 and 1")
+	suggestMappingsCmd.Flags().BoolP("write", "", false, "write the suggested mappings to the mapping file after confirmation")
+}
+
+func runSuggestMappingsCmd(_ *cobra.Command, _ []string) {
+	validateSourceFlags()
+
+	similarity := viper.GetFloat64("similarity")
+	if similarity <= 0 || similarity > 1 {
+		cobra.CheckErr(tr("similarity must be between 0 and 1"))
+	}
+
+	mappingFile := viper.GetString("mapping-file")
+	if viper.GetBool("write") && mappingFile == "" {
+		cobra.CheckErr(tr("mapping file must be set to write mappings"))
+	}
+
+	start, end := getTimeRange()
+	entries, err := fetchEntries(context.Background(), start, end)
+	cobra.CheckErr(err)
+
+	var completeEntries worklog.Entries
+	var incompleteEntries worklog.Entries
+
+	for _, entry := range entries {
+		if entry.IsComplete() {
+			completeEntries = append(completeEntries, entry)
+		} else {
+			incompleteEntries = append(incompleteEntries, entry)
+		}
+	}
+
+	clusters := worklog.ClusterBySummary(incompleteEntries, similarity)
+	if len(clusters) == 0 {
+		fmt.Println(tr("No unmapped entries found."))
+		return
+	}
+
+	var suggestions []worklog.Mapping
+	for i := range clusters {
+		cluster := clusters[i]
+		mapping := cluster.SuggestMapping(completeEntries)
+
+		// The suggestions without client, project and task must be filled
+		// manually, as they would match the entries without completing them
+		if !mapping.IsEmpty() {
+			suggestions = append(suggestions, mapping)
+		}
+
+		fmt.Print(tr("\n# %d entries, e.g. %q\n", len(cluster.Entries), cluster.Entries[0].Summary))
+
+		suggestion, err := toml.Marshal(map[string][]suggestedMapping{
+			mappingsKey: {{
+				Summary: mapping.Summary,
+				Client:  mapping.Client,
+				Project: mapping.Project,
+				Task:    mapping.Task,
+			}},
+		})
+		cobra.CheckErr(err)
+
+		fmt.Print(string(suggestion))
+	}
+
+	if !viper.GetBool("write") {
+		return
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Println(tr("\nNo mappings to write; fill the client, project or task of the suggestions manually."))
+		return
+	}
+
+	if strings.ToLower(utils.Prompt(tr("\nWrite %d mappings to %s? [y/n]: ", len(suggestions), mappingFile))) != "y" {
+		fmt.Println(tr("User interruption. Aborting."))
+		return
+	}
+
+	mappings, err := loadMappings(mappingFile)
+	cobra.CheckErr(err)
+
+	cobra.CheckErr(saveMappings(mappingFile, append(mappings, suggestions...)))
+	fmt.Print(tr("Mappings written to %s\n", mappingFile))
+}
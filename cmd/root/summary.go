@@ -0,0 +1,180 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// summaryKeyFormat is the time format used in the storage keys of the
+	// history and receipts.
+	summaryKeyFormat string = "20060102T150405Z"
+)
+
+// runError represents an error occurred during the sync. If the error is
+// caused by an unsuccessful HTTP response, the status code and the response
+// body are set. If the failed entry is known, the links to review its source
+// entries are set.
+type runError struct {
+	Kind       client.ErrorKind `json:"kind"`
+	Message    string           `json:"message"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Body       string           `json:"body,omitempty"`
+	SourceURLs []string         `json:"source_urls,omitempty"`
+}
+
+// newRunError returns the run error of the error.
+func newRunError(err error) runError {
+	runErr := runError{
+		Kind:    client.KindOf(err),
+		Message: err.Error(),
+	}
+
+	var httpErr *client.HTTPError
+	if errors.As(err, &httpErr) {
+		runErr.StatusCode = httpErr.StatusCode
+		runErr.Body = string(httpErr.Body)
+	}
+
+	if entry := client.EntryOf(err); entry != nil {
+		runErr.SourceURLs = entry.Provenance.SourceURLs
+	}
+
+	return runErr
+}
+
+// runSummary represents the outcome of a sync, including the provenance of
+// every entry, so every uploaded duration can be explained later.
+type runSummary struct {
+	Source   string          `json:"source"`
+	Target   string          `json:"target"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	RanAt    time.Time       `json:"ran_at"`
+	DryRun   bool            `json:"dry_run"`
+	Uploaded int             `json:"uploaded"`
+	Failed   int             `json:"failed"`
+	Errors   []runError      `json:"errors,omitempty"`
+	Entries  worklog.Entries `json:"entries"`
+}
+
+// newRunSummary returns the summary of the sync of the entries. Since the
+// upload errors are not bound to entries, the failed entries are not listed.
+func newRunSummary(start time.Time, end time.Time, entries worklog.Entries, uploadErrors []error) *runSummary {
+	summary := &runSummary{
+		Source:  viper.GetString("source"),
+		Target:  viper.GetString("target"),
+		Start:   start,
+		End:     end,
+		RanAt:   time.Now().UTC(),
+		DryRun:  viper.GetBool("dry-run"),
+		Failed:  len(uploadErrors),
+		Entries: entries,
+	}
+
+	if !summary.DryRun {
+		summary.Uploaded = len(entries) - len(uploadErrors)
+	}
+
+	for _, err := range uploadErrors {
+		summary.Errors = append(summary.Errors, newRunError(err))
+	}
+
+	return summary
+}
+
+// saveRunSummary writes the summary to the summary file if set, and persists
+// it in the history of the store if the history is enabled. If any entries
+// were uploaded, the summary is persisted as a receipt too, signed by the
+// receipt secret key if set.
+func saveRunSummary(ctx context.Context, summary *runSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if path := viper.GetString("summary-file"); path != "" {
+		if err = os.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+	}
+
+	if !viper.GetBool("history") {
+		return nil
+	}
+
+	store, err := getStore()
+	if err != nil {
+		return err
+	}
+
+	name := summary.RanAt.Format(summaryKeyFormat) + ".json"
+
+	if err = store.Put(ctx, storage.PrefixHistory+name, data); err != nil {
+		return err
+	}
+
+	if summary.Uploaded == 0 {
+		return nil
+	}
+
+	if err = store.Put(ctx, storage.PrefixReceipts+name, data); err != nil {
+		return err
+	}
+
+	key, err := getReceiptSecretKey()
+	if err != nil || key == nil {
+		return err
+	}
+
+	signature, err := signReceipt(key, name, summary.RanAt, data)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, storage.PrefixReceipts+name+receiptSignatureExt, signature)
+}
+
+// printProvenance prints where the entries come from and how they were
+// transformed.
+func printProvenance(entries worklog.Entries) {
+	reportLocale := getLocale()
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.SetTitle(tr("Entry provenance"))
+	writer.AppendHeader(table.Row{tr("Start"), tr("Task"), tr("Summary"), tr("Provenance")})
+
+	for _, entry := range entries {
+		writer.AppendRow(table.Row{
+			reportLocale.FormatDateTime(entry.Start.Local()),
+			entry.Task.Name,
+			entry.Summary,
+			entry.Provenance.String(),
+		})
+	}
+
+	writer.Render()
+	fmt.Println()
+}
+
+// recordRun saves the summary of the sync and reports if saving failed, then
+// calls the post-run hook and sends the notifications with the summary.
+func recordRun(start time.Time, end time.Time, entries worklog.Entries, uploadErrors []error) {
+	summary := newRunSummary(start, end, entries, uploadErrors)
+	cobra.CheckErr(saveRunSummary(context.Background(), summary))
+	runHook(context.Background(), summary)
+	sendNotifications(context.Background(), summary)
+}
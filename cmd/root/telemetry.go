@@ -0,0 +1,43 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/telemetry"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func getReporterOpts() *telemetry.ReporterOpts {
+	return &telemetry.ReporterOpts{
+		Mode:     viper.GetString("telemetry"),
+		Endpoint: viper.GetString("telemetry-url"),
+		Output:   os.Stdout,
+		Timeout:  client.DefaultRequestTimeout,
+	}
+}
+
+// reportUsage reports the anonymous usage statistics of the command if the
+// telemetry is enabled. Failing to report does not fail the command.
+func reportUsage(cmd *cobra.Command, entries int, errs ...error) {
+	opts := getReporterOpts()
+	if opts.Mode == telemetry.ModeOff {
+		return
+	}
+
+	report := telemetry.NewReport(version, cmd.CommandPath())
+	report.Source = viper.GetString("source")
+	report.Target = viper.GetString("target")
+	report.Entries = entries
+
+	for _, err := range errs {
+		report.AddError(err)
+	}
+
+	if err := telemetry.Send(context.Background(), report, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to report usage: %v\n", err)
+	}
+}
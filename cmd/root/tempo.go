@@ -0,0 +1,126 @@
+package root
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/tempo"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// tempoOAuthKey is the storage key of the latest refresh token of the
+	// Tempo Cloud OAuth app, since Tempo rotates the refresh tokens.
+	tempoOAuthKey string = storage.PrefixState + "tempo-oauth.json"
+)
+
+var authorizeTempoCmd = &cobra.Command{
+	Use:   "authorize-tempo",
+	Short: "Authorize the Tempo Cloud OAuth app",
+	Long: `
+Authorize the Tempo Cloud OAuth app set by --tempo-oauth-client-id to log time
+on behalf of the user, using the authorization code flow.
+
+Visit the printed URL and authorize the app. After the authorization, the
+browser is redirected to the redirect URL of the app; paste the "code" query
+parameter of the redirected URL. The printed refresh token must be set by
+--tempo-oauth-refresh-token.
+
+Tempo rotates the refresh tokens on every refresh, hence the latest refresh
+token is kept in the storage. If the storage is lost, authorize the app again.`,
+	Run: runAuthorizeTempoCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(authorizeTempoCmd)
+}
+
+// getTempoOAuthOpts returns the OAuth app of Tempo Cloud, without the
+// rotation of the refresh tokens.
+func getTempoOAuthOpts() *client.OAuthOpts {
+	return &client.OAuthOpts{
+		TokenURL:     viper.GetString("tempo-oauth-token-url"),
+		ClientID:     viper.GetString("tempo-oauth-client-id"),
+		ClientSecret: viper.GetString("tempo-oauth-client-secret"),
+		RedirectURL:  viper.GetString("tempo-oauth-redirect-url"),
+		Timeout:      client.DefaultRequestTimeout,
+	}
+}
+
+// getTempoOAuth returns the OAuth app of Tempo Cloud and the latest refresh
+// token. The rotated refresh tokens are persisted in the storage. If the OAuth
+// app is not set, nil returns and the basic auth is used.
+func getTempoOAuth() (*client.OAuthOpts, string, error) {
+	if viper.GetString("tempo-oauth-client-id") == "" {
+		return nil, "", nil
+	}
+
+	store, err := getStore()
+	if err != nil {
+		return nil, "", err
+	}
+
+	configured := viper.GetString("tempo-oauth-refresh-token")
+
+	refreshToken, err := loadRefreshToken(context.Background(), store, tempoOAuthKey, configured)
+	if err != nil {
+		return nil, "", err
+	}
+
+	oauthOpts := getTempoOAuthOpts()
+	oauthOpts.RotateRefreshToken = func(ctx context.Context, refreshToken string) error {
+		return saveRefreshToken(ctx, store, tempoOAuthKey, configured, refreshToken)
+	}
+
+	return oauthOpts, refreshToken, nil
+}
+
+func runAuthorizeTempoCmd(_ *cobra.Command, _ []string) {
+	oauthOpts := getTempoOAuthOpts()
+
+	if oauthOpts.ClientID == "" {
+		cobra.CheckErr("tempo oauth client id must be set")
+	}
+
+	if oauthOpts.RedirectURL == "" {
+		cobra.CheckErr("tempo oauth redirect url must be set")
+	}
+
+	jiraURL := getJiraOption("url")
+	if jiraURL == "" {
+		cobra.CheckErr("jira url must be set to the Jira site of Tempo Cloud")
+	}
+
+	authorizationURL, err := tempo.AuthorizationURL(jiraURL, oauthOpts.ClientID, oauthOpts.RedirectURL)
+	cobra.CheckErr(err)
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	fmt.Printf("Visit %s and authorize the app\n", authorizationURL)
+	code := utils.Prompt("Paste the code of the redirected URL: ")
+	if code == "" {
+		cobra.CheckErr("no authorization code was entered")
+	}
+
+	ctx := context.Background()
+
+	token, err := client.ExchangeAuthorizationCode(ctx, &client.HTTPClient{}, oauthOpts, code)
+	cobra.CheckErr(err)
+
+	if token.RefreshToken == "" {
+		cobra.CheckErr("no refresh token was issued")
+	}
+
+	// The configured token is the issued one, which is not rotated yet
+	cobra.CheckErr(saveRefreshToken(ctx, store, tempoOAuthKey, token.RefreshToken, token.RefreshToken))
+
+	fmt.Println()
+	fmt.Println("Authorization granted. Set the refresh token in the config:")
+	fmt.Println()
+	fmt.Printf("tempo-oauth-refresh-token = %q\n", token.RefreshToken)
+}
@@ -0,0 +1,141 @@
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <task> [note]",
+	Short: "Start a timer",
+	Long: `
+Start a local timer of the task, so minutes can serve as a minimal tracker. The
+timer is persisted in the configured storage, so it keeps running between the
+runs. When the timer is stopped by "minutes stop", the elapsed time is staged
+as an entry and uploaded by the next sync, like the entries added by
+"minutes add".
+
+Example:
+
+  minutes start CPT-2014 fixing the flaky test`,
+	Args:   cobra.MinimumNArgs(1),
+	PreRun: bindCmdFlags,
+	Run:    runStartCmd,
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Long: `
+Stop the timer started by "minutes start" and stage the elapsed time as an
+entry, uploaded by the next sync.`,
+	Args:   cobra.NoArgs,
+	PreRun: bindCmdFlags,
+	Run:    runStopCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
+
+	startCmd.Flags().StringP("timer-project", "", "", "set the project of the timed entry")
+	startCmd.Flags().StringP("timer-client", "", "", "set the client of the timed entry")
+}
+
+// loadTimer returns the running timer persisted in the store. If no timer is
+// running, nil returns.
+func loadTimer(ctx context.Context, store storage.Store) (*staging.Timer, error) {
+	data, err := store.Get(ctx, timerKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	timer := &staging.Timer{}
+	if err = json.Unmarshal(data, timer); err != nil {
+		return nil, err
+	}
+
+	return timer, nil
+}
+
+// saveTimer persists the running timer in the store.
+func saveTimer(ctx context.Context, store storage.Store, timer *staging.Timer) error {
+	data, err := json.Marshal(timer)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(ctx, timerKey, data)
+}
+
+func runStartCmd(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	runningTimer, err := loadTimer(ctx, store)
+	cobra.CheckErr(err)
+
+	reportLocale := getLocale()
+
+	if runningTimer != nil {
+		cobra.CheckErr(tr(
+			"the timer of %s is already running since %s, stop it first",
+			runningTimer.Task,
+			reportLocale.FormatDateTime(runningTimer.Start.Local()),
+		))
+	}
+
+	timer := &staging.Timer{
+		Task:    args[0],
+		Note:    strings.Join(args[1:], " "),
+		Project: viper.GetString("timer-project"),
+		Client:  viper.GetString("timer-client"),
+		Start:   time.Now(),
+	}
+
+	cobra.CheckErr(saveTimer(ctx, store, timer))
+
+	fmt.Print(tr("Started the timer of %s at %s.\n", timer.Task, reportLocale.FormatDateTime(timer.Start.Local())))
+}
+
+func runStopCmd(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+
+	store, err := getStore()
+	cobra.CheckErr(err)
+
+	timer, err := loadTimer(ctx, store)
+	cobra.CheckErr(err)
+
+	if timer == nil {
+		cobra.CheckErr(tr("no timer is running"))
+	}
+
+	entry, err := timer.Entry(time.Now())
+	cobra.CheckErr(err)
+
+	// The entry is staged before removing the timer, so the elapsed time is
+	// not lost if staging fails
+	cobra.CheckErr(staging.NewStage(store, stagedEntriesKey).Add(ctx, *entry))
+	cobra.CheckErr(store.Delete(ctx, timerKey))
+
+	fmt.Print(tr(
+		"Stopped the timer of %s after %s.\nStaged entry #%s, uploaded by the next sync.\n",
+		entry.Task.Name,
+		entry.BillableDuration,
+		entry.Provenance.SourceIDs[0],
+	))
+}
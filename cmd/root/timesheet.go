@@ -0,0 +1,107 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/timesheet"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Render the entries of a week or month as a PDF timesheet",
+	Long: `
+Fetch the entries of the week or month containing the start date from the
+source, and render them as a PDF timesheet with the total hours and signature
+lines for the employee and the client.
+
+With the daily detail level, only the start, end and total of every day is
+listed, without the projects, tasks and summaries of the entries. It suits the
+approvals where the activities must not be shared, like works councils.
+
+The entries are fetched and mapped the same way as during the sync, therefore
+the timesheet matches the uploaded entries.`,
+	PreRun: bindCmdFlags,
+	Run:    runTimesheetCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+
+	timesheetCmd.Flags().StringP("timesheet-output", "", "timesheet.pdf", "set the path of the written PDF file")
+	timesheetCmd.Flags().StringP("timesheet-period", "", timesheet.PeriodWeek, fmt.Sprintf("set the period of the timesheet %v", timesheet.Periods))
+	timesheetCmd.Flags().StringP("timesheet-detail", "", timesheet.DetailEntries, fmt.Sprintf("set what is listed on the timesheet %v", timesheet.Details))
+	timesheetCmd.Flags().StringP("timesheet-title", "", "", fmt.Sprintf("set the title of the timesheet; if not set, %q in the language of the messages", timesheet.DefaultTitle))
+	timesheetCmd.Flags().StringP("timesheet-employee", "", "", "set the name of the employee")
+	timesheetCmd.Flags().StringP("timesheet-client", "", "", "set the name of the client")
+	timesheetCmd.Flags().StringP("timesheet-logo", "", "", "set the path of the JPEG or PNG logo")
+}
+
+func runTimesheetCmd(_ *cobra.Command, _ []string) {
+	validateSourceFlags()
+
+	if viper.GetString("timesheet-output") == "" {
+		cobra.CheckErr("timesheet output must be set")
+	}
+
+	period := viper.GetString("timesheet-period")
+	if !utils.IsSliceContains(period, timesheet.Periods) {
+		cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the supported timesheet periods %v\n", period, timesheet.Periods))
+	}
+
+	detail := viper.GetString("timesheet-detail")
+	if !utils.IsSliceContains(detail, timesheet.Details) {
+		cobra.CheckErr(fmt.Sprintf("\"%s\" is not part of the supported timesheet details %v\n", detail, timesheet.Details))
+	}
+
+	if viper.GetBool("pseudonymize") {
+		validateStorageFlags()
+	}
+
+	reportLocale := getLocale()
+
+	date, _ := getTimeRange()
+	start, end, err := timesheet.PeriodRange(period, date, reportLocale)
+	cobra.CheckErr(err)
+
+	entries, err := fetchEntries(context.Background(), start, end)
+	cobra.CheckErr(err)
+
+	wl := newWorklog(entries)
+
+	reportEntries, err := pseudonymizeReportEntries(context.Background(), append(wl.CompleteEntries(), wl.IncompleteEntries()...))
+	cobra.CheckErr(err)
+
+	var logo io.Reader
+	if logoPath := viper.GetString("timesheet-logo"); logoPath != "" {
+		logoFile, err := os.Open(logoPath)
+		cobra.CheckErr(err)
+		defer logoFile.Close()
+
+		logo = logoFile
+	}
+
+	output, err := os.Create(viper.GetString("timesheet-output"))
+	cobra.CheckErr(err)
+	defer output.Close()
+
+	err = timesheet.RenderPDF(output, reportEntries, &timesheet.PDFOpts{
+		Title:      viper.GetString("timesheet-title"),
+		Employee:   viper.GetString("timesheet-employee"),
+		Client:     viper.GetString("timesheet-client"),
+		Logo:       logo,
+		Detail:     detail,
+		Start:      start,
+		End:        end,
+		Locale:     reportLocale,
+		Translator: getTranslator(),
+	})
+	cobra.CheckErr(err)
+
+	fmt.Print(tr("Timesheet written to %s\n", viper.GetString("timesheet-output")))
+}
@@ -2,9 +2,14 @@ package root
 
 import (
 	"errors"
+	"text/template"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/icsfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jiraworklog"
 	"github.com/gabor-boros/minutes/internal/pkg/client/tempo"
+	"github.com/gabor-boros/minutes/internal/pkg/client/xlsxfile"
 	"github.com/spf13/viper"
 )
 
@@ -12,9 +17,113 @@ var (
 	ErrNoTargetImplementation = errors.New("no target implementation found")
 )
 
+// getUploader returns the uploader of the target. If route targets are set,
+// the entries tagged by the route tag prefix are uploaded to the route targets.
 func getUploader() (client.Uploader, error) {
-	switch viper.GetString("target") {
+	target := viper.GetString("target")
+
+	uploader, err := getTargetUploader(target)
+	if err != nil {
+		return nil, err
+	}
+
+	routeTargets := viper.GetStringSlice("route-targets")
+	if len(routeTargets) == 0 {
+		return uploader, nil
+	}
+
+	router := &client.Router{
+		DefaultTarget: target,
+		Default:       uploader,
+		Routes:        map[string]client.Uploader{},
+		TagPrefix:     viper.GetString("route-tag-prefix"),
+	}
+
+	for _, routeTarget := range routeTargets {
+		if router.Routes[routeTarget], err = getTargetUploader(routeTarget); err != nil {
+			return nil, err
+		}
+	}
+
+	return router, nil
+}
+
+func getTargetUploader(target string) (client.Uploader, error) {
+	switch target {
+	case "csvfile":
+		columns, err := csvfile.ParseColumns(viper.GetStringSlice("csvfile-columns"))
+		if err != nil {
+			return nil, err
+		}
+
+		delimiter, err := csvfile.ParseDelimiter(viper.GetString("csvfile-delimiter"))
+		if err != nil {
+			return nil, err
+		}
+
+		costCenters, err := getCostCenters()
+		if err != nil {
+			return nil, err
+		}
+
+		return csvfile.NewUploader(&csvfile.ClientOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			Path:              viper.GetString("csvfile-path"),
+			Columns:           columns,
+			OmitHeader:        viper.GetBool("csvfile-omit-header"),
+			Delimiter:         delimiter,
+			DurationFormat:    viper.GetString("csvfile-duration-format"),
+			DecimalPrecision:  viper.GetInt("csvfile-decimal-precision"),
+			Locale:            getLocale(),
+			DateFormat:        viper.GetString("csvfile-date-format"),
+			DateTimeFormat:    viper.GetString("csvfile-datetime-format"),
+			CostCenters:       costCenters,
+			SplitByCostCenter: viper.GetBool("csvfile-split-by-cost-center"),
+		})
+	case "icsfile":
+		return icsfile.NewUploader(&icsfile.ClientOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			Path: viper.GetString("icsfile-path"),
+		})
+	case "jira":
+		return jiraworklog.NewUploader(&jiraworklog.ClientOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			BasicAuth: client.BasicAuth{
+				Username: getJiraOption("username"),
+				Password: getJiraOption("password"),
+			},
+			BaseURL: getJiraOption("url"),
+		})
 	case "tempo":
+		var teamRoles []tempo.TeamRole
+		if err := viper.UnmarshalKey("tempo-team-roles", &teamRoles); err != nil {
+			return nil, err
+		}
+
+		var attributeValues []tempo.AttributeValues
+		if err := viper.UnmarshalKey("tempo-attribute-values", &attributeValues); err != nil {
+			return nil, err
+		}
+
+		var issueCommentTemplate *template.Template
+		if rawTemplate := viper.GetString("tempo-issue-comment-template"); rawTemplate != "" {
+			var err error
+			if issueCommentTemplate, err = client.NewCommentTemplate(rawTemplate); err != nil {
+				return nil, err
+			}
+		}
+
+		oauthOpts, refreshToken, err := getTempoOAuth()
+		if err != nil {
+			return nil, err
+		}
+
 		return tempo.NewUploader(&tempo.ClientOpts{
 			BaseClientOpts: client.BaseClientOpts{
 				Timeout: client.DefaultRequestTimeout,
@@ -23,7 +132,33 @@ func getUploader() (client.Uploader, error) {
 				Username: viper.GetString("tempo-username"),
 				Password: viper.GetString("tempo-password"),
 			},
-			BaseURL: viper.GetString("tempo-url"),
+			BaseURL:                 viper.GetString("tempo-url"),
+			TeamRoles:               teamRoles,
+			TeamAttribute:           viper.GetString("tempo-team-attribute"),
+			RoleAttribute:           viper.GetString("tempo-role-attribute"),
+			ClassificationAttribute: viper.GetString("tempo-classification-attribute"),
+			MaxCommentLength:        viper.GetInt("tempo-max-comment-length"),
+			AttributeValues:         attributeValues,
+			IssueCommentTemplate:    issueCommentTemplate,
+			IssueCommentMinDuration: viper.GetDuration("tempo-issue-comment-min-duration"),
+			OAuth:                   oauthOpts,
+			RefreshToken:            refreshToken,
+			Impersonate:             viper.GetBool("impersonate"),
+			ImpersonatedUsers:       viper.GetStringSlice("tempo-impersonate-users"),
+		})
+	case "xlsxfile":
+		overtimeOpts := getOvertimeOpts()
+
+		return xlsxfile.NewUploader(&xlsxfile.ClientOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			Path:           viper.GetString("xlsxfile-path"),
+			DailyTarget:    overtimeOpts.DailyDuration,
+			WorkingDays:    overtimeOpts.WorkingDays,
+			WeekdayTargets: overtimeOpts.WeekdayDurations,
+			Holidays:       overtimeOpts.Holidays,
+			Locale:         getLocale(),
 		})
 	default:
 		return nil, ErrNoTargetImplementation
@@ -0,0 +1,88 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// checkVerifySample stops the run if the uploaded worklogs should be verified,
+// but the target cannot fetch them, so the missing verification is noticed
+// before the upload.
+func checkVerifySample(uploader client.Uploader) {
+	if viper.GetInt("verify-sample") <= 0 {
+		return
+	}
+
+	if _, ok := uploader.(client.UploadVerifier); !ok {
+		cobra.CheckErr(tr("target does not support verifying the uploaded worklogs"))
+	}
+}
+
+// newVerifyCollector returns the collector of the created resources if the
+// uploaded worklogs should be verified, recording the mutations by the
+// recorder of the upload options too. Otherwise, nil returns.
+func newVerifyCollector(opts *client.UploadOpts) *client.MutationCollector {
+	if viper.GetInt("verify-sample") <= 0 {
+		return nil
+	}
+
+	collector := &client.MutationCollector{Next: opts.MutationRecorder}
+	opts.MutationRecorder = collector
+
+	return collector
+}
+
+// verifyUploads fetches a random sample of the uploaded worklogs from the
+// target and compares them with the sent ones. The differences are printed,
+// and the number of sampled worklogs not matching the sent ones returns.
+func verifyUploads(ctx context.Context, uploader client.Uploader, collector *client.MutationCollector, opts *client.UploadOpts) (int, error) {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	verifications, err := client.VerifySample(ctx, uploader, collector.Mutations(), viper.GetInt("verify-sample"), random, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	writer := newTableWriter()
+	writer.SetOutputMirror(os.Stdout)
+	writer.SetStyle(getTableStyle())
+	writer.SetTitle(tr("Upload verification"))
+	writer.AppendHeader(table.Row{tr("Task"), tr("Worklog"), tr("Field"), tr("Sent"), tr("Received")})
+
+	failed := 0
+	for _, verification := range verifications {
+		if verification.IsVerified() {
+			continue
+		}
+
+		failed++
+		task := verification.Mutation.Entry.Task.Name
+		worklogID := verification.Mutation.ResourceID
+
+		if verification.Err != nil {
+			writer.AppendRow(table.Row{task, worklogID, "-", "-", verification.Err})
+			continue
+		}
+
+		for _, mismatch := range verification.Mismatches {
+			writer.AppendRow(table.Row{task, worklogID, mismatch.Field, mismatch.Sent, mismatch.Received})
+		}
+	}
+
+	if failed != 0 {
+		fmt.Println()
+		writer.Render()
+	}
+
+	fmt.Print(tr("\nVerified %d of %d sampled worklogs against the target.\n", len(verifications)-failed, len(verifications)))
+
+	return failed, nil
+}
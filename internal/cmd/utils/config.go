@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// credentialKeyParts lists the parts of the config keys holding credentials or
+// configuring the authentication, like "tempo-password" or "toggl-api-key".
+var credentialKeyParts = []string{"password", "username", "secret", "token", "key", "oauth"}
+
+// IsCredentialKey returns true if the config key holds a credential or
+// configures the authentication.
+func IsCredentialKey(key string) bool {
+	if strings.HasSuffix(key, "client-id") {
+		return true
+	}
+
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '-' || r == '.' }) {
+		if IsSliceContains(part, credentialKeyParts) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangedConfigKeys returns the keys set differently by the two configs,
+// including the keys set by only one of them, in alphabetical order.
+func ChangedConfigKeys(previous *viper.Viper, current *viper.Viper) []string {
+	keys := map[string]bool{}
+	for _, key := range previous.AllKeys() {
+		keys[key] = true
+	}
+
+	for _, key := range current.AllKeys() {
+		keys[key] = true
+	}
+
+	var changed []string
+	for key := range keys {
+		if !reflect.DeepEqual(previous.Get(key), current.Get(key)) {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// SetConfigFile sets the config file read by v. If the config file is given,
+// like by the --config flag, the file is read from the given path; otherwise,
+// the TOML config named name is searched in the paths. Config files without
+// extension are read as TOML.
+func SetConfigFile(v *viper.Viper, configFile string, name string, paths ...string) {
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+
+		if filepath.Ext(configFile) == "" {
+			v.SetConfigType("toml")
+		}
+
+		return
+	}
+
+	for _, path := range paths {
+		v.AddConfigPath(path)
+	}
+
+	v.SetConfigName(name)
+	v.SetConfigType("toml")
+}
+
+// ConfigFileArgs returns the CLI arguments making a command read the given
+// config file regardless of its working directory, like the command run by a
+// background service. If no config file is given, no arguments return.
+func ConfigFileArgs(configFile string) ([]string, error) {
+	if configFile == "" {
+		return nil, nil
+	}
+
+	absConfigFile, err := filepath.Abs(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{"--config", absConfigFile}, nil
+}
+
+// WatchFile calls the notify function when the file is written or created,
+// until the context is canceled. The directory of the file is watched, so the
+// file is followed even if editors replace it when saving. The notify function
+// may be called multiple times for a single change.
+func WatchFile(ctx context.Context, path string, notify func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	path = filepath.Clean(path)
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					notify()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package utils_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func readConfig(t *testing.T, content string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("toml")
+	require.Nil(t, v.ReadConfig(strings.NewReader(content)))
+
+	return v
+}
+
+func TestIsCredentialKey(t *testing.T) {
+	for _, key := range []string{"tempo-password", "jira-username", "toggl-api-key", "webhook-github-secret", "outlook-refresh-token", "tempo-oauth-redirect-url", "personio-client-id"} {
+		require.True(t, utils.IsCredentialKey(key), key)
+	}
+
+	for _, key := range []string{"filter-client", "mapping-file", "round-increment", "table-column-config.task.hidden", "source-user"} {
+		require.False(t, utils.IsCredentialKey(key), key)
+	}
+}
+
+func TestChangedConfigKeys(t *testing.T) {
+	previous := readConfig(t, `
+filter-client = "ACME"
+round-increment = "15m"
+tempo-password = "secret"
+
+[[mappings]]
+summary = "standup"
+task = "CPT-1"
+`)
+
+	current := readConfig(t, `
+filter-client = "ACME"
+round-increment = "30m"
+filter-project = "Internal"
+
+[[mappings]]
+summary = "standup"
+task = "CPT-2"
+`)
+
+	require.Equal(t, []string{"filter-project", "mappings", "round-increment", "tempo-password"}, utils.ChangedConfigKeys(previous, current))
+	require.Empty(t, utils.ChangedConfigKeys(previous, previous))
+}
+
+func TestSetConfigFile_Search(t *testing.T) {
+	emptyDir := t.TempDir()
+	configDir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(configDir, ".minutes.toml"), []byte(`filter-client = "ACME"`), 0600))
+
+	v := viper.New()
+	utils.SetConfigFile(v, "", ".minutes", emptyDir, configDir)
+
+	require.Nil(t, v.ReadInConfig())
+	require.Equal(t, "ACME", v.GetString("filter-client"))
+}
+
+func TestSetConfigFile_Missing(t *testing.T) {
+	v := viper.New()
+	utils.SetConfigFile(v, filepath.Join(t.TempDir(), "missing.toml"), ".minutes")
+
+	// Missing config files set explicitly are not ignored like the missing
+	// config files searched
+	err := v.ReadInConfig()
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestConfigFileArgs(t *testing.T) {
+	configDir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(configDir, "minutes"), []byte(`filter-client = "ACME"`), 0600))
+
+	workDir, err := os.Getwd()
+	require.Nil(t, err)
+	defer func() {
+		require.Nil(t, os.Chdir(workDir))
+	}()
+
+	// The arguments are generated relative to the directory of the config
+	// file, while the command runs from another directory, like a service
+	require.Nil(t, os.Chdir(configDir))
+	args, err := utils.ConfigFileArgs("minutes")
+	require.Nil(t, err)
+	require.Nil(t, os.Chdir(t.TempDir()))
+
+	flags := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	configFile := flags.String("config", "", "config file")
+	require.Nil(t, flags.Parse(args))
+
+	v := viper.New()
+	utils.SetConfigFile(v, *configFile, ".minutes", t.TempDir())
+
+	require.Nil(t, v.ReadInConfig())
+	require.Equal(t, "ACME", v.GetString("filter-client"))
+}
+
+func TestConfigFileArgs_NoConfigFile(t *testing.T) {
+	args, err := utils.ConfigFileArgs("")
+
+	require.Nil(t, err)
+	require.Empty(t, args)
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".minutes.toml")
+	require.Nil(t, os.WriteFile(path, []byte(`filter-client = "ACME"`), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan bool, 10)
+	require.Nil(t, utils.WatchFile(ctx, path, func() { changes <- true }))
+
+	// Other files of the directory are not reported
+	require.Nil(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.toml"), []byte(""), 0600))
+	require.Nil(t, os.WriteFile(path, []byte(`filter-client = "Globex"`), 0600))
+
+	select {
+	case <-changes:
+	case <-time.After(time.Second * 5):
+		require.Fail(t, "the change of the file is not reported")
+	}
+}
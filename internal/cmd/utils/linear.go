@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// linearTableWriter is a table.Writer that renders the table as linear text,
+// a line per row, instead of drawing the table. Every cell is prefixed by the
+// name of its column, so the rows can be understood without seeing the
+// header, which makes the output suitable for screen readers and log files.
+type linearTableWriter struct {
+	table.Writer
+	output        io.Writer
+	title         string
+	caption       string
+	header        table.Row
+	rows          []table.Row
+	footers       []table.Row
+	columnConfigs []table.ColumnConfig
+}
+
+func (w *linearTableWriter) AppendHeader(row table.Row, _ ...table.RowConfig) {
+	w.header = row
+}
+
+func (w *linearTableWriter) AppendRow(row table.Row, _ ...table.RowConfig) {
+	w.rows = append(w.rows, row)
+}
+
+func (w *linearTableWriter) AppendRows(rows []table.Row, _ ...table.RowConfig) {
+	w.rows = append(w.rows, rows...)
+}
+
+func (w *linearTableWriter) AppendFooter(row table.Row, _ ...table.RowConfig) {
+	w.footers = append(w.footers, row)
+}
+
+func (w *linearTableWriter) SetTitle(format string, a ...interface{}) {
+	w.title = fmt.Sprintf(format, a...)
+}
+
+func (w *linearTableWriter) SetCaption(format string, a ...interface{}) {
+	w.caption = fmt.Sprintf(format, a...)
+}
+
+func (w *linearTableWriter) SetColumnConfigs(configs []table.ColumnConfig) {
+	w.columnConfigs = configs
+}
+
+func (w *linearTableWriter) SetOutputMirror(mirror io.Writer) {
+	w.output = mirror
+}
+
+// isHidden returns true if the column at the index is hidden by the column
+// configs, either by its name or number.
+func (w *linearTableWriter) isHidden(columnIndex int) bool {
+	for _, config := range w.columnConfigs {
+		isColumn := config.Number == columnIndex+1
+		if config.Name != "" && columnIndex < len(w.header) {
+			isColumn = isColumn || config.Name == fmt.Sprint(w.header[columnIndex])
+		}
+
+		if isColumn && config.Hidden {
+			return true
+		}
+	}
+
+	return false
+}
+
+// columnName returns the name of the column at the index, or its number if the
+// table has no header.
+func (w *linearTableWriter) columnName(columnIndex int) string {
+	if columnIndex < len(w.header) {
+		return fmt.Sprint(w.header[columnIndex])
+	}
+
+	return fmt.Sprintf("column %d", columnIndex+1)
+}
+
+// renderCells returns the non-empty, visible cells of the row, prefixed by the
+// name of their column and separated by commas.
+func (w *linearTableWriter) renderCells(row table.Row, skip int) string {
+	var cells []string
+
+	for i := skip; i < len(row); i++ {
+		value := strings.TrimSpace(fmt.Sprint(row[i]))
+		if value == "" || w.isHidden(i) {
+			continue
+		}
+
+		cells = append(cells, w.columnName(i)+" "+value)
+	}
+
+	return strings.Join(cells, ", ")
+}
+
+func (w *linearTableWriter) Render() string {
+	var lines []string
+
+	if w.title != "" {
+		lines = append(lines, w.title+":")
+	}
+
+	if len(w.rows) == 0 {
+		lines = append(lines, "No rows.")
+	}
+
+	for i, row := range w.rows {
+		lines = append(lines, fmt.Sprintf("Row %d of %d: %s.", i+1, len(w.rows), w.renderCells(row, 0)))
+	}
+
+	// The first non-empty cell of the footer is its label, like "Total"
+	for _, footer := range w.footers {
+		for i, cell := range footer {
+			if label := strings.TrimSpace(fmt.Sprint(cell)); label != "" {
+				lines = append(lines, fmt.Sprintf("%s: %s.", label, w.renderCells(footer, i+1)))
+				break
+			}
+		}
+	}
+
+	if caption := strings.TrimSpace(w.caption); caption != "" {
+		lines = append(lines, caption)
+	}
+
+	rendered := strings.Join(lines, "\n")
+
+	if w.output != nil {
+		_, _ = io.WriteString(w.output, rendered+"\n")
+	}
+
+	return rendered
+}
+
+// NewLinearTableWriter returns a table.Writer that renders the rows as linear
+// text instead of a table. The styling of the table is ignored.
+func NewLinearTableWriter() table.Writer {
+	return &linearTableWriter{Writer: table.NewWriter()}
+}
@@ -0,0 +1,41 @@
+package utils_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearTableWriter_Render(t *testing.T) {
+	output := new(bytes.Buffer)
+
+	writer := utils.NewLinearTableWriter()
+	writer.SetOutputMirror(output)
+	writer.SetTitle("Overtime balance")
+	writer.AppendHeader(table.Row{"Date", "Expected", "Actual", "Note"})
+	writer.SetColumnConfigs([]table.ColumnConfig{{Name: "Note", Hidden: true}})
+	writer.AppendRow(table.Row{"2021-10-01", "8h0m0s", "7h0m0s", "hidden"})
+	writer.AppendRow(table.Row{"2021-10-02", "", "1h0m0s", "hidden"})
+	writer.AppendFooter(table.Row{"", "Total", "8h0m0s", ""})
+	writer.SetCaption("Balance: %s", "-1h0m0s")
+
+	rendered := writer.Render()
+
+	require.Equal(t, `Overtime balance:
+Row 1 of 2: Date 2021-10-01, Expected 8h0m0s, Actual 7h0m0s.
+Row 2 of 2: Date 2021-10-02, Actual 1h0m0s.
+Total: Actual 8h0m0s.
+Balance: -1h0m0s`, rendered)
+	require.Equal(t, rendered+"\n", output.String())
+}
+
+func TestLinearTableWriter_Render_NoRows(t *testing.T) {
+	writer := utils.NewLinearTableWriter()
+	writer.SetTitle("Remote validation problems")
+	writer.AppendHeader(table.Row{"Kind", "Value"})
+
+	require.Equal(t, "Remote validation problems:\nNo rows.", writer.Render())
+}
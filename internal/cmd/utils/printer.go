@@ -3,11 +3,14 @@ package utils
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -15,7 +18,6 @@ import (
 )
 
 const (
-	rowDateFormat    string = "2006-01-02 15:04:05"
 	ColumnTask       string = "task"
 	ColumnSummary    string = "summary"
 	ColumnProject    string = "project"
@@ -24,6 +26,7 @@ const (
 	ColumnEnd        string = "end"
 	ColumnBillable   string = "billable"
 	ColumnUnbillable string = "unbillable"
+	ColumnAttributes string = "attributes"
 )
 
 // Columns lists all available columns that can be printed.
@@ -36,6 +39,7 @@ var Columns = []string{
 	ColumnEnd,
 	ColumnBillable,
 	ColumnUnbillable,
+	ColumnAttributes,
 }
 
 // HideableColumns lists all columns that can be hidden when printing.
@@ -45,6 +49,7 @@ var HideableColumns = []string{
 	ColumnClient,
 	ColumnStart,
 	ColumnEnd,
+	ColumnAttributes,
 }
 
 // TableColumnConfig represents the configuration of a column.
@@ -78,6 +83,12 @@ type BasePrinterOpts struct {
 	SortBy []string
 	// HiddenColumns lists the columns that will be hidden during printing.
 	HiddenColumns []string
+	// Locale sets the format of the printed dates. If not set, the default
+	// locale is used.
+	Locale *locale.Locale
+	// Translator translates the header, footer and caption. If not set, they
+	// are printed in the default language.
+	Translator *i18n.Translator
 }
 
 // TablePrinterOpts represents the configuration for a table base printer.
@@ -88,11 +99,34 @@ type TablePrinterOpts struct {
 	Style           table.Style
 	ColumnConfig    []table.ColumnConfig
 	ColumnTruncates map[string]int
+	// MaxWidth is the width of the terminal. If the table would be wider, the
+	// text columns are truncated to fit. Zero means unlimited width.
+	MaxWidth int
+	// Linear prints a line per entry instead of drawing a table, which is
+	// easier to follow using screen readers. The style and max width are not
+	// used for linear output.
+	Linear bool
+}
+
+// ShrinkableColumns lists the columns in the order they are truncated when
+// the table does not fit the terminal.
+var ShrinkableColumns = []string{
+	ColumnSummary,
+	ColumnAttributes,
+	ColumnProject,
+	ColumnClient,
+	ColumnTask,
 }
 
 type tablePrinter struct {
-	writer      table.Writer
-	truncateMap map[string]int
+	writer        table.Writer
+	output        io.Writer
+	maxWidth      int
+	columnConfigs []table.ColumnConfig
+	truncateMap   map[string]int
+	sortBy        []string
+	locale        *locale.Locale
+	translator    *i18n.Translator
 }
 
 func (p *tablePrinter) convertEntryToRow(entry *worklog.Entry) table.Row {
@@ -104,52 +138,259 @@ func (p *tablePrinter) convertEntryToRow(entry *worklog.Entry) table.Row {
 		Truncate(entry.Summary, p.truncateMap[ColumnSummary]),
 		Truncate(entry.Project.Name, p.truncateMap[ColumnProject]),
 		Truncate(entry.Client.Name, p.truncateMap[ColumnClient]),
-		entryStart.Format(rowDateFormat),
-		entryStart.Add(timeSpent).Format(rowDateFormat),
+		entryStart,
+		entryStart.Add(timeSpent),
 		entry.BillableDuration,
 		entry.UnbillableDuration,
+		Truncate(formatAttributes(entry.Attributes), p.truncateMap[ColumnAttributes]),
 	}
 }
 
-func (p *tablePrinter) generateRows(entries worklog.Entries, billable *time.Duration, unbillable *time.Duration) {
+// formatAttributes returns the attributes as a comma separated list of
+// "name=value" pairs, sorted by name.
+func formatAttributes(attributes map[string]string) string {
+	var pairs []string
+	for name, value := range attributes {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+func (p *tablePrinter) generateRows(entries worklog.Entries, billable *time.Duration, unbillable *time.Duration) []table.Row {
+	var rows []table.Row
+
 	for i := range entries {
 		entry := entries[i]
 		*billable += entry.BillableDuration
 		*unbillable += entry.UnbillableDuration
-		p.writer.AppendRow(p.convertEntryToRow(&entry))
+		rows = append(rows, p.convertEntryToRow(&entry))
+	}
+
+	return rows
+}
+
+// compareCells returns a negative number if a is less than b, a positive
+// number if a is greater than b and zero if they are equal.
+func compareCells(a interface{}, b interface{}) int {
+	switch aValue := a.(type) {
+	case time.Time:
+		return aValue.Compare(b.(time.Time))
+	case time.Duration:
+		bValue := b.(time.Duration)
+		if aValue < bValue {
+			return -1
+		} else if aValue > bValue {
+			return 1
+		}
+
+		return 0
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
 	}
 }
 
+// sortRows sorts the rows by the sort columns. The rows are sorted by their
+// values and not by the printed text, so dates are sorted correctly
+// regardless of the locale.
+func (p *tablePrinter) sortRows(rows []table.Row) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, sortBy := range p.sortBy {
+			column := strings.TrimPrefix(sortBy, "-")
+
+			for columnIndex, name := range Columns {
+				if name != column {
+					continue
+				}
+
+				cmp := compareCells(rows[i][columnIndex], rows[j][columnIndex])
+				if cmp == 0 {
+					break
+				}
+
+				if strings.HasPrefix(sortBy, "-") {
+					return cmp > 0
+				}
+
+				return cmp < 0
+			}
+		}
+
+		return false
+	})
+}
+
 func (p *tablePrinter) Print(completeEntries worklog.Entries, incompleteEntries worklog.Entries) error {
 	var totalBillable time.Duration
 	var totalUnbillable time.Duration
 
 	var header table.Row
 	for _, column := range Columns {
-		header = append(header, column)
+		header = append(header, p.translator.Sprintf(column))
 	}
 
 	p.writer.AppendHeader(header)
 
-	p.generateRows(incompleteEntries, &totalBillable, &totalUnbillable)
-	p.generateRows(completeEntries, &totalBillable, &totalUnbillable)
+	// The attributes column is hidden if none of the entries has attributes,
+	// as most sources are not setting attributes
+	hasAttributes := false
+	for _, entries := range []worklog.Entries{completeEntries, incompleteEntries} {
+		for _, entry := range entries {
+			hasAttributes = hasAttributes || len(entry.Attributes) > 0
+		}
+	}
+
+	columnConfigs := make([]table.ColumnConfig, len(p.columnConfigs))
+	copy(columnConfigs, p.columnConfigs)
+
+	if !hasAttributes {
+		isConfigured := false
+		for i := range columnConfigs {
+			if columnConfigs[i].Name == ColumnAttributes {
+				columnConfigs[i].Hidden = true
+				isConfigured = true
+			}
+		}
+
+		if !isConfigured {
+			columnConfigs = append(columnConfigs, table.ColumnConfig{Name: ColumnAttributes, Hidden: true})
+		}
+	}
+
+	// The columns are referred by their number, since the header is translated
+	for i := range columnConfigs {
+		if number := columnNumber(columnConfigs[i].Name); number > 0 {
+			columnConfigs[i].Number = number
+		}
+	}
+
+	p.writer.SetColumnConfigs(columnConfigs)
+
+	rows := p.generateRows(incompleteEntries, &totalBillable, &totalUnbillable)
+	rows = append(rows, p.generateRows(completeEntries, &totalBillable, &totalUnbillable)...)
+
+	p.sortRows(rows)
+
+	for _, row := range rows {
+		for i, cell := range row {
+			if date, ok := cell.(time.Time); ok {
+				row[i] = p.locale.FormatDateTime(date)
+			}
+		}
+
+		p.writer.AppendRow(row)
+	}
 
 	p.writer.AppendFooter(table.Row{
-		"", "", "", "", "", "total time spent", totalBillable.String(), totalUnbillable.String(),
+		"", "", "", "", "", p.translator.Sprintf("total time spent"), totalBillable.String(), totalUnbillable.String(), "",
 	})
-	p.writer.SetCaption(
+
+	if p.maxWidth > 0 {
+		p.fitColumns(columnConfigs, rows)
+	}
+
+	p.writer.SetCaption(p.translator.Sprintf(
 		"You have %d complete and %d incomplete items. Before proceeding, please double-check them.\n",
 		len(completeEntries),
 		len(incompleteEntries),
-	)
+	))
 	p.writer.Render()
 
 	return nil
 }
 
+// columnNumber returns the 1-based number of the column, or zero if the name
+// is not a column.
+func columnNumber(column string) int {
+	for i, name := range Columns {
+		if name == column {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// columnWidth returns the width of the widest cell of the column, including
+// the header.
+func (p *tablePrinter) columnWidth(column string, rows []table.Row) int {
+	columnIndex := columnNumber(column) - 1
+
+	width := text.RuneWidthWithoutEscSequences(p.translator.Sprintf(column))
+	for _, row := range rows {
+		if cellWidth := text.RuneWidthWithoutEscSequences(fmt.Sprint(row[columnIndex])); cellWidth > width {
+			width = cellWidth
+		}
+	}
+
+	return width
+}
+
+// fitColumns truncates the shrinkable columns, so the table fits into the max
+// width. The columns are not truncated below the min column width, hence the
+// table may remain wider than the max width on very narrow terminals.
+func (p *tablePrinter) fitColumns(columnConfigs []table.ColumnConfig, rows []table.Row) {
+	// Render the table without printing to measure its width
+	p.writer.SetOutputMirror(nil)
+	overflow := text.LongestLineLen(p.writer.Render()) - p.maxWidth
+	p.writer.SetOutputMirror(p.output)
+
+	for _, column := range ShrinkableColumns {
+		if overflow <= 0 {
+			break
+		}
+
+		config := table.ColumnConfig{Name: column, Number: columnNumber(column)}
+		configIndex := -1
+
+		for i := range columnConfigs {
+			if columnConfigs[i].Name == column {
+				config = columnConfigs[i]
+				configIndex = i
+			}
+		}
+
+		if config.Hidden {
+			continue
+		}
+
+		width := p.columnWidth(column, rows)
+		if config.WidthMax > 0 && config.WidthMax < width {
+			width = config.WidthMax
+		}
+
+		shrink := width - minColumnWidth
+		if shrink <= 0 {
+			continue
+		} else if shrink > overflow {
+			shrink = overflow
+		}
+
+		config.WidthMax = width - shrink
+		config.WidthMaxEnforcer = Truncate
+		overflow -= shrink
+
+		if configIndex >= 0 {
+			columnConfigs[configIndex] = config
+		} else {
+			columnConfigs = append(columnConfigs, config)
+		}
+	}
+
+	p.writer.SetColumnConfigs(columnConfigs)
+}
+
 // NewTablePrinter returns a new Printer that print tables to os.Stdout.
 func NewTablePrinter(opts *TablePrinterOpts) Printer {
 	writer := table.NewWriter()
+	maxWidth := opts.MaxWidth
+
+	if opts.Linear {
+		writer = NewLinearTableWriter()
+		maxWidth = 0
+	}
+
 	writer.SetOutputMirror(opts.Output)
 
 	writer.SetTitle(opts.Title)
@@ -157,27 +398,26 @@ func NewTablePrinter(opts *TablePrinterOpts) Printer {
 
 	writer.SetStyle(opts.Style)
 	writer.Style().Format.Footer = text.FormatLower
-	writer.SetColumnConfigs(opts.ColumnConfig)
-
-	var sortBy []table.SortBy
-	for _, column := range viper.GetStringSlice("table-sort-by") {
-		mode := table.Asc
 
-		if strings.HasPrefix(column, "-") {
-			mode = table.Dsc
-		}
-
-		sortBy = append(sortBy, table.SortBy{
-			Name: column,
-			Mode: mode,
-		})
+	printerLocale := opts.Locale
+	if printerLocale == nil {
+		printerLocale = locale.Default()
 	}
 
-	writer.SortBy(sortBy)
+	translator := opts.Translator
+	if translator == nil {
+		translator = i18n.Default()
+	}
 
 	return &tablePrinter{
-		writer:      writer,
-		truncateMap: opts.ColumnTruncates,
+		writer:        writer,
+		output:        opts.Output,
+		maxWidth:      maxWidth,
+		columnConfigs: opts.ColumnConfig,
+		truncateMap:   opts.ColumnTruncates,
+		sortBy:        opts.SortBy,
+		locale:        printerLocale,
+		translator:    translator,
 	}
 }
 
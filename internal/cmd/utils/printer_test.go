@@ -0,0 +1,179 @@
+package utils_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablePrinter_Print(t *testing.T) {
+	output := new(bytes.Buffer)
+
+	printerLocale, err := locale.Get("en-US")
+	require.Nil(t, err)
+
+	printer := utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{
+			Output: output,
+			SortBy: []string{"-" + utils.ColumnStart},
+			Locale: printerLocale,
+		},
+		Style: table.StyleDefault,
+	})
+
+	entries := worklog.Entries{
+		{
+			Summary:          "first",
+			Start:            time.Date(2020, 10, 1, 10, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+		},
+		{
+			Summary:          "second",
+			Start:            time.Date(2021, 9, 30, 10, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+		},
+	}
+
+	require.Nil(t, printer.Print(entries, worklog.Entries{}))
+
+	printed := output.String()
+	require.Contains(t, printed, "09/30/2021 10:00:00 AM")
+	require.Contains(t, printed, "10/01/2020 11:00:00 AM")
+
+	// Sorted by the start date descending, even though the printed dates
+	// would sort the other way around
+	require.Less(t, strings.Index(printed, "second"), strings.Index(printed, "first"))
+}
+
+func TestTablePrinter_Print_Attributes(t *testing.T) {
+	entry := worklog.Entry{
+		Summary:          "first",
+		Start:            time.Date(2021, 10, 1, 10, 0, 0, 0, time.Local),
+		BillableDuration: time.Hour,
+	}
+
+	output := new(bytes.Buffer)
+	printer := utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{Output: output},
+		Style:           table.StyleDefault,
+	})
+
+	require.Nil(t, printer.Print(worklog.Entries{entry}, worklog.Entries{}))
+	require.NotContains(t, output.String(), "ATTRIBUTES")
+
+	entry.SetAttribute("jsm.status", "Open")
+	entry.SetAttribute("jsm.request_type", "Get IT help")
+
+	output.Reset()
+	printer = utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{Output: output},
+		Style:           table.StyleDefault,
+	})
+
+	require.Nil(t, printer.Print(worklog.Entries{entry}, worklog.Entries{}))
+	require.Contains(t, output.String(), "ATTRIBUTES")
+	require.Contains(t, output.String(), "jsm.request_type=Get IT help, jsm.status=Open")
+}
+
+func TestTablePrinter_Print_MaxWidth(t *testing.T) {
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"},
+		Summary:          strings.Repeat("Fix the flaky test of the printer ", 4),
+		Project:          worklog.IDNameField{ID: "ml", Name: "機械学習プロジェクト"},
+		Start:            time.Date(2021, 10, 1, 10, 0, 0, 0, time.Local),
+		BillableDuration: time.Hour,
+	}
+
+	output := new(bytes.Buffer)
+	printer := utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{Output: output},
+		Style:           table.StyleDefault,
+		MaxWidth:        140,
+	})
+
+	require.Nil(t, printer.Print(worklog.Entries{entry}, worklog.Entries{}))
+
+	lines := strings.Split(output.String(), "\n")
+	require.Contains(t, output.String(), "Fix the flaky test")
+	require.Contains(t, output.String(), "...")
+	require.Contains(t, output.String(), "機械学習プロジェクト")
+
+	// The caption is not part of the table
+	for _, line := range lines[:len(lines)-2] {
+		require.LessOrEqual(t, text.RuneWidthWithoutEscSequences(line), 140)
+	}
+}
+
+func TestTablePrinter_Print_Linear(t *testing.T) {
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"},
+		Summary:          "Fix the flaky test",
+		Start:            time.Date(2021, 10, 1, 10, 0, 0, 0, time.Local),
+		BillableDuration: time.Hour,
+	}
+
+	output := new(bytes.Buffer)
+	printer := utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{
+			Output: output,
+			Title:  "Worklog entries",
+			Locale: locale.Default(),
+		},
+		Style:    table.StyleDefault,
+		MaxWidth: 20,
+		Linear:   true,
+	})
+
+	require.Nil(t, printer.Print(worklog.Entries{entry}, worklog.Entries{}))
+
+	printed := output.String()
+	require.Contains(t, printed, "Worklog entries:\n")
+	require.Contains(t, printed, "Row 1 of 1: task CPT-2014, summary Fix the flaky test, start 2021-10-01 10:00:00, end 2021-10-01 11:00:00, billable 1h0m0s, unbillable 0s.\n")
+	require.Contains(t, printed, "total time spent: billable 1h0m0s, unbillable 0s.\n")
+	require.NotContains(t, printed, "attributes")
+	require.NotContains(t, printed, "+--")
+}
+
+func TestTablePrinter_Print_Translated(t *testing.T) {
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"},
+		Summary:          "Fix the flaky test",
+		Project:          worklog.IDNameField{ID: "cpt", Name: "Company Project"},
+		Start:            time.Date(2021, 10, 1, 10, 0, 0, 0, time.Local),
+		BillableDuration: time.Hour,
+	}
+
+	translator, err := i18n.Get("de")
+	require.Nil(t, err)
+
+	output := new(bytes.Buffer)
+	printer := utils.NewTablePrinter(&utils.TablePrinterOpts{
+		BasePrinterOpts: utils.BasePrinterOpts{
+			Output:     output,
+			Translator: translator,
+		},
+		Style:        table.StyleDefault,
+		ColumnConfig: []table.ColumnConfig{{Name: utils.ColumnProject, Hidden: true}},
+	})
+
+	require.Nil(t, printer.Print(worklog.Entries{entry}, worklog.Entries{}))
+
+	printed := output.String()
+	require.Contains(t, printed, "AUFGABE")
+	require.Contains(t, printed, "gesamte erfasste zeit")
+	require.Contains(t, printed, "Sie haben 1 vollständige und 0 unvollständige Einträge.")
+	require.NotContains(t, printed, "TASK")
+
+	// The columns are still configured by their names
+	require.NotContains(t, printed, "PROJEKT")
+	require.NotContains(t, printed, "Company Project")
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const (
+	// minColumnWidth is the narrowest width a column is shrunk to, so at least
+	// the beginning of its text remains readable.
+	minColumnWidth int = 10
+	// maxProgressMessageWidth is the width of the progress messages on terminals
+	// wide enough to fit them.
+	maxProgressMessageWidth int = 50
+	// progressDecorationWidth is the width of the progress line without the
+	// message, including the separators, status and elapsed time.
+	progressDecorationWidth int = 24
+)
+
+// TerminalWidth returns the width of the terminal attached to the standard
+// output in columns. The `COLUMNS` environment variable takes precedence over
+// the detected width. If the width cannot be determined, for example the
+// output is redirected to a file, zero returns.
+func TerminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return terminalWidth(os.Stdout)
+}
+
+// IsDumbTerminal returns true if the terminal is not capable of interpreting
+// ANSI escape sequences, like the `dumb` terminal or the legacy Windows
+// console. These terminals cannot render colors and animated progress bars.
+func IsDumbTerminal() bool {
+	return os.Getenv("TERM") == "dumb" || !text.ANSICodesSupported
+}
+
+// ConfigureOutput configures the rendering of texts for the terminal. If
+// plain is set, or the `NO_COLOR` environment variable is present, the
+// colors are disabled.
+func ConfigureOutput(plain bool) {
+	// Characters of ambiguous width, like the box-drawing characters, are
+	// rendered narrow by the terminals, even with East Asian locales
+	text.OverrideRuneWidthEastAsianWidth(false)
+
+	if _, noColor := os.LookupEnv("NO_COLOR"); plain || noColor {
+		text.DisableColors()
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package utils
+
+import "os"
+
+func terminalWidth(_ *os.File) int {
+	return 0
+}
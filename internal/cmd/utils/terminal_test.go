@@ -0,0 +1,18 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+	require.Equal(t, 80, utils.TerminalWidth())
+}
+
+func TestIsDumbTerminal(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+	require.True(t, utils.IsDumbTerminal())
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func terminalWidth(file *os.File) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(file.Fd()), &info); err != nil {
+		return 0
+	}
+
+	// The buffer can be wider than the visible window, hence the window is used
+	return int(info.Window.Right-info.Window.Left) + 1
+}
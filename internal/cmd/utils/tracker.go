@@ -1,19 +1,169 @@
 package utils
 
 import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/jedib0t/go-pretty/v6/text"
 )
 
-// NewProgressWriter returns a pre-configured progress writer.
+// progressWriter is a progress.Writer that truncates the tracker messages
+// before appending them. The progress writer of go-pretty is trimming the
+// messages by runes, so messages containing wide characters would overflow
+// the message width.
+type progressWriter struct {
+	progress.Writer
+	messageWidth int
+}
+
+func (w *progressWriter) AppendTracker(tracker *progress.Tracker) {
+	tracker.Message = Truncate(tracker.Message, w.messageWidth)
+	w.Writer.AppendTracker(tracker)
+}
+
+func (w *progressWriter) AppendTrackers(trackers []*progress.Tracker) {
+	for _, tracker := range trackers {
+		w.AppendTracker(tracker)
+	}
+}
+
+// plainProgressWriter is a progress.Writer that prints a line per finished
+// tracker instead of redrawing the progress of all trackers. It is used on
+// terminals which cannot move the cursor.
+type plainProgressWriter struct {
+	*progressWriter
+	output          io.Writer
+	updateFrequency time.Duration
+	// isLinear prints the messages without padding them to the message width
+	isLinear bool
+
+	mutex            sync.Mutex
+	trackers         []*progress.Tracker
+	hasTrackers      bool
+	renderInProgress bool
+	done             chan bool
+}
+
+func (w *plainProgressWriter) AppendTracker(tracker *progress.Tracker) {
+	// The tracker is appended to the wrapped writer as well to start tracking
+	w.progressWriter.AppendTracker(tracker)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.trackers = append(w.trackers, tracker)
+	w.hasTrackers = true
+}
+
+func (w *plainProgressWriter) AppendTrackers(trackers []*progress.Tracker) {
+	for _, tracker := range trackers {
+		w.AppendTracker(tracker)
+	}
+}
+
+func (w *plainProgressWriter) IsRenderInProgress() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.renderInProgress
+}
+
+// renderTrackers prints the trackers finished since the last render. It
+// returns true if all trackers are finished.
+func (w *plainProgressWriter) renderTrackers() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var activeTrackers []*progress.Tracker
+	for _, tracker := range w.trackers {
+		status := w.Style().Options.DoneString
+		if tracker.IsErrored() {
+			status = w.Style().Options.ErrorString
+		} else if !tracker.IsDone() {
+			activeTrackers = append(activeTrackers, tracker)
+			continue
+		}
+
+		if w.isLinear {
+			_, _ = fmt.Fprintf(w.output, "%s: %s\n", tracker.Message, strings.TrimSpace(status))
+		} else {
+			_, _ = fmt.Fprintf(w.output, "%s  %s\n", text.Pad(tracker.Message, w.messageWidth, ' '), status)
+		}
+	}
+
+	w.trackers = activeTrackers
+	return w.hasTrackers && len(w.trackers) == 0
+}
+
+func (w *plainProgressWriter) Render() {
+	w.mutex.Lock()
+	if w.renderInProgress {
+		w.mutex.Unlock()
+		return
+	}
+	w.renderInProgress = true
+	w.mutex.Unlock()
+
+	defer func() {
+		w.mutex.Lock()
+		w.renderInProgress = false
+		w.mutex.Unlock()
+	}()
+
+	ticker := time.NewTicker(w.updateFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if w.renderTrackers() {
+				return
+			}
+		case <-w.done:
+			w.renderTrackers()
+			return
+		}
+	}
+}
+
+func (w *plainProgressWriter) Stop() {
+	if w.IsRenderInProgress() {
+		w.done <- true
+	}
+}
+
+// progressMessageWidth returns the width of the progress messages fitting
+// into the terminal.
+func progressMessageWidth() int {
+	terminalWidth := TerminalWidth()
+	if terminalWidth <= 0 {
+		return maxProgressMessageWidth
+	}
+
+	width := terminalWidth - progressDecorationWidth
+	if width > maxProgressMessageWidth {
+		return maxProgressMessageWidth
+	} else if width < minColumnWidth {
+		return minColumnWidth
+	}
+
+	return width
+}
+
+// NewProgressWriter returns a pre-configured progress writer. The width of the
+// messages is fitted to the terminal.
 func NewProgressWriter(updateFrequency time.Duration) progress.Writer {
 	writer := progress.NewWriter()
+	messageWidth := progressMessageWidth()
 
 	writer.SetAutoStop(true)
 	writer.SetTrackerPosition(progress.PositionRight)
 
-	writer.SetMessageWidth(50)
+	writer.SetMessageWidth(messageWidth)
 	writer.SetUpdateFrequency(updateFrequency)
 
 	writer.Style().Colors = progress.StyleColorsDefault
@@ -25,5 +175,31 @@ func NewProgressWriter(updateFrequency time.Duration) progress.Writer {
 	writer.Style().Visibility.Tracker = false
 	writer.Style().Visibility.Value = false
 
+	return &progressWriter{
+		Writer:       writer,
+		messageWidth: messageWidth,
+	}
+}
+
+// NewPlainProgressWriter returns a progress writer for dumb terminals. Instead
+// of animating the progress, a line is printed to the output per finished
+// tracker.
+func NewPlainProgressWriter(updateFrequency time.Duration, output io.Writer) progress.Writer {
+	return &plainProgressWriter{
+		progressWriter:  NewProgressWriter(updateFrequency).(*progressWriter),
+		output:          output,
+		updateFrequency: updateFrequency,
+		done:            make(chan bool, 1),
+	}
+}
+
+// NewLinearProgressWriter returns a progress writer for screen readers and log
+// files. A status line is printed to the output per finished tracker, without
+// truncating or padding the messages.
+func NewLinearProgressWriter(updateFrequency time.Duration, output io.Writer) progress.Writer {
+	writer := NewPlainProgressWriter(updateFrequency, output).(*plainProgressWriter)
+	writer.messageWidth = 0
+	writer.isLinear = true
+
 	return writer
 }
@@ -1,15 +1,62 @@
 package utils_test
 
 import (
+	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/cmd/utils"
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/stretchr/testify/require"
 )
 
 func TestNewProgressWriter(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
 	progressWriter := utils.NewProgressWriter(time.Millisecond * 100)
-	require.Equal(t, "*progress.Progress", reflect.TypeOf(progressWriter).String())
+	require.Equal(t, "*utils.progressWriter", reflect.TypeOf(progressWriter).String())
+
+	tracker := &progress.Tracker{Message: strings.Repeat("日本語", 20)}
+	progressWriter.AppendTracker(tracker)
+	require.Equal(t, strings.Repeat("日本語", 7)+"日本...", tracker.Message)
+	require.LessOrEqual(t, text.RuneWidthWithoutEscSequences(tracker.Message), 50)
+}
+
+func TestNewPlainProgressWriter(t *testing.T) {
+	t.Setenv("COLUMNS", "200")
+
+	output := new(bytes.Buffer)
+	progressWriter := utils.NewPlainProgressWriter(time.Millisecond*10, output)
+
+	done := &progress.Tracker{Message: "CPT-2014"}
+	failed := &progress.Tracker{Message: "CPT-2015"}
+	progressWriter.AppendTrackers([]*progress.Tracker{done, failed})
+
+	done.MarkAsDone()
+	failed.MarkAsErrored()
+
+	// Render returns when all trackers are finished
+	progressWriter.Render()
+	require.False(t, progressWriter.IsRenderInProgress())
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Regexp(t, `^CPT-2014 +uploaded!$`, lines[0])
+	require.Regexp(t, `^CPT-2015 +failed!$`, strings.TrimSpace(lines[1]))
+	require.NotContains(t, output.String(), "\x1b")
+}
+
+func TestNewLinearProgressWriter(t *testing.T) {
+	output := new(bytes.Buffer)
+	progressWriter := utils.NewLinearProgressWriter(time.Millisecond*10, output)
+
+	message := strings.Repeat("Fetching entries from tempo ", 3)
+	done := &progress.Tracker{Message: message}
+	progressWriter.AppendTracker(done)
+	done.MarkAsDone()
+
+	progressWriter.Render()
+	require.Equal(t, message+": uploaded!\n", output.String())
 }
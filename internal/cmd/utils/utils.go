@@ -7,20 +7,25 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 )
 
 // Truncate chops the text at length and replaces the remaining with "...".
-func Truncate(text string, length int) string {
-	if length >= len(text) || length <= 0 {
-		return text
+// The length is measured in terminal columns, so wide characters, like CJK
+// characters and emojis, are counted as two columns.
+func Truncate(str string, length int) string {
+	if length <= 0 || text.RuneWidthWithoutEscSequences(str) <= length {
+		return str
 	}
 
 	truncated := ""
-	maxLength := length - 3
+	width := 0
+	maxWidth := length - 3
 
-	for i, char := range text {
-		if i >= maxLength {
+	for _, char := range str {
+		width += text.RuneWidth(char)
+		if width > maxWidth {
 			break
 		}
 
@@ -55,10 +60,40 @@ func Prompt(message string) string {
 // GetTime parses a string based on the given format and returns the time.
 // If the rawDate was an empty string, the today's midnight will return.
 func GetTime(rawDate string, dateFormat string) (time.Time, error) {
+	return GetTimeInLocation(rawDate, dateFormat, time.Local)
+}
+
+// GetTimeInLocation parses a string based on the given format in the location
+// and returns the time. If the rawDate was an empty string, the today's
+// midnight in the location will return.
+func GetTimeInLocation(rawDate string, dateFormat string, loc *time.Location) (time.Time, error) {
 	if rawDate == "" {
-		year, month, day := time.Now().Date()
-		return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
+		year, month, day := time.Now().In(loc).Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, loc), nil
+	}
+
+	return time.ParseInLocation(dateFormat, rawDate, loc)
+}
+
+// FormatBalance returns the duration with an explicit sign, like "+1h30m0s" or
+// "-2h0m0s", so overtime and undertime can be distinguished.
+func FormatBalance(d time.Duration) string {
+	if d < 0 {
+		return d.String()
+	}
+
+	return "+" + d.String()
+}
+
+// ParseWeekday parses the full or abbreviated English name of a weekday, like
+// "monday" or "Mon".
+func ParseWeekday(name string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		fullName := strings.ToLower(weekday.String())
+		if lowerName := strings.ToLower(name); lowerName == fullName || lowerName == fullName[:3] {
+			return weekday, nil
+		}
 	}
 
-	return time.ParseInLocation(dateFormat, rawDate, time.Local)
+	return 0, fmt.Errorf("invalid weekday: %s", name)
 }
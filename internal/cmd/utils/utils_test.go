@@ -32,6 +32,24 @@ func TestTruncate(t *testing.T) {
 	require.Equal(t, "This is a short...", truncated)
 }
 
+func TestTruncate_WideCharacters(t *testing.T) {
+	var truncated string
+	text := "日本語のプロジェクト"
+
+	truncated = utils.Truncate(text, 20)
+	require.Equal(t, text, truncated)
+
+	truncated = utils.Truncate(text, 10)
+	require.Equal(t, "日本語...", truncated)
+
+	// Wide characters are not split, so the text may be narrower than the length
+	truncated = utils.Truncate(text, 8)
+	require.Equal(t, "日本...", truncated)
+
+	truncated = utils.Truncate("🚀 Launch", 6)
+	require.Equal(t, "🚀 ...", truncated)
+}
+
 func TestIsSliceContains(t *testing.T) {
 	require.False(t, utils.IsSliceContains("test", []string{}))
 	require.True(t, utils.IsSliceContains("test", []string{"test"}))
@@ -53,3 +71,35 @@ func TestGetTime(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, time.Date(year, month, day, 0, 0, 0, 0, time.Local), parsed)
 }
+
+func TestGetTimeInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	year, month, day := time.Now().In(loc).Date()
+
+	parsed, err := utils.GetTimeInLocation("2021-01-01", "2006-01-02", loc)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, loc), parsed)
+
+	parsed, err = utils.GetTimeInLocation("", "2006-01-02", loc)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(year, month, day, 0, 0, 0, 0, loc), parsed)
+}
+
+func TestFormatBalance(t *testing.T) {
+	require.Equal(t, "+1h30m0s", utils.FormatBalance(time.Hour+time.Minute*30))
+	require.Equal(t, "-2h0m0s", utils.FormatBalance(-time.Hour*2))
+	require.Equal(t, "+0s", utils.FormatBalance(0))
+}
+
+func TestParseWeekday(t *testing.T) {
+	weekday, err := utils.ParseWeekday("monday")
+	require.Nil(t, err)
+	require.Equal(t, time.Monday, weekday)
+
+	weekday, err = utils.ParseWeekday("Sun")
+	require.Nil(t, err)
+	require.Equal(t, time.Sunday, weekday)
+
+	_, err = utils.ParseWeekday("someday")
+	require.Error(t, err)
+}
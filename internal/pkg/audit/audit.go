@@ -0,0 +1,340 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	netURL "net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultMaxSize is the default size of the audit log in bytes, after
+	// which the log is rotated.
+	DefaultMaxSize int64 = 10 * 1024 * 1024
+
+	// rotatedTimeFormat is the format of the timestamp appended to the path of
+	// the rotated logs. The format sorts the rotated logs chronologically.
+	rotatedTimeFormat string = "20060102T150405.000000000"
+
+	// syslogAppName is the app name of the syslog messages.
+	syslogAppName string = "minutes"
+	// syslogPriorityInfo and syslogPriorityWarning are the priorities of the
+	// successful and failed calls, using the user-level facility.
+	syslogPriorityInfo    int = 8 + 6
+	syslogPriorityWarning int = 8 + 4
+)
+
+var (
+	// ErrNoPath returns when the audit log has no path set.
+	ErrNoPath = errors.New("no audit log path provided")
+	// ErrInvalidSyslogURL returns when the syslog URL has no supported scheme.
+	ErrInvalidSyslogURL = errors.New("invalid syslog URL")
+)
+
+// Record represents a mutating call of an uploader in the audit log.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Target string    `json:"target"`
+	Method string    `json:"method"`
+	URL    string    `json:"url"`
+	// Fingerprint identifies the uploaded entry, without storing its content.
+	Fingerprint string `json:"fingerprint"`
+	// StatusCode is the status code of the response. If no response was
+	// received, it is 0.
+	StatusCode int `json:"status_code"`
+	// WorklogID is the ID of the created worklog, if returned by the target.
+	WorklogID string `json:"worklog_id,omitempty"`
+	// OnBehalfOf is the user impersonated by the call, if any.
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Fingerprint returns the SHA-256 hash of the entry's key and durations, so the
+// uploaded entries can be matched with the audit records later.
+func Fingerprint(entry worklog.Entry) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf(
+		"%s|%s|%d|%d",
+		entry.Key(),
+		entry.Start.UTC().Format(time.RFC3339),
+		entry.BillableDuration,
+		entry.UnbillableDuration,
+	)))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// LoggerOpts represents the options of the audit Logger.
+type LoggerOpts struct {
+	// Path is the path of the audit log. The records are appended to the log,
+	// one JSON record per line.
+	Path string
+	// MaxSize is the size of the log in bytes, after which the log is rotated.
+	// If not set, DefaultMaxSize is used.
+	MaxSize int64
+	// MaxBackups is the number of rotated logs kept. If not set, every rotated
+	// log is kept.
+	MaxBackups int
+	// SyslogURL is the address of the syslog server the records are shipped
+	// to as well, like "udp://localhost:514" or "unixgram:///dev/log". If not
+	// set, the records are not shipped.
+	SyslogURL string
+	// Target is the name of the target the records are written for.
+	Target string
+	// ErrorOutput is the writer of the recording errors. If not set, the
+	// errors are written to stderr.
+	ErrorOutput io.Writer
+}
+
+// Validate returns an error if the options cannot be used to write the audit
+// log.
+func (o *LoggerOpts) Validate() error {
+	if o.Path == "" {
+		return ErrNoPath
+	}
+
+	if o.SyslogURL != "" {
+		if _, _, err := parseSyslogURL(o.SyslogURL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Logger writes the append-only audit log of the mutating calls. The log is
+// rotated by renaming it, so the written records are never changed. Logger is
+// safe for concurrent use and implements client.MutationRecorder.
+type Logger struct {
+	mu     sync.Mutex
+	opts   LoggerOpts
+	file   *os.File
+	size   int64
+	syslog net.Conn
+}
+
+// open opens the log for appending.
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.opts.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+// rotate renames the log by appending the current time to its path, then
+// opens a new log. The oldest rotated logs exceeding MaxBackups are removed.
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(l.opts.Path, l.opts.Path+"."+time.Now().UTC().Format(rotatedTimeFormat)); err != nil {
+		return err
+	}
+
+	if l.opts.MaxBackups > 0 {
+		backups, err := filepath.Glob(l.opts.Path + ".*")
+		if err != nil {
+			return err
+		}
+
+		sort.Strings(backups)
+		for len(backups) > l.opts.MaxBackups {
+			if err = os.Remove(backups[0]); err != nil {
+				return err
+			}
+
+			backups = backups[1:]
+		}
+	}
+
+	return l.open()
+}
+
+// sendSyslog ships the record to the syslog server as an RFC 5424 message.
+func (l *Logger) sendSyslog(record *Record, line []byte) error {
+	priority := syslogPriorityInfo
+	if record.Error != "" {
+		priority = syslogPriorityWarning
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	message := fmt.Sprintf(
+		"<%d>1 %s %s %s %d - - %s",
+		priority,
+		record.Time.Format(time.RFC3339Nano),
+		hostname,
+		syslogAppName,
+		os.Getpid(),
+		line,
+	)
+
+	// Stream based connections need the messages to be framed
+	if _, isStream := l.syslog.(*net.TCPConn); isStream {
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+
+	_, err = l.syslog.Write([]byte(message))
+	return err
+}
+
+// Log appends the record to the audit log and ships it to syslog, if set. If
+// the log exceeds the MaxSize, it is rotated before writing the record.
+func (l *Logger) Log(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.size > 0 && l.size+int64(len(line))+1 > l.opts.MaxSize {
+		if err = l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := l.file.Write(append(line, '\n'))
+	l.size += int64(written)
+	if err != nil {
+		return err
+	}
+
+	if err = l.file.Sync(); err != nil {
+		return err
+	}
+
+	if l.syslog != nil {
+		return l.sendSyslog(record, line)
+	}
+
+	return nil
+}
+
+// RecordMutation writes the mutation to the audit log. Since the upload must
+// not fail because of the audit log, the errors are written to the
+// ErrorOutput.
+func (l *Logger) RecordMutation(mutation *client.Mutation) {
+	record := &Record{
+		Time:        time.Now().UTC(),
+		Target:      l.opts.Target,
+		Method:      mutation.Method,
+		URL:         mutation.URL,
+		Fingerprint: Fingerprint(mutation.Entry),
+		StatusCode:  mutation.StatusCode,
+		WorklogID:   mutation.ResourceID,
+		OnBehalfOf:  mutation.OnBehalfOf,
+	}
+
+	// Credentials must not be written to the log
+	if url, err := netURL.Parse(mutation.URL); err == nil {
+		record.URL = url.Redacted()
+	}
+
+	if mutation.Err != nil {
+		record.Error = mutation.Err.Error()
+	}
+
+	if err := l.Log(record); err != nil {
+		fmt.Fprintf(l.opts.ErrorOutput, "failed to write audit log: %v\n", err)
+	}
+}
+
+// Close closes the audit log and the syslog connection.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.file.Close()
+
+	if l.syslog != nil {
+		err = errors.Join(err, l.syslog.Close())
+	}
+
+	return err
+}
+
+// parseSyslogURL returns the network and address of the syslog server given in
+// "network://address" format, like "udp://localhost:514",
+// "tcp://localhost:514" or "unixgram:///dev/log".
+func parseSyslogURL(rawURL string) (string, string, error) {
+	network, address, ok := strings.Cut(rawURL, "://")
+	if !ok || address == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidSyslogURL, rawURL)
+	}
+
+	switch network {
+	case "udp", "tcp", "unix", "unixgram":
+		return network, address, nil
+	default:
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidSyslogURL, rawURL)
+	}
+}
+
+// NewLogger opens the audit log for appending, and connects to the syslog
+// server if set.
+func NewLogger(opts *LoggerOpts) (*Logger, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	loggerOpts := *opts
+
+	if loggerOpts.MaxSize <= 0 {
+		loggerOpts.MaxSize = DefaultMaxSize
+	}
+
+	if loggerOpts.ErrorOutput == nil {
+		loggerOpts.ErrorOutput = os.Stderr
+	}
+
+	logger := &Logger{opts: loggerOpts}
+
+	if err := logger.open(); err != nil {
+		return nil, err
+	}
+
+	if loggerOpts.SyslogURL != "" {
+		// The URL is already validated, parsing it again cannot fail
+		network, address, _ := parseSyslogURL(loggerOpts.SyslogURL)
+
+		conn, err := net.DialTimeout(network, address, client.DefaultRequestTimeout)
+		if err != nil {
+			_ = logger.file.Close()
+			return nil, err
+		}
+
+		logger.syslog = conn
+	}
+
+	return logger, nil
+}
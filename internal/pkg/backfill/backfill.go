@@ -0,0 +1,162 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultChunkDays is the number of days in a chunk, so a chunk is a week.
+	DefaultChunkDays int = 7
+)
+
+var (
+	// ErrRangeMismatch returns when the progress belongs to other range or
+	// chunk size than the backfilled one.
+	ErrRangeMismatch = errors.New("progress belongs to other backfill")
+)
+
+// Chunk represents a part of the backfilled range, processed at once. Like
+// the backfilled range, the chunk is half open: the entries starting at Start
+// belong to the chunk, but the entries starting at End do not.
+type Chunk struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Key returns the unique key of the chunk within the backfill.
+func (c Chunk) Key() string {
+	return c.Start.Format(time.RFC3339)
+}
+
+// SplitRange splits the range between start and end into chunks of the given
+// number of days. The days are added by the calendar, so the chunks are not
+// shifted by daylight saving time changes. The last chunk ends at the end of
+// the range, hence it can be shorter.
+func SplitRange(start time.Time, end time.Time, days int) []Chunk {
+	if days <= 0 {
+		days = DefaultChunkDays
+	}
+
+	var chunks []Chunk
+
+	for chunkStart := start; chunkStart.Before(end); chunkStart = chunkStart.AddDate(0, 0, days) {
+		chunkEnd := chunkStart.AddDate(0, 0, days)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		chunks = append(chunks, Chunk{Start: chunkStart, End: chunkEnd})
+	}
+
+	return chunks
+}
+
+// Progress represents the progress of a backfill, persisted after every
+// uploaded entry, so an interrupted backfill can be resumed. The completed
+// chunks are skipped, while the entries of the interrupted chunk recorded in
+// the ledger are not uploaded again.
+type Progress struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	ChunkDays int       `json:"chunk_days"`
+	// Completed maps the keys of the completed chunks to their completion.
+	Completed map[string]time.Time `json:"completed"`
+	// Ledger keeps track of the uploaded durations.
+	Ledger    *worklog.Ledger `json:"ledger"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// IsCompleted returns true if the chunk was completed.
+func (p *Progress) IsCompleted(chunk Chunk) bool {
+	_, ok := p.Completed[chunk.Key()]
+	return ok
+}
+
+// Complete marks the chunk as completed at the given time.
+func (p *Progress) Complete(chunk Chunk, completedAt time.Time) {
+	p.Completed[chunk.Key()] = completedAt
+}
+
+// Check returns ErrRangeMismatch if the progress belongs to other range or
+// chunk size, since the chunks would not match.
+func (p *Progress) Check(start time.Time, end time.Time, chunkDays int) error {
+	if !p.Start.Equal(start) || !p.End.Equal(end) || p.ChunkDays != chunkDays {
+		return ErrRangeMismatch
+	}
+
+	return nil
+}
+
+// Save writes the progress to the file at path. The progress is written to a
+// temporary file first, so an interrupted write does not corrupt the progress.
+func (p *Progress) Save(path string) error {
+	p.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// NewProgress returns the progress of a new backfill.
+func NewProgress(start time.Time, end time.Time, chunkDays int) *Progress {
+	return &Progress{
+		Start:     start,
+		End:       end,
+		ChunkDays: chunkDays,
+		Completed: map[string]time.Time{},
+		Ledger:    worklog.NewLedger(),
+	}
+}
+
+// LoadProgress reads the progress from the file at path. If the file does not
+// exist, the returned error wraps os.ErrNotExist.
+func LoadProgress(path string) (*Progress, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	progress := NewProgress(time.Time{}, time.Time{}, 0)
+	if err = json.Unmarshal(data, progress); err != nil {
+		return nil, err
+	}
+
+	if progress.Completed == nil {
+		progress.Completed = map[string]time.Time{}
+	}
+
+	if progress.Ledger == nil {
+		progress.Ledger = worklog.NewLedger()
+	}
+
+	return progress, nil
+}
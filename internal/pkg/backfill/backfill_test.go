@@ -0,0 +1,96 @@
+package backfill_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/backfill"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRange(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 18, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, []backfill.Chunk{
+		{Start: start, End: time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC), End: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), End: end},
+	}, backfill.SplitRange(start, end, 7))
+}
+
+func TestSplitRange_DefaultChunkDays(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	require.Len(t, backfill.SplitRange(start, end, 0), 2)
+}
+
+func TestSplitRange_Empty(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Empty(t, backfill.SplitRange(start, start, 7))
+}
+
+func TestSplitRange_DaylightSavingTime(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Budapest")
+	require.Nil(t, err)
+
+	start := time.Date(2023, 3, 20, 0, 0, 0, 0, loc)
+	end := time.Date(2023, 4, 3, 0, 0, 0, 0, loc)
+
+	chunks := backfill.SplitRange(start, end, 7)
+	require.Len(t, chunks, 2)
+	require.Equal(t, time.Date(2023, 3, 27, 0, 0, 0, 0, loc), chunks[1].Start)
+}
+
+func TestProgress(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	chunks := backfill.SplitRange(start, end, 7)
+
+	progress := backfill.NewProgress(start, end, 7)
+	require.False(t, progress.IsCompleted(chunks[0]))
+
+	progress.Complete(chunks[0], time.Now())
+	require.True(t, progress.IsCompleted(chunks[0]))
+	require.False(t, progress.IsCompleted(chunks[1]))
+
+	require.Nil(t, progress.Check(start, end, 7))
+	require.ErrorIs(t, progress.Check(start, end.AddDate(0, 0, 1), 7), backfill.ErrRangeMismatch)
+	require.ErrorIs(t, progress.Check(start, end, 1), backfill.ErrRangeMismatch)
+}
+
+func TestProgress_SaveLoad(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	chunks := backfill.SplitRange(start, end, 7)
+	path := filepath.Join(t.TempDir(), "backfill", "progress.json")
+
+	entry := worklog.Entry{
+		Summary:          "Write backfill",
+		Start:            time.Date(2023, 1, 9, 9, 0, 0, 0, time.UTC),
+		BillableDuration: time.Hour,
+	}
+
+	progress := backfill.NewProgress(start, end, 7)
+	progress.Complete(chunks[0], time.Now())
+	progress.Ledger.Record(entry)
+	require.Nil(t, progress.Save(path))
+
+	loaded, err := backfill.LoadProgress(path)
+	require.Nil(t, err)
+	require.Nil(t, loaded.Check(start, end, 7))
+	require.True(t, loaded.IsCompleted(chunks[0]))
+	require.False(t, loaded.IsCompleted(chunks[1]))
+	require.Empty(t, loaded.Ledger.Pending(worklog.Entries{entry}))
+}
+
+func TestLoadProgress_NotExist(t *testing.T) {
+	_, err := backfill.LoadProgress(filepath.Join(t.TempDir(), "progress.json"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+}
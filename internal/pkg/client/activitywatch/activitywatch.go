@@ -0,0 +1,415 @@
+package activitywatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the address of the ActivityWatch server running locally.
+	DefaultURL string = "http://localhost:5600"
+	// DefaultMinDuration is the shortest aggregated activity kept, if not
+	// configured otherwise.
+	DefaultMinDuration time.Duration = time.Minute
+	// DefaultMergeGap is the longest gap between the events of the same
+	// activity merged into one entry, if not configured otherwise.
+	DefaultMergeGap time.Duration = time.Minute
+
+	// PathBuckets is the endpoint used to list the buckets.
+	PathBuckets string = "/api/0/buckets/"
+	// PathEvents is the endpoint used to fetch the events of a bucket.
+	PathEvents string = "/api/0/buckets/%s/events"
+
+	// BucketTypeWindow is the type of the buckets of the window watchers.
+	BucketTypeWindow string = "currentwindow"
+	// BucketTypeWeb is the type of the buckets of the browser watchers.
+	BucketTypeWeb string = "web.tab.current"
+	// BucketTypeAFK is the type of the buckets of the AFK watchers.
+	BucketTypeAFK string = "afkstatus"
+
+	// StatusNotAFK is the status of the AFK events when the user is active.
+	StatusNotAFK string = "not-afk"
+)
+
+// Bucket represents a bucket of a watcher.
+type Bucket struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Client   string `json:"client"`
+	Hostname string `json:"hostname"`
+}
+
+// EventData represents the data of the events. The window events have the
+// app and title, the browser events have the URL and title, while the AFK
+// events have the status set.
+type EventData struct {
+	App    string `json:"app"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+// Event represents an event of a bucket. The duration is in seconds.
+type Event struct {
+	ID        int             `json:"id"`
+	Timestamp utils.Timestamp `json:"timestamp"`
+	Duration  float64         `json:"duration"`
+	Data      EventData       `json:"data"`
+}
+
+// End returns the end of the event.
+func (e *Event) End() time.Time {
+	return e.Timestamp.Add(time.Duration(e.Duration * float64(time.Second)))
+}
+
+// period represents a half open time period.
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+// activity represents the aggregated events of the same window or tab.
+type activity struct {
+	key      string
+	app      string
+	title    string
+	url      string
+	start    time.Time
+	end      time.Time
+	duration time.Duration
+	eventIDs []string
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Buckets lists the IDs of the buckets the activities are fetched from. If
+	// empty, every window and browser bucket is used.
+	Buckets []string
+	// MinDuration is the shortest aggregated activity kept. The shorter
+	// activities, like switching windows, are dropped.
+	MinDuration time.Duration
+	// MergeGap is the longest gap between the events of the same window or tab
+	// merged into one entry.
+	MergeGap time.Duration
+}
+
+type activityWatchClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	buckets     []string
+	minDuration time.Duration
+	mergeGap    time.Duration
+}
+
+func (c *activityWatchClient) fetchBuckets(ctx context.Context) (map[string]Bucket, error) {
+	bucketsURL, err := c.URL(PathBuckets, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     bucketsURL,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets map[string]Bucket
+	if err = json.Unmarshal(resp, &buckets); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func (c *activityWatchClient) fetchEvents(ctx context.Context, bucket string, opts *client.FetchOpts) ([]Event, error) {
+	eventsURL, err := c.URL(fmt.Sprintf(PathEvents, url.PathEscape(bucket)), map[string]string{
+		"start": opts.Start.Format(time.RFC3339),
+		"end":   opts.End.Format(time.RFC3339),
+		"limit": "-1",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     eventsURL,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	if err = json.Unmarshal(resp, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// getActivityBuckets returns the IDs of the buckets the activities are
+// fetched from. The configured buckets must exist.
+func (c *activityWatchClient) getActivityBuckets(buckets map[string]Bucket) ([]string, error) {
+	if len(c.buckets) != 0 {
+		for _, id := range c.buckets {
+			if _, ok := buckets[id]; !ok {
+				return nil, fmt.Errorf("bucket %s does not exist", id)
+			}
+		}
+
+		return c.buckets, nil
+	}
+
+	var ids []string
+	for id, bucket := range buckets {
+		if bucket.Type == BucketTypeWindow || bucket.Type == BucketTypeWeb {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// fetchActivePeriods returns the periods when the user was not AFK, ordered by
+// their start. If there are no AFK buckets, nil returns.
+func (c *activityWatchClient) fetchActivePeriods(ctx context.Context, buckets map[string]Bucket, opts *client.FetchOpts) ([]period, error) {
+	var periods []period
+	hasAFKBucket := false
+
+	for id, bucket := range buckets {
+		if bucket.Type != BucketTypeAFK {
+			continue
+		}
+
+		hasAFKBucket = true
+
+		events, err := c.fetchEvents(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if event.Data.Status == StatusNotAFK {
+				periods = append(periods, period{start: event.Timestamp.Time, end: event.End()})
+			}
+		}
+	}
+
+	if !hasAFKBucket {
+		return nil, nil
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].start.Before(periods[j].start)
+	})
+
+	// The overlapping periods of multiple hosts are merged, so the time is
+	// not counted twice
+	var merged []period
+	for _, p := range periods {
+		if last := len(merged) - 1; last >= 0 && !p.start.After(merged[last].end) {
+			if p.end.After(merged[last].end) {
+				merged[last].end = p.end
+			}
+			continue
+		}
+
+		merged = append(merged, p)
+	}
+
+	// Having AFK buckets without active periods means the user was away the
+	// whole time, which differs from not watching the AFK status at all
+	if merged == nil {
+		merged = []period{}
+	}
+
+	return merged, nil
+}
+
+// clipEvent returns the parts of the event within the active periods. If the
+// active periods are nil, the whole event returns.
+func clipEvent(event Event, activePeriods []period) []period {
+	if activePeriods == nil {
+		return []period{{start: event.Timestamp.Time, end: event.End()}}
+	}
+
+	var parts []period
+	for _, active := range activePeriods {
+		start, end := event.Timestamp.Time, event.End()
+
+		if active.start.After(start) {
+			start = active.start
+		}
+
+		if active.end.Before(end) {
+			end = active.end
+		}
+
+		if start.Before(end) {
+			parts = append(parts, period{start: start, end: end})
+		}
+	}
+
+	return parts
+}
+
+// aggregateEvents combines the active parts of the events of the same window
+// or tab into activities, if the gap between them is not longer than the
+// merge gap. The activities shorter than the minimum duration are dropped.
+func (c *activityWatchClient) aggregateEvents(events []Event, activePeriods []period) []*activity {
+	var parts []*activity
+
+	for _, event := range events {
+		key := event.Data.App + "\x00" + event.Data.Title + "\x00" + event.Data.URL
+
+		for _, part := range clipEvent(event, activePeriods) {
+			parts = append(parts, &activity{
+				key:      key,
+				app:      event.Data.App,
+				title:    event.Data.Title,
+				url:      event.Data.URL,
+				start:    part.start,
+				end:      part.end,
+				duration: part.end.Sub(part.start),
+				eventIDs: []string{strconv.Itoa(event.ID)},
+			})
+		}
+	}
+
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].start.Before(parts[j].start)
+	})
+
+	var activities []*activity
+	latest := map[string]*activity{}
+
+	for _, part := range parts {
+		if previous, ok := latest[part.key]; ok && part.start.Sub(previous.end) <= c.mergeGap {
+			previous.duration += part.duration
+			previous.eventIDs = append(previous.eventIDs, part.eventIDs...)
+
+			if part.end.After(previous.end) {
+				previous.end = part.end
+			}
+
+			continue
+		}
+
+		latest[part.key] = part
+		activities = append(activities, part)
+	}
+
+	var kept []*activity
+	for _, a := range activities {
+		if a.duration >= c.minDuration {
+			kept = append(kept, a)
+		}
+	}
+
+	return kept
+}
+
+// parseActivity returns the entry of the activity. The project is the app of
+// the window or the host of the tab, while the summary is the title. The task
+// is extracted from the title if the regex is set, otherwise the title is used
+// as task.
+func parseActivity(a *activity) worklog.Entry {
+	projectName := a.app
+	if a.url != "" {
+		if tabURL, err := url.Parse(a.url); err == nil && tabURL.Host != "" {
+			projectName = tabURL.Host
+		}
+	}
+
+	entry := worklog.Entry{
+		Project: worklog.IDNameField{
+			ID:   projectName,
+			Name: projectName,
+		},
+		Task: worklog.IDNameField{
+			ID:   a.title,
+			Name: a.title,
+		},
+		Summary:          a.title,
+		Start:            a.start,
+		BillableDuration: a.duration,
+		Provenance:       worklog.Provenance{SourceIDs: a.eventIDs},
+		TaskHints:        &worklog.TaskHints{Texts: []string{a.title}},
+	}
+
+	entry.AddLinks(a.url)
+
+	if len(a.eventIDs) > 1 {
+		entry.AddTransformation("aggregated %d events", len(a.eventIDs))
+	}
+
+	return entry
+}
+
+func (c *activityWatchClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	buckets, err := c.fetchBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	activityBuckets, err := c.getActivityBuckets(buckets)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	activePeriods, err := c.fetchActivePeriods(ctx, buckets, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var entries worklog.Entries
+	for _, bucket := range activityBuckets {
+		events, err := c.fetchEvents(ctx, bucket, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		// The tasks are extracted after aggregating the events, so the short
+		// events of the same window are not split into separate entries
+		for _, a := range c.aggregateEvents(events, activePeriods) {
+			entries = append(entries, parseActivity(a))
+		}
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new ActivityWatch client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &activityWatchClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		buckets:     opts.Buckets,
+		minDuration: opts.MinDuration,
+		mergeGap:    opts.MergeGap,
+	}, nil
+}
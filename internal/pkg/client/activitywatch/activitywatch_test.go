@@ -0,0 +1,183 @@
+package activitywatch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/activitywatch"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testStart = time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+)
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2021, 10, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func getTestBuckets() map[string]activitywatch.Bucket {
+	return map[string]activitywatch.Bucket{
+		"aw-watcher-window_laptop": {ID: "aw-watcher-window_laptop", Type: activitywatch.BucketTypeWindow, Hostname: "laptop"},
+		"aw-watcher-web-firefox":   {ID: "aw-watcher-web-firefox", Type: activitywatch.BucketTypeWeb, Hostname: "unknown"},
+		"aw-watcher-afk_laptop":    {ID: "aw-watcher-afk_laptop", Type: activitywatch.BucketTypeAFK, Hostname: "laptop"},
+		"aw-watcher-input_laptop":  {ID: "aw-watcher-input_laptop", Type: "os.hid.input", Hostname: "laptop"},
+	}
+}
+
+func getTestEvents() map[string][]activitywatch.Event {
+	return map[string][]activitywatch.Event{
+		"aw-watcher-window_laptop": {
+			{ID: 1, Timestamp: utils.Timestamp{Time: at(9, 0)}, Duration: 300, Data: activitywatch.EventData{App: "Code", Title: "TASK-1 minutes - main.go"}},
+			{ID: 2, Timestamp: utils.Timestamp{Time: at(9, 5)}, Duration: 60, Data: activitywatch.EventData{App: "Slack", Title: "general"}},
+			{ID: 3, Timestamp: utils.Timestamp{Time: at(9, 6)}, Duration: 1200, Data: activitywatch.EventData{App: "Code", Title: "TASK-1 minutes - main.go"}},
+			// Shorter than the minimum duration
+			{ID: 4, Timestamp: utils.Timestamp{Time: at(9, 26)}, Duration: 30, Data: activitywatch.EventData{App: "Slack", Title: "general"}},
+			// Partially AFK
+			{ID: 5, Timestamp: utils.Timestamp{Time: at(9, 50)}, Duration: 1200, Data: activitywatch.EventData{App: "Code", Title: "TASK-1 minutes - main.go"}},
+			// Completely AFK
+			{ID: 6, Timestamp: utils.Timestamp{Time: at(10, 10)}, Duration: 600, Data: activitywatch.EventData{App: "Terminal", Title: "htop"}},
+		},
+		"aw-watcher-web-firefox": {
+			{ID: 7, Timestamp: utils.Timestamp{Time: at(11, 0)}, Duration: 900, Data: activitywatch.EventData{URL: "https://github.com/gabor-boros/minutes/pull/1", Title: "TASK-2 Add the source"}},
+		},
+		"aw-watcher-afk_laptop": {
+			{ID: 8, Timestamp: utils.Timestamp{Time: at(9, 0)}, Duration: 3600, Data: activitywatch.EventData{Status: activitywatch.StatusNotAFK}},
+			{ID: 9, Timestamp: utils.Timestamp{Time: at(10, 0)}, Duration: 1800, Data: activitywatch.EventData{Status: "afk"}},
+			{ID: 10, Timestamp: utils.Timestamp{Time: at(10, 30)}, Duration: 5400, Data: activitywatch.EventData{Status: activitywatch.StatusNotAFK}},
+		},
+	}
+}
+
+func newMockServer(t *testing.T, buckets map[string]activitywatch.Bucket, events map[string][]activitywatch.Event) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(activitywatch.PathBuckets, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Nil(t, json.NewEncoder(w).Encode(buckets))
+	})
+
+	for id, bucketEvents := range events {
+		bucketEvents := bucketEvents
+
+		mux.HandleFunc("/api/0/buckets/"+id+"/events", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, testStart.Format(time.RFC3339), r.URL.Query().Get("start"))
+			require.Equal(t, testEnd.Format(time.RFC3339), r.URL.Query().Get("end"))
+			require.Equal(t, "-1", r.URL.Query().Get("limit"))
+			require.Nil(t, json.NewEncoder(w).Encode(bucketEvents))
+		})
+	}
+
+	return httptest.NewServer(mux)
+}
+
+func newTestFetcher(t *testing.T, serverURL string, buckets []string) client.Fetcher {
+	fetcher, err := activitywatch.NewFetcher(&activitywatch.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:     serverURL,
+		Buckets:     buckets,
+		MinDuration: activitywatch.DefaultMinDuration,
+		MergeGap:    activitywatch.DefaultMergeGap,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestActivityWatchClient_FetchEntries(t *testing.T) {
+	expectedEntries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{ID: "Code", Name: "Code"},
+			Task:             worklog.IDNameField{ID: "TASK-1", Name: "TASK-1"},
+			Summary:          "TASK-1 minutes - main.go",
+			Start:            at(9, 0),
+			BillableDuration: time.Minute * 25,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"1", "3"}, Transformations: []string{"aggregated 2 events"}},
+		},
+		{
+			Project:          worklog.IDNameField{ID: "Slack", Name: "Slack"},
+			Task:             worklog.IDNameField{ID: "general", Name: "general"},
+			Summary:          "general",
+			Start:            at(9, 5),
+			BillableDuration: time.Minute,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+		{
+			Project:          worklog.IDNameField{ID: "Code", Name: "Code"},
+			Task:             worklog.IDNameField{ID: "TASK-1", Name: "TASK-1"},
+			Summary:          "TASK-1 minutes - main.go",
+			Start:            at(9, 50),
+			BillableDuration: time.Minute * 10,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"5"}},
+		},
+		{
+			Project:          worklog.IDNameField{ID: "github.com", Name: "github.com"},
+			Task:             worklog.IDNameField{ID: "TASK-2", Name: "TASK-2"},
+			Summary:          "TASK-2 Add the source",
+			Start:            at(11, 0),
+			BillableDuration: time.Minute * 15,
+			Links:            []string{"https://github.com/gabor-boros/minutes/pull/1"},
+			Provenance:       worklog.Provenance{SourceIDs: []string{"7"}},
+		},
+	}
+
+	mockServer := newMockServer(t, getTestBuckets(), getTestEvents())
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, nil).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: testStart,
+		End:   testEnd,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestActivityWatchClient_FetchEntries_NoAFKBucket(t *testing.T) {
+	buckets := getTestBuckets()
+	delete(buckets, "aw-watcher-afk_laptop")
+
+	events := getTestEvents()
+	delete(events, "aw-watcher-afk_laptop")
+
+	mockServer := newMockServer(t, buckets, events)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, []string{"aw-watcher-window_laptop"}).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: testStart,
+		End:   testEnd,
+	})
+	require.Nil(t, err, "cannot fetch entries")
+
+	var durations []time.Duration
+	for _, entry := range entries {
+		durations = append(durations, entry.BillableDuration)
+	}
+
+	// Without AFK status, the whole events are counted
+	require.Equal(t, []time.Duration{time.Minute * 25, time.Minute, time.Minute * 20, time.Minute * 10}, durations)
+}
+
+func TestActivityWatchClient_FetchEntries_UnknownBucket(t *testing.T) {
+	mockServer := newMockServer(t, getTestBuckets(), getTestEvents())
+	defer mockServer.Close()
+
+	_, err := newTestFetcher(t, mockServer.URL, []string{"aw-watcher-window_desktop"}).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: testStart,
+		End:   testEnd,
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// AuthorStrategyCurrent uploads every entry in the name of the target user.
+	AuthorStrategyCurrent string = "current"
+	// AuthorStrategyFixed uploads every entry in the name of a fixed user, like
+	// a service account.
+	AuthorStrategyFixed string = "fixed"
+	// AuthorStrategySource uploads the entries in the name of their worker
+	// attribute, set by the source. The entries without the attribute are
+	// uploaded in the name of the target user.
+	AuthorStrategySource string = "source"
+	// AuthorStrategyMapped uploads the entries in the name of the user mapped
+	// to their worker attribute. The entries without the attribute are
+	// uploaded in the name of the target user.
+	AuthorStrategyMapped string = "mapped"
+)
+
+var (
+	// ErrNoAuthor returns when the author of an entry cannot be resolved, like
+	// when the worker of the entry is not mapped to any user.
+	ErrNoAuthor = errors.New("no author of the entry")
+	// ErrUnknownAuthorStrategy returns when an author strategy is not part of
+	// the AuthorStrategies.
+	ErrUnknownAuthorStrategy = errors.New("unknown author strategy")
+)
+
+// AuthorStrategies lists the strategies of setting the author of the uploaded
+// entries.
+var AuthorStrategies = []string{AuthorStrategyCurrent, AuthorStrategyFixed, AuthorStrategySource, AuthorStrategyMapped}
+
+// AuthorOpts sets how the author of the uploaded entries, like the worker of a
+// worklog, is resolved.
+type AuthorOpts struct {
+	// Strategy is one of the AuthorStrategies. If not set,
+	// AuthorStrategySource is used, so the entries without worker are
+	// uploaded in the name of the target user.
+	Strategy string
+	// FixedUser is the author of every entry when using AuthorStrategyFixed.
+	FixedUser string
+	// UserMap maps the worker of the entries to the author when using
+	// AuthorStrategyMapped. The workers are matched case-insensitively.
+	UserMap map[string]string
+}
+
+// Validate returns an error if the options cannot be used to resolve the
+// authors.
+func (o *AuthorOpts) Validate() error {
+	switch o.Strategy {
+	case "", AuthorStrategySource, AuthorStrategyCurrent:
+	case AuthorStrategyFixed:
+		if o.FixedUser == "" {
+			return fmt.Errorf("%w: no fixed user provided", ErrNoAuthor)
+		}
+	case AuthorStrategyMapped:
+		if len(o.UserMap) == 0 {
+			return fmt.Errorf("%w: no user map provided", ErrNoAuthor)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAuthorStrategy, o.Strategy)
+	}
+
+	return nil
+}
+
+// Resolve returns the author of the entry by the strategy. The user is the
+// target user, used by AuthorStrategyCurrent and for the entries without
+// worker.
+func (o *AuthorOpts) Resolve(entry *worklog.Entry, user string) (string, error) {
+	worker := entry.Attributes[worklog.AttributeWorker]
+
+	switch o.Strategy {
+	case AuthorStrategyCurrent:
+		return user, nil
+	case AuthorStrategyFixed:
+		return o.FixedUser, nil
+	case "", AuthorStrategySource:
+		if worker == "" {
+			return user, nil
+		}
+
+		return worker, nil
+	case AuthorStrategyMapped:
+		if worker == "" {
+			return user, nil
+		}
+
+		for mappedWorker, author := range o.UserMap {
+			if strings.EqualFold(mappedWorker, worker) {
+				return author, nil
+			}
+		}
+
+		return "", fmt.Errorf("%w: %s is not mapped", ErrNoAuthor, worker)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownAuthorStrategy, o.Strategy)
+	}
+}
@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorOpts_Resolve(t *testing.T) {
+	withWorker := getTestEntry()
+	withWorker.SetAttribute(worklog.AttributeWorker, "bucky-barnes")
+
+	withoutWorker := getTestEntry()
+
+	userMap := map[string]string{"Bucky-Barnes": "james.barnes@example.com"}
+
+	tests := []struct {
+		name  string
+		opts  *client.AuthorOpts
+		entry worklog.Entry
+		want  string
+		err   error
+	}{
+		{
+			name:  "default uses the worker",
+			opts:  &client.AuthorOpts{},
+			entry: withWorker,
+			want:  "bucky-barnes",
+		},
+		{
+			name:  "source without worker uses the target user",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategySource},
+			entry: withoutWorker,
+			want:  "steve-rogers",
+		},
+		{
+			name:  "current ignores the worker",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategyCurrent},
+			entry: withWorker,
+			want:  "steve-rogers",
+		},
+		{
+			name:  "fixed uses the fixed user",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategyFixed, FixedUser: "svc-minutes"},
+			entry: withWorker,
+			want:  "svc-minutes",
+		},
+		{
+			name:  "mapped maps the worker",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategyMapped, UserMap: userMap},
+			entry: withWorker,
+			want:  "james.barnes@example.com",
+		},
+		{
+			name:  "mapped without worker uses the target user",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategyMapped, UserMap: userMap},
+			entry: withoutWorker,
+			want:  "steve-rogers",
+		},
+		{
+			name:  "mapped fails for unmapped worker",
+			opts:  &client.AuthorOpts{Strategy: client.AuthorStrategyMapped, UserMap: map[string]string{"natasha-romanoff": "natasha"}},
+			entry: withWorker,
+			err:   client.ErrNoAuthor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author, err := tt.opts.Resolve(&tt.entry, "steve-rogers")
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.want, author)
+		})
+	}
+}
+
+func TestAuthorOpts_Validate(t *testing.T) {
+	require.Nil(t, (&client.AuthorOpts{}).Validate())
+	require.Nil(t, (&client.AuthorOpts{Strategy: client.AuthorStrategyCurrent}).Validate())
+	require.ErrorIs(t, (&client.AuthorOpts{Strategy: client.AuthorStrategyFixed}).Validate(), client.ErrNoAuthor)
+	require.ErrorIs(t, (&client.AuthorOpts{Strategy: client.AuthorStrategyMapped}).Validate(), client.ErrNoAuthor)
+	require.ErrorIs(t, (&client.AuthorOpts{Strategy: "random"}).Validate(), client.ErrUnknownAuthorStrategy)
+}
+
+func TestUploadOpts_AuthorOf(t *testing.T) {
+	entry := getTestEntry()
+	entry.SetAttribute(worklog.AttributeWorker, "bucky-barnes")
+
+	author, err := (&client.UploadOpts{User: "steve-rogers"}).AuthorOf(&entry)
+	require.Nil(t, err)
+	require.Equal(t, "bucky-barnes", author)
+
+	author, err = (&client.UploadOpts{
+		User:   "steve-rogers",
+		Author: &client.AuthorOpts{Strategy: client.AuthorStrategyCurrent},
+	}).AuthorOf(&entry)
+	require.Nil(t, err)
+	require.Equal(t, "steve-rogers", author)
+}
@@ -0,0 +1,209 @@
+package bamboohr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathTimeOffRequests is the API endpoint used to search time off requests.
+	PathTimeOffRequests string = "/api/gateway.php/%s/v1/time_off/requests/"
+
+	// StatusApproved is the status of the approved time off requests.
+	StatusApproved string = "approved"
+	// UnitHours is the unit of the time off requests measured in hours.
+	UnitHours string = "hours"
+)
+
+// Status represents the status of a time off request.
+type Status struct {
+	Status string `json:"status"`
+}
+
+// Type represents the time off type, like "Vacation" or "Sick".
+type Type struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Amount represents the amount of time off requested.
+type Amount struct {
+	Unit   string `json:"unit"`
+	Amount string `json:"amount"`
+}
+
+// Notes represents the notes of the employee and the manager.
+type Notes struct {
+	Employee string `json:"employee"`
+	Manager  string `json:"manager"`
+}
+
+// FetchEntry represents the time off request fetched from BambooHR. Dates maps
+// the days of the request to the amount taken that day, measured in the unit
+// of the request.
+type FetchEntry struct {
+	ID         string            `json:"id"`
+	EmployeeID string            `json:"employeeId"`
+	Status     Status            `json:"status"`
+	Name       string            `json:"name"`
+	Start      string            `json:"start"`
+	End        string            `json:"end"`
+	Type       Type              `json:"type"`
+	Amount     Amount            `json:"amount"`
+	Notes      Notes             `json:"notes"`
+	Dates      map[string]string `json:"dates"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	APIKey  string
+	BaseURL string
+	// Company is the subdomain of the company, like "acme" for
+	// "acme.bamboohr.com".
+	Company string
+	// AbsenceDuration is the duration of a full day time off.
+	AbsenceDuration time.Duration
+}
+
+type bambooHRClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator   client.Authenticator
+	company         string
+	absenceDuration time.Duration
+}
+
+// parseEntry creates an absence entry per day of the time off request.
+func (c *bambooHRClient) parseEntry(entry FetchEntry) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	var days []string
+	for day := range entry.Dates {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		date, err := utils.ParseTimestamp(day, time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := strconv.ParseFloat(entry.Dates[day], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		// Weekends and holidays are listed with zero amount
+		if amount <= 0 {
+			continue
+		}
+
+		duration := time.Duration(amount * float64(c.absenceDuration))
+		if entry.Amount.Unit == UnitHours {
+			duration = time.Duration(amount * float64(time.Hour))
+		}
+
+		entries = append(entries, worklog.Entry{
+			Summary:            entry.Type.Name,
+			Notes:              entry.Notes.Employee,
+			Start:              date,
+			UnbillableDuration: duration,
+			Absence:            worklog.ParseAbsence(entry.Type.Name),
+			Provenance:         worklog.Provenance{SourceIDs: []string{entry.ID}},
+		})
+	}
+
+	return entries, nil
+}
+
+func (c *bambooHRClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	params := map[string]string{
+		"start":  utils.DateFormatISO8601.Format(opts.Start),
+		"end":    utils.DateFormatISO8601.Format(opts.LastDay()),
+		"status": StatusApproved,
+	}
+
+	if opts.User != "" {
+		params["employeeId"] = opts.User
+	}
+
+	searchURL, err := c.URL(fmt.Sprintf(PathTimeOffRequests, c.company), params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     searchURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var fetchedEntries []FetchEntry
+	if err = json.Unmarshal(resp, &fetchedEntries); err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var entries worklog.Entries
+	for _, entry := range fetchedEntries {
+		if entry.Status.Status != StatusApproved {
+			continue
+		}
+
+		parsedEntries, err := c.parseEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		entries = append(entries, parsedEntries...)
+	}
+
+	return entries, nil
+}
+
+// NewFetcher returns a new BambooHR client for fetching approved time off
+// requests as absence entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// BambooHR uses the API key as username and any string as password
+	authenticator, err := client.NewBasicAuth(opts.APIKey, "x")
+	if err != nil {
+		return nil, err
+	}
+
+	absenceDuration := opts.AbsenceDuration
+	if absenceDuration == 0 {
+		absenceDuration = worklog.DefaultAbsenceDuration
+	}
+
+	return &bambooHRClient{
+		authenticator:   authenticator,
+		HTTPClient:      &client.HTTPClient{BaseURL: baseURL},
+		BaseClientOpts:  &opts.BaseClientOpts,
+		company:         opts.Company,
+		absenceDuration: absenceDuration,
+	}, nil
+}
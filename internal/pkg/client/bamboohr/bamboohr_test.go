@@ -0,0 +1,156 @@
+package bamboohr_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/bamboohr"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockServerOpts struct {
+	Path         string
+	Method       string
+	StatusCode   int
+	Username     string
+	Password     string
+	Query        map[string]string
+	ResponseData *[]bamboohr.FetchEntry
+}
+
+func mockServer(t *testing.T, e *mockServerOpts) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, e.Method, r.Method, "API call methods are not matching")
+		require.Equal(t, e.Path, r.URL.Path, "API call URLs are not matching")
+		require.Equal(t, "application/json", r.Header.Get("Accept"))
+
+		username, password, _ := r.BasicAuth()
+		require.Equal(t, e.Username, username, "API call basic auth username mismatch")
+		require.Equal(t, e.Password, password, "API call basic auth password mismatch")
+
+		for key, value := range e.Query {
+			require.Equal(t, value, r.URL.Query().Get(key), "API call query param mismatch")
+		}
+
+		w.WriteHeader(e.StatusCode)
+
+		if e.ResponseData != nil {
+			err := json.NewEncoder(w).Encode(e.ResponseData)
+			require.Nil(t, err, "cannot encode response data")
+		}
+	}))
+}
+
+func newMockServer(t *testing.T, opts *mockServerOpts) *httptest.Server {
+	mockServer := mockServer(t, opts)
+	require.NotNil(t, mockServer, "cannot create mock server")
+	return mockServer
+}
+
+func TestBambooHRClient_FetchEntries(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2021, 11, 1, 0, 0, 0, 0, time.Local)
+
+	expectedEntries := worklog.Entries{
+		{
+			Summary:            "Vacation",
+			Notes:              "Going to the beach",
+			Start:              time.Date(2021, 10, 8, 0, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour * 8,
+			Absence:            worklog.AbsenceVacation,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Summary:            "Vacation",
+			Notes:              "Going to the beach",
+			Start:              time.Date(2021, 10, 11, 0, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour * 4,
+			Absence:            worklog.AbsenceVacation,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Summary:            "Sick",
+			Start:              time.Date(2021, 10, 14, 0, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour*2 + time.Minute*30,
+			Absence:            worklog.AbsenceSick,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+	}
+
+	mockServer := newMockServer(t, &mockServerOpts{
+		Path:       fmt.Sprintf(bamboohr.PathTimeOffRequests, "marvel-studios"),
+		Method:     http.MethodGet,
+		StatusCode: http.StatusOK,
+		Username:   "a-p-i-k-e-y",
+		Password:   "x",
+		Query: map[string]string{
+			"start":      "2021-10-01",
+			"end":        "2021-10-31",
+			"status":     bamboohr.StatusApproved,
+			"employeeId": "42",
+		},
+		ResponseData: &[]bamboohr.FetchEntry{
+			{
+				ID:         "1",
+				EmployeeID: "42",
+				Status:     bamboohr.Status{Status: bamboohr.StatusApproved},
+				Type:       bamboohr.Type{ID: "1", Name: "Vacation"},
+				Amount:     bamboohr.Amount{Unit: "days", Amount: "1.5"},
+				Notes:      bamboohr.Notes{Employee: "Going to the beach"},
+				Dates: map[string]string{
+					"2021-10-08": "1",
+					"2021-10-09": "0",
+					"2021-10-10": "0",
+					"2021-10-11": "0.5",
+				},
+			},
+			{
+				ID:         "2",
+				EmployeeID: "42",
+				Status:     bamboohr.Status{Status: bamboohr.StatusApproved},
+				Type:       bamboohr.Type{ID: "2", Name: "Sick"},
+				Amount:     bamboohr.Amount{Unit: bamboohr.UnitHours, Amount: "2.5"},
+				Dates: map[string]string{
+					"2021-10-14": "2.5",
+				},
+			},
+			{
+				ID:         "3",
+				EmployeeID: "42",
+				Status:     bamboohr.Status{Status: "denied"},
+				Type:       bamboohr.Type{ID: "1", Name: "Vacation"},
+				Amount:     bamboohr.Amount{Unit: "days", Amount: "1"},
+				Dates: map[string]string{
+					"2021-10-20": "1",
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	bambooHRClient, err := bamboohr.NewFetcher(&bamboohr.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		APIKey:  "a-p-i-k-e-y",
+		BaseURL: mockServer.URL,
+		Company: "marvel-studios",
+	})
+	require.Nil(t, err)
+
+	entries, err := bambooHRClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "42",
+		Start: start,
+		End:   end,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	require.Equal(t, expectedEntries, entries, "fetched entries are not matching")
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// CircuitClosed means the calls are let through.
+	CircuitClosed string = "closed"
+	// CircuitOpen means the calls are paused until the cooldown ends.
+	CircuitOpen string = "open"
+	// CircuitHalfOpen means the cooldown ended, and the next call decides if
+	// the circuit closes or opens again.
+	CircuitHalfOpen string = "half-open"
+)
+
+// CircuitState represents the state of a circuit breaker, persisted between
+// runs, so a restart does not reset an open circuit.
+type CircuitState struct {
+	// Failures is the number of consecutive failed calls.
+	Failures int `json:"failures"`
+	// OpenUntil is the end of the cooldown. If zero, the circuit is closed.
+	OpenUntil time.Time `json:"open_until"`
+	// LastError is the message of the last failed call.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Status returns the status of the circuit at the given time, one of
+// CircuitClosed, CircuitOpen and CircuitHalfOpen.
+func (s *CircuitState) Status(now time.Time) string {
+	if s.OpenUntil.IsZero() {
+		return CircuitClosed
+	} else if now.Before(s.OpenUntil) {
+		return CircuitOpen
+	}
+
+	return CircuitHalfOpen
+}
+
+// CircuitBreaker pauses the calls after a number of consecutive failures, so
+// an unavailable service is not flooded by calls failing anyway. After the
+// cooldown, the next call is let through; if it succeeds the circuit closes,
+// otherwise it opens again. CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failures opening the circuit. If
+	// 0, the circuit never opens.
+	Threshold int
+	// Cooldown is the duration the circuit stays open.
+	Cooldown time.Duration
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	state CircuitState
+}
+
+// Wait returns the duration until the calls are let through again. If the
+// circuit is not open, 0 returns.
+func (b *CircuitBreaker) Wait() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state.Status(b.now()) != CircuitOpen {
+		return 0
+	}
+
+	return b.state.OpenUntil.Sub(b.now())
+}
+
+// Record records the outcome of a call. A successful call closes the circuit,
+// while a failed call opens it if the failures reached the threshold.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = CircuitState{}
+		return
+	}
+
+	b.state.Failures++
+	b.state.LastError = err.Error()
+
+	if b.Threshold > 0 && b.state.Failures >= b.Threshold {
+		b.state.OpenUntil = b.now().Add(b.Cooldown)
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker opening after the threshold
+// of consecutive failures for the cooldown, continuing from the given state.
+func NewCircuitBreaker(threshold int, cooldown time.Duration, state CircuitState) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		now:       time.Now,
+		state:     state,
+	}
+}
@@ -0,0 +1,61 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := client.NewCircuitBreaker(2, time.Hour, client.CircuitState{})
+	require.Zero(t, breaker.Wait())
+
+	breaker.Record(errors.New("service unavailable"))
+	require.Zero(t, breaker.Wait())
+
+	state := breaker.State()
+	require.Equal(t, 1, state.Failures)
+	require.Equal(t, client.CircuitClosed, state.Status(time.Now()))
+
+	breaker.Record(errors.New("service unavailable"))
+	require.Greater(t, breaker.Wait(), time.Minute*59)
+	require.LessOrEqual(t, breaker.Wait(), time.Hour)
+
+	state = breaker.State()
+	require.Equal(t, 2, state.Failures)
+	require.Equal(t, "service unavailable", state.LastError)
+	require.Equal(t, client.CircuitOpen, state.Status(time.Now()))
+	require.Equal(t, client.CircuitHalfOpen, state.Status(time.Now().Add(time.Hour*2)))
+
+	breaker.Record(nil)
+	require.Zero(t, breaker.Wait())
+	require.Equal(t, client.CircuitState{}, breaker.State())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	breaker := client.NewCircuitBreaker(2, time.Hour, client.CircuitState{
+		Failures:  2,
+		OpenUntil: time.Now().Add(-time.Minute),
+	})
+
+	require.Zero(t, breaker.Wait())
+
+	// The trial call failed, so the circuit opens again
+	breaker.Record(errors.New("service unavailable"))
+	require.Greater(t, breaker.Wait(), time.Duration(0))
+	require.Equal(t, 3, breaker.State().Failures)
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := client.NewCircuitBreaker(0, time.Hour, client.CircuitState{})
+
+	for i := 0; i < 10; i++ {
+		breaker.Record(errors.New("service unavailable"))
+	}
+
+	require.Zero(t, breaker.Wait())
+	require.Equal(t, 10, breaker.State().Failures)
+}
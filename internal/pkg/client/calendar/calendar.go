@@ -0,0 +1,455 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// ProviderGoogle writes the events to Google Calendar.
+	ProviderGoogle string = "google"
+	// ProviderOutlook writes the events to Outlook calendars using Microsoft
+	// Graph.
+	ProviderOutlook string = "outlook"
+
+	// DefaultGoogleURL is the base URL of the Google Calendar API.
+	DefaultGoogleURL string = "https://www.googleapis.com"
+	// DefaultGoogleTokenURL is the endpoint used to refresh the Google access
+	// tokens.
+	DefaultGoogleTokenURL string = "https://oauth2.googleapis.com/token"
+	// DefaultGoogleCalendarID is the ID of the user's primary calendar.
+	DefaultGoogleCalendarID string = "primary"
+	// DefaultOutlookURL is the base URL of the Microsoft Graph API.
+	DefaultOutlookURL string = "https://graph.microsoft.com"
+	// DefaultOutlookTokenURL is the endpoint used to refresh the Microsoft
+	// access tokens.
+	DefaultOutlookTokenURL string = "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+
+	// PathGoogleEvents is the endpoint used to create the events of a Google
+	// calendar.
+	PathGoogleEvents string = "/calendar/v3/calendars/%s/events"
+	// PathOutlookEvents is the endpoint used to create the events of an
+	// Outlook calendar.
+	PathOutlookEvents string = "/v1.0/me/calendars/%s/events"
+	// PathOutlookDefaultEvents is the endpoint used to create the events of
+	// the user's default Outlook calendar.
+	PathOutlookDefaultEvents string = "/v1.0/me/calendar/events"
+	// PathOutlookCategories is the endpoint used to list and create the
+	// Outlook categories, which set the color of the events.
+	PathOutlookCategories string = "/v1.0/me/outlook/masterCategories"
+
+	// OutlookScope is the scope requested when refreshing Microsoft access
+	// tokens.
+	OutlookScope string = "offline_access Calendars.ReadWrite MailboxSettings.ReadWrite"
+
+	// googleColors is the number of the Google Calendar event colors.
+	googleColors int = 11
+	// outlookColors is the number of the Outlook category color presets.
+	outlookColors int = 25
+)
+
+var (
+	// Providers lists the supported calendar providers.
+	Providers = []string{ProviderGoogle, ProviderOutlook}
+
+	// ErrUnknownProvider returns when the calendar provider is not supported.
+	ErrUnknownProvider = errors.New("unknown calendar provider")
+	// ErrAuthentication returns when the OAuth credentials are missing or
+	// rejected.
+	ErrAuthentication = errors.New("failed to authenticate")
+	// ErrWriteEvents wraps the errors of writing the events.
+	ErrWriteEvents = errors.New("failed to write calendar events")
+)
+
+// TokenResponse represents the response of the OAuth token endpoint.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	// ExpiresIn is the lifetime of the token in seconds.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// GoogleEventTime represents the start or end of a Google Calendar event.
+type GoogleEventTime struct {
+	DateTime string `json:"dateTime"`
+}
+
+// GoogleEvent represents the event created in Google Calendar.
+type GoogleEvent struct {
+	Summary     string          `json:"summary"`
+	Description string          `json:"description"`
+	Start       GoogleEventTime `json:"start"`
+	End         GoogleEventTime `json:"end"`
+	ColorID     string          `json:"colorId,omitempty"`
+}
+
+// OutlookEventBody represents the body of an Outlook event.
+type OutlookEventBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+// OutlookEventTime represents the start or end of an Outlook event.
+type OutlookEventTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// OutlookEvent represents the event created in an Outlook calendar.
+type OutlookEvent struct {
+	Subject    string           `json:"subject"`
+	Body       OutlookEventBody `json:"body"`
+	Start      OutlookEventTime `json:"start"`
+	End        OutlookEventTime `json:"end"`
+	ShowAs     string           `json:"showAs"`
+	Categories []string         `json:"categories,omitempty"`
+}
+
+// OutlookCategory represents an Outlook category. The events having the
+// category are shown in the color of the category.
+type OutlookCategory struct {
+	DisplayName string `json:"displayName"`
+	Color       string `json:"color"`
+}
+
+// OutlookCategoriesResponse represents the response of listing the Outlook
+// categories.
+type OutlookCategoriesResponse struct {
+	Value []OutlookCategory `json:"value"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	Provider string
+	// BaseURL is the base URL of the calendar API. If not set, the URL of the
+	// provider is used.
+	BaseURL string
+	// TokenURL is the endpoint used to refresh the access token. If not set,
+	// the endpoint of the provider is used.
+	TokenURL string
+	// CalendarID is the ID of the calendar the events are written to. If not
+	// set, the primary or default calendar of the user is used.
+	CalendarID   string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+// Writer writes the uploaded entries back to a calendar as events, color-coded
+// by project, so the uploaded time of the days can be reviewed visually.
+type Writer interface {
+	client.CredentialWarmer
+	// WriteEvents creates an event per entry. The events written successfully
+	// are kept even if writing other entries failed.
+	WriteEvents(ctx context.Context, entries worklog.Entries) error
+}
+
+type calendarClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	provider     string
+	tokenURL     string
+	calendarID   string
+	clientID     string
+	clientSecret string
+	refreshToken string
+	auth         *client.RefreshingTokenAuth
+}
+
+// authenticate exchanges the refresh token to an access token.
+func (c *calendarClient) authenticate(ctx context.Context) (*client.AccessToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {c.refreshToken},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+	}
+
+	if c.provider == ProviderOutlook {
+		form.Set("scope", OutlookScope)
+	}
+
+	var tokenResponse TokenResponse
+	err := c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     c.tokenURL,
+		Data:    form,
+		Timeout: c.Timeout,
+		Codec:   &client.FormCodec{},
+	}, &tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuthentication, err)
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return nil, ErrAuthentication
+	}
+
+	token := &client.AccessToken{Token: tokenResponse.AccessToken}
+	if tokenResponse.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Second * time.Duration(tokenResponse.ExpiresIn))
+	}
+
+	return token, nil
+}
+
+func (c *calendarClient) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	return c.auth.WarmUp(ctx, d)
+}
+
+func (c *calendarClient) CredentialsExpireAt() time.Time {
+	return c.auth.ExpiresAt()
+}
+
+// ProjectColor returns the index of the project's color among the given
+// number of colors. The same project always gets the same color.
+func ProjectColor(project string, colors int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(project))
+
+	return int(hash.Sum32() % uint32(colors))
+}
+
+// outlookCategoryColor returns the color preset of the project's category.
+func outlookCategoryColor(project string) string {
+	return fmt.Sprintf("preset%d", ProjectColor(project, outlookColors))
+}
+
+// EventTitle returns the title of the entry's event, like
+// "CPT-2014 Fix the flaky test".
+func EventTitle(entry *worklog.Entry) string {
+	return strings.TrimSpace(entry.Task.Name + " " + entry.Summary)
+}
+
+// EventDescription returns the description of the entry's event, listing the
+// uploaded details of the entry.
+func EventDescription(entry *worklog.Entry) string {
+	var lines []string
+
+	if entry.Project.Name != "" {
+		lines = append(lines, "Project: "+entry.Project.Name)
+	}
+
+	if entry.Client.Name != "" {
+		lines = append(lines, "Client: "+entry.Client.Name)
+	}
+
+	lines = append(lines,
+		"Billable: "+entry.BillableDuration.String(),
+		"Unbillable: "+entry.UnbillableDuration.String(),
+	)
+
+	if entry.Notes != "" && entry.Notes != entry.Summary {
+		lines = append(lines, "", entry.Notes)
+	}
+
+	if len(entry.Links) != 0 {
+		lines = append(lines, "")
+		lines = append(lines, entry.Links...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// EventEnd returns the end of the entry's event.
+func EventEnd(entry *worklog.Entry) time.Time {
+	return entry.Start.Add(entry.BillableDuration + entry.UnbillableDuration)
+}
+
+// newEvent returns the event of the entry in the format of the provider.
+func (c *calendarClient) newEvent(entry *worklog.Entry) interface{} {
+	if c.provider == ProviderGoogle {
+		event := &GoogleEvent{
+			Summary:     EventTitle(entry),
+			Description: EventDescription(entry),
+			Start:       GoogleEventTime{DateTime: entry.Start.Format(time.RFC3339)},
+			End:         GoogleEventTime{DateTime: EventEnd(entry).Format(time.RFC3339)},
+		}
+
+		// The Google Calendar event colors are identified from 1
+		if entry.Project.Name != "" {
+			event.ColorID = fmt.Sprint(ProjectColor(entry.Project.Name, googleColors) + 1)
+		}
+
+		return event
+	}
+
+	event := &OutlookEvent{
+		Subject: EventTitle(entry),
+		Body:    OutlookEventBody{ContentType: "text", Content: EventDescription(entry)},
+		Start:   OutlookEventTime{DateTime: entry.Start.UTC().Format("2006-01-02T15:04:05"), TimeZone: "UTC"},
+		End:     OutlookEventTime{DateTime: EventEnd(entry).UTC().Format("2006-01-02T15:04:05"), TimeZone: "UTC"},
+		// The events are records of the past, so they must not block the
+		// user's availability
+		ShowAs: "free",
+	}
+
+	if entry.Project.Name != "" {
+		event.Categories = []string{entry.Project.Name}
+	}
+
+	return event
+}
+
+// eventsPath returns the path of the endpoint creating the events.
+func (c *calendarClient) eventsPath() string {
+	if c.provider == ProviderGoogle {
+		return fmt.Sprintf(PathGoogleEvents, url.PathEscape(c.calendarID))
+	}
+
+	if c.calendarID == "" {
+		return PathOutlookDefaultEvents
+	}
+
+	return fmt.Sprintf(PathOutlookEvents, url.PathEscape(c.calendarID))
+}
+
+// ensureOutlookCategories creates the missing Outlook categories of the
+// projects, so the events of every project are shown in its own color. The
+// color of the existing categories is not changed.
+func (c *calendarClient) ensureOutlookCategories(ctx context.Context, entries worklog.Entries) error {
+	categoriesURL, err := c.URL(PathOutlookCategories, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	var categories OutlookCategoriesResponse
+	err = c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     categoriesURL,
+		Auth:    c.auth,
+		Timeout: c.Timeout,
+	}, &categories)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for _, category := range categories.Value {
+		existing[category.DisplayName] = true
+	}
+
+	for _, entry := range entries {
+		project := entry.Project.Name
+		if project == "" || existing[project] {
+			continue
+		}
+
+		_, err = c.Call(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodPost,
+			Url:     categoriesURL,
+			Auth:    c.auth,
+			Timeout: c.Timeout,
+			Data:    &OutlookCategory{DisplayName: project, Color: outlookCategoryColor(project)},
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		existing[project] = true
+	}
+
+	return nil
+}
+
+func (c *calendarClient) WriteEvents(ctx context.Context, entries worklog.Entries) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if c.provider == ProviderOutlook {
+		if err := c.ensureOutlookCategories(ctx, entries); err != nil {
+			return fmt.Errorf("%w: %w", ErrWriteEvents, err)
+		}
+	}
+
+	eventsURL, err := c.URL(c.eventsPath(), map[string]string{})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteEvents, err)
+	}
+
+	var writeErrors []error
+	for i := range entries {
+		entry := &entries[i]
+
+		_, err = c.Call(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodPost,
+			Url:     eventsURL,
+			Auth:    c.auth,
+			Timeout: c.Timeout,
+			Data:    c.newEvent(entry),
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		})
+
+		if err != nil {
+			writeErrors = append(writeErrors, fmt.Errorf("%w: %s: %w", ErrWriteEvents, entry.Key(), err))
+		}
+	}
+
+	return errors.Join(writeErrors...)
+}
+
+// NewWriter returns a new calendar client writing the entries as events to
+// the calendar of the provider.
+func NewWriter(opts *ClientOpts) (Writer, error) {
+	var baseURL, tokenURL, calendarID string
+
+	switch opts.Provider {
+	case ProviderGoogle:
+		baseURL, tokenURL, calendarID = DefaultGoogleURL, DefaultGoogleTokenURL, DefaultGoogleCalendarID
+	case ProviderOutlook:
+		baseURL, tokenURL = DefaultOutlookURL, DefaultOutlookTokenURL
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, opts.Provider)
+	}
+
+	if opts.BaseURL != "" {
+		baseURL = opts.BaseURL
+	}
+
+	if opts.TokenURL != "" {
+		tokenURL = opts.TokenURL
+	}
+
+	if opts.CalendarID != "" {
+		calendarID = opts.CalendarID
+	}
+
+	if opts.ClientID == "" || opts.ClientSecret == "" || opts.RefreshToken == "" {
+		return nil, ErrAuthentication
+	}
+
+	parsedBaseURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	calendarClient := &calendarClient{
+		HTTPClient:     &client.HTTPClient{BaseURL: parsedBaseURL},
+		BaseClientOpts: &opts.BaseClientOpts,
+		provider:       opts.Provider,
+		tokenURL:       tokenURL,
+		calendarID:     calendarID,
+		clientID:       opts.ClientID,
+		clientSecret:   opts.ClientSecret,
+		refreshToken:   opts.RefreshToken,
+	}
+
+	calendarClient.auth = client.NewRefreshingTokenAuth("Authorization", "Bearer", calendarClient.authenticate)
+
+	return calendarClient, nil
+}
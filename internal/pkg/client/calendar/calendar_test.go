@@ -0,0 +1,184 @@
+package calendar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/calendar"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const pathToken string = "/token"
+
+func newEntries() worklog.Entries {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+
+	return worklog.Entries{
+		{
+			Client:           worklog.IDNameField{ID: "My Awesome Company", Name: "My Awesome Company"},
+			Project:          worklog.IDNameField{ID: "CPT", Name: "CPT"},
+			Task:             worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"},
+			Summary:          "Fix the flaky test",
+			Start:            start,
+			BillableDuration: time.Hour,
+			Links:            []string{"https://github.com/gabor-boros/minutes/pull/1"},
+		},
+		{
+			Project:            worklog.IDNameField{ID: "Internal", Name: "Internal"},
+			Task:               worklog.IDNameField{ID: "Standup", Name: "Standup"},
+			Start:              start.Add(time.Hour),
+			UnbillableDuration: time.Minute * 15,
+		},
+	}
+}
+
+func newWriter(t *testing.T, provider string, serverURL string) calendar.Writer {
+	writer, err := calendar.NewWriter(&calendar.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Provider:     provider,
+		BaseURL:      serverURL,
+		TokenURL:     serverURL + pathToken,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RefreshToken: "refresh-token",
+	})
+	require.Nil(t, err)
+
+	return writer
+}
+
+func handleToken(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	require.Equal(t, http.MethodPost, r.Method)
+	require.Nil(t, r.ParseForm())
+	require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+	require.Equal(t, "refresh-token", r.PostForm.Get("refresh_token"))
+	require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+	require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(calendar.TokenResponse{AccessToken: "t-o-k-e-n", ExpiresIn: 3600})
+}
+
+func TestCalendarClient_WriteEvents_Google(t *testing.T) {
+	var events []calendar.GoogleEvent
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathToken:
+			handleToken(t, w, r)
+		case "/calendar/v3/calendars/primary/events":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "Bearer t-o-k-e-n", r.Header.Get("Authorization"))
+
+			var event calendar.GoogleEvent
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+			events = append(events, event)
+
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	writer := newWriter(t, calendar.ProviderGoogle, mockServer.URL)
+	require.Nil(t, writer.WriteEvents(context.Background(), newEntries()))
+
+	require.Len(t, events, 2)
+	require.Equal(t, "CPT-2014 Fix the flaky test", events[0].Summary)
+	require.Equal(t, "2021-10-02T09:00:00Z", events[0].Start.DateTime)
+	require.Equal(t, "2021-10-02T10:00:00Z", events[0].End.DateTime)
+	require.Contains(t, events[0].Description, "Client: My Awesome Company")
+	require.Contains(t, events[0].Description, "https://github.com/gabor-boros/minutes/pull/1")
+	require.NotEmpty(t, events[0].ColorID)
+	require.Equal(t, "2021-10-02T10:15:00Z", events[1].End.DateTime)
+	require.NotEqual(t, events[0].ColorID, events[1].ColorID)
+}
+
+func TestCalendarClient_WriteEvents_Outlook(t *testing.T) {
+	var events []calendar.OutlookEvent
+	var createdCategories []calendar.OutlookCategory
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathToken:
+			require.Equal(t, calendar.OutlookScope, r.FormValue("scope"))
+			handleToken(t, w, r)
+		case calendar.PathOutlookCategories:
+			require.Equal(t, "Bearer t-o-k-e-n", r.Header.Get("Authorization"))
+
+			if r.Method == http.MethodGet {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(calendar.OutlookCategoriesResponse{
+					Value: []calendar.OutlookCategory{{DisplayName: "Internal", Color: "preset1"}},
+				})
+				return
+			}
+
+			var category calendar.OutlookCategory
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&category))
+			createdCategories = append(createdCategories, category)
+
+			w.WriteHeader(http.StatusCreated)
+		case calendar.PathOutlookDefaultEvents:
+			require.Equal(t, http.MethodPost, r.Method)
+
+			var event calendar.OutlookEvent
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+			events = append(events, event)
+
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	writer := newWriter(t, calendar.ProviderOutlook, mockServer.URL)
+	require.Nil(t, writer.WriteEvents(context.Background(), newEntries()))
+
+	require.Len(t, createdCategories, 1)
+	require.Equal(t, "CPT", createdCategories[0].DisplayName)
+	require.Regexp(t, `^preset\d+$`, createdCategories[0].Color)
+
+	require.Len(t, events, 2)
+	require.Equal(t, "CPT-2014 Fix the flaky test", events[0].Subject)
+	require.Equal(t, []string{"CPT"}, events[0].Categories)
+	require.Equal(t, calendar.OutlookEventTime{DateTime: "2021-10-02T09:00:00", TimeZone: "UTC"}, events[0].Start)
+	require.Equal(t, "Standup", events[1].Subject)
+	require.Equal(t, []string{"Internal"}, events[1].Categories)
+}
+
+func TestCalendarClient_WriteEvents_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pathToken {
+			handleToken(t, w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer mockServer.Close()
+
+	writer := newWriter(t, calendar.ProviderGoogle, mockServer.URL)
+	err := writer.WriteEvents(context.Background(), newEntries())
+
+	require.ErrorIs(t, err, calendar.ErrWriteEvents)
+	require.ErrorContains(t, err, "CPT:CPT-2014:Fix the flaky test")
+}
+
+func TestNewWriter_Invalid(t *testing.T) {
+	_, err := calendar.NewWriter(&calendar.ClientOpts{Provider: "icloud"})
+	require.ErrorIs(t, err, calendar.ErrUnknownProvider)
+
+	_, err = calendar.NewWriter(&calendar.ClientOpts{Provider: calendar.ProviderGoogle, ClientID: "client-id"})
+	require.ErrorIs(t, err, calendar.ErrAuthentication)
+}
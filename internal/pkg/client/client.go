@@ -10,8 +10,8 @@ import (
 	"net/http"
 	netURL "net/url"
 	"os/exec"
-	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
@@ -111,10 +111,140 @@ func NewTokenAuth(header string, tokenName string, token string) (Authenticator,
 	}, nil
 }
 
+// AccessToken represents a short-living token, like an OAuth access token.
+type AccessToken struct {
+	Token string
+	// ExpiresAt is the time the token expires. If zero, the expiry is unknown
+	// and the token is refreshed only when the service rejects it.
+	ExpiresAt time.Time
+}
+
+// TokenRefreshFunc obtains a new access token, like exchanging the client
+// credentials to an access token.
+type TokenRefreshFunc func(ctx context.Context) (*AccessToken, error)
+
+// Refresher is implemented by the Authenticators using short-living
+// credentials, so the credentials can be refreshed before they expire.
+type Refresher interface {
+	// WarmUp refreshes the credentials if they are not obtained yet or expire
+	// within the given duration.
+	WarmUp(ctx context.Context, d time.Duration) error
+	// Refresh refreshes the credentials regardless of their expiry, like when
+	// the service rejected them.
+	Refresh(ctx context.Context) error
+}
+
+// CredentialWarmer is implemented by the fetchers and uploaders using
+// short-living credentials, so the credentials expiring during a long run can
+// be refreshed before the run starts.
+type CredentialWarmer interface {
+	// WarmUpCredentials refreshes the credentials if they are not obtained yet
+	// or expire within the expected duration of the run.
+	WarmUpCredentials(ctx context.Context, d time.Duration) error
+}
+
+// CredentialInspector is implemented by the fetchers and uploaders using
+// short-living credentials, so the expiry of the credentials can be reported.
+type CredentialInspector interface {
+	// CredentialsExpireAt returns the time the credentials expire. If zero,
+	// the credentials are not obtained yet or their expiry is unknown.
+	CredentialsExpireAt() time.Time
+}
+
+// RefreshingTokenAuth represents the parameters of token based authentication
+// using short-living access tokens, obtained by the refresh function. It is
+// safe for concurrent use.
+type RefreshingTokenAuth struct {
+	Header    string
+	TokenName string
+
+	refresh TokenRefreshFunc
+	now     func() time.Time
+
+	mu    sync.Mutex
+	token *AccessToken
+}
+
+func (a *RefreshingTokenAuth) SetAuthHeader(req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		return
+	}
+
+	(&TokenAuth{Header: a.Header, TokenName: a.TokenName, Token: a.token.Token}).SetAuthHeader(req)
+}
+
+func (a *RefreshingTokenAuth) WarmUp(ctx context.Context, d time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != nil && (a.token.ExpiresAt.IsZero() || a.token.ExpiresAt.After(a.now().Add(d))) {
+		return nil
+	}
+
+	return a.refreshToken(ctx)
+}
+
+func (a *RefreshingTokenAuth) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.refreshToken(ctx)
+}
+
+// ExpiresAt returns the time the access token expires. If no token was obtained
+// yet or its expiry is unknown, zero time returns.
+func (a *RefreshingTokenAuth) ExpiresAt() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		return time.Time{}
+	}
+
+	return a.token.ExpiresAt
+}
+
+func (a *RefreshingTokenAuth) refreshToken(ctx context.Context) error {
+	token, err := a.refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	if token == nil || token.Token == "" {
+		return ErrInvalidTokenAuth
+	}
+
+	a.token = token
+	return nil
+}
+
+// NewRefreshingTokenAuth returns a new RefreshingTokenAuth that implements
+// Authenticator and Refresher. The token is obtained by the refresh function
+// on the first request. If the header name is not set, the standard
+// "Authorization" header will be used.
+func NewRefreshingTokenAuth(header string, tokenName string, refresh TokenRefreshFunc) *RefreshingTokenAuth {
+	if header == "" {
+		header = "Authorization"
+	}
+
+	return &RefreshingTokenAuth{
+		Header:    header,
+		TokenName: tokenName,
+		refresh:   refresh,
+		now:       time.Now,
+	}
+}
+
 // CLIExecuteOpts represents the options that CLI client's Execute method
 // receives.
 type CLIExecuteOpts struct {
 	Timeout time.Duration
+	// Stdin is passed to the command's standard input if set. It should be
+	// used instead of arguments when the input can be large.
+	Stdin io.Reader
 }
 
 // CLIClient implements a client that communicates with a CLI tool.
@@ -131,7 +261,12 @@ func (c *CLIClient) Execute(ctx context.Context, arguments []string, opts *CLIEx
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	return c.CommandCtxExecutor(ctxWithTimeout, c.Command, arguments...).Output() // #nosec G204
+	cmd := c.CommandCtxExecutor(ctxWithTimeout, c.Command, arguments...) // #nosec G204
+	if opts.Stdin != nil {
+		cmd.Stdin = opts.Stdin
+	}
+
+	return cmd.Output()
 }
 
 // HTTPRequestOpts represents the call options for an HTTP request, fired by the
@@ -143,6 +278,18 @@ type HTTPRequestOpts struct {
 	Headers map[string]string
 	Auth    Authenticator
 	Timeout time.Duration
+	// Codec encodes the Data and decodes the response. If not set, the Data is
+	// encoded as JSON and no content headers are set.
+	Codec Codec
+}
+
+// HTTPResponse represents the successful response of an HTTP request, fired by
+// the HTTPClient when `Send` method is called.
+type HTTPResponse struct {
+	StatusCode int
+	// Header is the header of the response, like the pagination headers.
+	Header http.Header
+	Body   []byte
 }
 
 // HTTPClient implements a client that communicates with the server over HTTP.
@@ -178,30 +325,72 @@ func (c *HTTPClient) URL(path string, params map[string]string) (string, error)
 // Call fires an HTTP request with the given method and body (in its body) to
 // the API URL returned by the `URL` method.
 func (c *HTTPClient) Call(ctx context.Context, opts *HTTPRequestOpts) ([]byte, error) {
+	resp, err := c.Send(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Send fires an HTTP request like `Call`, but returns the status code of the
+// response too, like the status code of the created resources. For
+// unsuccessful responses, the status code is available by `StatusCodeOf`.
+func (c *HTTPClient) Send(ctx context.Context, opts *HTTPRequestOpts) (*HTTPResponse, error) {
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	req, err := c.newRequest(ctxWithTimeout, opts)
+	resp, err := c.doRequest(ctxWithTimeout, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.sendRequest(c.Client, req)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	return io.ReadAll(resp.Body)
+	return &HTTPResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// CallAndDecode fires an HTTP request like `Call` and decodes the response
+// body into result. The codec used for decoding is negotiated by the response's
+// content type, so services answering JSON to XML requests can be handled.
+func (c *HTTPClient) CallAndDecode(ctx context.Context, opts *HTTPRequestOpts, result interface{}) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	resp, err := c.doRequest(ctxWithTimeout, opts)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var codec Codec = &JSONCodec{}
+	if opts.Codec != nil {
+		codec = opts.Codec
+	}
+
+	codec, err = negotiateCodec(codec, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(body, result)
 }
 
-// PaginatedFetch fetches the entries from the given paginated API.
-// I helps working with paginated APIs and gives a unified entrypoint
-// to fetch and parse entries.
-// TODO: Write separate unit tests
-func (c *HTTPClient) PaginatedFetch(ctx context.Context, opts *PaginatedFetchOpts) (worklog.Entries, error) {
+// PaginatedFetch fetches the entries from the given paginated API, using the
+// HTTP client to build the URL of the pages. The pages are requested until
+// every entry is fetched, or an empty page returns.
+func PaginatedFetch[T any](ctx context.Context, c *HTTPClient, opts *PaginatedFetchOpts[T]) (worklog.Entries, error) {
 	var entries worklog.Entries
 
 	currentPage := 1
+	fetchedItems := 0
 
 	pageSize := opts.PageSize
 	if pageSize <= 0 {
@@ -219,41 +408,50 @@ func (c *HTTPClient) PaginatedFetch(ctx context.Context, opts *PaginatedFetchOpt
 	}
 
 	for {
-		url, err := c.URL(opts.URL, map[string]string{
-			pageParam:     strconv.Itoa(currentPage),
+		params := map[string]string{
 			pageSizeParam: strconv.Itoa(pageSize),
-		})
+		}
 
+		if opts.OffsetParam != "" {
+			params[opts.OffsetParam] = strconv.Itoa(fetchedItems)
+		} else {
+			params[pageParam] = strconv.Itoa(currentPage)
+		}
+
+		url, err := c.URL(opts.URL, params)
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", ErrFetchEntries, err)
 		}
 
-		rawEntries, paginatedResponse, err := opts.FetchFunc(ctx, url)
+		items, paginatedResponse, err := opts.FetchFunc(ctx, url)
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", ErrFetchEntries, err)
 		}
 
 		// No entries were returned, no need to parse entries
-		if reflect.ValueOf(rawEntries).Len() == 0 {
+		if len(items) == 0 {
 			break
 		}
 
-		parsedEntries, err := opts.ParseFunc(rawEntries, opts.BaseFetchOpts)
+		parsedEntries, err := opts.ParseFunc(items, opts.BaseFetchOpts)
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", ErrFetchEntries, err)
 		}
 
 		entries = append(entries, parsedEntries...)
+		fetchedItems += len(items)
+
+		if paginatedResponse == nil {
+			paginatedResponse = &PaginatedFetchResponse{}
+		}
 
 		if paginatedResponse.EntriesPerPage > 0 {
 			pageSize = paginatedResponse.EntriesPerPage
 		}
 
 		// If the number of entries known, break the loop if all entries are fetched
-		if paginatedResponse.TotalEntries > 0 {
-			if paginatedResponse.TotalEntries-pageSize*currentPage <= 0 {
-				break
-			}
+		if paginatedResponse.TotalEntries > 0 && fetchedItems >= paginatedResponse.TotalEntries {
+			break
 		}
 
 		currentPage++
@@ -262,12 +460,50 @@ func (c *HTTPClient) PaginatedFetch(ctx context.Context, opts *PaginatedFetchOpt
 	return entries, nil
 }
 
+// doRequest creates and sends the request. If the authenticator implements
+// Refresher, the credentials expiring within the timeout are refreshed before
+// sending, and if the credentials are rejected by 401 Unauthorized, they are
+// refreshed and the request is retried once.
+func (c *HTTPClient) doRequest(ctx context.Context, opts *HTTPRequestOpts) (*http.Response, error) {
+	refresher, canRefresh := opts.Auth.(Refresher)
+	if canRefresh {
+		if err := refresher.WarmUp(ctx, opts.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := c.newRequest(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest(c.Client, req)
+	if err == nil || !canRefresh || StatusCodeOf(err) != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if err = refresher.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if req, err = c.newRequest(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	return c.sendRequest(c.Client, req)
+}
+
 func (c *HTTPClient) newRequest(ctx context.Context, opts *HTTPRequestOpts) (*http.Request, error) {
 	var err error
 	var body []byte
 
 	if opts.Data != nil {
-		body, err = json.Marshal(opts.Data)
+		if opts.Codec != nil {
+			body, err = opts.Codec.Marshal(opts.Data)
+		} else {
+			body, err = json.Marshal(opts.Data)
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -278,6 +514,10 @@ func (c *HTTPClient) newRequest(ctx context.Context, opts *HTTPRequestOpts) (*ht
 		return nil, err
 	}
 
+	if opts.Codec != nil {
+		opts.Codec.SetContentHeaders(req)
+	}
+
 	for key, val := range opts.Headers {
 		req.Header.Set(key, val)
 	}
@@ -297,7 +537,7 @@ func (c *HTTPClient) sendRequest(httpClient *http.Client, req *http.Request) (*h
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, &NetworkError{Err: err}
 	}
 
 	// If the response wasn't successful, return an error containing the error code
@@ -308,7 +548,7 @@ func (c *HTTPClient) sendRequest(httpClient *http.Client, req *http.Request) (*h
 			return nil, err
 		}
 
-		return nil, fmt.Errorf("%d: %s", resp.StatusCode, string(errBody))
+		return nil, newStatusError(resp, errBody)
 	}
 
 	return resp, nil
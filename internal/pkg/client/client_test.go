@@ -3,6 +3,7 @@ package client_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,8 +13,10 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -180,6 +183,63 @@ func TestTokenAuth_Invalid(t *testing.T) {
 	require.ErrorIs(t, err, client.ErrInvalidTokenAuth)
 }
 
+func TestRefreshingTokenAuth(t *testing.T) {
+	refreshes := 0
+	expiresAt := time.Now().Add(time.Hour)
+
+	auth := client.NewRefreshingTokenAuth("", "Bearer", func(ctx context.Context) (*client.AccessToken, error) {
+		refreshes++
+		return &client.AccessToken{Token: "token-" + strconv.Itoa(refreshes), ExpiresAt: expiresAt}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "", nil)
+	require.Nil(t, err)
+
+	auth.SetAuthHeader(req)
+	require.Equal(t, "", req.Header.Get("Authorization"))
+	require.True(t, auth.ExpiresAt().IsZero())
+
+	require.Nil(t, auth.WarmUp(context.Background(), time.Minute*10))
+	require.Nil(t, auth.WarmUp(context.Background(), time.Minute*10))
+	require.Equal(t, 1, refreshes)
+	require.Equal(t, expiresAt, auth.ExpiresAt())
+
+	auth.SetAuthHeader(req)
+	require.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+
+	// The token expires within the expected duration
+	require.Nil(t, auth.WarmUp(context.Background(), time.Hour*2))
+	require.Equal(t, 2, refreshes)
+
+	require.Nil(t, auth.Refresh(context.Background()))
+	require.Equal(t, 3, refreshes)
+
+	auth.SetAuthHeader(req)
+	require.Equal(t, "Bearer token-3", req.Header.Get("Authorization"))
+}
+
+func TestRefreshingTokenAuth_UnknownExpiry(t *testing.T) {
+	refreshes := 0
+
+	auth := client.NewRefreshingTokenAuth("", "", func(ctx context.Context) (*client.AccessToken, error) {
+		refreshes++
+		return &client.AccessToken{Token: "token"}, nil
+	})
+
+	require.Nil(t, auth.WarmUp(context.Background(), time.Hour*24))
+	require.Nil(t, auth.WarmUp(context.Background(), time.Hour*24))
+	require.Equal(t, 1, refreshes)
+	require.True(t, auth.ExpiresAt().IsZero())
+}
+
+func TestRefreshingTokenAuth_Invalid(t *testing.T) {
+	auth := client.NewRefreshingTokenAuth("", "", func(ctx context.Context) (*client.AccessToken, error) {
+		return &client.AccessToken{}, nil
+	})
+
+	require.ErrorIs(t, auth.WarmUp(context.Background(), time.Hour), client.ErrInvalidTokenAuth)
+}
+
 // TestExecCommandHelper is a helper test case that will be called by `mockedExecCommand`.
 // This workaround is needed to be able to "mock" system calls.
 func TestExecCommandHelper(t *testing.T) {
@@ -464,6 +524,74 @@ func TestHTTPClient_Call_Auth(t *testing.T) {
 	require.Equal(t, []byte{}, resp)
 }
 
+func TestHTTPClient_Call_RefreshingAuth(t *testing.T) {
+	requests := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// The first token is revoked before its expiry
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+
+	baseURL, err := url.Parse(mockServer.URL)
+	require.Nil(t, err)
+
+	httpClient := client.HTTPClient{BaseURL: baseURL}
+
+	refreshes := 0
+	auth := client.NewRefreshingTokenAuth("", "Bearer", func(ctx context.Context) (*client.AccessToken, error) {
+		refreshes++
+		return &client.AccessToken{Token: "token-" + strconv.Itoa(refreshes), ExpiresAt: time.Now().Add(time.Hour)}, nil
+	})
+
+	_, err = httpClient.Call(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     mockServer.URL,
+		Auth:    auth,
+		Timeout: client.DefaultRequestTimeout,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, 2, refreshes)
+	require.Equal(t, 2, requests)
+}
+
+func TestHTTPClient_Call_RefreshingAuth_Rejected(t *testing.T) {
+	requests := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	baseURL, err := url.Parse(mockServer.URL)
+	require.Nil(t, err)
+
+	httpClient := client.HTTPClient{BaseURL: baseURL}
+
+	auth := client.NewRefreshingTokenAuth("", "Bearer", func(ctx context.Context) (*client.AccessToken, error) {
+		return &client.AccessToken{Token: "token"}, nil
+	})
+
+	_, err = httpClient.Call(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     mockServer.URL,
+		Auth:    auth,
+		Timeout: client.DefaultRequestTimeout,
+	})
+
+	require.Equal(t, client.ErrorKindAuth, client.KindOf(err))
+	require.Equal(t, 2, requests)
+}
+
 func TestHTTPClient_Call_Failure(t *testing.T) {
 	path := "/endpoint"
 	method := http.MethodGet
@@ -497,3 +625,125 @@ func TestHTTPClient_Call_Failure(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func newPaginatedFetchTest(t *testing.T, items []string, pageSizeParam string, pageParam string) (*client.HTTPClient, client.PaginatedFetchFunc[string], *[]url.Values) {
+	baseURL, err := url.Parse("https://example.com")
+	require.Nil(t, err)
+
+	var queries []url.Values
+
+	fetchFunc := func(_ context.Context, reqURL string) ([]string, *client.PaginatedFetchResponse, error) {
+		pageURL, err := url.Parse(reqURL)
+		require.Nil(t, err)
+		require.Equal(t, "/items", pageURL.Path)
+
+		queries = append(queries, pageURL.Query())
+
+		pageSize, err := strconv.Atoi(pageURL.Query().Get(pageSizeParam))
+		require.Nil(t, err)
+
+		index, err := strconv.Atoi(pageURL.Query().Get(pageParam))
+		require.Nil(t, err)
+
+		if pageParam == client.DefaultPageParam {
+			index = (index - 1) * pageSize
+		}
+
+		if index >= len(items) {
+			return []string{}, &client.PaginatedFetchResponse{}, nil
+		}
+
+		end := index + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		return items[index:end], &client.PaginatedFetchResponse{TotalEntries: len(items)}, nil
+	}
+
+	return &client.HTTPClient{BaseURL: baseURL}, fetchFunc, &queries
+}
+
+func parseItems(items []string, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+	for _, item := range items {
+		entries = append(entries, worklog.Entry{Summary: item})
+	}
+
+	return entries, nil
+}
+
+func TestPaginatedFetch(t *testing.T) {
+	httpClient, fetchFunc, queries := newPaginatedFetchTest(t, []string{"a", "b", "c", "d", "e"}, client.DefaultPageSizeParam, client.DefaultPageParam)
+
+	entries, err := client.PaginatedFetch(context.Background(), httpClient, &client.PaginatedFetchOpts[string]{
+		URL:       "/items?user=1",
+		PageSize:  2,
+		FetchFunc: fetchFunc,
+		ParseFunc: parseItems,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, worklog.Entries{{Summary: "a"}, {Summary: "b"}, {Summary: "c"}, {Summary: "d"}, {Summary: "e"}}, entries)
+	require.Len(t, *queries, 3)
+	require.Equal(t, url.Values{"user": {"1"}, "page": {"3"}, "per_page": {"2"}}, (*queries)[2])
+}
+
+func TestPaginatedFetch_Offset(t *testing.T) {
+	httpClient, fetchFunc, queries := newPaginatedFetchTest(t, []string{"a", "b", "c"}, "limit", "offset")
+
+	entries, err := client.PaginatedFetch(context.Background(), httpClient, &client.PaginatedFetchOpts[string]{
+		URL:           "/items",
+		PageSize:      2,
+		PageSizeParam: "limit",
+		OffsetParam:   "offset",
+		FetchFunc:     fetchFunc,
+		ParseFunc:     parseItems,
+	})
+
+	require.Nil(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, []url.Values{
+		{"limit": {"2"}, "offset": {"0"}},
+		{"limit": {"2"}, "offset": {"2"}},
+	}, *queries)
+}
+
+func TestPaginatedFetch_EmptyPage(t *testing.T) {
+	httpClient, _, _ := newPaginatedFetchTest(t, nil, client.DefaultPageSizeParam, client.DefaultPageParam)
+
+	var pages []string
+	entries, err := client.PaginatedFetch(context.Background(), httpClient, &client.PaginatedFetchOpts[string]{
+		URL: "/items",
+		FetchFunc: func(_ context.Context, reqURL string) ([]string, *client.PaginatedFetchResponse, error) {
+			pages = append(pages, reqURL)
+
+			// The total is unknown, hence the pages are fetched until an empty one
+			if len(pages) > 2 {
+				return nil, nil, nil
+			}
+
+			return []string{reqURL}, nil, nil
+		},
+		ParseFunc: parseItems,
+	})
+
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	require.Len(t, pages, 3)
+}
+
+func TestPaginatedFetch_Error(t *testing.T) {
+	httpClient, fetchFunc, _ := newPaginatedFetchTest(t, []string{"a"}, client.DefaultPageSizeParam, client.DefaultPageParam)
+
+	_, err := client.PaginatedFetch(context.Background(), httpClient, &client.PaginatedFetchOpts[string]{
+		URL:       "/items",
+		FetchFunc: fetchFunc,
+		ParseFunc: func(_ []string, _ *client.FetchOpts) (worklog.Entries, error) {
+			return nil, errors.New("invalid item")
+		},
+	})
+
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.EqualError(t, err, "failed to fetch entries: invalid item")
+}
@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"time"
-
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
 	"github.com/gabor-boros/minutes/internal/pkg/utils"
@@ -18,6 +18,30 @@ import (
 const (
 	// PathWorklog is the API endpoint used to search and create worklogs.
 	PathWorklog string = "/api/v1/workspaces/%s/user/%s/time-entries"
+	// PathTimeOffRequests is the time off API endpoint used to search time off
+	// requests.
+	PathTimeOffRequests string = "/v1/workspaces/%s/requests"
+	// PathDetailedReport is the reports API endpoint used to fetch the
+	// detailed report of the workspace.
+	PathDetailedReport string = "/v1/workspaces/%s/reports/detailed"
+	// PathAppDetailedReport is the page of the detailed report in the web app.
+	PathAppDetailedReport string = "/reports/detailed"
+
+	// DefaultAppURL is the base URL of the Clockify web app.
+	DefaultAppURL string = "https://app.clockify.me"
+
+	// TimeOffStatusApproved is the status of the approved time off requests.
+	TimeOffStatusApproved string = "APPROVED"
+	// TimeOffUnitHours is the time unit of the time off requests measured in
+	// hours instead of days.
+	TimeOffUnitHours string = "HOURS"
+
+	// ReportPageSizeParam is the pagination parameter of the report's page
+	// size. Since the report parameters are sent in the request body, the
+	// parameter is only used to pass the page size to the request.
+	ReportPageSizeParam string = "page-size"
+
+	timeOffPageSize int = 50
 )
 
 // Project represents the project assigned to an entry.
@@ -29,12 +53,13 @@ type Project struct {
 
 // Interval represents the Start and End date of an entry.
 type Interval struct {
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
+	Start utils.Timestamp `json:"start"`
+	End   utils.Timestamp `json:"end"`
 }
 
 // FetchEntry represents the entry fetched from Clockify.
 type FetchEntry struct {
+	ID           string                `json:"id"`
 	Description  string                `json:"description"`
 	Billable     bool                  `json:"billable"`
 	Project      Project               `json:"project"`
@@ -43,6 +68,125 @@ type FetchEntry struct {
 	Tags         []worklog.IDNameField `json:"tags"`
 }
 
+// ReportEntry represents the time entry of the detailed report. The report
+// resolves the names of the project, client and task, unlike the time entry
+// API returning their IDs only.
+type ReportEntry struct {
+	ID           string                `json:"_id"`
+	Description  string                `json:"description"`
+	UserID       string                `json:"userId"`
+	Billable     bool                  `json:"billable"`
+	ProjectID    string                `json:"projectId"`
+	ProjectName  string                `json:"projectName"`
+	ClientID     string                `json:"clientId"`
+	ClientName   string                `json:"clientName"`
+	TaskID       string                `json:"taskId"`
+	TaskName     string                `json:"taskName"`
+	TimeInterval Interval              `json:"timeInterval"`
+	Tags         []worklog.IDNameField `json:"tags"`
+}
+
+// FetchEntry converts the report entry to the entry fetched from Clockify, so
+// the entries of the report are parsed the same way.
+func (e *ReportEntry) FetchEntry() FetchEntry {
+	return FetchEntry{
+		ID:          e.ID,
+		Description: e.Description,
+		Billable:    e.Billable,
+		Project: Project{
+			IDNameField: worklog.IDNameField{
+				ID:   e.ProjectID,
+				Name: e.ProjectName,
+			},
+			ClientID:   e.ClientID,
+			ClientName: e.ClientName,
+		},
+		TimeInterval: e.TimeInterval,
+		Task: worklog.IDNameField{
+			ID:   e.TaskID,
+			Name: e.TaskName,
+		},
+		Tags: e.Tags,
+	}
+}
+
+// ReportTotals represents the totals of the detailed report.
+type ReportTotals struct {
+	EntriesCount int `json:"entriesCount"`
+}
+
+// ReportResponse represents the response of the detailed report. The totals
+// may contain null if the report has no entries.
+type ReportResponse struct {
+	Totals      []*ReportTotals `json:"totals"`
+	TimeEntries []ReportEntry   `json:"timeentries"`
+}
+
+// ReportDetailedFilter represents the pagination and sorting of the detailed
+// report.
+type ReportDetailedFilter struct {
+	Page       int    `json:"page"`
+	PageSize   int    `json:"pageSize"`
+	SortColumn string `json:"sortColumn"`
+}
+
+// ReportUsersFilter represents the filter of the users of the detailed report.
+type ReportUsersFilter struct {
+	IDs      []string `json:"ids"`
+	Contains string   `json:"contains"`
+	Status   string   `json:"status"`
+}
+
+// ReportSearchParams represents the parameters used to fetch the detailed
+// report. If no users filter is set, the entries of every user are returned.
+type ReportSearchParams struct {
+	DateRangeStart string               `json:"dateRangeStart"`
+	DateRangeEnd   string               `json:"dateRangeEnd"`
+	DetailedFilter ReportDetailedFilter `json:"detailedFilter"`
+	Users          *ReportUsersFilter   `json:"users,omitempty"`
+	SortOrder      string               `json:"sortOrder"`
+	ExportType     string               `json:"exportType"`
+}
+
+// TimeOffPeriod represents the period of a time off request.
+type TimeOffPeriod struct {
+	Period    Interval `json:"period"`
+	IsHalfDay bool     `json:"isHalfDay"`
+}
+
+// TimeOffStatus represents the status of a time off request.
+type TimeOffStatus struct {
+	StatusType string `json:"statusType"`
+}
+
+// TimeOffRequest represents the time off request fetched from Clockify.
+type TimeOffRequest struct {
+	ID            string        `json:"id"`
+	PolicyName    string        `json:"policyName"`
+	Note          string        `json:"note"`
+	Status        TimeOffStatus `json:"status"`
+	TimeOffPeriod TimeOffPeriod `json:"timeOffPeriod"`
+	TimeUnit      string        `json:"timeUnit"`
+}
+
+// TimeOffSearchResponse represents the paginated response of the time off
+// request search.
+type TimeOffSearchResponse struct {
+	Count    int              `json:"count"`
+	Requests []TimeOffRequest `json:"requests"`
+}
+
+// TimeOffSearchParams represents the parameters used to search time off
+// requests.
+type TimeOffSearchParams struct {
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Statuses []string `json:"statuses"`
+	Users    []string `json:"users,omitempty"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"pageSize"`
+}
+
 // WorklogSearchParams represents the parameters used to filter search results.
 // Hydrated indicates to return the "expanded" search result. Expanded result
 // contains the project, task, and tag details, not just their ID.
@@ -61,25 +205,53 @@ type ClientOpts struct {
 	client.TokenAuth
 	BaseURL   string
 	Workspace string
+	// TimeOffURL is the base URL of the time off API. If set, the approved
+	// time off requests are fetched as absence entries.
+	TimeOffURL string
+	// AbsenceDuration is the duration of a full day time off.
+	AbsenceDuration time.Duration
+	// ReportsURL is the base URL of the reports API. If set, the time entries
+	// are fetched from the detailed report of the workspace, instead of the
+	// time entries of the user.
+	ReportsURL string
+	// AppURL is the base URL of the web app, used to link the detailed report
+	// showing the fetched entries. If not set, no links are recorded.
+	AppURL string
 }
 
 type clockifyClient struct {
 	*client.BaseClientOpts
 	*client.HTTPClient
-	authenticator client.Authenticator
-	workspace     string
+	authenticator   client.Authenticator
+	workspace       string
+	timeOffClient   *client.HTTPClient
+	reportsClient   *client.HTTPClient
+	absenceDuration time.Duration
+	appURL          string
 }
 
-func (c *clockifyClient) parseEntries(rawEntries interface{}, opts *client.FetchOpts) (worklog.Entries, error) {
-	var entries worklog.Entries
-
-	fetchedEntries, ok := rawEntries.([]FetchEntry)
-	if !ok {
-		return nil, fmt.Errorf("%v: %s", client.ErrFetchEntries, "cannot parse returned entries")
+// reportURL returns the link of the detailed report showing the entries of
+// the day the entry started, since the web app has no page per entry. If the
+// app URL is not set, an empty string returns.
+func (c *clockifyClient) reportURL(start time.Time) string {
+	if c.appURL == "" {
+		return ""
 	}
 
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+	params := url.Values{}
+	params.Set("start", utils.DateFormatRFC3339UTC.Format(dayStart.UTC()))
+	params.Set("end", utils.DateFormatRFC3339UTC.Format(dayStart.AddDate(0, 0, 1).Add(-time.Second).UTC()))
+
+	return strings.TrimSuffix(c.appURL, "/") + PathAppDetailedReport + "?" + params.Encode()
+}
+
+func (c *clockifyClient) parseEntries(fetchedEntries []FetchEntry, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
 	for _, entry := range fetchedEntries {
-		billableDuration := entry.TimeInterval.End.Sub(entry.TimeInterval.Start)
+		billableDuration := entry.TimeInterval.End.Sub(entry.TimeInterval.Start.Time)
 		unbillableDuration := time.Duration(0)
 
 		if !entry.Billable {
@@ -102,29 +274,33 @@ func (c *clockifyClient) parseEntries(rawEntries interface{}, opts *client.Fetch
 			},
 			Summary:            entry.Task.Name,
 			Notes:              entry.Description,
-			Start:              entry.TimeInterval.Start,
+			Start:              entry.TimeInterval.Start.Time,
 			BillableDuration:   billableDuration,
 			UnbillableDuration: unbillableDuration,
+			Provenance:         worklog.Provenance{SourceIDs: []string{entry.ID}},
+			TaskHints: &worklog.TaskHints{
+				Summary:     entry.Description,
+				Description: entry.Description,
+				Tags:        entry.Tags,
+			},
 		}
 
+		worklogEntry.AddLinks(utils.ExtractURLs(entry.Description)...)
+		worklogEntry.AddSourceURL(c.reportURL(entry.TimeInterval.Start.Time))
+
 		// If the entry's summary is empty, but we have notes, let's use notes for summary too
 		// See: https://github.com/gabor-boros/minutes/issues/38
 		if worklogEntry.Summary == "" && worklogEntry.Notes != "" {
 			worklogEntry.Summary = worklogEntry.Notes
 		}
 
-		if utils.IsRegexSet(opts.TagsAsTasksRegex) && len(entry.Tags) > 0 {
-			pageEntries := worklogEntry.SplitByTagsAsTasks(entry.Description, opts.TagsAsTasksRegex, entry.Tags)
-			entries = append(entries, pageEntries...)
-		} else {
-			entries = append(entries, worklogEntry)
-		}
+		entries = append(entries, worklogEntry)
 	}
 
 	return entries, nil
 }
 
-func (c *clockifyClient) fetchEntries(ctx context.Context, reqURL string) (interface{}, *client.PaginatedFetchResponse, error) {
+func (c *clockifyClient) fetchEntries(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
 	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
 		Method:  http.MethodGet,
 		Url:     reqURL,
@@ -133,36 +309,249 @@ func (c *clockifyClient) fetchEntries(ctx context.Context, reqURL string) (inter
 	})
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	var fetchedEntries []FetchEntry
 	if err = json.Unmarshal(resp, &fetchedEntries); err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	return fetchedEntries, &client.PaginatedFetchResponse{}, err
 }
 
+// fetchReportEntries fetches a page of the detailed report. The page and page
+// size set by the paginated fetch are sent in the request body.
+func (c *clockifyClient) fetchReportEntries(ctx context.Context, reqURL string, params ReportSearchParams) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+	reportURL, err := url.Parse(reqURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	params.DetailedFilter.Page, err = strconv.Atoi(reportURL.Query().Get(client.DefaultPageParam))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	params.DetailedFilter.PageSize, err = strconv.Atoi(reportURL.Query().Get(ReportPageSizeParam))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	reportURL.RawQuery = ""
+
+	resp, err := c.reportsClient.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     reportURL.String(),
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Data:    &params,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	})
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var reportResponse ReportResponse
+	if err = json.Unmarshal(resp, &reportResponse); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	fetchedEntries := make([]FetchEntry, 0, len(reportResponse.TimeEntries))
+	for i := range reportResponse.TimeEntries {
+		fetchedEntries = append(fetchedEntries, reportResponse.TimeEntries[i].FetchEntry())
+	}
+
+	paginatedResponse := &client.PaginatedFetchResponse{
+		EntriesPerPage: params.DetailedFilter.PageSize,
+	}
+
+	if len(reportResponse.Totals) > 0 && reportResponse.Totals[0] != nil {
+		paginatedResponse.TotalEntries = reportResponse.Totals[0].EntriesCount
+	}
+
+	return fetchedEntries, paginatedResponse, nil
+}
+
+func (c *clockifyClient) fetchReport(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	reportURL, err := c.reportsClient.URL(fmt.Sprintf(PathDetailedReport, c.workspace), map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	params := ReportSearchParams{
+		DateRangeStart: utils.DateFormatRFC3339UTC.Format(opts.Start.UTC()),
+		DateRangeEnd:   utils.DateFormatRFC3339UTC.Format(opts.End.UTC()),
+		DetailedFilter: ReportDetailedFilter{
+			SortColumn: "DATE",
+		},
+		SortOrder:  "ASCENDING",
+		ExportType: "JSON",
+	}
+
+	if opts.User != "" {
+		params.Users = &ReportUsersFilter{
+			IDs:      []string{opts.User},
+			Contains: "CONTAINS",
+			Status:   "ALL",
+		}
+	}
+
+	entries, err := client.PaginatedFetch(ctx, c.reportsClient, &client.PaginatedFetchOpts[FetchEntry]{
+		BaseFetchOpts: opts,
+		URL:           reportURL,
+		PageSizeParam: ReportPageSizeParam,
+		FetchFunc: func(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+			return c.fetchReportEntries(ctx, reqURL, params)
+		},
+		ParseFunc: c.parseEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+func (c *clockifyClient) parseTimeOffRequest(request TimeOffRequest) worklog.Entries {
+	period := request.TimeOffPeriod.Period
+	absence := worklog.ParseAbsence(request.PolicyName)
+
+	// Time off measured in hours is taken within a single day
+	if request.TimeUnit == TimeOffUnitHours {
+		return worklog.Entries{
+			{
+				Summary:            request.PolicyName,
+				Notes:              request.Note,
+				Start:              period.Start.Time,
+				UnbillableDuration: period.End.Sub(period.Start.Time),
+				Absence:            absence,
+				Provenance:         worklog.Provenance{SourceIDs: []string{request.ID}},
+			},
+		}
+	}
+
+	return worklog.NewAbsenceEntries(&worklog.AbsenceOpts{
+		Absence:     absence,
+		Summary:     request.PolicyName,
+		Notes:       request.Note,
+		Start:       period.Start.Time,
+		End:         period.End.Time,
+		DayDuration: c.absenceDuration,
+		HalfDay:     request.TimeOffPeriod.IsHalfDay,
+		SourceID:    request.ID,
+	})
+}
+
+func (c *clockifyClient) fetchTimeOff(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	searchURL, err := c.timeOffClient.URL(fmt.Sprintf(PathTimeOffRequests, c.workspace), map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	searchParams := &TimeOffSearchParams{
+		Start:    utils.DateFormatRFC3339UTC.Format(opts.Start.UTC()),
+		End:      utils.DateFormatRFC3339UTC.Format(opts.End.UTC()),
+		Statuses: []string{TimeOffStatusApproved},
+		Page:     1,
+		PageSize: timeOffPageSize,
+	}
+
+	if opts.User != "" {
+		searchParams.Users = []string{opts.User}
+	}
+
+	var entries worklog.Entries
+
+	for {
+		resp, err := c.timeOffClient.Call(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodPost,
+			Url:     searchURL,
+			Auth:    c.authenticator,
+			Timeout: c.Timeout,
+			Data:    searchParams,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		var searchResponse TimeOffSearchResponse
+		if err = json.Unmarshal(resp, &searchResponse); err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		for _, request := range searchResponse.Requests {
+			if request.Status.StatusType != TimeOffStatusApproved {
+				continue
+			}
+
+			entries = append(entries, c.parseTimeOffRequest(request)...)
+		}
+
+		if len(searchResponse.Requests) < searchParams.PageSize {
+			break
+		}
+
+		searchParams.Page++
+	}
+
+	return entries, nil
+}
+
 func (c *clockifyClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	entries, err := c.fetchTimeEntries(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.timeOffClient == nil {
+		return entries, nil
+	}
+
+	absenceEntries, err := c.fetchTimeOff(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(entries, absenceEntries...), nil
+}
+
+func (c *clockifyClient) fetchTimeEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	if c.reportsClient != nil {
+		return c.fetchReport(ctx, opts)
+	}
+
 	fetchURL, err := c.URL(fmt.Sprintf(PathWorklog, c.workspace, opts.User), map[string]string{
-		"start":       utils.DateFormatRFC3339UTC.Format(opts.Start.Local()),
-		"end":         utils.DateFormatRFC3339UTC.Format(opts.End.Local()),
+		"start":       utils.DateFormatRFC3339UTC.Format(opts.Start.UTC()),
+		"end":         utils.DateFormatRFC3339UTC.Format(opts.End.UTC()),
 		"hydrated":    strconv.FormatBool(true),
 		"in-progress": strconv.FormatBool(false),
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
-	return c.PaginatedFetch(ctx, &client.PaginatedFetchOpts{
+	entries, err := client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[FetchEntry]{
 		BaseFetchOpts: opts,
 		URL:           fetchURL,
 		PageSizeParam: "page-size",
 		FetchFunc:     c.fetchEntries,
 		ParseFunc:     c.parseEntries,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Clockify returns the entries overlapping the period too
+	return opts.FilterEntries(entries), nil
 }
 
 // NewFetcher returns a new Clockify client for fetching entries.
@@ -177,10 +566,34 @@ func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
 		return nil, err
 	}
 
+	var timeOffClient *client.HTTPClient
+	if opts.TimeOffURL != "" {
+		timeOffURL, err := url.Parse(opts.TimeOffURL)
+		if err != nil {
+			return nil, err
+		}
+
+		timeOffClient = &client.HTTPClient{BaseURL: timeOffURL}
+	}
+
+	var reportsClient *client.HTTPClient
+	if opts.ReportsURL != "" {
+		reportsURL, err := url.Parse(opts.ReportsURL)
+		if err != nil {
+			return nil, err
+		}
+
+		reportsClient = &client.HTTPClient{BaseURL: reportsURL}
+	}
+
 	return &clockifyClient{
-		authenticator:  authenticator,
-		HTTPClient:     &client.HTTPClient{BaseURL: baseURL},
-		BaseClientOpts: &opts.BaseClientOpts,
-		workspace:      opts.Workspace,
+		authenticator:   authenticator,
+		HTTPClient:      &client.HTTPClient{BaseURL: baseURL},
+		BaseClientOpts:  &opts.BaseClientOpts,
+		workspace:       opts.Workspace,
+		timeOffClient:   timeOffClient,
+		reportsClient:   reportsClient,
+		absenceDuration: opts.AbsenceDuration,
+		appURL:          opts.AppURL,
 	}, nil
 }
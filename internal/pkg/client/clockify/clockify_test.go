@@ -12,6 +12,7 @@ import (
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
 	"github.com/gabor-boros/minutes/internal/pkg/client/clockify"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/stretchr/testify/require"
 )
@@ -82,6 +83,20 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Links:              []string{"https://app.clockify.me/reports/detailed?end=2021-10-02T23%3A59%3A59Z&start=2021-10-02T00%3A00%3A00Z"},
+			TaskHints: &worklog.TaskHints{
+				Summary:     "Have a coffee with Tony",
+				Description: "Have a coffee with Tony",
+				Tags: []worklog.IDNameField{
+					{ID: "1234", Name: "Coffee"},
+					{ID: "5678", Name: "Meeting"},
+					{ID: "9876", Name: "TASK-1234"},
+				},
+			},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"1"},
+				SourceURLs: []string{"https://app.clockify.me/reports/detailed?end=2021-10-02T23%3A59%3A59Z&start=2021-10-02T00%3A00%3A00Z"},
+			},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -101,6 +116,21 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Links:              []string{"https://app.clockify.me/reports/detailed?end=2021-10-02T23%3A59%3A59Z&start=2021-10-02T00%3A00%3A00Z"},
+			TaskHints: &worklog.TaskHints{
+				Summary:     "Go back for my wallet",
+				Description: "Go back for my wallet",
+				Tags: []worklog.IDNameField{
+					{ID: "1234", Name: "Coffee"},
+					{ID: "5678", Name: "Meeting"},
+					{ID: "9876", Name: "TASK-1234"},
+					{ID: "5432", Name: "TASK-5678"},
+				},
+			},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"2"},
+				SourceURLs: []string{"https://app.clockify.me/reports/detailed?end=2021-10-02T23%3A59%3A59Z&start=2021-10-02T00%3A00%3A00Z"},
+			},
 		},
 	}
 
@@ -113,6 +143,7 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 		RemainingCalls: &remainingCalls,
 		ResponseData: &[]clockify.FetchEntry{
 			{
+				ID:          "1",
 				Description: "Have a coffee with Tony",
 				Billable:    true,
 				Project: clockify.Project{
@@ -124,8 +155,8 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 					ClientName: "My Awesome Company",
 				},
 				TimeInterval: clockify.Interval{
-					Start: start,
-					End:   end,
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
 				},
 				Task: worklog.IDNameField{
 					ID:   "789",
@@ -147,6 +178,7 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 				},
 			},
 			{
+				ID:          "2",
 				Description: "Go back for my wallet",
 				Billable:    false,
 				Project: clockify.Project{
@@ -158,8 +190,8 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 					ClientName: "My Awesome Company",
 				},
 				TimeInterval: clockify.Interval{
-					Start: start,
-					End:   end,
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
 				},
 				Task: worklog.IDNameField{
 					ID:   "789",
@@ -198,6 +230,7 @@ func TestClockifyClient_FetchEntries(t *testing.T) {
 		},
 		BaseURL:   mockServer.URL,
 		Workspace: "marvel-studios",
+		AppURL:    clockify.DefaultAppURL,
 	})
 
 	require.Nil(t, err)
@@ -236,6 +269,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -255,6 +289,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start) / 2,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}, Transformations: []string{"split by tags"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -274,6 +309,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start) / 2,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}, Transformations: []string{"split by tags"}},
 		},
 	}
 
@@ -286,6 +322,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 		RemainingCalls: &remainingCalls,
 		ResponseData: &[]clockify.FetchEntry{
 			{
+				ID:          "1",
 				Description: "Have a coffee with Tony",
 				Billable:    true,
 				Project: clockify.Project{
@@ -297,8 +334,8 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 					ClientName: "My Awesome Company",
 				},
 				TimeInterval: clockify.Interval{
-					Start: start,
-					End:   end,
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
 				},
 				Task: worklog.IDNameField{},
 				Tags: []worklog.IDNameField{
@@ -317,6 +354,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 				},
 			},
 			{
+				ID:          "2",
 				Description: "Go back for my wallet",
 				Billable:    false,
 				Project: clockify.Project{
@@ -328,8 +366,8 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 					ClientName: "My Awesome Company",
 				},
 				TimeInterval: clockify.Interval{
-					Start: start,
-					End:   end,
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
 				},
 				Task: worklog.IDNameField{},
 				Tags: []worklog.IDNameField{
@@ -370,13 +408,13 @@ func TestClockifyClient_FetchEntries_TagsAsTasks(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := clockifyClient.FetchEntries(context.Background(), &client.FetchOpts{
-		User:             "steve-rogers",
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(`^TASK-\d+$`),
+		User:  "steve-rogers",
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
 
@@ -401,6 +439,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks_NoTags(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 	}
 
@@ -413,6 +452,7 @@ func TestClockifyClient_FetchEntries_TagsAsTasks_NoTags(t *testing.T) {
 		RemainingCalls: &remainingCalls,
 		ResponseData: &[]clockify.FetchEntry{
 			{
+				ID:          "1",
 				Description: "Have a coffee with Tony",
 				Billable:    true,
 				Project: clockify.Project{
@@ -424,8 +464,8 @@ func TestClockifyClient_FetchEntries_TagsAsTasks_NoTags(t *testing.T) {
 					ClientName: "My Awesome Company",
 				},
 				TimeInterval: clockify.Interval{
-					Start: start,
-					End:   end,
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
 				},
 				Task: worklog.IDNameField{},
 				Tags: []worklog.IDNameField{},
@@ -449,12 +489,375 @@ func TestClockifyClient_FetchEntries_TagsAsTasks_NoTags(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := clockifyClient.FetchEntries(context.Background(), &client.FetchOpts{
-		User:             "steve-rogers",
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(`^TASK-\d+$`),
+		User:  "steve-rogers",
+		Start: start,
+		End:   end,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestClockifyClient_FetchEntries_TimeOff(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 10, 31, 23, 59, 59, 0, time.UTC)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "t-o-k-e-n", r.Header.Get("X-Api-Key"), "API call auth token mismatch")
+
+		switch r.URL.Path {
+		case fmt.Sprintf(clockify.PathWorklog, "marvel-studios", "steve-rogers"):
+			_ = json.NewEncoder(w).Encode(&[]clockify.FetchEntry{})
+		case fmt.Sprintf(clockify.PathTimeOffRequests, "marvel-studios"):
+			require.Equal(t, http.MethodPost, r.Method)
+
+			var searchParams clockify.TimeOffSearchParams
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&searchParams))
+			require.Equal(t, []string{clockify.TimeOffStatusApproved}, searchParams.Statuses)
+			require.Equal(t, []string{"steve-rogers"}, searchParams.Users)
+
+			_ = json.NewEncoder(w).Encode(&clockify.TimeOffSearchResponse{
+				Count: 3,
+				Requests: []clockify.TimeOffRequest{
+					{
+						ID:         "1",
+						PolicyName: "Annual leave",
+						Status:     clockify.TimeOffStatus{StatusType: clockify.TimeOffStatusApproved},
+						TimeOffPeriod: clockify.TimeOffPeriod{
+							Period: clockify.Interval{
+								Start: utils.Timestamp{Time: time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC)},
+								End:   utils.Timestamp{Time: time.Date(2021, 10, 5, 23, 59, 59, 0, time.UTC)},
+							},
+						},
+						TimeUnit: "DAYS",
+					},
+					{
+						ID:         "2",
+						PolicyName: "Sick leave",
+						Note:       "Flu",
+						Status:     clockify.TimeOffStatus{StatusType: clockify.TimeOffStatusApproved},
+						TimeOffPeriod: clockify.TimeOffPeriod{
+							Period: clockify.Interval{
+								Start: utils.Timestamp{Time: time.Date(2021, 10, 7, 0, 0, 0, 0, time.UTC)},
+								End:   utils.Timestamp{Time: time.Date(2021, 10, 7, 23, 59, 59, 0, time.UTC)},
+							},
+							IsHalfDay: true,
+						},
+						TimeUnit: "DAYS",
+					},
+					{
+						ID:         "3",
+						PolicyName: "Annual leave",
+						Status:     clockify.TimeOffStatus{StatusType: clockify.TimeOffStatusApproved},
+						TimeOffPeriod: clockify.TimeOffPeriod{
+							Period: clockify.Interval{
+								Start: utils.Timestamp{Time: time.Date(2021, 10, 8, 14, 0, 0, 0, time.UTC)},
+								End:   utils.Timestamp{Time: time.Date(2021, 10, 8, 16, 0, 0, 0, time.UTC)},
+							},
+						},
+						TimeUnit: clockify.TimeOffUnitHours,
+					},
+				},
+			})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	clockifyClient, err := clockify.NewFetcher(&clockify.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		TokenAuth: client.TokenAuth{
+			Header: "X-Api-Key",
+			Token:  "t-o-k-e-n",
+		},
+		BaseURL:         mockServer.URL,
+		Workspace:       "marvel-studios",
+		TimeOffURL:      mockServer.URL,
+		AbsenceDuration: time.Hour * 8,
+	})
+	require.Nil(t, err)
+
+	entries, err := clockifyClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "steve-rogers",
+		Start: start,
+		End:   end,
+	})
+	require.Nil(t, err, "cannot fetch entries")
+
+	require.Equal(t, worklog.Entries{
+		{
+			Summary:            "Annual leave",
+			Start:              time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC),
+			UnbillableDuration: time.Hour * 8,
+			Absence:            worklog.AbsenceVacation,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Summary:            "Annual leave",
+			Start:              time.Date(2021, 10, 5, 0, 0, 0, 0, time.UTC),
+			UnbillableDuration: time.Hour * 8,
+			Absence:            worklog.AbsenceVacation,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Summary:            "Sick leave",
+			Notes:              "Flu",
+			Start:              time.Date(2021, 10, 7, 0, 0, 0, 0, time.UTC),
+			UnbillableDuration: time.Hour * 4,
+			Absence:            worklog.AbsenceSick,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+		{
+			Summary:            "Annual leave",
+			Start:              time.Date(2021, 10, 8, 14, 0, 0, 0, time.UTC),
+			UnbillableDuration: time.Hour * 2,
+			Absence:            worklog.AbsenceVacation,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
+		},
+	}, entries)
+}
+
+func TestClockifyClient_FetchEntries_TaskFromDescription(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 10, 2, 23, 59, 59, 0, time.UTC)
+	remainingCalls := 1
+
+	expectedEntries := worklog.Entries{
+		{
+			Client: worklog.IDNameField{
+				ID:   "456",
+				Name: "My Awesome Company",
+			},
+			Project: worklog.IDNameField{
+				ID:   "123",
+				Name: "MARVEL-101",
+			},
+			Task: worklog.IDNameField{
+				ID:   "TASK-1234",
+				Name: "TASK-1234",
+			},
+			Summary:            "TASK-1234 Have a coffee with Tony",
+			Notes:              "TASK-1234 Have a coffee with Tony",
+			Start:              start,
+			BillableDuration:   end.Sub(start),
+			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Client: worklog.IDNameField{
+				ID:   "456",
+				Name: "My Awesome Company",
+			},
+			Project: worklog.IDNameField{
+				ID:   "123",
+				Name: "MARVEL-101",
+			},
+			Task: worklog.IDNameField{
+				ID:   "789",
+				Name: "Meet with Iron Man",
+			},
+			Summary:            "Meet with Iron Man",
+			Notes:              "Go back for my wallet",
+			Start:              start,
+			BillableDuration:   0,
+			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+	}
+
+	mockServer := newMockServer(t, &mockServerOpts{
+		Path:           fmt.Sprintf(clockify.PathWorklog, "marvel-studios", "steve-rogers"),
+		Method:         http.MethodGet,
+		StatusCode:     http.StatusOK,
+		Token:          "t-o-k-e-n",
+		TokenHeader:    "X-Api-Key",
+		RemainingCalls: &remainingCalls,
+		ResponseData: &[]clockify.FetchEntry{
+			{
+				ID:          "1",
+				Description: "TASK-1234 Have a coffee with Tony",
+				Billable:    true,
+				Project: clockify.Project{
+					IDNameField: worklog.IDNameField{
+						ID:   "123",
+						Name: "MARVEL-101",
+					},
+					ClientID:   "456",
+					ClientName: "My Awesome Company",
+				},
+				TimeInterval: clockify.Interval{
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
+				},
+				Tags: []worklog.IDNameField{
+					{
+						ID:   "9876",
+						Name: "TASK-5678",
+					},
+				},
+			},
+			{
+				ID:          "2",
+				Description: "Go back for my wallet",
+				Billable:    false,
+				Project: clockify.Project{
+					IDNameField: worklog.IDNameField{
+						ID:   "123",
+						Name: "MARVEL-101",
+					},
+					ClientID:   "456",
+					ClientName: "My Awesome Company",
+				},
+				TimeInterval: clockify.Interval{
+					Start: utils.Timestamp{Time: start},
+					End:   utils.Timestamp{Time: end},
+				},
+				Task: worklog.IDNameField{
+					ID:   "789",
+					Name: "Meet with Iron Man",
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	clockifyClient, err := clockify.NewFetcher(&clockify.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		TokenAuth: client.TokenAuth{
+			Header: "X-Api-Key",
+			Token:  "t-o-k-e-n",
+		},
+		BaseURL:   mockServer.URL,
+		Workspace: "marvel-studios",
+	})
+
+	require.Nil(t, err)
+
+	entries, err := clockifyClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "steve-rogers",
+		Start: start,
+		End:   end,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionDescription)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestClockifyClient_FetchEntries_Report(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC)
+
+	var expectedEntries worklog.Entries
+	var reportEntries []clockify.ReportEntry
+
+	for i := 1; i <= client.DefaultPageSize+1; i++ {
+		entryStart := start.Add(time.Minute * time.Duration(i))
+		id := fmt.Sprintf("%d", i)
+
+		expectedEntries = append(expectedEntries, worklog.Entry{
+			Client: worklog.IDNameField{
+				ID:   "456",
+				Name: "My Awesome Company",
+			},
+			Project: worklog.IDNameField{
+				ID:   "123",
+				Name: "MARVEL-101",
+			},
+			Task: worklog.IDNameField{
+				ID:   "789",
+				Name: "Meet with Iron Man",
+			},
+			Summary:          "Meet with Iron Man",
+			Notes:            "Have a coffee with Tony",
+			Start:            entryStart,
+			BillableDuration: time.Minute,
+			Provenance:       worklog.Provenance{SourceIDs: []string{id}},
+			TaskHints: &worklog.TaskHints{
+				Summary:     "Have a coffee with Tony",
+				Description: "Have a coffee with Tony",
+			},
+		})
+
+		reportEntries = append(reportEntries, clockify.ReportEntry{
+			ID:          id,
+			Description: "Have a coffee with Tony",
+			UserID:      "steve-rogers",
+			Billable:    true,
+			ProjectID:   "123",
+			ProjectName: "MARVEL-101",
+			ClientID:    "456",
+			ClientName:  "My Awesome Company",
+			TaskID:      "789",
+			TaskName:    "Meet with Iron Man",
+			TimeInterval: clockify.Interval{
+				Start: utils.Timestamp{Time: entryStart},
+				End:   utils.Timestamp{Time: entryStart.Add(time.Minute)},
+			},
+		})
+	}
+
+	var requestedPages []int
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method, "API call methods are not matching")
+		require.Equal(t, fmt.Sprintf(clockify.PathDetailedReport, "marvel-studios"), r.URL.Path, "API call URLs are not matching")
+		require.Empty(t, r.URL.Query(), "API call query params are not empty")
+		require.Equal(t, "t-o-k-e-n", r.Header.Get("X-Api-Key"), "API call auth token mismatch")
+
+		var params clockify.ReportSearchParams
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&params))
+		require.Equal(t, "2021-10-02T00:00:00Z", params.DateRangeStart)
+		require.Equal(t, "2021-10-03T00:00:00Z", params.DateRangeEnd)
+		require.Equal(t, &clockify.ReportUsersFilter{
+			IDs:      []string{"steve-rogers"},
+			Contains: "CONTAINS",
+			Status:   "ALL",
+		}, params.Users)
+		require.Equal(t, client.DefaultPageSize, params.DetailedFilter.PageSize)
+
+		requestedPages = append(requestedPages, params.DetailedFilter.Page)
+
+		pageStart := (params.DetailedFilter.Page - 1) * params.DetailedFilter.PageSize
+		pageEnd := pageStart + params.DetailedFilter.PageSize
+		if pageEnd > len(reportEntries) {
+			pageEnd = len(reportEntries)
+		}
+
+		_ = json.NewEncoder(w).Encode(&clockify.ReportResponse{
+			Totals:      []*clockify.ReportTotals{{EntriesCount: len(reportEntries)}},
+			TimeEntries: reportEntries[pageStart:pageEnd],
+		})
+	}))
+	defer mockServer.Close()
+
+	clockifyClient, err := clockify.NewFetcher(&clockify.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		TokenAuth: client.TokenAuth{
+			Header: "X-Api-Key",
+			Token:  "t-o-k-e-n",
+		},
+		BaseURL:    "http://localhost",
+		Workspace:  "marvel-studios",
+		ReportsURL: mockServer.URL,
+	})
+	require.Nil(t, err)
+
+	entries, err := clockifyClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "steve-rogers",
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
+	require.Equal(t, []int{1, 2}, requestedPages)
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
@@ -0,0 +1,327 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SOAPEnvelopeNamespace is the namespace of the SOAP 1.1 envelope.
+	SOAPEnvelopeNamespace string = "http://schemas.xmlsoap.org/soap/envelope/"
+	// SOAP12EnvelopeNamespace is the namespace of the SOAP 1.2 envelope.
+	SOAP12EnvelopeNamespace string = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+var (
+	// ErrSOAPFault wraps the faults returned in SOAP responses.
+	ErrSOAPFault = errors.New("soap fault")
+	// ErrUnsupportedMediaType returns when the response's content type cannot
+	// be decoded by any codecs.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// Codec is responsible for encoding the request body and decoding the
+// response body of HTTP requests, so the HTTPClient can talk to services not
+// using JSON.
+type Codec interface {
+	// SetContentHeaders sets the content related headers, like the
+	// "Content-Type" and "Accept" headers on the request before the
+	// HTTPClient sends it.
+	SetContentHeaders(req *http.Request)
+	// Handles returns true if the codec can decode the given media type.
+	Handles(mediaType string) bool
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec encodes and decodes JSON bodies. The HTTPClient uses it when no
+// codec is set.
+type JSONCodec struct{}
+
+func (c *JSONCodec) SetContentHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+}
+
+func (c *JSONCodec) Handles(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (c *JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (c *JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// XMLCodec encodes and decodes plain XML bodies.
+type XMLCodec struct{}
+
+func (c *XMLCodec) SetContentHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Accept", "application/xml, text/xml")
+}
+
+func (c *XMLCodec) Handles(mediaType string) bool {
+	return isXMLMediaType(mediaType)
+}
+
+func (c *XMLCodec) Marshal(v interface{}) ([]byte, error) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func (c *XMLCodec) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// FormCodec encodes URL encoded form bodies, like the requests of OAuth token
+// endpoints. The data must be url.Values. Since such services usually answer
+// JSON, the response is decoded by the negotiated codec.
+type FormCodec struct{}
+
+func (c *FormCodec) SetContentHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+}
+
+func (c *FormCodec) Handles(mediaType string) bool {
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+func (c *FormCodec) Marshal(v interface{}) ([]byte, error) {
+	values, ok := v.(url.Values)
+	if !ok {
+		return nil, fmt.Errorf("%w: form body must be url.Values, got %T", ErrUnsupportedMediaType, v)
+	}
+
+	return []byte(values.Encode()), nil
+}
+
+func (c *FormCodec) Unmarshal(data []byte, v interface{}) error {
+	values, ok := v.(*url.Values)
+	if !ok {
+		return fmt.Errorf("%w: form body must be decoded to *url.Values, got %T", ErrUnsupportedMediaType, v)
+	}
+
+	parsed, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	*values = parsed
+	return nil
+}
+
+// SOAPFault represents the fault returned in the body of a SOAP response. Both
+// SOAP 1.1 and SOAP 1.2 faults are parsed.
+type SOAPFault struct {
+	Code   string
+	Reason string
+	Detail string
+}
+
+func (f *SOAPFault) Error() string {
+	return fmt.Sprintf("%v: %s: %s", ErrSOAPFault, f.Code, f.Reason)
+}
+
+func (f *SOAPFault) Unwrap() error {
+	return ErrSOAPFault
+}
+
+// soapFault represents the fault element of a SOAP 1.1 or SOAP 1.2 response.
+type soapFault struct {
+	FaultCode   string `xml:"faultcode"`
+	FaultString string `xml:"faultstring"`
+	FaultDetail struct {
+		Content string `xml:",innerxml"`
+	} `xml:"detail"`
+	Code struct {
+		Value string `xml:"Value"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+	Detail struct {
+		Content string `xml:",innerxml"`
+	} `xml:"Detail"`
+}
+
+func (f *soapFault) toSOAPFault() *SOAPFault {
+	fault := &SOAPFault{
+		Code:   f.FaultCode,
+		Reason: f.FaultString,
+		Detail: strings.TrimSpace(f.FaultDetail.Content),
+	}
+
+	if fault.Code == "" {
+		fault.Code = f.Code.Value
+	}
+
+	if fault.Reason == "" {
+		fault.Reason = f.Reason.Text
+	}
+
+	if fault.Detail == "" {
+		fault.Detail = strings.TrimSpace(f.Detail.Content)
+	}
+
+	return fault
+}
+
+// soapEnvelope represents the envelope of a SOAP response. The namespace of
+// the elements is not checked, so both SOAP versions are decoded.
+type soapEnvelope struct {
+	Body struct {
+		Fault   *soapFault `xml:"Fault"`
+		Content []byte     `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// soapRequestEnvelope represents the envelope of a SOAP request. The header
+// and the body are marshalled in advance.
+type soapRequestEnvelope struct {
+	XMLName   xml.Name `xml:"soapenv:Envelope"`
+	Namespace string   `xml:"xmlns:soapenv,attr"`
+	Header    *struct {
+		Content []byte `xml:",innerxml"`
+	} `xml:"soapenv:Header,omitempty"`
+	Body struct {
+		Content []byte `xml:",innerxml"`
+	} `xml:"soapenv:Body"`
+}
+
+// SOAPCodec wraps the request body in a SOAP envelope and unwraps the content
+// of the response's body. If the response contains a fault, the fault returns
+// as a SOAPFault.
+type SOAPCodec struct {
+	// Action is the SOAP action of the operation. For SOAP 1.1 it is sent in
+	// the "SOAPAction" header, for SOAP 1.2 it is part of the content type.
+	Action string
+	// Header is marshalled into the header of the envelope if set, like the
+	// token passports required by some services.
+	Header interface{}
+	// UseSOAP12 sets the codec to use SOAP 1.2 instead of SOAP 1.1.
+	UseSOAP12 bool
+}
+
+func (c *SOAPCodec) SetContentHeaders(req *http.Request) {
+	if c.UseSOAP12 {
+		contentType := "application/soap+xml; charset=utf-8"
+		if c.Action != "" {
+			contentType += fmt.Sprintf("; action=%q", c.Action)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", "application/soap+xml")
+		return
+	}
+
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("Accept", "text/xml")
+	req.Header.Set("SOAPAction", fmt.Sprintf("%q", c.Action))
+}
+
+func (c *SOAPCodec) Handles(mediaType string) bool {
+	return isXMLMediaType(mediaType)
+}
+
+func (c *SOAPCodec) Marshal(v interface{}) ([]byte, error) {
+	envelope := soapRequestEnvelope{Namespace: SOAPEnvelopeNamespace}
+	if c.UseSOAP12 {
+		envelope.Namespace = SOAP12EnvelopeNamespace
+	}
+
+	if c.Header != nil {
+		header, err := xml.Marshal(c.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		envelope.Header = &struct {
+			Content []byte `xml:",innerxml"`
+		}{Content: header}
+	}
+
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Body.Content = body
+
+	data, err := xml.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+func (c *SOAPCodec) Unmarshal(data []byte, v interface{}) error {
+	var envelope soapEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	if envelope.Body.Fault != nil {
+		return envelope.Body.Fault.toSOAPFault()
+	}
+
+	if v == nil || len(bytes.TrimSpace(envelope.Body.Content)) == 0 {
+		return nil
+	}
+
+	return xml.Unmarshal(envelope.Body.Content, v)
+}
+
+// parseSOAPFault returns the fault of the SOAP response body. If the body is
+// not a SOAP fault, nil returns.
+func parseSOAPFault(body []byte) *SOAPFault {
+	var envelope soapEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil || envelope.Body.Fault == nil {
+		return nil
+	}
+
+	return envelope.Body.Fault.toSOAPFault()
+}
+
+// isXMLMediaType returns true if the media type is an XML based media type.
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// negotiateCodec returns the codec used to decode the response of the given
+// content type. The request's codec is preferred; if it cannot decode the
+// media type, the JSON or XML codec is used. If no codecs can decode the
+// media type, an ErrUnsupportedMediaType error returns.
+func negotiateCodec(codec Codec, contentType string) (Codec, error) {
+	if contentType == "" {
+		return codec, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	for _, candidate := range []Codec{codec, &JSONCodec{}, &XMLCodec{}} {
+		if candidate.Handles(mediaType) {
+			return candidate, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
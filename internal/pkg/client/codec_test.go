@@ -0,0 +1,192 @@
+package client_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+type addTimeEntry struct {
+	XMLName xml.Name `xml:"urn:time addTimeEntry"`
+	Hours   float64  `xml:"hours"`
+}
+
+type addTimeEntryResponse struct {
+	ID string `xml:"id"`
+}
+
+func TestSOAPCodec_Marshal(t *testing.T) {
+	codec := &client.SOAPCodec{
+		Header: struct {
+			XMLName xml.Name `xml:"passport"`
+			Token   string   `xml:"token"`
+		}{Token: "secret"},
+	}
+
+	data, err := codec.Marshal(addTimeEntry{Hours: 1.5})
+	require.Nil(t, err)
+	require.Equal(t, xml.Header+
+		`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">`+
+		`<soapenv:Header><passport><token>secret</token></passport></soapenv:Header>`+
+		`<soapenv:Body><addTimeEntry xmlns="urn:time"><hours>1.5</hours></addTimeEntry></soapenv:Body>`+
+		`</soapenv:Envelope>`, string(data))
+}
+
+func TestHTTPClient_CallAndDecode_SOAP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "text/xml; charset=utf-8", r.Header.Get("Content-Type"))
+		require.Equal(t, `"add"`, r.Header.Get("SOAPAction"))
+
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Contains(t, string(body), "<hours>2</hours>")
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+			`<addTimeEntryResponse><id>123</id></addTimeEntryResponse></s:Body></s:Envelope>`))
+	}))
+	defer server.Close()
+
+	var resp addTimeEntryResponse
+	httpClient := client.HTTPClient{}
+	err := httpClient.CallAndDecode(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     server.URL,
+		Data:    addTimeEntry{Hours: 2},
+		Timeout: client.DefaultRequestTimeout,
+		Codec:   &client.SOAPCodec{Action: "add"},
+	}, &resp)
+
+	require.Nil(t, err)
+	require.Equal(t, "123", resp.ID)
+}
+
+func TestHTTPClient_CallAndDecode_SOAPFault(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+		body       string
+	}{
+		"soap 1.1": {
+			statusCode: http.StatusInternalServerError,
+			body: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>` +
+				`<faultcode>s:Client</faultcode><faultstring>Invalid hours</faultstring></s:Fault></s:Body></s:Envelope>`,
+		},
+		"soap 1.2": {
+			statusCode: http.StatusOK,
+			body: `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>` +
+				`<env:Code><env:Value>s:Client</env:Value></env:Code><env:Reason><env:Text>Invalid hours</env:Text></env:Reason>` +
+				`</env:Fault></env:Body></env:Envelope>`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/soap+xml")
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			httpClient := client.HTTPClient{}
+			err := httpClient.CallAndDecode(context.Background(), &client.HTTPRequestOpts{
+				Method:  http.MethodPost,
+				Url:     server.URL,
+				Data:    addTimeEntry{Hours: 2},
+				Timeout: client.DefaultRequestTimeout,
+				Codec:   &client.SOAPCodec{UseSOAP12: true},
+			}, &addTimeEntryResponse{})
+
+			require.ErrorContains(t, err, "s:Client: Invalid hours")
+		})
+	}
+}
+
+func TestHTTPClient_CallAndDecode_Negotiation(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		expectedErr error
+	}{
+		"json response to xml request": {
+			contentType: "application/json",
+			body:        `{"id":"123"}`,
+		},
+		"xml response": {
+			contentType: "application/xml",
+			body:        `<response><id>123</id></response>`,
+		},
+		"unsupported media type": {
+			contentType: "text/html",
+			body:        `<html></html>`,
+			expectedErr: client.ErrUnsupportedMediaType,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "application/xml, text/xml", r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", test.contentType)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			var resp struct {
+				ID string `json:"id" xml:"id"`
+			}
+
+			httpClient := client.HTTPClient{}
+			err := httpClient.CallAndDecode(context.Background(), &client.HTTPRequestOpts{
+				Method:  http.MethodGet,
+				Url:     server.URL,
+				Timeout: client.DefaultRequestTimeout,
+				Codec:   &client.XMLCodec{},
+			}, &resp)
+
+			if test.expectedErr != nil {
+				require.True(t, errors.Is(err, test.expectedErr))
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, "123", resp.ID)
+		})
+	}
+}
+
+func TestHTTPClient_CallAndDecode_Form(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"access_token":"secret"}`))
+	}))
+	defer server.Close()
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	httpClient := client.HTTPClient{}
+	err := httpClient.CallAndDecode(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     server.URL,
+		Data:    url.Values{"grant_type": {"refresh_token"}},
+		Timeout: client.DefaultRequestTimeout,
+		Codec:   &client.FormCodec{},
+	}, &resp)
+
+	require.Nil(t, err)
+	require.Equal(t, "secret", resp.AccessToken)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+var (
+	// ErrCorrectionUnsupported returns when the uploader cannot change the
+	// worklogs uploaded before, hence it cannot apply the corrections.
+	ErrCorrectionUnsupported = errors.New("target does not support corrections")
+	// ErrCorrectedWorklogNotFound returns when no worklog uploaded before is
+	// found for a negative correction, or the worklogs found are shorter than
+	// the correction.
+	ErrCorrectedWorklogNotFound = errors.New("corrected worklog not found")
+)
+
+// Corrector is implemented by the uploaders able to change the worklogs
+// uploaded before, so the mistakes of the past can be fixed by corrections
+// through the normal sync. The worklogs replaced by the corrections are updated
+// or deleted, and the negative corrections are subtracted from a worklog of the
+// same task on the same day.
+type Corrector interface {
+	// CorrectEntries applies the corrections on the target, sending one
+	// result per entry to the channel, like UploadEntries.
+	CorrectEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *UploadOpts)
+}
+
+// SplitCorrections returns the entries to upload and the corrections among the
+// entries separately, keeping their order.
+func SplitCorrections(entries worklog.Entries) (worklog.Entries, worklog.Entries) {
+	var uploads worklog.Entries
+	var corrections worklog.Entries
+
+	for _, entry := range entries {
+		if entry.IsCorrection() {
+			corrections = append(corrections, entry)
+		} else {
+			uploads = append(uploads, entry)
+		}
+	}
+
+	return uploads, corrections
+}
+
+// UploadWithCorrections uploads the entries by the uploader, and applies the
+// corrections among them if the uploader is a Corrector. Otherwise, every
+// correction fails with ErrCorrectionUnsupported. One result per entry is sent
+// to the channel.
+func UploadWithCorrections(ctx context.Context, uploader Uploader, entries worklog.Entries, errChan chan error, opts *UploadOpts) {
+	uploads, corrections := SplitCorrections(entries)
+
+	if len(uploads) != 0 {
+		uploader.UploadEntries(ctx, uploads, errChan, opts)
+	}
+
+	if len(corrections) == 0 {
+		return
+	}
+
+	corrector, ok := uploader.(Corrector)
+	if !ok {
+		for _, entry := range corrections {
+			errChan <- NewEntryError(entry, fmt.Errorf("%w: %w", ErrUploadEntries, ErrCorrectionUnsupported))
+		}
+
+		return
+	}
+
+	corrector.CorrectEntries(ctx, corrections, errChan, opts)
+}
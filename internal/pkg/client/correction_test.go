@@ -0,0 +1,106 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCorrectorUploader struct {
+	recordingUploader
+	corrections worklog.Entries
+}
+
+func (u *mockCorrectorUploader) CorrectEntries(_ context.Context, entries worklog.Entries, errChan chan error, _ *client.UploadOpts) {
+	u.corrections = append(u.corrections, entries...)
+
+	for range entries {
+		errChan <- nil
+	}
+}
+
+func getTestCorrections() worklog.Entries {
+	entries := worklog.Entries{getTestEntry(), getTestEntry(), getTestEntry()}
+	entries[1].BillableDuration = -time.Hour
+	entries[2].Replaces = "123"
+
+	return entries
+}
+
+// collectErrors returns the errors of the results sent to the channel.
+func collectErrors(errChan chan error) []error {
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func TestSplitCorrections(t *testing.T) {
+	entries := getTestCorrections()
+
+	uploads, corrections := client.SplitCorrections(entries)
+	require.Equal(t, worklog.Entries{entries[0]}, uploads)
+	require.Equal(t, worklog.Entries{entries[1], entries[2]}, corrections)
+}
+
+func TestUploadWithCorrections(t *testing.T) {
+	entries := getTestCorrections()
+	uploader := &mockCorrectorUploader{}
+
+	errChan := make(chan error, len(entries))
+	client.UploadWithCorrections(context.Background(), uploader, entries, errChan, &client.UploadOpts{})
+
+	require.Empty(t, collectErrors(errChan))
+	require.Equal(t, worklog.Entries{entries[0]}, uploader.entries)
+	require.Equal(t, worklog.Entries{entries[1], entries[2]}, uploader.corrections)
+}
+
+func TestUploadWithCorrections_Unsupported(t *testing.T) {
+	entries := getTestCorrections()
+	uploader := &recordingUploader{}
+
+	errChan := make(chan error, len(entries))
+	client.UploadWithCorrections(context.Background(), uploader, entries, errChan, &client.UploadOpts{})
+
+	errs := collectErrors(errChan)
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], client.ErrCorrectionUnsupported)
+	require.Equal(t, entries[1], *client.EntryOf(errs[0]))
+	require.Equal(t, worklog.Entries{entries[0]}, uploader.entries)
+}
+
+func TestRouter_CorrectEntries(t *testing.T) {
+	tempoUploader := &mockCorrectorUploader{}
+	csvUploader := &recordingUploader{}
+
+	router := &client.Router{
+		DefaultTarget: "tempo",
+		Default:       tempoUploader,
+		Routes:        map[string]client.Uploader{"csvfile": csvUploader},
+	}
+
+	entries := getTestCorrections()
+	entries[0].Summary = "Fix the bug #target:csvfile"
+	entries[0].Replaces = "456"
+
+	errChan := make(chan error, len(entries))
+	client.UploadWithCorrections(context.Background(), router, entries, errChan, &client.UploadOpts{})
+
+	errs := collectErrors(errChan)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrCorrectionUnsupported)
+	require.Equal(t, "Fix the bug", client.EntryOf(errs[0]).Summary)
+
+	require.Empty(t, csvUploader.entries)
+	require.Equal(t, worklog.Entries{entries[1], entries[2]}, tempoUploader.corrections)
+}
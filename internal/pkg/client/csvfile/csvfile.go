@@ -0,0 +1,534 @@
+package csvfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// ColumnDate is the start date of the entry, without time of the day.
+	ColumnDate string = "date"
+	// ColumnStart is the start date and time of the entry.
+	ColumnStart string = "start"
+	// ColumnEnd is the end date and time of the entry.
+	ColumnEnd string = "end"
+	// ColumnClient is the client name of the entry.
+	ColumnClient string = "client"
+	// ColumnProject is the project name of the entry.
+	ColumnProject string = "project"
+	// ColumnTask is the task name of the entry.
+	ColumnTask string = "task"
+	// ColumnSummary is the summary of the entry.
+	ColumnSummary string = "summary"
+	// ColumnNotes is the notes of the entry.
+	ColumnNotes string = "notes"
+	// ColumnComment is the comment rendered by the comment template.
+	ColumnComment string = "comment"
+	// ColumnBillable is the billable duration of the entry.
+	ColumnBillable string = "billable"
+	// ColumnUnbillable is the unbillable duration of the entry.
+	ColumnUnbillable string = "unbillable"
+	// ColumnDuration is the total time spent on the entry.
+	ColumnDuration string = "duration"
+	// ColumnAbsence is the kind of absence, if the entry stands for one.
+	ColumnAbsence string = "absence"
+	// ColumnLinks is the space separated list of the entry's links.
+	ColumnLinks string = "links"
+	// ColumnClassification is the cost classification of the entry, like
+	// "CAPEX" or "OPEX".
+	ColumnClassification string = "classification"
+	// ColumnCostCenter is the name of the cost center the entry's project
+	// belongs to.
+	ColumnCostCenter string = "cost-center"
+	// ColumnAuthor is the user the entry is uploaded in the name of, resolved
+	// by the author strategy. When reading, it sets the worker of the entry.
+	ColumnAuthor string = "author"
+	// ColumnAttributePrefix is the prefix of the columns containing an
+	// attribute of the entry, followed by the attribute name, like
+	// "attributes.jsm.status".
+	ColumnAttributePrefix string = "attributes."
+
+	// DurationFormatDecimal formats the durations as decimal hours, like "1.5".
+	DurationFormatDecimal string = "decimal"
+	// DurationFormatClock formats the durations as hours and minutes, like
+	// "1:30".
+	DurationFormatClock string = "clock"
+
+	// DurationUnitHours, DurationUnitMinutes and DurationUnitSeconds are the
+	// units of the read durations given as plain numbers, like "90" minutes.
+	DurationUnitHours   string = "hours"
+	DurationUnitMinutes string = "minutes"
+	DurationUnitSeconds string = "seconds"
+
+	// DefaultDelimiter is the default field delimiter of the CSV file.
+	DefaultDelimiter rune = ','
+	// DefaultDecimalPrecision is the default number of decimals used by the
+	// decimal duration format.
+	DefaultDecimalPrecision int = 2
+	// UnassignedCostCenter is the file name suffix of the entries not
+	// belonging to any cost center when the file is split by cost centers.
+	UnassignedCostCenter string = "unassigned"
+)
+
+var (
+	// Columns lists the available columns.
+	Columns = []string{
+		ColumnDate,
+		ColumnStart,
+		ColumnEnd,
+		ColumnClient,
+		ColumnProject,
+		ColumnTask,
+		ColumnSummary,
+		ColumnNotes,
+		ColumnComment,
+		ColumnBillable,
+		ColumnUnbillable,
+		ColumnDuration,
+		ColumnAbsence,
+		ColumnLinks,
+		ColumnClassification,
+		ColumnCostCenter,
+		ColumnAuthor,
+	}
+
+	// DefaultColumns lists the columns written if no columns are configured.
+	DefaultColumns = []Column{
+		{Name: ColumnDate, Header: ColumnDate},
+		{Name: ColumnClient, Header: ColumnClient},
+		{Name: ColumnProject, Header: ColumnProject},
+		{Name: ColumnTask, Header: ColumnTask},
+		{Name: ColumnSummary, Header: ColumnSummary},
+		{Name: ColumnBillable, Header: ColumnBillable},
+		{Name: ColumnUnbillable, Header: ColumnUnbillable},
+	}
+
+	// DurationFormats lists the available duration formats.
+	DurationFormats = []string{DurationFormatDecimal, DurationFormatClock}
+
+	// DurationUnits lists the available units of the plain number durations.
+	DurationUnits = []string{DurationUnitHours, DurationUnitMinutes, DurationUnitSeconds}
+
+	// ErrUnknownColumn returns when a column is not part of the Columns.
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrUnknownDurationFormat returns when a duration format is not part of
+	// the DurationFormats.
+	ErrUnknownDurationFormat = errors.New("unknown duration format")
+	// ErrUnknownDurationUnit returns when a duration unit is not part of the
+	// DurationUnits.
+	ErrUnknownDurationUnit = errors.New("unknown duration unit")
+	// ErrInvalidDelimiter returns when the delimiter cannot be used to
+	// separate CSV fields.
+	ErrInvalidDelimiter = errors.New("invalid delimiter")
+)
+
+// Column represents a column of the CSV file.
+type Column struct {
+	// Name is one of the available Columns.
+	Name string
+	// Header is the column's text in the header row.
+	Header string
+}
+
+// String returns the column in "name" or "name:header" format, as parsed by
+// ParseColumn.
+func (c Column) String() string {
+	if c.Header == "" || c.Header == c.Name {
+		return c.Name
+	}
+
+	return c.Name + ":" + c.Header
+}
+
+// ParseColumn parses the column in "name" or "name:header" format. If no header
+// is given, the name is used as header.
+func ParseColumn(rawColumn string) (Column, error) {
+	name, header, hasHeader := strings.Cut(rawColumn, ":")
+	name = strings.TrimSpace(name)
+
+	isKnown := strings.HasPrefix(name, ColumnAttributePrefix) && len(name) > len(ColumnAttributePrefix)
+	for _, column := range Columns {
+		if column == name {
+			isKnown = true
+			break
+		}
+	}
+
+	if !isKnown {
+		return Column{}, fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+	}
+
+	if !hasHeader {
+		header = name
+	}
+
+	return Column{Name: name, Header: header}, nil
+}
+
+// ParseColumns parses every column by ParseColumn.
+func ParseColumns(rawColumns []string) ([]Column, error) {
+	var columns []Column
+
+	for _, rawColumn := range rawColumns {
+		column, err := ParseColumn(rawColumn)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// ParseDelimiter returns the single character delimiter. The "\t" escape
+// sequence is accepted for tab separated files.
+func ParseDelimiter(rawDelimiter string) (rune, error) {
+	if rawDelimiter == `\t` {
+		return '\t', nil
+	}
+
+	delimiter, size := utf8.DecodeRuneInString(rawDelimiter)
+	if size == 0 || size != len(rawDelimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDelimiter, rawDelimiter)
+	}
+
+	return delimiter, nil
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Since the CSV file is read and written locally, the timeout is not used.
+type ClientOpts struct {
+	client.BaseClientOpts
+	// Path is the path of the read or written CSV file. The written file is
+	// overwritten if it exists.
+	Path string
+	// Columns lists the written columns in order. If empty, DefaultColumns
+	// are written.
+	Columns []Column
+	// OmitHeader indicates to not write the header row.
+	OmitHeader bool
+	// Delimiter is the field delimiter. If not set, DefaultDelimiter is used.
+	Delimiter rune
+	// DurationFormat is one of the DurationFormats. If not set,
+	// DurationFormatDecimal is used.
+	DurationFormat string
+	// DecimalPrecision is the number of decimals of the decimal durations. If
+	// not set, DefaultDecimalPrecision is used.
+	DecimalPrecision int
+	// Locale sets the format of the dates and decimal numbers. If not set, the
+	// default locale is used.
+	Locale *locale.Locale
+	// DateFormat and DateTimeFormat are the Go layouts of the dates and date
+	// times, like "01/02/2006" and "01/02/2006 3:04 PM", overriding the
+	// formats of the Locale. If not set, the formats of the Locale are used.
+	DateFormat     string
+	DateTimeFormat string
+	// DurationUnit is the unit of the read durations given as plain numbers,
+	// one of the DurationUnits. If not set, DurationUnitHours is used.
+	DurationUnit string
+	// Strict indicates to fail reading the file if any row is invalid,
+	// instead of skipping the invalid rows.
+	Strict bool
+	// RejectsPath is the path of the file the invalid rows are written to,
+	// extended by the error of the row. If empty, no rejects file is written.
+	RejectsPath string
+	// CostCenters lists the compiled cost centers, used by the ColumnCostCenter
+	// and to split the written file.
+	CostCenters []worklog.CostCenter
+	// SplitByCostCenter indicates to write one file per cost center instead of
+	// one file. The files are named after the Path, extended by the cost
+	// center, like "entries-CC-1000.csv".
+	SplitByCostCenter bool
+}
+
+type csvClient struct {
+	*client.BaseClientOpts
+	client.DefaultUploader
+	opts ClientOpts
+	// readRecords reads the rows of the file if it is not a CSV file.
+	readRecords RecordReader
+}
+
+func (c *csvClient) formatDuration(d time.Duration) string {
+	if c.opts.DurationFormat == DurationFormatClock {
+		minutes := int64(math.Round(d.Minutes()))
+		return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+	}
+
+	return c.opts.Locale.FormatHours(d, c.opts.DecimalPrecision)
+}
+
+// formatDate returns the date by the DateFormat, or by the locale if not set.
+func (c *csvClient) formatDate(t time.Time) string {
+	if c.opts.DateFormat != "" {
+		return t.Format(c.opts.DateFormat)
+	}
+
+	return c.opts.Locale.FormatDate(t)
+}
+
+// formatDateTime returns the date time by the DateTimeFormat, or by the locale
+// if not set.
+func (c *csvClient) formatDateTime(t time.Time) string {
+	if c.opts.DateTimeFormat != "" {
+		return t.Format(c.opts.DateTimeFormat)
+	}
+
+	return c.opts.Locale.FormatDateTime(t)
+}
+
+func (c *csvClient) convertEntryToRecord(entry worklog.Entry, opts *client.UploadOpts) ([]string, error) {
+	start := entry.Start.Local()
+	end := start.Add(entry.BillableDuration + entry.UnbillableDuration)
+
+	var record []string
+	for _, column := range c.opts.Columns {
+		var value string
+
+		switch column.Name {
+		case ColumnDate:
+			value = c.formatDate(start)
+		case ColumnStart:
+			value = c.formatDateTime(start)
+		case ColumnEnd:
+			value = c.formatDateTime(end)
+		case ColumnClient:
+			value = entry.Client.Name
+		case ColumnProject:
+			value = entry.Project.Name
+		case ColumnTask:
+			value = entry.Task.Name
+		case ColumnSummary:
+			value = entry.Summary
+		case ColumnNotes:
+			value = entry.Notes
+		case ColumnComment:
+			comment, err := opts.RenderComment(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			value = comment
+		case ColumnBillable:
+			value = c.formatDuration(entry.BillableDuration)
+		case ColumnUnbillable:
+			value = c.formatDuration(entry.UnbillableDuration)
+		case ColumnDuration:
+			value = c.formatDuration(entry.BillableDuration + entry.UnbillableDuration)
+		case ColumnAbsence:
+			value = string(entry.Absence)
+		case ColumnLinks:
+			value = strings.Join(entry.Links, " ")
+		case ColumnAuthor:
+			author, err := opts.AuthorOf(&entry)
+			if err != nil {
+				return nil, err
+			}
+
+			value = author
+		case ColumnClassification:
+			value = entry.Attributes[worklog.AttributeClassification]
+		case ColumnCostCenter:
+			value = worklog.CostCenterOf(&entry, c.opts.CostCenters)
+		default:
+			if strings.HasPrefix(column.Name, ColumnAttributePrefix) {
+				value = entry.Attributes[strings.TrimPrefix(column.Name, ColumnAttributePrefix)]
+				break
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, column.Name)
+		}
+
+		record = append(record, value)
+	}
+
+	return record, nil
+}
+
+func (c *csvClient) PreviewPayloads(entries worklog.Entries, opts *client.UploadOpts) ([]client.Payload, error) {
+	payloads := make([]client.Payload, 0, len(entries))
+	for _, entry := range entries {
+		record, err := c.convertEntryToRecord(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var body bytes.Buffer
+		writer := csv.NewWriter(&body)
+		writer.Comma = c.opts.Delimiter
+
+		if err = writer.Write(record); err != nil {
+			return nil, err
+		}
+
+		writer.Flush()
+		if err = writer.Error(); err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, client.Payload{Entry: entry, Body: body.Bytes()})
+	}
+
+	return payloads, nil
+}
+
+// costCenterPath returns the path of the file the entries of the cost center
+// are written to. The characters of the name that may not be used in file
+// names are replaced by underscores.
+func costCenterPath(path string, costCenter string) string {
+	if costCenter == "" {
+		costCenter = UnassignedCostCenter
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, costCenter)
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + name + ext
+}
+
+// splitByCostCenter groups the entries by the path of the file they are
+// written to.
+func (c *csvClient) splitByCostCenter(entries worklog.Entries) map[string]worklog.Entries {
+	files := map[string]worklog.Entries{}
+
+	if !c.opts.SplitByCostCenter {
+		files[c.opts.Path] = entries
+		return files
+	}
+
+	for _, entry := range entries {
+		path := costCenterPath(c.opts.Path, worklog.CostCenterOf(&entry, c.opts.CostCenters))
+		files[path] = append(files[path], entry)
+	}
+
+	return files
+}
+
+func (c *csvClient) writeFile(path string, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	file, err := os.Create(path)
+	if err != nil {
+		// Every entry must report its result, otherwise the caller waits
+		for range entries {
+			errChan <- fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+		}
+		return
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Comma = c.opts.Delimiter
+
+	if !c.opts.OmitHeader {
+		var header []string
+		for _, column := range c.opts.Columns {
+			header = append(header, column.Header)
+		}
+
+		if err = writer.Write(header); err != nil {
+			for range entries {
+				errChan <- fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+			}
+			return
+		}
+	}
+
+	sortedEntries := make(worklog.Entries, len(entries))
+	copy(sortedEntries, entries)
+	sort.SliceStable(sortedEntries, func(i, j int) bool {
+		return sortedEntries[i].Start.Before(sortedEntries[j].Start)
+	})
+
+	for _, entry := range sortedEntries {
+		tracker := c.StartTracking(entry, opts.ProgressWriter)
+
+		record, err := c.convertEntryToRecord(entry, opts)
+		if err == nil {
+			err = writer.Write(record)
+		}
+
+		if err == nil {
+			writer.Flush()
+			err = writer.Error()
+		}
+
+		if err != nil {
+			err = fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+		}
+
+		c.StopTracking(tracker, err)
+		errChan <- client.NewEntryError(entry, err)
+	}
+}
+
+func (c *csvClient) UploadEntries(_ context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	files := c.splitByCostCenter(entries)
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		c.writeFile(path, files[path], errChan, opts)
+	}
+}
+
+// NewUploader returns a new CSV file client for writing entries.
+func NewUploader(opts *ClientOpts) (client.Uploader, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no CSV file path provided")
+	}
+
+	clientOpts := *opts
+
+	if len(clientOpts.Columns) == 0 {
+		clientOpts.Columns = DefaultColumns
+	}
+
+	if clientOpts.Delimiter == 0 {
+		clientOpts.Delimiter = DefaultDelimiter
+	}
+
+	if clientOpts.DurationFormat == "" {
+		clientOpts.DurationFormat = DurationFormatDecimal
+	}
+
+	if clientOpts.DurationFormat != DurationFormatDecimal && clientOpts.DurationFormat != DurationFormatClock {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDurationFormat, clientOpts.DurationFormat)
+	}
+
+	if clientOpts.DecimalPrecision <= 0 {
+		clientOpts.DecimalPrecision = DefaultDecimalPrecision
+	}
+
+	if clientOpts.Locale == nil {
+		clientOpts.Locale = locale.Default()
+	}
+
+	return &csvClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+	}, nil
+}
@@ -0,0 +1,504 @@
+package csvfile_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func uploadEntries(t *testing.T, uploader client.Uploader, entries worklog.Entries, opts *client.UploadOpts) {
+	errChan := make(chan error, len(entries))
+	uploader.UploadEntries(context.Background(), entries, errChan, opts)
+
+	for i := 0; i < len(entries); i++ {
+		require.Nil(t, <-errChan)
+	}
+}
+
+func getTestEntries() worklog.Entries {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local)
+
+	return worklog.Entries{
+		{
+			Client:             worklog.IDNameField{ID: "client-id", Name: "My Awesome Company"},
+			Project:            worklog.IDNameField{ID: "project-id", Name: "Internal projects"},
+			Task:               worklog.IDNameField{ID: "task-id", Name: "TASK-456"},
+			Summary:            "Write documentation",
+			Start:              start.Add(time.Hour * 2),
+			BillableDuration:   time.Minute * 30,
+			UnbillableDuration: 0,
+		},
+		{
+			Client:             worklog.IDNameField{ID: "client-id", Name: "My Awesome Company"},
+			Project:            worklog.IDNameField{ID: "project-id", Name: "Internal projects"},
+			Task:               worklog.IDNameField{ID: "task-id", Name: "TASK-123"},
+			Summary:            "Fix; the bug",
+			Start:              start,
+			BillableDuration:   time.Hour + time.Minute*15,
+			UnbillableDuration: time.Minute * 15,
+		},
+	}
+}
+
+func TestParseColumn(t *testing.T) {
+	column, err := csvfile.ParseColumn("billable:Stunden")
+	require.Nil(t, err)
+	require.Equal(t, csvfile.Column{Name: csvfile.ColumnBillable, Header: "Stunden"}, column)
+
+	column, err = csvfile.ParseColumn("task")
+	require.Nil(t, err)
+	require.Equal(t, csvfile.Column{Name: csvfile.ColumnTask, Header: csvfile.ColumnTask}, column)
+
+	column, err = csvfile.ParseColumn("attributes.jsm.status:Status")
+	require.Nil(t, err)
+	require.Equal(t, csvfile.Column{Name: "attributes.jsm.status", Header: "Status"}, column)
+
+	_, err = csvfile.ParseColumn("unknown:Header")
+	require.ErrorContains(t, err, csvfile.ErrUnknownColumn.Error())
+
+	_, err = csvfile.ParseColumn("attributes.")
+	require.ErrorContains(t, err, csvfile.ErrUnknownColumn.Error())
+}
+
+func TestParseDelimiter(t *testing.T) {
+	delimiter, err := csvfile.ParseDelimiter(";")
+	require.Nil(t, err)
+	require.Equal(t, ';', delimiter)
+
+	delimiter, err = csvfile.ParseDelimiter(`\t`)
+	require.Nil(t, err)
+	require.Equal(t, '\t', delimiter)
+
+	for _, rawDelimiter := range []string{"", ";;", "\"", "\n"} {
+		_, err = csvfile.ParseDelimiter(rawDelimiter)
+		require.ErrorContains(t, err, csvfile.ErrInvalidDelimiter.Error())
+	}
+}
+
+func TestCSVClient_UploadEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path: path,
+	})
+	require.Nil(t, err)
+
+	uploadEntries(t, uploader, getTestEntries(), &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, `date,client,project,task,summary,billable,unbillable
+2021-10-02,My Awesome Company,Internal projects,TASK-123,Fix; the bug,1.25,0.25
+2021-10-02,My Awesome Company,Internal projects,TASK-456,Write documentation,0.50,0.00
+`, string(content))
+}
+
+func TestCSVClient_UploadEntries_CustomLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	columns, err := csvfile.ParseColumns([]string{"date:Datum", "start:Beginn", "task:Ticket", "summary:Text", "duration:Dauer"})
+	require.Nil(t, err)
+
+	reportLocale, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:      path,
+		Columns:   columns,
+		Delimiter: ';',
+		Locale:    reportLocale,
+	})
+	require.Nil(t, err)
+
+	uploadEntries(t, uploader, getTestEntries(), &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, `Datum;Beginn;Ticket;Text;Dauer
+02.10.2021;02.10.2021 09:00:00;TASK-123;"Fix; the bug";1,50
+02.10.2021;02.10.2021 11:00:00;TASK-456;Write documentation;0,50
+`, string(content))
+}
+
+func TestCSVClient_UploadEntries_CustomDateFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:           path,
+		Columns:        []csvfile.Column{{Name: csvfile.ColumnDate}, {Name: csvfile.ColumnStart}, {Name: csvfile.ColumnTask}},
+		OmitHeader:     true,
+		DateFormat:     "01/02/2006",
+		DateTimeFormat: "01/02/2006 3:04 PM",
+	})
+	require.Nil(t, err)
+
+	uploadEntries(t, uploader, getTestEntries(), &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "10/02/2021,10/02/2021 9:00 AM,TASK-123\n10/02/2021,10/02/2021 11:00 AM,TASK-456\n", string(content))
+}
+
+func TestCSVClient_UploadEntries_ClockDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:           path,
+		Columns:        []csvfile.Column{{Name: csvfile.ColumnTask}, {Name: csvfile.ColumnBillable}},
+		OmitHeader:     true,
+		DurationFormat: csvfile.DurationFormatClock,
+	})
+	require.Nil(t, err)
+
+	uploadEntries(t, uploader, getTestEntries(), &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "TASK-123,1:15\nTASK-456,0:30\n", string(content))
+}
+
+func TestCSVClient_UploadEntries_Attributes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	columns, err := csvfile.ParseColumns([]string{"task", "attributes.jsm.status:Status", "classification"})
+	require.Nil(t, err)
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:    path,
+		Columns: columns,
+	})
+	require.Nil(t, err)
+
+	entries := getTestEntries()
+	entries[1].SetAttribute("jsm.status", "Resolved")
+	entries[1].SetAttribute(worklog.AttributeClassification, "OPEX")
+
+	uploadEntries(t, uploader, entries, &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "task,Status,classification\nTASK-123,Resolved,OPEX\nTASK-456,,\n", string(content))
+}
+
+func TestCSVClient_UploadEntries_Author(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:       path,
+		Columns:    []csvfile.Column{{Name: csvfile.ColumnTask}, {Name: csvfile.ColumnAuthor}},
+		OmitHeader: true,
+	})
+	require.Nil(t, err)
+
+	entries := getTestEntries()
+	entries[1].SetAttribute(worklog.AttributeWorker, "bucky-barnes")
+
+	uploadEntries(t, uploader, entries, &client.UploadOpts{
+		User: "steve-rogers",
+		Author: &client.AuthorOpts{
+			Strategy: client.AuthorStrategyMapped,
+			UserMap:  map[string]string{"bucky-barnes": "james.barnes"},
+		},
+	})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "TASK-123,james.barnes\nTASK-456,steve-rogers\n", string(content))
+}
+
+func TestNewUploader_InvalidOpts(t *testing.T) {
+	_, err := csvfile.NewUploader(&csvfile.ClientOpts{})
+	require.Error(t, err)
+
+	_, err = csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:           "entries.csv",
+		DurationFormat: "seconds",
+	})
+	require.ErrorContains(t, err, csvfile.ErrUnknownDurationFormat.Error())
+}
+
+func TestCSVClient_PreviewPayloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:      path,
+		Delimiter: ';',
+	})
+	require.Nil(t, err)
+
+	payloads, err := uploader.(client.PayloadPreviewer).PreviewPayloads(getTestEntries(), &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Len(t, payloads, 2)
+	require.Equal(t, "2021-10-02;My Awesome Company;Internal projects;TASK-456;Write documentation;0.50;0.00\n", string(payloads[0].Body))
+	require.Equal(t, "2021-10-02;My Awesome Company;Internal projects;TASK-123;\"Fix; the bug\";1.25;0.25\n", string(payloads[1].Body))
+	require.Empty(t, payloads[0].Method)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCSVClient_FetchEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	columns, err := csvfile.ParseColumns([]string{"date:Datum", "start:Beginn", "task:Ticket", "summary:Text", "duration:Dauer", "unbillable"})
+	require.Nil(t, err)
+
+	reportLocale, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	content := "Text;Ticket;Dauer;Datum;Beginn;unbillable;Extra\n" +
+		"Fix the bug;TASK-123;1,5;02.10.2021;02.10.2021 09:00:00;0:30;x\n" +
+		"Write documentation;TASK-456;45m;02.10.2021;;;x\n" +
+		"Too early;TASK-789;1h;01.10.2021;;;x\n"
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetcher, err := csvfile.NewFetcher(&csvfile.ClientOpts{
+		Path:      path,
+		Columns:   columns,
+		Delimiter: ';',
+		Locale:    reportLocale,
+	})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	})
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local), entries[0].Start)
+	require.Equal(t, time.Hour, entries[0].BillableDuration)
+	require.Equal(t, time.Minute*30, entries[0].UnbillableDuration)
+	require.Equal(t, []string{"line 2"}, entries[0].Provenance.SourceIDs)
+
+	require.Equal(t, "Write documentation", entries[1].Summary)
+	require.Equal(t, time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local), entries[1].Start)
+	require.Equal(t, time.Minute*45, entries[1].BillableDuration)
+}
+
+func TestCSVClient_FetchEntries_CustomFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	columns, err := csvfile.ParseColumns([]string{"start:Start Time", "project:Project", "summary:Description", "duration:Minutes", "billable:Billable Minutes"})
+	require.Nil(t, err)
+
+	content := "Start Time,Project,Description,Minutes,Billable Minutes\n" +
+		"10/02/2021 9:00 AM,MARVEL,Fix the bug,90,60\n" +
+		"10/02/2021 2:30 PM,SHIELD,Write documentation,45,\n" +
+		"10/02/2021 4:00 PM,SHIELD,Review,1h15m,\n"
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetcher, err := csvfile.NewFetcher(&csvfile.ClientOpts{
+		Path:           path,
+		Columns:        columns,
+		DateTimeFormat: "01/02/2006 3:04 PM",
+		DurationUnit:   csvfile.DurationUnitMinutes,
+	})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	})
+	require.Nil(t, err)
+	require.Len(t, entries, 3)
+
+	require.Equal(t, time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local), entries[0].Start)
+	require.Equal(t, time.Hour, entries[0].BillableDuration)
+	require.Equal(t, "MARVEL", entries[0].Project.Name)
+
+	require.Equal(t, time.Date(2021, 10, 2, 14, 30, 0, 0, time.Local), entries[1].Start)
+	require.Equal(t, time.Minute*45, entries[1].BillableDuration)
+
+	// Durations having units are parsed regardless of the unit
+	require.Equal(t, time.Hour+time.Minute*15, entries[2].BillableDuration)
+}
+
+func TestNewFetcher_InvalidOpts(t *testing.T) {
+	_, err := csvfile.NewFetcher(&csvfile.ClientOpts{
+		Path:         "entries.csv",
+		DurationUnit: "days",
+	})
+	require.ErrorIs(t, err, csvfile.ErrUnknownDurationUnit)
+}
+
+func TestCSVClient_FetchEntries_InvalidRows(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entries.csv")
+	rejectsPath := filepath.Join(dir, "rejects.csv")
+
+	content := "date,task,billable\n" +
+		"2021-10-02,TASK-123,1:30\n" +
+		"2021-10-02,TASK-456,1x\n" +
+		",TASK-789,1\n" +
+		"2021-10-02\n"
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetchOpts := &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	}
+
+	for _, strict := range []bool{false, true} {
+		fetcher, err := csvfile.NewFetcher(&csvfile.ClientOpts{
+			Path:        path,
+			Columns:     []csvfile.Column{{Name: csvfile.ColumnDate, Header: "date"}, {Name: csvfile.ColumnTask, Header: "task"}, {Name: csvfile.ColumnBillable, Header: "billable"}},
+			Strict:      strict,
+			RejectsPath: rejectsPath,
+		})
+		require.Nil(t, err)
+
+		entries, err := fetcher.FetchEntries(context.Background(), fetchOpts)
+
+		var rowErrors client.RowErrors
+		require.True(t, errors.As(err, &rowErrors))
+		require.Len(t, rowErrors, 3)
+		require.EqualError(t, rowErrors[0], `line 3: billable: invalid duration: "1x"`)
+		require.Equal(t, 4, rowErrors[1].Line)
+		require.Equal(t, 5, rowErrors[2].Line)
+
+		if strict {
+			require.ErrorIs(t, err, client.ErrFetchEntries)
+			require.Nil(t, entries)
+		} else {
+			require.Len(t, entries, 1)
+			require.Equal(t, "TASK-123", entries[0].Task.Name)
+		}
+
+		rejects, err := os.ReadFile(rejectsPath)
+		require.Nil(t, err)
+		require.Equal(t, "date,task,billable,error\n"+
+			"2021-10-02,TASK-456,1x,\"billable: invalid duration: \"\"1x\"\"\"\n"+
+			",TASK-789,1,missing value: start or date\n"+
+			"2021-10-02,\"missing value: expected at least 3 columns, got 1\"\n", string(rejects))
+	}
+}
+
+func TestCSVClient_FetchEntries_MissingColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+	require.Nil(t, os.WriteFile(path, []byte("date,task\n2021-10-02,TASK-123\n"), 0600))
+
+	fetcher, err := csvfile.NewFetcher(&csvfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	_, err = fetcher.FetchEntries(context.Background(), &client.FetchOpts{})
+	require.ErrorIs(t, err, csvfile.ErrMissingColumn)
+}
+
+func TestInferColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+	require.Nil(t, os.WriteFile(path, []byte("Datum;Kunde;Auftrag;Beschreibung;Zeit (h);Ref;Link\n"+
+		"02.10.2021;ACME;A1;Fix the bug;1,5;ABC-1;https://example.com/1\n"+
+		"03.10.2021;ACME;;Meeting;0:45;ABC-2;https://example.com/2\n"), 0600))
+
+	deDE, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	columns, err := csvfile.InferColumns(&csvfile.ClientOpts{Path: path, Delimiter: ';', Locale: deDE})
+	require.Nil(t, err)
+	require.Equal(t, []csvfile.Column{
+		{Name: csvfile.ColumnDate, Header: "Datum"},
+		{Name: csvfile.ColumnClient, Header: "Kunde"},
+		{Name: csvfile.ColumnSummary, Header: "Beschreibung"},
+		{Name: csvfile.ColumnDuration, Header: "Zeit (h)"},
+		{Name: csvfile.ColumnTask, Header: "Ref"},
+		{Name: csvfile.ColumnLinks, Header: "Link"},
+	}, columns)
+
+	require.Equal(t, "date:Datum", columns[0].String())
+}
+
+func TestInferColumns_NotInferred(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+	require.Nil(t, os.WriteFile(path, []byte("name,value\nfoo,bar\n"), 0600))
+
+	_, err := csvfile.InferColumns(&csvfile.ClientOpts{Path: path})
+	require.ErrorIs(t, err, csvfile.ErrNoColumnsInferred)
+
+	_, err = csvfile.InferColumns(&csvfile.ClientOpts{Path: path, OmitHeader: true})
+	require.ErrorIs(t, err, csvfile.ErrMissingHeader)
+}
+
+func getTestCostCenters(t *testing.T) []worklog.CostCenter {
+	costCenters := []worklog.CostCenter{
+		{Name: "CC-1000", Project: "^Internal"},
+		{Name: "CC/2000", Project: "^Customer"},
+	}
+
+	for i := range costCenters {
+		require.Nil(t, costCenters[i].Compile())
+	}
+
+	return costCenters
+}
+
+func TestCSVClient_UploadEntries_CostCenter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.csv")
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:        path,
+		Columns:     []csvfile.Column{{Name: csvfile.ColumnTask, Header: "task"}, {Name: csvfile.ColumnCostCenter, Header: "Kostenstelle"}},
+		CostCenters: getTestCostCenters(t),
+	})
+	require.Nil(t, err)
+
+	entries := getTestEntries()
+	entries[0].Project = worklog.IDNameField{ID: "other", Name: "Other"}
+
+	uploadEntries(t, uploader, entries, &client.UploadOpts{})
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "task,Kostenstelle\nTASK-123,CC-1000\nTASK-456,\n", string(content))
+}
+
+func TestCSVClient_UploadEntries_SplitByCostCenter(t *testing.T) {
+	dir := t.TempDir()
+
+	uploader, err := csvfile.NewUploader(&csvfile.ClientOpts{
+		Path:              filepath.Join(dir, "entries.csv"),
+		Columns:           []csvfile.Column{{Name: csvfile.ColumnTask, Header: "task"}},
+		CostCenters:       getTestCostCenters(t),
+		SplitByCostCenter: true,
+	})
+	require.Nil(t, err)
+
+	entries := getTestEntries()
+	entries = append(entries, worklog.Entry{
+		Project:          worklog.IDNameField{ID: "customer", Name: "Customer portal"},
+		Task:             worklog.IDNameField{ID: "task-id", Name: "TASK-789"},
+		Start:            entries[0].Start,
+		BillableDuration: time.Hour,
+	}, worklog.Entry{
+		Project:          worklog.IDNameField{ID: "other", Name: "Other"},
+		Task:             worklog.IDNameField{ID: "task-id", Name: "TASK-999"},
+		Start:            entries[0].Start,
+		BillableDuration: time.Hour,
+	})
+
+	uploadEntries(t, uploader, entries, &client.UploadOpts{})
+
+	for name, expected := range map[string]string{
+		"entries-CC-1000.csv":    "task\nTASK-123\nTASK-456\n",
+		"entries-CC_2000.csv":    "task\nTASK-789\n",
+		"entries-unassigned.csv": "task\nTASK-999\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		require.Nil(t, err)
+		require.Equal(t, expected, string(content), name)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "entries.csv"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,469 @@
+package csvfile
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// RejectsErrorHeader is the header of the column containing the error of
+	// the rejected rows in the rejects file.
+	RejectsErrorHeader string = "error"
+)
+
+var (
+	// ErrMissingColumn returns when a configured column is not part of the
+	// header row of the read file.
+	ErrMissingColumn = errors.New("missing column")
+	// ErrMissingValue returns when a required value of a row is empty.
+	ErrMissingValue = errors.New("missing value")
+	// ErrInvalidValue returns when a value of a row cannot be parsed.
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// row represents a read row of the CSV file.
+type row struct {
+	line   int
+	record []string
+}
+
+// Record represents a read row of a tabular file other than CSV, like a row of
+// a spreadsheet. Line is the number of the row in the file, reported for the
+// invalid rows.
+type Record struct {
+	Line   int
+	Values []string
+}
+
+// RecordReader reads the rows of a tabular file other than CSV, including the
+// header row, if any. The empty rows are expected to be skipped.
+type RecordReader func() ([]Record, error)
+
+// columnIndexes returns the index of every configured column in the records.
+// If the file has a header row, the columns are looked up by their header or
+// name, otherwise the columns are expected in the configured order.
+func (c *csvClient) columnIndexes(header []string) (map[string]int, error) {
+	indexes := map[string]int{}
+
+	for i, column := range c.opts.Columns {
+		if header == nil {
+			indexes[column.Name] = i
+			continue
+		}
+
+		index := -1
+		for j, cell := range header {
+			cell = strings.TrimSpace(cell)
+			if strings.EqualFold(cell, column.Header) || strings.EqualFold(cell, column.Name) {
+				index = j
+				break
+			}
+		}
+
+		if index == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, column.Header)
+		}
+
+		indexes[column.Name] = index
+	}
+
+	return indexes, nil
+}
+
+// columnHeader returns the header of the column by its name. If the column is
+// not configured, the name returns.
+func (c *csvClient) columnHeader(name string) string {
+	for _, column := range c.opts.Columns {
+		if column.Name == name && column.Header != "" {
+			return column.Header
+		}
+	}
+
+	return name
+}
+
+// dateFormats returns the accepted date formats. The configured format is
+// tried first, followed by the format of the locale.
+func (c *csvClient) dateFormats() []string {
+	formats := []string{c.opts.Locale.DateFormat, "2006-01-02"}
+	if c.opts.DateFormat != "" {
+		formats = append([]string{c.opts.DateFormat}, formats...)
+	}
+
+	return formats
+}
+
+// dateTimeFormats returns the accepted date time formats. The configured
+// format is tried first, followed by the format of the locale.
+func (c *csvClient) dateTimeFormats() []string {
+	formats := []string{c.opts.Locale.DateTimeFormat, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", time.RFC3339}
+	if c.opts.DateTimeFormat != "" {
+		formats = append([]string{c.opts.DateTimeFormat}, formats...)
+	}
+
+	return formats
+}
+
+// parseDuration parses the duration using the locale. The durations given as
+// plain numbers, like "90", are in the DurationUnit.
+func (c *csvClient) parseDuration(value string) (time.Duration, error) {
+	if c.opts.DurationUnit != DurationUnitHours {
+		if number, err := c.opts.Locale.ParseNumber(value); err == nil {
+			unit := time.Minute
+			if c.opts.DurationUnit == DurationUnitSeconds {
+				unit = time.Second
+			}
+
+			return time.Duration(number * float64(unit)), nil
+		}
+	}
+
+	return c.opts.Locale.ParseDuration(value)
+}
+
+// parseTime parses the date or date time value using the locale's formats.
+// ISO 8601 formats are accepted too.
+func parseTime(value string, formats ...string) (time.Time, error) {
+	for _, format := range formats {
+		if parsed, err := time.ParseInLocation(format, value, time.Local); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidValue, value)
+}
+
+// convertRecordToEntry parses the record of the row into an entry. If any
+// value is invalid, a client.RowError returns.
+func (c *csvClient) convertRecordToEntry(r *row, indexes map[string]int) (worklog.Entry, error) {
+	entry := worklog.Entry{
+		Provenance: worklog.Provenance{SourceIDs: []string{"line " + strconv.Itoa(r.line)}},
+	}
+
+	rowError := func(column string, err error) error {
+		return &client.RowError{Line: r.line, Column: column, Err: err}
+	}
+
+	columnCount := 0
+	for _, index := range indexes {
+		if index >= columnCount {
+			columnCount = index + 1
+		}
+	}
+
+	if len(r.record) < columnCount {
+		return entry, rowError("", fmt.Errorf("%w: expected at least %d columns, got %d", ErrMissingValue, columnCount, len(r.record)))
+	}
+
+	values := map[string]string{}
+	for name, index := range indexes {
+		values[name] = strings.TrimSpace(r.record[index])
+	}
+
+	dateFormats := c.dateFormats()
+	dateTimeFormats := c.dateTimeFormats()
+
+	var date, end time.Time
+	durations := map[string]*time.Duration{}
+
+	for _, column := range c.opts.Columns {
+		name, value := column.Name, values[column.Name]
+		if value == "" {
+			continue
+		}
+
+		var err error
+
+		switch name {
+		case ColumnDate:
+			date, err = parseTime(value, dateFormats...)
+		case ColumnStart:
+			entry.Start, err = parseTime(value, dateTimeFormats...)
+		case ColumnEnd:
+			end, err = parseTime(value, dateTimeFormats...)
+		case ColumnClient:
+			entry.Client = worklog.IDNameField{ID: value, Name: value}
+		case ColumnProject:
+			entry.Project = worklog.IDNameField{ID: value, Name: value}
+		case ColumnTask:
+			entry.Task = worklog.IDNameField{ID: value, Name: value}
+		case ColumnSummary:
+			entry.Summary = value
+		case ColumnNotes:
+			entry.Notes = value
+		case ColumnBillable, ColumnUnbillable, ColumnDuration:
+			var duration time.Duration
+			duration, err = c.parseDuration(value)
+			durations[name] = &duration
+		case ColumnAbsence:
+			entry.Absence = worklog.ParseAbsence(value)
+		case ColumnLinks:
+			entry.Links = strings.Fields(value)
+		case ColumnClassification:
+			entry.SetAttribute(worklog.AttributeClassification, value)
+		case ColumnAuthor:
+			entry.SetAttribute(worklog.AttributeWorker, value)
+		default:
+			if strings.HasPrefix(name, ColumnAttributePrefix) {
+				entry.SetAttribute(strings.TrimPrefix(name, ColumnAttributePrefix), value)
+			}
+		}
+
+		if err != nil {
+			return entry, rowError(c.columnHeader(name), err)
+		}
+	}
+
+	// Rows having only a date are daily totals, starting at midnight
+	if entry.Start.IsZero() {
+		entry.Start = date
+	}
+
+	if entry.Start.IsZero() {
+		return entry, rowError("", fmt.Errorf("%w: %s or %s", ErrMissingValue, ColumnStart, ColumnDate))
+	}
+
+	if !end.IsZero() && end.Before(entry.Start) {
+		return entry, rowError(c.columnHeader(ColumnEnd), fmt.Errorf("%w: end is before start", ErrInvalidValue))
+	}
+
+	if unbillable := durations[ColumnUnbillable]; unbillable != nil {
+		entry.UnbillableDuration = *unbillable
+	}
+
+	switch {
+	case durations[ColumnBillable] != nil:
+		entry.BillableDuration = *durations[ColumnBillable]
+	case durations[ColumnDuration] != nil:
+		entry.BillableDuration = *durations[ColumnDuration] - entry.UnbillableDuration
+	case !end.IsZero():
+		entry.BillableDuration = end.Sub(entry.Start) - entry.UnbillableDuration
+	case durations[ColumnUnbillable] == nil:
+		return entry, rowError("", fmt.Errorf("%w: %s, %s, %s or %s", ErrMissingValue, ColumnBillable, ColumnUnbillable, ColumnDuration, ColumnEnd))
+	}
+
+	if entry.BillableDuration < 0 {
+		return entry, rowError(c.columnHeader(ColumnDuration), fmt.Errorf("%w: unbillable duration exceeds the total duration", ErrInvalidValue))
+	}
+
+	return entry, nil
+}
+
+// readRows returns the header and the rows of the file, read by the record
+// reader if set. If the file has no header row, the returned header is nil.
+func (c *csvClient) readRows() ([]string, []*row, error) {
+	if c.readRecords == nil {
+		return c.readCSVRows()
+	}
+
+	records, err := c.readRecords()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var header []string
+	var rows []*row
+
+	for _, record := range records {
+		if header == nil && !c.opts.OmitHeader {
+			header = record.Values
+			continue
+		}
+
+		rows = append(rows, &row{line: record.Line, record: record.Values})
+	}
+
+	return header, rows, nil
+}
+
+// readCSVRows returns the header and the rows of the CSV file. If the file has
+// no header row, the returned header is nil.
+func (c *csvClient) readCSVRows() ([]string, []*row, error) {
+	file, err := os.Open(c.opts.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = c.opts.Delimiter
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	var rows []*row
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if header == nil && !c.opts.OmitHeader {
+			header = record
+			continue
+		}
+
+		rows = append(rows, &row{line: line, record: record})
+	}
+
+	return header, rows, nil
+}
+
+// writeRejects writes the rejected rows to the rejects file, extended by the
+// error of the row, so the rows can be fixed and imported again.
+func (c *csvClient) writeRejects(header []string, rejects []*row, rowErrors client.RowErrors) error {
+	file, err := os.Create(c.opts.RejectsPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Comma = c.opts.Delimiter
+
+	if header != nil {
+		if err = writer.Write(append(append([]string{}, header...), RejectsErrorHeader)); err != nil {
+			return err
+		}
+	}
+
+	for i, reject := range rejects {
+		message := rowErrors[i].Err.Error()
+		if rowErrors[i].Column != "" {
+			message = rowErrors[i].Column + ": " + message
+		}
+
+		if err = writer.Write(append(append([]string{}, reject.record...), message)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (c *csvClient) FetchEntries(_ context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	header, rows, err := c.readRows()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	indexes, err := c.columnIndexes(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	entries := worklog.Entries{}
+	var rowErrors client.RowErrors
+	var rejects []*row
+
+	for _, r := range rows {
+		entry, err := c.convertRecordToEntry(r, indexes)
+		if err != nil {
+			var rowErr *client.RowError
+			errors.As(err, &rowErr)
+
+			rowErrors = append(rowErrors, rowErr)
+			rejects = append(rejects, r)
+			continue
+		}
+
+		if !opts.Contains(entry.Start) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(rowErrors) == 0 {
+		return entries, nil
+	}
+
+	if c.opts.RejectsPath != "" {
+		if err = c.writeRejects(header, rejects, rowErrors); err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+	}
+
+	if c.opts.Strict {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, rowErrors)
+	}
+
+	return entries, rowErrors
+}
+
+// newFetcher returns a new CSV file client for reading entries, setting the
+// defaults of the options.
+func newFetcher(opts *ClientOpts) (*csvClient, error) {
+	clientOpts := *opts
+
+	if len(clientOpts.Columns) == 0 {
+		clientOpts.Columns = DefaultColumns
+	}
+
+	if clientOpts.Delimiter == 0 {
+		clientOpts.Delimiter = DefaultDelimiter
+	}
+
+	if clientOpts.Locale == nil {
+		clientOpts.Locale = locale.Default()
+	}
+
+	if clientOpts.DurationUnit == "" {
+		clientOpts.DurationUnit = DurationUnitHours
+	}
+
+	switch clientOpts.DurationUnit {
+	case DurationUnitHours, DurationUnitMinutes, DurationUnitSeconds:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDurationUnit, clientOpts.DurationUnit)
+	}
+
+	return &csvClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+	}, nil
+}
+
+// NewFetcher returns a new CSV file client for reading entries. The file is
+// read using the same columns and format as the written files, hence the files
+// written by the uploader can be imported again.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no CSV file path provided")
+	}
+
+	return newFetcher(opts)
+}
+
+// NewRecordFetcher returns a new client reading the entries from the records
+// of a tabular file other than CSV, like a spreadsheet. The records are parsed
+// the same way as the rows of a CSV file, using the columns and formats of the
+// options. The rejected rows are written to the rejects file in CSV format.
+func NewRecordFetcher(opts *ClientOpts, reader RecordReader) (client.Fetcher, error) {
+	c, err := newFetcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.readRecords = reader
+
+	return c, nil
+}
@@ -0,0 +1,259 @@
+package csvfile
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+)
+
+const (
+	// inferSampleSize is the number of rows inspected to infer the column of
+	// the values.
+	inferSampleSize int = 20
+)
+
+var (
+	// ErrMissingHeader returns when the columns should be inferred from a file
+	// without header row.
+	ErrMissingHeader = errors.New("missing header row")
+	// ErrNoColumnsInferred returns when the start date or the spent time
+	// columns cannot be inferred.
+	ErrNoColumnsInferred = errors.New("cannot infer the start and duration columns")
+
+	// columnSynonyms lists the normalized headers of the columns, used by
+	// other tools and spreadsheets, in the languages of the supported locales.
+	columnSynonyms = map[string][]string{
+		ColumnDate:           {"date", "day", "datum", "tag", "dátum", "nap", "jour", "dag"},
+		ColumnStart:          {"start", "started", "start time", "begin", "beginn", "from", "von", "kezdés", "début", "starttijd"},
+		ColumnEnd:            {"end", "ended", "end time", "finish", "stop", "to", "ende", "bis", "vége", "befejezés", "fin", "eindtijd"},
+		ColumnClient:         {"client", "customer", "company", "kunde", "auftraggeber", "ügyfél", "megrendelő", "klant"},
+		ColumnProject:        {"project", "projekt", "projet", "project name"},
+		ColumnTask:           {"task", "ticket", "issue", "issue key", "key", "aufgabe", "feladat", "tâche", "taak"},
+		ColumnSummary:        {"summary", "description", "activity", "work description", "beschreibung", "tätigkeit", "leírás", "tevékenység", "omschrijving"},
+		ColumnNotes:          {"notes", "note", "comment", "comments", "remarks", "notizen", "bemerkung", "kommentar", "megjegyzés", "opmerking"},
+		ColumnBillable:       {"billable", "billable hours", "billable time", "billable duration", "abrechenbar", "számlázható"},
+		ColumnUnbillable:     {"unbillable", "non billable", "nonbillable", "unbillable hours", "non billable hours", "nicht abrechenbar", "nem számlázható"},
+		ColumnDuration:       {"duration", "hours", "time", "time spent", "spent", "dauer", "stunden", "zeit", "aufwand", "időtartam", "óra", "órák", "durée", "heures", "uren", "duur"},
+		ColumnAbsence:        {"absence", "time off", "leave", "abwesenheit", "távollét"},
+		ColumnLinks:          {"links", "link", "url", "urls"},
+		ColumnClassification: {"classification", "cost classification", "klassifizierung"},
+		ColumnAuthor:         {"author", "user", "worker", "employee", "mitarbeiter", "benutzer", "munkatárs", "medewerker"},
+	}
+
+	// headerUnits lists the units dropped from the end of the headers.
+	headerUnits = map[string]bool{"h": true, "hrs": true, "min": true, "std": true}
+
+	// taskKeyRegex matches the issue keys of issue trackers, like "TASK-123".
+	taskKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-\d+$`)
+)
+
+// normalizeHeader returns the lower case header, with the punctuation replaced
+// by spaces, so "Time-Spent (h)" and "time spent" are matched the same way.
+func normalizeHeader(header string) string {
+	fields := strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	// Drop the units, like "(h)" or "[min]"
+	for len(fields) > 1 {
+		if !headerUnits[fields[len(fields)-1]] {
+			break
+		}
+
+		fields = fields[:len(fields)-1]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// inferColumnByHeader returns the name of the column having the header as
+// synonym. If no column matches, an empty string returns.
+func inferColumnByHeader(header string, assigned map[string]bool) string {
+	normalized := normalizeHeader(header)
+
+	for _, name := range Columns {
+		if assigned[name] {
+			continue
+		}
+
+		if normalized == name {
+			return name
+		}
+
+		for _, synonym := range columnSynonyms[name] {
+			if normalized == synonym {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// inferColumnByValues returns the name of the column that can parse every
+// sample value. If no column matches, an empty string returns.
+func (c *csvClient) inferColumnByValues(values []string, assigned map[string]bool) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	every := func(isValid func(value string) bool) bool {
+		for _, value := range values {
+			if !isValid(value) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	candidates := []struct {
+		names   []string
+		isValid func(value string) bool
+	}{
+		{
+			names: []string{ColumnDate},
+			isValid: func(value string) bool {
+				_, err := parseTime(value, c.dateFormats()...)
+				return err == nil
+			},
+		},
+		{
+			names: []string{ColumnStart, ColumnEnd},
+			isValid: func(value string) bool {
+				_, err := parseTime(value, c.dateTimeFormats()...)
+				return err == nil
+			},
+		},
+		{
+			names: []string{ColumnTask},
+			isValid: func(value string) bool {
+				return taskKeyRegex.MatchString(value)
+			},
+		},
+		{
+			names: []string{ColumnLinks},
+			isValid: func(value string) bool {
+				return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+			},
+		},
+		{
+			names: []string{ColumnDuration},
+			isValid: func(value string) bool {
+				_, err := c.parseDuration(value)
+				return err == nil
+			},
+		},
+	}
+
+	for _, candidate := range candidates {
+		if !every(candidate.isValid) {
+			continue
+		}
+
+		for _, name := range candidate.names {
+			if !assigned[name] {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// InferColumns proposes the columns of the CSV file by inspecting its header
+// row and the values of the first rows. The headers are matched against the
+// column names and their common synonyms first, like "Datum" or "Time spent",
+// then the remaining columns are inferred from their values, like dates,
+// durations or issue keys. The columns that cannot be inferred are left out.
+//
+// The returned columns can be used as ClientOpts.Columns, or printed by
+// Column.String to be saved in the configuration.
+func InferColumns(opts *ClientOpts) ([]Column, error) {
+	return InferRecordColumns(opts, nil)
+}
+
+// InferRecordColumns proposes the columns of a tabular file other than CSV,
+// read by the record reader, the same way as InferColumns. If the reader is
+// nil, the CSV file of the options is read.
+func InferRecordColumns(opts *ClientOpts, reader RecordReader) ([]Column, error) {
+	if opts.OmitHeader {
+		return nil, ErrMissingHeader
+	}
+
+	clientOpts := *opts
+
+	if clientOpts.Delimiter == 0 {
+		clientOpts.Delimiter = DefaultDelimiter
+	}
+
+	if clientOpts.Locale == nil {
+		clientOpts.Locale = locale.Default()
+	}
+
+	if clientOpts.DurationUnit == "" {
+		clientOpts.DurationUnit = DurationUnitHours
+	}
+
+	c := &csvClient{opts: clientOpts, readRecords: reader}
+
+	header, rows, err := c.readRows()
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return nil, ErrMissingHeader
+	}
+
+	names := make([]string, len(header))
+	assigned := map[string]bool{}
+
+	for i, cell := range header {
+		if name := inferColumnByHeader(cell, assigned); name != "" {
+			names[i] = name
+			assigned[name] = true
+		}
+	}
+
+	for i := range header {
+		if names[i] != "" {
+			continue
+		}
+
+		var values []string
+		for _, r := range rows {
+			if len(values) == inferSampleSize {
+				break
+			}
+
+			if i < len(r.record) && strings.TrimSpace(r.record[i]) != "" {
+				values = append(values, strings.TrimSpace(r.record[i]))
+			}
+		}
+
+		if name := c.inferColumnByValues(values, assigned); name != "" {
+			names[i] = name
+			assigned[name] = true
+		}
+	}
+
+	hasStart := assigned[ColumnDate] || assigned[ColumnStart]
+	hasDuration := assigned[ColumnBillable] || assigned[ColumnUnbillable] || assigned[ColumnDuration] || assigned[ColumnEnd]
+
+	if !hasStart || !hasDuration {
+		return nil, ErrNoColumnsInferred
+	}
+
+	var columns []Column
+	for i, name := range names {
+		if name != "" {
+			columns = append(columns, Column{Name: name, Header: strings.TrimSpace(header[i])})
+		}
+	}
+
+	return columns, nil
+}
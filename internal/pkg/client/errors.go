@@ -0,0 +1,291 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxErrorBodyLength is the maximum length of the response body included
+	// in the error message, if the body contains no known error messages.
+	maxErrorBodyLength int = 1024
+)
+
+// ErrorKind represents the class of an error returned by the clients. The
+// kind decides whether the failed operation should be retried, skipped or
+// aborted.
+type ErrorKind string
+
+const (
+	// ErrorKindUnknown is the kind of the errors not classified by the clients.
+	ErrorKindUnknown ErrorKind = "unknown"
+	// ErrorKindAuth is the kind of AuthError.
+	ErrorKindAuth ErrorKind = "auth"
+	// ErrorKindNotFound is the kind of NotFoundError.
+	ErrorKindNotFound ErrorKind = "not found"
+	// ErrorKindRateLimited is the kind of RateLimitError.
+	ErrorKindRateLimited ErrorKind = "rate limited"
+	// ErrorKindValidation is the kind of ValidationError.
+	ErrorKindValidation ErrorKind = "validation"
+	// ErrorKindNetwork is the kind of NetworkError.
+	ErrorKindNetwork ErrorKind = "network"
+)
+
+// HTTPError represents an unsuccessful HTTP response. The typed errors wrap it
+// when the error is caused by the response, so the status code and the
+// response body are available using `errors.As`.
+type HTTPError struct {
+	StatusCode int
+	// Body is the raw response body.
+	Body []byte
+	// Messages are the error messages parsed from the body, like the field
+	// errors returned by Jira and Tempo.
+	Messages []string
+}
+
+func (e *HTTPError) Error() string {
+	if len(e.Messages) != 0 {
+		return fmt.Sprintf("%d: %s", e.StatusCode, strings.Join(e.Messages, "; "))
+	}
+
+	body := strings.TrimSpace(string(e.Body))
+	if len(body) > maxErrorBodyLength {
+		body = body[:maxErrorBodyLength] + "..."
+	}
+
+	return fmt.Sprintf("%d: %s", e.StatusCode, body)
+}
+
+// errorResponse represents the error response formats of the supported
+// services. Jira uses "errorMessages" and "errors" as field to message map,
+// Jira Service Management uses "errorMessage", while Tempo uses "errors" as a
+// list of messages. SOAP faults are parsed separately.
+type errorResponse struct {
+	Message       string          `json:"message"`
+	ErrorMessage  string          `json:"errorMessage"`
+	ErrorMessages []string        `json:"errorMessages"`
+	Errors        json.RawMessage `json:"errors"`
+}
+
+// parseErrorMessages returns the error messages of the response body. If the
+// body is not a known error response, nil returns.
+func parseErrorMessages(body []byte) []string {
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		if fault := parseSOAPFault(body); fault != nil {
+			return []string{fault.Code + ": " + fault.Reason}
+		}
+
+		return nil
+	}
+
+	var messages []string
+	for _, message := range append([]string{resp.Message, resp.ErrorMessage}, resp.ErrorMessages...) {
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	var fieldErrors map[string]string
+	var listErrors []struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(resp.Errors, &fieldErrors); err == nil {
+		var fields []string
+		for field := range fieldErrors {
+			fields = append(fields, field)
+		}
+
+		sort.Strings(fields)
+		for _, field := range fields {
+			messages = append(messages, field+": "+fieldErrors[field])
+		}
+	} else if err = json.Unmarshal(resp.Errors, &listErrors); err == nil {
+		for _, listError := range listErrors {
+			if listError.Message != "" {
+				messages = append(messages, listError.Message)
+			}
+		}
+	}
+
+	return messages
+}
+
+// AuthError returns when the credentials are missing, invalid or not
+// permitted to access the resource. Retrying the request will not help.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError returns when the requested resource does not exist, like a
+// missing issue. The entry belonging to the resource can be skipped.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError returns when the service rejected the request because too
+// many requests were sent. The request can be retried after RetryAfter, if
+// set by the service.
+type RateLimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError returns when the service rejected the request's payload,
+// like an entry missing a required field. Retrying the same request will not
+// help.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkError returns when the service cannot be reached or it is
+// temporarily unavailable. The request can be retried.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of the first typed error in the error's chain. If
+// the chain contains no typed errors, ErrorKindUnknown returns.
+func KindOf(err error) ErrorKind {
+	var authErr *AuthError
+	var notFoundErr *NotFoundError
+	var rateLimitErr *RateLimitError
+	var validationErr *ValidationError
+	var networkErr *NetworkError
+
+	switch {
+	case errors.As(err, &authErr):
+		return ErrorKindAuth
+	case errors.As(err, &notFoundErr):
+		return ErrorKindNotFound
+	case errors.As(err, &rateLimitErr):
+		return ErrorKindRateLimited
+	case errors.As(err, &validationErr):
+		return ErrorKindValidation
+	case errors.As(err, &networkErr):
+		return ErrorKindNetwork
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// IsRetryable returns true if retrying the failed operation may succeed.
+func IsRetryable(err error) bool {
+	kind := KindOf(err)
+	return kind == ErrorKindRateLimited || kind == ErrorKindNetwork
+}
+
+// RetryAfter returns the duration to wait before retrying the failed operation
+// as requested by the service. If the service did not request it, 0 returns.
+func RetryAfter(err error) time.Duration {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter
+	}
+
+	return 0
+}
+
+// StatusCodeOf returns the status code of the HTTPError in the error's chain.
+// If the chain contains no HTTPError, like for network errors, 0 returns.
+func StatusCodeOf(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+
+	return 0
+}
+
+// newStatusError returns the typed error of the unsuccessful HTTP response,
+// wrapping the HTTPError. If the status code cannot be classified, the
+// HTTPError returns.
+func newStatusError(resp *http.Response, body []byte) error {
+	err := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Messages:   parseErrorMessages(body),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthError{Err: err}
+	case http.StatusNotFound, http.StatusGone:
+		return &NotFoundError{Err: err}
+	case http.StatusTooManyRequests:
+		return &RateLimitError{Err: err, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+		return &ValidationError{Err: err}
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return &NetworkError{Err: err}
+	default:
+		return err
+	}
+}
+
+// parseRetryAfter parses the Retry-After header, given in seconds or as an
+// HTTP date. If the header is not set or invalid, 0 returns.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,178 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+// callWithStatus calls a server responding with the status code and header,
+// and returns the error of the call.
+func callWithStatus(t *testing.T, statusCode int, header map[string]string) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, value := range header {
+			w.Header().Set(key, value)
+		}
+
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("error"))
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL)
+	require.Nil(t, err)
+
+	httpClient := client.HTTPClient{BaseURL: baseURL}
+
+	_, err = httpClient.Call(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     server.URL,
+		Timeout: client.DefaultRequestTimeout,
+	})
+
+	return err
+}
+
+func TestHTTPClient_Call_ErrorKinds(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+		kind       client.ErrorKind
+		retryable  bool
+	}{
+		"unauthorized": {
+			statusCode: http.StatusUnauthorized,
+			kind:       client.ErrorKindAuth,
+		},
+		"forbidden": {
+			statusCode: http.StatusForbidden,
+			kind:       client.ErrorKindAuth,
+		},
+		"not found": {
+			statusCode: http.StatusNotFound,
+			kind:       client.ErrorKindNotFound,
+		},
+		"too many requests": {
+			statusCode: http.StatusTooManyRequests,
+			kind:       client.ErrorKindRateLimited,
+			retryable:  true,
+		},
+		"bad request": {
+			statusCode: http.StatusBadRequest,
+			kind:       client.ErrorKindValidation,
+		},
+		"service unavailable": {
+			statusCode: http.StatusServiceUnavailable,
+			kind:       client.ErrorKindNetwork,
+			retryable:  true,
+		},
+		"internal server error": {
+			statusCode: http.StatusInternalServerError,
+			kind:       client.ErrorKindUnknown,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := callWithStatus(t, test.statusCode, map[string]string{})
+			require.ErrorContains(t, err, fmt.Sprintf("%d: error", test.statusCode))
+
+			wrappedErr := fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+			require.ErrorIs(t, wrappedErr, client.ErrFetchEntries)
+			require.Equal(t, test.kind, client.KindOf(wrappedErr))
+			require.Equal(t, test.retryable, client.IsRetryable(wrappedErr))
+			require.Equal(t, test.statusCode, client.StatusCodeOf(wrappedErr))
+		})
+	}
+}
+
+func TestHTTPClient_Call_RetryAfter(t *testing.T) {
+	err := callWithStatus(t, http.StatusTooManyRequests, map[string]string{"Retry-After": "3"})
+
+	var rateLimitErr *client.RateLimitError
+	require.True(t, errors.As(err, &rateLimitErr))
+	require.Equal(t, time.Second*3, client.RetryAfter(err))
+	require.Equal(t, time.Duration(0), client.RetryAfter(errors.New("other")))
+}
+
+func TestHTTPClient_Call_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	httpClient := client.HTTPClient{}
+
+	_, err := httpClient.Call(context.Background(), &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     server.URL,
+		Timeout: client.DefaultRequestTimeout,
+	})
+
+	require.Equal(t, client.ErrorKindNetwork, client.KindOf(err))
+	require.True(t, client.IsRetryable(err))
+	require.Equal(t, 0, client.StatusCodeOf(err))
+}
+
+func TestHTTPError_Messages(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected string
+	}{
+		"jira": {
+			body:     `{"errorMessages":["Issue does not exist"],"errors":{"timeSpent":"Time spent is required","comment":"Too long"}}`,
+			expected: "400: Issue does not exist; comment: Too long; timeSpent: Time spent is required",
+		},
+		"jira service management": {
+			body:     `{"errorMessage":"The request does not exist"}`,
+			expected: "400: The request does not exist",
+		},
+		"tempo": {
+			body:     `{"errors":[{"message":"Work attribute _Team_ is required"}]}`,
+			expected: "400: Work attribute _Team_ is required",
+		},
+		"unknown": {
+			body:     " something went wrong\n",
+			expected: "400: something went wrong",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			httpClient := client.HTTPClient{}
+			_, err := httpClient.Call(context.Background(), &client.HTTPRequestOpts{
+				Method:  http.MethodGet,
+				Url:     server.URL,
+				Timeout: client.DefaultRequestTimeout,
+			})
+
+			var httpErr *client.HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+			require.Equal(t, test.body, string(httpErr.Body))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestRowError(t *testing.T) {
+	err := fmt.Errorf("%w: %w", client.ErrFetchEntries, &client.RowError{Line: 3, Column: "duration", Err: errors.New("invalid duration")})
+	require.EqualError(t, err, client.ErrFetchEntries.Error()+": line 3: duration: invalid duration")
+
+	var rowErr *client.RowError
+	require.True(t, errors.As(err, &rowErr))
+	require.Equal(t, 3, rowErr.Line)
+
+	require.EqualError(t, &client.RowError{Line: 4, Err: errors.New("too few columns")}, "line 4: too few columns")
+}
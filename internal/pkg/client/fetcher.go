@@ -3,7 +3,7 @@ package client
 import (
 	"context"
 	"errors"
-	"regexp"
+	"fmt"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
@@ -29,39 +29,148 @@ var (
 // In contract to the BaseClientOpts, these options shall not be extended or
 // overridden.
 type FetchOpts struct {
-	User  string
+	User string
+	// Start and End are the boundaries of the fetched period, which is half
+	// open: the entries starting at Start are fetched, but the entries starting
+	// at End are not. Hence, consecutive periods neither lose nor duplicate the
+	// entries at their boundary. The dates of the period, like the midnight of
+	// the days, are in the location of Start and End.
 	Start time.Time
 	End   time.Time
+}
+
+// LastDay returns the last day of the period, used by the sources fetching
+// the entries of inclusive date ranges, like from Start to LastDay.
+func (o *FetchOpts) LastDay() time.Time {
+	return o.End.Add(-time.Nanosecond)
+}
+
+// Contains returns true if the time is within the period.
+func (o *FetchOpts) Contains(t time.Time) bool {
+	return !t.Before(o.Start) && t.Before(o.End)
+}
+
+// FilterEntries returns the entries starting within the period. The sources
+// having the start time of the entries use it to drop the entries returned by
+// the APIs overlapping, but not starting within the period.
+func (o *FetchOpts) FilterEntries(entries worklog.Entries) worklog.Entries {
+	var filtered worklog.Entries
+
+	for _, entry := range entries {
+		if o.Contains(entry.Start) {
+			filtered = append(filtered, entry)
+		}
+	}
 
-	// TagsAsTasksRegex sets the regular expression used for extracting tasks
-	// from the list of tags.
-	TagsAsTasksRegex *regexp.Regexp
+	return filtered
+}
+
+// Chunks splits the period into consecutive periods of the given number of
+// days at most. The chunks end at the midnight of the days in the location of
+// Start, so the sources querying the dates of the period neither lose nor
+// duplicate the entries at the boundaries. If the number of days is not
+// positive, the period is returned as the only chunk.
+func (o *FetchOpts) Chunks(days int) []*FetchOpts {
+	if days <= 0 || !o.Start.Before(o.End) {
+		chunk := *o
+		return []*FetchOpts{&chunk}
+	}
+
+	var chunks []*FetchOpts
+
+	for start := o.Start; start.Before(o.End); {
+		midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+		end := midnight.AddDate(0, 0, days)
+		if end.After(o.End) {
+			end = o.End
+		}
+
+		chunk := *o
+		chunk.Start = start
+		chunk.End = end
+
+		chunks = append(chunks, &chunk)
+		start = end
+	}
+
+	return chunks
 }
 
 // Fetcher specifies the functions used to fetch worklog entries.
 type Fetcher interface {
 	// FetchEntries from a given source and return the list of worklog entries
 	// If the fetching resulted in an error, the list of worklog entries will be
-	// nil and an error will return.
+	// nil and an error will return. File based sources may return the entries
+	// of the valid rows together with RowErrors, if not all rows are valid.
 	FetchEntries(ctx context.Context, opts *FetchOpts) (worklog.Entries, error)
 }
 
+// RangeLimiter is implemented by the fetchers of the APIs capping the period
+// queried at once, like to a year. The longer periods are fetched by
+// FetchChunked in consecutive chunks.
+type RangeLimiter interface {
+	// MaxRangeDays returns the number of days queried at once at most.
+	MaxRangeDays() int
+}
+
+// FetchChunked fetches the entries of the period using the fetcher. If the
+// fetcher limits the range queried at once, the period is split into chunks
+// fetched one after the other, and the entries of the chunks are merged in
+// the order of the chunks. If fetching a chunk fails, no entries return.
+func FetchChunked(ctx context.Context, fetcher Fetcher, opts *FetchOpts) (worklog.Entries, error) {
+	limiter, ok := fetcher.(RangeLimiter)
+	if !ok {
+		return fetcher.FetchEntries(ctx, opts)
+	}
+
+	chunks := opts.Chunks(limiter.MaxRangeDays())
+	if len(chunks) == 1 {
+		return fetcher.FetchEntries(ctx, opts)
+	}
+
+	var entries worklog.Entries
+
+	for _, chunk := range chunks {
+		chunkEntries, err := fetcher.FetchEntries(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %s - %s: %w", chunk.Start.Format(time.DateOnly), chunk.LastDay().Format(time.DateOnly), err)
+		}
+
+		entries = append(entries, chunkEntries...)
+	}
+
+	return entries, nil
+}
+
+// PaginatedFetchResponse represents the pagination details of a fetched
+// page. If the number of entries per page is set, it overrides the page size
+// requested. If the total number of entries is known, the fetching stops when
+// every entry is fetched, otherwise, when an empty page is returned.
 type PaginatedFetchResponse struct {
 	EntriesPerPage int
 	TotalEntries   int
 }
 
-type PaginatedFetchFunc = func(context.Context, string) (interface{}, *PaginatedFetchResponse, error)
-type PaginatedParseFunc = func(interface{}, *FetchOpts) (worklog.Entries, error)
+// PaginatedFetchFunc fetches the items of the page from the URL, having the
+// page and page size set as query params.
+type PaginatedFetchFunc[T any] func(context.Context, string) ([]T, *PaginatedFetchResponse, error)
+
+// PaginatedParseFunc parses the fetched items of a page into entries.
+type PaginatedParseFunc[T any] func([]T, *FetchOpts) (worklog.Entries, error)
 
-type PaginatedFetchOpts struct {
+// PaginatedFetchOpts specifies the options of fetching the pages of T items.
+type PaginatedFetchOpts[T any] struct {
 	BaseFetchOpts *FetchOpts
 
 	URL           string
 	PageSize      int
 	PageSizeParam string
 	PageParam     string
+	// OffsetParam sets the query param of the number of items to skip. If set,
+	// the pages are requested by offset instead of page number.
+	OffsetParam string
 
-	FetchFunc PaginatedFetchFunc
-	ParseFunc PaginatedParseFunc
+	FetchFunc PaginatedFetchFunc[T]
+	ParseFunc PaginatedParseFunc[T]
 }
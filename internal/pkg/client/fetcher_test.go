@@ -0,0 +1,134 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchOpts_Period(t *testing.T) {
+	opts := &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	require.Equal(t, "2021-10-02", opts.LastDay().Format("2006-01-02"))
+
+	require.True(t, opts.Contains(opts.Start))
+	require.True(t, opts.Contains(opts.End.Add(-time.Second)))
+	require.False(t, opts.Contains(opts.End))
+	require.False(t, opts.Contains(opts.Start.Add(-time.Second)))
+
+	entries := worklog.Entries{
+		{Summary: "before", Start: opts.Start.Add(-time.Hour)},
+		{Summary: "first", Start: opts.Start},
+		{Summary: "last", Start: opts.End.Add(-time.Hour)},
+		{Summary: "after", Start: opts.End},
+	}
+
+	filtered := opts.FilterEntries(entries)
+	require.Len(t, filtered, 2)
+	require.Equal(t, "first", filtered[0].Summary)
+	require.Equal(t, "last", filtered[1].Summary)
+}
+
+func TestFetchOpts_Chunks(t *testing.T) {
+	opts := &client.FetchOpts{
+		User:  "jdoe",
+		Start: time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 6, 6, 0, 0, 0, time.UTC),
+	}
+
+	chunks := opts.Chunks(2)
+	require.Len(t, chunks, 3)
+
+	require.Equal(t, opts.Start, chunks[0].Start)
+	require.Equal(t, time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC), chunks[0].End)
+	require.Equal(t, time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC), chunks[1].Start)
+	require.Equal(t, time.Date(2021, 10, 5, 0, 0, 0, 0, time.UTC), chunks[1].End)
+	require.Equal(t, time.Date(2021, 10, 5, 0, 0, 0, 0, time.UTC), chunks[2].Start)
+	require.Equal(t, opts.End, chunks[2].End)
+
+	for _, chunk := range chunks {
+		require.Equal(t, opts.User, chunk.User)
+	}
+}
+
+func TestFetchOpts_Chunks_Unlimited(t *testing.T) {
+	opts := &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+	}
+
+	require.Equal(t, []*client.FetchOpts{opts}, opts.Chunks(0))
+	require.Equal(t, []*client.FetchOpts{opts}, opts.Chunks(5))
+}
+
+type mockFetcher struct {
+	periods []string
+	err     error
+}
+
+func (f *mockFetcher) FetchEntries(_ context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	period := opts.Start.Format(time.DateOnly) + " - " + opts.LastDay().Format(time.DateOnly)
+	f.periods = append(f.periods, period)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return worklog.Entries{{Summary: period, Start: opts.Start}}, nil
+}
+
+type mockRangeLimiter struct {
+	mockFetcher
+}
+
+func (f *mockRangeLimiter) MaxRangeDays() int {
+	return 31
+}
+
+func TestFetchChunked(t *testing.T) {
+	fetcher := &mockRangeLimiter{}
+
+	entries, err := client.FetchChunked(context.Background(), fetcher, &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, err)
+
+	expectedPeriods := []string{"2021-10-01 - 2021-10-31", "2021-11-01 - 2021-11-30"}
+	require.Equal(t, expectedPeriods, fetcher.periods)
+	require.Len(t, entries, 2)
+	require.Equal(t, expectedPeriods[0], entries[0].Summary)
+	require.Equal(t, expectedPeriods[1], entries[1].Summary)
+}
+
+func TestFetchChunked_Unlimited(t *testing.T) {
+	fetcher := &mockFetcher{}
+
+	entries, err := client.FetchChunked(context.Background(), fetcher, &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"2021-10-01 - 2021-11-30"}, fetcher.periods)
+	require.Len(t, entries, 1)
+}
+
+func TestFetchChunked_Error(t *testing.T) {
+	fetcher := &mockRangeLimiter{mockFetcher{err: client.ErrFetchEntries}}
+
+	entries, err := client.FetchChunked(context.Background(), fetcher, &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.EqualError(t, err, "chunk 2021-10-01 - 2021-10-31: failed to fetch entries")
+	require.Len(t, fetcher.periods, 1)
+}
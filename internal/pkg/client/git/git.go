@@ -0,0 +1,393 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// TimeSpentTrailer is the commit message trailer declaring the time spent
+	// on the commit, like "Time-Spent: 2h30m CPT-2014".
+	TimeSpentTrailer string = "Time-Spent"
+
+	// AttributeBranch is the name of the attribute containing the branch the
+	// commit was read from, if known.
+	AttributeBranch string = "git.branch"
+
+	// DurationStrategyTrailer reads only the commits declaring the time spent
+	// by Time-Spent trailers.
+	DurationStrategyTrailer string = "trailer"
+	// DurationStrategyFixed assumes a fixed duration spent on the commits
+	// without Time-Spent trailer.
+	DurationStrategyFixed string = "fixed"
+	// DurationStrategyGap infers the time spent on the commits without
+	// Time-Spent trailer from the time elapsed since the previous commit of
+	// the author. If the gap is too long, the fixed duration is assumed.
+	DurationStrategyGap string = "gap"
+
+	// DefaultAssumedDuration is the duration assumed for the commits without
+	// Time-Spent trailer, if not configured.
+	DefaultAssumedDuration time.Duration = time.Minute * 30
+	// DefaultMaxGap is the longest gap between two commits considered as
+	// continuous work, if not configured.
+	DefaultMaxGap time.Duration = time.Hour * 2
+
+	// fieldSeparator separates the fields of a commit in the log output.
+	fieldSeparator string = "\x1f"
+	// trailerSeparator separates the values of the trailers of a commit.
+	trailerSeparator string = "\x1e"
+)
+
+var (
+	// ErrInvalidTimeSpent returns when a Time-Spent trailer cannot be parsed.
+	ErrInvalidTimeSpent = errors.New("invalid time spent trailer")
+	// ErrUnknownDurationStrategy returns when the duration strategy is not
+	// part of the DurationStrategies.
+	ErrUnknownDurationStrategy = errors.New("unknown duration strategy")
+)
+
+// DurationStrategies lists the strategies of setting the time spent on the
+// commits.
+var DurationStrategies = []string{DurationStrategyTrailer, DurationStrategyFixed, DurationStrategyGap}
+
+// refPrefixes lists the prefixes removed from the refs to get the branch
+// names.
+var refPrefixes = []string{"refs/heads/", "refs/remotes/", "refs/tags/"}
+
+// logFormat is the format of the commits printed by git log. The fields are
+// the hash, the author date and email, the ref the commit was reached from,
+// the subject and the values of the Time-Spent trailers.
+var logFormat = strings.Join([]string{
+	"%H",
+	"%aI",
+	"%ae",
+	"%S",
+	"%s",
+	"%(trailers:key=" + TimeSpentTrailer + ",valueonly,separator=%x1e)",
+}, "%x1f")
+
+// Commit represents a commit logged by git.
+type Commit struct {
+	Hash        string
+	AuthorDate  time.Time
+	AuthorEmail string
+	// Branch is the name of the branch the commit was reached from, like
+	// "feature/CPT-2014". If not known, it is empty.
+	Branch  string
+	Subject string
+	// TimeSpent is the list of the values of the Time-Spent trailers.
+	TimeSpent []string
+}
+
+// TimeSpent represents the value of a Time-Spent trailer.
+type TimeSpent struct {
+	Duration time.Duration
+	// Task is the key of the task the time was spent on. If not declared, it
+	// is empty.
+	Task string
+}
+
+// ParseTimeSpent parses the value of a Time-Spent trailer, like "2h30m" or
+// "2h30m CPT-2014". The duration is followed by the task key optionally.
+func ParseTimeSpent(value string) (*TimeSpent, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeSpent, value)
+	}
+
+	duration, err := time.ParseDuration(fields[0])
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeSpent, value)
+	}
+
+	timeSpent := &TimeSpent{Duration: duration}
+	if len(fields) == 2 {
+		timeSpent.Task = fields[1]
+	}
+
+	return timeSpent, nil
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Like Timewarrior, git is a CLI tool, hence the client.HTTPClientOpts of
+// client.BaseClientOpts is not used, instead the path of the executable
+// (Command) and the additional arguments of git log (CommandArguments) are
+// defined.
+type ClientOpts struct {
+	client.BaseClientOpts
+	client.CLIClient
+	// Repositories is the list of the paths of the repositories read.
+	Repositories []string
+	// DurationStrategy is one of the DurationStrategies, setting the time
+	// spent on the commits without Time-Spent trailer. If not set,
+	// DurationStrategyTrailer is used, skipping these commits.
+	DurationStrategy string
+	// AssumedDuration is the time spent on the commits without Time-Spent
+	// trailer by DurationStrategyFixed, and on the first commits after a long
+	// gap by DurationStrategyGap. If not set, DefaultAssumedDuration is used.
+	AssumedDuration time.Duration
+	// MaxGap is the longest gap between two commits of the author considered
+	// as continuous work by DurationStrategyGap. If not set, DefaultMaxGap is
+	// used.
+	MaxGap time.Duration
+}
+
+type gitClient struct {
+	*client.BaseClientOpts
+	*client.CLIClient
+	repositories     []string
+	durationStrategy string
+	assumedDuration  time.Duration
+	maxGap           time.Duration
+}
+
+// branchName returns the name of the branch of the ref, like "main" for
+// "refs/heads/main". The refs not pointing to branches or tags, like "HEAD",
+// have no branch name.
+func branchName(ref string) string {
+	for _, prefix := range refPrefixes {
+		if name, found := strings.CutPrefix(ref, prefix); found {
+			// The remote branches are prefixed by the name of the remote
+			if prefix == "refs/remotes/" {
+				_, name, _ = strings.Cut(name, "/")
+			}
+
+			return name
+		}
+	}
+
+	return ""
+}
+
+func (c *gitClient) parseLog(out []byte) ([]Commit, error) {
+	var commits []Commit
+
+	for _, record := range bytes.Split(out, []byte{0}) {
+		record = bytes.TrimSpace(record)
+		if len(record) == 0 {
+			continue
+		}
+
+		fields := strings.Split(string(record), fieldSeparator)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("unexpected log record: %q", record)
+		}
+
+		authorDate, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		commit := Commit{
+			Hash:        fields[0],
+			AuthorDate:  authorDate,
+			AuthorEmail: fields[2],
+			Branch:      branchName(fields[3]),
+			Subject:     fields[4],
+		}
+
+		for _, value := range strings.Split(fields[5], trailerSeparator) {
+			if value = strings.TrimSpace(value); value != "" {
+				commit.TimeSpent = append(commit.TimeSpent, value)
+			}
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// newEntry returns the entry of the time spent on the commit. The time was
+// spent before the commit was authored. If the task was not declared, it is
+// extracted from the subject or else from the branch name of the commit.
+func newEntry(commit Commit, project string, task string, duration time.Duration) worklog.Entry {
+	entry := worklog.Entry{
+		Project: worklog.IDNameField{
+			ID:   project,
+			Name: project,
+		},
+		Task: worklog.IDNameField{
+			ID:   task,
+			Name: task,
+		},
+		Summary:          commit.Subject,
+		Notes:            commit.Subject,
+		Start:            commit.AuthorDate.Local().Add(-duration),
+		BillableDuration: duration,
+		Provenance:       worklog.Provenance{SourceIDs: []string{commit.Hash}},
+	}
+
+	if task == "" {
+		entry.TaskHints = &worklog.TaskHints{Texts: []string{commit.Subject, commit.Branch}}
+	}
+
+	if commit.Branch != "" {
+		entry.SetAttribute(AttributeBranch, commit.Branch)
+	}
+
+	return entry
+}
+
+func (c *gitClient) parseCommit(commit Commit, project string) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, value := range commit.TimeSpent {
+		timeSpent, err := ParseTimeSpent(value)
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: %w", commit.Hash, err)
+		}
+
+		entries = append(entries, newEntry(commit, project, timeSpent.Task, timeSpent.Duration))
+	}
+
+	return entries, nil
+}
+
+// inferDuration returns the time spent on the commit without Time-Spent
+// trailer by the duration strategy. The previous commit is the previous commit
+// of the same author, or nil if there is none.
+func (c *gitClient) inferDuration(commit Commit, previous *Commit) time.Duration {
+	if c.durationStrategy == DurationStrategyGap && previous != nil {
+		if gap := commit.AuthorDate.Sub(previous.AuthorDate); gap > 0 && gap <= c.maxGap {
+			return gap
+		}
+	}
+
+	return c.assumedDuration
+}
+
+// parseCommits returns the entries of the commits. The commits without
+// Time-Spent trailer are skipped, unless their duration can be inferred.
+func (c *gitClient) parseCommits(commits []Commit, project string) (worklog.Entries, error) {
+	// The gaps are calculated in the order the commits were authored
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].AuthorDate.Before(commits[j].AuthorDate)
+	})
+
+	var entries worklog.Entries
+	previousCommits := map[string]*Commit{}
+
+	for i := range commits {
+		commit := commits[i]
+		previous := previousCommits[commit.AuthorEmail]
+		previousCommits[commit.AuthorEmail] = &commits[i]
+
+		if len(commit.TimeSpent) == 0 {
+			if c.durationStrategy != DurationStrategyTrailer {
+				entries = append(entries, newEntry(commit, project, "", c.inferDuration(commit, previous)))
+			}
+
+			continue
+		}
+
+		commitEntries, err := c.parseCommit(commit, project)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, commitEntries...)
+	}
+
+	return entries, nil
+}
+
+func (c *gitClient) fetchRepositoryEntries(ctx context.Context, repository string, opts *client.FetchOpts) (worklog.Entries, error) {
+	path, err := filepath.Abs(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	// The commits are filtered by the committer date, which is not before the
+	// author date, hence the commits of the time spent within the period are
+	// never filtered out. The gaps are inferred from the previous commits,
+	// which may be before the period.
+	since := opts.Start
+	if c.durationStrategy == DurationStrategyGap {
+		since = since.Add(-c.maxGap)
+	}
+
+	arguments := []string{
+		"-C", path,
+		"log",
+		"-z",
+		"--no-merges",
+		"--source",
+		"--format=" + logFormat,
+		"--since=" + since.Format(time.RFC3339),
+	}
+
+	if opts.User != "" {
+		arguments = append(arguments, "--author="+opts.User)
+	}
+
+	arguments = append(arguments, c.CommandArguments...)
+
+	out, err := c.Execute(ctx, arguments, &client.CLIExecuteOpts{
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := c.parseLog(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parseCommits(commits, filepath.Base(path))
+}
+
+func (c *gitClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, repository := range c.repositories {
+		repositoryEntries, err := c.fetchRepositoryEntries(ctx, repository, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", client.ErrFetchEntries, repository, err)
+		}
+
+		entries = append(entries, repositoryEntries...)
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new git client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	gitClient := &gitClient{
+		BaseClientOpts:   &opts.BaseClientOpts,
+		CLIClient:        &opts.CLIClient,
+		repositories:     opts.Repositories,
+		durationStrategy: opts.DurationStrategy,
+		assumedDuration:  opts.AssumedDuration,
+		maxGap:           opts.MaxGap,
+	}
+
+	switch gitClient.durationStrategy {
+	case "":
+		gitClient.durationStrategy = DurationStrategyTrailer
+	case DurationStrategyTrailer, DurationStrategyFixed, DurationStrategyGap:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDurationStrategy, opts.DurationStrategy)
+	}
+
+	if gitClient.assumedDuration <= 0 {
+		gitClient.assumedDuration = DefaultAssumedDuration
+	}
+
+	if gitClient.maxGap <= 0 {
+		gitClient.maxGap = DefaultMaxGap
+	}
+
+	return gitClient, nil
+}
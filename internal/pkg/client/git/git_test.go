@@ -0,0 +1,266 @@
+package git_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/git"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockedExitCode  int
+	mockedStdout    string
+	mockedArguments []string
+)
+
+func mockedExecCommand(_ context.Context, command string, args ...string) *exec.Cmd {
+	mockedArguments = args
+
+	arguments := []string{"-test.run=TestExecCommandHelper", "--", command}
+	arguments = append(arguments, args...)
+	cmd := exec.Command(os.Args[0], arguments...)
+
+	// The log records are separated by NUL characters, which cannot be passed
+	// in environment variables, hence the output is encoded
+	cmd.Env = []string{"GO_TEST_HELPER_PROCESS=1",
+		"STDOUT=" + base64.StdEncoding.EncodeToString([]byte(mockedStdout)),
+		"EXIT_CODE=" + strconv.Itoa(mockedExitCode),
+	}
+
+	return cmd
+}
+
+// TestExecCommandHelper is a helper test case that will be called by `mockedExecCommand`.
+// This workaround is needed to be able to "mock" system calls.
+func TestExecCommandHelper(t *testing.T) {
+	// Not executed by the mocked command function, so return
+	if os.Getenv("GO_TEST_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	stdout, _ := base64.StdEncoding.DecodeString(os.Getenv("STDOUT"))
+	_, _ = fmt.Fprint(os.Stdout, string(stdout))
+	exitCode, _ := strconv.Atoi(os.Getenv("EXIT_CODE"))
+	os.Exit(exitCode)
+}
+
+func logRecord(hash string, authorDate string, subject string, timeSpent ...string) string {
+	return refLogRecord(hash, authorDate, "jane@example.com", "HEAD", subject, timeSpent...)
+}
+
+func refLogRecord(hash string, authorDate string, authorEmail string, ref string, subject string, timeSpent ...string) string {
+	return strings.Join([]string{hash, authorDate, authorEmail, ref, subject, strings.Join(timeSpent, "\x1e")}, "\x1f") + "\x00"
+}
+
+func TestParseTimeSpent(t *testing.T) {
+	timeSpent, err := git.ParseTimeSpent("2h30m CPT-2014")
+	require.Nil(t, err)
+	require.Equal(t, &git.TimeSpent{Duration: time.Hour*2 + time.Minute*30, Task: "CPT-2014"}, timeSpent)
+
+	timeSpent, err = git.ParseTimeSpent(" 45m ")
+	require.Nil(t, err)
+	require.Equal(t, &git.TimeSpent{Duration: time.Minute * 45}, timeSpent)
+
+	for _, value := range []string{"", "CPT-2014", "2h30m CPT-2014 CPT-2015", "-1h CPT-2014", "0s"} {
+		_, err = git.ParseTimeSpent(value)
+		require.ErrorIs(t, err, git.ErrInvalidTimeSpent, value)
+	}
+}
+
+func TestGitClient_FetchEntries(t *testing.T) {
+	authorDate, _ := time.Parse(time.RFC3339, "2021-10-12T11:00:00+02:00")
+	start := authorDate.Add(-time.Hour * 12).Local()
+	end := authorDate.Add(time.Hour * 12).Local()
+
+	mockedExitCode = 0
+	mockedStdout = logRecord("a1b2c3", "2021-10-12T11:00:00+02:00", "CPT-1 Fix the login", "2h30m CPT-2014", "15m") +
+		"\n" + logRecord("d4e5f6", "2021-10-12T12:00:00+02:00", "Update the readme") +
+		"\n" + logRecord("0a0b0c", "2021-10-11T09:00:00+02:00", "Old commit", "1h CPT-1")
+
+	repository := t.TempDir()
+	project := filepath.Base(repository)
+
+	expectedEntries := worklog.Entries{
+		{
+			Project: worklog.IDNameField{
+				ID:   project,
+				Name: project,
+			},
+			Task: worklog.IDNameField{
+				ID:   "CPT-2014",
+				Name: "CPT-2014",
+			},
+			Summary:          "CPT-1 Fix the login",
+			Notes:            "CPT-1 Fix the login",
+			Start:            authorDate.Local().Add(-time.Hour*2 - time.Minute*30),
+			BillableDuration: time.Hour*2 + time.Minute*30,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"a1b2c3"}},
+		},
+		{
+			Project: worklog.IDNameField{
+				ID:   project,
+				Name: project,
+			},
+			Task: worklog.IDNameField{
+				ID:   "CPT-1",
+				Name: "CPT-1",
+			},
+			Summary:          "CPT-1 Fix the login",
+			Notes:            "CPT-1 Fix the login",
+			Start:            authorDate.Local().Add(-time.Minute * 15),
+			BillableDuration: time.Minute * 15,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"a1b2c3"}},
+		},
+	}
+
+	gitClient, err := git.NewFetcher(&git.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		CLIClient: client.CLIClient{
+			Command:            "git",
+			CommandArguments:   []string{"--all"},
+			CommandCtxExecutor: mockedExecCommand,
+		},
+		Repositories: []string{repository},
+	})
+	require.Nil(t, err)
+
+	entries, err := gitClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "jane@example.com",
+		Start: start,
+		End:   end,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`[A-Z]+-\d+`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+
+	require.Equal(t, []string{"-C", repository, "log"}, mockedArguments[:3])
+	require.Contains(t, mockedArguments, "--since="+start.Format(time.RFC3339))
+	require.Contains(t, mockedArguments, "--author=jane@example.com")
+	require.Equal(t, "--all", mockedArguments[len(mockedArguments)-1])
+}
+
+func TestGitClient_FetchEntries_InvalidTimeSpent(t *testing.T) {
+	mockedExitCode = 0
+	mockedStdout = logRecord("a1b2c3", "2021-10-12T11:00:00+02:00", "Fix the login", "two hours")
+
+	gitClient, err := git.NewFetcher(&git.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		CLIClient: client.CLIClient{
+			Command:            "git",
+			CommandCtxExecutor: mockedExecCommand,
+		},
+		Repositories: []string{t.TempDir()},
+	})
+	require.Nil(t, err)
+
+	_, err = gitClient.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 12, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 13, 0, 0, 0, 0, time.Local),
+	})
+
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, git.ErrInvalidTimeSpent)
+	require.ErrorContains(t, err, "a1b2c3")
+}
+
+func TestGitClient_FetchEntries_InferredDuration(t *testing.T) {
+	mockedExitCode = 0
+	mockedStdout = refLogRecord("c3", "2021-10-12T13:30:00+02:00", "jane@example.com", "refs/heads/feature/CPT-2014", "Fix the tests") +
+		refLogRecord("b2", "2021-10-12T10:45:00+02:00", "jane@example.com", "refs/remotes/origin/feature/CPT-2014", "Fix the login") +
+		refLogRecord("x9", "2021-10-12T10:30:00+02:00", "john@example.com", "HEAD", "CPT-7 Update the readme") +
+		refLogRecord("a1", "2021-10-12T10:00:00+02:00", "jane@example.com", "refs/heads/main", "CPT-1 Start the login", "1h")
+
+	newFetcher := func(strategy string) client.Fetcher {
+		gitClient, err := git.NewFetcher(&git.ClientOpts{
+			BaseClientOpts: client.BaseClientOpts{
+				Timeout: client.DefaultRequestTimeout,
+			},
+			CLIClient: client.CLIClient{
+				Command:            "git",
+				CommandCtxExecutor: mockedExecCommand,
+			},
+			Repositories:     []string{t.TempDir()},
+			DurationStrategy: strategy,
+			AssumedDuration:  time.Minute * 20,
+			MaxGap:           time.Hour,
+		})
+		require.Nil(t, err)
+
+		return gitClient
+	}
+
+	fetchOpts := &client.FetchOpts{
+		Start: time.Date(2021, 10, 12, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 13, 0, 0, 0, 0, time.Local),
+	}
+
+	durationsOf := func(entries worklog.Entries) map[string]time.Duration {
+		durations := map[string]time.Duration{}
+		for _, entry := range entries {
+			durations[entry.Provenance.SourceIDs[0]] = entry.BillableDuration
+		}
+
+		return durations
+	}
+
+	entries, err := newFetcher(git.DurationStrategyTrailer).FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Equal(t, map[string]time.Duration{"a1": time.Hour}, durationsOf(entries))
+
+	entries, err = newFetcher(git.DurationStrategyFixed).FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Equal(t, map[string]time.Duration{
+		"a1": time.Hour,
+		"b2": time.Minute * 20,
+		"x9": time.Minute * 20,
+		"c3": time.Minute * 20,
+	}, durationsOf(entries))
+
+	entries, err = newFetcher(git.DurationStrategyGap).FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Equal(t, map[string]time.Duration{
+		"a1": time.Hour,
+		// The gaps are calculated per author, and the long gaps fall back to
+		// the assumed duration
+		"b2": time.Minute * 45,
+		"x9": time.Minute * 20,
+		"c3": time.Minute * 20,
+	}, durationsOf(entries))
+
+	require.Contains(t, mockedArguments, "--since="+fetchOpts.Start.Add(-time.Hour).Format(time.RFC3339))
+
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`[A-Z]+-\d+`), worklog.TaskExtractionTags)
+	for _, entry := range entries {
+		switch entry.Provenance.SourceIDs[0] {
+		case "b2":
+			// The task is looked up in the branch name, if not in the subject
+			require.Equal(t, "CPT-2014", entry.Task.Name)
+			require.Equal(t, "feature/CPT-2014", entry.Attributes[git.AttributeBranch])
+		case "x9":
+			require.Equal(t, "CPT-7", entry.Task.Name)
+			require.Empty(t, entry.Attributes[git.AttributeBranch])
+		}
+	}
+}
+
+func TestNewFetcher_UnknownDurationStrategy(t *testing.T) {
+	_, err := git.NewFetcher(&git.ClientOpts{DurationStrategy: "guess"})
+	require.ErrorIs(t, err, git.ErrUnknownDurationStrategy)
+}
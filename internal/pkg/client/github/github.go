@@ -0,0 +1,555 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the GitHub API.
+	DefaultURL string = "https://api.github.com"
+	// DefaultCommitDuration is the time accounted for the commits of a
+	// repository on a day.
+	DefaultCommitDuration time.Duration = time.Minute * 30
+	// DefaultReviewDuration is the time accounted for a pull request review.
+	DefaultReviewDuration time.Duration = time.Minute * 30
+	// DefaultCommentDuration is the time accounted for an issue or pull request
+	// comment.
+	DefaultCommentDuration time.Duration = time.Minute * 10
+	// MaxPageSize is the maximum number of nodes returned on a page.
+	MaxPageSize int = 100
+	// MaxRangeDays is the number of days the contributions are queried for at
+	// once, since GitHub limits the contributions to a year.
+	MaxRangeDays int = 365
+
+	// AttributeCommits is the name of the attribute containing the number of
+	// commits grouped into the entry.
+	AttributeCommits string = "github.commits"
+	// AttributeReviews is the name of the attribute containing the number of
+	// pull request reviews grouped into the entry.
+	AttributeReviews string = "github.reviews"
+	// AttributeComments is the name of the attribute containing the number of
+	// comments grouped into the entry.
+	AttributeComments string = "github.comments"
+)
+
+var (
+	viewerQuery = client.GraphQLQuery{
+		Name:      "Viewer",
+		Selection: "viewer { login }",
+	}
+
+	commitsQuery = client.GraphQLQuery{
+		Name: "CommitContributions",
+		Variables: []client.GraphQLVariable{
+			{Name: "login", Type: "String!"},
+			{Name: "from", Type: "DateTime!"},
+			{Name: "to", Type: "DateTime!"},
+		},
+		Selection: `user(login: $login) {
+			contributionsCollection(from: $from, to: $to) {
+				commitContributionsByRepository(maxRepositories: 100) {
+					repository { nameWithOwner url }
+					contributions(first: 100) { nodes { occurredAt commitCount url } }
+				}
+			}
+		}`,
+	}
+
+	reviewsQuery = client.GraphQLQuery{
+		Name: "ReviewContributions",
+		Variables: []client.GraphQLVariable{
+			{Name: "login", Type: "String!"},
+			{Name: "from", Type: "DateTime!"},
+			{Name: "to", Type: "DateTime!"},
+			{Name: "first", Type: "Int!"},
+			{Name: "after", Type: "String"},
+		},
+		Selection: `user(login: $login) {
+			contributionsCollection(from: $from, to: $to) {
+				pullRequestReviewContributions(first: $first, after: $after) {
+					pageInfo { hasNextPage endCursor }
+					nodes {
+						occurredAt
+						pullRequestReview { url }
+						pullRequest { number title repository { nameWithOwner } }
+					}
+				}
+			}
+		}`,
+	}
+
+	commentsQuery = client.GraphQLQuery{
+		Name: "IssueComments",
+		Variables: []client.GraphQLVariable{
+			{Name: "login", Type: "String!"},
+			{Name: "first", Type: "Int!"},
+			{Name: "after", Type: "String"},
+		},
+		Selection: `user(login: $login) {
+			issueComments(first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+				pageInfo { hasNextPage endCursor }
+				nodes {
+					createdAt
+					updatedAt
+					url
+					repository { nameWithOwner }
+					issue { number title }
+				}
+			}
+		}`,
+	}
+)
+
+// Repository represents the repository of an activity.
+type Repository struct {
+	NameWithOwner string `json:"nameWithOwner"`
+	URL           string `json:"url"`
+}
+
+// Issue represents the issue or pull request an activity is related to.
+type Issue struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	Repository Repository `json:"repository"`
+}
+
+// CommitContribution represents the commits of the user to a repository on a
+// day. Since GitHub reports the commits per day, the time of the contribution
+// is the start of the day.
+type CommitContribution struct {
+	OccurredAt  utils.Timestamp `json:"occurredAt"`
+	CommitCount int             `json:"commitCount"`
+	URL         string          `json:"url"`
+}
+
+// CommitContributions represents the commit contributions of a repository.
+type CommitContributions struct {
+	Repository    Repository `json:"repository"`
+	Contributions struct {
+		Nodes []CommitContribution `json:"nodes"`
+	} `json:"contributions"`
+}
+
+// ReviewContribution represents a pull request review of the user.
+type ReviewContribution struct {
+	OccurredAt        utils.Timestamp `json:"occurredAt"`
+	PullRequestReview struct {
+		URL string `json:"url"`
+	} `json:"pullRequestReview"`
+	PullRequest Issue `json:"pullRequest"`
+}
+
+// IssueComment represents a comment of the user on an issue or pull request.
+type IssueComment struct {
+	CreatedAt  utils.Timestamp `json:"createdAt"`
+	UpdatedAt  utils.Timestamp `json:"updatedAt"`
+	URL        string          `json:"url"`
+	Repository Repository      `json:"repository"`
+	Issue      Issue           `json:"issue"`
+}
+
+// activityKind is the kind of the user's activity on GitHub.
+type activityKind int
+
+const (
+	activityCommit activityKind = iota
+	activityReview
+	activityComment
+)
+
+// activity represents an activity of the user in a repository.
+type activity struct {
+	kind       activityKind
+	repository string
+	start      time.Time
+	count      int
+	issue      Issue
+	url        string
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Token is the personal access token used to authenticate.
+	Token string
+	// User is the login of the user the activity is fetched for. If not set,
+	// the owner of the token is used.
+	User string
+	// CommitDuration is the time accounted for the commits of a repository on
+	// a day. If not set, DefaultCommitDuration is used.
+	CommitDuration time.Duration
+	// ReviewDuration is the time accounted for a pull request review. If not
+	// set, DefaultReviewDuration is used.
+	ReviewDuration time.Duration
+	// CommentDuration is the time accounted for a comment. If not set,
+	// DefaultCommentDuration is used.
+	CommentDuration time.Duration
+}
+
+type gitHubClient struct {
+	*client.BaseClientOpts
+	*client.GraphQLClient
+	user            string
+	commitDuration  time.Duration
+	reviewDuration  time.Duration
+	commentDuration time.Duration
+}
+
+func (c *gitHubClient) fetchLogin(ctx context.Context) (string, error) {
+	if c.user != "" {
+		return c.user, nil
+	}
+
+	var data struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+
+	err := c.Query(ctx, &client.GraphQLRequest{
+		Query:         viewerQuery.String(),
+		OperationName: viewerQuery.Name,
+	}, &data)
+	if err != nil {
+		return "", err
+	}
+
+	return data.Viewer.Login, nil
+}
+
+func (c *gitHubClient) fetchCommits(ctx context.Context, login string, opts *client.FetchOpts) ([]activity, error) {
+	var data struct {
+		User struct {
+			ContributionsCollection struct {
+				CommitContributionsByRepository []CommitContributions `json:"commitContributionsByRepository"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+
+	err := c.Query(ctx, &client.GraphQLRequest{
+		Query:         commitsQuery.String(),
+		OperationName: commitsQuery.Name,
+		Variables: map[string]interface{}{
+			"login": login,
+			"from":  opts.Start,
+			"to":    opts.End,
+		},
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	var activities []activity
+	for _, contributions := range data.User.ContributionsCollection.CommitContributionsByRepository {
+		for _, contribution := range contributions.Contributions.Nodes {
+			activities = append(activities, activity{
+				kind:       activityCommit,
+				repository: contributions.Repository.NameWithOwner,
+				start:      contribution.OccurredAt.Time,
+				count:      contribution.CommitCount,
+				url:        contribution.URL,
+			})
+		}
+	}
+
+	return activities, nil
+}
+
+func (c *gitHubClient) fetchReviews(ctx context.Context, login string, opts *client.FetchOpts) ([]activity, error) {
+	var activities []activity
+
+	_, err := c.PaginatedFetch(ctx, &client.GraphQLPaginatedFetchOpts{
+		BaseFetchOpts: opts,
+		Request: &client.GraphQLRequest{
+			Query:         reviewsQuery.String(),
+			OperationName: reviewsQuery.Name,
+			Variables: map[string]interface{}{
+				"login": login,
+				"from":  opts.Start,
+				"to":    opts.End,
+			},
+		},
+		PageSize: MaxPageSize,
+		ParseFunc: func(raw json.RawMessage, _ *client.FetchOpts) (worklog.Entries, *client.PageInfo, error) {
+			var data struct {
+				User struct {
+					ContributionsCollection struct {
+						PullRequestReviewContributions struct {
+							PageInfo client.PageInfo      `json:"pageInfo"`
+							Nodes    []ReviewContribution `json:"nodes"`
+						} `json:"pullRequestReviewContributions"`
+					} `json:"contributionsCollection"`
+				} `json:"user"`
+			}
+
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, nil, err
+			}
+
+			connection := data.User.ContributionsCollection.PullRequestReviewContributions
+			for _, review := range connection.Nodes {
+				activities = append(activities, activity{
+					kind:       activityReview,
+					repository: review.PullRequest.Repository.NameWithOwner,
+					start:      review.OccurredAt.Time,
+					count:      1,
+					issue:      review.PullRequest,
+					url:        review.PullRequestReview.URL,
+				})
+			}
+
+			return nil, &connection.PageInfo, nil
+		},
+	})
+
+	return activities, err
+}
+
+func (c *gitHubClient) fetchComments(ctx context.Context, login string, opts *client.FetchOpts) ([]activity, error) {
+	var activities []activity
+
+	_, err := c.PaginatedFetch(ctx, &client.GraphQLPaginatedFetchOpts{
+		BaseFetchOpts: opts,
+		Request: &client.GraphQLRequest{
+			Query:         commentsQuery.String(),
+			OperationName: commentsQuery.Name,
+			Variables: map[string]interface{}{
+				"login": login,
+			},
+		},
+		PageSize: MaxPageSize,
+		ParseFunc: func(raw json.RawMessage, opts *client.FetchOpts) (worklog.Entries, *client.PageInfo, error) {
+			var data struct {
+				User struct {
+					IssueComments struct {
+						PageInfo client.PageInfo `json:"pageInfo"`
+						Nodes    []IssueComment  `json:"nodes"`
+					} `json:"issueComments"`
+				} `json:"user"`
+			}
+
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, nil, err
+			}
+
+			connection := data.User.IssueComments
+			for _, comment := range connection.Nodes {
+				// The comments are ordered by their last update, so the
+				// remaining comments were created before the period too
+				if comment.UpdatedAt.Before(opts.Start) {
+					return nil, nil, nil
+				}
+
+				if !opts.Contains(comment.CreatedAt.Time) {
+					continue
+				}
+
+				activities = append(activities, activity{
+					kind:       activityComment,
+					repository: comment.Repository.NameWithOwner,
+					start:      comment.CreatedAt.Time,
+					count:      1,
+					issue:      comment.Issue,
+					url:        comment.URL,
+				})
+			}
+
+			return nil, &connection.PageInfo, nil
+		},
+	})
+
+	return activities, err
+}
+
+// groupActivities combines the activities of the same repository on the same
+// day into one entry, starting at the earliest activity. The duration of the
+// entry is the sum of the durations accounted for the activities, and the
+// summary lists the activities, like "3 commits; Reviewed #12 Fix thing".
+func (c *gitHubClient) groupActivities(activities []activity, opts *client.FetchOpts) worklog.Entries {
+	type group struct {
+		repository string
+		start      time.Time
+		activities []activity
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].start.Before(activities[j].start)
+	})
+
+	var groups []*group
+	groupsByKey := map[string]*group{}
+
+	for _, a := range activities {
+		key := a.repository + "\x00" + utils.DateFormatISO8601.Format(a.start.In(opts.Start.Location()))
+
+		g, ok := groupsByKey[key]
+		if !ok {
+			g = &group{repository: a.repository, start: a.start}
+			groupsByKey[key] = g
+			groups = append(groups, g)
+		}
+
+		g.activities = append(g.activities, a)
+	}
+
+	var entries worklog.Entries
+	for _, g := range groups {
+		var summaries []string
+		var timeSpent time.Duration
+		counts := map[activityKind]int{}
+		isSummarized := map[string]bool{}
+
+		entry := worklog.Entry{
+			Project: worklog.IDNameField{
+				ID:   g.repository,
+				Name: g.repository,
+			},
+			Start: g.start.In(opts.Start.Location()),
+		}
+
+		for _, a := range g.activities {
+			counts[a.kind] += a.count
+
+			var summary string
+			switch a.kind {
+			case activityReview:
+				timeSpent += c.reviewDuration
+				summary = fmt.Sprintf("Reviewed #%d %s", a.issue.Number, a.issue.Title)
+			case activityComment:
+				timeSpent += c.commentDuration
+				summary = fmt.Sprintf("Commented on #%d %s", a.issue.Number, a.issue.Title)
+			}
+
+			// Multiple comments on the same issue are listed only once
+			if summary != "" && !isSummarized[summary] {
+				isSummarized[summary] = true
+				summaries = append(summaries, summary)
+			}
+
+			entry.AddLinks(a.url)
+			entry.AddSourceURL(a.url)
+		}
+
+		if commits := counts[activityCommit]; commits > 0 {
+			timeSpent += c.commitDuration
+
+			summary := "1 commit"
+			if commits > 1 {
+				summary = strconv.Itoa(commits) + " commits"
+			}
+
+			summaries = append([]string{summary}, summaries...)
+		}
+
+		entry.Summary = strings.Join(summaries, "; ")
+		entry.BillableDuration = timeSpent
+
+		entry.SetAttribute(AttributeCommits, strconv.Itoa(counts[activityCommit]))
+		entry.SetAttribute(AttributeReviews, strconv.Itoa(counts[activityReview]))
+		entry.SetAttribute(AttributeComments, strconv.Itoa(counts[activityComment]))
+
+		if len(g.activities) > 1 {
+			entry.AddTransformation("grouped %d activities", len(g.activities))
+		}
+
+		entry.TaskHints = &worklog.TaskHints{Texts: []string{entry.Summary}}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// MaxRangeDays returns the number of days the contributions are queried for at
+// once, so longer periods are fetched in chunks.
+func (c *gitHubClient) MaxRangeDays() int {
+	return MaxRangeDays
+}
+
+func (c *gitHubClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	login, err := c.fetchLogin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var activities []activity
+	for _, fetch := range []func(context.Context, string, *client.FetchOpts) ([]activity, error){
+		c.fetchCommits,
+		c.fetchReviews,
+		c.fetchComments,
+	} {
+		fetched, err := fetch(ctx, login, opts)
+		if err != nil {
+			// The paginated fetches wrap the errors already
+			if errors.Is(err, client.ErrFetchEntries) {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		activities = append(activities, fetched...)
+	}
+
+	return opts.FilterEntries(c.groupActivities(activities, opts)), nil
+}
+
+// NewFetcher returns a new GitHub client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Token == "" {
+		return nil, errors.New("no GitHub token provided")
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Bearer", opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	commitDuration := opts.CommitDuration
+	if commitDuration <= 0 {
+		commitDuration = DefaultCommitDuration
+	}
+
+	reviewDuration := opts.ReviewDuration
+	if reviewDuration <= 0 {
+		reviewDuration = DefaultReviewDuration
+	}
+
+	commentDuration := opts.CommentDuration
+	if commentDuration <= 0 {
+		commentDuration = DefaultCommentDuration
+	}
+
+	return &gitHubClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		GraphQLClient: &client.GraphQLClient{
+			HTTPClient: &client.HTTPClient{
+				BaseURL: baseURL,
+			},
+			Auth:    authenticator,
+			Timeout: opts.Timeout,
+		},
+		user:            opts.User,
+		commitDuration:  commitDuration,
+		reviewDuration:  reviewDuration,
+		commentDuration: commentDuration,
+	}, nil
+}
@@ -0,0 +1,294 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/github"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+func newMockServer(t *testing.T, responses map[string]func(variables map[string]interface{}) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/graphql", r.URL.Path)
+		require.Equal(t, "Bearer ghp_s3cr3t", r.Header.Get("Authorization"))
+
+		var req client.GraphQLRequest
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+
+		respond, ok := responses[req.OperationName]
+		require.True(t, ok, "unexpected operation %s", req.OperationName)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(respond(req.Variables)))
+		require.Nil(t, err)
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string, user string) client.Fetcher {
+	fetcher, err := github.NewFetcher(&github.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: serverURL,
+		Token:   "ghp_s3cr3t",
+		User:    user,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.Nil(t, err)
+	return string(data)
+}
+
+func newResponses(t *testing.T) map[string]func(map[string]interface{}) string {
+	return map[string]func(map[string]interface{}) string{
+		"Viewer": func(_ map[string]interface{}) string {
+			return `{"data":{"viewer":{"login":"octocat"}}}`
+		},
+		"CommitContributions": func(variables map[string]interface{}) string {
+			require.Equal(t, "octocat", variables["login"])
+
+			return marshal(t, map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": map[string]interface{}{
+						"contributionsCollection": map[string]interface{}{
+							"commitContributionsByRepository": []map[string]interface{}{
+								{
+									"repository": github.Repository{NameWithOwner: "octo/minutes"},
+									"contributions": map[string]interface{}{
+										"nodes": []github.CommitContribution{
+											{OccurredAt: utils.Timestamp{Time: at(1, 0, 0)}, CommitCount: 3, URL: "https://github.com/octo/minutes/commits?day=1"},
+											{OccurredAt: utils.Timestamp{Time: at(2, 0, 0)}, CommitCount: 1, URL: "https://github.com/octo/minutes/commits?day=2"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+		},
+		"ReviewContributions": func(variables map[string]interface{}) string {
+			review := github.ReviewContribution{
+				OccurredAt:  utils.Timestamp{Time: at(1, 10, 0)},
+				PullRequest: github.Issue{Number: 12, Title: "CPT-123 Fix thing", Repository: github.Repository{NameWithOwner: "octo/minutes"}},
+			}
+			review.PullRequestReview.URL = "https://github.com/octo/minutes/pull/12#pullrequestreview-1"
+
+			pageInfo := client.PageInfo{HasNextPage: true, EndCursor: "c1"}
+			if variables["after"] != nil {
+				require.Equal(t, "c1", variables["after"])
+
+				review = github.ReviewContribution{
+					OccurredAt:  utils.Timestamp{Time: at(1, 14, 0)},
+					PullRequest: github.Issue{Number: 4, Title: "Add docs", Repository: github.Repository{NameWithOwner: "octo/docs"}},
+				}
+				review.PullRequestReview.URL = "https://github.com/octo/docs/pull/4#pullrequestreview-2"
+				pageInfo = client.PageInfo{}
+			}
+
+			return marshal(t, map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": map[string]interface{}{
+						"contributionsCollection": map[string]interface{}{
+							"pullRequestReviewContributions": map[string]interface{}{
+								"pageInfo": pageInfo,
+								"nodes":    []github.ReviewContribution{review},
+							},
+						},
+					},
+				},
+			})
+		},
+		"IssueComments": func(variables map[string]interface{}) string {
+			// The comments older than the period must not be paginated
+			require.Nil(t, variables["after"])
+
+			return marshal(t, map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": map[string]interface{}{
+						"issueComments": map[string]interface{}{
+							"pageInfo": client.PageInfo{HasNextPage: true, EndCursor: "c1"},
+							"nodes": []github.IssueComment{
+								{
+									CreatedAt:  utils.Timestamp{Time: at(3, 9, 0)},
+									UpdatedAt:  utils.Timestamp{Time: at(3, 9, 0)},
+									URL:        "https://github.com/octo/minutes/issues/7#issuecomment-3",
+									Repository: github.Repository{NameWithOwner: "octo/minutes"},
+									Issue:      github.Issue{Number: 7, Title: "Crash"},
+								},
+								{
+									CreatedAt:  utils.Timestamp{Time: at(1, 11, 0)},
+									UpdatedAt:  utils.Timestamp{Time: at(1, 11, 0)},
+									URL:        "https://github.com/octo/minutes/issues/7#issuecomment-2",
+									Repository: github.Repository{NameWithOwner: "octo/minutes"},
+									Issue:      github.Issue{Number: 7, Title: "Crash"},
+								},
+								{
+									CreatedAt:  utils.Timestamp{Time: at(1, 9, 30)},
+									UpdatedAt:  utils.Timestamp{Time: at(1, 9, 30)},
+									URL:        "https://github.com/octo/minutes/issues/7#issuecomment-1",
+									Repository: github.Repository{NameWithOwner: "octo/minutes"},
+									Issue:      github.Issue{Number: 7, Title: "Crash"},
+								},
+								{
+									CreatedAt:  utils.Timestamp{Time: at(1, 8, 0).AddDate(0, 0, -7)},
+									UpdatedAt:  utils.Timestamp{Time: at(1, 8, 0).AddDate(0, 0, -7)},
+									URL:        "https://github.com/octo/minutes/issues/1#issuecomment-0",
+									Repository: github.Repository{NameWithOwner: "octo/minutes"},
+									Issue:      github.Issue{Number: 1, Title: "Old"},
+								},
+							},
+						},
+					},
+				},
+			})
+		},
+	}
+}
+
+func TestGitHubClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, newResponses(t))
+	defer mockServer.Close()
+
+	fetcher := newTestFetcher(t, mockServer.URL, "")
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	minutes := worklog.IDNameField{ID: "octo/minutes", Name: "octo/minutes"}
+	docs := worklog.IDNameField{ID: "octo/docs", Name: "octo/docs"}
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          minutes,
+			Summary:          "3 commits; Commented on #7 Crash; Reviewed #12 CPT-123 Fix thing",
+			Start:            at(1, 0, 0),
+			BillableDuration: time.Minute * 80,
+			Links: []string{
+				"https://github.com/octo/minutes/commits?day=1",
+				"https://github.com/octo/minutes/issues/7#issuecomment-1",
+				"https://github.com/octo/minutes/pull/12#pullrequestreview-1",
+				"https://github.com/octo/minutes/issues/7#issuecomment-2",
+			},
+			Attributes: map[string]string{
+				github.AttributeCommits:  "3",
+				github.AttributeReviews:  "1",
+				github.AttributeComments: "2",
+			},
+			TaskHints: &worklog.TaskHints{Texts: []string{"3 commits; Commented on #7 Crash; Reviewed #12 CPT-123 Fix thing"}},
+			Provenance: worklog.Provenance{
+				SourceURLs: []string{
+					"https://github.com/octo/minutes/commits?day=1",
+					"https://github.com/octo/minutes/issues/7#issuecomment-1",
+					"https://github.com/octo/minutes/pull/12#pullrequestreview-1",
+					"https://github.com/octo/minutes/issues/7#issuecomment-2",
+				},
+				Transformations: []string{"grouped 4 activities"},
+			},
+		},
+		{
+			Project:          docs,
+			Summary:          "Reviewed #4 Add docs",
+			Start:            at(1, 14, 0),
+			BillableDuration: time.Minute * 30,
+			Links:            []string{"https://github.com/octo/docs/pull/4#pullrequestreview-2"},
+			Attributes: map[string]string{
+				github.AttributeCommits:  "0",
+				github.AttributeReviews:  "1",
+				github.AttributeComments: "0",
+			},
+			TaskHints: &worklog.TaskHints{Texts: []string{"Reviewed #4 Add docs"}},
+			Provenance: worklog.Provenance{
+				SourceURLs: []string{"https://github.com/octo/docs/pull/4#pullrequestreview-2"},
+			},
+		},
+		{
+			Project:          minutes,
+			Summary:          "1 commit",
+			Start:            at(2, 0, 0),
+			BillableDuration: time.Minute * 30,
+			Links:            []string{"https://github.com/octo/minutes/commits?day=2"},
+			Attributes: map[string]string{
+				github.AttributeCommits:  "1",
+				github.AttributeReviews:  "0",
+				github.AttributeComments: "0",
+			},
+			TaskHints: &worklog.TaskHints{Texts: []string{"1 commit"}},
+			Provenance: worklog.Provenance{
+				SourceURLs: []string{"https://github.com/octo/minutes/commits?day=2"},
+			},
+		},
+	}
+
+	require.Nil(t, err)
+	require.ElementsMatch(t, expectedEntries, entries)
+}
+
+func TestGitHubClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, newResponses(t))
+	defer mockServer.Close()
+
+	fetcher := newTestFetcher(t, mockServer.URL, "octocat")
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 3)
+
+	var tasks []string
+	for _, entry := range entries {
+		tasks = append(tasks, entry.Task.Name)
+	}
+
+	require.ElementsMatch(t, []string{"CPT-123", "", ""}, tasks)
+}
+
+func TestGitHubClient_FetchEntries_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data":null,"errors":[{"message":"Bad credentials"}]}`))
+		require.Nil(t, err)
+	}))
+	defer mockServer.Close()
+
+	fetcher := newTestFetcher(t, mockServer.URL, "")
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, client.ErrGraphQL)
+}
+
+func TestNewFetcher_NoToken(t *testing.T) {
+	_, err := github.NewFetcher(&github.ClientOpts{BaseURL: github.DefaultURL})
+	require.EqualError(t, err, "no GitHub token provided")
+}
@@ -0,0 +1,245 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of GitLab.
+	DefaultURL string = "https://gitlab.com"
+	// PathGraphQL is the endpoint of the GraphQL API.
+	PathGraphQL string = "/api/graphql"
+	// MaxPageSize is the maximum number of nodes returned on a page.
+	MaxPageSize int = 100
+)
+
+var (
+	currentUserQuery = client.GraphQLQuery{
+		Name:      "CurrentUser",
+		Selection: "currentUser { username }",
+	}
+
+	timelogsQuery = client.GraphQLQuery{
+		Name: "Timelogs",
+		Variables: []client.GraphQLVariable{
+			{Name: "username", Type: "String!"},
+			{Name: "startTime", Type: "Time!"},
+			{Name: "endTime", Type: "Time!"},
+			{Name: "first", Type: "Int!"},
+			{Name: "after", Type: "String"},
+		},
+		Selection: `timelogs(username: $username, startTime: $startTime, endTime: $endTime, first: $first, after: $after) {
+			pageInfo { hasNextPage endCursor }
+			nodes {
+				id
+				spentAt
+				timeSpent
+				summary
+				project { fullPath webUrl }
+				issue { title webUrl reference(full: true) }
+				mergeRequest { title webUrl reference(full: true) }
+			}
+		}`,
+	}
+)
+
+// Project represents the project a timelog is recorded in.
+type Project struct {
+	FullPath string `json:"fullPath"`
+	WebURL   string `json:"webUrl"`
+}
+
+// Issuable represents the issue or merge request a timelog is recorded on.
+type Issuable struct {
+	Title string `json:"title"`
+	// WebURL is the link to the issue or merge request.
+	WebURL string `json:"webUrl"`
+	// Reference is the full reference of the issue or merge request, like
+	// "group/project#12" or "group/project!3".
+	Reference string `json:"reference"`
+}
+
+// Timelog represents the time spent recorded by a `/spend` quick action or
+// on the time tracking dialog. The time spent is in seconds.
+type Timelog struct {
+	ID           string          `json:"id"`
+	SpentAt      utils.Timestamp `json:"spentAt"`
+	TimeSpent    int             `json:"timeSpent"`
+	Summary      string          `json:"summary"`
+	Project      Project         `json:"project"`
+	Issue        *Issuable       `json:"issue"`
+	MergeRequest *Issuable       `json:"mergeRequest"`
+}
+
+// issuable returns the issue or merge request of the timelog, or nil if the
+// timelog is recorded on neither of them.
+func (t *Timelog) issuable() *Issuable {
+	if t.Issue != nil {
+		return t.Issue
+	}
+
+	return t.MergeRequest
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Token is the personal access token used to authenticate.
+	Token string
+	// User is the username of the user the timelogs are fetched for. If not
+	// set, the owner of the token is used.
+	User string
+}
+
+type gitLabClient struct {
+	*client.BaseClientOpts
+	*client.GraphQLClient
+	user string
+}
+
+func (c *gitLabClient) fetchUsername(ctx context.Context) (string, error) {
+	if c.user != "" {
+		return c.user, nil
+	}
+
+	var data struct {
+		CurrentUser struct {
+			Username string `json:"username"`
+		} `json:"currentUser"`
+	}
+
+	err := c.Query(ctx, &client.GraphQLRequest{
+		Query:         currentUserQuery.String(),
+		OperationName: currentUserQuery.Name,
+	}, &data)
+	if err != nil {
+		return "", err
+	}
+
+	if data.CurrentUser.Username == "" {
+		return "", errors.New("no current user returned for the GitLab token")
+	}
+
+	return data.CurrentUser.Username, nil
+}
+
+func (c *gitLabClient) parseTimelogs(data json.RawMessage, opts *client.FetchOpts) (worklog.Entries, *client.PageInfo, error) {
+	var page struct {
+		Timelogs struct {
+			PageInfo client.PageInfo `json:"pageInfo"`
+			Nodes    []Timelog       `json:"nodes"`
+		} `json:"timelogs"`
+	}
+
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, nil, err
+	}
+
+	var entries worklog.Entries
+	for _, timelog := range page.Timelogs.Nodes {
+		entry := worklog.Entry{
+			Project: worklog.IDNameField{
+				ID:   timelog.Project.FullPath,
+				Name: timelog.Project.FullPath,
+			},
+			Summary:          timelog.Summary,
+			Start:            timelog.SpentAt.In(opts.Start.Location()),
+			BillableDuration: time.Duration(timelog.TimeSpent) * time.Second,
+			Provenance:       worklog.Provenance{SourceIDs: []string{timelog.ID}},
+		}
+
+		title := ""
+		if issuable := timelog.issuable(); issuable != nil {
+			title = issuable.Title
+
+			entry.Task = worklog.IDNameField{
+				ID:   issuable.Reference,
+				Name: issuable.Reference,
+			}
+
+			entry.AddLinks(issuable.WebURL)
+			entry.AddSourceURL(issuable.WebURL)
+		} else {
+			entry.AddSourceURL(timelog.Project.WebURL)
+		}
+
+		// The summary of the timelogs is optional, hence the title of the
+		// issue or merge request is used instead
+		if entry.Summary == "" {
+			entry.Summary = title
+		}
+
+		entry.TaskHints = &worklog.TaskHints{Texts: []string{title, timelog.Summary}}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, &page.Timelogs.PageInfo, nil
+}
+
+func (c *gitLabClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	username, err := c.fetchUsername(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	entries, err := c.PaginatedFetch(ctx, &client.GraphQLPaginatedFetchOpts{
+		BaseFetchOpts: opts,
+		Request: &client.GraphQLRequest{
+			Query:         timelogsQuery.String(),
+			OperationName: timelogsQuery.Name,
+			Variables: map[string]interface{}{
+				"username":  username,
+				"startTime": opts.Start,
+				"endTime":   opts.End,
+			},
+		},
+		PageSize:  MaxPageSize,
+		ParseFunc: c.parseTimelogs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new GitLab client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Token == "" {
+		return nil, errors.New("no GitLab token provided")
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Bearer", opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitLabClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		GraphQLClient: &client.GraphQLClient{
+			HTTPClient: &client.HTTPClient{
+				BaseURL: baseURL,
+			},
+			Path:    PathGraphQL,
+			Auth:    authenticator,
+			Timeout: opts.Timeout,
+		},
+		user: opts.User,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/gitlab"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+var (
+	issue = &gitlab.Issuable{
+		Title:     "CPT-123 Fix thing",
+		WebURL:    "https://gitlab.com/octo/minutes/-/issues/12",
+		Reference: "octo/minutes#12",
+	}
+	mergeRequest = &gitlab.Issuable{
+		Title:     "Add docs",
+		WebURL:    "https://gitlab.com/octo/minutes/-/merge_requests/3",
+		Reference: "octo/minutes!3",
+	}
+	project = gitlab.Project{
+		FullPath: "octo/minutes",
+		WebURL:   "https://gitlab.com/octo/minutes",
+	}
+)
+
+func newMockServer(t *testing.T, pages [][]gitlab.Timelog) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, gitlab.PathGraphQL, r.URL.Path)
+		require.Equal(t, "Bearer glpat-s3cr3t", r.Header.Get("Authorization"))
+
+		var req client.GraphQLRequest
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.OperationName == "CurrentUser" {
+			_, err := w.Write([]byte(`{"data":{"currentUser":{"username":"octocat"}}}`))
+			require.Nil(t, err)
+			return
+		}
+
+		require.Equal(t, "Timelogs", req.OperationName)
+		require.Equal(t, "octocat", req.Variables["username"])
+		require.Equal(t, float64(gitlab.MaxPageSize), req.Variables["first"])
+
+		page := 0
+		if req.Variables["after"] != nil {
+			require.Equal(t, "c1", req.Variables["after"])
+			page = 1
+		}
+
+		pageInfo := client.PageInfo{HasNextPage: page < len(pages)-1, EndCursor: "c1"}
+
+		require.Nil(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"timelogs": map[string]interface{}{
+					"pageInfo": pageInfo,
+					"nodes":    pages[page],
+				},
+			},
+		}))
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string, user string) client.Fetcher {
+	fetcher, err := gitlab.NewFetcher(&gitlab.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: serverURL,
+		Token:   "glpat-s3cr3t",
+		User:    user,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestGitLabClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, [][]gitlab.Timelog{
+		{
+			{ID: "gid://gitlab/Timelog/1", SpentAt: utils.Timestamp{Time: at(1, 9, 0)}, TimeSpent: 5400, Summary: "Investigating", Project: project, Issue: issue},
+			{ID: "gid://gitlab/Timelog/2", SpentAt: utils.Timestamp{Time: at(1, 14, 0)}, TimeSpent: 1800, Project: project, MergeRequest: mergeRequest},
+		},
+		{
+			{ID: "gid://gitlab/Timelog/3", SpentAt: utils.Timestamp{Time: at(2, 10, 0)}, TimeSpent: 600, Summary: "Planning", Project: project},
+			{ID: "gid://gitlab/Timelog/4", SpentAt: utils.Timestamp{Time: at(3, 10, 0)}, TimeSpent: 600, Summary: "Out of period", Project: project},
+		},
+	})
+	defer mockServer.Close()
+
+	minutes := worklog.IDNameField{ID: "octo/minutes", Name: "octo/minutes"}
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          minutes,
+			Task:             worklog.IDNameField{ID: "octo/minutes#12", Name: "octo/minutes#12"},
+			Summary:          "Investigating",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Minute * 90,
+			Links:            []string{issue.WebURL},
+			TaskHints:        &worklog.TaskHints{Texts: []string{"CPT-123 Fix thing", "Investigating"}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"gid://gitlab/Timelog/1"},
+				SourceURLs: []string{issue.WebURL},
+			},
+		},
+		{
+			Project:          minutes,
+			Task:             worklog.IDNameField{ID: "octo/minutes!3", Name: "octo/minutes!3"},
+			Summary:          "Add docs",
+			Start:            at(1, 14, 0),
+			BillableDuration: time.Minute * 30,
+			Links:            []string{mergeRequest.WebURL},
+			TaskHints:        &worklog.TaskHints{Texts: []string{"Add docs", ""}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"gid://gitlab/Timelog/2"},
+				SourceURLs: []string{mergeRequest.WebURL},
+			},
+		},
+		{
+			Project:          minutes,
+			Summary:          "Planning",
+			Start:            at(2, 10, 0),
+			BillableDuration: time.Minute * 10,
+			Links:            []string{project.WebURL},
+			TaskHints:        &worklog.TaskHints{Texts: []string{"", "Planning"}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"gid://gitlab/Timelog/3"},
+				SourceURLs: []string{project.WebURL},
+			},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL, "").FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, expectedEntries, entries)
+}
+
+func TestGitLabClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, [][]gitlab.Timelog{
+		{
+			{ID: "gid://gitlab/Timelog/1", SpentAt: utils.Timestamp{Time: at(1, 9, 0)}, TimeSpent: 5400, Summary: "Investigating", Project: project, Issue: issue},
+			{ID: "gid://gitlab/Timelog/2", SpentAt: utils.Timestamp{Time: at(1, 14, 0)}, TimeSpent: 1800, Project: project, MergeRequest: mergeRequest},
+		},
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, "octocat").FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 2)
+	require.Equal(t, worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}, entries[0].Task)
+	require.Equal(t, "Investigating", entries[0].Summary)
+	require.Equal(t, worklog.IDNameField{ID: "octo/minutes!3", Name: "octo/minutes!3"}, entries[1].Task)
+}
+
+func TestGitLabClient_FetchEntries_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"data":null,"errors":[{"message":"Invalid token"}]}`))
+		require.Nil(t, err)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, "octocat").FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, client.ErrGraphQL)
+}
+
+func TestNewFetcher_NoToken(t *testing.T) {
+	_, err := gitlab.NewFetcher(&gitlab.ClientOpts{BaseURL: gitlab.DefaultURL})
+	require.EqualError(t, err, "no GitLab token provided")
+}
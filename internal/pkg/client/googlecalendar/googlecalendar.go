@@ -0,0 +1,303 @@
+package googlecalendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the Google Calendar API.
+	DefaultURL string = "https://www.googleapis.com"
+	// DefaultTokenURL is the endpoint issuing the Google access tokens.
+	DefaultTokenURL string = "https://oauth2.googleapis.com/token"
+	// DefaultDeviceCodeURL is the device authorization endpoint of Google.
+	DefaultDeviceCodeURL string = "https://oauth2.googleapis.com/device/code"
+	// DefaultCalendarID is the ID of the user's primary calendar.
+	DefaultCalendarID string = "primary"
+	// Scope is the scope requested to read the events.
+	Scope string = "https://www.googleapis.com/auth/calendar.readonly"
+
+	// PathEvents is the endpoint used to list the events of a calendar.
+	PathEvents string = "/calendar/v3/calendars/%s/events"
+	// MaxPageSize is the maximum number of events returned on a page.
+	MaxPageSize int = 2500
+
+	// AttributeAttendees is the name of the attribute listing the e-mail
+	// addresses of the other attendees of the event.
+	AttributeAttendees string = "googlecalendar.attendees"
+	// AttributeColor is the name of the attribute containing the color of the
+	// event, like "Tomato".
+	AttributeColor string = "googlecalendar.color"
+
+	statusCancelled        string = "cancelled"
+	responseStatusDeclined string = "declined"
+)
+
+// colors maps the event color IDs to the names of the colors, as shown by
+// Google Calendar.
+var colors = map[string]string{
+	"1":  "Lavender",
+	"2":  "Sage",
+	"3":  "Grape",
+	"4":  "Flamingo",
+	"5":  "Banana",
+	"6":  "Tangerine",
+	"7":  "Peacock",
+	"8":  "Graphite",
+	"9":  "Blueberry",
+	"10": "Basil",
+	"11": "Tomato",
+}
+
+// EventTime represents the start or end of an event. All-day events set the
+// Date instead of the DateTime.
+type EventTime struct {
+	DateTime utils.Timestamp `json:"dateTime"`
+	Date     string          `json:"date"`
+}
+
+// Attendee represents an attendee of the event.
+type Attendee struct {
+	Email          string `json:"email"`
+	DisplayName    string `json:"displayName"`
+	ResponseStatus string `json:"responseStatus"`
+	// Self is set for the attendee of the calendar's owner.
+	Self bool `json:"self"`
+}
+
+// Event represents an event of the calendar.
+type Event struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	HTMLLink    string     `json:"htmlLink"`
+	Summary     string     `json:"summary"`
+	Description string     `json:"description"`
+	ColorID     string     `json:"colorId"`
+	Start       EventTime  `json:"start"`
+	End         EventTime  `json:"end"`
+	Attendees   []Attendee `json:"attendees"`
+}
+
+// FetchResponse represents a page of the listed events.
+type FetchResponse struct {
+	Items         []Event `json:"items"`
+	NextPageToken string  `json:"nextPageToken"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// TokenURL is the endpoint used to refresh the access token. If not set,
+	// DefaultTokenURL is used.
+	TokenURL string
+	// CalendarIDs lists the calendars the events are read from. If empty, the
+	// primary calendar of the user is read.
+	CalendarIDs  []string
+	ClientID     string
+	ClientSecret string
+	// RefreshToken is the OAuth refresh token of the user, obtained by the
+	// device authorization flow.
+	RefreshToken string
+}
+
+type googleCalendarClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	calendarIDs []string
+	auth        *client.RefreshingTokenAuth
+}
+
+func (c *googleCalendarClient) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	return c.auth.WarmUp(ctx, d)
+}
+
+func (c *googleCalendarClient) CredentialsExpireAt() time.Time {
+	return c.auth.ExpiresAt()
+}
+
+// isDeclined returns true if the owner of the calendar declined the event.
+func isDeclined(event *Event) bool {
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			return attendee.ResponseStatus == responseStatusDeclined
+		}
+	}
+
+	return false
+}
+
+// otherAttendees returns the sorted e-mail addresses of the attendees of the
+// event, except the owner of the calendar.
+func otherAttendees(event *Event) []string {
+	var attendees []string
+
+	for _, attendee := range event.Attendees {
+		if !attendee.Self && attendee.Email != "" {
+			attendees = append(attendees, attendee.Email)
+		}
+	}
+
+	sort.Strings(attendees)
+	return attendees
+}
+
+// parseEvents converts the events to entries. The cancelled, declined and
+// all-day events are skipped, since they do not stand for time spent.
+func parseEvents(events []Event) worklog.Entries {
+	var entries worklog.Entries
+
+	for i := range events {
+		event := &events[i]
+
+		if event.Status == statusCancelled || isDeclined(event) || event.Start.DateTime.IsZero() || event.End.DateTime.IsZero() {
+			continue
+		}
+
+		entry := worklog.Entry{
+			Summary:          event.Summary,
+			Notes:            event.Description,
+			Start:            event.Start.DateTime.Local(),
+			BillableDuration: event.End.DateTime.Sub(event.Start.DateTime.Time),
+			Provenance:       worklog.Provenance{SourceIDs: []string{event.ID}},
+		}
+
+		entry.AddLinks(utils.ExtractURLs(event.Description)...)
+		entry.AddSourceURL(event.HTMLLink)
+
+		// The color and the attendees are the tags of the event
+		var tags []worklog.IDNameField
+
+		color := colors[event.ColorID]
+		if color != "" {
+			entry.SetAttribute(AttributeColor, color)
+			tags = append(tags, worklog.IDNameField{ID: color, Name: color})
+		}
+
+		attendees := otherAttendees(event)
+		if len(attendees) != 0 {
+			entry.SetAttribute(AttributeAttendees, strings.Join(attendees, " "))
+		}
+
+		for _, attendee := range attendees {
+			tags = append(tags, worklog.IDNameField{ID: attendee, Name: attendee})
+		}
+
+		entry.TaskHints = &worklog.TaskHints{
+			Texts: []string{event.Summary},
+			Tags:  tags,
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// fetchEvents fetches every page of the calendar's events overlapping the
+// fetch window. The recurring events are expanded to their instances.
+func (c *googleCalendarClient) fetchEvents(ctx context.Context, calendarID string, opts *client.FetchOpts) ([]Event, error) {
+	var events []Event
+	pageToken := ""
+
+	for {
+		params := map[string]string{
+			"timeMin":      opts.Start.Format(time.RFC3339),
+			"timeMax":      opts.End.Format(time.RFC3339),
+			"singleEvents": "true",
+			"orderBy":      "startTime",
+			"maxResults":   fmt.Sprint(MaxPageSize),
+		}
+
+		if pageToken != "" {
+			params["pageToken"] = pageToken
+		}
+
+		fetchURL, err := c.URL(fmt.Sprintf(PathEvents, url.PathEscape(calendarID)), params)
+		if err != nil {
+			return nil, err
+		}
+
+		var fetchResponse FetchResponse
+		err = c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodGet,
+			Url:     fetchURL,
+			Auth:    c.auth,
+			Timeout: c.Timeout,
+		}, &fetchResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, fetchResponse.Items...)
+
+		if fetchResponse.NextPageToken == "" {
+			return events, nil
+		}
+
+		pageToken = fetchResponse.NextPageToken
+	}
+}
+
+func (c *googleCalendarClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, calendarID := range c.calendarIDs {
+		events, err := c.fetchEvents(ctx, calendarID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		entries = append(entries, parseEvents(events)...)
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new Google Calendar client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.ClientID == "" || opts.RefreshToken == "" {
+		return nil, errors.New("no Google Calendar OAuth client ID or refresh token provided")
+	}
+
+	tokenURL := opts.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+
+	calendarIDs := opts.CalendarIDs
+	if len(calendarIDs) == 0 {
+		calendarIDs = []string{DefaultCalendarID}
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &client.HTTPClient{BaseURL: baseURL}
+
+	refresh := client.NewOAuthRefreshFunc(httpClient, &client.OAuthOpts{
+		TokenURL:     tokenURL,
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		Timeout:      opts.Timeout,
+	}, opts.RefreshToken)
+
+	return &googleCalendarClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient:     httpClient,
+		calendarIDs:    calendarIDs,
+		auth:           client.NewRefreshingTokenAuth("Authorization", "Bearer", refresh),
+	}, nil
+}
@@ -0,0 +1,225 @@
+package googlecalendar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/googlecalendar"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2021, 10, 2, hour, minute, 0, 0, time.Local)
+}
+
+func newMockServer(t *testing.T, pages map[string][]googlecalendar.FetchResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/token" {
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, "refresh-token", r.PostForm.Get("refresh_token"))
+			_, _ = w.Write([]byte(`{"access_token":"access-token","expires_in":3600}`))
+			return
+		}
+
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+		require.Equal(t, "true", r.URL.Query().Get("singleEvents"))
+		require.Equal(t, at(0, 0).Format(time.RFC3339), r.URL.Query().Get("timeMin"))
+
+		calendarPages, ok := pages[r.URL.EscapedPath()]
+		require.True(t, ok, "unexpected path %s", r.URL.EscapedPath())
+
+		page := 0
+		if pageToken := r.URL.Query().Get("pageToken"); pageToken != "" {
+			page = 1
+		}
+
+		require.Nil(t, json.NewEncoder(w).Encode(calendarPages[page]))
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string, calendarIDs []string) client.Fetcher {
+	fetcher, err := googlecalendar.NewFetcher(&googlecalendar.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      serverURL,
+		TokenURL:     serverURL + "/token",
+		CalendarIDs:  calendarIDs,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RefreshToken: "refresh-token",
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestGoogleCalendarClient_FetchEntries(t *testing.T) {
+	self := googlecalendar.Attendee{Email: "me@example.com", Self: true, ResponseStatus: "accepted"}
+
+	mockServer := newMockServer(t, map[string][]googlecalendar.FetchResponse{
+		"/calendar/v3/calendars/primary/events": {
+			{
+				Items: []googlecalendar.Event{
+					{
+						ID:          "event-1",
+						HTMLLink:    "https://calendar.google.com/event?eid=1",
+						Summary:     "CPT-123 planning",
+						Description: "Agenda: https://example.com/agenda",
+						ColorID:     "11",
+						Start:       googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(9, 0)}},
+						End:         googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(10, 0)}},
+						Attendees: []googlecalendar.Attendee{
+							self,
+							{Email: "bob@example.com"},
+							{Email: "alice@example.com"},
+						},
+					},
+					{
+						ID:      "all-day",
+						Summary: "Conference",
+						Start:   googlecalendar.EventTime{Date: "2021-10-02"},
+						End:     googlecalendar.EventTime{Date: "2021-10-03"},
+					},
+				},
+				NextPageToken: "next",
+			},
+			{
+				Items: []googlecalendar.Event{
+					{
+						ID:      "declined",
+						Summary: "Lunch and learn",
+						Start:   googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(12, 0)}},
+						End:     googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(13, 0)}},
+						Attendees: []googlecalendar.Attendee{
+							{Email: "me@example.com", Self: true, ResponseStatus: "declined"},
+						},
+					},
+					{
+						ID:      "cancelled",
+						Status:  "cancelled",
+						Summary: "Retro",
+						Start:   googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(14, 0)}},
+						End:     googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(15, 0)}},
+					},
+				},
+			},
+		},
+		"/calendar/v3/calendars/team@example.com/events": {
+			{
+				Items: []googlecalendar.Event{
+					{
+						ID:      "event-2",
+						Summary: "Pairing",
+						Start:   googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(16, 0)}},
+						End:     googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(16, 30)}},
+					},
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	expectedEntries := worklog.Entries{
+		{
+			Task:             worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"},
+			Summary:          "CPT-123 planning",
+			Notes:            "Agenda: https://example.com/agenda",
+			Start:            at(9, 0),
+			BillableDuration: time.Hour,
+			Links:            []string{"https://example.com/agenda", "https://calendar.google.com/event?eid=1"},
+			Attributes: map[string]string{
+				googlecalendar.AttributeColor:     "Tomato",
+				googlecalendar.AttributeAttendees: "alice@example.com bob@example.com",
+			},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"event-1"},
+				SourceURLs: []string{"https://calendar.google.com/event?eid=1"},
+			},
+		},
+		{
+			Summary:          "Pairing",
+			Start:            at(16, 0),
+			BillableDuration: time.Minute * 30,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"event-2"}},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL, []string{"primary", "team@example.com"}).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(23, 59),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^[A-Z]{2,7}-\d{1,6}`), worklog.TaskExtractionTags)
+	require.Equal(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestGoogleCalendarClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, map[string][]googlecalendar.FetchResponse{
+		"/calendar/v3/calendars/primary/events": {
+			{
+				Items: []googlecalendar.Event{
+					{
+						ID:      "event-1",
+						Summary: "Customer calls",
+						ColorID: "2",
+						Start:   googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(9, 0)}},
+						End:     googlecalendar.EventTime{DateTime: utils.Timestamp{Time: at(10, 0)}},
+						Attendees: []googlecalendar.Attendee{
+							{Email: "support@acme.example"},
+							{Email: "support@globex.example"},
+							{Email: "colleague@example.com"},
+						},
+					},
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, nil).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(23, 59),
+	})
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^support@.*`), worklog.TaskExtractionTags)
+
+	require.Len(t, entries, 2)
+	require.Equal(t, "support@acme.example", entries[0].Task.Name)
+	require.Equal(t, time.Minute*30, entries[0].BillableDuration)
+	require.Equal(t, "support@globex.example", entries[1].Task.Name)
+	require.Equal(t, "Sage", entries[1].Attributes[googlecalendar.AttributeColor])
+}
+
+func TestGoogleCalendarClient_FetchEntries_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	_, err := newTestFetcher(t, mockServer.URL, nil).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(23, 59),
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoRefreshToken(t *testing.T) {
+	_, err := googlecalendar.NewFetcher(&googlecalendar.ClientOpts{
+		BaseURL:  googlecalendar.DefaultURL,
+		ClientID: "client-id",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultGraphQLPath is the endpoint path used by GraphQL clients if no
+	// path is set.
+	DefaultGraphQLPath string = "/graphql"
+	// DefaultCursorVariable is the variable used by paginated GraphQL fetchers
+	// to pass the cursor of the next page.
+	DefaultCursorVariable string = "after"
+	// DefaultPageSizeVariable is the variable used by paginated GraphQL
+	// fetchers to set the page size.
+	DefaultPageSizeVariable string = "first"
+)
+
+var (
+	// ErrGraphQL wraps the errors returned in GraphQL responses.
+	ErrGraphQL = errors.New("graphql request failed")
+)
+
+// GraphQLVariable represents a variable definition of a GraphQL operation,
+// like `$first: Int!`.
+type GraphQLVariable struct {
+	Name string
+	Type string
+}
+
+// GraphQLQuery builds the document of a GraphQL operation from its parts, so
+// the clients don't need to concatenate the variable definitions by hand.
+type GraphQLQuery struct {
+	// Operation is the type of the operation, "query" if not set.
+	Operation string
+	// Name is the name of the operation.
+	Name      string
+	Variables []GraphQLVariable
+	// Selection is the selection set of the operation, without the outermost
+	// braces.
+	Selection string
+}
+
+// String returns the GraphQL document of the query.
+func (q *GraphQLQuery) String() string {
+	var document strings.Builder
+
+	operation := q.Operation
+	if operation == "" {
+		operation = "query"
+	}
+
+	document.WriteString(operation)
+
+	if q.Name != "" {
+		document.WriteString(" " + q.Name)
+	}
+
+	if len(q.Variables) > 0 {
+		definitions := make([]string, 0, len(q.Variables))
+		for _, variable := range q.Variables {
+			definitions = append(definitions, fmt.Sprintf("$%s: %s", variable.Name, variable.Type))
+		}
+
+		document.WriteString("(" + strings.Join(definitions, ", ") + ")")
+	}
+
+	document.WriteString(" { " + strings.TrimSpace(q.Selection) + " }")
+
+	return document.String()
+}
+
+// GraphQLRequest represents the payload of a GraphQL request.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLError represents an error returned in the "errors" field of a
+// GraphQL response.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Code returns the error code set in the extensions, like "UNAUTHENTICATED".
+// If no code is set, an empty string returns.
+func (e *GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// GraphQLErrors represents the list of errors returned in a GraphQL response.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, graphQLError := range e {
+		messages = append(messages, graphQLError.Message)
+	}
+
+	return fmt.Sprintf("%v: %s", ErrGraphQL, strings.Join(messages, "; "))
+}
+
+func (e GraphQLErrors) Unwrap() error {
+	return ErrGraphQL
+}
+
+// typed returns the typed error of the GraphQL errors using the error code
+// of the first error. Since GraphQL servers respond with 200 for most errors,
+// the error code is the only way to classify them.
+func (e GraphQLErrors) typed() error {
+	switch strings.ToUpper(e[0].Code()) {
+	case "UNAUTHENTICATED", "UNAUTHORIZED", "FORBIDDEN":
+		return &AuthError{Err: e}
+	case "NOT_FOUND":
+		return &NotFoundError{Err: e}
+	case "RATE_LIMITED", "RATELIMITED", "TOO_MANY_REQUESTS":
+		return &RateLimitError{Err: e}
+	case "BAD_USER_INPUT", "GRAPHQL_VALIDATION_FAILED", "GRAPHQL_PARSE_FAILED", "INVALID_INPUT":
+		return &ValidationError{Err: e}
+	default:
+		return e
+	}
+}
+
+// graphQLResponse represents the response of a GraphQL request.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// PageInfo represents the cursor based pagination info of a GraphQL
+// connection, as defined by the Relay specification.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// GraphQLClient implements a client that communicates with a GraphQL API.
+type GraphQLClient struct {
+	*HTTPClient
+	// Path is the path of the GraphQL endpoint, relative to the BaseURL of the
+	// HTTPClient. If not set, DefaultGraphQLPath is used.
+	Path string
+	Auth Authenticator
+	// Timeout is the timeout of a request. If not set, DefaultRequestTimeout
+	// is used.
+	Timeout time.Duration
+	Headers map[string]string
+}
+
+// Query sends the GraphQL request and unmarshals the "data" of the response
+// into data. If the response contains errors, the errors return as typed
+// errors, classified by their error code.
+func (c *GraphQLClient) Query(ctx context.Context, req *GraphQLRequest, data interface{}) error {
+	path := c.Path
+	if path == "" {
+		path = DefaultGraphQLPath
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	url, err := c.URL(path, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+
+	for key, val := range c.Headers {
+		headers[key] = val
+	}
+
+	resp, err := c.Call(ctx, &HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     url,
+		Data:    req,
+		Headers: headers,
+		Auth:    c.Auth,
+		Timeout: timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	var graphQLResp graphQLResponse
+	if err = json.Unmarshal(resp, &graphQLResp); err != nil {
+		return err
+	}
+
+	if len(graphQLResp.Errors) > 0 {
+		return graphQLResp.Errors.typed()
+	}
+
+	if data == nil || len(graphQLResp.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(graphQLResp.Data, data)
+}
+
+// GraphQLPaginatedParseFunc parses the "data" of a page and returns the
+// entries and the pagination info of the page.
+type GraphQLPaginatedParseFunc = func(json.RawMessage, *FetchOpts) (worklog.Entries, *PageInfo, error)
+
+// GraphQLPaginatedFetchOpts represents the options of fetching entries from a
+// cursor paginated GraphQL connection.
+type GraphQLPaginatedFetchOpts struct {
+	BaseFetchOpts *FetchOpts
+
+	// Request is the GraphQL request of the first page. The cursor and page
+	// size variables are set by the fetcher.
+	Request          *GraphQLRequest
+	PageSize         int
+	PageSizeVariable string
+	CursorVariable   string
+
+	ParseFunc GraphQLPaginatedParseFunc
+}
+
+// PaginatedFetch fetches the entries from a cursor paginated GraphQL
+// connection, requesting the pages until the last page is reached.
+func (c *GraphQLClient) PaginatedFetch(ctx context.Context, opts *GraphQLPaginatedFetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	pageSizeVariable := opts.PageSizeVariable
+	if pageSizeVariable == "" {
+		pageSizeVariable = DefaultPageSizeVariable
+	}
+
+	cursorVariable := opts.CursorVariable
+	if cursorVariable == "" {
+		cursorVariable = DefaultCursorVariable
+	}
+
+	variables := map[string]interface{}{}
+	for key, val := range opts.Request.Variables {
+		variables[key] = val
+	}
+
+	variables[pageSizeVariable] = pageSize
+
+	for {
+		var data json.RawMessage
+
+		err := c.Query(ctx, &GraphQLRequest{
+			Query:         opts.Request.Query,
+			OperationName: opts.Request.OperationName,
+			Variables:     variables,
+		}, &data)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFetchEntries, err)
+		}
+
+		parsedEntries, pageInfo, err := opts.ParseFunc(data, opts.BaseFetchOpts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFetchEntries, err)
+		}
+
+		entries = append(entries, parsedEntries...)
+
+		if pageInfo == nil || !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
+		}
+
+		variables[cursorVariable] = pageInfo.EndCursor
+	}
+
+	return entries, nil
+}
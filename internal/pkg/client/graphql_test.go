@@ -0,0 +1,161 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+// newGraphQLClient returns a GraphQL client calling a server that responds
+// using the handler.
+func newGraphQLClient(t *testing.T, handler func(req *client.GraphQLRequest) string) (*client.GraphQLClient, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, client.DefaultGraphQLPath, r.URL.Path)
+
+		var req client.GraphQLRequest
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+
+		_, _ = w.Write([]byte(handler(&req)))
+	}))
+
+	baseURL, err := url.Parse(server.URL)
+	require.Nil(t, err)
+
+	return &client.GraphQLClient{HTTPClient: &client.HTTPClient{BaseURL: baseURL}}, server.Close
+}
+
+func TestGraphQLQuery_String(t *testing.T) {
+	query := client.GraphQLQuery{
+		Name: "Entries",
+		Variables: []client.GraphQLVariable{
+			{Name: "first", Type: "Int!"},
+			{Name: "after", Type: "String"},
+		},
+		Selection: "entries(first: $first, after: $after) { nodes { id } }",
+	}
+
+	require.Equal(t, "query Entries($first: Int!, $after: String) { entries(first: $first, after: $after) { nodes { id } } }", query.String())
+	require.Equal(t, "mutation { ping }", (&client.GraphQLQuery{Operation: "mutation", Selection: "ping"}).String())
+}
+
+func TestGraphQLClient_Query(t *testing.T) {
+	graphQLClient, closeServer := newGraphQLClient(t, func(req *client.GraphQLRequest) string {
+		require.Equal(t, "query { user(id: $id) { name } }", req.Query)
+		require.Equal(t, "42", req.Variables["id"])
+		return `{"data":{"user":{"name":"Gabor"}}}`
+	})
+	defer closeServer()
+
+	var data struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+
+	err := graphQLClient.Query(context.Background(), &client.GraphQLRequest{
+		Query:     "query { user(id: $id) { name } }",
+		Variables: map[string]interface{}{"id": "42"},
+	}, &data)
+
+	require.Nil(t, err)
+	require.Equal(t, "Gabor", data.User.Name)
+}
+
+func TestGraphQLClient_Query_Errors(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		kind client.ErrorKind
+	}{
+		"unauthenticated": {
+			code: "UNAUTHENTICATED",
+			kind: client.ErrorKindAuth,
+		},
+		"not found": {
+			code: "NOT_FOUND",
+			kind: client.ErrorKindNotFound,
+		},
+		"rate limited": {
+			code: "RATE_LIMITED",
+			kind: client.ErrorKindRateLimited,
+		},
+		"bad user input": {
+			code: "BAD_USER_INPUT",
+			kind: client.ErrorKindValidation,
+		},
+		"unknown": {
+			code: "INTERNAL_SERVER_ERROR",
+			kind: client.ErrorKindUnknown,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			graphQLClient, closeServer := newGraphQLClient(t, func(_ *client.GraphQLRequest) string {
+				return `{"data":null,"errors":[{"message":"first","extensions":{"code":"` + test.code + `"}},{"message":"second"}]}`
+			})
+			defer closeServer()
+
+			err := graphQLClient.Query(context.Background(), &client.GraphQLRequest{Query: "query { ping }"}, nil)
+
+			require.ErrorIs(t, err, client.ErrGraphQL)
+			require.EqualError(t, err, client.ErrGraphQL.Error()+": first; second")
+			require.Equal(t, test.kind, client.KindOf(err))
+		})
+	}
+}
+
+func TestGraphQLClient_PaginatedFetch(t *testing.T) {
+	graphQLClient, closeServer := newGraphQLClient(t, func(req *client.GraphQLRequest) string {
+		require.Equal(t, float64(1), req.Variables["first"])
+		require.Equal(t, "me", req.Variables["user"])
+
+		if req.Variables["after"] == nil {
+			return `{"data":{"entries":{"nodes":["a"],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`
+		}
+
+		require.Equal(t, "c1", req.Variables["after"])
+		return `{"data":{"entries":{"nodes":["b"],"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}`
+	})
+	defer closeServer()
+
+	entries, err := graphQLClient.PaginatedFetch(context.Background(), &client.GraphQLPaginatedFetchOpts{
+		BaseFetchOpts: &client.FetchOpts{},
+		Request: &client.GraphQLRequest{
+			Query:     "query { ... }",
+			Variables: map[string]interface{}{"user": "me"},
+		},
+		PageSize: 1,
+		ParseFunc: func(data json.RawMessage, _ *client.FetchOpts) (worklog.Entries, *client.PageInfo, error) {
+			var page struct {
+				Entries struct {
+					Nodes    []string        `json:"nodes"`
+					PageInfo client.PageInfo `json:"pageInfo"`
+				} `json:"entries"`
+			}
+
+			if err := json.Unmarshal(data, &page); err != nil {
+				return nil, nil, err
+			}
+
+			var entries worklog.Entries
+			for _, node := range page.Entries.Nodes {
+				entries = append(entries, worklog.Entry{Summary: node})
+			}
+
+			return entries, &page.Entries.PageInfo, nil
+		},
+	})
+
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "a", entries[0].Summary)
+	require.Equal(t, "b", entries[1].Summary)
+}
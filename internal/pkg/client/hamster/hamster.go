@@ -0,0 +1,251 @@
+package hamster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// timeFormat is the format of the start and end times of the facts,
+	// stored in local time.
+	timeFormat string = "2006-01-02 15:04:05"
+
+	// factsQuery selects the finished facts started within the period. The
+	// texts are hex encoded to not break the output by special characters and
+	// the tags are separated by commas.
+	factsQuery string = `SELECT
+	f.id,
+	f.start_time,
+	f.end_time,
+	hex(a.name),
+	hex(coalesce(c.name, '')),
+	hex(coalesce(f.description, '')),
+	coalesce((SELECT group_concat(hex(t.name), ',') FROM fact_tags ft JOIN tags t ON t.id = ft.tag_id WHERE ft.fact_id = f.id), '')
+FROM facts f
+JOIN activities a ON a.id = f.activity_id
+LEFT JOIN categories c ON c.id = a.category_id
+WHERE f.end_time IS NOT NULL AND f.start_time >= '%s' AND f.start_time < '%s'
+ORDER BY f.start_time;
+`
+)
+
+var (
+	// ErrInvalidRow returns when a row of the query output cannot be parsed.
+	ErrInvalidRow = errors.New("invalid row")
+)
+
+// Fact represents an activity tracked by Hamster.
+type Fact struct {
+	ID          string
+	Start       time.Time
+	End         time.Time
+	Activity    string
+	Category    string
+	Description string
+	Tags        []string
+}
+
+// DefaultDatabasePath returns the path of the database used by Hamster. The
+// database of Hamster 3 is used if exists, otherwise the database of the
+// Hamster applet.
+func DefaultDatabasePath() string {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+
+		dataDir = filepath.Join(homeDir, ".local", "share")
+	}
+
+	path := filepath.Join(dataDir, "hamster", "hamster.db")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	return filepath.Join(dataDir, "hamster-applet", "hamster.db")
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Since Hamster has no API, the database is queried by the `sqlite3` command
+// line tool set by client.CLIClient.
+type ClientOpts struct {
+	client.BaseClientOpts
+	client.CLIClient
+	// Path is the path of the Hamster database.
+	Path string
+	// UnbillableTag marks the facts as unbillable.
+	UnbillableTag string
+}
+
+type hamsterClient struct {
+	*client.BaseClientOpts
+	*client.CLIClient
+	opts ClientOpts
+}
+
+// decodeText returns the hex encoded text of the query output.
+func decodeText(s string) (string, error) {
+	text, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(text), nil
+}
+
+// parseRow parses a row of the facts query output.
+func parseRow(row string) (Fact, error) {
+	fields := strings.Split(row, "|")
+	if len(fields) != 7 {
+		return Fact{}, fmt.Errorf("%w: %s", ErrInvalidRow, row)
+	}
+
+	fact := Fact{ID: fields[0]}
+
+	var err error
+	if fact.Start, err = time.ParseInLocation(timeFormat, fields[1], time.Local); err != nil {
+		return Fact{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+	}
+
+	if fact.End, err = time.ParseInLocation(timeFormat, fields[2], time.Local); err != nil {
+		return Fact{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+	}
+
+	texts := []*string{&fact.Activity, &fact.Category, &fact.Description}
+	for i, text := range texts {
+		if *text, err = decodeText(fields[i+3]); err != nil {
+			return Fact{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+		}
+	}
+
+	if fields[6] != "" {
+		for _, rawTag := range strings.Split(fields[6], ",") {
+			tag, err := decodeText(rawTag)
+			if err != nil {
+				return Fact{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+			}
+
+			fact.Tags = append(fact.Tags, tag)
+		}
+	}
+
+	return fact, nil
+}
+
+func (c *hamsterClient) queryFacts(ctx context.Context, opts *client.FetchOpts) ([]Fact, error) {
+	query := fmt.Sprintf(factsQuery, opts.Start.Local().Format(timeFormat), opts.End.Local().Format(timeFormat))
+
+	// The database is opened read-only, so Hamster can keep running
+	out, err := c.Execute(ctx, []string{"-batch", "-noheader", "-list", "-readonly", c.opts.Path}, &client.CLIExecuteOpts{
+		Timeout: c.Timeout,
+		Stdin:   strings.NewReader(query),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sqlite query failed: %v", err)
+	}
+
+	var facts []Fact
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fact, err := parseRow(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+
+		facts = append(facts, fact)
+	}
+
+	return facts, scanner.Err()
+}
+
+func (c *hamsterClient) parseFact(fact Fact) worklog.Entry {
+	summary := fact.Description
+	if summary == "" {
+		summary = fact.Activity
+	}
+
+	activity := worklog.IDNameField{
+		ID:   fact.Activity,
+		Name: fact.Activity,
+	}
+
+	entry := worklog.Entry{
+		Project: worklog.IDNameField{
+			ID:   fact.Category,
+			Name: fact.Category,
+		},
+		Task:             activity,
+		Summary:          summary,
+		Notes:            fact.Description,
+		Start:            fact.Start,
+		BillableDuration: fact.End.Sub(fact.Start),
+		Provenance:       worklog.Provenance{SourceIDs: []string{fact.ID}},
+	}
+
+	entry.AddLinks(utils.ExtractURLs(fact.Description)...)
+
+	var tags []worklog.IDNameField
+	for _, tag := range fact.Tags {
+		if c.opts.UnbillableTag != "" && tag == c.opts.UnbillableTag {
+			entry.UnbillableDuration = entry.BillableDuration
+			entry.BillableDuration = 0
+			continue
+		}
+
+		tags = append(tags, worklog.IDNameField{
+			ID:   tag,
+			Name: tag,
+		})
+	}
+
+	if len(tags) > 0 {
+		entry.TaskHints = &worklog.TaskHints{Tags: tags}
+	}
+
+	return entry
+}
+
+func (c *hamsterClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	facts, err := c.queryFacts(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var entries worklog.Entries
+	for _, fact := range facts {
+		entries = append(entries, c.parseFact(fact))
+	}
+
+	return entries, nil
+}
+
+// NewFetcher returns a new Hamster client for reading the facts of the
+// database.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no Hamster database path provided")
+	}
+
+	clientOpts := *opts
+
+	return &hamsterClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		CLIClient:      &clientOpts.CLIClient,
+		opts:           clientOpts,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package hamster_test
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hamster"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const testDatabase string = `
+CREATE TABLE categories (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE activities (id INTEGER PRIMARY KEY, name TEXT, category_id INTEGER);
+CREATE TABLE facts (id INTEGER PRIMARY KEY, activity_id INTEGER, start_time TIMESTAMP, end_time TIMESTAMP, description TEXT);
+CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE fact_tags (fact_id INTEGER, tag_id INTEGER);
+
+INSERT INTO categories VALUES (1, 'ACME | Website');
+INSERT INTO activities VALUES (1, 'Development', 1), (2, 'Meeting', NULL);
+INSERT INTO tags VALUES (1, 'TASK-1'), (2, 'TASK-2'), (3, 'unbillable');
+INSERT INTO facts VALUES
+	(1, 1, '2021-10-01 09:00:00', '2021-10-01 10:00:00', 'Fix the bug
+https://example.com/issues/1'),
+	(2, 1, '2021-10-01 11:00:00', '2021-10-01 13:00:00', ''),
+	(3, 2, '2021-10-01 14:00:00', '2021-10-01 14:30:00', NULL),
+	(4, 1, '2021-09-30 09:00:00', '2021-09-30 10:00:00', 'Outside the period'),
+	(5, 1, '2021-10-01 15:00:00', NULL, 'Running');
+INSERT INTO fact_tags VALUES (2, 1), (2, 2), (3, 3);
+`
+
+func getTestFetcher(t *testing.T, statements string) client.Fetcher {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+
+	path := filepath.Join(t.TempDir(), "hamster.db")
+
+	cmd := exec.Command("sqlite3", path)
+	cmd.Stdin = strings.NewReader(statements)
+	require.Nil(t, cmd.Run())
+
+	fetcher, err := hamster.NewFetcher(&hamster.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: time.Second * 10,
+		},
+		CLIClient: client.CLIClient{
+			Command:            "sqlite3",
+			CommandCtxExecutor: exec.CommandContext,
+		},
+		Path:          path,
+		UnbillableTag: "unbillable",
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestHamsterClient_FetchEntries(t *testing.T) {
+	fetcher := getTestFetcher(t, testDatabase)
+
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+	category := worklog.IDNameField{ID: "ACME | Website", Name: "ACME | Website"}
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          category,
+			Task:             worklog.IDNameField{ID: "Development", Name: "Development"},
+			Summary:          "Fix the bug\nhttps://example.com/issues/1",
+			Notes:            "Fix the bug\nhttps://example.com/issues/1",
+			Start:            time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+			Links:            []string{"https://example.com/issues/1"},
+			Provenance:       worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Project:          category,
+			Task:             worklog.IDNameField{ID: "TASK-1", Name: "TASK-1"},
+			Summary:          "Development",
+			Start:            time.Date(2021, 10, 1, 11, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"2"}, Transformations: []string{"split by tags"}},
+		},
+		{
+			Project:          category,
+			Task:             worklog.IDNameField{ID: "TASK-2", Name: "TASK-2"},
+			Summary:          "Development",
+			Start:            time.Date(2021, 10, 1, 11, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"2"}, Transformations: []string{"split by tags"}},
+		},
+		{
+			Task:               worklog.IDNameField{ID: "Meeting", Name: "Meeting"},
+			Summary:            "Meeting",
+			Start:              time.Date(2021, 10, 1, 14, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Minute * 30,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
+		},
+	}
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestHamsterClient_FetchEntries_InvalidDatabase(t *testing.T) {
+	fetcher := getTestFetcher(t, "CREATE TABLE facts (id INTEGER PRIMARY KEY);")
+
+	_, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestDefaultDatabasePath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/data")
+	require.Equal(t, filepath.Join("/tmp/data", "hamster-applet", "hamster.db"), hamster.DefaultDatabasePath())
+}
+
+func TestNewFetcher_NoPath(t *testing.T) {
+	_, err := hamster.NewFetcher(&hamster.ClientOpts{})
+	require.Error(t, err)
+}
@@ -19,17 +19,26 @@ const (
 	PathWorklog string = "/v2/time_entries"
 )
 
+// ExternalReference represents the external resource an entry is linked to,
+// like a Jira issue or a GitHub pull request.
+type ExternalReference struct {
+	Permalink string `json:"permalink"`
+}
+
 // FetchEntry represents the entry fetched from Harvest.
 type FetchEntry struct {
+	ID        int                    `json:"id"`
 	Client    worklog.IntIDNameField `json:"client"`
 	Project   worklog.IntIDNameField `json:"project"`
 	Task      worklog.IntIDNameField `json:"task"`
 	Notes     string                 `json:"notes"`
 	SpentDate string                 `json:"spent_date"`
 	Hours     float32                `json:"hours"`
-	CreatedAt time.Time              `json:"created_at"`
+	CreatedAt utils.Timestamp        `json:"created_at"`
 	Billable  bool                   `json:"billable"`
 	IsRunning bool                   `json:"is_running"`
+
+	ExternalReference ExternalReference `json:"external_reference"`
 }
 
 // Start returns the start date created from the spent date and created at.
@@ -82,23 +91,18 @@ type harvestClient struct {
 	account       int
 }
 
-func (c *harvestClient) parseEntries(rawEntries interface{}, _ *client.FetchOpts) (worklog.Entries, error) {
+func (c *harvestClient) parseEntries(fetchedEntries []FetchEntry, _ *client.FetchOpts) (worklog.Entries, error) {
 	var entries worklog.Entries
 
-	fetchedEntries, ok := rawEntries.([]FetchEntry)
-	if !ok {
-		return nil, fmt.Errorf("%v: %s", client.ErrFetchEntries, "cannot parse returned entries")
-	}
-
 	for _, fetchedEntry := range fetchedEntries {
 		startDate, err := fetchedEntry.Start()
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 		}
 
-		billableDuration, err := time.ParseDuration(fmt.Sprintf("%fh", fetchedEntry.Hours))
+		billableDuration, err := utils.ParseDuration(strconv.FormatFloat(float64(fetchedEntry.Hours), 'f', -1, 32), time.Hour)
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 		}
 
 		unbillableDuration := time.Duration(0)
@@ -108,7 +112,7 @@ func (c *harvestClient) parseEntries(rawEntries interface{}, _ *client.FetchOpts
 			billableDuration = 0
 		}
 
-		entries = append(entries, worklog.Entry{
+		entry := worklog.Entry{
 			Client:             fetchedEntry.Client.ConvertToIDNameField(),
 			Project:            fetchedEntry.Project.ConvertToIDNameField(),
 			Task:               fetchedEntry.Task.ConvertToIDNameField(),
@@ -117,13 +121,19 @@ func (c *harvestClient) parseEntries(rawEntries interface{}, _ *client.FetchOpts
 			Start:              startDate,
 			BillableDuration:   billableDuration,
 			UnbillableDuration: unbillableDuration,
-		})
+			Provenance:         worklog.Provenance{SourceIDs: []string{strconv.Itoa(fetchedEntry.ID)}},
+		}
+
+		entry.AddLinks(fetchedEntry.ExternalReference.Permalink)
+		entry.AddLinks(utils.ExtractURLs(fetchedEntry.Notes)...)
+
+		entries = append(entries, entry)
 	}
 
 	return entries, nil
 }
 
-func (c *harvestClient) fetchEntries(ctx context.Context, reqURL string) (interface{}, *client.PaginatedFetchResponse, error) {
+func (c *harvestClient) fetchEntries(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
 	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
 		Method:  http.MethodGet,
 		Url:     reqURL,
@@ -135,12 +145,12 @@ func (c *harvestClient) fetchEntries(ctx context.Context, reqURL string) (interf
 	})
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	var fetchResponse FetchResponse
 	if err = json.Unmarshal(resp, &fetchResponse); err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	paginatedResponse := &client.PaginatedFetchResponse{
@@ -153,25 +163,25 @@ func (c *harvestClient) fetchEntries(ctx context.Context, reqURL string) (interf
 
 func (c *harvestClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
 	fetchURL, err := c.URL(PathWorklog, map[string]string{
-		"from":       utils.DateFormatRFC3339UTC.Format(opts.Start),
-		"to":         utils.DateFormatRFC3339UTC.Format(opts.End),
+		"from":       utils.DateFormatISO8601.Format(opts.Start),
+		"to":         utils.DateFormatISO8601.Format(opts.LastDay()),
 		"user_id":    opts.User,
 		"is_running": strconv.FormatBool(false),
 		"user_agent": "github.com/gabor-boros/minutes",
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
-	return c.PaginatedFetch(ctx, &client.PaginatedFetchOpts{
+	return client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[FetchEntry]{
 		URL:       fetchURL,
 		FetchFunc: c.fetchEntries,
 		ParseFunc: c.parseEntries,
 	})
 }
 
-// NewFetcher returns a new Clockify client for fetching entries.
+// NewFetcher returns a new Harvest client for fetching entries.
 func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
 	baseURL, err := url.Parse(opts.BaseURL)
 	if err != nil {
@@ -24,6 +24,7 @@ type mockServerOpts struct {
 	StatusCode   int
 	Token        string
 	TokenHeader  string
+	Account      string
 	ResponseData *harvest.FetchResponse
 }
 
@@ -38,6 +39,8 @@ func mockServer(t *testing.T, e *mockServerOpts) *httptest.Server {
 			require.Equal(t, e.Token, headerValue, "API call auth token mismatch")
 		}
 
+		require.Equal(t, e.Account, r.Header.Get("Harvest-Account-ID"), "API call account mismatch")
+
 		if e.ResponseData != nil {
 			err := json.NewEncoder(w).Encode(e.ResponseData)
 			require.Nil(t, err, "cannot encode response data")
@@ -58,7 +61,7 @@ func TestFetchEntry_Start(t *testing.T) {
 
 	entry := harvest.FetchEntry{
 		SpentDate: "2021-09-30",
-		CreatedAt: time.Date(2021, 10, 1, 23, 59, 59, 0, time.UTC),
+		CreatedAt: utils.Timestamp{Time: time.Date(2021, 10, 1, 23, 59, 59, 0, time.UTC)},
 	}
 
 	startDate, err := entry.Start()
@@ -90,6 +93,7 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   time.Hour * 2,
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -109,6 +113,7 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: time.Hour * 3,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
 		},
 	}
 
@@ -117,8 +122,8 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 		QueryParams: url.Values{
 			"page":       {"1"},
 			"per_page":   {"50"},
-			"from":       {utils.DateFormatRFC3339UTC.Format(start)},
-			"to":         {utils.DateFormatRFC3339UTC.Format(end)},
+			"from":       {utils.DateFormatISO8601.Format(start)},
+			"to":         {utils.DateFormatISO8601.Format(end)},
 			"user_id":    {"987654321"},
 			"is_running": {"false"},
 			"user_agent": {"github.com/gabor-boros/minutes"},
@@ -127,9 +132,11 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 		StatusCode:  http.StatusOK,
 		Token:       "Bearer t-o-k-e-n",
 		TokenHeader: "Authorization",
+		Account:     "123456789",
 		ResponseData: &harvest.FetchResponse{
 			TimeEntries: []harvest.FetchEntry{
 				{
+					ID: 1,
 					Client: worklog.IntIDNameField{
 						ID:   1,
 						Name: "My Awesome Company",
@@ -145,11 +152,12 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 					Notes:     "I met with The Winter Soldier",
 					SpentDate: utils.DateFormatISO8601.Format(start),
 					Hours:     2.0,
-					CreatedAt: start,
+					CreatedAt: utils.Timestamp{Time: start},
 					Billable:  true,
 					IsRunning: false,
 				},
 				{
+					ID: 2,
 					Client: worklog.IntIDNameField{
 						ID:   1,
 						Name: "My Awesome Company",
@@ -165,7 +173,7 @@ func TestHarvestClient_FetchEntries(t *testing.T) {
 					Notes:     "I helped him to get back on track",
 					SpentDate: utils.DateFormatISO8601.Format(start),
 					Hours:     3.0,
-					CreatedAt: start,
+					CreatedAt: utils.Timestamp{Time: start},
 					Billable:  false,
 					IsRunning: false,
 				},
@@ -0,0 +1,524 @@
+package hubstaff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the Hubstaff API.
+	DefaultURL string = "https://api.hubstaff.com"
+	// DefaultTokenURL is the endpoint exchanging the personal access tokens
+	// to access tokens.
+	DefaultTokenURL string = "https://account.hubstaff.com/access_tokens"
+	// PathActivities is the endpoint used to list the activities of the
+	// organization. The activities are the time slots of ten minutes at most
+	// tracked by the users.
+	PathActivities string = "/v2/organizations/%s/activities"
+	// PathWorkBreaks is the endpoint used to list the work breaks of the
+	// organization.
+	PathWorkBreaks string = "/v2/organizations/%s/work_breaks"
+	// PathProject is the endpoint used to get a project.
+	PathProject string = "/v2/projects/%d"
+	// PathTask is the endpoint used to get a task.
+	PathTask string = "/v2/tasks/%d"
+	// PathClient is the endpoint used to get a client.
+	PathClient string = "/v2/clients/%d"
+	// PathMe is the endpoint used to get the owner of the token.
+	PathMe string = "/v2/users/me"
+	// MaxPageSize is the maximum number of items returned per page.
+	MaxPageSize int = 500
+	// MaxRangeDays is the number of days the activities are listed for at
+	// once, since the API limits the time slot queried to a week.
+	MaxRangeDays int = 7
+
+	// IdleModeKeep counts the idle time as tracked time.
+	IdleModeKeep string = "keep"
+	// IdleModeUnbillable counts the idle time of the billable activities as
+	// unbillable time.
+	IdleModeUnbillable string = "unbillable"
+	// IdleModeDrop removes the idle time from the tracked time.
+	IdleModeDrop string = "drop"
+)
+
+var (
+	// IdleModes lists the supported ways of handling the idle time.
+	IdleModes = []string{IdleModeKeep, IdleModeUnbillable, IdleModeDrop}
+
+	// WorkBreakProject is the project of the entries created from the paid
+	// work breaks.
+	WorkBreakProject = worklog.IDNameField{ID: "work_break", Name: "Work break"}
+)
+
+// Pagination represents the cursor of the next page. If the start ID of the
+// next page is not set, the last page was fetched.
+type Pagination struct {
+	NextPageStartID int `json:"next_page_start_id"`
+}
+
+// Activity represents a time slot tracked by the user. The tracked and idle
+// durations are in seconds, and the idle time is part of the tracked time.
+// The date is the day of the activity in the timezone of the user.
+type Activity struct {
+	ID        int       `json:"id"`
+	Date      string    `json:"date"`
+	UserID    int       `json:"user_id"`
+	ProjectID int       `json:"project_id"`
+	TaskID    int       `json:"task_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	Tracked   int       `json:"tracked"`
+	Idle      int       `json:"idle"`
+	Billable  bool      `json:"billable"`
+}
+
+// ActivitiesResponse represents a page of the activities.
+type ActivitiesResponse struct {
+	Activities []Activity `json:"activities"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// WorkBreak represents a break taken by the user. The tracked duration is in
+// seconds.
+type WorkBreak struct {
+	ID       int       `json:"id"`
+	Date     string    `json:"date"`
+	UserID   int       `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	Tracked  int       `json:"tracked"`
+	Paid     bool      `json:"paid"`
+}
+
+// WorkBreaksResponse represents a page of the work breaks.
+type WorkBreaksResponse struct {
+	WorkBreaks []WorkBreak `json:"work_breaks"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// Project represents a project of the organization. The client ID is not set
+// if the project has no client.
+type Project struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ClientID int    `json:"client_id"`
+}
+
+// Task represents a to-do of a project.
+type Task struct {
+	ID        int    `json:"id"`
+	Summary   string `json:"summary"`
+	ProjectID int    `json:"project_id"`
+}
+
+// Client represents a client of the organization.
+type Client struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// User represents a Hubstaff user.
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// TokenURL is the endpoint used to exchange the RefreshToken to access
+	// tokens. If not set, DefaultTokenURL is used.
+	TokenURL string
+	// RefreshToken is the personal access token of the user.
+	RefreshToken string
+	// RotateRefreshToken is called with the new refresh token if Hubstaff
+	// rotates it, so the new token can be used by the next runs.
+	RotateRefreshToken func(ctx context.Context, refreshToken string) error
+	// OrganizationID is the ID of the organization the activities are
+	// fetched from.
+	OrganizationID string
+	// IdleMode sets the handling of the idle time, like IdleModeKeep.
+	IdleMode string
+	// IncludeBreaks creates unbillable entries from the paid work breaks.
+	IncludeBreaks bool
+}
+
+type hubstaffClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	auth           client.Authenticator
+	organizationID string
+	idleMode       string
+	includeBreaks  bool
+}
+
+// names resolves the names of the projects, tasks and clients of the
+// activities, and caches them during a fetch.
+type names struct {
+	projects map[int]Project
+	tasks    map[int]Task
+	clients  map[int]Client
+}
+
+// activityKey identifies the activities collapsed into the same entry.
+type activityKey struct {
+	date      string
+	projectID int
+	taskID    int
+}
+
+// splitDuration returns the billable and unbillable durations of the activity
+// by the idle mode.
+func splitDuration(activity *Activity, idleMode string) (time.Duration, time.Duration) {
+	tracked := time.Duration(activity.Tracked) * time.Second
+	idle := time.Duration(activity.Idle) * time.Second
+
+	if idle > tracked {
+		idle = tracked
+	}
+
+	switch idleMode {
+	case IdleModeDrop:
+		tracked -= idle
+		idle = 0
+	case IdleModeKeep:
+		idle = 0
+	}
+
+	if !activity.Billable {
+		return 0, tracked
+	}
+
+	return tracked - idle, idle
+}
+
+func (c *hubstaffClient) get(ctx context.Context, path string, params map[string]string, result interface{}) error {
+	reqURL, err := c.URL(path, params)
+	if err != nil {
+		return err
+	}
+
+	return c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.auth,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	}, result)
+}
+
+// userID returns the ID of the user the activities are fetched for. If the
+// user is not set, the ID of the owner of the token returns.
+func (c *hubstaffClient) userID(ctx context.Context, user string) (string, error) {
+	if user != "" {
+		return user, nil
+	}
+
+	var response struct {
+		User User `json:"user"`
+	}
+
+	if err := c.get(ctx, PathMe, map[string]string{}, &response); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(response.User.ID), nil
+}
+
+// listParams returns the query params of listing the items of the user in the
+// period, from the first page.
+func listParams(userID string, opts *client.FetchOpts) map[string]string {
+	return map[string]string{
+		"time_slot[start]": opts.Start.UTC().Format(time.RFC3339),
+		"time_slot[stop]":  opts.End.UTC().Format(time.RFC3339),
+		"user_ids[]":       userID,
+		"page_limit":       strconv.Itoa(MaxPageSize),
+	}
+}
+
+func (c *hubstaffClient) fetchActivities(ctx context.Context, params map[string]string) ([]Activity, error) {
+	var activities []Activity
+
+	path := fmt.Sprintf(PathActivities, url.PathEscape(c.organizationID))
+
+	for {
+		var response ActivitiesResponse
+		if err := c.get(ctx, path, params, &response); err != nil {
+			return nil, err
+		}
+
+		activities = append(activities, response.Activities...)
+
+		if response.Pagination.NextPageStartID == 0 {
+			return activities, nil
+		}
+
+		params["page_start_id"] = strconv.Itoa(response.Pagination.NextPageStartID)
+	}
+}
+
+func (c *hubstaffClient) fetchWorkBreaks(ctx context.Context, params map[string]string) ([]WorkBreak, error) {
+	var workBreaks []WorkBreak
+
+	path := fmt.Sprintf(PathWorkBreaks, url.PathEscape(c.organizationID))
+
+	for {
+		var response WorkBreaksResponse
+		if err := c.get(ctx, path, params, &response); err != nil {
+			return nil, err
+		}
+
+		workBreaks = append(workBreaks, response.WorkBreaks...)
+
+		if response.Pagination.NextPageStartID == 0 {
+			return workBreaks, nil
+		}
+
+		params["page_start_id"] = strconv.Itoa(response.Pagination.NextPageStartID)
+	}
+}
+
+// resolveNames fetches the projects, tasks and clients of the activities, as
+// the activities return their IDs only.
+func (c *hubstaffClient) resolveNames(ctx context.Context, activities []Activity) (*names, error) {
+	resolved := &names{
+		projects: map[int]Project{},
+		tasks:    map[int]Task{},
+		clients:  map[int]Client{},
+	}
+
+	for _, activity := range activities {
+		if _, ok := resolved.projects[activity.ProjectID]; !ok {
+			var response struct {
+				Project Project `json:"project"`
+			}
+
+			if err := c.get(ctx, fmt.Sprintf(PathProject, activity.ProjectID), map[string]string{}, &response); err != nil {
+				return nil, err
+			}
+
+			resolved.projects[activity.ProjectID] = response.Project
+		}
+
+		if _, ok := resolved.tasks[activity.TaskID]; activity.TaskID != 0 && !ok {
+			var response struct {
+				Task Task `json:"task"`
+			}
+
+			if err := c.get(ctx, fmt.Sprintf(PathTask, activity.TaskID), map[string]string{}, &response); err != nil {
+				return nil, err
+			}
+
+			resolved.tasks[activity.TaskID] = response.Task
+		}
+	}
+
+	for _, project := range resolved.projects {
+		if _, ok := resolved.clients[project.ClientID]; project.ClientID == 0 || ok {
+			continue
+		}
+
+		var response struct {
+			Client Client `json:"client"`
+		}
+
+		if err := c.get(ctx, fmt.Sprintf(PathClient, project.ClientID), map[string]string{}, &response); err != nil {
+			return nil, err
+		}
+
+		resolved.clients[project.ClientID] = response.Client
+	}
+
+	return resolved, nil
+}
+
+// parseActivities collapses the activities of the same day, project and task
+// into one entry, starting at the first activity. The entries are sorted by
+// their start.
+func (c *hubstaffClient) parseActivities(activities []Activity, resolved *names) worklog.Entries {
+	var keys []activityKey
+	collapsed := map[activityKey]*worklog.Entry{}
+
+	for i := range activities {
+		activity := &activities[i]
+		billableDuration, unbillableDuration := splitDuration(activity, c.idleMode)
+
+		if billableDuration+unbillableDuration == 0 {
+			continue
+		}
+
+		key := activityKey{date: activity.Date, projectID: activity.ProjectID, taskID: activity.TaskID}
+		startDate := activity.StartsAt.Local()
+
+		entry, ok := collapsed[key]
+		if !ok {
+			entry = c.newEntry(activity, resolved)
+			collapsed[key] = entry
+			keys = append(keys, key)
+		}
+
+		if startDate.Before(entry.Start) {
+			entry.Start = startDate
+		}
+
+		entry.BillableDuration += billableDuration
+		entry.UnbillableDuration += unbillableDuration
+		entry.Provenance.SourceIDs = append(entry.Provenance.SourceIDs, strconv.Itoa(activity.ID))
+	}
+
+	entries := make(worklog.Entries, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, *collapsed[key])
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Start.Before(entries[j].Start)
+	})
+
+	return entries
+}
+
+// newEntry returns the entry of the activity without durations. The summary
+// is set to the task of the activity, or the project if no task is set.
+func (c *hubstaffClient) newEntry(activity *Activity, resolved *names) *worklog.Entry {
+	project := resolved.projects[activity.ProjectID]
+
+	entry := &worklog.Entry{
+		Project: worklog.IDNameField{ID: strconv.Itoa(project.ID), Name: project.Name},
+		Summary: project.Name,
+		Start:   activity.StartsAt.Local(),
+	}
+
+	if project.ClientID != 0 {
+		projectClient := resolved.clients[project.ClientID]
+		entry.Client = worklog.IDNameField{ID: strconv.Itoa(projectClient.ID), Name: projectClient.Name}
+	}
+
+	task, ok := resolved.tasks[activity.TaskID]
+	if !ok {
+		return entry
+	}
+
+	entry.Task = worklog.IDNameField{ID: strconv.Itoa(task.ID), Name: task.Summary}
+	entry.Summary = task.Summary
+
+	// The to-dos are usually named after the issues of the issue trackers,
+	// hence the task is extracted from their summary
+	entry.TaskHints = &worklog.TaskHints{Texts: []string{task.Summary}}
+
+	return entry
+}
+
+// parseWorkBreaks returns an unbillable entry for every paid work break.
+func parseWorkBreaks(workBreaks []WorkBreak) worklog.Entries {
+	var entries worklog.Entries
+
+	for _, workBreak := range workBreaks {
+		if !workBreak.Paid || workBreak.Tracked <= 0 {
+			continue
+		}
+
+		entries = append(entries, worklog.Entry{
+			Project:            WorkBreakProject,
+			Summary:            WorkBreakProject.Name,
+			Start:              workBreak.StartsAt.Local(),
+			UnbillableDuration: time.Duration(workBreak.Tracked) * time.Second,
+			Provenance:         worklog.Provenance{SourceIDs: []string{strconv.Itoa(workBreak.ID)}},
+		})
+	}
+
+	return entries
+}
+
+// MaxRangeDays returns the number of days the activities are listed for at
+// once, so longer periods are fetched in chunks.
+func (c *hubstaffClient) MaxRangeDays() int {
+	return MaxRangeDays
+}
+
+func (c *hubstaffClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	userID, err := c.userID(ctx, opts.User)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	activities, err := c.fetchActivities(ctx, listParams(userID, opts))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	resolved, err := c.resolveNames(ctx, activities)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	entries := c.parseActivities(activities, resolved)
+
+	if !c.includeBreaks {
+		return entries, nil
+	}
+
+	workBreaks, err := c.fetchWorkBreaks(ctx, listParams(userID, opts))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	return append(entries, parseWorkBreaks(workBreaks)...), nil
+}
+
+// NewFetcher returns a new Hubstaff client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.RefreshToken == "" {
+		return nil, errors.New("no Hubstaff personal access token provided")
+	}
+
+	if opts.OrganizationID == "" {
+		return nil, errors.New("no Hubstaff organization provided")
+	}
+
+	idleMode := opts.IdleMode
+	if idleMode == "" {
+		idleMode = IdleModeKeep
+	}
+
+	if idleMode != IdleModeKeep && idleMode != IdleModeUnbillable && idleMode != IdleModeDrop {
+		return nil, fmt.Errorf("\"%s\" is not part of the supported idle modes %v", idleMode, IdleModes)
+	}
+
+	tokenURL := opts.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &client.HTTPClient{BaseURL: baseURL}
+
+	refresh := client.NewOAuthRefreshFunc(httpClient, &client.OAuthOpts{
+		TokenURL:           tokenURL,
+		Timeout:            opts.Timeout,
+		RotateRefreshToken: opts.RotateRefreshToken,
+	}, opts.RefreshToken)
+
+	return &hubstaffClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient:     httpClient,
+		auth:           client.NewRefreshingTokenAuth("Authorization", "Bearer", refresh),
+		organizationID: opts.OrganizationID,
+		idleMode:       idleMode,
+		includeBreaks:  opts.IncludeBreaks,
+	}, nil
+}
@@ -0,0 +1,283 @@
+package hubstaff_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/hubstaff"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	organizationID = "7"
+
+	// The activities of the "CPT-123 Fix thing" task are collapsed into one
+	// entry; the second page has an activity of a project without task.
+	activitiesFirstPage = `{
+		"activities": [
+			{"id": 10, "date": "2021-10-01", "user_id": 1, "project_id": 2, "task_id": 4, "starts_at": "2021-10-01T09:00:00Z", "tracked": 600, "idle": 120, "billable": true},
+			{"id": 11, "date": "2021-10-01", "user_id": 1, "project_id": 2, "task_id": 4, "starts_at": "2021-10-01T08:50:00Z", "tracked": 600, "idle": 0, "billable": true}
+		],
+		"pagination": {"next_page_start_id": 12}
+	}`
+	activitiesSecondPage = `{
+		"activities": [
+			{"id": 12, "date": "2021-10-01", "user_id": 1, "project_id": 5, "task_id": null, "starts_at": "2021-10-01T14:00:00Z", "tracked": 1800, "idle": 0, "billable": false}
+		],
+		"pagination": {}
+	}`
+	workBreaksResponse = `{
+		"work_breaks": [
+			{"id": 20, "date": "2021-10-01", "user_id": 1, "starts_at": "2021-10-01T12:00:00Z", "tracked": 900, "paid": true},
+			{"id": 21, "date": "2021-10-01", "user_id": 1, "starts_at": "2021-10-01T12:30:00Z", "tracked": 1800, "paid": false}
+		]
+	}`
+)
+
+var (
+	acme     = worklog.IDNameField{ID: "3", Name: "ACME Inc."}
+	website  = worklog.IDNameField{ID: "2", Name: "Website"}
+	internal = worklog.IDNameField{ID: "5", Name: "Internal"}
+	fixThing = worklog.IDNameField{ID: "4", Name: "CPT-123 Fix thing"}
+)
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2021, 10, 1, hour, minute, 0, 0, time.UTC).Local()
+}
+
+func newMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/access_tokens" {
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, client.GrantTypeRefreshToken, r.PostForm.Get("grant_type"))
+			require.Equal(t, "personal-token", r.PostForm.Get("refresh_token"))
+			_, _ = w.Write([]byte(`{"access_token":"access-token","refresh_token":"rotated-token","expires_in":86400}`))
+			return
+		}
+
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+
+		query := r.URL.Query()
+		response := ""
+
+		switch r.URL.Path {
+		case hubstaff.PathMe:
+			response = `{"user": {"id": 1, "name": "Jane Doe"}}`
+		case fmt.Sprintf(hubstaff.PathActivities, organizationID), fmt.Sprintf(hubstaff.PathWorkBreaks, organizationID):
+			require.Equal(t, "2021-10-01T00:00:00Z", query.Get("time_slot[start]"))
+			require.Equal(t, "2021-10-02T00:00:00Z", query.Get("time_slot[stop]"))
+			require.Equal(t, "1", query.Get("user_ids[]"))
+			require.Equal(t, strconv.Itoa(hubstaff.MaxPageSize), query.Get("page_limit"))
+
+			switch {
+			case r.URL.Path == fmt.Sprintf(hubstaff.PathWorkBreaks, organizationID):
+				response = workBreaksResponse
+			case query.Get("page_start_id") == "12":
+				response = activitiesSecondPage
+			default:
+				require.Empty(t, query.Get("page_start_id"))
+				response = activitiesFirstPage
+			}
+		case fmt.Sprintf(hubstaff.PathProject, 2):
+			response = `{"project": {"id": 2, "name": "Website", "client_id": 3}}`
+		case fmt.Sprintf(hubstaff.PathProject, 5):
+			response = `{"project": {"id": 5, "name": "Internal", "client_id": null}}`
+		case fmt.Sprintf(hubstaff.PathTask, 4):
+			response = `{"task": {"id": 4, "summary": "CPT-123 Fix thing", "project_id": 2}}`
+		case fmt.Sprintf(hubstaff.PathClient, 3):
+			response = `{"client": {"id": 3, "name": "ACME Inc."}}`
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		_, err := w.Write([]byte(response))
+		require.Nil(t, err)
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string, idleMode string, includeBreaks bool) client.Fetcher {
+	fetcher, err := hubstaff.NewFetcher(&hubstaff.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:        serverURL,
+		TokenURL:       serverURL + "/access_tokens",
+		RefreshToken:   "personal-token",
+		OrganizationID: organizationID,
+		IdleMode:       idleMode,
+		IncludeBreaks:  includeBreaks,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func newFetchOpts() *client.FetchOpts {
+	return &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHubstaffClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	expectedEntries := worklog.Entries{
+		{
+			Client:           acme,
+			Project:          website,
+			Task:             fixThing,
+			Summary:          "CPT-123 Fix thing",
+			Start:            at(8, 50),
+			BillableDuration: time.Minute * 20,
+			TaskHints:        &worklog.TaskHints{Texts: []string{"CPT-123 Fix thing"}},
+			Provenance:       worklog.Provenance{SourceIDs: []string{"10", "11"}},
+		},
+		{
+			Project:            internal,
+			Summary:            "Internal",
+			Start:              at(14, 0),
+			UnbillableDuration: time.Minute * 30,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"12"}},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL, "", false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, expectedEntries, entries)
+}
+
+func TestHubstaffClient_FetchEntries_IdleModes(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	tests := []struct {
+		idleMode           string
+		billableDuration   time.Duration
+		unbillableDuration time.Duration
+	}{
+		{idleMode: hubstaff.IdleModeKeep, billableDuration: time.Minute * 20},
+		{idleMode: hubstaff.IdleModeUnbillable, billableDuration: time.Minute * 18, unbillableDuration: time.Minute * 2},
+		{idleMode: hubstaff.IdleModeDrop, billableDuration: time.Minute * 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.idleMode, func(t *testing.T) {
+			entries, err := newTestFetcher(t, mockServer.URL, tt.idleMode, false).FetchEntries(context.Background(), newFetchOpts())
+			require.Nil(t, err)
+			require.Len(t, entries, 2)
+			require.Equal(t, tt.billableDuration, entries[0].BillableDuration)
+			require.Equal(t, tt.unbillableDuration, entries[0].UnbillableDuration)
+
+			// The idle mode does not change the activities without idle time
+			require.Equal(t, time.Minute*30, entries[1].UnbillableDuration)
+		})
+	}
+}
+
+func TestHubstaffClient_FetchEntries_IncludeBreaks(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, "", true).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Len(t, entries, 3)
+
+	// The unpaid work breaks are skipped
+	require.Equal(t, worklog.Entry{
+		Project:            hubstaff.WorkBreakProject,
+		Summary:            hubstaff.WorkBreakProject.Name,
+		Start:              at(12, 0),
+		UnbillableDuration: time.Minute * 15,
+		Provenance:         worklog.Provenance{SourceIDs: []string{"20"}},
+	}, entries[2])
+}
+
+func TestHubstaffClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, "", false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 2)
+	require.Equal(t, worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}, entries[0].Task)
+	require.Equal(t, "CPT-123 Fix thing", entries[0].Summary)
+	require.Equal(t, worklog.IDNameField{}, entries[1].Task)
+}
+
+func TestHubstaffClient_FetchEntries_RotateRefreshToken(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	var rotated string
+
+	fetcher, err := hubstaff.NewFetcher(&hubstaff.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:        mockServer.URL,
+		TokenURL:       mockServer.URL + "/access_tokens",
+		RefreshToken:   "personal-token",
+		OrganizationID: organizationID,
+		RotateRefreshToken: func(_ context.Context, refreshToken string) error {
+			rotated = refreshToken
+			return nil
+		},
+	})
+	require.Nil(t, err)
+
+	_, err = fetcher.FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, "rotated-token", rotated)
+}
+
+func TestHubstaffClient_FetchEntries_Unauthorized(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, "", false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestHubstaffClient_MaxRangeDays(t *testing.T) {
+	fetcher := newTestFetcher(t, "https://api.hubstaff.com", "", false)
+
+	limiter, ok := fetcher.(client.RangeLimiter)
+	require.True(t, ok)
+	require.Equal(t, hubstaff.MaxRangeDays, limiter.MaxRangeDays())
+}
+
+func TestNewFetcher_NoRefreshToken(t *testing.T) {
+	_, err := hubstaff.NewFetcher(&hubstaff.ClientOpts{OrganizationID: organizationID})
+	require.EqualError(t, err, "no Hubstaff personal access token provided")
+}
+
+func TestNewFetcher_NoOrganization(t *testing.T) {
+	_, err := hubstaff.NewFetcher(&hubstaff.ClientOpts{RefreshToken: "personal-token"})
+	require.EqualError(t, err, "no Hubstaff organization provided")
+}
+
+func TestNewFetcher_UnknownIdleMode(t *testing.T) {
+	_, err := hubstaff.NewFetcher(&hubstaff.ClientOpts{
+		RefreshToken:   "personal-token",
+		OrganizationID: organizationID,
+		IdleMode:       "ignore",
+	})
+	require.EqualError(t, err, `"ignore" is not part of the supported idle modes [keep unbillable drop]`)
+}
@@ -0,0 +1,565 @@
+package icsfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// AttributeCategories is the name of the attribute listing the categories
+	// of the event, separated by commas.
+	AttributeCategories string = "icsfile.categories"
+	// AttributeLocation is the name of the attribute containing the location
+	// of the event.
+	AttributeLocation string = "icsfile.location"
+
+	// dateFormat is the format of the DATE values.
+	dateFormat string = "20060102"
+	// localDateTimeFormat is the format of the floating and the TZID bound
+	// DATE-TIME values.
+	localDateTimeFormat string = "20060102T150405"
+
+	statusCancelled string = "CANCELLED"
+)
+
+var (
+	// ErrInvalidCalendar returns when the read file is not a valid iCalendar
+	// file.
+	ErrInvalidCalendar = errors.New("invalid calendar")
+
+	// remotePrefixes lists the prefixes of the paths read over HTTP.
+	remotePrefixes = []string{"http://", "https://", "webcal://"}
+)
+
+// property represents a content line of the calendar, like
+// "DTSTART;TZID=Europe/Budapest:20211002T090000".
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// event represents a parsed VEVENT component.
+type event struct {
+	uid          string
+	summary      string
+	description  string
+	location     string
+	url          string
+	status       string
+	categories   []string
+	start        time.Time
+	end          time.Time
+	duration     time.Duration
+	allDay       bool
+	rule         string
+	recurrenceID time.Time
+	exDates      []time.Time
+	exDays       map[string]bool
+	rDates       []time.Time
+}
+
+// unescape reverts the escaping of the text value as set by RFC 5545.
+func unescape(text string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(text)
+}
+
+// unfold joins the folded content lines of the calendar.
+func unfold(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\n ", "")
+	content = strings.ReplaceAll(content, "\n\t", "")
+
+	return strings.Split(content, "\n")
+}
+
+// splitUnquoted splits the text by the separator, except within double quotes.
+func splitUnquoted(text string, separator byte, limit int) []string {
+	var parts []string
+	quoted := false
+	partStart := 0
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '"':
+			quoted = !quoted
+		case text[i] == separator && !quoted && (limit <= 0 || len(parts) < limit-1):
+			parts = append(parts, text[partStart:i])
+			partStart = i + 1
+		}
+	}
+
+	return append(parts, text[partStart:])
+}
+
+// splitText splits the list of text values by the unescaped commas.
+func splitText(value string) []string {
+	var values []string
+	valueStart := 0
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			// The escaped character is skipped
+			i++
+		case ',':
+			values = append(values, value[valueStart:i])
+			valueStart = i + 1
+		}
+	}
+
+	return append(values, value[valueStart:])
+}
+
+// parseProperty parses the content line into a property.
+func parseProperty(line string) (*property, error) {
+	nameAndValue := splitUnquoted(line, ':', 2)
+	if len(nameAndValue) != 2 {
+		return nil, fmt.Errorf("%w: malformed line %q", ErrInvalidCalendar, line)
+	}
+
+	nameAndParams := splitUnquoted(nameAndValue[0], ';', 0)
+
+	prop := &property{
+		name:   strings.ToUpper(nameAndParams[0]),
+		params: map[string]string{},
+		value:  nameAndValue[1],
+	}
+
+	for _, param := range nameAndParams[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// location returns the location of the TZID. If the TZID is not a known IANA
+// time zone, like the Windows time zone names, the local time zone returns.
+func location(tzid string) *time.Location {
+	if tzid == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+	if err != nil {
+		return time.Local
+	}
+
+	return loc
+}
+
+// parseDateTime parses the DATE or DATE-TIME value. The UTC values end with
+// "Z", the others are parsed in the location of their TZID parameter, or in
+// the given location if the TZID is not set.
+func parseDateTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if tzid, ok := params["TZID"]; ok {
+		loc = location(tzid)
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		date, err := time.ParseInLocation(dateFormat, value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+		}
+
+		return date, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		loc = time.UTC
+	}
+
+	dateTime, err := time.ParseInLocation(localDateTimeFormat, strings.TrimSuffix(value, "Z"), loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+
+	return dateTime, false, nil
+}
+
+// parseDuration parses the DURATION value, like "PT1H30M" or "P1D". The
+// durations are treated as exact, hence a day is 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("%w: duration %q", ErrInvalidCalendar, value)
+	}
+
+	units := map[byte]time.Duration{
+		'W': time.Hour * 24 * 7,
+		'D': time.Hour * 24,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+
+	number := 0
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == 'T':
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+		case units[c] != 0:
+			duration += time.Duration(number) * units[c]
+			number = 0
+		default:
+			return 0, fmt.Errorf("%w: duration %q", ErrInvalidCalendar, value)
+		}
+	}
+
+	return sign * duration, nil
+}
+
+// startLocation returns the location of the event's start. If the start is
+// not parsed yet, the local time zone returns.
+func (e *event) startLocation() *time.Location {
+	if e.start.IsZero() {
+		return time.Local
+	}
+
+	return e.start.Location()
+}
+
+// isExcluded returns true if the occurrence is excluded by an EXDATE. The
+// DATE values exclude the occurrences of the whole day.
+func (e *event) isExcluded(start time.Time) bool {
+	for _, exDate := range e.exDates {
+		if exDate.Equal(start) {
+			return true
+		}
+	}
+
+	return e.exDays[start.In(e.startLocation()).Format(dateFormat)]
+}
+
+// setProperty sets the property of the event.
+func (e *event) setProperty(prop *property, durationValue *string) error {
+	var err error
+
+	switch prop.name {
+	case "UID":
+		e.uid = prop.value
+	case "SUMMARY":
+		e.summary = unescape(prop.value)
+	case "DESCRIPTION":
+		e.description = unescape(prop.value)
+	case "LOCATION":
+		e.location = unescape(prop.value)
+	case "URL":
+		e.url = prop.value
+	case "STATUS":
+		e.status = strings.ToUpper(prop.value)
+	case "CATEGORIES":
+		for _, category := range splitText(prop.value) {
+			if category = strings.TrimSpace(unescape(category)); category != "" {
+				e.categories = append(e.categories, category)
+			}
+		}
+	case "DTSTART":
+		e.start, e.allDay, err = parseDateTime(prop.value, prop.params, time.Local)
+	case "DTEND":
+		e.end, _, err = parseDateTime(prop.value, prop.params, time.Local)
+	case "DURATION":
+		*durationValue = prop.value
+	case "RRULE":
+		e.rule = prop.value
+	case "RECURRENCE-ID":
+		e.recurrenceID, _, err = parseDateTime(prop.value, prop.params, time.Local)
+	case "EXDATE", "RDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var dateTime time.Time
+			var isDate bool
+
+			// The floating values are in the time zone of the start
+			if dateTime, isDate, err = parseDateTime(value, prop.params, e.startLocation()); err != nil {
+				break
+			}
+
+			switch {
+			case prop.name == "RDATE":
+				e.rDates = append(e.rDates, dateTime)
+			case isDate:
+				if e.exDays == nil {
+					e.exDays = map[string]bool{}
+				}
+				e.exDays[dateTime.Format(dateFormat)] = true
+			default:
+				e.exDates = append(e.exDates, dateTime)
+			}
+		}
+	}
+
+	return err
+}
+
+// parseEvents returns the events of the calendar. The properties of the other
+// components, like the VTIMEZONE and VALARM components, are skipped.
+func parseEvents(content string) ([]event, error) {
+	var events []event
+	var current *event
+	var durationValue string
+	var nestedComponents int
+
+	for _, line := range unfold(content) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			current = &event{}
+			durationValue = ""
+		case current == nil:
+			continue
+		case prop.name == "BEGIN":
+			nestedComponents++
+		case prop.name == "END" && nestedComponents > 0:
+			nestedComponents--
+		case nestedComponents > 0:
+			continue
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current.start.IsZero() {
+				return nil, fmt.Errorf("%w: event %q has no start", ErrInvalidCalendar, current.uid)
+			}
+
+			switch {
+			case durationValue != "":
+				if current.duration, err = parseDuration(durationValue); err != nil {
+					return nil, err
+				}
+			case !current.end.IsZero():
+				current.duration = current.end.Sub(current.start)
+			case current.allDay:
+				current.duration = time.Hour * 24
+			}
+
+			events = append(events, *current)
+			current = nil
+		default:
+			if err = current.setProperty(prop, &durationValue); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// occurrenceID returns the ID of the event's occurrence. The occurrences of the
+// recurring events are identified by their UID and original start, hence the
+// modified occurrences are identified by their RECURRENCE-ID.
+func occurrenceID(e *event, start time.Time, recurring bool) string {
+	if !recurring {
+		return e.uid
+	}
+
+	if !e.recurrenceID.IsZero() {
+		start = e.recurrenceID
+	}
+
+	return e.uid + "/" + start.UTC().Format(dateTimeFormat)
+}
+
+// expandEvents returns the starts of the events' occurrences before the end.
+// The recurring events are expanded by their rules, while the occurrences
+// replaced by a modified occurrence or excluded by EXDATE are skipped.
+func expandEvents(events []event, end time.Time) (map[*event][]time.Time, error) {
+	overridden := map[string]bool{}
+	for i := range events {
+		if e := &events[i]; !e.recurrenceID.IsZero() {
+			overridden[occurrenceID(e, e.recurrenceID, true)] = true
+		}
+	}
+
+	occurrences := map[*event][]time.Time{}
+
+	for i := range events {
+		e := &events[i]
+
+		if e.rule == "" && len(e.rDates) == 0 {
+			occurrences[e] = []time.Time{e.start}
+			continue
+		}
+
+		starts := []time.Time{e.start}
+		if e.rule != "" {
+			rule, err := parseRecurrenceRule(e.rule, e.start.Location())
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", e.uid, err)
+			}
+
+			starts = rule.occurrences(e.start, end)
+		}
+
+		starts = append(starts, e.rDates...)
+
+		for _, start := range starts {
+			if e.isExcluded(start) || overridden[occurrenceID(e, start, true)] {
+				continue
+			}
+
+			occurrences[e] = append(occurrences[e], start)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// parseEntry converts the occurrence of the event into an entry.
+func parseEntry(e *event, start time.Time, recurring bool) worklog.Entry {
+	entry := worklog.Entry{
+		Summary:          e.summary,
+		Notes:            e.description,
+		Start:            start.Local(),
+		BillableDuration: e.duration,
+		Provenance:       worklog.Provenance{SourceIDs: []string{occurrenceID(e, start, recurring)}},
+	}
+
+	if e.url != "" {
+		entry.AddLinks(e.url)
+	}
+	entry.AddLinks(utils.ExtractURLs(e.description)...)
+
+	if e.location != "" {
+		entry.SetAttribute(AttributeLocation, e.location)
+	}
+
+	// The categories are the tags of the event
+	var tags []worklog.IDNameField
+
+	if len(e.categories) != 0 {
+		entry.SetAttribute(AttributeCategories, strings.Join(e.categories, ", "))
+	}
+
+	for _, category := range e.categories {
+		tags = append(tags, worklog.IDNameField{ID: category, Name: category})
+	}
+
+	entry.TaskHints = &worklog.TaskHints{
+		Texts: []string{e.summary},
+		Tags:  tags,
+	}
+
+	return entry
+}
+
+// isRemote returns true if the path is a URL of a calendar.
+func isRemote(path string) bool {
+	for _, prefix := range remotePrefixes {
+		if strings.HasPrefix(strings.ToLower(path), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readCalendar returns the content of the local or remote calendar. The
+// webcal URLs are requested over HTTPS.
+func (c *icsClient) readCalendar(ctx context.Context) (string, error) {
+	if !isRemote(c.opts.Path) {
+		content, err := os.ReadFile(c.opts.Path)
+		return string(content), err
+	}
+
+	calendarURL := c.opts.Path
+	if strings.HasPrefix(strings.ToLower(calendarURL), "webcal://") {
+		calendarURL = "https://" + calendarURL[len("webcal://"):]
+	}
+
+	content, err := c.httpClient.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     calendarURL,
+		Timeout: c.Timeout,
+	})
+
+	return string(content), err
+}
+
+func (c *icsClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	content, err := c.readCalendar(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	events, err := parseEvents(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	occurrences, err := expandEvents(events, opts.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var entries worklog.Entries
+
+	for i := range events {
+		e := &events[i]
+
+		// The cancelled and all-day events do not stand for time spent
+		if e.status == statusCancelled || e.allDay {
+			continue
+		}
+
+		recurring := e.rule != "" || len(e.rDates) != 0 || !e.recurrenceID.IsZero()
+
+		for _, start := range occurrences[e] {
+			if opts.Contains(start) {
+				entries = append(entries, parseEntry(e, start, recurring))
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// NewFetcher returns a new ICS file client for reading entries. The Path may
+// be the URL of a published calendar too, like "https://" or "webcal://" URLs.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no ICS file path provided")
+	}
+
+	clientOpts := *opts
+
+	return &icsClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+		httpClient:     &client.HTTPClient{},
+		now:            time.Now,
+	}, nil
+}
@@ -0,0 +1,208 @@
+package icsfile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/calendar"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// ProductID identifies the application generating the calendar.
+	ProductID string = "-//gabor-boros//minutes//EN"
+	// UIDDomain is the domain part of the event UIDs.
+	UIDDomain string = "minutes"
+
+	// dateTimeFormat is the UTC date-time format of iCalendar.
+	dateTimeFormat string = "20060102T150405Z"
+	// maxLineLength is the maximum length of the content lines in octets,
+	// excluding the line break.
+	maxLineLength int = 75
+)
+
+// Colors lists the CSS color names used to color-code the events by project.
+var Colors = []string{
+	"steelblue",
+	"seagreen",
+	"darkorange",
+	"orchid",
+	"goldenrod",
+	"tomato",
+	"teal",
+	"slateblue",
+	"olivedrab",
+	"indianred",
+	"cadetblue",
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// The timeout is used only by reading remote calendars.
+type ClientOpts struct {
+	client.BaseClientOpts
+	// Path is the path of the read or written ICS file. The written file is
+	// overwritten if it exists. The read file may be the URL of a published
+	// calendar.
+	Path string
+}
+
+type icsClient struct {
+	*client.BaseClientOpts
+	client.DefaultUploader
+	opts       ClientOpts
+	httpClient *client.HTTPClient
+	now        func() time.Time
+}
+
+// escape escapes the text value as set by RFC 5545.
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// fold splits the content line into lines of at most maxLineLength octets. The
+// continuation lines start with a space. Multi-byte characters are not split.
+func fold(line string) string {
+	var folded strings.Builder
+
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+
+		// The leading space of the continuation line counts to its length
+		limit = maxLineLength - 1
+	}
+
+	folded.WriteString(line)
+	folded.WriteString("\r\n")
+
+	return folded.String()
+}
+
+// isRuneStart returns true if the byte is not a continuation byte of a UTF-8
+// encoded character.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// eventUID returns the UID of the entry's event. The UID is derived from the
+// entry, so importing the file again updates the events instead of
+// duplicating them.
+func eventUID(entry *worklog.Entry) string {
+	hash := sha256.Sum256([]byte(entry.Key() + "|" + entry.Start.UTC().Format(dateTimeFormat)))
+	return hex.EncodeToString(hash[:16]) + "@" + UIDDomain
+}
+
+// writeCalendar writes the calendar containing an event per entry.
+func (c *icsClient) writeCalendar(w io.Writer, entries worklog.Entries) error {
+	var lines []string
+
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:"+ProductID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	)
+
+	stamp := c.now().UTC().Format(dateTimeFormat)
+
+	for i := range entries {
+		entry := &entries[i]
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+eventUID(entry),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+entry.Start.UTC().Format(dateTimeFormat),
+			"DTEND:"+calendar.EventEnd(entry).UTC().Format(dateTimeFormat),
+			"SUMMARY:"+escape(calendar.EventTitle(entry)),
+			"DESCRIPTION:"+escape(calendar.EventDescription(entry)),
+			// The events are records of the past, so they must not block the
+			// user's availability
+			"TRANSP:TRANSPARENT",
+		)
+
+		if project := entry.Project.Name; project != "" {
+			lines = append(lines,
+				"CATEGORIES:"+escape(project),
+				"COLOR:"+Colors[calendar.ProjectColor(project, len(Colors))],
+			)
+		}
+
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *icsClient) UploadEntries(_ context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	err := func() error {
+		file, err := os.Create(c.opts.Path)
+		if err != nil {
+			return err
+		}
+
+		if err = c.writeCalendar(file, entries); err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+	}()
+
+	if err != nil {
+		err = fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+	}
+
+	// The entries are written at once, hence they succeed or fail together
+	for _, entry := range entries {
+		tracker := c.StartTracking(entry, opts.ProgressWriter)
+		c.StopTracking(tracker, err)
+		errChan <- err
+	}
+}
+
+// NewUploader returns a new ICS file client for writing entries as calendar
+// events.
+func NewUploader(opts *ClientOpts) (client.Uploader, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no ICS file path provided")
+	}
+
+	clientOpts := *opts
+
+	return &icsClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+		now:            time.Now,
+	}, nil
+}
@@ -0,0 +1,274 @@
+package icsfile_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/icsfile"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func uploadEntries(t *testing.T, path string, entries worklog.Entries) string {
+	uploader, err := icsfile.NewUploader(&icsfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	errChan := make(chan error)
+	go uploader.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{})
+
+	for range entries {
+		require.Nil(t, <-errChan)
+	}
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+
+	return string(content)
+}
+
+func TestICSClient_UploadEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.ics")
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+
+	entries := worklog.Entries{
+		{
+			Client:           worklog.IDNameField{ID: "My Awesome Company", Name: "My Awesome Company"},
+			Project:          worklog.IDNameField{ID: "CPT", Name: "CPT"},
+			Task:             worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"},
+			Summary:          "Fix the flaky test, again; for real",
+			Start:            start,
+			BillableDuration: time.Hour,
+		},
+		{
+			Task:               worklog.IDNameField{ID: "Standup", Name: "Standup"},
+			Start:              start.Add(time.Hour),
+			UnbillableDuration: time.Minute * 15,
+		},
+	}
+
+	content := uploadEntries(t, path, entries)
+	unfolded := strings.ReplaceAll(content, "\r\n ", "")
+
+	require.True(t, strings.HasPrefix(content, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(content, "END:VCALENDAR\r\n"))
+	require.Equal(t, 2, strings.Count(content, "BEGIN:VEVENT\r\n"))
+
+	require.Contains(t, content, "DTSTART:20211002T090000Z\r\n")
+	require.Contains(t, content, "DTEND:20211002T100000Z\r\n")
+	require.Contains(t, content, `SUMMARY:CPT-2014 Fix the flaky test\, again\; for real`+"\r\n")
+	require.Contains(t, unfolded, `DESCRIPTION:Project: CPT\nClient: My Awesome Company\nBillable: 1h0m0s\nUnbillable: 0s`+"\r\n")
+	require.Contains(t, unfolded, `DESCRIPTION:Billable: 0s\nUnbillable: 15m0s`+"\r\n")
+	require.Contains(t, content, "CATEGORIES:CPT\r\n")
+	require.Contains(t, content, "DTEND:20211002T101500Z\r\n")
+
+	// The entry without project has no color
+	require.Equal(t, 1, strings.Count(content, "COLOR:"))
+
+	// The content lines are folded
+	for _, line := range strings.Split(content, "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+	}
+
+	// The UIDs are stable across exports
+	require.Equal(t, content[:strings.Index(content, "DTSTAMP")], uploadEntries(t, path, entries)[:strings.Index(content, "DTSTAMP")])
+}
+
+func TestNewUploader_NoPath(t *testing.T) {
+	_, err := icsfile.NewUploader(&icsfile.ClientOpts{})
+	require.NotNil(t, err)
+}
+
+const calendarContent = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//Calendar//EN
+BEGIN:VTIMEZONE
+TZID:Europe/Budapest
+BEGIN:STANDARD
+DTSTART:19701025T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:review
+DTSTART;TZID=Europe/Budapest:20211004T090000
+DTEND;TZID=Europe/Budapest:20211004T103000
+SUMMARY:CPT-123 Review the flaky test\, again
+DESCRIPTION:Notes: https://example.com/notes\nBring coffee
+CATEGORIES:ACME,Globex\, Inc.
+LOCATION:Office
+URL:https://example.com/event
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+DTSTART:20210927T080000Z
+DURATION:PT15M
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20211008T235959Z
+EXDATE:20211006T080000Z
+SUMMARY:Standup
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID:20211004T080000Z
+DTSTART:20211004T083000Z
+DURATION:PT20M
+SUMMARY:Standup (moved)
+END:VEVENT
+BEGIN:VEVENT
+UID:retro
+DTSTART:20210730T150000Z
+DTEND:20210730T160000Z
+RRULE:FREQ=MONTHLY;BYDAY=-1FR
+SUMMARY:Retro
+END:VEVENT
+BEGIN:VEVENT
+UID:pairing
+DTSTART:20211010T130000Z
+DTEND:20211010T150000Z
+RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3
+SUMMARY:Pairing
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+DTSTART:20211005T100000Z
+DTEND:20211005T110000Z
+STATUS:CANCELLED
+SUMMARY:Cancelled meeting
+END:VEVENT
+BEGIN:VEVENT
+UID:conference
+DTSTART;VALUE=DATE:20211007
+DTEND;VALUE=DATE:20211008
+SUMMARY:Conference
+END:VEVENT
+END:VCALENDAR
+`
+
+func fetchEntries(t *testing.T, path string, opts *client.FetchOpts) worklog.Entries {
+	fetcher, err := icsfile.NewFetcher(&icsfile.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		Path: path,
+	})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), opts)
+	require.Nil(t, err, "cannot fetch entries")
+
+	return entries
+}
+
+func writeCalendar(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "calendar.ics")
+	require.Nil(t, os.WriteFile(path, []byte(strings.ReplaceAll(content, "\n", "\r\n")), 0o600))
+
+	return path
+}
+
+func TestICSClient_FetchEntries(t *testing.T) {
+	path := writeCalendar(t, calendarContent)
+
+	entries := fetchEntries(t, path, &client.FetchOpts{
+		Start: time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	var occurrences []string
+	for _, entry := range entries {
+		occurrences = append(occurrences, entry.Summary+" "+entry.Start.UTC().Format(time.RFC3339)+" "+entry.BillableDuration.String())
+	}
+
+	require.Equal(t, []string{
+		"CPT-123 Review the flaky test, again 2021-10-04T07:00:00Z 1h30m0s",
+		"Standup 2021-10-08T08:00:00Z 15m0s",
+		"Standup (moved) 2021-10-04T08:30:00Z 20m0s",
+		"Retro 2021-10-29T15:00:00Z 1h0m0s",
+		"Pairing 2021-10-10T13:00:00Z 2h0m0s",
+		"Pairing 2021-10-12T13:00:00Z 2h0m0s",
+		"Pairing 2021-10-14T13:00:00Z 2h0m0s",
+	}, occurrences)
+
+	review := entries[0]
+	require.Equal(t, "Notes: https://example.com/notes\nBring coffee", review.Notes)
+	require.Equal(t, []string{"https://example.com/event", "https://example.com/notes"}, review.Links)
+	require.Equal(t, "ACME, Globex, Inc.", review.Attributes[icsfile.AttributeCategories])
+	require.Equal(t, "Office", review.Attributes[icsfile.AttributeLocation])
+	require.Equal(t, []string{"review"}, review.Provenance.SourceIDs)
+
+	require.Equal(t, []string{"standup/20211008T080000Z"}, entries[1].Provenance.SourceIDs)
+	require.Equal(t, []string{"standup/20211004T080000Z"}, entries[2].Provenance.SourceIDs)
+}
+
+func TestICSClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	path := writeCalendar(t, calendarContent)
+
+	entries := fetchEntries(t, path, &client.FetchOpts{
+		Start: time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 5, 0, 0, 0, 0, time.UTC),
+	})
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^(ACME|Globex)`), worklog.TaskExtractionTags)
+
+	require.Len(t, entries, 3)
+	require.Equal(t, "ACME", entries[0].Task.Name)
+	require.Equal(t, time.Minute*45, entries[0].BillableDuration)
+	require.Equal(t, "Globex, Inc.", entries[1].Task.Name)
+	require.Equal(t, "", entries[2].Task.Name)
+}
+
+func TestICSClient_FetchEntries_Remote(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/calendar.ics", r.URL.Path)
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(calendarContent))
+	}))
+	defer mockServer.Close()
+
+	entries := fetchEntries(t, mockServer.URL+"/calendar.ics", &client.FetchOpts{
+		Start: time.Date(2021, 10, 29, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 30, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.Len(t, entries, 1)
+	require.Equal(t, "Retro", entries[0].Summary)
+}
+
+func TestICSClient_FetchEntries_UnsupportedRecurrence(t *testing.T) {
+	path := writeCalendar(t, `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:hourly
+DTSTART:20211004T080000Z
+RRULE:FREQ=HOURLY
+END:VEVENT
+END:VCALENDAR
+`)
+
+	fetcher, err := icsfile.NewFetcher(&icsfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	_, err = fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 5, 0, 0, 0, 0, time.UTC),
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.True(t, errors.Is(err, icsfile.ErrUnsupportedRecurrence))
+}
+
+func TestNewFetcher_NoPath(t *testing.T) {
+	_, err := icsfile.NewFetcher(&icsfile.ClientOpts{})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,390 @@
+package icsfile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	frequencyDaily   string = "DAILY"
+	frequencyWeekly  string = "WEEKLY"
+	frequencyMonthly string = "MONTHLY"
+	frequencyYearly  string = "YEARLY"
+)
+
+var (
+	// ErrUnsupportedRecurrence returns when the recurrence rule of an event
+	// uses a frequency or rule part which cannot be expanded.
+	ErrUnsupportedRecurrence = errors.New("unsupported recurrence rule")
+
+	weekdays = map[string]time.Weekday{
+		"SU": time.Sunday,
+		"MO": time.Monday,
+		"TU": time.Tuesday,
+		"WE": time.Wednesday,
+		"TH": time.Thursday,
+		"FR": time.Friday,
+		"SA": time.Saturday,
+	}
+)
+
+// weekdayNum represents a BYDAY value, like "MO" or "-1FR". The zero ordinal
+// stands for every weekday of the period.
+type weekdayNum struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// recurrenceRule represents the supported parts of an RRULE as set by RFC
+// 5545. The BYSETPOS, BYWEEKNO, BYYEARDAY and sub-daily parts are not
+// supported.
+type recurrenceRule struct {
+	frequency  string
+	interval   int
+	count      int
+	until      time.Time
+	weekStart  time.Weekday
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+}
+
+// parseInts parses the comma separated integers of the rule part.
+func parseInts(value string) ([]int, error) {
+	var numbers []int
+
+	for _, rawNumber := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(rawNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+// parseWeekdayNum parses a BYDAY value, like "MO", "2TU" or "-1FR".
+func parseWeekdayNum(value string) (weekdayNum, error) {
+	if len(value) < 2 {
+		return weekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrUnsupportedRecurrence, value)
+	}
+
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return weekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrUnsupportedRecurrence, value)
+	}
+
+	var ordinal int
+	if rawOrdinal := value[:len(value)-2]; rawOrdinal != "" {
+		var err error
+		if ordinal, err = strconv.Atoi(rawOrdinal); err != nil {
+			return weekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrUnsupportedRecurrence, value)
+		}
+	}
+
+	return weekdayNum{ordinal: ordinal, weekday: weekday}, nil
+}
+
+// parseRecurrenceRule parses the value of the RRULE property. The UNTIL of
+// the rule is parsed in the location of the event's start.
+func parseRecurrenceRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1, weekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		key, partValue, _ := strings.Cut(part, "=")
+
+		var err error
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.frequency = strings.ToUpper(partValue)
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(partValue)
+		case "COUNT":
+			rule.count, err = strconv.Atoi(partValue)
+		case "UNTIL":
+			var allDay bool
+			rule.until, allDay, err = parseDateTime(partValue, map[string]string{}, loc)
+			// The date of the UNTIL is inclusive
+			if allDay {
+				rule.until = rule.until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "WKST":
+			var ok bool
+			if rule.weekStart, ok = weekdays[strings.ToUpper(partValue)]; !ok {
+				err = fmt.Errorf("%w: WKST=%s", ErrUnsupportedRecurrence, partValue)
+			}
+		case "BYDAY":
+			for _, rawDay := range strings.Split(strings.ToUpper(partValue), ",") {
+				var day weekdayNum
+				if day, err = parseWeekdayNum(rawDay); err != nil {
+					break
+				}
+
+				rule.byDay = append(rule.byDay, day)
+			}
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseInts(partValue)
+		case "BYMONTH":
+			rule.byMonth, err = parseInts(partValue)
+		case "":
+			// Trailing separators are tolerated
+		default:
+			err = fmt.Errorf("%w: %s", ErrUnsupportedRecurrence, key)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch rule.frequency {
+	case frequencyDaily, frequencyWeekly, frequencyMonthly:
+	case frequencyYearly:
+		// Without months, the ordinals would count the weekdays of the year
+		for _, day := range rule.byDay {
+			if day.ordinal != 0 && len(rule.byMonth) == 0 {
+				return nil, fmt.Errorf("%w: BYDAY ordinal without BYMONTH", ErrUnsupportedRecurrence)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: FREQ=%s", ErrUnsupportedRecurrence, rule.frequency)
+	}
+
+	if rule.interval <= 0 {
+		return nil, fmt.Errorf("%w: INTERVAL=%d", ErrUnsupportedRecurrence, rule.interval)
+	}
+
+	return rule, nil
+}
+
+// containsInt returns true if the numbers contain the number.
+func containsInt(numbers []int, number int) bool {
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+// daysIn returns the number of days of the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchesWeekday returns true if the date matches any BYDAY value, ignoring
+// their ordinals.
+func (r *recurrenceRule) matchesWeekday(date time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+
+	for _, day := range r.byDay {
+		if day.weekday == date.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// monthDays returns the sorted days of the month matching the BYMONTHDAY and
+// BYDAY parts. If none of them is set, the day of the event's start returns.
+func (r *recurrenceRule) monthDays(year int, month time.Month, startDay int) []int {
+	lastDay := daysIn(year, month)
+
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		if startDay > lastDay {
+			return nil
+		}
+
+		return []int{startDay}
+	}
+
+	matches := map[int]bool{}
+
+	if len(r.byDay) != 0 {
+		for _, day := range r.byDay {
+			var days []int
+			for d := 1; d <= lastDay; d++ {
+				if time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday() == day.weekday {
+					days = append(days, d)
+				}
+			}
+
+			switch {
+			case day.ordinal == 0:
+				for _, d := range days {
+					matches[d] = true
+				}
+			case day.ordinal > 0 && day.ordinal <= len(days):
+				matches[days[day.ordinal-1]] = true
+			case day.ordinal < 0 && -day.ordinal <= len(days):
+				matches[days[len(days)+day.ordinal]] = true
+			}
+		}
+	}
+
+	if len(r.byMonthDay) != 0 {
+		monthDays := map[int]bool{}
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d = lastDay + d + 1
+			}
+
+			if d >= 1 && d <= lastDay {
+				monthDays[d] = true
+			}
+		}
+
+		// Having both parts set, the days must match both of them
+		if len(r.byDay) != 0 {
+			for d := range matches {
+				if !monthDays[d] {
+					delete(matches, d)
+				}
+			}
+		} else {
+			matches = monthDays
+		}
+	}
+
+	days := make([]int, 0, len(matches))
+	for d := range matches {
+		days = append(days, d)
+	}
+	sort.Ints(days)
+
+	return days
+}
+
+// periodCandidates returns the candidate dates of the nth period of the rule,
+// in order. The candidates may precede the start of the event.
+func (r *recurrenceRule) periodCandidates(start time.Time, n int) []time.Time {
+	year, month, day := start.Date()
+	hour, minute, second := start.Clock()
+	loc := start.Location()
+
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, second, 0, loc)
+	}
+
+	var candidates []time.Time
+
+	switch r.frequency {
+	case frequencyDaily:
+		candidate := at(year, month, day+n*r.interval)
+		if (len(r.byMonth) == 0 || containsInt(r.byMonth, int(candidate.Month()))) &&
+			(len(r.byMonthDay) == 0 || containsInt(r.byMonthDay, candidate.Day())) &&
+			r.matchesWeekday(candidate) {
+			candidates = append(candidates, candidate)
+		}
+	case frequencyWeekly:
+		weekStart := day - (int(start.Weekday())-int(r.weekStart)+7)%7 + n*r.interval*7
+
+		byDay := r.byDay
+		if len(byDay) == 0 {
+			byDay = []weekdayNum{{weekday: start.Weekday()}}
+		}
+
+		for _, weekday := range byDay {
+			candidate := at(year, month, weekStart+(int(weekday.weekday)-int(r.weekStart)+7)%7)
+			if len(r.byMonth) == 0 || containsInt(r.byMonth, int(candidate.Month())) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Before(candidates[j])
+		})
+	case frequencyMonthly:
+		periodStart := time.Date(year, month+time.Month(n*r.interval), 1, 0, 0, 0, 0, loc)
+		if len(r.byMonth) != 0 && !containsInt(r.byMonth, int(periodStart.Month())) {
+			break
+		}
+
+		for _, d := range r.monthDays(periodStart.Year(), periodStart.Month(), day) {
+			candidates = append(candidates, at(periodStart.Year(), periodStart.Month(), d))
+		}
+	case frequencyYearly:
+		periodYear := year + n*r.interval
+
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(month)}
+		}
+		sort.Ints(months)
+
+		for _, m := range months {
+			for _, d := range r.monthDays(periodYear, time.Month(m), day) {
+				candidates = append(candidates, at(periodYear, time.Month(m), d))
+			}
+		}
+	}
+
+	return candidates
+}
+
+// periodStart returns the earliest time the nth period of the rule may
+// contain, used to stop the expansion.
+func (r *recurrenceRule) periodStart(start time.Time, n int) time.Time {
+	year, month, day := start.Date()
+	loc := start.Location()
+
+	switch r.frequency {
+	case frequencyWeekly:
+		return time.Date(year, month, day-6+n*r.interval*7, 0, 0, 0, 0, loc)
+	case frequencyMonthly:
+		return time.Date(year, month+time.Month(n*r.interval), 1, 0, 0, 0, 0, loc)
+	case frequencyYearly:
+		return time.Date(year+n*r.interval, time.January, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(year, month, day+n*r.interval, 0, 0, 0, 0, loc)
+	}
+}
+
+// occurrences returns the starts of the occurrences before the end, in order.
+// As set by RFC 5545, the start of the event is the first occurrence, even if
+// it does not match the rule. The COUNT of the rule counts the occurrences
+// preceding the fetched period too.
+func (r *recurrenceRule) occurrences(start time.Time, end time.Time) []time.Time {
+	if !start.Before(end) {
+		return nil
+	}
+
+	occurrences := []time.Time{start}
+	count := 1
+
+	if r.count == 1 {
+		return occurrences
+	}
+
+	for n := 0; r.periodStart(start, n).Before(end); n++ {
+		for _, candidate := range r.periodCandidates(start, n) {
+			if !candidate.After(start) {
+				continue
+			}
+
+			if !candidate.Before(end) || (!r.until.IsZero() && candidate.After(r.until)) {
+				return occurrences
+			}
+
+			occurrences = append(occurrences, candidate)
+
+			count++
+			if r.count != 0 && count == r.count {
+				return occurrences
+			}
+		}
+	}
+
+	return occurrences
+}
@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"context"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+// ClassificationRule classifies the entries of the matching issues, like
+// "CAPEX" or "OPEX". Empty Project, Epic or Label matches every issue.
+type ClassificationRule struct {
+	// Project is the key of the issue's project.
+	Project string `mapstructure:"project"`
+	// Epic is the key of the issue's epic.
+	Epic string `mapstructure:"epic"`
+	// Label is one of the issue's labels.
+	Label string `mapstructure:"label"`
+	// Class is the classification of the matching entries.
+	Class string `mapstructure:"class"`
+}
+
+// Matches returns true if the rule applies for the issue of the given
+// project, epic and labels.
+func (r *ClassificationRule) Matches(project string, epic string, labels []string) bool {
+	if (r.Project != "" && r.Project != project) || (r.Epic != "" && r.Epic != epic) {
+		return false
+	}
+
+	if r.Label == "" {
+		return true
+	}
+
+	for _, label := range labels {
+		if label == r.Label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Classify sets the class of the first matching rule as the classification
+// attribute of the entries. If no rule matches or the issue does not exist, the
+// default class is set, unless it is empty. The task name of the entries must be the issue key.
+func (c *Client) Classify(ctx context.Context, entries worklog.Entries, rules []ClassificationRule, defaultClass string) (worklog.Entries, error) {
+	classes := map[string]string{}
+	classifiedEntries := make(worklog.Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		issueKey := entry.Task.Name
+		if issueKey == "" {
+			classifiedEntries = append(classifiedEntries, entry)
+			continue
+		}
+
+		class, isClassified := classes[issueKey]
+		if !isClassified {
+			var err error
+
+			if class, err = c.classifyIssue(ctx, issueKey, rules, defaultClass); err != nil {
+				return nil, err
+			}
+
+			classes[issueKey] = class
+		}
+
+		if class != "" {
+			entry.SetAttribute(worklog.AttributeClassification, class)
+			entry.AddTransformation("classified as %s", class)
+		}
+
+		classifiedEntries = append(classifiedEntries, entry)
+	}
+
+	return classifiedEntries, nil
+}
+
+func (c *Client) classifyIssue(ctx context.Context, issueKey string, rules []ClassificationRule, defaultClass string) (string, error) {
+	issue, err := c.GetIssue(ctx, issueKey)
+	if client.KindOf(err) == client.ErrorKindNotFound {
+		return defaultClass, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	epic, err := c.GetEpic(ctx, issueKey)
+	if err != nil {
+		return "", err
+	}
+
+	epicKey := ""
+	if epic != nil {
+		epicKey = epic.Key
+	}
+
+	for i := range rules {
+		if rules[i].Matches(issue.Fields.Project.Key, epicKey, issue.Fields.Labels) {
+			return rules[i].Class, nil
+		}
+	}
+
+	return defaultClass, nil
+}
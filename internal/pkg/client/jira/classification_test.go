@@ -0,0 +1,115 @@
+package jira_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jira"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassificationRule_Matches(t *testing.T) {
+	tests := map[string]struct {
+		rule     jira.ClassificationRule
+		expected bool
+	}{
+		"empty rule": {
+			rule:     jira.ClassificationRule{},
+			expected: true,
+		},
+		"matching project": {
+			rule:     jira.ClassificationRule{Project: "DEV"},
+			expected: true,
+		},
+		"not matching project": {
+			rule:     jira.ClassificationRule{Project: "OPS"},
+			expected: false,
+		},
+		"matching epic and label": {
+			rule:     jira.ClassificationRule{Epic: "DEV-1", Label: "feature"},
+			expected: true,
+		},
+		"not matching epic": {
+			rule:     jira.ClassificationRule{Epic: "DEV-2", Label: "feature"},
+			expected: false,
+		},
+		"not matching label": {
+			rule:     jira.ClassificationRule{Label: "bug"},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.rule.Matches("DEV", "DEV-1", []string{"backend", "feature"}))
+		})
+	}
+}
+
+func TestClient_Classify(t *testing.T) {
+	calls := map[string]int{}
+	server := newMockServer(t, map[string]string{
+		"/rest/api/2/issue/DEV-1": `{"key": "DEV-1", "fields": {"project": {"key": "DEV"}, "issuetype": {"hierarchyLevel": 1}}}`,
+		"/rest/api/2/issue/DEV-2": `{"key": "DEV-2", "fields": {"project": {"key": "DEV"}, "labels": ["maintenance"], "parent": {"key": "DEV-1"}}}`,
+		"/rest/api/2/issue/DEV-3": `{"key": "DEV-3", "fields": {"project": {"key": "DEV"}, "parent": {"key": "DEV-1"}}}`,
+		"/rest/api/2/issue/OPS-1": `{"key": "OPS-1", "fields": {"project": {"key": "OPS"}}}`,
+	}, calls)
+
+	entries := worklog.Entries{
+		{Task: worklog.IDNameField{Name: "DEV-2"}, Summary: "Upgrade dependencies"},
+		{Task: worklog.IDNameField{Name: "DEV-3"}, Summary: "Develop feature"},
+		{Task: worklog.IDNameField{Name: "DEV-3"}, Summary: "Review feature"},
+		{Task: worklog.IDNameField{Name: "OPS-1"}, Summary: "Restart servers"},
+		{Summary: "No task"},
+	}
+
+	rules := []jira.ClassificationRule{
+		{Label: "maintenance", Class: "OPEX"},
+		{Epic: "DEV-1", Class: "CAPEX"},
+	}
+
+	classifiedEntries, err := newClient(t, server.URL).Classify(context.Background(), entries, rules, "")
+	require.Nil(t, err)
+	require.Len(t, classifiedEntries, 5)
+
+	var classes []string
+	for _, entry := range classifiedEntries {
+		classes = append(classes, entry.Attributes[worklog.AttributeClassification])
+	}
+
+	require.Equal(t, []string{"OPEX", "CAPEX", "CAPEX", "", ""}, classes)
+	require.Nil(t, classifiedEntries[3].Attributes)
+	require.Equal(t, 1, calls["/rest/api/2/issue/DEV-3"])
+
+	classifiedEntries, err = newClient(t, server.URL).Classify(context.Background(), entries, rules, "OPEX")
+	require.Nil(t, err)
+	require.Equal(t, "OPEX", classifiedEntries[3].Attributes[worklog.AttributeClassification])
+	require.Nil(t, classifiedEntries[4].Attributes)
+}
+
+func TestClient_Classify_NotFound(t *testing.T) {
+	server := newMockServer(t, map[string]string{}, map[string]int{})
+
+	classifiedEntries, err := newClient(t, server.URL).Classify(context.Background(), worklog.Entries{
+		{Task: worklog.IDNameField{Name: "DEV-1"}},
+	}, []jira.ClassificationRule{}, "OPEX")
+	require.Nil(t, err)
+	require.Equal(t, "OPEX", classifiedEntries[0].Attributes[worklog.AttributeClassification])
+}
+
+func TestClient_Classify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := newClient(t, server.URL).Classify(context.Background(), worklog.Entries{
+		{Task: worklog.IDNameField{Name: "DEV-1"}},
+	}, []jira.ClassificationRule{}, "")
+	require.ErrorContains(t, err, "401")
+	require.Equal(t, client.ErrorKindAuth, client.KindOf(err))
+}
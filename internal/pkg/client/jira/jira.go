@@ -0,0 +1,387 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathIssue is the Jira endpoint used to get the details of an issue.
+	PathIssue string = "/rest/api/2/issue/%s"
+	// PathServiceDeskRequest is the Jira Service Management endpoint used to
+	// get the customer request of an issue.
+	PathServiceDeskRequest string = "/rest/servicedeskapi/request/%s"
+	// PathServiceDeskRequestSLA is the Jira Service Management endpoint used
+	// to get the SLA information of a customer request.
+	PathServiceDeskRequestSLA string = "/rest/servicedeskapi/request/%s/sla"
+
+	// AttributeRequestType is the entry attribute of the request type name.
+	AttributeRequestType string = "jsm.request_type"
+	// AttributeRequestStatus is the entry attribute of the request status.
+	AttributeRequestStatus string = "jsm.status"
+	// AttributeSLAPrefix is the prefix of the SLA entry attributes, followed
+	// by the SLA name in snake case, like "jsm.sla.time_to_resolution".
+	AttributeSLAPrefix string = "jsm.sla."
+
+	// SLAStatusMet is the status of a completed SLA cycle within the goal.
+	SLAStatusMet string = "met"
+	// SLAStatusBreached is the status of an SLA cycle over the goal.
+	SLAStatusBreached string = "breached"
+	// SLAStatusPaused is the status of a paused ongoing SLA cycle.
+	SLAStatusPaused string = "paused"
+	// SLAStatusOngoing is the status of an ongoing SLA cycle within the goal.
+	SLAStatusOngoing string = "ongoing"
+
+	// AttributeRolledUpIssue is the entry attribute of the issue key the entry
+	// was logged against before rolling it up to an ancestor issue.
+	AttributeRolledUpIssue string = "jira.rolled_up_issue"
+
+	// HierarchyLevelEpic is the hierarchy level of epic issue types.
+	HierarchyLevelEpic int = 1
+
+	issueFields string = "summary,project,labels,issuetype,parent"
+	// maxParentDepth limits the number of parents looked up for an issue, to
+	// protect against cyclic hierarchies.
+	maxParentDepth int = 10
+)
+
+var (
+	// ErrNotServiceDeskRequest returns when the issue is not a customer
+	// request of Jira Service Management.
+	ErrNotServiceDeskRequest = errors.New("issue is not a service desk request")
+
+	nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Duration represents the durations returned by Jira Service Management.
+type Duration struct {
+	Millis   int64  `json:"millis"`
+	Friendly string `json:"friendly"`
+}
+
+// SLACycle represents an ongoing or completed cycle of an SLA.
+type SLACycle struct {
+	Breached      bool     `json:"breached"`
+	Paused        bool     `json:"paused"`
+	GoalDuration  Duration `json:"goalDuration"`
+	ElapsedTime   Duration `json:"elapsedTime"`
+	RemainingTime Duration `json:"remainingTime"`
+}
+
+// SLA represents an SLA of a customer request, like "Time to resolution".
+type SLA struct {
+	Name            string     `json:"name"`
+	OngoingCycle    *SLACycle  `json:"ongoingCycle"`
+	CompletedCycles []SLACycle `json:"completedCycles"`
+}
+
+// Status returns the status of the SLA, based on the ongoing cycle if any,
+// otherwise the last completed cycle. The remaining time is appended to the
+// status of ongoing cycles, like "ongoing, 2h remaining".
+func (s *SLA) Status() string {
+	if cycle := s.OngoingCycle; cycle != nil {
+		switch {
+		case cycle.Breached:
+			return SLAStatusBreached
+		case cycle.Paused:
+			return SLAStatusPaused
+		case cycle.RemainingTime.Friendly != "":
+			return fmt.Sprintf("%s, %s remaining", SLAStatusOngoing, cycle.RemainingTime.Friendly)
+		default:
+			return SLAStatusOngoing
+		}
+	}
+
+	if len(s.CompletedCycles) == 0 {
+		return ""
+	}
+
+	if s.CompletedCycles[len(s.CompletedCycles)-1].Breached {
+		return SLAStatusBreached
+	}
+
+	return SLAStatusMet
+}
+
+// AttributeName returns the entry attribute name of the SLA.
+func (s *SLA) AttributeName() string {
+	return AttributeSLAPrefix + strings.Trim(nonAlphanumericRegex.ReplaceAllString(strings.ToLower(s.Name), "_"), "_")
+}
+
+// SLAResponse represents the paginated SLA response.
+type SLAResponse struct {
+	Values     []SLA `json:"values"`
+	IsLastPage bool  `json:"isLastPage"`
+}
+
+// ServiceDeskRequest represents the customer request of an issue.
+type ServiceDeskRequest struct {
+	IssueKey    string `json:"issueKey"`
+	RequestType struct {
+		Name string `json:"name"`
+	} `json:"requestType"`
+	CurrentStatus struct {
+		Status string `json:"status"`
+	} `json:"currentStatus"`
+	SLAs []SLA `json:"-"`
+}
+
+// IssueType represents the type of an issue. The hierarchy level is -1 for
+// sub-tasks, 0 for standard issues and 1 for epics.
+type IssueType struct {
+	Name           string `json:"name"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+// IssueParent represents the parent of an issue.
+type IssueParent struct {
+	Key string `json:"key"`
+}
+
+// IssueFields represents the fields of an issue used by minutes.
+type IssueFields struct {
+	Summary string `json:"summary"`
+	Project struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	} `json:"project"`
+	Labels    []string     `json:"labels"`
+	IssueType IssueType    `json:"issuetype"`
+	Parent    *IssueParent `json:"parent"`
+}
+
+// Issue represents a Jira issue.
+type Issue struct {
+	ID     string      `json:"id"`
+	Key    string      `json:"key"`
+	Fields IssueFields `json:"fields"`
+}
+
+// IsEpic indicates if the issue is an epic.
+func (i *Issue) IsEpic() bool {
+	return i.Fields.IssueType.HierarchyLevel == HierarchyLevelEpic
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	client.BasicAuth
+	BaseURL string
+}
+
+// Client looks up the details of Jira issues.
+type Client struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator client.Authenticator
+	issues        map[string]*Issue
+}
+
+func (c *Client) get(ctx context.Context, path string, params map[string]string, v interface{}) error {
+	reqURL, err := c.URL(path, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(resp, v)
+}
+
+// GetIssue returns the issue by its key. Issues are cached by the client, so
+// an issue is fetched only once.
+func (c *Client) GetIssue(ctx context.Context, issueKey string) (*Issue, error) {
+	if issue, isFetched := c.issues[issueKey]; isFetched {
+		return issue, nil
+	}
+
+	var issue Issue
+	err := c.get(ctx, fmt.Sprintf(PathIssue, url.PathEscape(issueKey)), map[string]string{
+		"fields": issueFields,
+	}, &issue)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.issues[issueKey] = &issue
+	return &issue, nil
+}
+
+// GetAncestor returns the first issue at or above the given hierarchy level by
+// walking the parents of the issue. If the issue itself is at or above the
+// level, the issue itself returns. If no such issue exists, nil returns.
+func (c *Client) GetAncestor(ctx context.Context, issueKey string, level int) (*Issue, error) {
+	for depth := 0; depth < maxParentDepth && issueKey != ""; depth++ {
+		issue, err := c.GetIssue(ctx, issueKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if issue.Fields.IssueType.HierarchyLevel >= level {
+			return issue, nil
+		}
+
+		if issue.Fields.Parent == nil {
+			return nil, nil
+		}
+
+		issueKey = issue.Fields.Parent.Key
+	}
+
+	return nil, nil
+}
+
+// GetEpic returns the epic of the issue by walking its parents. If the issue
+// is an epic, the issue itself returns. If the issue has no epic, nil returns.
+func (c *Client) GetEpic(ctx context.Context, issueKey string) (*Issue, error) {
+	return c.GetAncestor(ctx, issueKey, HierarchyLevelEpic)
+}
+
+// Rollup replaces the task of the entries with their ancestor issue at the
+// given hierarchy level, like the epic, so time is logged against the
+// ancestor instead of the individual issue. The original issue key is kept in
+// the AttributeRolledUpIssue attribute. Entries without such an ancestor or
+// which issue does not exist are left intact. The task name of the entries must be the issue key.
+func (c *Client) Rollup(ctx context.Context, entries worklog.Entries, level int) (worklog.Entries, error) {
+	rolledUpEntries := make(worklog.Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		issueKey := entry.Task.Name
+		if issueKey == "" {
+			rolledUpEntries = append(rolledUpEntries, entry)
+			continue
+		}
+
+		ancestor, err := c.GetAncestor(ctx, issueKey, level)
+		if client.KindOf(err) == client.ErrorKindNotFound {
+			rolledUpEntries = append(rolledUpEntries, entry)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if ancestor != nil && ancestor.Key != issueKey {
+			entry.SetAttribute(AttributeRolledUpIssue, issueKey)
+			entry.Task = worklog.IDNameField{
+				ID:   ancestor.ID,
+				Name: ancestor.Key,
+			}
+			entry.AddTransformation("rolled up from %s to %s", issueKey, ancestor.Key)
+		}
+
+		rolledUpEntries = append(rolledUpEntries, entry)
+	}
+
+	return rolledUpEntries, nil
+}
+
+// GetServiceDeskRequest returns the customer request of the issue, including
+// its SLAs. If the issue is not a customer request, ErrNotServiceDeskRequest
+// returns.
+func (c *Client) GetServiceDeskRequest(ctx context.Context, issueKey string) (*ServiceDeskRequest, error) {
+	var request ServiceDeskRequest
+
+	err := c.get(ctx, fmt.Sprintf(PathServiceDeskRequest, url.PathEscape(issueKey)), map[string]string{
+		"expand": "requestType",
+	}, &request)
+
+	if err != nil {
+		// Jira returns 404 for issues that are not customer requests
+		if client.KindOf(err) == client.ErrorKindNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrNotServiceDeskRequest, issueKey)
+		}
+
+		return nil, err
+	}
+
+	var slaResponse SLAResponse
+	if err = c.get(ctx, fmt.Sprintf(PathServiceDeskRequestSLA, url.PathEscape(issueKey)), map[string]string{}, &slaResponse); err != nil {
+		return nil, err
+	}
+
+	request.SLAs = slaResponse.Values
+	return &request, nil
+}
+
+// EnrichWithServiceDesk sets the request type, status and SLA status of the
+// related customer request as attributes of the entries. The task name of the
+// entries must be the issue key. Entries of issues that are not customer
+// requests are left intact.
+func (c *Client) EnrichWithServiceDesk(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	requests := map[string]*ServiceDeskRequest{}
+	enrichedEntries := make(worklog.Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		issueKey := entry.Task.Name
+		if issueKey == "" {
+			enrichedEntries = append(enrichedEntries, entry)
+			continue
+		}
+
+		request, isFetched := requests[issueKey]
+		if !isFetched {
+			var err error
+
+			request, err = c.GetServiceDeskRequest(ctx, issueKey)
+			if err != nil && !errors.Is(err, ErrNotServiceDeskRequest) {
+				return nil, err
+			}
+
+			requests[issueKey] = request
+		}
+
+		if request != nil {
+			entry.SetAttribute(AttributeRequestType, request.RequestType.Name)
+			entry.SetAttribute(AttributeRequestStatus, request.CurrentStatus.Status)
+
+			for i := range request.SLAs {
+				sla := request.SLAs[i]
+				if status := sla.Status(); status != "" {
+					entry.SetAttribute(sla.AttributeName(), status)
+				}
+			}
+		}
+
+		enrichedEntries = append(enrichedEntries, entry)
+	}
+
+	return enrichedEntries, nil
+}
+
+// NewClient returns a new Jira client.
+func NewClient(opts *ClientOpts) (*Client, error) {
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewBasicAuth(opts.Username, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient:     &client.HTTPClient{BaseURL: baseURL},
+		authenticator:  authenticator,
+		issues:         map[string]*Issue{},
+	}, nil
+}
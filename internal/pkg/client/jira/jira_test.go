@@ -0,0 +1,242 @@
+package jira_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jira"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockServer returns a server responding with the given bodies by path.
+// Every other path responds with 404. The number of calls per path is counted
+// in calls.
+func newMockServer(t *testing.T, responses map[string]string, calls map[string]int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		require.Equal(t, "user", username)
+		require.Equal(t, "token", password)
+		require.Equal(t, http.MethodGet, r.Method)
+
+		calls[r.URL.Path]++
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessage":"not found"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newClient(t *testing.T, baseURL string) *jira.Client {
+	jiraClient, err := jira.NewClient(&jira.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "user",
+			Password: "token",
+		},
+		BaseURL: baseURL,
+	})
+	require.Nil(t, err)
+
+	return jiraClient
+}
+
+func TestSLA_Status(t *testing.T) {
+	tests := map[string]struct {
+		sla      jira.SLA
+		expected string
+	}{
+		"ongoing": {
+			sla:      jira.SLA{OngoingCycle: &jira.SLACycle{RemainingTime: jira.Duration{Friendly: "2h"}}},
+			expected: "ongoing, 2h remaining",
+		},
+		"ongoing breached": {
+			sla:      jira.SLA{OngoingCycle: &jira.SLACycle{Breached: true}},
+			expected: jira.SLAStatusBreached,
+		},
+		"paused": {
+			sla:      jira.SLA{OngoingCycle: &jira.SLACycle{Paused: true}},
+			expected: jira.SLAStatusPaused,
+		},
+		"completed breached": {
+			sla:      jira.SLA{CompletedCycles: []jira.SLACycle{{}, {Breached: true}}},
+			expected: jira.SLAStatusBreached,
+		},
+		"completed met": {
+			sla:      jira.SLA{CompletedCycles: []jira.SLACycle{{Breached: true}, {}}},
+			expected: jira.SLAStatusMet,
+		},
+		"no cycles": {
+			sla:      jira.SLA{},
+			expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.sla.Status())
+		})
+	}
+}
+
+func TestSLA_AttributeName(t *testing.T) {
+	sla := jira.SLA{Name: "Time to resolution"}
+	require.Equal(t, "jsm.sla.time_to_resolution", sla.AttributeName())
+
+	sla = jira.SLA{Name: " Time to first response (P1) "}
+	require.Equal(t, "jsm.sla.time_to_first_response_p1", sla.AttributeName())
+}
+
+func TestClient_EnrichWithServiceDesk(t *testing.T) {
+	calls := map[string]int{}
+	server := newMockServer(t, map[string]string{
+		"/rest/servicedeskapi/request/SD-1": `{
+			"issueKey": "SD-1",
+			"requestType": {"name": "Get IT help"},
+			"currentStatus": {"status": "Waiting for support"}
+		}`,
+		"/rest/servicedeskapi/request/SD-1/sla": `{
+			"isLastPage": true,
+			"values": [
+				{
+					"name": "Time to resolution",
+					"ongoingCycle": {
+						"breached": false,
+						"paused": false,
+						"remainingTime": {"millis": 7200000, "friendly": "2h"}
+					},
+					"completedCycles": []
+				},
+				{
+					"name": "Time to first response",
+					"completedCycles": [{"breached": true}]
+				}
+			]
+		}`,
+	}, calls)
+
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+	entries := worklog.Entries{
+		{Task: worklog.IDNameField{Name: "SD-1"}, Summary: "Reset password", Start: start},
+		{Task: worklog.IDNameField{Name: "SD-1"}, Summary: "Follow up", Start: start.Add(time.Hour)},
+		{Task: worklog.IDNameField{Name: "DEV-1"}, Summary: "Develop", Start: start},
+		{Summary: "No task", Start: start},
+	}
+
+	enrichedEntries, err := newClient(t, server.URL).EnrichWithServiceDesk(context.Background(), entries)
+	require.Nil(t, err)
+	require.Len(t, enrichedEntries, 4)
+
+	expectedAttributes := map[string]string{
+		jira.AttributeRequestType:        "Get IT help",
+		jira.AttributeRequestStatus:      "Waiting for support",
+		"jsm.sla.time_to_resolution":     "ongoing, 2h remaining",
+		"jsm.sla.time_to_first_response": jira.SLAStatusBreached,
+	}
+
+	require.Equal(t, expectedAttributes, enrichedEntries[0].Attributes)
+	require.Equal(t, expectedAttributes, enrichedEntries[1].Attributes)
+	require.Nil(t, enrichedEntries[2].Attributes)
+	require.Nil(t, enrichedEntries[3].Attributes)
+
+	// The original entries are not modified
+	require.Nil(t, entries[0].Attributes)
+
+	// Requests are fetched once per issue
+	require.Equal(t, 1, calls["/rest/servicedeskapi/request/SD-1"])
+	require.Equal(t, 1, calls["/rest/servicedeskapi/request/SD-1/sla"])
+	require.Equal(t, 1, calls["/rest/servicedeskapi/request/DEV-1"])
+}
+
+func TestClient_GetServiceDeskRequest_NotFound(t *testing.T) {
+	server := newMockServer(t, map[string]string{}, map[string]int{})
+
+	_, err := newClient(t, server.URL).GetServiceDeskRequest(context.Background(), "DEV-1")
+	require.ErrorContains(t, err, jira.ErrNotServiceDeskRequest.Error())
+}
+
+func TestClient_EnrichWithServiceDesk_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := newClient(t, server.URL).EnrichWithServiceDesk(context.Background(), worklog.Entries{
+		{Task: worklog.IDNameField{Name: "SD-1"}},
+	})
+	require.ErrorContains(t, err, "401")
+}
+
+func TestClient_GetEpic(t *testing.T) {
+	calls := map[string]int{}
+	server := newMockServer(t, map[string]string{
+		"/rest/api/2/issue/DEV-3": `{"key": "DEV-3", "fields": {"issuetype": {"name": "Sub-task", "hierarchyLevel": -1}, "parent": {"key": "DEV-2"}}}`,
+		"/rest/api/2/issue/DEV-2": `{"key": "DEV-2", "fields": {"issuetype": {"name": "Story", "hierarchyLevel": 0}, "parent": {"key": "DEV-1"}}}`,
+		"/rest/api/2/issue/DEV-1": `{"key": "DEV-1", "fields": {"issuetype": {"name": "Epic", "hierarchyLevel": 1}}}`,
+		"/rest/api/2/issue/DEV-4": `{"key": "DEV-4", "fields": {"issuetype": {"name": "Task", "hierarchyLevel": 0}}}`,
+	}, calls)
+
+	jiraClient := newClient(t, server.URL)
+
+	epic, err := jiraClient.GetEpic(context.Background(), "DEV-3")
+	require.Nil(t, err)
+	require.Equal(t, "DEV-1", epic.Key)
+
+	epic, err = jiraClient.GetEpic(context.Background(), "DEV-1")
+	require.Nil(t, err)
+	require.Equal(t, "DEV-1", epic.Key)
+
+	epic, err = jiraClient.GetEpic(context.Background(), "DEV-4")
+	require.Nil(t, err)
+	require.Nil(t, epic)
+
+	// Issues are fetched once
+	require.Equal(t, 1, calls["/rest/api/2/issue/DEV-1"])
+}
+
+func TestClient_Rollup(t *testing.T) {
+	server := newMockServer(t, map[string]string{
+		"/rest/api/2/issue/DEV-3": `{"id": "10003", "key": "DEV-3", "fields": {"issuetype": {"hierarchyLevel": -1}, "parent": {"key": "DEV-2"}}}`,
+		"/rest/api/2/issue/DEV-2": `{"id": "10002", "key": "DEV-2", "fields": {"issuetype": {"hierarchyLevel": 0}, "parent": {"key": "DEV-1"}}}`,
+		"/rest/api/2/issue/DEV-1": `{"id": "10001", "key": "DEV-1", "fields": {"issuetype": {"hierarchyLevel": 1}}}`,
+		"/rest/api/2/issue/DEV-4": `{"id": "10004", "key": "DEV-4", "fields": {"issuetype": {"hierarchyLevel": 0}}}`,
+	}, map[string]int{})
+
+	entries := worklog.Entries{
+		{Task: worklog.IDNameField{ID: "10003", Name: "DEV-3"}, Summary: "Write tests"},
+		{Task: worklog.IDNameField{ID: "10001", Name: "DEV-1"}, Summary: "Plan epic"},
+		{Task: worklog.IDNameField{ID: "10004", Name: "DEV-4"}, Summary: "No epic"},
+		{Summary: "No task"},
+	}
+
+	jiraClient := newClient(t, server.URL)
+
+	rolledUpEntries, err := jiraClient.Rollup(context.Background(), entries, jira.HierarchyLevelEpic)
+	require.Nil(t, err)
+	require.Equal(t, worklog.IDNameField{ID: "10001", Name: "DEV-1"}, rolledUpEntries[0].Task)
+	require.Equal(t, map[string]string{jira.AttributeRolledUpIssue: "DEV-3"}, rolledUpEntries[0].Attributes)
+	require.Equal(t, []string{"rolled up from DEV-3 to DEV-1"}, rolledUpEntries[0].Provenance.Transformations)
+	require.Equal(t, entries[1:], rolledUpEntries[1:])
+
+	// The original entries are not modified
+	require.Equal(t, "DEV-3", entries[0].Task.Name)
+	require.Nil(t, entries[0].Attributes)
+
+	rolledUpEntries, err = jiraClient.Rollup(context.Background(), entries, 0)
+	require.Nil(t, err)
+	require.Equal(t, worklog.IDNameField{ID: "10002", Name: "DEV-2"}, rolledUpEntries[0].Task)
+}
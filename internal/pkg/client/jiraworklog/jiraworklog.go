@@ -0,0 +1,402 @@
+package jiraworklog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathWorklogCreate is the endpoint used to create new worklogs on the
+	// issue.
+	PathWorklogCreate string = "/rest/api/3/issue/%s/worklog"
+	// PathWorklog is the endpoint used to update or delete a worklog of the
+	// issue by its ID.
+	PathWorklog string = "/rest/api/3/issue/%s/worklog/%s"
+
+	// StartedLayout is the layout of the worklog start, required by Jira.
+	StartedLayout string = "2006-01-02T15:04:05.000-0700"
+	// MinTimeSpent is the shortest time Jira accepts for a worklog.
+	MinTimeSpent = time.Minute
+
+	// DefaultMaxCommentLength is the default maximum length of the worklog
+	// comments, matching the default text field limit of Jira.
+	DefaultMaxCommentLength int = 32767
+)
+
+// DocumentNode represents a node of the Atlassian Document Format, used by
+// the version 3 API of Jira for rich text fields, like the comments.
+type DocumentNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Content []DocumentNode `json:"content,omitempty"`
+}
+
+// NewDocument returns the document of the text, having a paragraph per line.
+// If the text is empty, nil returns.
+func NewDocument(text string) *DocumentNode {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	document := &DocumentNode{Type: "doc", Version: 1}
+	for _, line := range strings.Split(text, "\n") {
+		paragraph := DocumentNode{Type: "paragraph"}
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			paragraph.Content = []DocumentNode{{Type: "text", Text: line}}
+		}
+
+		document.Content = append(document.Content, paragraph)
+	}
+
+	return document
+}
+
+// UploadEntry represents the payload to create a new worklog in Jira.
+type UploadEntry struct {
+	Comment          *DocumentNode `json:"comment,omitempty"`
+	Started          string        `json:"started"`
+	TimeSpentSeconds int           `json:"timeSpentSeconds"`
+}
+
+// CreatedWorklog represents the worklog returned by Jira after creating it.
+type CreatedWorklog struct {
+	ID string `json:"id"`
+}
+
+// parseCreatedWorklogID returns the ID of the created worklog. If the response
+// contains no worklog, an empty string returns.
+func parseCreatedWorklogID(body []byte) string {
+	var createdWorklog CreatedWorklog
+	if err := json.Unmarshal(body, &createdWorklog); err != nil {
+		return ""
+	}
+
+	return createdWorklog.ID
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// For Jira Cloud, the username is the email address of the user and the
+// password is an API token.
+type ClientOpts struct {
+	client.BaseClientOpts
+	client.BasicAuth
+	BaseURL string
+	// MaxCommentLength is the maximum length of the worklog comments. If not
+	// set, DefaultMaxCommentLength is used.
+	MaxCommentLength int
+}
+
+type jiraWorklogClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	*client.DefaultUploader
+	authenticator client.Authenticator
+	limits        *client.FieldLimits
+}
+
+// createURL returns the URL of creating a worklog on the issue of the entry.
+func (c *jiraWorklogClient) createURL(entry *worklog.Entry) (string, error) {
+	return c.URL(fmt.Sprintf(PathWorklogCreate, url.PathEscape(entry.Task.Name)), map[string]string{})
+}
+
+// worklogURL returns the URL of the worklog replaced by the entry, on the
+// issue set as the task of the entry.
+func (c *jiraWorklogClient) worklogURL(entry *worklog.Entry) (string, error) {
+	return c.URL(fmt.Sprintf(PathWorklog, url.PathEscape(entry.Task.Name), url.PathEscape(entry.Replaces)), map[string]string{})
+}
+
+// buildUploadEntry returns the worklog of the entry without applying the
+// limits of Jira. Jira does not distinguish billable and unbillable time,
+// hence the total time spent is logged.
+func (c *jiraWorklogClient) buildUploadEntry(entry worklog.Entry, opts *client.UploadOpts) (*UploadEntry, string, error) {
+	comment, err := opts.RenderComment(entry)
+	if err != nil {
+		return nil, "", err
+	}
+
+	totalTimeSpent := entry.BillableDuration + entry.UnbillableDuration
+
+	return &UploadEntry{
+		Started:          entry.Start.Local().Format(StartedLayout),
+		TimeSpentSeconds: int(totalTimeSpent.Seconds()),
+	}, comment, nil
+}
+
+// newUploadEntry returns the worklog sent to Jira for the entry. If the limit
+// policy is transform, the comment is truncated.
+func (c *jiraWorklogClient) newUploadEntry(entry worklog.Entry, opts *client.UploadOpts) (*UploadEntry, error) {
+	uploadEntry, comment, err := c.buildUploadEntry(entry, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.LimitPolicy == client.LimitPolicyTransform {
+		comment = c.limits.TruncateComment(comment)
+	}
+
+	uploadEntry.Comment = NewDocument(comment)
+
+	return uploadEntry, nil
+}
+
+func (c *jiraWorklogClient) CheckLimits(entries worklog.Entries, opts *client.UploadOpts) ([]client.LimitViolation, error) {
+	var violations []client.LimitViolation
+
+	for _, entry := range entries {
+		// The deletions and the negative corrections send no worklog of the
+		// entry, hence they cannot violate the limits
+		if entry.IsDeletion() || (entry.IsCorrection() && entry.Replaces == "") {
+			continue
+		}
+
+		_, comment, err := c.buildUploadEntry(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Task.Name == "" {
+			violations = append(violations, client.LimitViolation{
+				Entry:   entry,
+				Field:   "task",
+				Message: "issue key is not set",
+			})
+		}
+
+		if violation := c.limits.CheckComment(entry, comment); violation != nil {
+			violations = append(violations, *violation)
+		}
+
+		if violation := c.limits.CheckDate(entry, entry.Start.Local()); violation != nil {
+			violations = append(violations, *violation)
+		}
+
+		if timeSpent := entry.BillableDuration + entry.UnbillableDuration; timeSpent < MinTimeSpent {
+			violations = append(violations, client.LimitViolation{
+				Entry:   entry,
+				Field:   "duration",
+				Message: fmt.Sprintf("%s is shorter than the minimum of %s", timeSpent, MinTimeSpent),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// PreviewPayloads returns the payloads of creating the worklogs of the entries
+// and updating or deleting the worklogs replaced by the corrections. Since the
+// negative corrections cannot be applied, their payloads are left out.
+func (c *jiraWorklogClient) PreviewPayloads(entries worklog.Entries, opts *client.UploadOpts) ([]client.Payload, error) {
+	payloads := make([]client.Payload, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsCorrection() && entry.Replaces == "" {
+			continue
+		}
+
+		payload := client.Payload{
+			Entry:  entry,
+			Method: http.MethodPost,
+		}
+
+		var err error
+		if entry.Replaces != "" {
+			payload.Method = http.MethodPut
+			payload.URL, err = c.worklogURL(&entry)
+		} else {
+			payload.URL, err = c.createURL(&entry)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.IsDeletion() {
+			payload.Method = http.MethodDelete
+			payloads = append(payloads, payload)
+			continue
+		}
+
+		uploadEntry, err := c.newUploadEntry(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if payload.Body, err = json.Marshal(uploadEntry); err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
+
+// UploadEntries creates a worklog per entry on the issue set as the task of
+// the entry. The worklogs are created in the name of the authenticated user,
+// since Jira does not allow logging time for other users.
+func (c *jiraWorklogClient) UploadEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	for _, groupEntries := range entries.GroupByTask() {
+		go func(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+			for _, entry := range entries {
+				createURL, err := c.createURL(&entry)
+				if err != nil {
+					errChan <- client.NewEntryError(entry, fmt.Errorf("%w: %w", client.ErrUploadEntries, err))
+					continue
+				}
+
+				uploadEntry, err := c.newUploadEntry(entry, opts)
+				if err != nil {
+					errChan <- client.NewEntryError(entry, fmt.Errorf("%w: %w", client.ErrUploadEntries, err))
+					continue
+				}
+
+				tracker := c.StartTracking(entry, opts.ProgressWriter)
+
+				resp, err := c.Send(ctx, &client.HTTPRequestOpts{
+					Method:  http.MethodPost,
+					Url:     createURL,
+					Auth:    c.authenticator,
+					Timeout: c.Timeout,
+					Data:    uploadEntry,
+					Headers: map[string]string{
+						"Content-Type": "application/json",
+						"Accept":       "application/json",
+					},
+				})
+
+				mutation := &client.Mutation{
+					Entry:      entry,
+					Method:     http.MethodPost,
+					URL:        createURL,
+					StatusCode: client.StatusCodeOf(err),
+					Err:        err,
+				}
+
+				if resp != nil {
+					mutation.StatusCode = resp.StatusCode
+					mutation.ResourceID = parseCreatedWorklogID(resp.Body)
+				}
+
+				opts.RecordMutation(mutation)
+
+				if err != nil {
+					err = fmt.Errorf("%w: %s: %w", client.ErrUploadEntries, entry.Task.Name, err)
+				}
+
+				c.StopTracking(tracker, err)
+				errChan <- client.NewEntryError(entry, err)
+			}
+		}(ctx, groupEntries, errChan, opts)
+	}
+}
+
+// correctEntry updates or deletes the worklog replaced by the entry and
+// records the mutation. The negative corrections are not supported, since the
+// worklogs of the issue cannot be searched by their author.
+func (c *jiraWorklogClient) correctEntry(ctx context.Context, entry worklog.Entry, opts *client.UploadOpts) error {
+	if entry.Replaces == "" {
+		return fmt.Errorf("%w: %w: negative correction of %s without the replaced worklog", client.ErrUploadEntries, client.ErrCorrectionUnsupported, entry.Task.Name)
+	}
+
+	worklogURL, err := c.worklogURL(&entry)
+	if err != nil {
+		return fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+	}
+
+	requestOpts := &client.HTTPRequestOpts{
+		Method:  http.MethodDelete,
+		Url:     worklogURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"Accept":       "application/json",
+		},
+	}
+
+	if !entry.IsDeletion() {
+		uploadEntry, err := c.newUploadEntry(entry, opts)
+		if err != nil {
+			return fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+		}
+
+		requestOpts.Method = http.MethodPut
+		requestOpts.Data = uploadEntry
+	}
+
+	resp, err := c.Send(ctx, requestOpts)
+
+	mutation := &client.Mutation{
+		Entry:      entry,
+		Method:     requestOpts.Method,
+		URL:        worklogURL,
+		StatusCode: client.StatusCodeOf(err),
+		ResourceID: entry.Replaces,
+		Err:        err,
+	}
+
+	if resp != nil {
+		mutation.StatusCode = resp.StatusCode
+	}
+
+	opts.RecordMutation(mutation)
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s worklog %s: %w", client.ErrUploadEntries, entry.Task.Name, requestOpts.Method, entry.Replaces, err)
+	}
+
+	return nil
+}
+
+// CorrectEntries updates the worklogs replaced by the corrections on the issue
+// set as the task of the entries, or deletes them if the corrections have no
+// duration.
+func (c *jiraWorklogClient) CorrectEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	for _, groupEntries := range entries.GroupByTask() {
+		go func(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+			for _, entry := range entries {
+				tracker := c.StartTracking(entry, opts.ProgressWriter)
+				err := c.correctEntry(ctx, entry, opts)
+				c.StopTracking(tracker, err)
+				errChan <- client.NewEntryError(entry, err)
+			}
+		}(ctx, groupEntries, errChan, opts)
+	}
+}
+
+// NewUploader returns a new Jira client for uploading entries as native Jira
+// worklogs, without Tempo.
+func NewUploader(opts *ClientOpts) (client.Uploader, error) {
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewBasicAuth(opts.Username, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	maxCommentLength := opts.MaxCommentLength
+	if maxCommentLength == 0 {
+		maxCommentLength = DefaultMaxCommentLength
+	}
+
+	return &jiraWorklogClient{
+		authenticator:  authenticator,
+		HTTPClient:     &client.HTTPClient{BaseURL: baseURL},
+		BaseClientOpts: &opts.BaseClientOpts,
+		limits: &client.FieldLimits{
+			MaxCommentLength: maxCommentLength,
+			DateLayout:       StartedLayout,
+		},
+	}, nil
+}
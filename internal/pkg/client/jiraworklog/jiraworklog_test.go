@@ -0,0 +1,274 @@
+package jiraworklog_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jiraworklog"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMutationRecorder struct {
+	mutations chan *client.Mutation
+}
+
+func (r *mockMutationRecorder) RecordMutation(mutation *client.Mutation) {
+	r.mutations <- mutation
+}
+
+func newUploader(t *testing.T, baseURL string, maxCommentLength int) client.Uploader {
+	uploader, err := jiraworklog.NewUploader(&jiraworklog.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "steve.rogers@example.com",
+			Password: "The first Avenger",
+		},
+		BaseURL:          baseURL,
+		MaxCommentLength: maxCommentLength,
+	})
+	require.Nil(t, err)
+
+	return uploader
+}
+
+func TestNewDocument(t *testing.T) {
+	require.Nil(t, jiraworklog.NewDocument(" "))
+
+	require.Equal(t, &jiraworklog.DocumentNode{
+		Type:    "doc",
+		Version: 1,
+		Content: []jiraworklog.DocumentNode{
+			{Type: "paragraph", Content: []jiraworklog.DocumentNode{{Type: "text", Text: "Meet with The Winter Soldier"}}},
+			{Type: "paragraph"},
+			{Type: "paragraph", Content: []jiraworklog.DocumentNode{{Type: "text", Text: "In Bucharest"}}},
+		},
+	}, jiraworklog.NewDocument("Meet with The Winter Soldier\r\n\nIn Bucharest"))
+}
+
+func TestJiraWorklogClient_UploadEntries(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local)
+
+	entries := worklog.Entries{
+		{
+			Project:            worklog.IDNameField{ID: "456", Name: "MARVEL"},
+			Task:               worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:            "Meet with The Winter Soldier",
+			Start:              start,
+			BillableDuration:   time.Hour,
+			UnbillableDuration: time.Minute * 30,
+		},
+		{
+			Project:          worklog.IDNameField{ID: "123", Name: "SHIELD"},
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: time.Hour,
+		},
+	}
+
+	var mutex sync.Mutex
+	requests := map[string]jiraworklog.UploadEntry{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		require.Equal(t, "steve.rogers@example.com", username)
+		require.Equal(t, "The first Avenger", password)
+		require.Equal(t, http.MethodPost, r.Method)
+
+		var uploadEntry jiraworklog.UploadEntry
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&uploadEntry))
+
+		mutex.Lock()
+		requests[r.URL.Path] = uploadEntry
+		mutex.Unlock()
+
+		if r.URL.Path == fmt.Sprintf(jiraworklog.PathWorklogCreate, "SHD-2012") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errorMessages":[],"errors":{"issueId":"Issue does not exist"}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(jiraworklog.CreatedWorklog{ID: "10042"})
+	}))
+	defer mockServer.Close()
+
+	recorder := &mockMutationRecorder{mutations: make(chan *client.Mutation, len(entries))}
+	uploader := newUploader(t, mockServer.URL, 0)
+
+	errChan := make(chan error)
+	uploader.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{
+		MutationRecorder: recorder,
+	})
+
+	var errs []error
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	close(recorder.mutations)
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrUploadEntries)
+	require.Contains(t, errs[0].Error(), "SHD-2012")
+
+	uploadEntry := requests[fmt.Sprintf(jiraworklog.PathWorklogCreate, "CPT-2014")]
+	require.Equal(t, start.Format(jiraworklog.StartedLayout), uploadEntry.Started)
+	require.Equal(t, 5400, uploadEntry.TimeSpentSeconds)
+	require.Equal(t, "Meet with The Winter Soldier", uploadEntry.Comment.Content[0].Content[0].Text)
+
+	mutations := map[string]*client.Mutation{}
+	for mutation := range recorder.mutations {
+		mutations[mutation.Entry.Task.Name] = mutation
+	}
+
+	require.Equal(t, http.StatusCreated, mutations["CPT-2014"].StatusCode)
+	require.Equal(t, "10042", mutations["CPT-2014"].ResourceID)
+	require.Equal(t, mockServer.URL+fmt.Sprintf(jiraworklog.PathWorklogCreate, "CPT-2014"), mutations["CPT-2014"].URL)
+	require.Equal(t, http.StatusBadRequest, mutations["SHD-2012"].StatusCode)
+	require.Empty(t, mutations["SHD-2012"].ResourceID)
+}
+
+func TestJiraWorklogClient_PreviewPayloads(t *testing.T) {
+	uploader := newUploader(t, "https://example.atlassian.net", 10)
+
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+		Summary:          "Meet with The Winter Soldier",
+		Start:            time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC),
+		BillableDuration: time.Hour,
+	}
+
+	payloads, err := uploader.(client.PayloadPreviewer).PreviewPayloads(worklog.Entries{entry}, &client.UploadOpts{
+		LimitPolicy: client.LimitPolicyTransform,
+	})
+	require.Nil(t, err)
+	require.Len(t, payloads, 1)
+	require.Equal(t, http.MethodPost, payloads[0].Method)
+	require.Equal(t, "https://example.atlassian.net/rest/api/3/issue/CPT-2014/worklog", payloads[0].URL)
+
+	var uploadEntry jiraworklog.UploadEntry
+	require.Nil(t, json.Unmarshal(payloads[0].Body, &uploadEntry))
+	require.Equal(t, 3600, uploadEntry.TimeSpentSeconds)
+	require.Equal(t, "Meet with…", uploadEntry.Comment.Content[0].Content[0].Text)
+}
+
+func TestJiraWorklogClient_CheckLimits(t *testing.T) {
+	uploader := newUploader(t, "https://example.atlassian.net", 10)
+
+	entries := worklog.Entries{
+		{
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          strings.Repeat("Meet ", 3),
+			Start:            time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC),
+			BillableDuration: time.Second * 30,
+		},
+		{
+			Summary:          "Short",
+			BillableDuration: time.Hour,
+		},
+	}
+
+	violations, err := uploader.(client.LimitChecker).CheckLimits(entries, &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Len(t, violations, 4)
+
+	require.Equal(t, "comment", violations[0].Field)
+	require.True(t, violations[0].Transformable)
+	require.Equal(t, "duration", violations[1].Field)
+	require.Equal(t, "task", violations[2].Field)
+	require.Equal(t, "date", violations[3].Field)
+	require.False(t, violations[3].Transformable)
+}
+
+func TestJiraWorklogClient_CorrectEntries(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local)
+
+	entries := worklog.Entries{
+		{
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour * 2,
+			Replaces:         "10001",
+		},
+		{
+			Task:     worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:  "Assemble the Avengers",
+			Start:    start,
+			Replaces: "10002",
+		},
+		{
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: -time.Hour,
+		},
+	}
+
+	var mutex sync.Mutex
+	requests := map[string]int{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeSpent := 0
+		if r.Method == http.MethodPut {
+			var uploadEntry jiraworklog.UploadEntry
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&uploadEntry))
+			timeSpent = uploadEntry.TimeSpentSeconds
+		}
+
+		mutex.Lock()
+		requests[r.Method+" "+r.URL.Path] = timeSpent
+		mutex.Unlock()
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer mockServer.Close()
+
+	recorder := &mockMutationRecorder{mutations: make(chan *client.Mutation, len(entries))}
+
+	errChan := make(chan error, len(entries))
+	newUploader(t, mockServer.URL, 0).(client.Corrector).CorrectEntries(context.Background(), entries, errChan, &client.UploadOpts{
+		MutationRecorder: recorder,
+	})
+
+	var errs []error
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	close(recorder.mutations)
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrCorrectionUnsupported)
+	require.Equal(t, &entries[2], client.EntryOf(errs[0]))
+
+	require.Equal(t, map[string]int{
+		"PUT " + fmt.Sprintf(jiraworklog.PathWorklog, "CPT-2014", "10001"):    7200,
+		"DELETE " + fmt.Sprintf(jiraworklog.PathWorklog, "SHD-2012", "10002"): 0,
+	}, requests)
+
+	mutations := map[string]*client.Mutation{}
+	for mutation := range recorder.mutations {
+		mutations[mutation.Method] = mutation
+	}
+
+	require.Len(t, mutations, 2)
+	require.Equal(t, "10001", mutations[http.MethodPut].ResourceID)
+	require.Equal(t, http.StatusNoContent, mutations[http.MethodDelete].StatusCode)
+}
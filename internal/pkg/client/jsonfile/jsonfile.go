@@ -0,0 +1,217 @@
+package jsonfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+var (
+	// ErrMissingStart returns when a record has no start date and time.
+	ErrMissingStart = errors.New("missing start")
+	// ErrNegativeDuration returns when a record, not being a correction, has
+	// a negative billable or unbillable duration.
+	ErrNegativeDuration = errors.New("negative duration")
+)
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Since the JSON file is read locally, the timeout is not used.
+type ClientOpts struct {
+	client.BaseClientOpts
+	// Path is the path of the read JSON or newline-delimited JSON file.
+	Path string
+	// Strict indicates to fail reading the file if any record is invalid,
+	// instead of skipping the invalid records.
+	Strict bool
+	// RejectsPath is the path of the file the invalid records are written to,
+	// one record per line, so they can be fixed and imported again. If empty,
+	// the invalid records are not written.
+	RejectsPath string
+}
+
+type jsonClient struct {
+	*client.BaseClientOpts
+	opts ClientOpts
+}
+
+// record represents a read record of the file and its line number.
+type record struct {
+	line int
+	data json.RawMessage
+}
+
+// lineAt returns the 1-based line number of the offset in the content.
+func lineAt(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// readArray returns the elements of the JSON array.
+func readArray(content []byte) ([]*record, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	// The opening bracket of the array
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var records []*record
+
+	for decoder.More() {
+		var data json.RawMessage
+		if err := decoder.Decode(&data); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineAt(content, int(decoder.InputOffset())), err)
+		}
+
+		start := int(decoder.InputOffset()) - len(data)
+		records = append(records, &record{line: lineAt(content, start), data: data})
+	}
+
+	return records, nil
+}
+
+// readLines returns the non-empty lines of the newline-delimited JSON file.
+func readLines(content []byte) []*record {
+	var records []*record
+
+	for i, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		records = append(records, &record{line: i + 1, data: line})
+	}
+
+	return records
+}
+
+// readRecords returns the records of the file. Files starting with an opening
+// bracket are read as JSON arrays, others as newline-delimited JSON.
+func (c *jsonClient) readRecords() ([]*record, error) {
+	content, err := os.ReadFile(c.opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+		return readArray(content)
+	}
+
+	return readLines(content), nil
+}
+
+// convertRecordToEntry parses the record as a worklog.Entry. If the record
+// has no source IDs, its line number is used.
+func convertRecordToEntry(r *record) (worklog.Entry, error) {
+	var entry worklog.Entry
+
+	if err := json.Unmarshal(r.data, &entry); err != nil {
+		return entry, &client.RowError{Line: r.line, Err: err}
+	}
+
+	if entry.Start.IsZero() {
+		return entry, &client.RowError{Line: r.line, Column: "start", Err: ErrMissingStart}
+	}
+
+	// The corrections are validated by the pipeline, like the other entries
+	if !entry.IsCorrection() && entry.BillableDuration < 0 {
+		return entry, &client.RowError{Line: r.line, Column: "billable_duration", Err: ErrNegativeDuration}
+	}
+
+	if !entry.IsCorrection() && entry.UnbillableDuration < 0 {
+		return entry, &client.RowError{Line: r.line, Column: "unbillable_duration", Err: ErrNegativeDuration}
+	}
+
+	if len(entry.Provenance.SourceIDs) == 0 {
+		entry.Provenance.SourceIDs = []string{"line " + strconv.Itoa(r.line)}
+	}
+
+	return entry, nil
+}
+
+// writeRejects writes the invalid records to the rejects file as
+// newline-delimited JSON.
+func (c *jsonClient) writeRejects(rejects []*record) error {
+	var content bytes.Buffer
+
+	for _, reject := range rejects {
+		// The records of arrays may span multiple lines
+		if err := json.Compact(&content, reject.data); err != nil {
+			content.Write(reject.data)
+		}
+
+		content.WriteByte('\n')
+	}
+
+	return os.WriteFile(c.opts.RejectsPath, content.Bytes(), 0600)
+}
+
+func (c *jsonClient) FetchEntries(_ context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	records, err := c.readRecords()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	entries := worklog.Entries{}
+	var rowErrors client.RowErrors
+	var rejects []*record
+
+	for _, r := range records {
+		entry, err := convertRecordToEntry(r)
+		if err != nil {
+			var rowErr *client.RowError
+			errors.As(err, &rowErr)
+
+			rowErrors = append(rowErrors, rowErr)
+			rejects = append(rejects, r)
+			continue
+		}
+
+		if !opts.Contains(entry.Start) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(rowErrors) == 0 {
+		return entries, nil
+	}
+
+	if c.opts.RejectsPath != "" {
+		if err = c.writeRejects(rejects); err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+	}
+
+	if c.opts.Strict {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, rowErrors)
+	}
+
+	return entries, rowErrors
+}
+
+// NewFetcher returns a new JSON file client for reading entries. The file
+// contains the entries in the format of the "entry" schema, either as a JSON
+// array or as newline-delimited JSON, one entry per line.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no JSON file path provided")
+	}
+
+	clientOpts := *opts
+
+	return &jsonClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package jsonfile_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/jsonfile"
+	"github.com/stretchr/testify/require"
+)
+
+var fetchOpts = &client.FetchOpts{
+	Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC),
+}
+
+func TestJSONClient_FetchEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.json")
+
+	content := `[
+  {
+    "client": {"id": "1", "name": "My Awesome Company"},
+    "project": {"id": "2", "name": "MARVEL"},
+    "task": {"id": "3", "name": "TASK-123"},
+    "summary": "Fix the bug",
+    "start": "2021-10-02T09:00:00Z",
+    "billable_duration": 5400000000000,
+    "unbillable_duration": 0,
+    "attributes": {"worker": "steve.rogers"},
+    "provenance": {"source_ids": ["abc-123"]}
+  },
+  {
+    "task": {"id": "4", "name": "TASK-456"},
+    "start": "2021-10-02T13:00:00Z",
+    "billable_duration": 0,
+    "unbillable_duration": 1800000000000
+  },
+  {"task": {"id": "5", "name": "TASK-789"}, "start": "2021-10-01T09:00:00Z", "billable_duration": 3600000000000}
+]
+`
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetcher, err := jsonfile.NewFetcher(&jsonfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "MARVEL", entries[0].Project.Name)
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC), entries[0].Start.UTC())
+	require.Equal(t, time.Minute*90, entries[0].BillableDuration)
+	require.Equal(t, "steve.rogers", entries[0].Attributes["worker"])
+	require.Equal(t, []string{"abc-123"}, entries[0].Provenance.SourceIDs)
+
+	require.Equal(t, time.Minute*30, entries[1].UnbillableDuration)
+	require.Equal(t, []string{"line 13"}, entries[1].Provenance.SourceIDs)
+}
+
+func TestJSONClient_FetchEntries_NDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.ndjson")
+
+	content := `{"task": {"id": "3", "name": "TASK-123"}, "start": "2021-10-02T09:00:00Z", "billable_duration": 3600000000000}
+
+{"task": {"id": "4", "name": "TASK-456"}, "start": "2021-10-02T13:00:00Z", "billable_duration": 1800000000000}
+`
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetcher, err := jsonfile.NewFetcher(&jsonfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, []string{"line 1"}, entries[0].Provenance.SourceIDs)
+	require.Equal(t, "TASK-456", entries[1].Task.Name)
+	require.Equal(t, []string{"line 3"}, entries[1].Provenance.SourceIDs)
+}
+
+func TestJSONClient_FetchEntries_Corrections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.ndjson")
+
+	content := `{"task": {"id": "3", "name": "TASK-123"}, "start": "2021-10-02T09:00:00Z", "billable_duration": -1800000000000}
+{"task": {"id": "4", "name": "TASK-456"}, "start": "2021-10-02T13:00:00Z", "billable_duration": 0, "replaces": "10001"}
+`
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	fetcher, err := jsonfile.NewFetcher(&jsonfile.ClientOpts{Path: path, Strict: true})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), fetchOpts)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, -time.Minute*30, entries[0].BillableDuration)
+	require.True(t, entries[0].IsCorrection())
+	require.Equal(t, "10001", entries[1].Replaces)
+	require.True(t, entries[1].IsDeletion())
+}
+
+func TestJSONClient_FetchEntries_InvalidRecords(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entries.ndjson")
+	rejectsPath := filepath.Join(dir, "rejects.ndjson")
+
+	content := `{"task": {"id": "3", "name": "TASK-123"}, "start": "2021-10-02T09:00:00Z", "billable_duration": 3600000000000}
+{"task": {"id": "4", "name": "TASK-456"}, "billable_duration": 3600000000000}
+{"task": {"id": "5", "name": "TASK-789"}, "start": "2021-10-02T13:00:00Z", "billable_duration": -1, "unbillable_duration": 3600000000000}
+{"task": "TASK-999"
+`
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+
+	for _, strict := range []bool{false, true} {
+		fetcher, err := jsonfile.NewFetcher(&jsonfile.ClientOpts{
+			Path:        path,
+			Strict:      strict,
+			RejectsPath: rejectsPath,
+		})
+		require.Nil(t, err)
+
+		entries, err := fetcher.FetchEntries(context.Background(), fetchOpts)
+
+		var rowErrors client.RowErrors
+		require.True(t, errors.As(err, &rowErrors))
+		require.Len(t, rowErrors, 3)
+		require.EqualError(t, rowErrors[0], "line 2: start: missing start")
+		require.ErrorIs(t, rowErrors[1], jsonfile.ErrNegativeDuration)
+		require.Equal(t, 4, rowErrors[2].Line)
+
+		if strict {
+			require.ErrorIs(t, err, client.ErrFetchEntries)
+			require.Nil(t, entries)
+		} else {
+			require.Len(t, entries, 1)
+			require.Equal(t, "TASK-123", entries[0].Task.Name)
+		}
+
+		rejects, err := os.ReadFile(rejectsPath)
+		require.Nil(t, err)
+		require.Equal(t, `{"task":{"id":"4","name":"TASK-456"},"billable_duration":3600000000000}
+{"task":{"id":"5","name":"TASK-789"},"start":"2021-10-02T13:00:00Z","billable_duration":-1,"unbillable_duration":3600000000000}
+{"task": "TASK-999"
+`, string(rejects))
+	}
+}
+
+func TestNewFetcher_InvalidOpts(t *testing.T) {
+	_, err := jsonfile.NewFetcher(&jsonfile.ClientOpts{})
+	require.NotNil(t, err)
+}
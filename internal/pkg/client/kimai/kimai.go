@@ -0,0 +1,221 @@
+package kimai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathTimesheets is the endpoint used to search timesheets.
+	PathTimesheets string = "/api/timesheets"
+	// HeaderTotalCount is the header returning the number of timesheets
+	// matching the filters on every page.
+	HeaderTotalCount string = "X-Total-Count"
+	// MaxPageSize is the number of timesheets requested on a page.
+	MaxPageSize int = 100
+	// StateStopped is the state filter of the stopped timesheets, as the
+	// running timesheets have no duration yet.
+	StateStopped string = "3"
+	// DateTimeFormat is the local date time format of the date filters.
+	DateTimeFormat string = "2006-01-02T15:04:05"
+)
+
+// Customer represents the customer of a project.
+type Customer struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Project represents the project of a timesheet.
+type Project struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Customer Customer `json:"customer"`
+}
+
+// FetchEntry represents the timesheet fetched from Kimai. The duration is in
+// seconds.
+type FetchEntry struct {
+	ID          int                    `json:"id"`
+	Begin       utils.Timestamp        `json:"begin"`
+	End         utils.Timestamp        `json:"end"`
+	Duration    int                    `json:"duration"`
+	Description string                 `json:"description"`
+	Tags        []string               `json:"tags"`
+	Exported    bool                   `json:"exported"`
+	Billable    bool                   `json:"billable"`
+	Project     Project                `json:"project"`
+	Activity    worklog.IntIDNameField `json:"activity"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Token is the API token used to authenticate.
+	Token string
+	// IncludeExported fetches the exported timesheets too. The exported
+	// timesheets are already billed or exported to other systems, hence they
+	// are skipped by default.
+	IncludeExported bool
+}
+
+type kimaiClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator   client.Authenticator
+	includeExported bool
+}
+
+func (c *kimaiClient) parseEntries(fetchedEntries []FetchEntry, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, fetchedEntry := range fetchedEntries {
+		if fetchedEntry.Exported && !c.includeExported {
+			continue
+		}
+
+		billableDuration := time.Duration(fetchedEntry.Duration) * time.Second
+		unbillableDuration := time.Duration(0)
+
+		if !fetchedEntry.Billable {
+			unbillableDuration = billableDuration
+			billableDuration = 0
+		}
+
+		entry := worklog.Entry{
+			Client: worklog.IDNameField{
+				ID:   strconv.Itoa(fetchedEntry.Project.Customer.ID),
+				Name: fetchedEntry.Project.Customer.Name,
+			},
+			Project: worklog.IDNameField{
+				ID:   strconv.Itoa(fetchedEntry.Project.ID),
+				Name: fetchedEntry.Project.Name,
+			},
+			Task:               fetchedEntry.Activity.ConvertToIDNameField(),
+			Summary:            fetchedEntry.Description,
+			Notes:              fetchedEntry.Description,
+			Start:              fetchedEntry.Begin.Local(),
+			BillableDuration:   billableDuration,
+			UnbillableDuration: unbillableDuration,
+			Provenance:         worklog.Provenance{SourceIDs: []string{strconv.Itoa(fetchedEntry.ID)}},
+		}
+
+		// The description of the timesheets is optional, hence the activity is
+		// used instead
+		if entry.Summary == "" {
+			entry.Summary = fetchedEntry.Activity.Name
+		}
+
+		entry.AddLinks(utils.ExtractURLs(fetchedEntry.Description)...)
+
+		var tags []worklog.IDNameField
+		for _, tag := range fetchedEntry.Tags {
+			tags = append(tags, worklog.IDNameField{
+				ID:   tag,
+				Name: tag,
+			})
+		}
+
+		if len(tags) > 0 {
+			entry.TaskHints = &worklog.TaskHints{Tags: tags}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *kimaiClient) fetchEntries(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+	resp, err := c.Send(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fetchedEntries []FetchEntry
+	if err = json.Unmarshal(resp.Body, &fetchedEntries); err != nil {
+		return nil, nil, err
+	}
+
+	// Kimai returns the pagination details in the headers, and responds not
+	// found for the pages after the last one, hence the total is required
+	totalEntries, err := strconv.Atoi(resp.Header.Get(HeaderTotalCount))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s header: %w", HeaderTotalCount, err)
+	}
+
+	return fetchedEntries, &client.PaginatedFetchResponse{
+		EntriesPerPage: MaxPageSize,
+		TotalEntries:   totalEntries,
+	}, nil
+}
+
+func (c *kimaiClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	params := map[string]string{
+		"begin": opts.Start.Local().Format(DateTimeFormat),
+		"end":   opts.End.Local().Format(DateTimeFormat),
+		"state": StateStopped,
+		"full":  "true",
+	}
+
+	// The timesheets of the owner of the token are returned by default
+	if opts.User != "" {
+		params["user"] = opts.User
+	}
+
+	fetchURL, err := c.URL(PathTimesheets, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	return client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[FetchEntry]{
+		BaseFetchOpts: opts,
+		URL:           fetchURL,
+		PageSize:      MaxPageSize,
+		PageSizeParam: "size",
+		FetchFunc:     c.fetchEntries,
+		ParseFunc:     c.parseEntries,
+	})
+}
+
+// NewFetcher returns a new Kimai client for fetching timesheets.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.BaseURL == "" {
+		return nil, errors.New("no Kimai URL provided")
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Bearer", opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kimaiClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		authenticator:   authenticator,
+		includeExported: opts.IncludeExported,
+	}, nil
+}
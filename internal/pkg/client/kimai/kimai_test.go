@@ -0,0 +1,184 @@
+package kimai_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/kimai"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	developmentTimesheet = `{"id":1,"begin":"2021-10-01T09:00:00+0000","end":"2021-10-01T10:30:00+0000","duration":5400,"description":"CPT-123 Fix thing","tags":["CPT-123"],"exported":false,"billable":true,"project":{"id":2,"name":"Website","customer":{"id":3,"name":"ACME Inc."}},"activity":{"id":4,"name":"Development"}}`
+	meetingTimesheet     = `{"id":5,"begin":"2021-10-01T14:00:00+0000","end":"2021-10-01T14:30:00+0000","duration":1800,"description":"","tags":[],"exported":false,"billable":false,"project":{"id":2,"name":"Website","customer":{"id":3,"name":"ACME Inc."}},"activity":{"id":6,"name":"Meeting"}}`
+	exportedTimesheet    = `{"id":7,"begin":"2021-10-02T10:00:00+0000","end":"2021-10-02T11:00:00+0000","duration":3600,"description":"Deploy","tags":[],"exported":true,"billable":true,"project":{"id":2,"name":"Website","customer":{"id":3,"name":"ACME Inc."}},"activity":{"id":4,"name":"Development"}}`
+)
+
+var (
+	acme    = worklog.IDNameField{ID: "3", Name: "ACME Inc."}
+	website = worklog.IDNameField{ID: "2", Name: "Website"}
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.UTC).Local()
+}
+
+// newMockServer returns a server responding the pages by their number, and
+// the total number of timesheets in the header.
+func newMockServer(t *testing.T, totalCount int, pages map[int]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, kimai.PathTimesheets, r.URL.Path)
+		require.Equal(t, "Bearer s3cr3t", r.Header.Get("Authorization"))
+
+		query := r.URL.Query()
+		require.Equal(t, "2021-10-01T00:00:00", query.Get("begin"))
+		require.Equal(t, "2021-10-03T00:00:00", query.Get("end"))
+		require.Equal(t, kimai.StateStopped, query.Get("state"))
+		require.Equal(t, "true", query.Get("full"))
+		require.Equal(t, strconv.Itoa(kimai.MaxPageSize), query.Get("size"))
+
+		page, ok := pages[mustAtoi(t, query.Get("page"))]
+		require.True(t, ok, "unexpected page %s", query.Get("page"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(kimai.HeaderTotalCount, strconv.Itoa(totalCount))
+		_, err := w.Write([]byte(page))
+		require.Nil(t, err)
+	}))
+}
+
+func mustAtoi(t *testing.T, value string) int {
+	number, err := strconv.Atoi(value)
+	require.Nil(t, err)
+
+	return number
+}
+
+func newTestFetcher(t *testing.T, serverURL string, includeExported bool) client.Fetcher {
+	fetcher, err := kimai.NewFetcher(&kimai.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:         serverURL,
+		Token:           "s3cr3t",
+		IncludeExported: includeExported,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func newFetchOpts() *client.FetchOpts {
+	return &client.FetchOpts{
+		Start: time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	}
+}
+
+func TestKimaiClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, 3, map[int]string{
+		1: "[" + developmentTimesheet + "," + meetingTimesheet + "]",
+		2: "[" + exportedTimesheet + "]",
+	})
+	defer mockServer.Close()
+
+	expectedEntries := worklog.Entries{
+		{
+			Client:           acme,
+			Project:          website,
+			Task:             worklog.IDNameField{ID: "4", Name: "Development"},
+			Summary:          "CPT-123 Fix thing",
+			Notes:            "CPT-123 Fix thing",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Minute * 90,
+			TaskHints:        &worklog.TaskHints{Tags: []worklog.IDNameField{{ID: "CPT-123", Name: "CPT-123"}}},
+			Provenance:       worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Client:             acme,
+			Project:            website,
+			Task:               worklog.IDNameField{ID: "6", Name: "Meeting"},
+			Summary:            "Meeting",
+			Start:              at(1, 14, 0),
+			UnbillableDuration: time.Minute * 30,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"5"}},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL, false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, expectedEntries, entries)
+}
+
+func TestKimaiClient_FetchEntries_IncludeExported(t *testing.T) {
+	mockServer := newMockServer(t, 1, map[int]string{
+		1: "[" + exportedTimesheet + "]",
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, true).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, worklog.Entries{
+		{
+			Client:           acme,
+			Project:          website,
+			Task:             worklog.IDNameField{ID: "4", Name: "Development"},
+			Summary:          "Deploy",
+			Notes:            "Deploy",
+			Start:            at(2, 10, 0),
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"7"}},
+		},
+	}, entries)
+}
+
+func TestKimaiClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, 1, map[int]string{
+		1: "[" + developmentTimesheet + "]",
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^CPT-\d+$`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.Equal(t, worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}, entries[0].Task)
+	require.Equal(t, time.Minute*90, entries[0].BillableDuration)
+}
+
+func TestKimaiClient_FetchEntries_MissingTotalCount(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("[" + developmentTimesheet + "]"))
+		require.Nil(t, err)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorContains(t, err, kimai.HeaderTotalCount)
+}
+
+func TestKimaiClient_FetchEntries_Unauthorized(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, false).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoURL(t *testing.T) {
+	_, err := kimai.NewFetcher(&kimai.ClientOpts{Token: "s3cr3t"})
+	require.EqualError(t, err, "no Kimai URL provided")
+}
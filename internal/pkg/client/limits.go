@@ -0,0 +1,183 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// LimitPolicyFail stops the sync before uploading if any entry violates
+	// the limits of the target.
+	LimitPolicyFail string = "fail"
+	// LimitPolicyTransform transforms the fields violating the limits, like
+	// truncating the comments, and stops the sync only if a field cannot be
+	// transformed.
+	LimitPolicyTransform string = "transform"
+	// LimitPolicyIgnore uploads the entries as is, leaving the validation to
+	// the target.
+	LimitPolicyIgnore string = "ignore"
+
+	// truncatedSuffix is appended to the truncated comments.
+	truncatedSuffix string = "…"
+)
+
+var (
+	// ErrLimitExceeded wraps the violations of the target's limits.
+	ErrLimitExceeded = errors.New("target limits exceeded")
+)
+
+// LimitPolicies lists the policies of handling the violations of the limits.
+var LimitPolicies = []string{LimitPolicyFail, LimitPolicyTransform, LimitPolicyIgnore}
+
+// FieldLimits represents the constraints of a target on the uploaded fields.
+type FieldLimits struct {
+	// MaxCommentLength is the maximum number of characters of the comment. If
+	// 0, the length of the comment is not limited.
+	MaxCommentLength int
+	// DateLayout is the layout of the dates sent to the target. If set, the
+	// start of the entries must be representable in the layout.
+	DateLayout string
+	// AttributeValues maps the keys of the attributes to their allowed values.
+	// The attributes not listed accept any value.
+	AttributeValues map[string][]string
+}
+
+// CheckComment returns the violation if the comment is longer than the
+// maximum length, otherwise nil.
+func (l *FieldLimits) CheckComment(entry worklog.Entry, comment string) *LimitViolation {
+	if length := utf8.RuneCountInString(comment); l.MaxCommentLength > 0 && length > l.MaxCommentLength {
+		return &LimitViolation{
+			Entry:         entry,
+			Field:         "comment",
+			Message:       fmt.Sprintf("%d characters exceed the maximum of %d", length, l.MaxCommentLength),
+			Transformable: true,
+		}
+	}
+
+	return nil
+}
+
+// TruncateComment returns the comment truncated to the maximum length, ending
+// with an ellipsis. Comments within the limit are returned as is.
+func (l *FieldLimits) TruncateComment(comment string) string {
+	if l.MaxCommentLength <= 0 || utf8.RuneCountInString(comment) <= l.MaxCommentLength {
+		return comment
+	}
+
+	runes := []rune(comment)
+	suffix := []rune(truncatedSuffix)
+
+	if l.MaxCommentLength <= len(suffix) {
+		return string(runes[:l.MaxCommentLength])
+	}
+
+	return string(runes[:l.MaxCommentLength-len(suffix)]) + truncatedSuffix
+}
+
+// CheckDate returns the violation if the date is not set or cannot be
+// represented in the date layout, like years beyond 9999, otherwise nil.
+func (l *FieldLimits) CheckDate(entry worklog.Entry, date time.Time) *LimitViolation {
+	if l.DateLayout == "" {
+		return nil
+	}
+
+	if date.IsZero() {
+		return &LimitViolation{Entry: entry, Field: "date", Message: "date is not set"}
+	}
+
+	formatted := date.Format(l.DateLayout)
+	if _, err := time.Parse(l.DateLayout, formatted); err != nil {
+		return &LimitViolation{
+			Entry:   entry,
+			Field:   "date",
+			Message: fmt.Sprintf("\"%s\" is not a valid date in %s format", formatted, l.DateLayout),
+		}
+	}
+
+	return nil
+}
+
+// CheckAttribute returns the violation if the value is not allowed for the
+// attribute, otherwise nil.
+func (l *FieldLimits) CheckAttribute(entry worklog.Entry, key string, value string) *LimitViolation {
+	allowedValues, ok := l.AttributeValues[key]
+	if !ok {
+		return nil
+	}
+
+	for _, allowedValue := range allowedValues {
+		if value == allowedValue {
+			return nil
+		}
+	}
+
+	return &LimitViolation{
+		Entry:         entry,
+		Field:         key,
+		Message:       fmt.Sprintf("\"%s\" is not part of the allowed values %v", value, allowedValues),
+		Transformable: true,
+	}
+}
+
+// LimitViolation represents a field of an entry violating a limit of the
+// target.
+type LimitViolation struct {
+	Entry   worklog.Entry
+	Field   string
+	Message string
+	// Transformable is true if the field is transformed to satisfy the limit
+	// when LimitPolicyTransform is used, like truncating the comment or
+	// omitting the attribute.
+	Transformable bool
+}
+
+func (v *LimitViolation) Error() string {
+	return fmt.Sprintf("%s: %s: %s", v.Entry.Key(), v.Field, v.Message)
+}
+
+// LimitChecker is implemented by the uploaders having limits on the uploaded
+// fields, so the entries can be validated before uploading them, instead of
+// failing on the target's side.
+type LimitChecker interface {
+	// CheckLimits returns the violations of the target's limits by the
+	// entries, as the entries would be uploaded without transformation.
+	CheckLimits(entries worklog.Entries, opts *UploadOpts) ([]LimitViolation, error)
+}
+
+// CheckLimits validates the entries against the limits of the uploader by the
+// limit policy of the options. The violations transformed by the policy are
+// returned, while the remaining violations are returned as an error. If the
+// uploader has no limits or the policy is LimitPolicyIgnore, nothing returns.
+func CheckLimits(uploader Uploader, entries worklog.Entries, opts *UploadOpts) ([]LimitViolation, error) {
+	checker, ok := uploader.(LimitChecker)
+	if !ok || opts.LimitPolicy == LimitPolicyIgnore {
+		return nil, nil
+	}
+
+	violations, err := checker.CheckLimits(entries, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var transformed []LimitViolation
+	var errs []error
+
+	for i := range violations {
+		if opts.LimitPolicy == LimitPolicyTransform && violations[i].Transformable {
+			transformed = append(transformed, violations[i])
+			continue
+		}
+
+		errs = append(errs, &violations[i])
+	}
+
+	if len(errs) != 0 {
+		return transformed, fmt.Errorf("%w: %w", ErrLimitExceeded, errors.Join(errs...))
+	}
+
+	return transformed, nil
+}
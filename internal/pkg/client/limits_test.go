@@ -0,0 +1,106 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUploader struct{}
+
+func (u *mockUploader) UploadEntries(_ context.Context, _ worklog.Entries, _ chan error, _ *client.UploadOpts) {
+}
+
+type mockLimitedUploader struct {
+	mockUploader
+	limits *client.FieldLimits
+}
+
+func (u *mockLimitedUploader) CheckLimits(entries worklog.Entries, _ *client.UploadOpts) ([]client.LimitViolation, error) {
+	var violations []client.LimitViolation
+
+	for _, entry := range entries {
+		if violation := u.limits.CheckComment(entry, entry.Summary); violation != nil {
+			violations = append(violations, *violation)
+		}
+
+		if violation := u.limits.CheckDate(entry, entry.Start); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	return violations, nil
+}
+
+func TestFieldLimits_TruncateComment(t *testing.T) {
+	limits := &client.FieldLimits{MaxCommentLength: 5}
+
+	require.Equal(t, "short", limits.TruncateComment("short"))
+	require.Equal(t, "long…", limits.TruncateComment("longer comment"))
+	require.Equal(t, "árví…", limits.TruncateComment("árvíztűrő"))
+
+	limits.MaxCommentLength = 1
+	require.Equal(t, "l", limits.TruncateComment("longer comment"))
+
+	limits.MaxCommentLength = 0
+	require.Equal(t, "longer comment", limits.TruncateComment("longer comment"))
+}
+
+func TestFieldLimits_CheckDate(t *testing.T) {
+	entry := getTestEntry()
+	limits := &client.FieldLimits{DateLayout: "2006-01-02"}
+
+	require.Nil(t, limits.CheckDate(entry, entry.Start))
+	require.NotNil(t, limits.CheckDate(entry, time.Time{}))
+	require.NotNil(t, limits.CheckDate(entry, time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	limits.DateLayout = ""
+	require.Nil(t, limits.CheckDate(entry, time.Time{}))
+}
+
+func TestFieldLimits_CheckAttribute(t *testing.T) {
+	entry := getTestEntry()
+	limits := &client.FieldLimits{AttributeValues: map[string][]string{"_Role_": {"Developer"}}}
+
+	require.Nil(t, limits.CheckAttribute(entry, "_Role_", "Developer"))
+	require.Nil(t, limits.CheckAttribute(entry, "_Team_", "Avengers"))
+
+	violation := limits.CheckAttribute(entry, "_Role_", "Captain")
+	require.NotNil(t, violation)
+	require.True(t, violation.Transformable)
+	require.Equal(t, entry.Key()+": _Role_: \"Captain\" is not part of the allowed values [Developer]", violation.Error())
+}
+
+func TestCheckLimits(t *testing.T) {
+	uploader := &mockLimitedUploader{limits: &client.FieldLimits{MaxCommentLength: 10, DateLayout: "2006-01-02"}}
+
+	entry := getTestEntry()
+	noDateEntry := getTestEntry()
+	noDateEntry.Summary = "Short"
+	noDateEntry.Start = time.Time{}
+
+	transformed, err := client.CheckLimits(uploader, worklog.Entries{entry}, &client.UploadOpts{LimitPolicy: client.LimitPolicyFail})
+	require.ErrorIs(t, err, client.ErrLimitExceeded)
+	require.Nil(t, transformed)
+
+	transformed, err = client.CheckLimits(uploader, worklog.Entries{entry}, &client.UploadOpts{LimitPolicy: client.LimitPolicyTransform})
+	require.Nil(t, err)
+	require.Len(t, transformed, 1)
+	require.Equal(t, "comment", transformed[0].Field)
+
+	_, err = client.CheckLimits(uploader, worklog.Entries{entry, noDateEntry}, &client.UploadOpts{LimitPolicy: client.LimitPolicyTransform})
+	require.ErrorIs(t, err, client.ErrLimitExceeded)
+	require.ErrorContains(t, err, "date is not set")
+
+	transformed, err = client.CheckLimits(uploader, worklog.Entries{entry, noDateEntry}, &client.UploadOpts{LimitPolicy: client.LimitPolicyIgnore})
+	require.Nil(t, err)
+	require.Nil(t, transformed)
+
+	transformed, err = client.CheckLimits(&mockUploader{}, worklog.Entries{entry}, &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Nil(t, transformed)
+}
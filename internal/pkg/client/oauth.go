@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// GrantTypeRefreshToken is the OAuth grant exchanging a refresh token to an
+	// access token.
+	GrantTypeRefreshToken string = "refresh_token"
+	// GrantTypeDeviceCode is the OAuth grant exchanging the device code of
+	// the device authorization flow to tokens.
+	GrantTypeDeviceCode string = "urn:ietf:params:oauth:grant-type:device_code"
+	// GrantTypeAuthorizationCode is the OAuth grant exchanging the code of
+	// the authorization code flow to tokens.
+	GrantTypeAuthorizationCode string = "authorization_code"
+
+	// DefaultDevicePollInterval is the interval of polling the token endpoint
+	// if the device authorization endpoint does not set it.
+	DefaultDevicePollInterval time.Duration = time.Second * 5
+	// devicePollSlowDown is added to the polling interval when the token
+	// endpoint asks to slow down.
+	devicePollSlowDown time.Duration = time.Second * 5
+
+	oauthErrAuthorizationPending string = "authorization_pending"
+	oauthErrSlowDown             string = "slow_down"
+	oauthErrAccessDenied         string = "access_denied"
+	oauthErrExpiredToken         string = "expired_token"
+)
+
+var (
+	// ErrOAuth returns when the OAuth credentials are missing or rejected.
+	ErrOAuth = errors.New("failed to authenticate by OAuth")
+	// ErrDeviceAccessDenied returns when the user denied the device
+	// authorization request.
+	ErrDeviceAccessDenied = errors.New("device authorization denied")
+	// ErrDeviceCodeExpired returns when the user did not complete the device
+	// authorization before the device code expired.
+	ErrDeviceCodeExpired = errors.New("device code expired")
+)
+
+// OAuthOpts represents the OAuth client and its endpoints.
+type OAuthOpts struct {
+	// TokenURL is the endpoint issuing the tokens.
+	TokenURL string
+	// DeviceCodeURL is the device authorization endpoint, used by the device
+	// authorization flow only.
+	DeviceCodeURL string
+	ClientID      string
+	ClientSecret  string
+	// RedirectURL is the redirect URI registered for the client, used by the
+	// authorization code flow only.
+	RedirectURL string
+	// Scope is the space separated list of the requested scopes.
+	Scope   string
+	Timeout time.Duration
+	// RotateRefreshToken is called with the new refresh token if the token
+	// endpoint rotates it when refreshing the access token, so the new token
+	// can be persisted for the next runs. The previous refresh token may be
+	// revoked by then.
+	RotateRefreshToken func(ctx context.Context, refreshToken string) error
+}
+
+// OAuthToken represents the response of the OAuth token endpoint.
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	// RefreshToken is returned by the device authorization flow, and it may
+	// be rotated when refreshing the access token.
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the lifetime of the access token in seconds.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// oauthError represents the error response of the OAuth endpoints.
+type oauthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// DeviceCode represents the response of the device authorization endpoint,
+// as defined by RFC 8628.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	// VerificationURL is set by Google instead of the VerificationURI.
+	VerificationURL string `json:"verification_url"`
+	// ExpiresIn is the lifetime of the device code in seconds.
+	ExpiresIn int `json:"expires_in"`
+	// Interval is the minimum number of seconds between polling the token
+	// endpoint.
+	Interval int `json:"interval"`
+}
+
+// Verification returns the URL the user must visit to enter the user code.
+func (d *DeviceCode) Verification() string {
+	if d.VerificationURI != "" {
+		return d.VerificationURI
+	}
+
+	return d.VerificationURL
+}
+
+// oauthErrorOf returns the OAuth error code of the failed request. If the
+// response is not an OAuth error, an empty string returns.
+func oauthErrorOf(err error) string {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return ""
+	}
+
+	var resp oauthError
+	if json.Unmarshal(httpErr.Body, &resp) != nil {
+		return ""
+	}
+
+	return resp.Error
+}
+
+// requestToken posts the form to the token endpoint, extended by the client
+// credentials.
+func requestToken(ctx context.Context, httpClient *HTTPClient, opts *OAuthOpts, form url.Values) (*OAuthToken, error) {
+	form.Set("client_id", opts.ClientID)
+	if opts.ClientSecret != "" {
+		form.Set("client_secret", opts.ClientSecret)
+	}
+
+	var token OAuthToken
+	err := httpClient.CallAndDecode(ctx, &HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     opts.TokenURL,
+		Data:    form,
+		Timeout: opts.Timeout,
+		Codec:   &FormCodec{},
+	}, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" {
+		return nil, ErrOAuth
+	}
+
+	return &token, nil
+}
+
+// NewOAuthRefreshFunc returns a TokenRefreshFunc exchanging the refresh token
+// to an access token at the token endpoint. If the token endpoint rotates the
+// refresh token, the new one is used for the next refreshes.
+func NewOAuthRefreshFunc(httpClient *HTTPClient, opts *OAuthOpts, refreshToken string) TokenRefreshFunc {
+	return func(ctx context.Context) (*AccessToken, error) {
+		form := url.Values{
+			"grant_type":    {GrantTypeRefreshToken},
+			"refresh_token": {refreshToken},
+		}
+
+		if opts.Scope != "" {
+			form.Set("scope", opts.Scope)
+		}
+
+		token, err := requestToken(ctx, httpClient, opts, form)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrOAuth, err)
+		}
+
+		if token.RefreshToken != "" && token.RefreshToken != refreshToken {
+			refreshToken = token.RefreshToken
+
+			if opts.RotateRefreshToken != nil {
+				if err = opts.RotateRefreshToken(ctx, refreshToken); err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrOAuth, err)
+				}
+			}
+		}
+
+		accessToken := &AccessToken{Token: token.AccessToken}
+		if token.ExpiresIn > 0 {
+			accessToken.ExpiresAt = time.Now().Add(time.Second * time.Duration(token.ExpiresIn))
+		}
+
+		return accessToken, nil
+	}
+}
+
+// ExchangeAuthorizationCode exchanges the code of the authorization code flow
+// to tokens. The code is returned to the RedirectURL after the user authorized
+// the client.
+func ExchangeAuthorizationCode(ctx context.Context, httpClient *HTTPClient, opts *OAuthOpts, code string) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type": {GrantTypeAuthorizationCode},
+		"code":       {code},
+	}
+
+	if opts.RedirectURL != "" {
+		form.Set("redirect_uri", opts.RedirectURL)
+	}
+
+	token, err := requestToken(ctx, httpClient, opts, form)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuth, err)
+	}
+
+	return token, nil
+}
+
+// RequestDeviceCode starts the OAuth device authorization flow. The user must
+// visit the verification URL and enter the user code of the returned device
+// code, then the tokens can be obtained by PollDeviceToken.
+func RequestDeviceCode(ctx context.Context, httpClient *HTTPClient, opts *OAuthOpts) (*DeviceCode, error) {
+	form := url.Values{
+		"client_id": {opts.ClientID},
+	}
+
+	if opts.Scope != "" {
+		form.Set("scope", opts.Scope)
+	}
+
+	var deviceCode DeviceCode
+	err := httpClient.CallAndDecode(ctx, &HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     opts.DeviceCodeURL,
+		Data:    form,
+		Timeout: opts.Timeout,
+		Codec:   &FormCodec{},
+	}, &deviceCode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuth, err)
+	}
+
+	if deviceCode.DeviceCode == "" || deviceCode.UserCode == "" {
+		return nil, ErrOAuth
+	}
+
+	return &deviceCode, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user completes the
+// device authorization, then it returns the issued tokens. Polling stops if
+// the user denied the authorization, the device code expired or the context
+// is done.
+func PollDeviceToken(ctx context.Context, httpClient *HTTPClient, opts *OAuthOpts, deviceCode *DeviceCode) (*OAuthToken, error) {
+	interval := DefaultDevicePollInterval
+	if deviceCode.Interval > 0 {
+		interval = time.Second * time.Duration(deviceCode.Interval)
+	}
+
+	if deviceCode.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(deviceCode.ExpiresIn))
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrDeviceCodeExpired
+			}
+
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		token, err := requestToken(ctx, httpClient, opts, url.Values{
+			"grant_type":  {GrantTypeDeviceCode},
+			"device_code": {deviceCode.DeviceCode},
+		})
+		if err == nil {
+			return token, nil
+		}
+
+		switch oauthErrorOf(err) {
+		case oauthErrAuthorizationPending:
+			continue
+		case oauthErrSlowDown:
+			interval += devicePollSlowDown
+		case oauthErrAccessDenied:
+			return nil, ErrDeviceAccessDenied
+		case oauthErrExpiredToken:
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, fmt.Errorf("%w: %w", ErrOAuth, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/stretchr/testify/require"
+)
+
+func newOAuthServer(t *testing.T, tokenResponses []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/device/code":
+			require.Equal(t, "calendar", r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{"device_code":"device-code","user_code":"ABCD-EFGH","verification_url":"https://example.com/device","expires_in":60,"interval":1}`))
+		case "/token":
+			require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+
+			if r.PostForm.Get("grant_type") == client.GrantTypeRefreshToken {
+				require.Equal(t, "refresh-token", r.PostForm.Get("refresh_token"))
+				_, _ = w.Write([]byte(`{"access_token":"access-token","expires_in":3600}`))
+				return
+			}
+
+			require.Equal(t, client.GrantTypeDeviceCode, r.PostForm.Get("grant_type"))
+			require.Equal(t, "device-code", r.PostForm.Get("device_code"))
+			require.NotEmpty(t, tokenResponses, "unexpected token request")
+
+			response := tokenResponses[0]
+			tokenResponses = tokenResponses[1:]
+
+			if response != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"` + response + `"}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"access_token":"access-token","refresh_token":"refresh-token","expires_in":3600}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+func newOAuthOpts(serverURL string) *client.OAuthOpts {
+	return &client.OAuthOpts{
+		TokenURL:      serverURL + "/token",
+		DeviceCodeURL: serverURL + "/device/code",
+		ClientID:      "client-id",
+		ClientSecret:  "client-secret",
+		Scope:         "calendar",
+		Timeout:       client.DefaultRequestTimeout,
+	}
+}
+
+func TestDeviceFlow(t *testing.T) {
+	mockServer := newOAuthServer(t, []string{"authorization_pending", ""})
+	defer mockServer.Close()
+
+	httpClient := &client.HTTPClient{}
+	opts := newOAuthOpts(mockServer.URL)
+
+	deviceCode, err := client.RequestDeviceCode(context.Background(), httpClient, opts)
+	require.Nil(t, err)
+	require.Equal(t, "ABCD-EFGH", deviceCode.UserCode)
+	require.Equal(t, "https://example.com/device", deviceCode.Verification())
+
+	token, err := client.PollDeviceToken(context.Background(), httpClient, opts, deviceCode)
+	require.Nil(t, err)
+	require.Equal(t, &client.OAuthToken{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresIn:    3600,
+	}, token)
+}
+
+func TestDeviceFlow_AccessDenied(t *testing.T) {
+	mockServer := newOAuthServer(t, []string{"access_denied"})
+	defer mockServer.Close()
+
+	_, err := client.PollDeviceToken(context.Background(), &client.HTTPClient{}, newOAuthOpts(mockServer.URL), &client.DeviceCode{
+		DeviceCode: "device-code",
+		Interval:   1,
+	})
+	require.ErrorIs(t, err, client.ErrDeviceAccessDenied)
+}
+
+func TestNewOAuthRefreshFunc(t *testing.T) {
+	mockServer := newOAuthServer(t, nil)
+	defer mockServer.Close()
+
+	refresh := client.NewOAuthRefreshFunc(&client.HTTPClient{}, newOAuthOpts(mockServer.URL), "refresh-token")
+
+	token, err := refresh(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "access-token", token.Token)
+	require.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func TestNewOAuthRefreshFunc_Rotation(t *testing.T) {
+	var refreshTokens []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, client.GrantTypeRefreshToken, r.PostForm.Get("grant_type"))
+		refreshTokens = append(refreshTokens, r.PostForm.Get("refresh_token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-token","refresh_token":"refresh-token-` + strconv.Itoa(len(refreshTokens)) + `","expires_in":3600}`))
+	}))
+	defer mockServer.Close()
+
+	var rotated []string
+	opts := newOAuthOpts(mockServer.URL)
+	opts.RotateRefreshToken = func(_ context.Context, refreshToken string) error {
+		rotated = append(rotated, refreshToken)
+		return nil
+	}
+
+	refresh := client.NewOAuthRefreshFunc(&client.HTTPClient{}, opts, "refresh-token")
+
+	for i := 0; i < 2; i++ {
+		_, err := refresh(context.Background())
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, []string{"refresh-token", "refresh-token-1"}, refreshTokens)
+	require.Equal(t, []string{"refresh-token-1", "refresh-token-2"}, rotated)
+}
+
+func TestExchangeAuthorizationCode(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, client.GrantTypeAuthorizationCode, r.PostForm.Get("grant_type"))
+		require.Equal(t, "code", r.PostForm.Get("code"))
+		require.Equal(t, "https://example.com/callback", r.PostForm.Get("redirect_uri"))
+		require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-token","refresh_token":"refresh-token","expires_in":3600}`))
+	}))
+	defer mockServer.Close()
+
+	opts := newOAuthOpts(mockServer.URL)
+	opts.RedirectURL = "https://example.com/callback"
+
+	token, err := client.ExchangeAuthorizationCode(context.Background(), &client.HTTPClient{}, opts, "code")
+	require.Nil(t, err)
+	require.Equal(t, "refresh-token", token.RefreshToken)
+}
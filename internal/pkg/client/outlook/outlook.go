@@ -0,0 +1,322 @@
+package outlook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the Microsoft Graph API.
+	DefaultURL string = "https://graph.microsoft.com"
+	// DefaultTokenURL is the endpoint issuing the Microsoft access tokens.
+	DefaultTokenURL string = "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+	// DefaultDeviceCodeURL is the device authorization endpoint of Microsoft.
+	DefaultDeviceCodeURL string = "https://login.microsoftonline.com/common/oauth2/v2.0/devicecode"
+	// Scope is the scope requested to read the events. The offline_access
+	// scope is required to obtain a refresh token.
+	Scope string = "offline_access Calendars.Read"
+
+	// PathCalendarView is the endpoint used to list the events of the user's
+	// default calendar, including the occurrences of the recurring events.
+	PathCalendarView string = "/v1.0/me/calendarView"
+	// PathCalendarViewOf is the endpoint used to list the events of a
+	// calendar, including the occurrences of the recurring events.
+	PathCalendarViewOf string = "/v1.0/me/calendars/%s/calendarView"
+	// MaxPageSize is the number of events requested on a page.
+	MaxPageSize int = 100
+
+	// AttributeAttendees is the name of the attribute listing the e-mail
+	// addresses of the attendees of the event.
+	AttributeAttendees string = "outlook.attendees"
+	// AttributeCategories is the name of the attribute listing the
+	// categories of the event, separated by commas.
+	AttributeCategories string = "outlook.categories"
+
+	// selectedFields lists the fields of the events returned by Graph.
+	selectedFields string = "id,subject,bodyPreview,start,end,isAllDay,isCancelled,categories,attendees,responseStatus,webLink"
+
+	responseDeclined string = "declined"
+)
+
+// DateTimeTimeZone represents the start or end of an event. The time zone is
+// UTC, as requested by the Prefer header.
+type DateTimeTimeZone struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// Time returns the parsed date and time.
+func (d *DateTimeTimeZone) Time() (time.Time, error) {
+	return utils.ParseTimestamp(d.DateTime, time.UTC)
+}
+
+// EmailAddress represents the e-mail address of an attendee.
+type EmailAddress struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// Attendee represents an attendee of the event.
+type Attendee struct {
+	EmailAddress EmailAddress `json:"emailAddress"`
+}
+
+// ResponseStatus represents the response of the user to the event.
+type ResponseStatus struct {
+	Response string `json:"response"`
+}
+
+// Event represents an event of the calendar.
+type Event struct {
+	ID             string           `json:"id"`
+	Subject        string           `json:"subject"`
+	BodyPreview    string           `json:"bodyPreview"`
+	Start          DateTimeTimeZone `json:"start"`
+	End            DateTimeTimeZone `json:"end"`
+	IsAllDay       bool             `json:"isAllDay"`
+	IsCancelled    bool             `json:"isCancelled"`
+	Categories     []string         `json:"categories"`
+	Attendees      []Attendee       `json:"attendees"`
+	ResponseStatus ResponseStatus   `json:"responseStatus"`
+	WebLink        string           `json:"webLink"`
+}
+
+// FetchResponse represents a page of the listed events.
+type FetchResponse struct {
+	Value    []Event `json:"value"`
+	NextLink string  `json:"@odata.nextLink"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// TokenURL is the endpoint used to refresh the access token. If not set,
+	// DefaultTokenURL is used.
+	TokenURL string
+	// CalendarIDs lists the calendars the events are read from. If empty, the
+	// default calendar of the user is read.
+	CalendarIDs []string
+	ClientID    string
+	// ClientSecret is not set for public clients, like the ones authorized
+	// by the device authorization flow.
+	ClientSecret string
+	// RefreshToken is the OAuth refresh token of the user, obtained by the
+	// device authorization flow.
+	RefreshToken string
+}
+
+type outlookClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	calendarIDs []string
+	auth        *client.RefreshingTokenAuth
+}
+
+func (c *outlookClient) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	return c.auth.WarmUp(ctx, d)
+}
+
+func (c *outlookClient) CredentialsExpireAt() time.Time {
+	return c.auth.ExpiresAt()
+}
+
+// attendees returns the sorted e-mail addresses of the attendees of the event.
+// The organizer is not listed by Graph as attendee.
+func attendees(event *Event) []string {
+	var addresses []string
+
+	for _, attendee := range event.Attendees {
+		if attendee.EmailAddress.Address != "" {
+			addresses = append(addresses, attendee.EmailAddress.Address)
+		}
+	}
+
+	sort.Strings(addresses)
+	return addresses
+}
+
+// parseEvents converts the events to entries. The cancelled, declined and
+// all-day events are skipped, since they do not stand for time spent.
+func parseEvents(events []Event) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for i := range events {
+		event := &events[i]
+
+		if event.IsCancelled || event.IsAllDay || event.ResponseStatus.Response == responseDeclined {
+			continue
+		}
+
+		start, err := event.Start.Time()
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := event.End.Time()
+		if err != nil {
+			return nil, err
+		}
+
+		entry := worklog.Entry{
+			Summary:          event.Subject,
+			Notes:            event.BodyPreview,
+			Start:            start.Local(),
+			BillableDuration: end.Sub(start),
+			Provenance:       worklog.Provenance{SourceIDs: []string{event.ID}},
+		}
+
+		entry.AddLinks(utils.ExtractURLs(event.BodyPreview)...)
+		entry.AddSourceURL(event.WebLink)
+
+		// The categories and the attendees are the tags of the event
+		var tags []worklog.IDNameField
+
+		if len(event.Categories) != 0 {
+			entry.SetAttribute(AttributeCategories, strings.Join(event.Categories, ", "))
+		}
+
+		for _, category := range event.Categories {
+			tags = append(tags, worklog.IDNameField{ID: category, Name: category})
+		}
+
+		eventAttendees := attendees(event)
+		if len(eventAttendees) != 0 {
+			entry.SetAttribute(AttributeAttendees, strings.Join(eventAttendees, " "))
+		}
+
+		for _, attendee := range eventAttendees {
+			tags = append(tags, worklog.IDNameField{ID: attendee, Name: attendee})
+		}
+
+		entry.TaskHints = &worklog.TaskHints{
+			Texts: []string{event.Subject},
+			Tags:  tags,
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// calendarViewPath returns the path of the calendar view of the calendar. If
+// the calendar ID is empty, the path of the default calendar returns.
+func calendarViewPath(calendarID string) string {
+	if calendarID == "" {
+		return PathCalendarView
+	}
+
+	return fmt.Sprintf(PathCalendarViewOf, url.PathEscape(calendarID))
+}
+
+// fetchEvents fetches every page of the calendar view between the start and
+// end of the fetch window. The calendar view expands the recurring events to
+// their occurrences.
+func (c *outlookClient) fetchEvents(ctx context.Context, calendarID string, opts *client.FetchOpts) ([]Event, error) {
+	fetchURL, err := c.URL(calendarViewPath(calendarID), map[string]string{
+		"startDateTime": opts.Start.UTC().Format(time.RFC3339),
+		"endDateTime":   opts.End.UTC().Format(time.RFC3339),
+		"$select":       selectedFields,
+		"$orderby":      "start/dateTime",
+		"$top":          fmt.Sprint(MaxPageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+
+	// The next pages are requested by the absolute next links of the
+	// responses, which keep the query parameters of the first page
+	for fetchURL != "" {
+		var fetchResponse FetchResponse
+		err = c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodGet,
+			Url:     fetchURL,
+			Auth:    c.auth,
+			Timeout: c.Timeout,
+			Headers: map[string]string{
+				"Prefer": `outlook.timezone="UTC"`,
+			},
+		}, &fetchResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, fetchResponse.Value...)
+		fetchURL = fetchResponse.NextLink
+	}
+
+	return events, nil
+}
+
+func (c *outlookClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, calendarID := range c.calendarIDs {
+		events, err := c.fetchEvents(ctx, calendarID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		calendarEntries, err := parseEvents(events)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		entries = append(entries, calendarEntries...)
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new Outlook client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.ClientID == "" || opts.RefreshToken == "" {
+		return nil, errors.New("no Outlook OAuth client ID or refresh token provided")
+	}
+
+	tokenURL := opts.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+
+	// The default calendar is read if no calendars are set
+	calendarIDs := opts.CalendarIDs
+	if len(calendarIDs) == 0 {
+		calendarIDs = []string{""}
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &client.HTTPClient{BaseURL: baseURL}
+
+	refresh := client.NewOAuthRefreshFunc(httpClient, &client.OAuthOpts{
+		TokenURL:     tokenURL,
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		Scope:        Scope,
+		Timeout:      opts.Timeout,
+	}, opts.RefreshToken)
+
+	return &outlookClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient:     httpClient,
+		calendarIDs:    calendarIDs,
+		auth:           client.NewRefreshingTokenAuth("Authorization", "Bearer", refresh),
+	}, nil
+}
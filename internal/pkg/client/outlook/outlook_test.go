@@ -0,0 +1,220 @@
+package outlook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/outlook"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2021, 10, 2, hour, minute, 0, 0, time.UTC)
+}
+
+func graphTime(t time.Time) outlook.DateTimeTimeZone {
+	return outlook.DateTimeTimeZone{DateTime: t.Format("2006-01-02T15:04:05.0000000"), TimeZone: "UTC"}
+}
+
+func newMockServer(t *testing.T, pages map[string][]outlook.FetchResponse) *httptest.Server {
+	var mockServer *httptest.Server
+
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/token" {
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, "refresh-token", r.PostForm.Get("refresh_token"))
+			require.Equal(t, outlook.Scope, r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{"access_token":"access-token","expires_in":3600}`))
+			return
+		}
+
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+		require.Equal(t, `outlook.timezone="UTC"`, r.Header.Get("Prefer"))
+		require.Equal(t, "2021-10-02T00:00:00Z", r.URL.Query().Get("startDateTime"))
+		require.Equal(t, "2021-10-03T00:00:00Z", r.URL.Query().Get("endDateTime"))
+
+		calendarPages, ok := pages[r.URL.Path]
+		require.True(t, ok, "unexpected path %s", r.URL.Path)
+
+		page := calendarPages[0]
+		if r.URL.Query().Get("$skip") != "" {
+			page = calendarPages[1]
+		} else if len(calendarPages) > 1 {
+			page.NextLink = mockServer.URL + r.URL.Path + "?" + r.URL.RawQuery + "&$skip=100"
+		}
+
+		require.Nil(t, json.NewEncoder(w).Encode(page))
+	}))
+
+	return mockServer
+}
+
+func newTestFetcher(t *testing.T, serverURL string, calendarIDs []string) client.Fetcher {
+	fetcher, err := outlook.NewFetcher(&outlook.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      serverURL,
+		TokenURL:     serverURL + "/token",
+		CalendarIDs:  calendarIDs,
+		ClientID:     "client-id",
+		RefreshToken: "refresh-token",
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestOutlookClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, map[string][]outlook.FetchResponse{
+		"/v1.0/me/calendarView": {
+			{
+				Value: []outlook.Event{
+					{
+						ID:          "event-1",
+						Subject:     "CPT-123 planning",
+						BodyPreview: "Agenda: https://example.com/agenda",
+						Start:       graphTime(at(9, 0)),
+						End:         graphTime(at(10, 0)),
+						Categories:  []string{"Blue category"},
+						Attendees: []outlook.Attendee{
+							{EmailAddress: outlook.EmailAddress{Name: "Bob", Address: "bob@example.com"}},
+							{EmailAddress: outlook.EmailAddress{Name: "Alice", Address: "alice@example.com"}},
+						},
+						WebLink: "https://outlook.office365.com/owa/?itemid=1",
+					},
+					{
+						ID:       "all-day",
+						Subject:  "Conference",
+						IsAllDay: true,
+						Start:    graphTime(at(0, 0)),
+						End:      graphTime(at(0, 0).AddDate(0, 0, 1)),
+					},
+				},
+			},
+			{
+				Value: []outlook.Event{
+					{
+						ID:             "declined",
+						Subject:        "Lunch and learn",
+						Start:          graphTime(at(12, 0)),
+						End:            graphTime(at(13, 0)),
+						ResponseStatus: outlook.ResponseStatus{Response: "declined"},
+					},
+					{
+						ID:          "cancelled",
+						Subject:     "Retro",
+						IsCancelled: true,
+						Start:       graphTime(at(14, 0)),
+						End:         graphTime(at(15, 0)),
+					},
+					{
+						// An occurrence of a recurring event
+						ID:      "standup-occurrence",
+						Subject: "Standup",
+						Start:   graphTime(at(9, 30)),
+						End:     graphTime(at(9, 45)),
+					},
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	expectedEntries := worklog.Entries{
+		{
+			Task:             worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"},
+			Summary:          "CPT-123 planning",
+			Notes:            "Agenda: https://example.com/agenda",
+			Start:            at(9, 0).Local(),
+			BillableDuration: time.Hour,
+			Links:            []string{"https://example.com/agenda", "https://outlook.office365.com/owa/?itemid=1"},
+			Attributes: map[string]string{
+				outlook.AttributeCategories: "Blue category",
+				outlook.AttributeAttendees:  "alice@example.com bob@example.com",
+			},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"event-1"},
+				SourceURLs: []string{"https://outlook.office365.com/owa/?itemid=1"},
+			},
+		},
+		{
+			Summary:          "Standup",
+			Start:            at(9, 30).Local(),
+			BillableDuration: time.Minute * 15,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"standup-occurrence"}},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL, nil).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(0, 0).AddDate(0, 0, 1),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^[A-Z]{2,7}-\d{1,6}`), worklog.TaskExtractionTags)
+	require.Equal(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestOutlookClient_FetchEntries_Calendars(t *testing.T) {
+	mockServer := newMockServer(t, map[string][]outlook.FetchResponse{
+		"/v1.0/me/calendars/calendar-1/calendarView": {
+			{
+				Value: []outlook.Event{
+					{
+						ID:         "event-1",
+						Subject:    "Customer calls",
+						Start:      graphTime(at(9, 0)),
+						End:        graphTime(at(10, 0)),
+						Categories: []string{"ACME", "Globex", "Internal"},
+					},
+				},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, []string{"calendar-1"}).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(0, 0).AddDate(0, 0, 1),
+	})
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^(ACME|Globex)$`), worklog.TaskExtractionTags)
+
+	require.Len(t, entries, 2)
+	require.Equal(t, "ACME", entries[0].Task.Name)
+	require.Equal(t, time.Minute*30, entries[0].BillableDuration)
+	require.Equal(t, "Globex", entries[1].Task.Name)
+	require.Equal(t, "ACME, Globex, Internal", entries[1].Attributes[outlook.AttributeCategories])
+}
+
+func TestOutlookClient_FetchEntries_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	_, err := newTestFetcher(t, mockServer.URL, nil).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(0, 0),
+		End:   at(0, 0).AddDate(0, 0, 1),
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoRefreshToken(t *testing.T) {
+	_, err := outlook.NewFetcher(&outlook.ClientOpts{
+		BaseURL:  outlook.DefaultURL,
+		ClientID: "client-id",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,267 @@
+package personio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathAuth is the API endpoint used to exchange the client credentials
+	// to an access token.
+	PathAuth string = "/v1/auth"
+	// PathTimeOffs is the API endpoint used to search time off periods.
+	PathTimeOffs string = "/v1/company/time-offs"
+
+	// StatusApproved is the status of the approved time off periods.
+	StatusApproved string = "approved"
+
+	timeOffPageSize int = 200
+)
+
+var (
+	// ErrAuthentication returns when the client credentials are rejected.
+	ErrAuthentication = errors.New("failed to authenticate")
+)
+
+// TimeOffType represents the type of the time off, like "Vacation".
+type TimeOffType struct {
+	Attributes struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"attributes"`
+}
+
+// TimeOffAttributes represents the attributes of a time off period.
+type TimeOffAttributes struct {
+	ID           int             `json:"id"`
+	Status       string          `json:"status"`
+	Comment      string          `json:"comment"`
+	StartDate    utils.Timestamp `json:"start_date"`
+	EndDate      utils.Timestamp `json:"end_date"`
+	HalfDayStart bool            `json:"half_day_start"`
+	HalfDayEnd   bool            `json:"half_day_end"`
+	TimeOffType  TimeOffType     `json:"time_off_type"`
+}
+
+// FetchEntry represents the time off period fetched from Personio.
+type FetchEntry struct {
+	Type       string            `json:"type"`
+	Attributes TimeOffAttributes `json:"attributes"`
+}
+
+// FetchResponse represents the response of the time off search.
+type FetchResponse struct {
+	Success bool         `json:"success"`
+	Data    []FetchEntry `json:"data"`
+}
+
+// AuthRequest represents the request exchanging the client credentials to an
+// access token. The credentials are sent in the body, so they are not logged
+// by proxies and servers like the URLs.
+type AuthRequest struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// AuthResponse represents the response of the authentication.
+type AuthResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Token string `json:"token"`
+		// ExpiresIn is the lifetime of the token in seconds. If not returned,
+		// the token is refreshed only when it is rejected.
+		ExpiresIn int `json:"expires_in"`
+	} `json:"data"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL      string
+	ClientID     string
+	ClientSecret string
+	// AbsenceDuration is the duration of a full day time off.
+	AbsenceDuration time.Duration
+}
+
+type personioClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	clientID        string
+	clientSecret    string
+	absenceDuration time.Duration
+	auth            *client.RefreshingTokenAuth
+}
+
+// authenticate exchanges the client credentials to an access token.
+func (c *personioClient) authenticate(ctx context.Context) (*client.AccessToken, error) {
+	authURL, err := c.URL(PathAuth, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method: http.MethodPost,
+		Url:    authURL,
+		Data: &AuthRequest{
+			ClientID:     c.clientID,
+			ClientSecret: c.clientSecret,
+		},
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept":       "application/json",
+			"Content-Type": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var authResponse AuthResponse
+	if err = json.Unmarshal(resp, &authResponse); err != nil {
+		return nil, err
+	}
+
+	if !authResponse.Success || authResponse.Data.Token == "" {
+		return nil, ErrAuthentication
+	}
+
+	token := &client.AccessToken{Token: authResponse.Data.Token}
+	if authResponse.Data.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Second * time.Duration(authResponse.Data.ExpiresIn))
+	}
+
+	return token, nil
+}
+
+func (c *personioClient) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	return c.auth.WarmUp(ctx, d)
+}
+
+func (c *personioClient) CredentialsExpireAt() time.Time {
+	return c.auth.ExpiresAt()
+}
+
+// parseEntry creates an absence entry per working day of the time off period.
+// The first and last day can be half days.
+func (c *personioClient) parseEntry(entry FetchEntry) worklog.Entries {
+	attributes := entry.Attributes
+
+	// The end date is the start of the last day, so the full last day must be
+	// covered by the absence period
+	entries := worklog.NewAbsenceEntries(&worklog.AbsenceOpts{
+		Absence:     worklog.ParseAbsence(attributes.TimeOffType.Attributes.Name),
+		Summary:     attributes.TimeOffType.Attributes.Name,
+		Notes:       attributes.Comment,
+		Start:       attributes.StartDate.Time,
+		End:         attributes.EndDate.AddDate(0, 0, 1),
+		DayDuration: c.absenceDuration,
+		SourceID:    strconv.Itoa(attributes.ID),
+	})
+
+	for i := range entries {
+		isStartDay := utils.DateFormatISO8601.Format(entries[i].Start) == utils.DateFormatISO8601.Format(attributes.StartDate.Time)
+		isEndDay := utils.DateFormatISO8601.Format(entries[i].Start) == utils.DateFormatISO8601.Format(attributes.EndDate.Time)
+
+		if (isStartDay && attributes.HalfDayStart) || (isEndDay && attributes.HalfDayEnd) {
+			entries[i].UnbillableDuration /= 2
+		}
+	}
+
+	return entries
+}
+
+func (c *personioClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for offset := 0; ; offset += timeOffPageSize {
+		params := map[string]string{
+			"start_date": utils.DateFormatISO8601.Format(opts.Start),
+			"end_date":   utils.DateFormatISO8601.Format(opts.LastDay()),
+			"limit":      strconv.Itoa(timeOffPageSize),
+			"offset":     strconv.Itoa(offset),
+		}
+
+		if opts.User != "" {
+			params["employees[]"] = opts.User
+		}
+
+		searchURL, err := c.URL(PathTimeOffs, params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+			Method:  http.MethodGet,
+			Url:     searchURL,
+			Auth:    c.auth,
+			Timeout: c.Timeout,
+			Headers: map[string]string{
+				"Accept": "application/json",
+			},
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		var fetchResponse FetchResponse
+		if err = json.Unmarshal(resp, &fetchResponse); err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		for _, entry := range fetchResponse.Data {
+			if entry.Attributes.Status != StatusApproved {
+				continue
+			}
+
+			entries = append(entries, c.parseEntry(entry)...)
+		}
+
+		if len(fetchResponse.Data) < timeOffPageSize {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// NewFetcher returns a new Personio client for fetching approved time off
+// periods as absence entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ClientID == "" || opts.ClientSecret == "" {
+		return nil, ErrAuthentication
+	}
+
+	absenceDuration := opts.AbsenceDuration
+	if absenceDuration == 0 {
+		absenceDuration = worklog.DefaultAbsenceDuration
+	}
+
+	personioClient := &personioClient{
+		HTTPClient:      &client.HTTPClient{BaseURL: baseURL},
+		BaseClientOpts:  &opts.BaseClientOpts,
+		clientID:        opts.ClientID,
+		clientSecret:    opts.ClientSecret,
+		absenceDuration: absenceDuration,
+	}
+
+	personioClient.auth = client.NewRefreshingTokenAuth("Authorization", "Bearer", personioClient.authenticate)
+
+	return personioClient, nil
+}
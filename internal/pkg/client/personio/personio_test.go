@@ -0,0 +1,201 @@
+package personio_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/personio"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func newTimeOff(id int, status string, typeName string, start time.Time, end time.Time, halfDayStart bool, halfDayEnd bool) personio.FetchEntry {
+	entry := personio.FetchEntry{
+		Type: "TimeOffPeriod",
+		Attributes: personio.TimeOffAttributes{
+			ID:           id,
+			Status:       status,
+			StartDate:    utils.Timestamp{Time: start},
+			EndDate:      utils.Timestamp{Time: end},
+			HalfDayStart: halfDayStart,
+			HalfDayEnd:   halfDayEnd,
+		},
+	}
+
+	entry.Attributes.TimeOffType.Attributes.Name = typeName
+
+	return entry
+}
+
+func TestPersonioClient_FetchEntries(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case personio.PathAuth:
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.Empty(t, r.URL.Query())
+
+			var authRequest personio.AuthRequest
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&authRequest))
+			require.Equal(t, personio.AuthRequest{ClientID: "client-id", ClientSecret: "client-secret"}, authRequest)
+
+			response := personio.AuthResponse{Success: true}
+			response.Data.Token = "t-o-k-e-n"
+			_ = json.NewEncoder(w).Encode(response)
+		case personio.PathTimeOffs:
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, "Bearer t-o-k-e-n", r.Header.Get("Authorization"))
+			require.Equal(t, "2021-10-01", r.URL.Query().Get("start_date"))
+			require.Equal(t, "2021-10-31", r.URL.Query().Get("end_date"))
+			require.Equal(t, "42", r.URL.Query().Get("employees[]"))
+
+			_ = json.NewEncoder(w).Encode(personio.FetchResponse{
+				Success: true,
+				Data: []personio.FetchEntry{
+					newTimeOff(
+						1,
+						personio.StatusApproved,
+						"Paid vacation",
+						time.Date(2021, 10, 8, 0, 0, 0, 0, time.UTC),
+						time.Date(2021, 10, 12, 0, 0, 0, 0, time.UTC),
+						true,
+						true,
+					),
+					newTimeOff(
+						2,
+						personio.StatusApproved,
+						"Sick days",
+						time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC),
+						time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC),
+						false,
+						false,
+					),
+					newTimeOff(
+						3,
+						"pending",
+						"Paid vacation",
+						time.Date(2021, 10, 20, 0, 0, 0, 0, time.UTC),
+						time.Date(2021, 10, 20, 0, 0, 0, 0, time.UTC),
+						false,
+						false,
+					),
+				},
+			})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	personioClient, err := personio.NewFetcher(&personio.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:         mockServer.URL,
+		ClientID:        "client-id",
+		ClientSecret:    "client-secret",
+		AbsenceDuration: time.Hour * 8,
+	})
+	require.Nil(t, err)
+
+	entries, err := personioClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "42",
+		Start: start,
+		End:   end,
+	})
+	require.Nil(t, err, "cannot fetch entries")
+
+	newEntry := func(sourceID string, summary string, day int, duration time.Duration, absence worklog.Absence) worklog.Entry {
+		return worklog.Entry{
+			Summary:            summary,
+			Start:              time.Date(2021, 10, day, 0, 0, 0, 0, time.UTC),
+			UnbillableDuration: duration,
+			Absence:            absence,
+			Provenance:         worklog.Provenance{SourceIDs: []string{sourceID}},
+		}
+	}
+
+	require.Equal(t, worklog.Entries{
+		newEntry("1", "Paid vacation", 8, time.Hour*4, worklog.AbsenceVacation),
+		newEntry("1", "Paid vacation", 11, time.Hour*8, worklog.AbsenceVacation),
+		newEntry("1", "Paid vacation", 12, time.Hour*4, worklog.AbsenceVacation),
+		newEntry("2", "Sick days", 14, time.Hour*8, worklog.AbsenceSick),
+	}, entries)
+}
+
+func TestPersonioClient_FetchEntries_AuthFailure(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(personio.AuthResponse{Success: false})
+	}))
+	defer mockServer.Close()
+
+	personioClient, err := personio.NewFetcher(&personio.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      mockServer.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	require.Nil(t, err)
+
+	_, err = personioClient.FetchEntries(context.Background(), &client.FetchOpts{})
+	require.ErrorContains(t, err, personio.ErrAuthentication.Error())
+}
+
+func TestPersonioClient_FetchEntries_Reauthenticate(t *testing.T) {
+	tokens := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case personio.PathAuth:
+			tokens++
+
+			response := personio.AuthResponse{Success: true}
+			response.Data.Token = "token-" + strconv.Itoa(tokens)
+			response.Data.ExpiresIn = 3600
+			_ = json.NewEncoder(w).Encode(response)
+		case personio.PathTimeOffs:
+			// The token obtained by the warm-up expires during the run
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(personio.FetchResponse{Success: true})
+		}
+	}))
+	defer mockServer.Close()
+
+	personioClient, err := personio.NewFetcher(&personio.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:      mockServer.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	require.Nil(t, err)
+
+	warmer, ok := personioClient.(client.CredentialWarmer)
+	require.True(t, ok)
+
+	require.Nil(t, warmer.WarmUpCredentials(context.Background(), time.Minute*30))
+	require.Nil(t, warmer.WarmUpCredentials(context.Background(), time.Minute*30))
+	require.Equal(t, 1, tokens)
+
+	entries, err := personioClient.FetchEntries(context.Background(), &client.FetchOpts{})
+	require.Nil(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, 2, tokens)
+}
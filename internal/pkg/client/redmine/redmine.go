@@ -0,0 +1,259 @@
+package redmine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathTimeEntries is the endpoint used to search time entries.
+	PathTimeEntries string = "/time_entries.json"
+	// PathIssue is the path of the issues on the web interface.
+	PathIssue string = "/issues/%d"
+	// PathTimeEntry is the path of the time entries on the web interface.
+	PathTimeEntry string = "/time_entries/%d/edit"
+	// HeaderAPIKey is the header used to send the API key.
+	HeaderAPIKey string = "X-Redmine-API-Key"
+	// MaxPageSize is the maximum number of time entries returned on a page.
+	MaxPageSize int = 100
+	// CurrentUser is the user ID referring to the owner of the API key.
+	CurrentUser string = "me"
+
+	// AttributeActivity is the name of the attribute set to the activity of
+	// the time entries, like "Development".
+	AttributeActivity string = "redmine.activity"
+)
+
+// Issue represents the issue a time entry is logged on. The subject is not
+// returned by the time entries endpoint.
+type Issue struct {
+	ID int `json:"id"`
+}
+
+// FetchEntry represents the time entry fetched from Redmine. The spent on
+// date is in YYYY-MM-DD format and the hours may be fractional.
+type FetchEntry struct {
+	ID        int                    `json:"id"`
+	Project   worklog.IntIDNameField `json:"project"`
+	Issue     *Issue                 `json:"issue"`
+	User      worklog.IntIDNameField `json:"user"`
+	Activity  worklog.IntIDNameField `json:"activity"`
+	Hours     float64                `json:"hours"`
+	Comments  string                 `json:"comments"`
+	SpentOn   string                 `json:"spent_on"`
+	CreatedOn utils.Timestamp        `json:"created_on"`
+}
+
+// Start returns the start date created from the spent on date and the time of
+// the creation, since Redmine does not record when the work started.
+func (e *FetchEntry) Start() (time.Time, error) {
+	spentOn, err := utils.DateFormatISO8601.Parse(e.SpentOn)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	createdOn := e.CreatedOn.Local()
+
+	return time.Date(
+		spentOn.Year(),
+		spentOn.Month(),
+		spentOn.Day(),
+		createdOn.Hour(),
+		createdOn.Minute(),
+		createdOn.Second(),
+		0,
+		time.Local,
+	), nil
+}
+
+// FetchResponse represents the page of time entries. The total count is the
+// number of time entries matching the filters on every page.
+type FetchResponse struct {
+	TimeEntries []FetchEntry `json:"time_entries"`
+	TotalCount  int          `json:"total_count"`
+	Offset      int          `json:"offset"`
+	Limit       int          `json:"limit"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// APIKey is the API access key shown on the account page.
+	APIKey string
+	// UnbillableActivities lists the names of the activities, like "Support",
+	// the time entries of which are unbillable. The names are matched case
+	// insensitively. The time entries of other activities are billable.
+	UnbillableActivities []string
+}
+
+type redmineClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator        client.Authenticator
+	unbillableActivities map[string]bool
+}
+
+func (c *redmineClient) isUnbillable(activity string) bool {
+	return c.unbillableActivities[strings.ToLower(activity)]
+}
+
+func (c *redmineClient) parseEntries(fetchedEntries []FetchEntry, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, fetchedEntry := range fetchedEntries {
+		startDate, err := fetchedEntry.Start()
+		if err != nil {
+			return nil, err
+		}
+
+		billableDuration, err := utils.ParseDuration(strconv.FormatFloat(fetchedEntry.Hours, 'f', -1, 64), time.Hour)
+		if err != nil {
+			return nil, err
+		}
+
+		unbillableDuration := time.Duration(0)
+		if c.isUnbillable(fetchedEntry.Activity.Name) {
+			unbillableDuration = billableDuration
+			billableDuration = 0
+		}
+
+		entryURL, err := c.URL(fmt.Sprintf(PathTimeEntry, fetchedEntry.ID), map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+
+		entry := worklog.Entry{
+			Project:            fetchedEntry.Project.ConvertToIDNameField(),
+			Summary:            fetchedEntry.Comments,
+			Notes:              fetchedEntry.Comments,
+			Start:              startDate,
+			BillableDuration:   billableDuration,
+			UnbillableDuration: unbillableDuration,
+			Links:              []string{entryURL},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{strconv.Itoa(fetchedEntry.ID)},
+				SourceURLs: []string{entryURL},
+			},
+		}
+
+		if fetchedEntry.Issue != nil {
+			issueURL, err := c.URL(fmt.Sprintf(PathIssue, fetchedEntry.Issue.ID), map[string]string{})
+			if err != nil {
+				return nil, err
+			}
+
+			issueID := strconv.Itoa(fetchedEntry.Issue.ID)
+			entry.Task = worklog.IDNameField{ID: issueID, Name: "#" + issueID}
+			entry.AddLinks(issueURL)
+		}
+
+		// The comments of the time entries are optional, hence the activity is
+		// used instead
+		if entry.Summary == "" {
+			entry.Summary = fetchedEntry.Activity.Name
+		}
+
+		if fetchedEntry.Activity.Name != "" {
+			entry.SetAttribute(AttributeActivity, fetchedEntry.Activity.Name)
+		}
+
+		entry.TaskHints = &worklog.TaskHints{Texts: []string{fetchedEntry.Comments}}
+		entry.AddLinks(utils.ExtractURLs(fetchedEntry.Comments)...)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *redmineClient) fetchEntries(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fetchResponse FetchResponse
+	if err = json.Unmarshal(resp, &fetchResponse); err != nil {
+		return nil, nil, err
+	}
+
+	return fetchResponse.TimeEntries, &client.PaginatedFetchResponse{
+		EntriesPerPage: fetchResponse.Limit,
+		TotalEntries:   fetchResponse.TotalCount,
+	}, nil
+}
+
+func (c *redmineClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	user := opts.User
+	if user == "" {
+		user = CurrentUser
+	}
+
+	fetchURL, err := c.URL(PathTimeEntries, map[string]string{
+		"user_id": user,
+		"from":    utils.DateFormatISO8601.Format(opts.Start),
+		"to":      utils.DateFormatISO8601.Format(opts.LastDay()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	// Redmine paginates by offset, returning the total count of the entries
+	return client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[FetchEntry]{
+		BaseFetchOpts: opts,
+		URL:           fetchURL,
+		PageSize:      MaxPageSize,
+		PageSizeParam: "limit",
+		OffsetParam:   "offset",
+		FetchFunc:     c.fetchEntries,
+		ParseFunc:     c.parseEntries,
+	})
+}
+
+// NewFetcher returns a new Redmine client for fetching time entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.BaseURL == "" {
+		return nil, errors.New("no Redmine URL provided")
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewTokenAuth(HeaderAPIKey, "", opts.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	unbillableActivities := make(map[string]bool, len(opts.UnbillableActivities))
+	for _, activity := range opts.UnbillableActivities {
+		unbillableActivities[strings.ToLower(strings.TrimSpace(activity))] = true
+	}
+
+	return &redmineClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		authenticator:        authenticator,
+		unbillableActivities: unbillableActivities,
+	}, nil
+}
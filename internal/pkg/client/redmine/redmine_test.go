@@ -0,0 +1,179 @@
+package redmine_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/redmine"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	project     = worklog.IntIDNameField{ID: 1, Name: "Minutes"}
+	development = worklog.IntIDNameField{ID: 9, Name: "Development"}
+	support     = worklog.IntIDNameField{ID: 10, Name: "Support"}
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+func newMockServer(t *testing.T, totalCount int, pages map[int][]redmine.FetchEntry) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, redmine.PathTimeEntries, r.URL.Path)
+		require.Equal(t, "s3cr3t", r.Header.Get(redmine.HeaderAPIKey))
+
+		query := r.URL.Query()
+		require.Equal(t, redmine.CurrentUser, query.Get("user_id"))
+		require.Equal(t, "2021-10-01", query.Get("from"))
+		require.Equal(t, "2021-10-02", query.Get("to"))
+		require.Equal(t, strconv.Itoa(redmine.MaxPageSize), query.Get("limit"))
+
+		offset, err := strconv.Atoi(query.Get("offset"))
+		require.Nil(t, err)
+
+		page, ok := pages[offset]
+		require.True(t, ok, "unexpected offset %d", offset)
+
+		require.Nil(t, json.NewEncoder(w).Encode(redmine.FetchResponse{
+			TimeEntries: page,
+			TotalCount:  totalCount,
+			Offset:      offset,
+			Limit:       redmine.MaxPageSize,
+		}))
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string) client.Fetcher {
+	fetcher, err := redmine.NewFetcher(&redmine.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:              serverURL,
+		APIKey:               "s3cr3t",
+		UnbillableActivities: []string{"support"},
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestFetchEntry_Start(t *testing.T) {
+	entry := redmine.FetchEntry{
+		SpentOn:   "2021-10-01",
+		CreatedOn: utils.Timestamp{Time: at(2, 10, 30)},
+	}
+
+	start, err := entry.Start()
+	require.Nil(t, err)
+	require.Equal(t, at(1, 10, 30), start)
+}
+
+func TestRedmineClient_FetchEntries(t *testing.T) {
+	var firstPage []redmine.FetchEntry
+	for i := 0; i < redmine.MaxPageSize-1; i++ {
+		firstPage = append(firstPage, redmine.FetchEntry{
+			ID:        100 + i,
+			Project:   project,
+			Activity:  development,
+			Hours:     0.25,
+			Comments:  "Filler",
+			SpentOn:   "2021-10-01",
+			CreatedOn: utils.Timestamp{Time: at(1, 8, 0)},
+		})
+	}
+
+	firstPage = append(firstPage, redmine.FetchEntry{
+		ID:        1,
+		Project:   project,
+		Issue:     &redmine.Issue{ID: 12},
+		Activity:  development,
+		Hours:     1.5,
+		Comments:  "CPT-123 Fix thing",
+		SpentOn:   "2021-10-01",
+		CreatedOn: utils.Timestamp{Time: at(1, 9, 0)},
+	})
+
+	mockServer := newMockServer(t, redmine.MaxPageSize+1, map[int][]redmine.FetchEntry{
+		0: firstPage,
+		redmine.MaxPageSize: {
+			{
+				ID:        2,
+				Project:   project,
+				Activity:  support,
+				Hours:     0.5,
+				SpentOn:   "2021-10-02",
+				CreatedOn: utils.Timestamp{Time: at(2, 14, 0)},
+			},
+		},
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, redmine.MaxPageSize+1)
+
+	minutes := worklog.IDNameField{ID: "1", Name: "Minutes"}
+
+	require.Equal(t, worklog.Entry{
+		Project:          minutes,
+		Task:             worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"},
+		Summary:          "CPT-123 Fix thing",
+		Notes:            "CPT-123 Fix thing",
+		Start:            at(1, 9, 0),
+		BillableDuration: time.Minute * 90,
+		Links:            []string{mockServer.URL + "/time_entries/1/edit", mockServer.URL + "/issues/12"},
+		Attributes:       map[string]string{redmine.AttributeActivity: "Development"},
+		Provenance: worklog.Provenance{
+			SourceIDs:  []string{"1"},
+			SourceURLs: []string{mockServer.URL + "/time_entries/1/edit"},
+		},
+	}, entries[redmine.MaxPageSize-1])
+
+	require.Equal(t, worklog.Entry{
+		Project:            minutes,
+		Summary:            "Support",
+		Start:              at(2, 14, 0),
+		UnbillableDuration: time.Minute * 30,
+		Attributes:         map[string]string{redmine.AttributeActivity: "Support"},
+		Links:              []string{mockServer.URL + "/time_entries/2/edit"},
+		Provenance: worklog.Provenance{
+			SourceIDs:  []string{"2"},
+			SourceURLs: []string{mockServer.URL + "/time_entries/2/edit"},
+		},
+	}, entries[redmine.MaxPageSize])
+}
+
+func TestRedmineClient_FetchEntries_Unauthorized(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoURL(t *testing.T) {
+	_, err := redmine.NewFetcher(&redmine.ClientOpts{APIKey: "s3cr3t"})
+	require.EqualError(t, err, "no Redmine URL provided")
+}
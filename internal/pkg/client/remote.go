@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// RemoteCheckIssue is the kind of the checks of the issue keys.
+	RemoteCheckIssue string = "issue"
+	// RemoteCheckAccount is the kind of the checks of the accounts.
+	RemoteCheckAccount string = "account"
+	// RemoteCheckAttribute is the kind of the checks of the attributes, like
+	// the Tempo work attributes.
+	RemoteCheckAttribute string = "attribute"
+)
+
+var (
+	// ErrRemoteValidationUnsupported returns when the uploader cannot validate
+	// the entries against the target.
+	ErrRemoteValidationUnsupported = errors.New("target does not support remote validation")
+)
+
+// RemoteCheck represents the result of checking a value referenced by the
+// entries, like an issue key, against the target.
+type RemoteCheck struct {
+	Kind  string
+	Value string
+	// Entries is the number of the entries referencing the value.
+	Entries int
+	// Problem describes why the value is not accepted by the target. If empty,
+	// the value is valid.
+	Problem string
+}
+
+// IsValid returns true if the target accepts the value.
+func (c *RemoteCheck) IsValid() bool {
+	return c.Problem == ""
+}
+
+// RemoteReport represents the result of validating the entries against the
+// target before uploading them.
+type RemoteReport struct {
+	Checks []RemoteCheck
+}
+
+// Add adds the check of the value referenced by the given number of entries.
+func (r *RemoteReport) Add(kind string, value string, entries int, problem string) {
+	r.Checks = append(r.Checks, RemoteCheck{
+		Kind:    kind,
+		Value:   value,
+		Entries: entries,
+		Problem: problem,
+	})
+}
+
+// Sort sorts the checks by their kind and value.
+func (r *RemoteReport) Sort() {
+	sort.SliceStable(r.Checks, func(i, j int) bool {
+		if r.Checks[i].Kind != r.Checks[j].Kind {
+			return r.Checks[i].Kind < r.Checks[j].Kind
+		}
+
+		return r.Checks[i].Value < r.Checks[j].Value
+	})
+}
+
+// Problems returns the checks of the values the target does not accept.
+func (r *RemoteReport) Problems() []RemoteCheck {
+	var problems []RemoteCheck
+
+	for _, check := range r.Checks {
+		if !check.IsValid() {
+			problems = append(problems, check)
+		}
+	}
+
+	return problems
+}
+
+// IsGo returns true if every checked value is accepted by the target, so the
+// entries can be uploaded.
+func (r *RemoteReport) IsGo() bool {
+	return len(r.Problems()) == 0
+}
+
+// RemoteValidator is implemented by the uploaders able to validate the entries
+// against the data of the target without uploading them, like checking that
+// the referenced issues exist. The unique values are checked in bulk, so huge
+// uploads can be validated before any upload begins.
+type RemoteValidator interface {
+	// ValidateRemote checks every unique value referenced by the entries, as
+	// the entries would be uploaded, against the target.
+	ValidateRemote(ctx context.Context, entries worklog.Entries, opts *UploadOpts) (*RemoteReport, error)
+}
+
+// ValidateRemote validates the entries against the target of the uploader. If
+// the uploader is not a RemoteValidator, ErrRemoteValidationUnsupported
+// returns.
+func ValidateRemote(ctx context.Context, uploader Uploader, entries worklog.Entries, opts *UploadOpts) (*RemoteReport, error) {
+	validator, ok := uploader.(RemoteValidator)
+	if !ok {
+		return nil, ErrRemoteValidationUnsupported
+	}
+
+	report, err := validator.ValidateRemote(ctx, entries, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Sort()
+
+	return report, nil
+}
@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockValidatorUploader struct {
+	mockUploader
+	report *client.RemoteReport
+}
+
+func (u *mockValidatorUploader) ValidateRemote(_ context.Context, _ worklog.Entries, _ *client.UploadOpts) (*client.RemoteReport, error) {
+	return u.report, nil
+}
+
+func TestRemoteReport(t *testing.T) {
+	report := &client.RemoteReport{}
+	require.True(t, report.IsGo())
+
+	report.Add(client.RemoteCheckIssue, "CPT-2015", 1, "issue does not exist or it is not visible")
+	report.Add(client.RemoteCheckAttribute, "_Team_=Avengers", 3, "")
+	report.Add(client.RemoteCheckIssue, "CPT-2014", 2, "")
+	report.Sort()
+
+	require.Equal(t, []client.RemoteCheck{
+		{Kind: client.RemoteCheckAttribute, Value: "_Team_=Avengers", Entries: 3},
+		{Kind: client.RemoteCheckIssue, Value: "CPT-2014", Entries: 2},
+		{Kind: client.RemoteCheckIssue, Value: "CPT-2015", Entries: 1, Problem: "issue does not exist or it is not visible"},
+	}, report.Checks)
+
+	require.Len(t, report.Problems(), 1)
+	require.False(t, report.IsGo())
+}
+
+func TestValidateRemote(t *testing.T) {
+	uploader := &mockValidatorUploader{report: &client.RemoteReport{}}
+	uploader.report.Add(client.RemoteCheckIssue, "CPT-2015", 1, "")
+	uploader.report.Add(client.RemoteCheckIssue, "CPT-2014", 1, "")
+
+	report, err := client.ValidateRemote(context.Background(), uploader, worklog.Entries{}, &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Equal(t, "CPT-2014", report.Checks[0].Value)
+	require.True(t, report.IsGo())
+}
+
+func TestValidateRemote_Unsupported(t *testing.T) {
+	_, err := client.ValidateRemote(context.Background(), &mockUploader{}, worklog.Entries{}, &client.UploadOpts{})
+	require.ErrorIs(t, err, client.ErrRemoteValidationUnsupported)
+}
@@ -0,0 +1,245 @@
+package rescuetime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the RescueTime API.
+	DefaultURL string = "https://www.rescuetime.com"
+	// PathAnalyticData is the endpoint of the Analytic Data API.
+	PathAnalyticData string = "/anapi/data"
+
+	// GranularityHour groups the activities of every hour into entries.
+	GranularityHour string = "hour"
+	// GranularityDay groups the activities of every day into entries.
+	GranularityDay string = "day"
+
+	// dateFormat is the format of the intervals returned by the API, in the
+	// time zone of the RescueTime account.
+	dateFormat string = "2006-01-02T15:04:05"
+)
+
+var (
+	// Granularities lists the supported granularities.
+	Granularities = []string{GranularityHour, GranularityDay}
+
+	// ErrInvalidRow returns when a row of the response cannot be parsed.
+	ErrInvalidRow = errors.New("invalid row")
+)
+
+// FetchResponse represents the response of the Analytic Data API. The rows
+// are arrays having the values of the columns named by the row headers, like
+// `["2021-10-01T09:00:00", 1200, 1, "github.com", "Software Development", 2]`.
+type FetchResponse struct {
+	RowHeaders []string            `json:"row_headers"`
+	Rows       [][]json.RawMessage `json:"rows"`
+}
+
+// Activity represents the time spent on an activity in an interval.
+type Activity struct {
+	Interval  time.Time
+	TimeSpent time.Duration
+	Name      string
+	Category  string
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// APIKey is the key of the Analytic Data API, passed as query parameter.
+	APIKey string
+	// Granularity sets the length of the intervals grouping the activities
+	// into entries, like GranularityHour.
+	Granularity string
+}
+
+type rescueTimeClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	apiKey      string
+	granularity string
+}
+
+// parseActivity returns the activity of the row. The columns are looked up by
+// the row headers, as the order of the columns depends on the query.
+func parseActivity(headers []string, row []json.RawMessage) (Activity, error) {
+	values := map[string]json.RawMessage{}
+	for i, header := range headers {
+		if i < len(row) {
+			values[header] = row[i]
+		}
+	}
+
+	var rawInterval string
+	var seconds int64
+	var activity Activity
+
+	fields := map[string]interface{}{
+		"Date":                 &rawInterval,
+		"Time Spent (seconds)": &seconds,
+		"Activity":             &activity.Name,
+		"Category":             &activity.Category,
+	}
+
+	for header, field := range fields {
+		value, ok := values[header]
+		if !ok {
+			return Activity{}, fmt.Errorf("%w: missing %s", ErrInvalidRow, header)
+		}
+
+		if err := json.Unmarshal(value, field); err != nil {
+			return Activity{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+		}
+	}
+
+	interval, err := utils.ParseTimestamp(rawInterval, time.Local)
+	if err != nil {
+		return Activity{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+	}
+
+	activity.Interval = interval
+	activity.TimeSpent = time.Duration(seconds) * time.Second
+
+	return activity, nil
+}
+
+// groupActivities combines the activities of the same interval and category
+// into one entry. The category is used as project and task, while the names
+// of the activities are listed in the notes, ordered by the time spent.
+func groupActivities(activities []Activity) worklog.Entries {
+	type group struct {
+		interval   time.Time
+		category   string
+		timeSpent  time.Duration
+		activities []Activity
+	}
+
+	var groups []*group
+	groupsByKey := map[string]*group{}
+
+	for _, activity := range activities {
+		key := activity.Interval.Format(dateFormat) + "\x00" + activity.Category
+
+		g, ok := groupsByKey[key]
+		if !ok {
+			g = &group{interval: activity.Interval, category: activity.Category}
+			groupsByKey[key] = g
+			groups = append(groups, g)
+		}
+
+		g.timeSpent += activity.TimeSpent
+		g.activities = append(g.activities, activity)
+	}
+
+	var entries worklog.Entries
+	for _, g := range groups {
+		sort.SliceStable(g.activities, func(i, j int) bool {
+			return g.activities[i].TimeSpent > g.activities[j].TimeSpent
+		})
+
+		var names []string
+		for _, activity := range g.activities {
+			names = append(names, activity.Name)
+		}
+
+		category := worklog.IDNameField{
+			ID:   g.category,
+			Name: g.category,
+		}
+
+		entry := worklog.Entry{
+			Project:          category,
+			Task:             category,
+			Summary:          g.category,
+			Notes:            strings.Join(names, ", "),
+			Start:            g.interval,
+			BillableDuration: g.timeSpent,
+		}
+
+		if len(g.activities) > 1 {
+			entry.AddTransformation("grouped %d activities", len(g.activities))
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func (c *rescueTimeClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	fetchURL, err := c.URL(PathAnalyticData, map[string]string{
+		"key":             c.apiKey,
+		"format":          "json",
+		"perspective":     "interval",
+		"restrict_kind":   "activity",
+		"resolution_time": c.granularity,
+		"restrict_begin":  utils.DateFormatISO8601.Format(opts.Start),
+		"restrict_end":    utils.DateFormatISO8601.Format(opts.LastDay()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var fetchResponse FetchResponse
+	err = c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     fetchURL,
+		Timeout: c.Timeout,
+	}, &fetchResponse)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var activities []Activity
+	for _, row := range fetchResponse.Rows {
+		activity, err := parseActivity(fetchResponse.RowHeaders, row)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		activities = append(activities, activity)
+	}
+
+	// The API returns the activities of whole days, hence the hours outside
+	// the period are dropped
+	return opts.FilterEntries(groupActivities(activities)), nil
+}
+
+// NewFetcher returns a new RescueTime client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("no RescueTime API key provided")
+	}
+
+	if opts.Granularity != GranularityHour && opts.Granularity != GranularityDay {
+		return nil, fmt.Errorf("\"%s\" is not part of the supported granularities %v", opts.Granularity, Granularities)
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rescueTimeClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		apiKey:      opts.APIKey,
+		granularity: opts.Granularity,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package rescuetime_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/rescuetime"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const testResponse string = `{
+	"notes": "data is an array of arrays (rows), column names for rows in row_headers",
+	"row_headers": ["Date", "Time Spent (seconds)", "Number of People", "Activity", "Category", "Productivity"],
+	"rows": [
+		["2021-10-01T09:00:00", 1200, 1, "github.com", "Software Development", 2],
+		["2021-10-01T09:00:00", 1500, 1, "Visual Studio Code", "Software Development", 2],
+		["2021-10-01T09:00:00", 300, 1, "Slack", "Communication & Scheduling", 0],
+		["2021-10-01T10:00:00", 600, 1, "github.com", "Software Development", 2],
+		["2021-09-30T23:00:00", 600, 1, "github.com", "Software Development", 2]
+	]
+}`
+
+func newMockServer(t *testing.T, granularity string, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, rescuetime.PathAnalyticData, r.URL.Path)
+
+		query := r.URL.Query()
+		require.Equal(t, "s3cr3t", query.Get("key"))
+		require.Equal(t, "json", query.Get("format"))
+		require.Equal(t, "interval", query.Get("perspective"))
+		require.Equal(t, "activity", query.Get("restrict_kind"))
+		require.Equal(t, granularity, query.Get("resolution_time"))
+		require.Equal(t, "2021-10-01", query.Get("restrict_begin"))
+		require.Equal(t, "2021-10-01", query.Get("restrict_end"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(response))
+		require.Nil(t, err)
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string, granularity string) client.Fetcher {
+	fetcher, err := rescuetime.NewFetcher(&rescuetime.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:     serverURL,
+		APIKey:      "s3cr3t",
+		Granularity: granularity,
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestRescueTimeClient_FetchEntries(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+	development := worklog.IDNameField{ID: "Software Development", Name: "Software Development"}
+	communication := worklog.IDNameField{ID: "Communication & Scheduling", Name: "Communication & Scheduling"}
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          development,
+			Task:             development,
+			Summary:          "Software Development",
+			Notes:            "Visual Studio Code, github.com",
+			Start:            time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Minute * 45,
+			Provenance:       worklog.Provenance{Transformations: []string{"grouped 2 activities"}},
+		},
+		{
+			Project:          communication,
+			Task:             communication,
+			Summary:          "Communication & Scheduling",
+			Notes:            "Slack",
+			Start:            time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Minute * 5,
+		},
+		{
+			Project:          development,
+			Task:             development,
+			Summary:          "Software Development",
+			Notes:            "github.com",
+			Start:            time.Date(2021, 10, 1, 10, 0, 0, 0, time.Local),
+			BillableDuration: time.Minute * 10,
+		},
+	}
+
+	mockServer := newMockServer(t, rescuetime.GranularityHour, testResponse)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL, rescuetime.GranularityHour).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestRescueTimeClient_FetchEntries_InvalidRow(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+
+	mockServer := newMockServer(t, rescuetime.GranularityDay, `{
+		"row_headers": ["Date", "Time Spent (seconds)", "Number of People", "Category"],
+		"rows": [["2021-10-01T00:00:00", 1200, 1, "Software Development"]]
+	}`)
+	defer mockServer.Close()
+
+	_, err := newTestFetcher(t, mockServer.URL, rescuetime.GranularityDay).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	})
+
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, rescuetime.ErrInvalidRow)
+}
+
+func TestNewFetcher_InvalidOpts(t *testing.T) {
+	_, err := rescuetime.NewFetcher(&rescuetime.ClientOpts{Granularity: rescuetime.GranularityDay})
+	require.Error(t, err)
+
+	_, err = rescuetime.NewFetcher(&rescuetime.ClientOpts{APIKey: "s3cr3t", Granularity: "week"})
+	require.Error(t, err)
+}
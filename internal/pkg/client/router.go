@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+// DefaultRouteTagPrefix is the prefix of the tag routing an entry to another
+// target, like "#target:jira" in the summary of the entry.
+const DefaultRouteTagPrefix string = "#target:"
+
+var (
+	// ErrUnknownRoute returns when an entry is routed to a target which is not
+	// part of the routes.
+	ErrUnknownRoute = errors.New("unknown route")
+)
+
+// RouteOf returns the target the entry is routed to by the tag in its summary,
+// and the entry without the tag. If the summary has no tag, the target is
+// empty. If the summary has multiple tags, the first one is used, but every
+// tag is removed.
+func RouteOf(entry worklog.Entry, prefix string) (string, worklog.Entry) {
+	if prefix == "" {
+		prefix = DefaultRouteTagPrefix
+	}
+
+	var target string
+	var words []string
+
+	for _, word := range strings.Fields(entry.Summary) {
+		if name, found := strings.CutPrefix(word, prefix); found && name != "" {
+			if target == "" {
+				target = name
+			}
+
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	if target != "" {
+		entry.Summary = strings.Join(words, " ")
+	}
+
+	return target, entry
+}
+
+// Router is an Uploader fanning out the entries to multiple targets. The
+// entries are uploaded by the Default uploader, unless their summary has a
+// route tag, like "#target:jira", routing them to the uploader of the named
+// target. The tag is removed from the summary before uploading.
+type Router struct {
+	// DefaultTarget is the name of the default target. The entries routed to
+	// it are uploaded by the Default uploader.
+	DefaultTarget string
+	// Default uploads the entries without route tag.
+	Default Uploader
+	// Routes maps the name of the targets to their uploaders.
+	Routes map[string]Uploader
+	// TagPrefix is the prefix of the route tags. If empty,
+	// DefaultRouteTagPrefix is used.
+	TagPrefix string
+}
+
+// route groups the entries by their uploader, in the order of the entries.
+// The entries routed to unknown targets are returned separately.
+func (r *Router) route(entries worklog.Entries) ([]Uploader, map[Uploader]worklog.Entries, worklog.Entries) {
+	var uploaders []Uploader
+	groups := map[Uploader]worklog.Entries{}
+	var unknown worklog.Entries
+
+	for _, entry := range entries {
+		target, routedEntry := RouteOf(entry, r.TagPrefix)
+
+		uploader := r.Default
+		if target != "" && target != r.DefaultTarget {
+			var ok bool
+			if uploader, ok = r.Routes[target]; !ok {
+				unknown = append(unknown, entry)
+				continue
+			}
+		}
+
+		if _, ok := groups[uploader]; !ok {
+			uploaders = append(uploaders, uploader)
+		}
+
+		groups[uploader] = append(groups[uploader], routedEntry)
+	}
+
+	return uploaders, groups, unknown
+}
+
+// rejectUnknown sends the error of the entries routed to unknown targets to
+// the channel.
+func (r *Router) rejectUnknown(unknown worklog.Entries, errChan chan error) {
+	for _, entry := range unknown {
+		target, _ := RouteOf(entry, r.TagPrefix)
+		errChan <- NewEntryError(entry, fmt.Errorf("%w: %w: %s", ErrUploadEntries, ErrUnknownRoute, target))
+	}
+}
+
+func (r *Router) UploadEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *UploadOpts) {
+	uploaders, groups, unknown := r.route(entries)
+	r.rejectUnknown(unknown, errChan)
+
+	// Every uploader sends one result per entry to the channel
+	for _, uploader := range uploaders {
+		uploader.UploadEntries(ctx, groups[uploader], errChan, opts)
+	}
+}
+
+// CorrectEntries applies the corrections by the uploader they are routed to.
+// The corrections routed to uploaders not being a Corrector fail.
+func (r *Router) CorrectEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *UploadOpts) {
+	uploaders, groups, unknown := r.route(entries)
+	r.rejectUnknown(unknown, errChan)
+
+	for _, uploader := range uploaders {
+		UploadWithCorrections(ctx, uploader, groups[uploader], errChan, opts)
+	}
+}
+
+// CheckLimits checks the limits of the entries by the uploader they are
+// routed to, if the uploader is a LimitChecker.
+func (r *Router) CheckLimits(entries worklog.Entries, opts *UploadOpts) ([]LimitViolation, error) {
+	uploaders, groups, _ := r.route(entries)
+
+	var violations []LimitViolation
+
+	for _, uploader := range uploaders {
+		checker, ok := uploader.(LimitChecker)
+		if !ok {
+			continue
+		}
+
+		uploaderViolations, err := checker.CheckLimits(groups[uploader], opts)
+		if err != nil {
+			return nil, err
+		}
+
+		violations = append(violations, uploaderViolations...)
+	}
+
+	return violations, nil
+}
+
+// PreviewPayloads returns the payloads of the entries routed to the uploaders
+// able to preview them. The entries of the other uploaders are left out.
+func (r *Router) PreviewPayloads(entries worklog.Entries, opts *UploadOpts) ([]Payload, error) {
+	uploaders, groups, _ := r.route(entries)
+
+	var payloads []Payload
+
+	for _, uploader := range uploaders {
+		previewer, ok := uploader.(PayloadPreviewer)
+		if !ok {
+			continue
+		}
+
+		uploaderPayloads, err := previewer.PreviewPayloads(groups[uploader], opts)
+		if err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, uploaderPayloads...)
+	}
+
+	return payloads, nil
+}
+
+// verifierOf returns the uploader able to verify the resource created by the
+// mutation. If none of the uploaders can verify it, nil returns.
+func (r *Router) verifierOf(mutation *Mutation) UploadVerifier {
+	uploaders := []Uploader{r.Default}
+	for _, uploader := range r.Routes {
+		uploaders = append(uploaders, uploader)
+	}
+
+	for _, uploader := range uploaders {
+		if verifier, ok := uploader.(UploadVerifier); ok && verifier.IsVerifiable(mutation) {
+			return verifier
+		}
+	}
+
+	return nil
+}
+
+// IsVerifiable returns true if any of the uploaders can verify the resource
+// created by the mutation.
+func (r *Router) IsVerifiable(mutation *Mutation) bool {
+	return r.verifierOf(mutation) != nil
+}
+
+// VerifyUpload verifies the resource created by the mutation by the uploader
+// able to verify it. Since the routed entries have no route tag anymore, the
+// uploader is found by the mutation instead of the entry.
+func (r *Router) VerifyUpload(ctx context.Context, mutation *Mutation, opts *UploadOpts) ([]FieldMismatch, error) {
+	verifier := r.verifierOf(mutation)
+	if verifier == nil {
+		return nil, ErrUploadVerificationUnsupported
+	}
+
+	return verifier.VerifyUpload(ctx, mutation, opts)
+}
+
+// WarmUpCredentials warms up the credentials of every uploader.
+func (r *Router) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	uploaders := []Uploader{r.Default}
+	for _, uploader := range r.Routes {
+		uploaders = append(uploaders, uploader)
+	}
+
+	for _, uploader := range uploaders {
+		if warmer, ok := uploader.(CredentialWarmer); ok {
+			if err := warmer.WarmUpCredentials(ctx, d); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
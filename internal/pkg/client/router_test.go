@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingUploader struct {
+	entries worklog.Entries
+}
+
+func (u *recordingUploader) UploadEntries(_ context.Context, entries worklog.Entries, errChan chan error, _ *client.UploadOpts) {
+	u.entries = append(u.entries, entries...)
+
+	for range entries {
+		errChan <- nil
+	}
+}
+
+func TestRouteOf(t *testing.T) {
+	entry := getTestEntry()
+	entry.Summary = "Review #target:jira the pull request #target:tempo"
+
+	target, routedEntry := client.RouteOf(entry, "")
+	require.Equal(t, "jira", target)
+	require.Equal(t, "Review the pull request", routedEntry.Summary)
+	require.Equal(t, "Review #target:jira the pull request #target:tempo", entry.Summary)
+
+	target, routedEntry = client.RouteOf(entry, "@")
+	require.Equal(t, "", target)
+	require.Equal(t, entry.Summary, routedEntry.Summary)
+}
+
+func TestRouter_UploadEntries(t *testing.T) {
+	tempoUploader := &recordingUploader{}
+	jiraUploader := &recordingUploader{}
+
+	router := &client.Router{
+		DefaultTarget: "tempo",
+		Default:       tempoUploader,
+		Routes:        map[string]client.Uploader{"jira": jiraUploader},
+	}
+
+	entries := worklog.Entries{getTestEntry(), getTestEntry(), getTestEntry(), getTestEntry()}
+	entries[1].Summary = "Fix the bug #target:jira"
+	entries[2].Summary = "#target:tempo Write documentation"
+	entries[3].Summary = "Plan the sprint #target:kimai"
+
+	errChan := make(chan error, len(entries))
+	router.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{})
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrUnknownRoute)
+	require.Equal(t, "Plan the sprint #target:kimai", client.EntryOf(errs[0]).Summary)
+
+	require.Len(t, tempoUploader.entries, 2)
+	require.Equal(t, entries[0].Summary, tempoUploader.entries[0].Summary)
+	require.Equal(t, "Write documentation", tempoUploader.entries[1].Summary)
+
+	require.Len(t, jiraUploader.entries, 1)
+	require.Equal(t, "Fix the bug", jiraUploader.entries[0].Summary)
+}
+
+func TestRouter_VerifyUpload(t *testing.T) {
+	verifier := &mockVerifierUploader{}
+	router := &client.Router{
+		DefaultTarget: "csvfile",
+		Default:       &recordingUploader{},
+		Routes:        map[string]client.Uploader{"tempo": verifier},
+	}
+
+	mutations := []client.Mutation{
+		{URL: "/worklogs", ResourceID: "1"},
+		{URL: "/comments", ResourceID: "100"},
+	}
+
+	verifications, err := client.VerifySample(context.Background(), router, mutations, 10, nil, &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Len(t, verifications, 1)
+	require.True(t, verifications[0].IsVerified())
+	require.Equal(t, []string{"1"}, verifier.verified)
+
+	_, err = router.VerifyUpload(context.Background(), &mutations[1], &client.UploadOpts{})
+	require.ErrorIs(t, err, client.ErrUploadVerificationUnsupported)
+}
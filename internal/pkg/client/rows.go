@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RowError represents an error of a single row read by file based sources,
+// like a CSV line containing an invalid duration. The line number lets the
+// user find and fix the row in the file.
+type RowError struct {
+	// Line is the 1-based line number of the row in the file.
+	Line int
+	// Column is the name of the column containing the invalid value. If the
+	// error is not bound to a column, it is empty.
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors collects the errors of the invalid rows read by file based
+// sources. Unless the source is strict, the sources return the entries of the
+// valid rows together with the RowErrors, so the valid rows can be imported.
+type RowErrors []*RowError
+
+func (e RowErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, rowErr := range e {
+		messages = append(messages, rowErr.Error())
+	}
+
+	return fmt.Sprintf("%d invalid rows: %s", len(e), strings.Join(messages, "; "))
+}
@@ -3,11 +3,14 @@ package tempo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
@@ -18,8 +21,64 @@ import (
 const (
 	// PathWorklogCreate is the endpoint used to create new worklogs.
 	PathWorklogCreate string = "/rest/tempo-timesheets/4/worklogs"
+	// PathWorklog is the endpoint used to get, update or delete a worklog by
+	// its ID.
+	PathWorklog string = "/rest/tempo-timesheets/4/worklogs/%s"
 	// PathWorklogSearch is the endpoint used to search existing worklogs.
 	PathWorklogSearch string = "/rest/tempo-timesheets/4/worklogs/search"
+	// PathIssueBrowse is the Jira page of the issue the worklog belongs to.
+	PathIssueBrowse string = "/browse/%s"
+	// PathIssueComment is the Jira endpoint used to comment on the issue of the
+	// uploaded worklogs.
+	PathIssueComment string = "/rest/api/2/issue/%s/comment"
+	// PathIssueSearch is the Jira endpoint used to search issues by JQL.
+	PathIssueSearch string = "/rest/api/2/search"
+	// PathWorkAttributes is the endpoint used to list the work attributes.
+	PathWorkAttributes string = "/rest/tempo-core/1/work-attribute"
+	// PathAccounts is the endpoint used to list the accounts.
+	PathAccounts string = "/rest/tempo-accounts/1/account"
+	// PathOAuthAuthorize is the Jira page where the user authorizes the OAuth
+	// app of Tempo Cloud.
+	PathOAuthAuthorize string = "/plugins/servlet/ac/io.tempo.jira/oauth-authorize/"
+
+	// DefaultOAuthTokenURL is the endpoint issuing the Tempo Cloud access
+	// tokens.
+	DefaultOAuthTokenURL string = "https://api.tempo.io/oauth/token/"
+
+	// WorkAttributeTypeStaticList is the type of the work attributes having
+	// a static list of values.
+	WorkAttributeTypeStaticList string = "STATIC_LIST"
+	// WorkAttributeTypeAccount is the type of the work attributes referencing
+	// an account by its key.
+	WorkAttributeTypeAccount string = "ACCOUNT"
+	// AccountStatusOpen is the status of the accounts worklogs can be logged
+	// against.
+	AccountStatusOpen string = "OPEN"
+
+	// issueSearchBatchSize is the number of issue keys searched at once.
+	issueSearchBatchSize int = 100
+
+	// DefaultTeamAttribute is the default key of the work attribute used to
+	// set the team of the worklogs.
+	DefaultTeamAttribute string = "_Team_"
+	// DefaultRoleAttribute is the default key of the work attribute used to
+	// set the role of the worklogs.
+	DefaultRoleAttribute string = "_Role_"
+	// DefaultClassificationAttribute is the default key of the work attribute
+	// used to set the cost classification of the worklogs.
+	DefaultClassificationAttribute string = "_Classification_"
+	// DefaultMaxCommentLength is the default maximum length of the worklog
+	// comments, matching the default text field limit of Jira.
+	DefaultMaxCommentLength int = 32767
+)
+
+var (
+	// ErrImpersonationDisabled returns when an entry would be uploaded on
+	// behalf of another user, but impersonation is not enabled.
+	ErrImpersonationDisabled = errors.New("impersonation is not enabled")
+	// ErrUserNotImpersonated returns when an entry would be uploaded on behalf
+	// of a user not listed as impersonated.
+	ErrUserNotImpersonated = errors.New("user is not listed for impersonation")
 )
 
 // Issue represents the Jira issue the time logged against.
@@ -35,25 +94,133 @@ type Issue struct {
 // FetchEntry represents the entry fetched from Tempo.
 // StartDate must be in the given YYYY-MM-DD format, required by Tempo.
 type FetchEntry struct {
-	ID               int       `json:"id"`
-	StartDate        time.Time `json:"startDate"`
-	BillableSeconds  int       `json:"billableSeconds"`
-	TimeSpentSeconds int       `json:"timeSpentSeconds"`
-	Comment          string    `json:"comment"`
-	WorkerKey        string    `json:"workerKey"`
-	Issue            Issue     `json:"issue"`
+	ID               int             `json:"id"`
+	StartDate        utils.Timestamp `json:"startDate"`
+	BillableSeconds  int             `json:"billableSeconds"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+	Comment          string          `json:"comment"`
+	WorkerKey        string          `json:"workerKey"`
+	Issue            Issue           `json:"issue"`
+}
+
+// WorkAttribute represents the value of a Tempo work attribute.
+type WorkAttribute struct {
+	Value string `json:"value"`
 }
 
 // UploadEntry represents the payload to create a new worklog in Tempo.
 // Started must be in the given YYYY-MM-DD format, required by Tempo.
 type UploadEntry struct {
-	Comment               string `json:"comment,omitempty"`
-	IncludeNonWorkingDays bool   `json:"includeNonWorkingDays,omitempty"`
-	OriginTaskID          string `json:"originTaskId,omitempty"`
-	Started               string `json:"started,omitempty"`
-	BillableSeconds       int    `json:"billableSeconds,omitempty"`
-	TimeSpentSeconds      int    `json:"timeSpentSeconds,omitempty"`
-	Worker                string `json:"worker,omitempty"`
+	Comment               string                   `json:"comment,omitempty"`
+	IncludeNonWorkingDays bool                     `json:"includeNonWorkingDays,omitempty"`
+	OriginTaskID          string                   `json:"originTaskId,omitempty"`
+	Started               string                   `json:"started,omitempty"`
+	BillableSeconds       int                      `json:"billableSeconds,omitempty"`
+	TimeSpentSeconds      int                      `json:"timeSpentSeconds,omitempty"`
+	Worker                string                   `json:"worker,omitempty"`
+	Attributes            map[string]WorkAttribute `json:"attributes,omitempty"`
+}
+
+// CreatedWorklog represents the worklog returned by Tempo after creating it.
+type CreatedWorklog struct {
+	TempoWorklogID int `json:"tempoWorklogId"`
+	JiraWorklogID  int `json:"jiraWorklogId"`
+}
+
+// Worklog represents a worklog returned by Tempo when getting it by its ID.
+// Started is in the "2006-01-02 15:04:05.000" format.
+type Worklog struct {
+	TempoWorklogID   int                      `json:"tempoWorklogId"`
+	Comment          string                   `json:"comment"`
+	Started          string                   `json:"started"`
+	BillableSeconds  int                      `json:"billableSeconds"`
+	TimeSpentSeconds int                      `json:"timeSpentSeconds"`
+	Worker           string                   `json:"worker"`
+	Issue            Issue                    `json:"issue"`
+	Attributes       map[string]WorkAttribute `json:"attributes"`
+}
+
+// parseCreatedWorklogID returns the ID of the created worklog. If the response
+// contains no worklog, an empty string returns.
+func parseCreatedWorklogID(body []byte) string {
+	var worklogs []CreatedWorklog
+	if err := json.Unmarshal(body, &worklogs); err != nil || len(worklogs) == 0 || worklogs[0].TempoWorklogID == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(worklogs[0].TempoWorklogID)
+}
+
+// IssueComment represents the payload to comment on a Jira issue.
+type IssueComment struct {
+	Body string `json:"body"`
+}
+
+// CreatedIssueComment represents the comment returned by Jira after creating
+// it.
+type CreatedIssueComment struct {
+	ID string `json:"id"`
+}
+
+// parseCreatedCommentID returns the ID of the created comment. If the response
+// contains no comment, an empty string returns.
+func parseCreatedCommentID(body []byte) string {
+	var comment CreatedIssueComment
+	if err := json.Unmarshal(body, &comment); err != nil {
+		return ""
+	}
+
+	return comment.ID
+}
+
+// TeamRole sets the team and role of the worklogs uploaded for a project or
+// user. An empty Project or User matches every project or user.
+type TeamRole struct {
+	Project string `mapstructure:"project" json:"project,omitempty"`
+	User    string `mapstructure:"user" json:"user,omitempty"`
+	Team    string `mapstructure:"team" json:"team,omitempty"`
+	Role    string `mapstructure:"role" json:"role,omitempty"`
+}
+
+// Matches returns true if the team role applies for the project and user.
+func (r *TeamRole) Matches(project string, user string) bool {
+	return (r.Project == "" || r.Project == project) && (r.User == "" || r.User == user)
+}
+
+// AttributeValues lists the allowed values of a static list work attribute.
+// The values of other attributes are not checked before uploading.
+type AttributeValues struct {
+	Key    string   `mapstructure:"key" json:"key"`
+	Values []string `mapstructure:"values" json:"values"`
+}
+
+// StaticListValue represents a value of a static list work attribute.
+type StaticListValue struct {
+	Value   string `json:"value"`
+	Removed bool   `json:"removed"`
+}
+
+// WorkAttributeDefinition represents a work attribute configured in Tempo.
+type WorkAttributeDefinition struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type struct {
+		Value string `json:"value"`
+	} `json:"type"`
+	StaticListValues []StaticListValue `json:"staticListValues"`
+}
+
+// Account represents a Tempo account.
+type Account struct {
+	Key    string `json:"key"`
+	Status string `json:"status"`
+}
+
+// IssueSearchResponse represents the response of the Jira issue search.
+type IssueSearchResponse struct {
+	Issues []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
 }
 
 // SearchParams represents the parameters used to filter Tempo search results.
@@ -69,19 +236,133 @@ type ClientOpts struct {
 	client.BaseClientOpts
 	client.BasicAuth
 	BaseURL string
+	// TeamRoles lists the team and role of the uploaded worklogs. The first
+	// matching team role is used.
+	TeamRoles []TeamRole
+	// TeamAttribute and RoleAttribute are the keys of the work attributes
+	// used to set the team and role. If not set, DefaultTeamAttribute and
+	// DefaultRoleAttribute are used.
+	TeamAttribute string
+	RoleAttribute string
+	// ClassificationAttribute is the key of the work attribute used to set the
+	// classification of the entries. If not set,
+	// DefaultClassificationAttribute is used.
+	ClassificationAttribute string
+	// MaxCommentLength is the maximum length of the worklog comments. If not
+	// set, DefaultMaxCommentLength is used.
+	MaxCommentLength int
+	// AttributeValues lists the allowed values of the work attributes.
+	AttributeValues []AttributeValues
+	// IssueCommentTemplate is used to render the comment posted on the Jira
+	// issue of the uploaded worklogs, executed with the entry as its data. In
+	// case the IssueCommentTemplate is nil, no comments are posted.
+	IssueCommentTemplate *template.Template
+	// IssueCommentMinDuration is the minimum duration of the uploaded worklogs
+	// commented on their issue.
+	IssueCommentMinDuration time.Duration
+	// OAuth is the OAuth app of Tempo Cloud. If set, the requests are
+	// authenticated by the access tokens obtained by the RefreshToken instead
+	// of the basic auth.
+	OAuth        *client.OAuthOpts
+	RefreshToken string
+	// Impersonate enables uploading the entries having the worker attribute
+	// on behalf of the worker, like by an admin installing Tempo for the
+	// team. Only the ImpersonatedUsers can be impersonated.
+	Impersonate       bool
+	ImpersonatedUsers []string
+}
+
+// oauthClient is the Tempo client authenticated by the OAuth app, refreshing
+// its access tokens.
+type oauthClient struct {
+	*tempoClient
+	auth *client.RefreshingTokenAuth
+}
+
+func (c *oauthClient) WarmUpCredentials(ctx context.Context, d time.Duration) error {
+	return c.auth.WarmUp(ctx, d)
+}
+
+func (c *oauthClient) CredentialsExpireAt() time.Time {
+	return c.auth.ExpiresAt()
 }
 
 type tempoClient struct {
 	*client.BaseClientOpts
 	*client.HTTPClient
 	*client.DefaultUploader
-	authenticator client.Authenticator
+	authenticator  client.Authenticator
+	teamRoles      []TeamRole
+	teamAttribute  string
+	roleAttribute  string
+	classAttribute string
+	limits         *client.FieldLimits
+
+	issueCommentTemplate    *template.Template
+	issueCommentMinDuration time.Duration
+
+	impersonate       bool
+	impersonatedUsers map[string]bool
+}
+
+// getWorker returns the user the entry is uploaded on behalf of, resolved by
+// the author strategy. Other users than the target user can be impersonated
+// only if impersonation is enabled and the user is listed.
+func (c *tempoClient) getWorker(entry *worklog.Entry, opts *client.UploadOpts) (string, error) {
+	worker, err := opts.AuthorOf(entry)
+	if err != nil || worker == opts.User {
+		return worker, err
+	}
+
+	if !c.impersonate {
+		return "", fmt.Errorf("%w: %s", ErrImpersonationDisabled, worker)
+	}
+
+	if !c.impersonatedUsers[worker] {
+		return "", fmt.Errorf("%w: %s", ErrUserNotImpersonated, worker)
+	}
+
+	return worker, nil
+}
+
+// getWorkAttributes returns the team and role work attributes of the first
+// team role matching the project and user, and the classification of the
+// entry.
+func (c *tempoClient) getWorkAttributes(entry *worklog.Entry, user string) map[string]WorkAttribute {
+	attributes := map[string]WorkAttribute{}
+
+	for i := range c.teamRoles {
+		teamRole := c.teamRoles[i]
+		if !teamRole.Matches(entry.Project.Name, user) {
+			continue
+		}
+
+		if teamRole.Team != "" {
+			attributes[c.teamAttribute] = WorkAttribute{Value: teamRole.Team}
+		}
+
+		if teamRole.Role != "" {
+			attributes[c.roleAttribute] = WorkAttribute{Value: teamRole.Role}
+		}
+
+		break
+	}
+
+	if class := entry.Attributes[worklog.AttributeClassification]; class != "" {
+		attributes[c.classAttribute] = WorkAttribute{Value: class}
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	return attributes
 }
 
 func (c *tempoClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
 	searchURL, err := c.URL(PathWorklogSearch, map[string]string{})
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
@@ -90,8 +371,8 @@ func (c *tempoClient) FetchEntries(ctx context.Context, opts *client.FetchOpts)
 		Auth:    c.authenticator,
 		Timeout: c.Timeout,
 		Data: &SearchParams{
-			From:   utils.DateFormatISO8601.Format(opts.Start.Local()),
-			To:     utils.DateFormatISO8601.Format(opts.End.Local()),
+			From:   utils.DateFormatISO8601.Format(opts.Start),
+			To:     utils.DateFormatISO8601.Format(opts.LastDay()),
 			Worker: opts.User,
 		},
 		Headers: map[string]string{
@@ -100,16 +381,23 @@ func (c *tempoClient) FetchEntries(ctx context.Context, opts *client.FetchOpts)
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	var fetchedEntries []FetchEntry
 	if err = json.Unmarshal(resp, &fetchedEntries); err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	var entries worklog.Entries
 	for _, entry := range fetchedEntries {
+		worklogURL, err := c.URL(fmt.Sprintf(PathIssueBrowse, entry.Issue.Key), map[string]string{
+			"focusedWorklogId": strconv.Itoa(entry.ID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
 		entries = append(entries, worklog.Entry{
 			Client: worklog.IDNameField{
 				ID:   entry.Issue.AccountKey,
@@ -125,53 +413,179 @@ func (c *tempoClient) FetchEntries(ctx context.Context, opts *client.FetchOpts)
 			},
 			Summary:            entry.Issue.Summary,
 			Notes:              entry.Comment,
-			Start:              entry.StartDate,
+			Start:              entry.StartDate.Time,
 			BillableDuration:   time.Second * time.Duration(entry.BillableSeconds),
 			UnbillableDuration: time.Second * time.Duration(entry.TimeSpentSeconds-entry.BillableSeconds),
+			Links:              []string{worklogURL},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{strconv.Itoa(entry.ID)},
+				SourceURLs: []string{worklogURL},
+			},
 		})
 	}
 
 	return entries, nil
 }
 
+// buildUploadEntry returns the worklog of the entry without applying the
+// limits of Tempo.
+func (c *tempoClient) buildUploadEntry(entry worklog.Entry, opts *client.UploadOpts) (*UploadEntry, error) {
+	comment, err := opts.RenderComment(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	worker, err := c.getWorker(&entry, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	totalTimeSpent := entry.BillableDuration + entry.UnbillableDuration
+
+	return &UploadEntry{
+		Comment:               comment,
+		IncludeNonWorkingDays: true,
+		OriginTaskID:          entry.Task.Name,
+		Started:               utils.DateFormatISO8601.Format(entry.Start.Local()),
+		BillableSeconds:       int(entry.BillableDuration.Seconds()),
+		TimeSpentSeconds:      int(totalTimeSpent.Seconds()),
+		Worker:                worker,
+		Attributes:            c.getWorkAttributes(&entry, worker),
+	}, nil
+}
+
+// newUploadEntry returns the worklog sent to Tempo for the entry. If the limit
+// policy is transform, the comment is truncated and the work attributes having
+// not allowed values are omitted.
+func (c *tempoClient) newUploadEntry(entry worklog.Entry, opts *client.UploadOpts) (*UploadEntry, error) {
+	uploadEntry, err := c.buildUploadEntry(entry, opts)
+	if err != nil || opts.LimitPolicy != client.LimitPolicyTransform {
+		return uploadEntry, err
+	}
+
+	uploadEntry.Comment = c.limits.TruncateComment(uploadEntry.Comment)
+
+	for key, attribute := range uploadEntry.Attributes {
+		if c.limits.CheckAttribute(entry, key, attribute.Value) != nil {
+			delete(uploadEntry.Attributes, key)
+		}
+	}
+
+	if len(uploadEntry.Attributes) == 0 {
+		uploadEntry.Attributes = nil
+	}
+
+	return uploadEntry, nil
+}
+
+func (c *tempoClient) CheckLimits(entries worklog.Entries, opts *client.UploadOpts) ([]client.LimitViolation, error) {
+	var violations []client.LimitViolation
+
+	for _, entry := range entries {
+		// The deletions and the negative corrections send no worklog of the
+		// entry, hence they cannot violate the limits
+		if entry.IsDeletion() || (entry.IsCorrection() && entry.Replaces == "") {
+			continue
+		}
+
+		uploadEntry, err := c.buildUploadEntry(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if violation := c.limits.CheckComment(entry, uploadEntry.Comment); violation != nil {
+			violations = append(violations, *violation)
+		}
+
+		if violation := c.limits.CheckDate(entry, entry.Start.Local()); violation != nil {
+			violations = append(violations, *violation)
+		}
+
+		keys := make([]string, 0, len(uploadEntry.Attributes))
+		for key := range uploadEntry.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if violation := c.limits.CheckAttribute(entry, key, uploadEntry.Attributes[key].Value); violation != nil {
+				violations = append(violations, *violation)
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// PreviewPayloads returns the payloads of creating the worklogs of the entries
+// and updating or deleting the worklogs replaced by the corrections. Since the
+// worklogs corrected by the negative corrections are searched while uploading,
+// their payloads are left out.
+func (c *tempoClient) PreviewPayloads(entries worklog.Entries, opts *client.UploadOpts) ([]client.Payload, error) {
+	createURL, err := c.URL(PathWorklogCreate, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([]client.Payload, 0, len(entries))
+	for _, entry := range entries {
+		payload := client.Payload{
+			Entry:  entry,
+			Method: http.MethodPost,
+			URL:    createURL,
+		}
+
+		if entry.IsCorrection() {
+			if entry.Replaces == "" {
+				continue
+			}
+
+			if payload.URL, err = c.worklogURL(entry.Replaces); err != nil {
+				return nil, err
+			}
+
+			payload.Method = http.MethodPut
+			if entry.IsDeletion() {
+				payload.Method = http.MethodDelete
+				payloads = append(payloads, payload)
+				continue
+			}
+		}
+
+		uploadEntry, err := c.newUploadEntry(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if payload.Body, err = json.Marshal(uploadEntry); err != nil {
+			return nil, err
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
+
 func (c *tempoClient) UploadEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
 	createURL, err := c.URL(PathWorklogCreate, map[string]string{})
 	if err != nil {
-		errChan <- fmt.Errorf("%v: %v", client.ErrUploadEntries, err)
+		errChan <- fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
 		return
 	}
 
 	for _, groupEntries := range entries.GroupByTask() {
 		go func(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
 			for _, entry := range entries {
-				billableDuration := entry.BillableDuration
-				unbillableDuration := entry.UnbillableDuration
-				totalTimeSpent := billableDuration + unbillableDuration
-
-				if opts.TreatDurationAsBilled {
-					billableDuration = entry.UnbillableDuration + entry.BillableDuration
-					unbillableDuration = 0
-				}
-
-				if opts.RoundToClosestMinute {
-					billableDuration = time.Second * time.Duration(math.Round(billableDuration.Minutes())*60)
-					unbillableDuration = time.Second * time.Duration(math.Round(unbillableDuration.Minutes())*60)
-					totalTimeSpent = billableDuration + unbillableDuration
-				}
-
-				uploadEntry := &UploadEntry{
-					Comment:               entry.Summary,
-					IncludeNonWorkingDays: true,
-					OriginTaskID:          entry.Task.Name,
-					Started:               utils.DateFormatISO8601.Format(entry.Start.Local()),
-					BillableSeconds:       int(billableDuration.Seconds()),
-					TimeSpentSeconds:      int(totalTimeSpent.Seconds()),
-					Worker:                opts.User,
+				uploadEntry, err := c.newUploadEntry(entry, opts)
+				if err != nil {
+					errChan <- client.NewEntryError(entry, fmt.Errorf("%w: %w", client.ErrUploadEntries, err))
+					continue
 				}
 
 				tracker := c.StartTracking(entry, opts.ProgressWriter)
 
-				_, err := c.Call(ctx, &client.HTTPRequestOpts{
+				resp, err := c.Send(ctx, &client.HTTPRequestOpts{
 					Method:  http.MethodPost,
 					Url:     createURL,
 					Auth:    c.authenticator,
@@ -182,41 +596,668 @@ func (c *tempoClient) UploadEntries(ctx context.Context, entries worklog.Entries
 					},
 				})
 
+				mutation := &client.Mutation{
+					Entry:      entry,
+					Method:     http.MethodPost,
+					URL:        createURL,
+					StatusCode: client.StatusCodeOf(err),
+					Err:        err,
+				}
+
+				if uploadEntry.Worker != opts.User {
+					mutation.OnBehalfOf = uploadEntry.Worker
+				}
+
+				if resp != nil {
+					mutation.StatusCode = resp.StatusCode
+					mutation.ResourceID = parseCreatedWorklogID(resp.Body)
+				}
+
+				opts.RecordMutation(mutation)
+
 				if err != nil {
-					err = fmt.Errorf("%v: %+v: %v", client.ErrUploadEntries, uploadEntry, err)
+					err = fmt.Errorf("%w: %+v: %w", client.ErrUploadEntries, uploadEntry, err)
+				}
+
+				if err == nil {
+					c.commentIssue(ctx, entry, opts)
 				}
 
 				c.StopTracking(tracker, err)
-				errChan <- err
+				errChan <- client.NewEntryError(entry, err)
 			}
 		}(ctx, groupEntries, errChan, opts)
 	}
 }
 
+// worklogURL returns the URL of the worklog having the ID.
+func (c *tempoClient) worklogURL(worklogID string) (string, error) {
+	return c.URL(fmt.Sprintf(PathWorklog, url.PathEscape(worklogID)), map[string]string{})
+}
+
+// findCorrectedWorklog returns the ID of the worklog the negative correction
+// is subtracted from, and the worklog as stored by Tempo. The latest worklog of
+// the worker logged on the issue on the day of the correction, being at least
+// as long as the correction, is corrected.
+func (c *tempoClient) findCorrectedWorklog(ctx context.Context, entry *worklog.Entry, worker string) (string, *Worklog, error) {
+	searchURL, err := c.URL(PathWorklogSearch, map[string]string{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	day := utils.DateFormatISO8601.Format(entry.Start.Local())
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     searchURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Data: &SearchParams{
+			From:   day,
+			To:     day,
+			Worker: worker,
+		},
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var fetchedEntries []FetchEntry
+	if err = json.Unmarshal(resp, &fetchedEntries); err != nil {
+		return "", nil, err
+	}
+
+	correction := -int((entry.BillableDuration + entry.UnbillableDuration).Seconds())
+
+	worklogID := 0
+	for _, fetchedEntry := range fetchedEntries {
+		if fetchedEntry.Issue.Key == entry.Task.Name && fetchedEntry.TimeSpentSeconds >= correction && fetchedEntry.ID > worklogID {
+			worklogID = fetchedEntry.ID
+		}
+	}
+
+	if worklogID == 0 {
+		return "", nil, fmt.Errorf("%w: %s on %s", client.ErrCorrectedWorklogNotFound, entry.Task.Name, day)
+	}
+
+	// The search does not return the work attributes, which must be kept
+	id := strconv.Itoa(worklogID)
+
+	var corrected Worklog
+	if err = c.get(ctx, fmt.Sprintf(PathWorklog, url.PathEscape(id)), map[string]string{}, &corrected); err != nil {
+		return "", nil, err
+	}
+
+	return id, &corrected, nil
+}
+
+// newCorrection returns the ID of the worklog changed by the correction and
+// the worklog it is replaced by. If the worklog is deleted, the returned
+// worklog is nil. The negative corrections are subtracted from the billable
+// time first, and the worklog is deleted if no time remains.
+func (c *tempoClient) newCorrection(ctx context.Context, entry worklog.Entry, opts *client.UploadOpts) (string, *UploadEntry, error) {
+	if entry.IsDeletion() {
+		return entry.Replaces, nil, nil
+	}
+
+	if entry.Replaces != "" {
+		uploadEntry, err := c.newUploadEntry(entry, opts)
+		return entry.Replaces, uploadEntry, err
+	}
+
+	worker, err := c.getWorker(&entry, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	worklogID, corrected, err := c.findCorrectedWorklog(ctx, &entry, worker)
+	if err != nil {
+		return "", nil, err
+	}
+
+	timeSpent := corrected.TimeSpentSeconds + int((entry.BillableDuration + entry.UnbillableDuration).Seconds())
+	if timeSpent <= 0 {
+		return worklogID, nil, nil
+	}
+
+	billable := corrected.BillableSeconds + int(entry.BillableDuration.Seconds())
+	if billable < 0 {
+		billable = 0
+	} else if billable > timeSpent {
+		billable = timeSpent
+	}
+
+	return worklogID, &UploadEntry{
+		Comment:               corrected.Comment,
+		IncludeNonWorkingDays: true,
+		OriginTaskID:          corrected.Issue.Key,
+		Started:               utils.DateFormatISO8601.Format(entry.Start.Local()),
+		BillableSeconds:       billable,
+		TimeSpentSeconds:      timeSpent,
+		Worker:                corrected.Worker,
+		Attributes:            corrected.Attributes,
+	}, nil
+}
+
+// correctEntry updates or deletes the worklog corrected by the entry and
+// records the mutation.
+func (c *tempoClient) correctEntry(ctx context.Context, entry worklog.Entry, opts *client.UploadOpts) error {
+	worklogID, uploadEntry, err := c.newCorrection(ctx, entry, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+	}
+
+	worklogURL, err := c.worklogURL(worklogID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+	}
+
+	requestOpts := &client.HTTPRequestOpts{
+		Method:  http.MethodDelete,
+		Url:     worklogURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+
+	if uploadEntry != nil {
+		requestOpts.Method = http.MethodPut
+		requestOpts.Data = uploadEntry
+	}
+
+	resp, err := c.Send(ctx, requestOpts)
+
+	mutation := &client.Mutation{
+		Entry:      entry,
+		Method:     requestOpts.Method,
+		URL:        worklogURL,
+		StatusCode: client.StatusCodeOf(err),
+		ResourceID: worklogID,
+		Err:        err,
+	}
+
+	if uploadEntry != nil && uploadEntry.Worker != opts.User {
+		mutation.OnBehalfOf = uploadEntry.Worker
+	}
+
+	if resp != nil {
+		mutation.StatusCode = resp.StatusCode
+	}
+
+	opts.RecordMutation(mutation)
+
+	if err != nil {
+		return fmt.Errorf("%w: %s worklog %s: %w", client.ErrUploadEntries, requestOpts.Method, worklogID, err)
+	}
+
+	return nil
+}
+
+// CorrectEntries updates the worklogs replaced by the corrections, or deletes
+// them if the corrections have no duration. The negative corrections are
+// subtracted from the latest worklog of the worker logged on the issue on the
+// same day.
+func (c *tempoClient) CorrectEntries(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	for _, groupEntries := range entries.GroupByTask() {
+		go func(ctx context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+			for _, entry := range entries {
+				tracker := c.StartTracking(entry, opts.ProgressWriter)
+				err := c.correctEntry(ctx, entry, opts)
+				c.StopTracking(tracker, err)
+				errChan <- client.NewEntryError(entry, err)
+			}
+		}(ctx, groupEntries, errChan, opts)
+	}
+}
+
+// commentIssue posts a comment on the Jira issue of the uploaded worklog, if
+// the issue comment template is set and the worklog is long enough. Since the
+// worklog is already created, failing to comment does not fail the upload, so
+// the worklog is not uploaded again; the failure is recorded as a mutation.
+func (c *tempoClient) commentIssue(ctx context.Context, entry worklog.Entry, opts *client.UploadOpts) {
+	if c.issueCommentTemplate == nil || entry.Task.Name == "" {
+		return
+	}
+
+	if entry.BillableDuration+entry.UnbillableDuration < c.issueCommentMinDuration {
+		return
+	}
+
+	commentURL, err := c.URL(fmt.Sprintf(PathIssueComment, url.PathEscape(entry.Task.Name)), map[string]string{})
+	if err != nil {
+		opts.RecordMutation(&client.Mutation{Entry: entry, Method: http.MethodPost, Err: err})
+		return
+	}
+
+	var body strings.Builder
+	if err = c.issueCommentTemplate.Execute(&body, entry); err != nil {
+		opts.RecordMutation(&client.Mutation{Entry: entry, Method: http.MethodPost, URL: commentURL, Err: err})
+		return
+	}
+
+	comment := strings.TrimSpace(body.String())
+	if comment == "" {
+		return
+	}
+
+	resp, err := c.Send(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodPost,
+		Url:     commentURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Data:    &IssueComment{Body: comment},
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	})
+
+	mutation := &client.Mutation{
+		Entry:      entry,
+		Method:     http.MethodPost,
+		URL:        commentURL,
+		StatusCode: client.StatusCodeOf(err),
+		Err:        err,
+	}
+
+	if resp != nil {
+		mutation.StatusCode = resp.StatusCode
+		mutation.ResourceID = parseCreatedCommentID(resp.Body)
+	}
+
+	opts.RecordMutation(mutation)
+}
+
+// get sends a GET request to the path and decodes the JSON response into v.
+func (c *tempoClient) get(ctx context.Context, path string, params map[string]string, v interface{}) error {
+	requestURL, err := c.URL(path, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     requestURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(resp, v)
+}
+
+// validateIssues checks that the issues exist in Jira, searching the issue
+// keys in batches. The issues not visible for the user are reported as not
+// existing, since worklogs cannot be logged against them either.
+func (c *tempoClient) validateIssues(ctx context.Context, issueEntries map[string]int, report *client.RemoteReport) error {
+	issueKeys := make([]string, 0, len(issueEntries))
+	for issueKey := range issueEntries {
+		issueKeys = append(issueKeys, issueKey)
+	}
+	sort.Strings(issueKeys)
+
+	for start := 0; start < len(issueKeys); start += issueSearchBatchSize {
+		end := start + issueSearchBatchSize
+		if end > len(issueKeys) {
+			end = len(issueKeys)
+		}
+
+		batch := issueKeys[start:end]
+
+		quotedKeys := make([]string, len(batch))
+		for i, issueKey := range batch {
+			quotedKeys[i] = `"` + strings.ReplaceAll(issueKey, `"`, `\"`) + `"`
+		}
+
+		// The query is validated with warnings only, so the keys of the not
+		// existing issues do not fail the whole search
+		var searchResponse IssueSearchResponse
+		err := c.get(ctx, PathIssueSearch, map[string]string{
+			"jql":           fmt.Sprintf("key in (%s)", strings.Join(quotedKeys, ",")),
+			"fields":        "key",
+			"maxResults":    strconv.Itoa(len(batch)),
+			"validateQuery": "warn",
+		}, &searchResponse)
+		if err != nil {
+			return err
+		}
+
+		existingKeys := map[string]bool{}
+		for _, issue := range searchResponse.Issues {
+			existingKeys[issue.Key] = true
+		}
+
+		for _, issueKey := range batch {
+			problem := ""
+			if !existingKeys[issueKey] {
+				problem = "issue does not exist or it is not visible"
+			}
+
+			report.Add(client.RemoteCheckIssue, issueKey, issueEntries[issueKey], problem)
+		}
+	}
+
+	return nil
+}
+
+// validateAttributes checks that the work attributes exist in Tempo, the
+// values of the static list attributes are part of the list, and the accounts
+// referenced by the account attributes are open.
+func (c *tempoClient) validateAttributes(ctx context.Context, attributeEntries map[string]map[string]int, report *client.RemoteReport) error {
+	var definitions []WorkAttributeDefinition
+	if err := c.get(ctx, PathWorkAttributes, map[string]string{}, &definitions); err != nil {
+		return err
+	}
+
+	definitionsByKey := map[string]WorkAttributeDefinition{}
+	for _, definition := range definitions {
+		definitionsByKey[definition.Key] = definition
+	}
+
+	var accounts map[string]Account
+
+	for key, valueEntries := range attributeEntries {
+		definition, ok := definitionsByKey[key]
+
+		if ok && definition.Type.Value == WorkAttributeTypeAccount && accounts == nil {
+			var accountList []Account
+			if err := c.get(ctx, PathAccounts, map[string]string{}, &accountList); err != nil {
+				return err
+			}
+
+			accounts = map[string]Account{}
+			for _, account := range accountList {
+				accounts[account.Key] = account
+			}
+		}
+
+		for value, entries := range valueEntries {
+			kind := client.RemoteCheckAttribute
+			checkedValue := key + "=" + value
+			problem := ""
+
+			switch {
+			case !ok:
+				problem = "work attribute does not exist"
+			case definition.Type.Value == WorkAttributeTypeAccount:
+				kind = client.RemoteCheckAccount
+				checkedValue = value
+
+				if account, exists := accounts[value]; !exists {
+					problem = "account does not exist"
+				} else if account.Status != AccountStatusOpen {
+					problem = fmt.Sprintf("account is %s", strings.ToLower(account.Status))
+				}
+			case definition.Type.Value == WorkAttributeTypeStaticList:
+				problem = fmt.Sprintf("\"%s\" is not a value of the static list", value)
+
+				for _, listValue := range definition.StaticListValues {
+					if listValue.Value != value {
+						continue
+					}
+
+					problem = ""
+					if listValue.Removed {
+						problem = fmt.Sprintf("\"%s\" is removed from the static list", value)
+					}
+
+					break
+				}
+			}
+
+			report.Add(kind, checkedValue, entries, problem)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRemote checks the issues, work attributes and accounts of the
+// worklogs, as they would be uploaded, against Jira and Tempo. Every unique
+// value is checked once, using a request per issue key batch and a request
+// per work attribute and account list.
+func (c *tempoClient) ValidateRemote(ctx context.Context, entries worklog.Entries, opts *client.UploadOpts) (*client.RemoteReport, error) {
+	issueEntries := map[string]int{}
+	attributeEntries := map[string]map[string]int{}
+
+	for _, entry := range entries {
+		uploadEntry, err := c.newUploadEntry(entry, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if uploadEntry.OriginTaskID != "" {
+			issueEntries[uploadEntry.OriginTaskID]++
+		}
+
+		for key, attribute := range uploadEntry.Attributes {
+			if attributeEntries[key] == nil {
+				attributeEntries[key] = map[string]int{}
+			}
+
+			attributeEntries[key][attribute.Value]++
+		}
+	}
+
+	report := &client.RemoteReport{}
+
+	if err := c.validateIssues(ctx, issueEntries, report); err != nil {
+		return nil, err
+	}
+
+	if len(attributeEntries) != 0 {
+		if err := c.validateAttributes(ctx, attributeEntries, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (c *tempoClient) IsVerifiable(mutation *client.Mutation) bool {
+	createURL, err := c.URL(PathWorklogCreate, map[string]string{})
+	return err == nil && mutation.URL == createURL
+}
+
+// compareWorklog returns the fields of the worklog stored by Tempo differing
+// from the worklog sent. Only the date of the start is compared, as the
+// worklogs are sent for a day. The attributes are compared by their values,
+// so the attributes sent but not stored are reported too.
+func compareWorklog(sent *UploadEntry, received *Worklog) []client.FieldMismatch {
+	var mismatches []client.FieldMismatch
+
+	compare := func(field string, sentValue string, receivedValue string) {
+		if sentValue != receivedValue {
+			mismatches = append(mismatches, client.FieldMismatch{
+				Field:    field,
+				Sent:     sentValue,
+				Received: receivedValue,
+			})
+		}
+	}
+
+	receivedDate := received.Started
+	if len(receivedDate) > len(sent.Started) {
+		receivedDate = receivedDate[:len(sent.Started)]
+	}
+
+	compare("issue", sent.OriginTaskID, received.Issue.Key)
+	compare("started", sent.Started, receivedDate)
+	compare("timeSpentSeconds", strconv.Itoa(sent.TimeSpentSeconds), strconv.Itoa(received.TimeSpentSeconds))
+	compare("billableSeconds", strconv.Itoa(sent.BillableSeconds), strconv.Itoa(received.BillableSeconds))
+	compare("comment", sent.Comment, received.Comment)
+	compare("worker", sent.Worker, received.Worker)
+
+	keys := make([]string, 0, len(sent.Attributes))
+	for key := range sent.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		compare("attributes."+key, sent.Attributes[key].Value, received.Attributes[key].Value)
+	}
+
+	return mismatches
+}
+
+// VerifyUpload gets the worklog created by the mutation from Tempo and
+// compares it with the worklog built for the entry, as it was sent.
+func (c *tempoClient) VerifyUpload(ctx context.Context, mutation *client.Mutation, opts *client.UploadOpts) ([]client.FieldMismatch, error) {
+	sent, err := c.newUploadEntry(mutation.Entry, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var received Worklog
+	if err = c.get(ctx, fmt.Sprintf(PathWorklog, url.PathEscape(mutation.ResourceID)), map[string]string{}, &received); err != nil {
+		return nil, err
+	}
+
+	return compareWorklog(sent, &received), nil
+}
+
+// AuthorizationURL returns the URL of the Jira page the user authorizes the
+// OAuth app of Tempo Cloud on. After the authorization, the user is redirected
+// to the redirect URL of the app, having the code exchangeable to tokens.
+func AuthorizationURL(jiraURL string, clientID string, redirectURL string) (string, error) {
+	baseURL, err := url.Parse(jiraURL)
+	if err != nil {
+		return "", err
+	}
+
+	httpClient := &client.HTTPClient{BaseURL: baseURL}
+	return httpClient.URL(PathOAuthAuthorize, map[string]string{
+		"client_id":    clientID,
+		"redirect_uri": redirectURL,
+	})
+}
+
+// newAuthenticator returns the OAuth authenticator if the OAuth app is set,
+// otherwise the basic auth.
+func newAuthenticator(httpClient *client.HTTPClient, opts *ClientOpts) (client.Authenticator, error) {
+	if opts.OAuth == nil {
+		return client.NewBasicAuth(opts.Username, opts.Password)
+	}
+
+	if opts.OAuth.ClientID == "" || opts.RefreshToken == "" {
+		return nil, errors.New("no Tempo OAuth client ID or refresh token provided")
+	}
+
+	oauthOpts := *opts.OAuth
+	if oauthOpts.TokenURL == "" {
+		oauthOpts.TokenURL = DefaultOAuthTokenURL
+	}
+
+	if oauthOpts.Timeout == 0 {
+		oauthOpts.Timeout = opts.Timeout
+	}
+
+	refresh := client.NewOAuthRefreshFunc(httpClient, &oauthOpts, opts.RefreshToken)
+	return client.NewRefreshingTokenAuth("Authorization", "Bearer", refresh), nil
+}
+
 func newClient(opts *ClientOpts) (*tempoClient, error) {
 	baseURL, err := url.Parse(opts.BaseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	authenticator, err := client.NewBasicAuth(opts.Username, opts.Password)
+	httpClient := &client.HTTPClient{BaseURL: baseURL}
+
+	authenticator, err := newAuthenticator(httpClient, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	teamAttribute := opts.TeamAttribute
+	if teamAttribute == "" {
+		teamAttribute = DefaultTeamAttribute
+	}
+
+	roleAttribute := opts.RoleAttribute
+	if roleAttribute == "" {
+		roleAttribute = DefaultRoleAttribute
+	}
+
+	classAttribute := opts.ClassificationAttribute
+	if classAttribute == "" {
+		classAttribute = DefaultClassificationAttribute
+	}
+
+	maxCommentLength := opts.MaxCommentLength
+	if maxCommentLength == 0 {
+		maxCommentLength = DefaultMaxCommentLength
+	}
+
+	attributeValues := map[string][]string{}
+	for _, attribute := range opts.AttributeValues {
+		attributeValues[attribute.Key] = attribute.Values
+	}
+
+	impersonatedUsers := make(map[string]bool, len(opts.ImpersonatedUsers))
+	for _, user := range opts.ImpersonatedUsers {
+		impersonatedUsers[user] = true
+	}
+
 	return &tempoClient{
 		authenticator:  authenticator,
-		HTTPClient:     &client.HTTPClient{BaseURL: baseURL},
+		HTTPClient:     httpClient,
 		BaseClientOpts: &opts.BaseClientOpts,
+		teamRoles:      opts.TeamRoles,
+		teamAttribute:  teamAttribute,
+		roleAttribute:  roleAttribute,
+		classAttribute: classAttribute,
+		limits: &client.FieldLimits{
+			MaxCommentLength: maxCommentLength,
+			DateLayout:       utils.DateFormatISO8601.String(),
+			AttributeValues:  attributeValues,
+		},
+		issueCommentTemplate:    opts.IssueCommentTemplate,
+		issueCommentMinDuration: opts.IssueCommentMinDuration,
+		impersonate:             opts.Impersonate,
+		impersonatedUsers:       impersonatedUsers,
 	}, nil
 }
 
+// withCredentials returns the client extended by refreshing the credentials,
+// if the client is authenticated by the OAuth app.
+func withCredentials(c *tempoClient) interface {
+	client.Fetcher
+	client.Uploader
+} {
+	if auth, ok := c.authenticator.(*client.RefreshingTokenAuth); ok {
+		return &oauthClient{tempoClient: c, auth: auth}
+	}
+
+	return c
+}
+
 // NewFetcher returns a new Tempo client for fetching entries.
 func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
-	return newClient(opts)
+	c, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return withCredentials(c), nil
 }
 
 // NewUploader returns a new Tempo client for uploading entries.
 func NewUploader(opts *ClientOpts) (client.Uploader, error) {
-	return newClient(opts)
+	c, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return withCredentials(c), nil
 }
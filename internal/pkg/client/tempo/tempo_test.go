@@ -3,10 +3,12 @@ package tempo_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -82,11 +84,11 @@ func mockServer(t *testing.T, e *mockServerOpts) *httptest.Server {
 				}
 
 				for i, entry := range *allEntries {
-					if data == entry {
+					if reflect.DeepEqual(data, entry) {
 						break
 					}
 
-					if i == len(*allEntries) && data != entry {
+					if i == len(*allEntries) && !reflect.DeepEqual(data, entry) {
 						t.Fatal("cannot find expected upload entry")
 					}
 				}
@@ -191,7 +193,7 @@ func TestTempoClient_FetchEntries(t *testing.T) {
 		ResponseData: &[]tempo.FetchEntry{
 			{
 				ID:               123,
-				StartDate:        start,
+				StartDate:        utils.Timestamp{Time: start},
 				BillableSeconds:  3600,
 				TimeSpentSeconds: 3600,
 				Comment:          "I met with The Winter Soldier",
@@ -207,7 +209,7 @@ func TestTempoClient_FetchEntries(t *testing.T) {
 			},
 			{
 				ID:               456,
-				StartDate:        start,
+				StartDate:        utils.Timestamp{Time: start},
 				BillableSeconds:  1800,
 				TimeSpentSeconds: 3600,
 				Comment:          "I met with him again",
@@ -223,7 +225,7 @@ func TestTempoClient_FetchEntries(t *testing.T) {
 			},
 			{
 				ID:               789,
-				StartDate:        start,
+				StartDate:        utils.Timestamp{Time: start},
 				BillableSeconds:  0,
 				TimeSpentSeconds: 3600,
 				Comment:          "I helped him to get back on track",
@@ -241,6 +243,16 @@ func TestTempoClient_FetchEntries(t *testing.T) {
 	})
 	defer mockServer.Close()
 
+	for i, id := range []int{123, 456, 789} {
+		worklogURL := fmt.Sprintf("%s/browse/CPT-2014?focusedWorklogId=%d", mockServer.URL, id)
+
+		expectedEntries[i].Links = []string{worklogURL}
+		expectedEntries[i].Provenance = worklog.Provenance{
+			SourceIDs:  []string{strconv.Itoa(id)},
+			SourceURLs: []string{worklogURL},
+		}
+	}
+
 	tempoClient, err := tempo.NewFetcher(&tempo.ClientOpts{
 		BaseClientOpts: client.BaseClientOpts{
 			Timeout: client.DefaultRequestTimeout,
@@ -361,236 +373,801 @@ func TestTempoClient_UploadEntries(t *testing.T) {
 	}
 }
 
-func TestTempoClient_UploadEntries_TreatDurationAsBilled(t *testing.T) {
+type mockMutationRecorder struct {
+	mutations chan *client.Mutation
+}
+
+func (r *mockMutationRecorder) RecordMutation(mutation *client.Mutation) {
+	r.mutations <- mutation
+}
+
+func TestTempoClient_UploadEntries_RecordMutations(t *testing.T) {
 	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
 
-	clientUsername := "Thor"
-	clientPassword := "The strongest Avenger"
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{ID: "456", Name: "MARVEL"},
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour,
+		},
+		{
+			Project:          worklog.IDNameField{ID: "123", Name: "SHIELD"},
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: time.Hour,
+		},
+	}
 
-	uploadOpts := &client.UploadOpts{
-		User:                  "steve-rogers",
-		TreatDurationAsBilled: true,
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry tempo.UploadEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			t.Error(err)
+		}
+
+		if entry.OriginTaskID == "SHD-2012" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Issue not found"}]}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode([]tempo.CreatedWorklog{{TempoWorklogID: 1234, JiraWorklogID: 5678}})
+	}))
+	defer mockServer.Close()
+
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL: mockServer.URL,
+	})
+	require.Nil(t, err)
+
+	recorder := &mockMutationRecorder{mutations: make(chan *client.Mutation, len(entries))}
+
+	errChan := make(chan error)
+	tempoClient.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{
+		User:             "steve-rogers",
+		MutationRecorder: recorder,
+	})
+
+	var failedEntries []*worklog.Entry
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			failedEntries = append(failedEntries, client.EntryOf(err))
+		}
 	}
+	close(recorder.mutations)
+
+	require.Equal(t, []*worklog.Entry{&entries[1]}, failedEntries)
+
+	mutations := map[string]*client.Mutation{}
+	for mutation := range recorder.mutations {
+		mutations[mutation.Entry.Task.Name] = mutation
+	}
+
+	require.Len(t, mutations, 2)
+
+	require.Equal(t, http.MethodPost, mutations["CPT-2014"].Method)
+	require.Equal(t, mockServer.URL+tempo.PathWorklogCreate, mutations["CPT-2014"].URL)
+	require.Equal(t, http.StatusCreated, mutations["CPT-2014"].StatusCode)
+	require.Equal(t, "1234", mutations["CPT-2014"].ResourceID)
+	require.Nil(t, mutations["CPT-2014"].Err)
+
+	require.Equal(t, http.StatusBadRequest, mutations["SHD-2012"].StatusCode)
+	require.Empty(t, mutations["SHD-2012"].ResourceID)
+	require.Equal(t, client.ErrorKindValidation, client.KindOf(mutations["SHD-2012"].Err))
+}
+
+func TestTempoClient_UploadEntries_Impersonation(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
 
 	entries := worklog.Entries{
 		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with The Winter Soldier",
-			Start:              start,
-			BillableDuration:   0,
-			UnbillableDuration: time.Second * 3600,
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour,
 		},
 		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with him again",
-			Start:              start,
-			BillableDuration:   time.Second * 3600,
-			UnbillableDuration: 0,
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{worklog.AttributeWorker: "bucky-barnes"},
+		},
+		{
+			Task:             worklog.IDNameField{ID: "654", Name: "SHD-2013"},
+			Summary:          "Infiltrate S.H.I.E.L.D.",
+			Start:            start,
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{worklog.AttributeWorker: "natasha-romanoff"},
 		},
 	}
 
-	var responseEntries []tempo.UploadEntry
-	for _, entry := range entries {
-		responseEntries = append(responseEntries, tempo.UploadEntry{
-			Comment:               entry.Notes,
-			IncludeNonWorkingDays: true,
-			OriginTaskID:          entry.Task.ID,
-			Started:               entry.Start.Local().Format("2006-01-02"),
-			BillableSeconds:       int(entry.BillableDuration.Seconds()),
-			TimeSpentSeconds:      int((entry.BillableDuration + entry.UnbillableDuration).Seconds()),
-			Worker:                uploadOpts.User,
-		})
+	workers := make(chan string, len(entries))
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry tempo.UploadEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			t.Error(err)
+		}
+
+		workers <- entry.Worker
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode([]tempo.CreatedWorklog{{TempoWorklogID: 1234, JiraWorklogID: 5678}})
+	}))
+	defer mockServer.Close()
+
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL:           mockServer.URL,
+		Impersonate:       true,
+		ImpersonatedUsers: []string{"bucky-barnes"},
+	})
+	require.Nil(t, err)
+
+	recorder := &mockMutationRecorder{mutations: make(chan *client.Mutation, len(entries))}
+
+	errChan := make(chan error)
+	tempoClient.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{
+		User:             "steve-rogers",
+		MutationRecorder: recorder,
+	})
+
+	var uploadErrors []error
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			uploadErrors = append(uploadErrors, err)
+		}
 	}
+	close(recorder.mutations)
+	close(workers)
 
-	mockServer := newMockServer(t, &mockServerOpts{
-		Path:        tempo.PathWorklogCreate,
-		Method:      http.MethodPost,
-		StatusCode:  http.StatusOK,
-		Username:    clientUsername,
-		Password:    clientPassword,
-		RequestData: &responseEntries,
+	require.Len(t, uploadErrors, 1)
+	require.ErrorIs(t, uploadErrors[0], tempo.ErrUserNotImpersonated)
+	require.Equal(t, &entries[2], client.EntryOf(uploadErrors[0]))
+
+	var uploadedWorkers []string
+	for worker := range workers {
+		uploadedWorkers = append(uploadedWorkers, worker)
+	}
+	require.ElementsMatch(t, []string{"steve-rogers", "bucky-barnes"}, uploadedWorkers)
+
+	onBehalfOf := map[string]string{}
+	for mutation := range recorder.mutations {
+		onBehalfOf[mutation.Entry.Task.Name] = mutation.OnBehalfOf
+	}
+	require.Equal(t, map[string]string{"CPT-2014": "", "SHD-2012": "bucky-barnes"}, onBehalfOf)
+}
+
+func TestTempoClient_PreviewPayloads_FixedAuthor(t *testing.T) {
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL:           "https://tempo.example.com",
+		Impersonate:       true,
+		ImpersonatedUsers: []string{"svc-minutes"},
+	})
+	require.Nil(t, err)
+
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+		Summary:          "Meet with The Winter Soldier",
+		Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+		BillableDuration: time.Hour,
+		Attributes:       map[string]string{worklog.AttributeWorker: "bucky-barnes"},
+	}
+
+	payloads, err := tempoClient.(client.PayloadPreviewer).PreviewPayloads(worklog.Entries{entry}, &client.UploadOpts{
+		User:   "steve-rogers",
+		Author: &client.AuthorOpts{Strategy: client.AuthorStrategyFixed, FixedUser: "svc-minutes"},
+	})
+	require.Nil(t, err)
+
+	var uploadEntry tempo.UploadEntry
+	require.Nil(t, json.Unmarshal(payloads[0].Body, &uploadEntry))
+	require.Equal(t, "svc-minutes", uploadEntry.Worker)
+}
+
+func TestTempoClient_PreviewPayloads_ImpersonationDisabled(t *testing.T) {
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL:           "https://tempo.example.com",
+		ImpersonatedUsers: []string{"bucky-barnes"},
 	})
+	require.Nil(t, err)
+
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+		Summary:          "Meet with The Winter Soldier",
+		Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+		BillableDuration: time.Hour,
+		Attributes:       map[string]string{worklog.AttributeWorker: "bucky-barnes"},
+	}
+
+	_, err = tempoClient.(client.PayloadPreviewer).PreviewPayloads(worklog.Entries{entry}, &client.UploadOpts{User: "steve-rogers"})
+	require.ErrorIs(t, err, tempo.ErrImpersonationDisabled)
+}
+
+func TestTempoClient_UploadEntries_IssueComments(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{ID: "456", Name: "MARVEL"},
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour * 2,
+		},
+		{
+			Project:          worklog.IDNameField{ID: "123", Name: "SHIELD"},
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: time.Minute * 30,
+		},
+	}
+
+	comments := make(chan string, len(entries))
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case tempo.PathWorklogCreate:
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode([]tempo.CreatedWorklog{{TempoWorklogID: 1234, JiraWorklogID: 5678}})
+		case fmt.Sprintf(tempo.PathIssueComment, "CPT-2014"):
+			var comment tempo.IssueComment
+			if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+				t.Error(err)
+			}
+
+			comments <- comment.Body
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&tempo.CreatedIssueComment{ID: "10000"})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
 	defer mockServer.Close()
 
+	issueCommentTemplate, err := client.NewCommentTemplate("Logged {{ .BillableDuration }}: {{ .Summary }}")
+	require.Nil(t, err)
+
 	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
 		BaseClientOpts: client.BaseClientOpts{
 			Timeout: client.DefaultRequestTimeout,
 		},
 		BasicAuth: client.BasicAuth{
-			Username: clientUsername,
-			Password: clientPassword,
+			Username: "Thor",
+			Password: "The strongest Avenger",
 		},
-		BaseURL: mockServer.URL,
+		BaseURL:                 mockServer.URL,
+		IssueCommentTemplate:    issueCommentTemplate,
+		IssueCommentMinDuration: time.Hour,
 	})
 	require.Nil(t, err)
 
+	recorder := &mockMutationRecorder{mutations: make(chan *client.Mutation, len(entries)+1)}
+
 	errChan := make(chan error)
-	tempoClient.UploadEntries(context.Background(), entries, errChan, uploadOpts)
+	tempoClient.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{
+		User:             "steve-rogers",
+		MutationRecorder: recorder,
+	})
 
-	require.Empty(t, errChan, "cannot fetch entries")
+	for i := 0; i < len(entries); i++ {
+		require.Nil(t, <-errChan)
+	}
+	close(recorder.mutations)
+	close(comments)
+
+	var commentBodies []string
+	for comment := range comments {
+		commentBodies = append(commentBodies, comment)
+	}
+
+	require.Equal(t, []string{"Logged 2h0m0s: Meet with The Winter Soldier"}, commentBodies)
+
+	var commentMutations []*client.Mutation
+	for mutation := range recorder.mutations {
+		if mutation.URL != mockServer.URL+tempo.PathWorklogCreate {
+			commentMutations = append(commentMutations, mutation)
+		}
+	}
+
+	require.Len(t, commentMutations, 1)
+	require.Equal(t, mockServer.URL+fmt.Sprintf(tempo.PathIssueComment, "CPT-2014"), commentMutations[0].URL)
+	require.Equal(t, http.StatusCreated, commentMutations[0].StatusCode)
+	require.Equal(t, "10000", commentMutations[0].ResourceID)
+	require.Nil(t, commentMutations[0].Err)
 }
 
-func TestTempoClient_UploadEntries_RoundToClosestMinute(t *testing.T) {
+func TestTempoClient_UploadEntries_WorkAttributes(t *testing.T) {
 	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
 
-	clientUsername := "Thor"
-	clientPassword := "The strongest Avenger"
-
+	progressWriter := cmdUtils.NewProgressWriter(progress.DefaultUpdateFrequency)
 	uploadOpts := &client.UploadOpts{
-		User:                 "steve-rogers",
-		RoundToClosestMinute: true,
+		User:           "steve-rogers",
+		ProgressWriter: progressWriter,
 	}
 
 	entries := worklog.Entries{
 		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with The Winter Soldier",
-			Start:              start,
-			BillableDuration:   0,
-			UnbillableDuration: time.Second * 30,
+			Project:          worklog.IDNameField{ID: "456", Name: "MARVEL"},
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour,
 		},
 		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with The Winter Soldier",
-			Start:              start,
-			BillableDuration:   0,
-			UnbillableDuration: time.Second * 29,
+			Project:          worklog.IDNameField{ID: "123", Name: "SHIELD"},
+			Task:             worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:          "Assemble the Avengers",
+			Start:            start,
+			BillableDuration: time.Hour,
 		},
 		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with The Winter Soldier",
-			Start:              start,
-			BillableDuration:   time.Second * 30,
-			UnbillableDuration: time.Second * 29,
+			Project:          worklog.IDNameField{ID: "999", Name: "HYDRA"},
+			Task:             worklog.IDNameField{ID: "998", Name: "HYD-1945"},
+			Summary:          "Hail Hydra",
+			Start:            start,
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{worklog.AttributeClassification: "OPEX"},
 		},
-		{
-			Client: worklog.IDNameField{
-				ID:   "My Awesome Company",
-				Name: "My Awesome Company",
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(456),
-				Name: "MARVEL",
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(789),
-				Name: "CPT-2014",
-			},
-			Summary:            "Meet with The Winter Soldier",
-			Notes:              "I met with The Winter Soldier",
-			Start:              start,
-			BillableDuration:   time.Second * 29,
-			UnbillableDuration: time.Second * 30,
+	}
+
+	uploaded := make(chan tempo.UploadEntry, len(entries))
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry tempo.UploadEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			t.Error(err)
+		}
+
+		uploaded <- entry
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
 		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL:       mockServer.URL,
+		RoleAttribute: "_Position_",
+		TeamRoles: []tempo.TeamRole{
+			{Project: "MARVEL", User: "tony-stark", Team: "Iron Legion", Role: "Leader"},
+			{Project: "MARVEL", Team: "Avengers", Role: "Captain"},
+			{User: "steve-rogers", Team: "SHIELD"},
+		},
+	})
+	require.Nil(t, err)
+
+	errChan := make(chan error)
+	tempoClient.UploadEntries(context.Background(), entries, errChan, uploadOpts)
+
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			require.Failf(t, "cannot upload entries", err.Error())
+		}
+	}
+	close(uploaded)
+
+	attributes := map[string]map[string]tempo.WorkAttribute{}
+	for entry := range uploaded {
+		attributes[entry.OriginTaskID] = entry.Attributes
 	}
 
-	responseEntries := []tempo.UploadEntry{
+	require.Equal(t, map[string]map[string]tempo.WorkAttribute{
+		"CPT-2014": {
+			tempo.DefaultTeamAttribute: {Value: "Avengers"},
+			"_Position_":               {Value: "Captain"},
+		},
+		"SHD-2012": {
+			tempo.DefaultTeamAttribute: {Value: "SHIELD"},
+		},
+		"HYD-1945": {
+			tempo.DefaultTeamAttribute:           {Value: "SHIELD"},
+			tempo.DefaultClassificationAttribute: {Value: "OPEX"},
+		},
+	}, attributes)
+}
+
+func TestTempoClient_PreviewPayloads(t *testing.T) {
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL: "https://tempo.example.com",
+	})
+	require.Nil(t, err)
+
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+		Summary:          "Meet with The Winter Soldier",
+		Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+		BillableDuration: time.Hour,
+	}
+
+	payloads, err := tempoClient.(client.PayloadPreviewer).PreviewPayloads(worklog.Entries{entry}, &client.UploadOpts{User: "steve-rogers"})
+	require.Nil(t, err)
+	require.Len(t, payloads, 1)
+	require.Equal(t, http.MethodPost, payloads[0].Method)
+	require.Equal(t, "https://tempo.example.com"+tempo.PathWorklogCreate, payloads[0].URL)
+
+	var uploadEntry tempo.UploadEntry
+	require.Nil(t, json.Unmarshal(payloads[0].Body, &uploadEntry))
+	require.Equal(t, "CPT-2014", uploadEntry.OriginTaskID)
+	require.Equal(t, "steve-rogers", uploadEntry.Worker)
+	require.Equal(t, 3600, uploadEntry.TimeSpentSeconds)
+}
+
+func TestTempoClient_CheckLimits(t *testing.T) {
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL:          "https://tempo.example.com",
+		MaxCommentLength: 10,
+		TeamRoles: []tempo.TeamRole{
+			{Team: "Avengers", Role: "Captain"},
+		},
+		AttributeValues: []tempo.AttributeValues{
+			{Key: tempo.DefaultTeamAttribute, Values: []string{"SHIELD"}},
+			{Key: tempo.DefaultRoleAttribute, Values: []string{"Captain"}},
+		},
+	})
+	require.Nil(t, err)
+
+	entries := worklog.Entries{
 		{
-			Comment:               entries[0].Notes,
-			IncludeNonWorkingDays: true,
-			OriginTaskID:          entries[0].Task.ID,
-			Started:               utils.DateFormatISO8601.Format(entries[0].Start.Local()),
-			BillableSeconds:       60,
-			TimeSpentSeconds:      60,
-			Worker:                uploadOpts.User,
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+			BillableDuration: time.Hour,
 		},
 		{
-			Comment:               entries[1].Notes,
-			IncludeNonWorkingDays: true,
-			OriginTaskID:          entries[1].Task.ID,
-			Started:               utils.DateFormatISO8601.Format(entries[1].Start.Local()),
-			BillableSeconds:       0,
-			TimeSpentSeconds:      0,
-			Worker:                uploadOpts.User,
+			Task:             worklog.IDNameField{ID: "790", Name: "CPT-2015"},
+			Summary:          "Short",
+			BillableDuration: time.Hour,
 		},
+	}
+
+	opts := &client.UploadOpts{User: "steve-rogers", LimitPolicy: client.LimitPolicyTransform}
+
+	violations, err := tempoClient.(client.LimitChecker).CheckLimits(entries, opts)
+	require.Nil(t, err)
+	require.Len(t, violations, 4)
+	require.Equal(t, "comment", violations[0].Field)
+	require.Equal(t, tempo.DefaultTeamAttribute, violations[1].Field)
+	require.Equal(t, "date", violations[2].Field)
+	require.False(t, violations[2].Transformable)
+	require.Equal(t, tempo.DefaultTeamAttribute, violations[3].Field)
+
+	payloads, err := tempoClient.(client.PayloadPreviewer).PreviewPayloads(entries[:1], opts)
+	require.Nil(t, err)
+
+	var uploadEntry tempo.UploadEntry
+	require.Nil(t, json.Unmarshal(payloads[0].Body, &uploadEntry))
+	require.Equal(t, "Meet with…", uploadEntry.Comment)
+	require.Equal(t, map[string]tempo.WorkAttribute{
+		tempo.DefaultRoleAttribute: {Value: "Captain"},
+	}, uploadEntry.Attributes)
+}
+
+func TestTempoClient_ValidateRemote(t *testing.T) {
+	requests := map[string]int{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		switch r.URL.Path {
+		case tempo.PathIssueSearch:
+			require.Equal(t, `key in ("CPT-2014","CPT-2015")`, r.URL.Query().Get("jql"))
+			require.Equal(t, "warn", r.URL.Query().Get("validateQuery"))
+			_, _ = w.Write([]byte(`{"issues": [{"key": "CPT-2014"}]}`))
+		case tempo.PathWorkAttributes:
+			_, _ = w.Write([]byte(`[
+				{"key": "_Team_", "type": {"value": "STATIC_LIST"}, "staticListValues": [{"value": "Avengers", "removed": true}]},
+				{"key": "_Role_", "type": {"value": "STATIC_LIST"}, "staticListValues": [{"value": "Captain"}]},
+				{"key": "_Classification_", "type": {"value": "ACCOUNT"}}
+			]`))
+		case tempo.PathAccounts:
+			_, _ = w.Write([]byte(`[{"key": "CAPEX", "status": "OPEN"}, {"key": "OPEX", "status": "CLOSED"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL: mockServer.URL,
+		TeamRoles: []tempo.TeamRole{
+			{Team: "Avengers", Role: "Captain"},
+		},
+	})
+	require.Nil(t, err)
+
+	entries := worklog.Entries{
 		{
-			Comment:               entries[2].Notes,
-			IncludeNonWorkingDays: true,
-			OriginTaskID:          entries[2].Task.ID,
-			Started:               utils.DateFormatISO8601.Format(entries[2].Start.Local()),
-			BillableSeconds:       1,
-			TimeSpentSeconds:      60,
-			Worker:                uploadOpts.User,
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{worklog.AttributeClassification: "CAPEX"},
 		},
 		{
-			Comment:               entries[3].Notes,
-			IncludeNonWorkingDays: true,
-			OriginTaskID:          entries[3].Task.ID,
-			Started:               utils.DateFormatISO8601.Format(entries[3].Start.Local()),
-			BillableSeconds:       0,
-			TimeSpentSeconds:      60,
-			Worker:                uploadOpts.User,
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Fight with The Winter Soldier",
+			Start:            time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC),
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{worklog.AttributeClassification: "OPEX"},
+		},
+		{
+			Task:             worklog.IDNameField{ID: "790", Name: "CPT-2015"},
+			Summary:          "Defrost",
+			Start:            time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC),
+			BillableDuration: time.Hour,
 		},
 	}
 
-	mockServer := newMockServer(t, &mockServerOpts{
-		Path:        tempo.PathWorklogCreate,
-		Method:      http.MethodPost,
-		StatusCode:  http.StatusOK,
-		Username:    clientUsername,
-		Password:    clientPassword,
-		RequestData: &responseEntries,
+	report, err := client.ValidateRemote(context.Background(), tempoClient, entries, &client.UploadOpts{User: "steve-rogers"})
+	require.Nil(t, err)
+
+	require.Equal(t, []client.RemoteCheck{
+		{Kind: client.RemoteCheckAccount, Value: "CAPEX", Entries: 1},
+		{Kind: client.RemoteCheckAccount, Value: "OPEX", Entries: 1, Problem: "account is closed"},
+		{Kind: client.RemoteCheckAttribute, Value: "_Role_=Captain", Entries: 3},
+		{Kind: client.RemoteCheckAttribute, Value: "_Team_=Avengers", Entries: 3, Problem: "\"Avengers\" is removed from the static list"},
+		{Kind: client.RemoteCheckIssue, Value: "CPT-2014", Entries: 2},
+		{Kind: client.RemoteCheckIssue, Value: "CPT-2015", Entries: 1, Problem: "issue does not exist or it is not visible"},
+	}, report.Checks)
+	require.False(t, report.IsGo())
+
+	require.Equal(t, map[string]int{
+		tempo.PathIssueSearch:    1,
+		tempo.PathWorkAttributes: 1,
+		tempo.PathAccounts:       1,
+	}, requests)
+}
+
+func TestTempoClient_VerifyUpload(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf(tempo.PathWorklog, "1"):
+			_, _ = w.Write([]byte(`{
+				"tempoWorklogId": 1,
+				"comment": "Meet with The Winter Soldier",
+				"started": "2021-10-02 00:00:00.000",
+				"billableSeconds": 3600,
+				"timeSpentSeconds": 3600,
+				"worker": "steve-rogers",
+				"issue": {"key": "CPT-2014"},
+				"attributes": {"_Team_": {"value": "Avengers"}}
+			}`))
+		case fmt.Sprintf(tempo.PathWorklog, "2"):
+			_, _ = w.Write([]byte(`{
+				"tempoWorklogId": 2,
+				"comment": "Fight with The",
+				"started": "2021-10-02 00:00:00.000",
+				"billableSeconds": 0,
+				"timeSpentSeconds": 3600,
+				"worker": "steve-rogers",
+				"issue": {"key": "CPT-2014"}
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL: mockServer.URL,
+		TeamRoles: []tempo.TeamRole{
+			{Team: "Avengers"},
+		},
+	})
+	require.Nil(t, err)
+
+	newMutation := func(id string, summary string) client.Mutation {
+		return client.Mutation{
+			Entry: worklog.Entry{
+				Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+				Summary:          summary,
+				Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+				BillableDuration: time.Hour,
+			},
+			Method:     http.MethodPost,
+			URL:        mockServer.URL + tempo.PathWorklogCreate,
+			StatusCode: http.StatusOK,
+			ResourceID: id,
+		}
+	}
+
+	mutations := []client.Mutation{
+		newMutation("1", "Meet with The Winter Soldier"),
+		newMutation("2", "Fight with The Winter Soldier"),
+		newMutation("3", "Defrost"),
+		{URL: mockServer.URL + fmt.Sprintf(tempo.PathIssueComment, "CPT-2014"), ResourceID: "10"},
+	}
+
+	verifications, err := client.VerifySample(context.Background(), tempoClient, mutations, 10, nil, &client.UploadOpts{User: "steve-rogers"})
+	require.Nil(t, err)
+	require.Len(t, verifications, 3)
+
+	require.True(t, verifications[0].IsVerified())
+	require.Equal(t, []client.FieldMismatch{
+		{Field: "billableSeconds", Sent: "3600", Received: "0"},
+		{Field: "comment", Sent: "Fight with The Winter Soldier", Received: "Fight with The"},
+		{Field: "attributes._Team_", Sent: "Avengers", Received: ""},
+	}, verifications[1].Mismatches)
+	require.Equal(t, client.ErrorKindNotFound, client.KindOf(verifications[2].Err))
+}
+
+func TestTempoClient_FetchEntries_OAuth(t *testing.T) {
+	var rotated string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/oauth/token/" {
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, client.GrantTypeRefreshToken, r.PostForm.Get("grant_type"))
+			require.Equal(t, "refresh-token", r.PostForm.Get("refresh_token"))
+			require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+			require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+			_, _ = w.Write([]byte(`{"access_token":"access-token","refresh_token":"rotated-refresh-token","expires_in":3600}`))
+			return
+		}
+
+		require.Equal(t, tempo.PathWorklogSearch, r.URL.Path)
+		require.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer mockServer.Close()
+
+	fetcher, err := tempo.NewFetcher(&tempo.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: mockServer.URL,
+		OAuth: &client.OAuthOpts{
+			TokenURL:     mockServer.URL + "/oauth/token/",
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			RotateRefreshToken: func(_ context.Context, refreshToken string) error {
+				rotated = refreshToken
+				return nil
+			},
+		},
+		RefreshToken: "refresh-token",
+	})
+	require.Nil(t, err)
+
+	warmer, ok := fetcher.(client.CredentialWarmer)
+	require.True(t, ok, "OAuth client must refresh its credentials")
+	require.Nil(t, warmer.WarmUpCredentials(context.Background(), time.Minute))
+	require.Equal(t, "rotated-refresh-token", rotated)
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "steve-rogers",
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.Nil(t, err)
+	require.Empty(t, entries)
+}
+
+func TestNewFetcher_OAuthNoRefreshToken(t *testing.T) {
+	_, err := tempo.NewFetcher(&tempo.ClientOpts{
+		BaseURL: "https://api.tempo.io",
+		OAuth:   &client.OAuthOpts{ClientID: "client-id"},
 	})
+	require.Error(t, err)
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	authorizationURL, err := tempo.AuthorizationURL("https://example.atlassian.net", "client-id", "https://example.com/callback")
+	require.Nil(t, err)
+	require.Equal(t, "https://example.atlassian.net/plugins/servlet/ac/io.tempo.jira/oauth-authorize/?client_id=client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback", authorizationURL)
+}
+
+func TestTempoClient_CorrectEntries(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local)
+
+	var mu sync.Mutex
+	requests := map[string]*tempo.UploadEntry{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == tempo.PathWorklogSearch:
+			var params tempo.SearchParams
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&params))
+			require.Equal(t, tempo.SearchParams{From: "2021-10-02", To: "2021-10-02", Worker: "steve-rogers"}, params)
+
+			_, _ = w.Write([]byte(`[
+				{"id": 3, "timeSpentSeconds": 3600, "billableSeconds": 3600, "issue": {"key": "CPT-2014"}},
+				{"id": 4, "timeSpentSeconds": 600, "billableSeconds": 600, "issue": {"key": "CPT-2014"}},
+				{"id": 5, "timeSpentSeconds": 7200, "billableSeconds": 7200, "issue": {"key": "SHD-2012"}}
+			]`))
+		case r.Method == http.MethodGet && r.URL.Path == fmt.Sprintf(tempo.PathWorklog, "3"):
+			_, _ = w.Write([]byte(`{
+				"tempoWorklogId": 3,
+				"comment": "Meet with The Winter Soldier",
+				"started": "2021-10-02 00:00:00.000",
+				"billableSeconds": 3600,
+				"timeSpentSeconds": 3600,
+				"worker": "steve-rogers",
+				"issue": {"key": "CPT-2014"},
+				"attributes": {"_Team_": {"value": "Avengers"}}
+			}`))
+		case r.Method == http.MethodPut || r.Method == http.MethodDelete:
+			var uploadEntry *tempo.UploadEntry
+			if r.Method == http.MethodPut {
+				uploadEntry = &tempo.UploadEntry{}
+				require.Nil(t, json.NewDecoder(r.Body).Decode(uploadEntry))
+			}
+
+			mu.Lock()
+			requests[r.Method+" "+r.URL.Path] = uploadEntry
+			mu.Unlock()
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
 	defer mockServer.Close()
 
 	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
@@ -598,15 +1175,112 @@ func TestTempoClient_UploadEntries_RoundToClosestMinute(t *testing.T) {
 			Timeout: client.DefaultRequestTimeout,
 		},
 		BasicAuth: client.BasicAuth{
-			Username: clientUsername,
-			Password: clientPassword,
+			Username: "Thor",
+			Password: "The strongest Avenger",
 		},
 		BaseURL: mockServer.URL,
 	})
 	require.Nil(t, err)
 
-	errChan := make(chan error)
-	tempoClient.UploadEntries(context.Background(), entries, errChan, uploadOpts)
+	entries := worklog.Entries{
+		{
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: time.Hour * 2,
+			Replaces:         "1",
+		},
+		{
+			Task:     worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:  "Fight with The Winter Soldier",
+			Start:    start,
+			Replaces: "2",
+		},
+		{
+			Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+			Summary:          "Meet with The Winter Soldier",
+			Start:            start,
+			BillableDuration: -time.Minute * 30,
+		},
+		{
+			Task:               worklog.IDNameField{ID: "321", Name: "SHD-2012"},
+			Summary:            "Assemble the Avengers",
+			Start:              start,
+			UnbillableDuration: -time.Hour * 3,
+		},
+	}
+
+	errChan := make(chan error, len(entries))
+	tempoClient.(client.Corrector).CorrectEntries(context.Background(), entries, errChan, &client.UploadOpts{User: "steve-rogers"})
+
+	var errs []error
+	for i := 0; i < len(entries); i++ {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrCorrectedWorklogNotFound)
+	require.Equal(t, &entries[3], client.EntryOf(errs[0]))
+
+	require.Equal(t, map[string]*tempo.UploadEntry{
+		"PUT " + fmt.Sprintf(tempo.PathWorklog, "1"): {
+			Comment:               "Meet with The Winter Soldier",
+			IncludeNonWorkingDays: true,
+			OriginTaskID:          "CPT-2014",
+			Started:               "2021-10-02",
+			BillableSeconds:       7200,
+			TimeSpentSeconds:      7200,
+			Worker:                "steve-rogers",
+		},
+		"DELETE " + fmt.Sprintf(tempo.PathWorklog, "2"): nil,
+		"PUT " + fmt.Sprintf(tempo.PathWorklog, "3"): {
+			Comment:               "Meet with The Winter Soldier",
+			IncludeNonWorkingDays: true,
+			OriginTaskID:          "CPT-2014",
+			Started:               "2021-10-02",
+			BillableSeconds:       1800,
+			TimeSpentSeconds:      1800,
+			Worker:                "steve-rogers",
+			Attributes:            map[string]tempo.WorkAttribute{"_Team_": {Value: "Avengers"}},
+		},
+	}, requests)
+}
+
+func TestTempoClient_PreviewPayloads_Corrections(t *testing.T) {
+	tempoClient, err := tempo.NewUploader(&tempo.ClientOpts{
+		BasicAuth: client.BasicAuth{
+			Username: "Thor",
+			Password: "The strongest Avenger",
+		},
+		BaseURL: "https://tempo.example.com",
+	})
+	require.Nil(t, err)
+
+	entry := worklog.Entry{
+		Task:             worklog.IDNameField{ID: "789", Name: "CPT-2014"},
+		Summary:          "Meet with The Winter Soldier",
+		Start:            time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		BillableDuration: time.Hour,
+		Replaces:         "1",
+	}
+
+	deletion := entry
+	deletion.BillableDuration = 0
+
+	negative := entry
+	negative.BillableDuration = -time.Hour
+	negative.Replaces = ""
+
+	payloads, err := tempoClient.(client.PayloadPreviewer).PreviewPayloads(worklog.Entries{entry, deletion, negative}, &client.UploadOpts{User: "steve-rogers"})
+	require.Nil(t, err)
+	require.Len(t, payloads, 2)
+
+	require.Equal(t, http.MethodPut, payloads[0].Method)
+	require.Equal(t, "https://tempo.example.com"+fmt.Sprintf(tempo.PathWorklog, "1"), payloads[0].URL)
+	require.Contains(t, string(payloads[0].Body), `"timeSpentSeconds":3600`)
 
-	require.Empty(t, errChan, "cannot fetch entries")
+	require.Equal(t, http.MethodDelete, payloads[1].Method)
+	require.Nil(t, payloads[1].Body)
 }
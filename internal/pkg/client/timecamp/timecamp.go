@@ -0,0 +1,292 @@
+package timecamp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of TimeCamp.
+	DefaultURL string = "https://app.timecamp.com"
+	// PathEntries is the endpoint used to list the time entries.
+	PathEntries string = "/third_party/api/entries"
+	// PathTasks is the endpoint used to list the tasks, including the
+	// projects at the top of the task hierarchy.
+	PathTasks string = "/third_party/api/tasks"
+	// RootTaskID is the parent ID of the tasks at the top of the hierarchy.
+	RootTaskID string = "0"
+
+	// AttributeTaskPath is the name of the attribute set to the names of the
+	// tasks from the top of the hierarchy to the task of the entry, separated
+	// by slashes, like "Website / CPT-123 Fix thing".
+	AttributeTaskPath string = "timecamp.task_path"
+
+	// maxTaskDepth limits walking up the task hierarchy, in case the parents
+	// of the tasks form a cycle.
+	maxTaskDepth int = 32
+)
+
+// Tag represents a tag of a time entry.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// FetchEntry represents the time entry fetched from TimeCamp. The date is in
+// YYYY-MM-DD format and the start time is in HH:MM:SS format, in the timezone
+// of the user. TimeCamp returns the IDs as strings or numbers, and the billable
+// flag as 0 or 1.
+type FetchEntry struct {
+	ID          json.Number    `json:"id"`
+	TaskID      json.Number    `json:"task_id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Date        string         `json:"date"`
+	StartTime   string         `json:"start_time"`
+	Duration    utils.Duration `json:"duration"`
+	Billable    json.Number    `json:"billable"`
+	Tags        []Tag          `json:"tags"`
+}
+
+// Start returns the start date of the entry, combining its date and start
+// time in the local timezone.
+func (e *FetchEntry) Start() (time.Time, error) {
+	return utils.ParseTimestamp(e.Date+" "+e.StartTime, time.Local)
+}
+
+// IsBillable returns true if the entry is billable.
+func (e *FetchEntry) IsBillable() bool {
+	return e.Billable.String() == "1"
+}
+
+// Task represents a task of the task hierarchy. The tasks at the top of the
+// hierarchy are the projects.
+type Task struct {
+	TaskID   json.Number `json:"task_id"`
+	ParentID json.Number `json:"parent_id"`
+	Name     string      `json:"name"`
+}
+
+// Tasks represents the tasks by their ID, as returned by TimeCamp.
+type Tasks map[string]Task
+
+// UnmarshalJSON decodes the tasks keyed by their ID. TimeCamp returns an
+// empty array instead of an empty object if there are no tasks.
+func (t *Tasks) UnmarshalJSON(data []byte) error {
+	tasks := map[string]Task{}
+	if err := json.Unmarshal(data, &tasks); err == nil {
+		*t = tasks
+		return nil
+	}
+
+	var taskList []Task
+	if err := json.Unmarshal(data, &taskList); err != nil {
+		return err
+	}
+
+	*t = Tasks{}
+	for _, task := range taskList {
+		(*t)[task.TaskID.String()] = task
+	}
+
+	return nil
+}
+
+// Path returns the task having the ID and its ancestors, from the top of the
+// hierarchy to the task. If the task is not known, nil returns.
+func (t Tasks) Path(taskID string) []Task {
+	var path []Task
+
+	for depth := 0; depth < maxTaskDepth; depth++ {
+		task, ok := t[taskID]
+		if !ok {
+			break
+		}
+
+		path = append([]Task{task}, path...)
+
+		taskID = task.ParentID.String()
+		if taskID == RootTaskID || taskID == "" {
+			break
+		}
+	}
+
+	return path
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Token is the API token used to authenticate.
+	Token string
+}
+
+type timeCampClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator client.Authenticator
+}
+
+func (c *timeCampClient) parseEntries(fetchedEntries []FetchEntry, tasks Tasks) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, fetchedEntry := range fetchedEntries {
+		startDate, err := fetchedEntry.Start()
+		if err != nil {
+			return nil, err
+		}
+
+		billableDuration := fetchedEntry.Duration.Duration
+		unbillableDuration := time.Duration(0)
+
+		if !fetchedEntry.IsBillable() {
+			unbillableDuration = billableDuration
+			billableDuration = 0
+		}
+
+		entry := worklog.Entry{
+			Summary:            fetchedEntry.Description,
+			Notes:              fetchedEntry.Description,
+			Start:              startDate,
+			BillableDuration:   billableDuration,
+			UnbillableDuration: unbillableDuration,
+			Provenance:         worklog.Provenance{SourceIDs: []string{fetchedEntry.ID.String()}},
+		}
+
+		// The top of the task hierarchy is the project, and the task of the
+		// entry is the task, unless the entry is logged on the project itself
+		path := tasks.Path(fetchedEntry.TaskID.String())
+		if len(path) == 0 {
+			path = []Task{{TaskID: fetchedEntry.TaskID, Name: fetchedEntry.Name}}
+		}
+
+		entry.Project = worklog.IDNameField{ID: path[0].TaskID.String(), Name: path[0].Name}
+		if len(path) > 1 {
+			task := path[len(path)-1]
+			entry.Task = worklog.IDNameField{ID: task.TaskID.String(), Name: task.Name}
+		}
+
+		var names []string
+		for _, task := range path {
+			names = append(names, task.Name)
+		}
+
+		entry.SetAttribute(AttributeTaskPath, strings.Join(names, " / "))
+
+		// The description of the entries is optional, hence the name of the
+		// task is used instead
+		if entry.Summary == "" {
+			entry.Summary = path[len(path)-1].Name
+		}
+
+		entry.AddLinks(utils.ExtractURLs(fetchedEntry.Description)...)
+
+		// The issue keys are usually stored in the names of the tasks, hence
+		// the task hierarchy is searched from the task of the entry upwards.
+		// The matching tags take precedence over the task hierarchy.
+		hints := &worklog.TaskHints{Description: fetchedEntry.Description}
+		for i := len(path) - 1; i >= 0; i-- {
+			hints.Texts = append(hints.Texts, path[i].Name)
+		}
+
+		for _, tag := range fetchedEntry.Tags {
+			hints.Tags = append(hints.Tags, worklog.IDNameField{ID: tag.Name, Name: tag.Name})
+		}
+
+		entry.TaskHints = hints
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *timeCampClient) get(ctx context.Context, path string, params map[string]string, result interface{}) error {
+	reqURL, err := c.URL(path, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(resp, result)
+}
+
+func (c *timeCampClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	params := map[string]string{
+		"from":       utils.DateFormatISO8601.Format(opts.Start),
+		"to":         utils.DateFormatISO8601.Format(opts.LastDay()),
+		"opt_fields": "tags",
+	}
+
+	// The entries of the owner of the token are returned by default
+	if opts.User != "" {
+		params["user_ids"] = opts.User
+	}
+
+	var fetchedEntries []FetchEntry
+	if err := c.get(ctx, PathEntries, params, &fetchedEntries); err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	// The entries return the task only, hence the hierarchy of the tasks is
+	// resolved by the list of tasks
+	tasks := Tasks{}
+	if len(fetchedEntries) != 0 {
+		if err := c.get(ctx, PathTasks, map[string]string{}, &tasks); err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+	}
+
+	entries, err := c.parseEntries(fetchedEntries, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	return entries, nil
+}
+
+// NewFetcher returns a new TimeCamp client for fetching time entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.BaseURL == "" {
+		return nil, errors.New("no TimeCamp URL provided")
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Bearer", opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeCampClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		authenticator: authenticator,
+	}, nil
+}
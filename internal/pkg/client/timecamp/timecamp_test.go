@@ -0,0 +1,205 @@
+package timecamp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/timecamp"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// The "Review" task is a subtask of the "CPT-123 Fix thing" task of the
+	// "Website" project.
+	tasksResponse = `{
+		"10": {"task_id": "10", "parent_id": "0", "name": "Website"},
+		"11": {"task_id": "11", "parent_id": "10", "name": "CPT-123 Fix thing"},
+		"12": {"task_id": "12", "parent_id": "11", "name": "Review"}
+	}`
+
+	entriesResponse = `[
+		{"id": "1", "task_id": "12", "name": "Review", "description": "Reviewing", "date": "2021-10-01", "start_time": "09:00:00", "duration": "5400", "billable": 1, "tags": []},
+		{"id": 2, "task_id": 10, "name": "Website", "description": "", "date": "2021-10-01", "start_time": "14:00:00", "duration": "1800", "billable": 0, "tags": [{"name": "CPT-456"}]}
+	]`
+)
+
+var website = worklog.IDNameField{ID: "10", Name: "Website"}
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+func newMockServer(t *testing.T, entries string, tasks string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer s3cr3t", r.Header.Get("Authorization"))
+
+		var err error
+
+		switch r.URL.Path {
+		case timecamp.PathEntries:
+			query := r.URL.Query()
+			require.Equal(t, "2021-10-01", query.Get("from"))
+			require.Equal(t, "2021-10-02", query.Get("to"))
+			require.Equal(t, "tags", query.Get("opt_fields"))
+
+			_, err = w.Write([]byte(entries))
+		case timecamp.PathTasks:
+			_, err = w.Write([]byte(tasks))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		require.Nil(t, err)
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string) client.Fetcher {
+	fetcher, err := timecamp.NewFetcher(&timecamp.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: serverURL,
+		Token:   "s3cr3t",
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func newFetchOpts() *client.FetchOpts {
+	return &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	}
+}
+
+func TestTasks_Path(t *testing.T) {
+	var tasks timecamp.Tasks
+	require.Nil(t, json.Unmarshal([]byte(tasksResponse), &tasks))
+
+	var names []string
+	for _, task := range tasks.Path("12") {
+		names = append(names, task.Name)
+	}
+
+	require.Equal(t, []string{"Website", "CPT-123 Fix thing", "Review"}, names)
+	require.Nil(t, tasks.Path("99"))
+}
+
+func TestTasks_Path_Cycle(t *testing.T) {
+	tasks := timecamp.Tasks{
+		"1": {TaskID: "1", ParentID: "2", Name: "First"},
+		"2": {TaskID: "2", ParentID: "1", Name: "Second"},
+	}
+
+	require.NotEmpty(t, tasks.Path("1"))
+}
+
+func TestTasks_UnmarshalJSON_EmptyArray(t *testing.T) {
+	var tasks timecamp.Tasks
+	require.Nil(t, json.Unmarshal([]byte(`[]`), &tasks))
+	require.Empty(t, tasks)
+}
+
+func TestTimeCampClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, entriesResponse, tasksResponse)
+	defer mockServer.Close()
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          website,
+			Task:             worklog.IDNameField{ID: "12", Name: "Review"},
+			Summary:          "Reviewing",
+			Notes:            "Reviewing",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Minute * 90,
+			Attributes:       map[string]string{timecamp.AttributeTaskPath: "Website / CPT-123 Fix thing / Review"},
+			TaskHints: &worklog.TaskHints{
+				Description: "Reviewing",
+				Texts:       []string{"Review", "CPT-123 Fix thing", "Website"},
+			},
+			Provenance: worklog.Provenance{SourceIDs: []string{"1"}},
+		},
+		{
+			Project:            website,
+			Summary:            "Website",
+			Start:              at(1, 14, 0),
+			UnbillableDuration: time.Minute * 30,
+			Attributes:         map[string]string{timecamp.AttributeTaskPath: "Website"},
+			TaskHints: &worklog.TaskHints{
+				Texts: []string{"Website"},
+				Tags:  []worklog.IDNameField{{ID: "CPT-456", Name: "CPT-456"}},
+			},
+			Provenance: worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, expectedEntries, entries)
+}
+
+func TestTimeCampClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, entriesResponse, tasksResponse)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 2)
+
+	// The key is extracted from the name of the parent task
+	require.Equal(t, worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}, entries[0].Task)
+	require.Equal(t, "Reviewing", entries[0].Summary)
+
+	// The matching tags take precedence over the task hierarchy
+	require.Equal(t, worklog.IDNameField{ID: "CPT-456", Name: "CPT-456"}, entries[1].Task)
+	require.Equal(t, time.Minute*30, entries[1].UnbillableDuration)
+}
+
+func TestTimeCampClient_FetchEntries_UnknownTask(t *testing.T) {
+	mockServer := newMockServer(t, `[{"id": "1", "task_id": "99", "name": "Archived", "description": "Cleanup", "date": "2021-10-01", "start_time": "09:00:00", "duration": "3600", "billable": "1"}]`, `[]`)
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, err)
+	require.Equal(t, worklog.Entries{
+		{
+			Project:          worklog.IDNameField{ID: "99", Name: "Archived"},
+			Summary:          "Cleanup",
+			Notes:            "Cleanup",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Hour,
+			Attributes:       map[string]string{timecamp.AttributeTaskPath: "Archived"},
+			Provenance:       worklog.Provenance{SourceIDs: []string{"1"}},
+			TaskHints: &worklog.TaskHints{
+				Description: "Cleanup",
+				Texts:       []string{"Archived"},
+			},
+		},
+	}, entries)
+}
+
+func TestTimeCampClient_FetchEntries_Unauthorized(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), newFetchOpts())
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoURL(t *testing.T) {
+	_, err := timecamp.NewFetcher(&timecamp.ClientOpts{Token: "s3cr3t"})
+	require.EqualError(t, err, "no TimeCamp URL provided")
+}
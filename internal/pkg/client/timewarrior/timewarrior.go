@@ -1,10 +1,17 @@
 package timewarrior
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
@@ -12,6 +19,16 @@ import (
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 )
 
+var (
+	// dataFileRegex matches the names of the monthly data files, like
+	// "2021-10.data".
+	dataFileRegex = regexp.MustCompile(`^\d{4}-\d{2}\.data$`)
+
+	// ErrInvalidDataLine returns when a line of the data files cannot be
+	// parsed.
+	ErrInvalidDataLine = errors.New("invalid data line")
+)
+
 // FetchEntry represents the entry exported from Timewarrior.
 type FetchEntry struct {
 	ID         int      `json:"id"`
@@ -30,6 +47,11 @@ type FetchEntry struct {
 type ClientOpts struct {
 	client.BaseClientOpts
 	client.CLIClient
+	// DataDir is the directory of Timewarrior's data files, like
+	// "~/.timewarrior/data". If set, the data files are read instead of
+	// executing the export command, so Timewarrior does not need to be
+	// installed.
+	DataDir         string
 	UnbillableTag   string
 	ClientTagRegex  string
 	ProjectTagRegex string
@@ -41,19 +63,18 @@ type timewarriorClient struct {
 	clientTagRegex  *regexp.Regexp
 	projectTagRegex *regexp.Regexp
 	unbillableTag   string
+	dataDir         string
 }
 
-func (c *timewarriorClient) parseEntry(entry FetchEntry, opts *client.FetchOpts) (worklog.Entries, error) {
-	var entries worklog.Entries
-
+func (c *timewarriorClient) parseEntry(entry FetchEntry) (worklog.Entry, error) {
 	startDate, err := time.ParseInLocation(utils.DateFormatRFC3339Compact.String(), entry.Start, time.Local)
 	if err != nil {
-		return nil, err
+		return worklog.Entry{}, err
 	}
 
 	endDate, err := time.ParseInLocation(utils.DateFormatRFC3339Compact.String(), entry.End, time.Local)
 	if err != nil {
-		return nil, err
+		return worklog.Entry{}, err
 	}
 
 	worklogEntry := worklog.Entry{
@@ -62,8 +83,11 @@ func (c *timewarriorClient) parseEntry(entry FetchEntry, opts *client.FetchOpts)
 		Start:              startDate,
 		BillableDuration:   endDate.Sub(startDate),
 		UnbillableDuration: 0,
+		Provenance:         worklog.Provenance{SourceIDs: []string{strconv.Itoa(entry.ID)}},
 	}
 
+	worklogEntry.AddLinks(utils.ExtractURLs(entry.Annotation)...)
+
 	for _, tag := range entry.Tags {
 		if tag == c.unbillableTag {
 			worklogEntry.UnbillableDuration = worklogEntry.BillableDuration
@@ -78,38 +102,29 @@ func (c *timewarriorClient) parseEntry(entry FetchEntry, opts *client.FetchOpts)
 				ID:   tag,
 				Name: tag,
 			}
-		} else if utils.IsRegexSet(opts.TagsAsTasksRegex) && opts.TagsAsTasksRegex.MatchString(tag) {
-			worklogEntry.Task = worklog.IDNameField{
-				ID:   tag,
-				Name: tag,
-			}
 		}
 	}
 
-	// If the task was not found in tags, make sure to set it to annotation
-	if !worklogEntry.Task.IsComplete() {
-		worklogEntry.Task = worklog.IDNameField{
-			ID:   entry.Annotation,
-			Name: entry.Annotation,
-		}
+	// The task is set to the annotation, unless tags are matching the task
+	// pattern
+	worklogEntry.Task = worklog.IDNameField{
+		ID:   entry.Annotation,
+		Name: entry.Annotation,
 	}
 
-	if utils.IsRegexSet(opts.TagsAsTasksRegex) && len(entry.Tags) > 0 {
-		var tags []worklog.IDNameField
-		for _, tag := range entry.Tags {
-			tags = append(tags, worklog.IDNameField{
-				ID:   tag,
-				Name: tag,
-			})
-		}
+	var tags []worklog.IDNameField
+	for _, tag := range entry.Tags {
+		tags = append(tags, worklog.IDNameField{
+			ID:   tag,
+			Name: tag,
+		})
+	}
 
-		splitEntries := worklogEntry.SplitByTagsAsTasks(worklogEntry.Summary, opts.TagsAsTasksRegex, tags)
-		entries = append(entries, splitEntries...)
-	} else {
-		entries = append(entries, worklogEntry)
+	if len(tags) > 0 {
+		worklogEntry.TaskHints = &worklog.TaskHints{Tags: tags}
 	}
 
-	return entries, nil
+	return worklogEntry, nil
 }
 
 func (c *timewarriorClient) executeCommand(ctx context.Context, subcommand string, entries *[]FetchEntry, opts *client.FetchOpts) error {
@@ -118,8 +133,8 @@ func (c *timewarriorClient) executeCommand(ctx context.Context, subcommand strin
 	arguments = append(
 		arguments,
 		[]string{
-			"from", utils.DateFormatRFC3339Local.Format(opts.Start),
-			"to", utils.DateFormatRFC3339Local.Format(opts.End),
+			"from", utils.DateFormatRFC3339Local.Format(opts.Start.Local()),
+			"to", utils.DateFormatRFC3339Local.Format(opts.End.Local()),
 		}...,
 	)
 
@@ -140,35 +155,206 @@ func (c *timewarriorClient) executeCommand(ctx context.Context, subcommand strin
 	return nil
 }
 
+// splitDataLine splits the line of a data file into words. The quoted words
+// are unquoted and unescaped. Since a quoted "#" is a tag, not a separator, the
+// separators are returned as nil.
+func splitDataLine(line string) ([]*string, error) {
+	var words []*string
+	var word strings.Builder
+
+	isWord, wasQuoted, isQuoted, isEscaped := false, false, false, false
+
+	appendWord := func() {
+		if w := word.String(); !wasQuoted && w == "#" {
+			words = append(words, nil)
+		} else {
+			words = append(words, &w)
+		}
+
+		word.Reset()
+		isWord, wasQuoted = false, false
+	}
+
+	for _, r := range line {
+		switch {
+		case isEscaped:
+			word.WriteRune(r)
+			isEscaped = false
+		case isQuoted && r == '\\':
+			isEscaped = true
+		case r == '"':
+			isQuoted = !isQuoted
+			isWord, wasQuoted = true, true
+		case !isQuoted && r == ' ':
+			if isWord {
+				appendWord()
+			}
+		default:
+			word.WriteRune(r)
+			isWord = true
+		}
+	}
+
+	if isQuoted || isEscaped {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDataLine, line)
+	}
+
+	if isWord {
+		appendWord()
+	}
+
+	return words, nil
+}
+
+// parseDataLine parses an interval of a data file, like
+// `inc 20211012T054408Z - 20211012T054420Z # TASK-123 "other tag" # "note"`.
+// The end of the open interval, which is still tracked, is empty.
+func parseDataLine(line string) (FetchEntry, error) {
+	var entry FetchEntry
+
+	words, err := splitDataLine(line)
+	if err != nil {
+		return entry, err
+	}
+
+	if len(words) < 2 || words[0] == nil || *words[0] != "inc" || words[1] == nil {
+		return entry, fmt.Errorf("%w: %s", ErrInvalidDataLine, line)
+	}
+
+	entry.Start = *words[1]
+	words = words[2:]
+
+	if len(words) >= 2 && words[0] != nil && *words[0] == "-" && words[1] != nil {
+		entry.End = *words[1]
+		words = words[2:]
+	}
+
+	if len(words) == 0 {
+		return entry, nil
+	} else if words[0] != nil {
+		return entry, fmt.Errorf("%w: %s", ErrInvalidDataLine, line)
+	}
+
+	words = words[1:]
+	for len(words) > 0 && words[0] != nil {
+		entry.Tags = append(entry.Tags, *words[0])
+		words = words[1:]
+	}
+
+	if len(words) == 0 {
+		return entry, nil
+	} else if len(words) != 2 || words[1] == nil {
+		return entry, fmt.Errorf("%w: %s", ErrInvalidDataLine, line)
+	}
+
+	entry.Annotation = *words[1]
+
+	return entry, nil
+}
+
+// readDataFiles reads the intervals of the monthly data files. The intervals
+// are numbered like by the export command, the latest interval being the
+// first. The open interval is skipped, as it is still tracked.
+func (c *timewarriorClient) readDataFiles() ([]FetchEntry, error) {
+	files, err := os.ReadDir(c.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && dataFileRegex.MatchString(file.Name()) {
+			names = append(names, file.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	var intervals []FetchEntry
+	for _, name := range names {
+		file, err := os.Open(filepath.Join(c.dataDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+
+			interval, err := parseDataLine(line)
+			if err != nil {
+				_ = file.Close()
+				return nil, err
+			}
+
+			intervals = append(intervals, interval)
+		}
+
+		if err = scanner.Err(); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+
+		if err = file.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []FetchEntry
+	for i, interval := range intervals {
+		if interval.End == "" {
+			continue
+		}
+
+		interval.ID = len(intervals) - i
+		entries = append(entries, interval)
+	}
+
+	return entries, nil
+}
+
 func (c *timewarriorClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
 	var fetchedEntries []FetchEntry
-	if err := c.executeCommand(ctx, "export", &fetchedEntries, opts); err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+	var err error
+
+	if c.dataDir != "" {
+		fetchedEntries, err = c.readDataFiles()
+	} else {
+		err = c.executeCommand(ctx, "export", &fetchedEntries, opts)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	var entries worklog.Entries
 	for _, entry := range fetchedEntries {
-		parsedEntries, err := c.parseEntry(entry, opts)
+		parsedEntry, err := c.parseEntry(entry)
 		if err != nil {
-			return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 		}
 
-		entries = append(entries, parsedEntries...)
+		entries = append(entries, parsedEntry)
 	}
 
-	return entries, nil
+	// Timewarrior exports the intervals overlapping the period too, while the
+	// data files contain every interval
+	return opts.FilterEntries(entries), nil
 }
 
 // NewFetcher returns a new Timewarrior client for fetching entries.
 func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
 	clientTagRegex, err := regexp.Compile(opts.ClientTagRegex)
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	projectTagRegex, err := regexp.Compile(opts.ProjectTagRegex)
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	return &timewarriorClient{
@@ -177,5 +363,6 @@ func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
 		unbillableTag:   opts.UnbillableTag,
 		clientTagRegex:  clientTagRegex,
 		projectTagRegex: projectTagRegex,
+		dataDir:         opts.DataDir,
 	}, nil
 }
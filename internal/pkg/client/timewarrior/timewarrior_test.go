@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"testing"
@@ -78,6 +79,7 @@ func TestTimewarriorClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -97,6 +99,7 @@ func TestTimewarriorClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -116,6 +119,7 @@ func TestTimewarriorClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 	}
 
@@ -136,12 +140,12 @@ func TestTimewarriorClient_FetchEntries(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := timewarriorClient.FetchEntries(context.Background(), &client.FetchOpts{
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(""),
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(""), worklog.TaskExtractionTags)
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
 
@@ -175,6 +179,7 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasksRegex_NoSplit(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -194,6 +199,7 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasksRegex_NoSplit(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -213,6 +219,7 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasksRegex_NoSplit(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 	}
 
@@ -233,12 +240,12 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasksRegex_NoSplit(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := timewarriorClient.FetchEntries(context.Background(), &client.FetchOpts{
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(`^TASK-\d+$`),
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
 
@@ -272,6 +279,7 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   end.Sub(start),
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -291,6 +299,7 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start),
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -310,6 +319,10 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start) / 2,
+			Provenance: worklog.Provenance{
+				SourceIDs:       []string{"1"},
+				Transformations: []string{"split by tags"},
+			},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -329,6 +342,10 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: end.Sub(start) / 2,
+			Provenance: worklog.Provenance{
+				SourceIDs:       []string{"1"},
+				Transformations: []string{"split by tags"},
+			},
 		},
 	}
 
@@ -349,11 +366,108 @@ func TestTimewarriorClient_FetchEntries_TagsAsTasks(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := timewarriorClient.FetchEntries(context.Background(), &client.FetchOpts{
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(`^TASK-\d+$`),
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
+
+func TestTimewarriorClient_FetchEntries_DataDir(t *testing.T) {
+	start, _ := time.ParseInLocation(utils.DateFormatRFC3339Compact.String(), "20211012T090000Z", time.Local)
+	end, _ := time.ParseInLocation(utils.DateFormatRFC3339Compact.String(), "20211012T100000Z", time.Local)
+
+	dataDir := t.TempDir()
+
+	require.Nil(t, os.WriteFile(filepath.Join(dataDir, "2021-09.data"), []byte(
+		"inc 20210930T090000Z - 20210930T100000Z # TASK-100 # \"before the period\"\n",
+	), 0600))
+
+	require.Nil(t, os.WriteFile(filepath.Join(dataDir, "2021-10.data"), []byte(
+		"inc 20211012T090000Z - 20211012T100000Z # TASK-123 \"other client\" project # \"working on the \\\"data\\\" files\"\n"+
+			"\n"+
+			"inc 20211012T100000Z - 20211012T103000Z # TASK-456 unbillable\n"+
+			"inc 20211012T110000Z\n",
+	), 0600))
+
+	// Only the monthly data files are read
+	require.Nil(t, os.WriteFile(filepath.Join(dataDir, "tags.data"), []byte("{}"), 0600))
+
+	expectedEntries := worklog.Entries{
+		{
+			Client: worklog.IDNameField{
+				ID:   "other client",
+				Name: "other client",
+			},
+			Project: worklog.IDNameField{
+				ID:   "project",
+				Name: "project",
+			},
+			Task: worklog.IDNameField{
+				ID:   "TASK-123",
+				Name: "TASK-123",
+			},
+			Summary:            "working on the \"data\" files",
+			Notes:              "working on the \"data\" files",
+			Start:              start,
+			BillableDuration:   time.Hour,
+			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"3"}},
+		},
+		{
+			Task: worklog.IDNameField{
+				ID:   "TASK-456",
+				Name: "TASK-456",
+			},
+			Start:              end,
+			BillableDuration:   0,
+			UnbillableDuration: time.Minute * 30,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"2"}},
+		},
+	}
+
+	timewarriorClient, err := timewarrior.NewFetcher(&timewarrior.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		DataDir:         dataDir,
+		UnbillableTag:   "unbillable",
+		ClientTagRegex:  "^(other client)$",
+		ProjectTagRegex: "^(project)$",
+	})
+
+	require.Nil(t, err)
+
+	entries, err := timewarriorClient.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestTimewarriorClient_FetchEntries_InvalidDataFile(t *testing.T) {
+	dataDir := t.TempDir()
+
+	require.Nil(t, os.WriteFile(filepath.Join(dataDir, "2021-10.data"), []byte(
+		"inc 20211012T090000Z - 20211012T100000Z # TASK-123 # \"unterminated\n",
+	), 0600))
+
+	timewarriorClient, err := timewarrior.NewFetcher(&timewarrior.ClientOpts{
+		DataDir: dataDir,
+	})
+
+	require.Nil(t, err)
+
+	_, err = timewarriorClient.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 12, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 13, 0, 0, 0, 0, time.Local),
+	})
+
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, timewarrior.ErrInvalidDataLine)
+}
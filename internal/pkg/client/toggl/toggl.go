@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gabor-boros/minutes/internal/pkg/client"
@@ -15,31 +16,63 @@ import (
 )
 
 const (
-	// PathWorklog is the endpoint used to search existing worklogs.
-	PathWorklog string = "/reports/api/v2/details"
+	// PathWorklog is the Reports API v3 endpoint used to search the time
+	// entries of the workspace.
+	PathWorklog string = "/reports/api/v3/workspace/%d/search/time_entries"
+	// PageSizeParam is the pagination parameter of the page size. Since the
+	// search parameters are sent in the request body, the parameter is only
+	// used to pass the page size to the request.
+	PageSizeParam string = "page_size"
+	// PathDetailedReport is the page of the detailed report showing the
+	// entries of the workspace between two days.
+	PathDetailedReport string = "/reports/detailed/%d/from/%s/to/%s"
+	// MaxRangeDays is the number of days the time entries are searched for at
+	// once, since the Reports API limits the searches to a year.
+	MaxRangeDays int = 365
+
+	// DefaultAppURL is the base URL of the Toggl Track web app.
+	DefaultAppURL string = "https://track.toggl.com"
 )
 
-// FetchEntry represents the entry fetched from Toggl Track.
+// TimeEntry represents a time entry of a detailed report row.
+type TimeEntry struct {
+	ID      int             `json:"id"`
+	Seconds int             `json:"seconds"`
+	Start   utils.Timestamp `json:"start"`
+	Stop    utils.Timestamp `json:"stop"`
+}
+
+// FetchEntry represents a row of the detailed report fetched from Toggl Track.
+// The names of the project, client, task and tags are returned since the
+// response is enriched. The response would have more fields, but those are
+// not relevant for us.
 type FetchEntry struct {
-	Client      string    `json:"client"`
-	Description string    `json:"description"`
-	Duration    int       `json:"dur"`
-	IsBillable  bool      `json:"is_billable"`
-	Project     string    `json:"project"`
-	ProjectID   int       `json:"pid"`
-	Start       time.Time `json:"start"`
-	End         time.Time `json:"end"`
-	Tags        []string  `json:"tags"`
-	Task        string    `json:"task"`
-	TaskID      int       `json:"tid"`
+	UserID      int         `json:"user_id"`
+	Username    string      `json:"username"`
+	ProjectID   int         `json:"project_id"`
+	ProjectName string      `json:"project_name"`
+	ClientName  string      `json:"client_name"`
+	TaskID      int         `json:"task_id"`
+	TaskName    string      `json:"task_name"`
+	Billable    bool        `json:"billable"`
+	Description string      `json:"description"`
+	TagNames    []string    `json:"tag_names"`
+	TimeEntries []TimeEntry `json:"time_entries"`
+	RowNumber   int         `json:"row_number"`
 }
 
-// FetchResponse represents the response of Toggl Track report APIs.
-// The response would have more fields, but those are not relevant for us.
-type FetchResponse struct {
-	TotalCount int          `json:"total_count"`
-	PerPage    int          `json:"per_page"`
-	Data       []FetchEntry `json:"data"`
+// SearchParams represents the parameters of the detailed report search.
+// StartDate and EndDate must be in the given YYYY-MM-DD format, required by
+// Toggl Track. The rows are paginated by their row number, starting from 1.
+type SearchParams struct {
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date"`
+	UserIDs        []int  `json:"user_ids,omitempty"`
+	PageSize       int    `json:"page_size"`
+	FirstRowNumber int    `json:"first_row_number"`
+	EnrichResponse bool   `json:"enrich_response"`
+	OrderBy        string `json:"order_by"`
+	OrderDir       string `json:"order_dir"`
 }
 
 // ClientOpts is the client specific options, extending client.BaseClientOpts.
@@ -48,6 +81,9 @@ type ClientOpts struct {
 	client.BasicAuth
 	BaseURL   string
 	Workspace int
+	// AppURL is the base URL of the web app, used to link the detailed report
+	// showing the fetched entries. If not set, no links are recorded.
+	AppURL string
 }
 
 type togglClient struct {
@@ -55,57 +91,70 @@ type togglClient struct {
 	*client.HTTPClient
 	authenticator client.Authenticator
 	workspace     int
+	appURL        string
 }
 
-func (c *togglClient) parseEntries(rawEntries interface{}, opts *client.FetchOpts) (worklog.Entries, error) {
-	var entries worklog.Entries
-
-	fetchedEntries, ok := rawEntries.([]FetchEntry)
-	if !ok {
-		return nil, fmt.Errorf("%v: %s", client.ErrFetchEntries, "cannot parse returned entries")
+// reportURL returns the link of the detailed report showing the entries of
+// the day the entry started, since the web app has no page per entry. If the
+// app URL is not set, an empty string returns.
+func (c *togglClient) reportURL(start time.Time) string {
+	if c.appURL == "" {
+		return ""
 	}
 
+	day := utils.DateFormatISO8601.Format(start)
+	return strings.TrimSuffix(c.appURL, "/") + fmt.Sprintf(PathDetailedReport, c.workspace, day, day)
+}
+
+func (c *togglClient) parseEntries(fetchedEntries []FetchEntry, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
 	for _, fetchedEntry := range fetchedEntries {
-		billableDuration := time.Millisecond * time.Duration(fetchedEntry.Duration)
-		unbillableDuration := time.Duration(0)
+		for _, timeEntry := range fetchedEntry.TimeEntries {
+			billableDuration := time.Second * time.Duration(timeEntry.Seconds)
+			unbillableDuration := time.Duration(0)
 
-		if !fetchedEntry.IsBillable {
-			unbillableDuration = billableDuration
-			billableDuration = 0
-		}
+			if !fetchedEntry.Billable {
+				unbillableDuration = billableDuration
+				billableDuration = 0
+			}
 
-		entry := worklog.Entry{
-			Client: worklog.IDNameField{
-				ID:   fetchedEntry.Client,
-				Name: fetchedEntry.Client,
-			},
-			Project: worklog.IDNameField{
-				ID:   strconv.Itoa(fetchedEntry.ProjectID),
-				Name: fetchedEntry.Project,
-			},
-			Task: worklog.IDNameField{
-				ID:   strconv.Itoa(fetchedEntry.TaskID),
-				Name: fetchedEntry.Task,
-			},
-			Summary:            fetchedEntry.Description,
-			Notes:              fetchedEntry.Description,
-			Start:              fetchedEntry.Start,
-			BillableDuration:   billableDuration,
-			UnbillableDuration: unbillableDuration,
-		}
+			entry := worklog.Entry{
+				Client: worklog.IDNameField{
+					ID:   fetchedEntry.ClientName,
+					Name: fetchedEntry.ClientName,
+				},
+				Project: worklog.IDNameField{
+					ID:   strconv.Itoa(fetchedEntry.ProjectID),
+					Name: fetchedEntry.ProjectName,
+				},
+				Task: worklog.IDNameField{
+					ID:   strconv.Itoa(fetchedEntry.TaskID),
+					Name: fetchedEntry.TaskName,
+				},
+				Summary:            fetchedEntry.Description,
+				Notes:              fetchedEntry.Description,
+				Start:              timeEntry.Start.Time,
+				BillableDuration:   billableDuration,
+				UnbillableDuration: unbillableDuration,
+				Provenance:         worklog.Provenance{SourceIDs: []string{strconv.Itoa(timeEntry.ID)}},
+			}
+
+			entry.AddLinks(utils.ExtractURLs(fetchedEntry.Description)...)
+			entry.AddSourceURL(c.reportURL(timeEntry.Start.Time))
 
-		if utils.IsRegexSet(opts.TagsAsTasksRegex) && len(fetchedEntry.Tags) > 0 {
 			var tags []worklog.IDNameField
-			for _, tag := range fetchedEntry.Tags {
+			for _, tag := range fetchedEntry.TagNames {
 				tags = append(tags, worklog.IDNameField{
 					ID:   tag,
 					Name: tag,
 				})
 			}
 
-			splitEntries := entry.SplitByTagsAsTasks(entry.Summary, opts.TagsAsTasksRegex, tags)
-			entries = append(entries, splitEntries...)
-		} else {
+			if len(tags) > 0 {
+				entry.TaskHints = &worklog.TaskHints{Tags: tags}
+			}
+
 			entries = append(entries, entry)
 		}
 	}
@@ -113,50 +162,104 @@ func (c *togglClient) parseEntries(rawEntries interface{}, opts *client.FetchOpt
 	return entries, nil
 }
 
-func (c *togglClient) fetchEntries(ctx context.Context, reqURL string) (interface{}, *client.PaginatedFetchResponse, error) {
+// fetchEntries fetches a page of the detailed report. The page and page size
+// set by the paginated fetch are converted to the row numbers of the search.
+// Since the total number of rows is not returned, the last page is the one
+// having fewer rows than the page size.
+func (c *togglClient) fetchEntries(ctx context.Context, reqURL string, params SearchParams) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+	searchURL, err := url.Parse(reqURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	page, err := strconv.Atoi(searchURL.Query().Get(client.DefaultPageParam))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	params.PageSize, err = strconv.Atoi(searchURL.Query().Get(PageSizeParam))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	params.FirstRowNumber = (page-1)*params.PageSize + 1
+	searchURL.RawQuery = ""
+
 	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
-		Method:  http.MethodGet,
-		Url:     reqURL,
+		Method:  http.MethodPost,
+		Url:     searchURL.String(),
 		Auth:    c.authenticator,
 		Timeout: c.Timeout,
+		Data:    &params,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
 	})
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
-	var fetchResponse FetchResponse
-	if err = json.Unmarshal(resp, &fetchResponse); err != nil {
-		return nil, nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+	var fetchedEntries []FetchEntry
+	if err = json.Unmarshal(resp, &fetchedEntries); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
 	paginatedResponse := &client.PaginatedFetchResponse{
-		EntriesPerPage: fetchResponse.PerPage,
-		TotalEntries:   fetchResponse.TotalCount,
+		EntriesPerPage: params.PageSize,
 	}
 
-	return fetchResponse.Data, paginatedResponse, err
+	if len(fetchedEntries) < params.PageSize {
+		paginatedResponse.TotalEntries = params.FirstRowNumber - 1 + len(fetchedEntries)
+	}
+
+	return fetchedEntries, paginatedResponse, nil
 }
 
-func (c *togglClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
-	fetchURL, err := c.URL(PathWorklog, map[string]string{
-		"since":        utils.DateFormatISO8601.Format(opts.Start),
-		"until":        utils.DateFormatISO8601.Format(opts.End),
-		"user_id":      opts.User,
-		"workspace_id": strconv.Itoa(c.workspace),
-		"user_agent":   "github.com/gabor-boros/minutes",
-	})
+// MaxRangeDays returns the number of days the time entries are searched for at
+// once, so longer periods are fetched in chunks.
+func (c *togglClient) MaxRangeDays() int {
+	return MaxRangeDays
+}
 
+func (c *togglClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	fetchURL, err := c.URL(fmt.Sprintf(PathWorklog, c.workspace), map[string]string{})
 	if err != nil {
-		return nil, fmt.Errorf("%v: %v", client.ErrFetchEntries, err)
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
 	}
 
-	return c.PaginatedFetch(ctx, &client.PaginatedFetchOpts{
+	params := SearchParams{
+		StartDate:      utils.DateFormatISO8601.Format(opts.Start),
+		EndDate:        utils.DateFormatISO8601.Format(opts.LastDay()),
+		EnrichResponse: true,
+		OrderBy:        "date",
+		OrderDir:       "ASC",
+	}
+
+	if opts.User != "" {
+		userID, err := strconv.Atoi(opts.User)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid user ID: %w", client.ErrFetchEntries, err)
+		}
+
+		params.UserIDs = []int{userID}
+	}
+
+	entries, err := client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[FetchEntry]{
 		BaseFetchOpts: opts,
 		URL:           fetchURL,
-		FetchFunc:     c.fetchEntries,
-		ParseFunc:     c.parseEntries,
+		PageSizeParam: PageSizeParam,
+		FetchFunc: func(ctx context.Context, reqURL string) ([]FetchEntry, *client.PaginatedFetchResponse, error) {
+			return c.fetchEntries(ctx, reqURL, params)
+		},
+		ParseFunc: c.parseEntries,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Toggl fetches the entries of whole days, regardless of the time of day
+	return opts.FilterEntries(entries), nil
 }
 
 // NewFetcher returns a new Toggl client for fetching entries.
@@ -178,5 +281,6 @@ func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
 		},
 		BaseClientOpts: &opts.BaseClientOpts,
 		workspace:      opts.Workspace,
+		appURL:         opts.AppURL,
 	}, nil
 }
@@ -3,9 +3,9 @@ package toggl_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 	"regexp"
 	"strconv"
 	"testing"
@@ -19,20 +19,24 @@ import (
 )
 
 type mockServerOpts struct {
-	Path         string
-	QueryParams  url.Values
-	Method       string
-	StatusCode   int
-	Username     string
-	Password     string
-	ResponseData *toggl.FetchResponse
+	Path       string
+	Method     string
+	StatusCode int
+	Username   string
+	Password   string
+	// RequestData and ResponseData are the expected search parameters and
+	// the returned rows of the subsequent pages.
+	RequestData  []toggl.SearchParams
+	ResponseData [][]toggl.FetchEntry
 }
 
 func mockServer(t *testing.T, e *mockServerOpts) *httptest.Server {
+	page := 0
+
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, e.Method, r.Method, "API call methods are not matching")
 		require.Equal(t, e.Path, r.URL.Path, "API call URLs are not matching")
-		require.Equal(t, e.QueryParams, r.URL.Query())
+		require.Empty(t, r.URL.Query(), "API call query params are not empty")
 
 		if e.Username != "" && e.Password != "" {
 			username, password, _ := r.BasicAuth()
@@ -40,12 +44,18 @@ func mockServer(t *testing.T, e *mockServerOpts) *httptest.Server {
 			require.Equal(t, e.Password, password, "API call basic auth password mismatch")
 		}
 
-		if e.ResponseData != nil {
-			err := json.NewEncoder(w).Encode(e.ResponseData)
-			require.Nil(t, err, "cannot encode response data")
-		}
+		require.Less(t, page, len(e.RequestData), "unexpected API call")
+
+		var params toggl.SearchParams
+		err := json.NewDecoder(r.Body).Decode(&params)
+		require.Nil(t, err, "cannot decode request data")
+		require.Equal(t, e.RequestData[page], params, "API call search params are not matching")
 
+		page++
 		w.WriteHeader(e.StatusCode)
+
+		err = json.NewEncoder(w).Encode(e.ResponseData[page-1])
+		require.Nil(t, err, "cannot encode response data")
 	}))
 }
 
@@ -55,6 +65,19 @@ func newMockServer(t *testing.T, opts *mockServerOpts) *httptest.Server {
 	return mockServer
 }
 
+func newSearchParams(start time.Time, end time.Time, firstRowNumber int) toggl.SearchParams {
+	return toggl.SearchParams{
+		StartDate:      utils.DateFormatISO8601.Format(start),
+		EndDate:        utils.DateFormatISO8601.Format(end),
+		UserIDs:        []int{987654321},
+		PageSize:       client.DefaultPageSize,
+		FirstRowNumber: firstRowNumber,
+		EnrichResponse: true,
+		OrderBy:        "date",
+		OrderDir:       "ASC",
+	}
+}
+
 func TestTogglClient_FetchEntries(t *testing.T) {
 	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2021, 10, 2, 23, 59, 59, 0, time.UTC)
@@ -81,6 +104,11 @@ func TestTogglClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   time.Second * 3600,
 			UnbillableDuration: 0,
+			Links:              []string{"https://track.toggl.com/reports/detailed/123456789/from/2021-10-02/to/2021-10-02"},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"1"},
+				SourceURLs: []string{"https://track.toggl.com/reports/detailed/123456789/from/2021-10-02/to/2021-10-02"},
+			},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -100,53 +128,64 @@ func TestTogglClient_FetchEntries(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: time.Second * 3600,
+			Links:              []string{"https://track.toggl.com/reports/detailed/123456789/from/2021-10-02/to/2021-10-02"},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"2"},
+				SourceURLs: []string{"https://track.toggl.com/reports/detailed/123456789/from/2021-10-02/to/2021-10-02"},
+			},
 		},
 	}
 
 	mockServer := newMockServer(t, &mockServerOpts{
-		Path: toggl.PathWorklog,
-		QueryParams: url.Values{
-			"page":         {"1"},
-			"per_page":     {"50"},
-			"since":        {utils.DateFormatISO8601.Format(start)},
-			"until":        {utils.DateFormatISO8601.Format(end)},
-			"user_id":      {"987654321"},
-			"workspace_id": {"123456789"},
-			"user_agent":   {"github.com/gabor-boros/minutes"},
-		},
-		Method:     http.MethodGet,
-		StatusCode: http.StatusOK,
-		Username:   clientUsername,
-		Password:   clientPassword,
-		ResponseData: &toggl.FetchResponse{
-			TotalCount: 2,
-			PerPage:    50,
-			Data: []toggl.FetchEntry{
+		Path:        fmt.Sprintf(toggl.PathWorklog, 123456789),
+		Method:      http.MethodPost,
+		StatusCode:  http.StatusOK,
+		Username:    clientUsername,
+		Password:    clientPassword,
+		RequestData: []toggl.SearchParams{newSearchParams(start, end, 1)},
+		ResponseData: [][]toggl.FetchEntry{
+			{
 				{
-					Client:      "My Awesome Company",
-					Description: "I met with The Winter Soldier",
-					Duration:    3600000,
-					IsBillable:  true,
-					Project:     "MARVEL",
+					UserID:      987654321,
+					Username:    "Steve Rogers",
 					ProjectID:   456,
-					Start:       start,
-					End:         start.Add(3600000),
-					Tags:        nil,
-					Task:        "CPT-2014",
+					ProjectName: "MARVEL",
+					ClientName:  "My Awesome Company",
 					TaskID:      789,
+					TaskName:    "CPT-2014",
+					Billable:    true,
+					Description: "I met with The Winter Soldier",
+					TagNames:    nil,
+					TimeEntries: []toggl.TimeEntry{
+						{
+							ID:      1,
+							Seconds: 3600,
+							Start:   utils.Timestamp{Time: start},
+							Stop:    utils.Timestamp{Time: start.Add(time.Hour)},
+						},
+					},
+					RowNumber: 1,
 				},
 				{
-					Client:      "My Awesome Company",
-					Description: "I helped him to get back on track",
-					Duration:    3600000,
-					IsBillable:  false,
-					Project:     "MARVEL",
+					UserID:      987654321,
+					Username:    "Steve Rogers",
 					ProjectID:   456,
-					Start:       start,
-					End:         start.Add(3600000),
-					Tags:        nil,
-					Task:        "CPT-2014",
+					ProjectName: "MARVEL",
+					ClientName:  "My Awesome Company",
 					TaskID:      789,
+					TaskName:    "CPT-2014",
+					Billable:    false,
+					Description: "I helped him to get back on track",
+					TagNames:    nil,
+					TimeEntries: []toggl.TimeEntry{
+						{
+							ID:      2,
+							Seconds: 3600,
+							Start:   utils.Timestamp{Time: start},
+							Stop:    utils.Timestamp{Time: start.Add(time.Hour)},
+						},
+					},
+					RowNumber: 2,
 				},
 			},
 		},
@@ -163,6 +202,7 @@ func TestTogglClient_FetchEntries(t *testing.T) {
 		},
 		BaseURL:   mockServer.URL,
 		Workspace: 123456789,
+		AppURL:    toggl.DefaultAppURL,
 	})
 	require.Nil(t, err)
 
@@ -202,6 +242,7 @@ func TestTogglClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   time.Second * 3600,
 			UnbillableDuration: 0,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"1"}},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -221,6 +262,10 @@ func TestTogglClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: time.Second * 1800,
+			Provenance: worklog.Provenance{
+				SourceIDs:       []string{"2"},
+				Transformations: []string{"split by tags"},
+			},
 		},
 		{
 			Client: worklog.IDNameField{
@@ -240,55 +285,65 @@ func TestTogglClient_FetchEntries_TagsAsTasks(t *testing.T) {
 			Start:              start,
 			BillableDuration:   0,
 			UnbillableDuration: time.Second * 1800,
+			Provenance: worklog.Provenance{
+				SourceIDs:       []string{"2"},
+				Transformations: []string{"split by tags"},
+			},
 		},
 	}
 
 	mockServer := newMockServer(t, &mockServerOpts{
-		Path: toggl.PathWorklog,
-		QueryParams: url.Values{
-			"page":         {"1"},
-			"per_page":     {"50"},
-			"since":        {utils.DateFormatISO8601.Format(start)},
-			"until":        {utils.DateFormatISO8601.Format(end)},
-			"user_id":      {"987654321"},
-			"workspace_id": {"123456789"},
-			"user_agent":   {"github.com/gabor-boros/minutes"},
-		},
-		Method:     http.MethodGet,
-		StatusCode: http.StatusOK,
-		Username:   clientUsername,
-		Password:   clientPassword,
-		ResponseData: &toggl.FetchResponse{
-			TotalCount: 2,
-			PerPage:    50,
-			Data: []toggl.FetchEntry{
+		Path:        fmt.Sprintf(toggl.PathWorklog, 123456789),
+		Method:      http.MethodPost,
+		StatusCode:  http.StatusOK,
+		Username:    clientUsername,
+		Password:    clientPassword,
+		RequestData: []toggl.SearchParams{newSearchParams(start, end, 1)},
+		ResponseData: [][]toggl.FetchEntry{
+			{
 				{
-					Client:      "My Awesome Company",
-					Description: "I met with The Winter Soldier",
-					Duration:    3600000,
-					IsBillable:  true,
-					Project:     "MARVEL",
+					UserID:      987654321,
+					Username:    "Steve Rogers",
 					ProjectID:   456,
-					Start:       start,
-					End:         start.Add(3600000),
-					Tags: []string{
+					ProjectName: "MARVEL",
+					ClientName:  "My Awesome Company",
+					Billable:    true,
+					Description: "I met with The Winter Soldier",
+					TagNames: []string{
 						"CPT-2014",
 					},
+					TimeEntries: []toggl.TimeEntry{
+						{
+							ID:      1,
+							Seconds: 3600,
+							Start:   utils.Timestamp{Time: start},
+							Stop:    utils.Timestamp{Time: start.Add(time.Hour)},
+						},
+					},
+					RowNumber: 1,
 				},
 				{
-					Client:      "My Awesome Company",
-					Description: "I helped him to get back on track",
-					Duration:    3600000,
-					IsBillable:  false,
-					Project:     "MARVEL",
+					UserID:      987654321,
+					Username:    "Steve Rogers",
 					ProjectID:   456,
-					Start:       start,
-					End:         start.Add(3600000),
-					Tags: []string{
+					ProjectName: "MARVEL",
+					ClientName:  "My Awesome Company",
+					Billable:    false,
+					Description: "I helped him to get back on track",
+					TagNames: []string{
 						"CPT-2014",
 						"CPT-MISC",
 						"IGNORED",
 					},
+					TimeEntries: []toggl.TimeEntry{
+						{
+							ID:      2,
+							Seconds: 3600,
+							Start:   utils.Timestamp{Time: start},
+							Stop:    utils.Timestamp{Time: start.Add(time.Hour)},
+						},
+					},
+					RowNumber: 2,
 				},
 			},
 		},
@@ -309,12 +364,119 @@ func TestTogglClient_FetchEntries_TagsAsTasks(t *testing.T) {
 	require.Nil(t, err)
 
 	entries, err := togglClient.FetchEntries(context.Background(), &client.FetchOpts{
-		User:             "987654321",
-		Start:            start,
-		End:              end,
-		TagsAsTasksRegex: regexp.MustCompile(`^CPT-\w+$`),
+		User:  "987654321",
+		Start: start,
+		End:   end,
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^CPT-\w+$`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestTogglClient_FetchEntries_Paginated(t *testing.T) {
+	start := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 10, 2, 23, 59, 59, 0, time.UTC)
+
+	var expectedEntries worklog.Entries
+	var fetchedEntries []toggl.FetchEntry
+
+	for i := 1; i <= client.DefaultPageSize+1; i++ {
+		entryStart := start.Add(time.Minute * time.Duration(i))
+
+		expectedEntries = append(expectedEntries, worklog.Entry{
+			Client: worklog.IDNameField{
+				ID:   "My Awesome Company",
+				Name: "My Awesome Company",
+			},
+			Project: worklog.IDNameField{
+				ID:   strconv.Itoa(456),
+				Name: "MARVEL",
+			},
+			Task: worklog.IDNameField{
+				ID:   strconv.Itoa(0),
+				Name: "",
+			},
+			Summary:          "Assembling",
+			Notes:            "Assembling",
+			Start:            entryStart,
+			BillableDuration: time.Minute,
+			Provenance:       worklog.Provenance{SourceIDs: []string{strconv.Itoa(i)}},
+		})
+
+		fetchedEntries = append(fetchedEntries, toggl.FetchEntry{
+			UserID:      987654321,
+			ProjectID:   456,
+			ProjectName: "MARVEL",
+			ClientName:  "My Awesome Company",
+			Billable:    true,
+			Description: "Assembling",
+			TimeEntries: []toggl.TimeEntry{
+				{
+					ID:      i,
+					Seconds: 60,
+					Start:   utils.Timestamp{Time: entryStart},
+					Stop:    utils.Timestamp{Time: entryStart.Add(time.Minute)},
+				},
+			},
+			RowNumber: i,
+		})
+	}
+
+	mockServer := newMockServer(t, &mockServerOpts{
+		Path:       fmt.Sprintf(toggl.PathWorklog, 123456789),
+		Method:     http.MethodPost,
+		StatusCode: http.StatusOK,
+		RequestData: []toggl.SearchParams{
+			newSearchParams(start, end, 1),
+			newSearchParams(start, end, client.DefaultPageSize+1),
+		},
+		ResponseData: [][]toggl.FetchEntry{
+			fetchedEntries[:client.DefaultPageSize],
+			fetchedEntries[client.DefaultPageSize:],
+		},
+	})
+	defer mockServer.Close()
+
+	togglClient, err := toggl.NewFetcher(&toggl.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BasicAuth: client.BasicAuth{
+			Username: "token-of-the-day",
+			Password: "api_token",
+		},
+		BaseURL:   mockServer.URL,
+		Workspace: 123456789,
+	})
+	require.Nil(t, err)
+
+	entries, err := togglClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "987654321",
+		Start: start,
+		End:   end,
 	})
 
 	require.Nil(t, err, "cannot fetch entries")
 	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
 }
+
+func TestTogglClient_FetchEntries_InvalidUser(t *testing.T) {
+	togglClient, err := toggl.NewFetcher(&toggl.ClientOpts{
+		BasicAuth: client.BasicAuth{
+			Username: "token-of-the-day",
+			Password: "api_token",
+		},
+		BaseURL:   "http://localhost",
+		Workspace: 123456789,
+	})
+	require.Nil(t, err)
+
+	_, err = togglClient.FetchEntries(context.Background(), &client.FetchOpts{
+		User:  "steve",
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 10, 2, 23, 59, 59, 0, time.UTC),
+	})
+
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
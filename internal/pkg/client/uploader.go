@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"errors"
+	"strings"
+	"text/template"
 
 	"github.com/gabor-boros/minutes/internal/pkg/worklog"
 	"github.com/jedib0t/go-pretty/v6/progress"
@@ -16,15 +18,6 @@ var (
 // UploadOpts specifies the only options for the Uploader. In contrast to the
 // BaseClientOpts, these options shall not be extended or overridden.
 type UploadOpts struct {
-	// RoundToClosestMinute indicates to round the billed and unbilled duration
-	// separately to the closest minute.
-	// If the elapsed time is 30 seconds or more, the closest minute is the
-	// next minute, otherwise the previous one. In case the previous minute is
-	// 0 (zero), then 0 (zero) will be used for the billed and/or unbilled
-	// duration.
-	RoundToClosestMinute bool
-	// TreatDurationAsBilled indicates to use every time spent as billed.
-	TreatDurationAsBilled bool
 	// CreateMissingResources indicates the need of resource creation if the
 	// resource is missing.
 	// In the case of some Uploader, the resources must exist to be able to
@@ -32,10 +25,103 @@ type UploadOpts struct {
 	CreateMissingResources bool
 	// User represents the user in which name the time log will be uploaded.
 	User string
+	// Author sets how the author of the entries is resolved by the targets
+	// supporting it. In case the Author is nil, AuthorStrategySource is used.
+	Author *AuthorOpts
 	// ProgressWriter represents a writer that tracks the upload progress.
 	// In case the ProgressWriter is nil, that means the upload progress should
 	// not be tracked, hence, that's not an error.
 	ProgressWriter progress.Writer
+	// CommentTemplate is used to render the comment of the uploaded entries.
+	// The template is executed with the entry as its data. In case the
+	// CommentTemplate is nil, the summary of the entry is used as comment.
+	CommentTemplate *template.Template
+	// MutationRecorder records the calls changing the data of the target, like
+	// creating a worklog. In case the MutationRecorder is nil, the mutations
+	// are not recorded.
+	MutationRecorder MutationRecorder
+	// LimitPolicy sets how the fields violating the limits of the target are
+	// handled. If it is LimitPolicyTransform, the uploaders transform the
+	// fields to satisfy the limits, otherwise the fields are uploaded as is.
+	LimitPolicy string
+}
+
+// EntryError binds the error of an upload to the entry failed to upload, so
+// the failure can be reported together with the origin of the entry.
+type EntryError struct {
+	Entry worklog.Entry
+	Err   error
+}
+
+func (e *EntryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// NewEntryError returns the error bound to the entry. If the error is nil, nil
+// returns, so the result can be sent to the error channel as is.
+func NewEntryError(entry worklog.Entry, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &EntryError{Entry: entry, Err: err}
+}
+
+// EntryOf returns the entry of the EntryError in the error's chain. If the
+// chain contains no EntryError, like for the errors not caused by a single
+// entry, nil returns.
+func EntryOf(err error) *worklog.Entry {
+	var entryErr *EntryError
+	if errors.As(err, &entryErr) {
+		return &entryErr.Entry
+	}
+
+	return nil
+}
+
+// RecordMutation records the mutation by the MutationRecorder, if set.
+func (o *UploadOpts) RecordMutation(mutation *Mutation) {
+	if o.MutationRecorder != nil {
+		o.MutationRecorder.RecordMutation(mutation)
+	}
+}
+
+// AuthorOf returns the user the entry is uploaded in the name of, resolved by
+// the Author options.
+func (o *UploadOpts) AuthorOf(entry *worklog.Entry) (string, error) {
+	if o.Author == nil {
+		return (&AuthorOpts{}).Resolve(entry, o.User)
+	}
+
+	return o.Author.Resolve(entry, o.User)
+}
+
+// RenderComment returns the comment of the entry rendered by the
+// CommentTemplate. If no template is set, the entry's summary returns.
+func (o *UploadOpts) RenderComment(entry worklog.Entry) (string, error) {
+	if o.CommentTemplate == nil {
+		return entry.Summary, nil
+	}
+
+	var comment strings.Builder
+	if err := o.CommentTemplate.Execute(&comment, entry); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(comment.String()), nil
+}
+
+// NewCommentTemplate parses the comment template used by the uploaders.
+// Besides the builtin template functions, `join` is available to concatenate
+// lists, like the links of the entry.
+func NewCommentTemplate(text string) (*template.Template, error) {
+	return template.New("comment").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
 }
 
 // Uploader specifies the functions used to upload worklog entries.
@@ -78,3 +164,49 @@ func (u *DefaultUploader) StopTracking(tracker *progress.Tracker, err error) {
 		tracker.MarkAsErrored()
 	}
 }
+
+// Payload represents the serialized data an uploader sends for an entry, like
+// the request body of an API call or the row of a file.
+type Payload struct {
+	Entry worklog.Entry
+	// Method and URL are set if the payload is sent as an HTTP request.
+	Method string
+	URL    string
+	Body   []byte
+}
+
+// PayloadPreviewer is implemented by the uploaders able to serialize the
+// entries without uploading them, so the exact payloads can be checked before
+// or during the upload.
+type PayloadPreviewer interface {
+	// PreviewPayloads returns the payloads the uploader would send for the
+	// entries, in the order the entries are given.
+	PreviewPayloads(entries worklog.Entries, opts *UploadOpts) ([]Payload, error)
+}
+
+// Mutation represents a call of an uploader that changed, or tried to change,
+// the data of the target, like creating a worklog through the target's API.
+type Mutation struct {
+	Entry  worklog.Entry
+	Method string
+	URL    string
+	// StatusCode is the status code of the response. If no response was
+	// received, like for network errors, it is 0.
+	StatusCode int
+	// ResourceID is the ID of the created or changed resource, like the ID of
+	// the worklog, if it was returned by the target.
+	ResourceID string
+	// OnBehalfOf is the user impersonated by the call. If the call was made in
+	// the name of the target user, it is empty.
+	OnBehalfOf string
+	// Err is the error of the call, if the call failed.
+	Err error
+}
+
+// MutationRecorder is implemented by the recorders of the mutating calls, like
+// the audit log. The uploaders may call RecordMutation concurrently.
+type MutationRecorder interface {
+	// RecordMutation records the mutation. Recording errors are handled by the
+	// recorder, so they do not fail the upload.
+	RecordMutation(mutation *Mutation)
+}
@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -93,3 +94,42 @@ func TestDefaultUploader_StopTracking_NoTracker(t *testing.T) {
 
 	uploader.StopTracking(tracker, nil)
 }
+
+func TestUploadOpts_RenderComment(t *testing.T) {
+	entry := getTestEntry()
+	entry.Links = []string{"https://example.com/1", "https://example.com/2"}
+
+	commentTemplate, err := client.NewCommentTemplate(`{{.Summary}} ({{join .Links ", "}})`)
+	require.Nil(t, err)
+
+	opts := &client.UploadOpts{CommentTemplate: commentTemplate}
+	comment, err := opts.RenderComment(entry)
+
+	require.Nil(t, err)
+	require.Equal(t, "Write worklog transfer CLI tool (https://example.com/1, https://example.com/2)", comment)
+}
+
+func TestUploadOpts_RenderComment_NoTemplate(t *testing.T) {
+	entry := getTestEntry()
+
+	opts := &client.UploadOpts{}
+	comment, err := opts.RenderComment(entry)
+
+	require.Nil(t, err)
+	require.Equal(t, entry.Summary, comment)
+}
+
+func TestEntryError(t *testing.T) {
+	entry := getTestEntry()
+	uploadErr := fmt.Errorf("%w: %w", client.ErrUploadEntries, &client.ValidationError{Err: errors.New("issue not found")})
+
+	require.Nil(t, client.NewEntryError(entry, nil))
+
+	err := client.NewEntryError(entry, uploadErr)
+	require.Equal(t, uploadErr.Error(), err.Error())
+	require.ErrorIs(t, err, client.ErrUploadEntries)
+	require.Equal(t, client.ErrorKindValidation, client.KindOf(err))
+	require.Equal(t, &entry, client.EntryOf(fmt.Errorf("wrapped: %w", err)))
+
+	require.Nil(t, client.EntryOf(uploadErr))
+}
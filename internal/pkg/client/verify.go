@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUploadVerificationUnsupported returns when the uploader cannot fetch
+	// the uploaded resources from the target to verify them.
+	ErrUploadVerificationUnsupported = errors.New("target does not support verifying the uploads")
+)
+
+// FieldMismatch represents a field of an uploaded resource having a different
+// value on the target than it was sent.
+type FieldMismatch struct {
+	Field    string
+	Sent     string
+	Received string
+}
+
+// Verification represents the result of fetching an uploaded resource from
+// the target and comparing it with what was sent.
+type Verification struct {
+	Mutation   Mutation
+	Mismatches []FieldMismatch
+	// Err is the error of fetching the resource, like if it does not exist.
+	Err error
+}
+
+// IsVerified returns true if the resource was fetched and every field matches
+// the sent value.
+func (v *Verification) IsVerified() bool {
+	return v.Err == nil && len(v.Mismatches) == 0
+}
+
+// UploadVerifier is implemented by the uploaders able to fetch the resources
+// they created, like the worklogs, so the uploads can be verified against the
+// data stored by the target.
+type UploadVerifier interface {
+	// IsVerifiable returns true if the mutation created a resource the
+	// uploader can fetch, like a worklog, but not an issue comment.
+	IsVerifiable(mutation *Mutation) bool
+	// VerifyUpload fetches the resource created by the mutation and compares
+	// it field-by-field with what was sent for the entry of the mutation.
+	VerifyUpload(ctx context.Context, mutation *Mutation, opts *UploadOpts) ([]FieldMismatch, error)
+}
+
+// MutationCollector collects the successful mutations that created a resource
+// returning its ID, so the uploads can be verified after the upload. Every
+// mutation is forwarded to the Next recorder, if set.
+type MutationCollector struct {
+	Next MutationRecorder
+
+	mu        sync.Mutex
+	mutations []Mutation
+}
+
+func (c *MutationCollector) RecordMutation(mutation *Mutation) {
+	if c.Next != nil {
+		c.Next.RecordMutation(mutation)
+	}
+
+	if mutation.Err != nil || mutation.ResourceID == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mutations = append(c.mutations, *mutation)
+}
+
+// Mutations returns the collected mutations.
+func (c *MutationCollector) Mutations() []Mutation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mutations := make([]Mutation, len(c.mutations))
+	copy(mutations, c.mutations)
+
+	return mutations
+}
+
+// VerifySample verifies the resources created by a random sample of n of the
+// verifiable mutations, or all of them if there are not more than n. If the
+// uploader is not an UploadVerifier, ErrUploadVerificationUnsupported returns.
+// The random source picks the sample, and the resources are verified in the
+// order of the mutations.
+func VerifySample(ctx context.Context, uploader Uploader, mutations []Mutation, n int, random *rand.Rand, opts *UploadOpts) ([]Verification, error) {
+	verifier, ok := uploader.(UploadVerifier)
+	if !ok {
+		return nil, ErrUploadVerificationUnsupported
+	}
+
+	var verifiable []int
+	for i := range mutations {
+		if verifier.IsVerifiable(&mutations[i]) {
+			verifiable = append(verifiable, i)
+		}
+	}
+
+	if n < len(verifiable) {
+		random.Shuffle(len(verifiable), func(i, j int) {
+			verifiable[i], verifiable[j] = verifiable[j], verifiable[i]
+		})
+
+		verifiable = verifiable[:n]
+	}
+
+	// Keeping the order of the mutations makes the report easier to follow
+	sort.Ints(verifiable)
+
+	verifications := make([]Verification, 0, len(verifiable))
+	for _, i := range verifiable {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		mismatches, err := verifier.VerifyUpload(ctx, &mutations[i], opts)
+		verifications = append(verifications, Verification{
+			Mutation:   mutations[i],
+			Mismatches: mismatches,
+			Err:        err,
+		})
+	}
+
+	return verifications, nil
+}
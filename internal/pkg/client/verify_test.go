@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+type mockVerifierUploader struct {
+	mockUploader
+	verified []string
+}
+
+func (u *mockVerifierUploader) IsVerifiable(mutation *client.Mutation) bool {
+	return mutation.URL == "/worklogs"
+}
+
+func (u *mockVerifierUploader) VerifyUpload(_ context.Context, mutation *client.Mutation, _ *client.UploadOpts) ([]client.FieldMismatch, error) {
+	u.verified = append(u.verified, mutation.ResourceID)
+
+	switch mutation.ResourceID {
+	case "2":
+		return []client.FieldMismatch{{Field: "comment", Sent: "Fix thing", Received: "Fix"}}, nil
+	case "3":
+		return nil, errors.New("worklog not found")
+	default:
+		return nil, nil
+	}
+}
+
+type mockMutationRecorder struct {
+	mutations []client.Mutation
+}
+
+func (r *mockMutationRecorder) RecordMutation(mutation *client.Mutation) {
+	r.mutations = append(r.mutations, *mutation)
+}
+
+func TestMutationCollector(t *testing.T) {
+	next := &mockMutationRecorder{}
+	collector := &client.MutationCollector{Next: next}
+
+	collector.RecordMutation(&client.Mutation{Method: http.MethodPost, URL: "/worklogs", ResourceID: "1"})
+	collector.RecordMutation(&client.Mutation{Method: http.MethodPost, URL: "/worklogs", Err: errors.New("failed")})
+	collector.RecordMutation(&client.Mutation{Method: http.MethodPost, URL: "/worklogs"})
+
+	require.Len(t, next.mutations, 3)
+	require.Equal(t, []client.Mutation{{Method: http.MethodPost, URL: "/worklogs", ResourceID: "1"}}, collector.Mutations())
+}
+
+func TestVerifySample(t *testing.T) {
+	mutations := []client.Mutation{
+		{Entry: worklog.Entry{Summary: "1"}, URL: "/worklogs", ResourceID: "1"},
+		{Entry: worklog.Entry{Summary: "1"}, URL: "/comments", ResourceID: "100"},
+		{Entry: worklog.Entry{Summary: "2"}, URL: "/worklogs", ResourceID: "2"},
+		{Entry: worklog.Entry{Summary: "3"}, URL: "/worklogs", ResourceID: "3"},
+	}
+
+	uploader := &mockVerifierUploader{}
+	verifications, err := client.VerifySample(context.Background(), uploader, mutations, 10, rand.New(rand.NewSource(1)), &client.UploadOpts{})
+	require.Nil(t, err)
+
+	require.Equal(t, []string{"1", "2", "3"}, uploader.verified)
+	require.Len(t, verifications, 3)
+	require.True(t, verifications[0].IsVerified())
+	require.False(t, verifications[1].IsVerified())
+	require.Equal(t, "comment", verifications[1].Mismatches[0].Field)
+	require.False(t, verifications[2].IsVerified())
+	require.EqualError(t, verifications[2].Err, "worklog not found")
+}
+
+func TestVerifySample_Sampled(t *testing.T) {
+	var mutations []client.Mutation
+	for _, id := range []string{"1", "4", "5", "6", "7", "8"} {
+		mutations = append(mutations, client.Mutation{URL: "/worklogs", ResourceID: id})
+	}
+
+	uploader := &mockVerifierUploader{}
+	verifications, err := client.VerifySample(context.Background(), uploader, mutations, 2, rand.New(rand.NewSource(1)), &client.UploadOpts{})
+	require.Nil(t, err)
+	require.Len(t, verifications, 2)
+	require.Len(t, uploader.verified, 2)
+	require.NotEqual(t, uploader.verified[0], uploader.verified[1])
+	require.Less(t, uploader.verified[0], uploader.verified[1])
+}
+
+func TestVerifySample_Unsupported(t *testing.T) {
+	_, err := client.VerifySample(context.Background(), &mockUploader{}, nil, 1, rand.New(rand.NewSource(1)), &client.UploadOpts{})
+	require.ErrorIs(t, err, client.ErrUploadVerificationUnsupported)
+}
@@ -0,0 +1,207 @@
+package wakatime
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// DefaultURL is the base URL of the WakaTime API.
+	DefaultURL string = "https://wakatime.com"
+	// DefaultUser is the user of the API key, used if no user is set.
+	DefaultUser string = "current"
+	// PathDurations is the endpoint used to fetch the durations of a day.
+	PathDurations string = "/api/v1/users/%s/durations"
+)
+
+// Duration represents a period of coding in a project. The time is the start
+// as UNIX timestamp, while the duration is in seconds.
+type Duration struct {
+	Project  string  `json:"project"`
+	Branch   string  `json:"branch"`
+	Time     float64 `json:"time"`
+	Duration float64 `json:"duration"`
+}
+
+// Start returns the start of the duration.
+func (d *Duration) Start() time.Time {
+	seconds, fraction := math.Modf(d.Time)
+	return time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+}
+
+// FetchResponse represents the durations of a day.
+type FetchResponse struct {
+	Data []Duration `json:"data"`
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// APIKey is the secret API key of the user, sent base64 encoded as basic
+	// authorization.
+	APIKey string
+	// User is the ID or username of the user the durations are fetched for. If
+	// not set, DefaultUser is used.
+	User string
+}
+
+type wakaTimeClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator client.Authenticator
+	user          string
+}
+
+// groupDurations combines the durations of the same project and branch into
+// one entry, starting at the earliest duration. The branch is used as the
+// summary and task, so the task can be extracted from branch names like
+// "CPT-123-fix-thing". Durations without branch are grouped by the project.
+func groupDurations(durations []Duration) worklog.Entries {
+	type group struct {
+		project   string
+		branch    string
+		start     time.Time
+		timeSpent time.Duration
+		count     int
+	}
+
+	var groups []*group
+	groupsByKey := map[string]*group{}
+
+	for _, duration := range durations {
+		key := duration.Project + "\x00" + duration.Branch
+		start := duration.Start()
+
+		g, ok := groupsByKey[key]
+		if !ok {
+			g = &group{project: duration.Project, branch: duration.Branch, start: start}
+			groupsByKey[key] = g
+			groups = append(groups, g)
+		}
+
+		if start.Before(g.start) {
+			g.start = start
+		}
+
+		g.timeSpent += time.Duration(duration.Duration * float64(time.Second))
+		g.count++
+	}
+
+	var entries worklog.Entries
+	for _, g := range groups {
+		summary := g.branch
+		if summary == "" {
+			summary = g.project
+		}
+
+		entry := worklog.Entry{
+			Project: worklog.IDNameField{
+				ID:   g.project,
+				Name: g.project,
+			},
+			Summary:          summary,
+			Start:            g.start,
+			BillableDuration: g.timeSpent.Round(time.Second),
+		}
+
+		if g.branch != "" {
+			entry.Task = worklog.IDNameField{
+				ID:   g.branch,
+				Name: g.branch,
+			}
+			entry.TaskHints = &worklog.TaskHints{Texts: []string{g.branch}}
+		}
+
+		if g.count > 1 {
+			entry.AddTransformation("grouped %d durations", g.count)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func (c *wakaTimeClient) fetchDurations(ctx context.Context, date time.Time) ([]Duration, error) {
+	fetchURL, err := c.URL(fmt.Sprintf(PathDurations, url.PathEscape(c.user)), map[string]string{
+		"date": utils.DateFormatISO8601.Format(date),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var fetchResponse FetchResponse
+	err = c.CallAndDecode(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     fetchURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+	}, &fetchResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchResponse.Data, nil
+}
+
+func (c *wakaTimeClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	year, month, day := opts.Start.Date()
+	firstDay := time.Date(year, month, day, 0, 0, 0, 0, opts.Start.Location())
+
+	// The durations are fetched per day, hence the entries of a day are
+	// grouped separately from the other days
+	for date := firstDay; !date.After(opts.LastDay()); date = date.AddDate(0, 0, 1) {
+		durations, err := c.fetchDurations(ctx, date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+		}
+
+		entries = append(entries, groupDurations(durations)...)
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new WakaTime client for fetching entries.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("no WakaTime API key provided")
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Basic", base64.StdEncoding.EncodeToString([]byte(opts.APIKey)))
+	if err != nil {
+		return nil, err
+	}
+
+	user := opts.User
+	if user == "" {
+		user = DefaultUser
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wakaTimeClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		authenticator: authenticator,
+		user:          user,
+	}, nil
+}
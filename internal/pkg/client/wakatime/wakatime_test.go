@@ -0,0 +1,156 @@
+package wakatime_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/wakatime"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+func unix(t time.Time) float64 {
+	return float64(t.Unix())
+}
+
+func newMockServer(t *testing.T, durations map[string][]wakatime.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/api/v1/users/current/durations", r.URL.Path)
+		require.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("waka_s3cr3t")), r.Header.Get("Authorization"))
+
+		date := r.URL.Query().Get("date")
+		dayDurations, ok := durations[date]
+		require.True(t, ok, "unexpected date %s", date)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.Nil(t, json.NewEncoder(w).Encode(wakatime.FetchResponse{Data: dayDurations}))
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string) client.Fetcher {
+	fetcher, err := wakatime.NewFetcher(&wakatime.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL: serverURL,
+		APIKey:  "waka_s3cr3t",
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestWakaTimeClient_FetchEntries(t *testing.T) {
+	mockServer := newMockServer(t, map[string][]wakatime.Duration{
+		"2021-10-01": {
+			{Project: "minutes", Branch: "CPT-123-fix-thing", Time: unix(at(1, 9, 0)), Duration: 1200},
+			{Project: "minutes", Branch: "main", Time: unix(at(1, 9, 20)), Duration: 300},
+			{Project: "minutes", Branch: "CPT-123-fix-thing", Time: unix(at(1, 9, 25)), Duration: 1500.4},
+			{Project: "dotfiles", Time: unix(at(1, 11, 0)), Duration: 600},
+		},
+		"2021-10-02": {
+			{Project: "minutes", Branch: "CPT-123-fix-thing", Time: unix(at(2, 10, 0)), Duration: 1800},
+		},
+	})
+	defer mockServer.Close()
+
+	minutes := worklog.IDNameField{ID: "minutes", Name: "minutes"}
+	task := worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          minutes,
+			Task:             task,
+			Summary:          "CPT-123-fix-thing",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Minute * 45,
+			Provenance:       worklog.Provenance{Transformations: []string{"grouped 2 durations"}},
+		},
+		{
+			// The branch does not match the regex, hence it is kept as task
+			Project:          minutes,
+			Task:             worklog.IDNameField{ID: "main", Name: "main"},
+			Summary:          "main",
+			Start:            at(1, 9, 20),
+			BillableDuration: time.Minute * 5,
+		},
+		{
+			Project:          worklog.IDNameField{ID: "dotfiles", Name: "dotfiles"},
+			Summary:          "dotfiles",
+			Start:            at(1, 11, 0),
+			BillableDuration: time.Minute * 10,
+		},
+		{
+			Project:          minutes,
+			Task:             task,
+			Summary:          "CPT-123-fix-thing",
+			Start:            at(2, 10, 0),
+			BillableDuration: time.Minute * 30,
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`[A-Z]{2,7}-\d{1,6}`), worklog.TaskExtractionTags)
+	require.Equal(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestWakaTimeClient_FetchEntries_PartialDays(t *testing.T) {
+	mockServer := newMockServer(t, map[string][]wakatime.Duration{
+		"2021-10-01": {
+			{Project: "minutes", Time: unix(at(1, 9, 0)), Duration: 600},
+			{Project: "dotfiles", Time: unix(at(1, 14, 0)), Duration: 600},
+		},
+		"2021-10-02": {
+			{Project: "minutes", Time: unix(at(2, 9, 0)), Duration: 600},
+			{Project: "dotfiles", Time: unix(at(2, 14, 0)), Duration: 600},
+		},
+	})
+	defer mockServer.Close()
+
+	// Both days are fetched, but only the entries starting within the period
+	// are kept
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 12, 0),
+		End:   at(2, 12, 0),
+	})
+	require.Nil(t, err, "cannot fetch entries")
+
+	require.Len(t, entries, 2)
+	require.Equal(t, at(1, 14, 0), entries[0].Start)
+	require.Equal(t, at(2, 9, 0), entries[1].Start)
+}
+
+func TestWakaTimeClient_FetchEntries_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	_, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(2, 0, 0),
+	})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoAPIKey(t *testing.T) {
+	_, err := wakatime.NewFetcher(&wakatime.ClientOpts{BaseURL: wakatime.DefaultURL})
+	require.Error(t, err)
+}
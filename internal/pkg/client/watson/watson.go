@@ -0,0 +1,161 @@
+package watson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+var (
+	// ErrInvalidFrame returns when a frame of the frames file cannot be parsed.
+	ErrInvalidFrame = errors.New("invalid frame")
+)
+
+// Frame represents a tracked period of Watson. The frames are stored as
+// arrays, like `[1633078800, 1633082400, "project", "<id>", ["tag"], 1633082400]`.
+type Frame struct {
+	Start     int64
+	Stop      int64
+	Project   string
+	ID        string
+	Tags      []string
+	UpdatedAt int64
+}
+
+// UnmarshalJSON decodes the frame from its array representation. The last
+// update time is optional.
+func (f *Frame) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if len(fields) < 5 {
+		return fmt.Errorf("%w: %s", ErrInvalidFrame, string(data))
+	}
+
+	values := []interface{}{&f.Start, &f.Stop, &f.Project, &f.ID, &f.Tags, &f.UpdatedAt}
+	for i, field := range fields {
+		if i == len(values) {
+			break
+		}
+
+		if err := json.Unmarshal(field, values[i]); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidFrame, string(data))
+		}
+	}
+
+	return nil
+}
+
+// DefaultFramesPath returns the path of the frames file used by Watson. The
+// directory is set by the WATSON_DIR environment variable, otherwise it is the
+// "watson" directory in the user's config directory.
+func DefaultFramesPath() string {
+	if dir := os.Getenv("WATSON_DIR"); dir != "" {
+		return filepath.Join(dir, "frames")
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "watson", "frames")
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Since the frames file is read locally, the timeout is not used.
+type ClientOpts struct {
+	client.BaseClientOpts
+	// Path is the path of the frames file.
+	Path string
+	// UnbillableTag marks the frames as unbillable.
+	UnbillableTag string
+}
+
+type watsonClient struct {
+	*client.BaseClientOpts
+	opts ClientOpts
+}
+
+func (c *watsonClient) parseFrame(frame Frame) worklog.Entry {
+	project := worklog.IDNameField{
+		ID:   frame.Project,
+		Name: frame.Project,
+	}
+
+	start := time.Unix(frame.Start, 0)
+
+	entry := worklog.Entry{
+		Project:          project,
+		Summary:          frame.Project,
+		Start:            start,
+		BillableDuration: time.Unix(frame.Stop, 0).Sub(start),
+		Provenance:       worklog.Provenance{SourceIDs: []string{frame.ID}},
+	}
+
+	var tags []worklog.IDNameField
+	for _, tag := range frame.Tags {
+		if c.opts.UnbillableTag != "" && tag == c.opts.UnbillableTag {
+			entry.UnbillableDuration = entry.BillableDuration
+			entry.BillableDuration = 0
+			continue
+		}
+
+		tags = append(tags, worklog.IDNameField{
+			ID:   tag,
+			Name: tag,
+		})
+	}
+
+	// If the task is not found in tags, the project is the most meaningful
+	// task, as Watson has no other field for it
+	entry.Task = project
+
+	if len(tags) > 0 {
+		entry.TaskHints = &worklog.TaskHints{Tags: tags}
+	}
+
+	return entry
+}
+
+func (c *watsonClient) FetchEntries(_ context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	data, err := os.ReadFile(c.opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var frames []Frame
+	if err = json.Unmarshal(data, &frames); err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	var entries worklog.Entries
+	for _, frame := range frames {
+		entries = append(entries, c.parseFrame(frame))
+	}
+
+	return opts.FilterEntries(entries), nil
+}
+
+// NewFetcher returns a new Watson client for reading the frames.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no Watson frames file path provided")
+	}
+
+	clientOpts := *opts
+
+	return &watsonClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package watson_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/watson"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFrames(t *testing.T, frames string) string {
+	path := filepath.Join(t.TempDir(), "frames")
+	require.Nil(t, os.WriteFile(path, []byte(frames), 0600))
+	return path
+}
+
+func TestFrame_UnmarshalJSON(t *testing.T) {
+	var frame watson.Frame
+
+	require.Nil(t, frame.UnmarshalJSON([]byte(`[1633078800, 1633082400, "minutes", "abc", ["TASK-1"], 1633082500]`)))
+	require.Equal(t, watson.Frame{
+		Start:     1633078800,
+		Stop:      1633082400,
+		Project:   "minutes",
+		ID:        "abc",
+		Tags:      []string{"TASK-1"},
+		UpdatedAt: 1633082500,
+	}, frame)
+
+	require.ErrorIs(t, frame.UnmarshalJSON([]byte(`[1633078800, 1633082400, "minutes"]`)), watson.ErrInvalidFrame)
+	require.ErrorIs(t, frame.UnmarshalJSON([]byte(`["start", 1633082400, "minutes", "abc", []]`)), watson.ErrInvalidFrame)
+}
+
+func TestDefaultFramesPath(t *testing.T) {
+	t.Setenv("WATSON_DIR", "/tmp/watson")
+	require.Equal(t, filepath.Join("/tmp/watson", "frames"), watson.DefaultFramesPath())
+}
+
+func TestWatsonClient_FetchEntries(t *testing.T) {
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+	first := time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local)
+	second := time.Date(2021, 10, 1, 11, 0, 0, 0, time.Local)
+	third := time.Date(2021, 10, 1, 14, 0, 0, 0, time.Local)
+
+	path := writeFrames(t, `[
+		[`+unix(first)+`, `+unix(first.Add(time.Hour))+`, "minutes", "a1", ["TASK-1", "backend"], 1633082500],
+		[`+unix(second)+`, `+unix(second.Add(time.Hour*2))+`, "minutes", "a2", ["TASK-1", "TASK-2"], 1633082500],
+		[`+unix(third)+`, `+unix(third.Add(time.Minute*30))+`, "meetings", "a3", ["unbillable"], 1633082500],
+		[`+unix(start.AddDate(0, 0, -1))+`, `+unix(start.AddDate(0, 0, -1).Add(time.Hour))+`, "minutes", "a0", [], 1633082500]
+	]`)
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{ID: "minutes", Name: "minutes"},
+			Task:             worklog.IDNameField{ID: "TASK-1", Name: "TASK-1"},
+			Summary:          "minutes",
+			Start:            first,
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"a1"}},
+		},
+		{
+			Project:          worklog.IDNameField{ID: "minutes", Name: "minutes"},
+			Task:             worklog.IDNameField{ID: "TASK-1", Name: "TASK-1"},
+			Summary:          "minutes",
+			Start:            second,
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"a2"}, Transformations: []string{"split by tags"}},
+		},
+		{
+			Project:          worklog.IDNameField{ID: "minutes", Name: "minutes"},
+			Task:             worklog.IDNameField{ID: "TASK-2", Name: "TASK-2"},
+			Summary:          "minutes",
+			Start:            second,
+			BillableDuration: time.Hour,
+			Provenance:       worklog.Provenance{SourceIDs: []string{"a2"}, Transformations: []string{"split by tags"}},
+		},
+		{
+			Project:            worklog.IDNameField{ID: "meetings", Name: "meetings"},
+			Task:               worklog.IDNameField{ID: "meetings", Name: "meetings"},
+			Summary:            "meetings",
+			Start:              third,
+			UnbillableDuration: time.Minute * 30,
+			Provenance:         worklog.Provenance{SourceIDs: []string{"a3"}},
+		},
+	}
+
+	watsonClient, err := watson.NewFetcher(&watson.ClientOpts{
+		Path:          path,
+		UnbillableTag: "unbillable",
+	})
+	require.Nil(t, err)
+
+	entries, err := watsonClient.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: start,
+		End:   start.AddDate(0, 0, 1),
+	})
+
+	require.Nil(t, err, "cannot fetch entries")
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
+	require.ElementsMatch(t, expectedEntries, entries, "fetched entries are not matching")
+}
+
+func TestWatsonClient_FetchEntries_InvalidFrames(t *testing.T) {
+	watsonClient, err := watson.NewFetcher(&watson.ClientOpts{
+		Path: writeFrames(t, `[[1633078800, 1633082400]]`),
+	})
+	require.Nil(t, err)
+
+	_, err = watsonClient.FetchEntries(context.Background(), &client.FetchOpts{})
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+	require.ErrorIs(t, err, watson.ErrInvalidFrame)
+}
+
+func TestNewFetcher_NoPath(t *testing.T) {
+	_, err := watson.NewFetcher(&watson.ClientOpts{})
+	require.Error(t, err)
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
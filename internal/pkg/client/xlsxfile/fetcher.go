@@ -0,0 +1,444 @@
+package xlsxfile
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+)
+
+var (
+	// ErrSheetNotFound returns when the configured sheet is not part of the
+	// workbook.
+	ErrSheetNotFound = errors.New("sheet not found")
+	// ErrInvalidWorkbook returns when a part of the workbook is missing or
+	// cannot be parsed.
+	ErrInvalidWorkbook = errors.New("invalid workbook")
+)
+
+// cellKind is the kind of the values formatted by a number format.
+type cellKind int
+
+const (
+	cellNumber cellKind = iota
+	cellDate
+	cellDateTime
+	cellDuration
+)
+
+var (
+	// elapsedTimePattern matches the elapsed time sections of number formats,
+	// like "[h]" or "[mm]".
+	elapsedTimePattern = regexp.MustCompile(`(?i)\[(h+|m+|s+)\]`)
+	// formatLiteralPattern matches the quoted texts, escaped characters and
+	// the bracketed sections of number formats, like colors or locales.
+	formatLiteralPattern = regexp.MustCompile(`"[^"]*"|\\.|\[[^\]]*\]`)
+	// cellReferencePattern matches the column letters of a cell reference.
+	cellReferencePattern = regexp.MustCompile(`^[A-Z]+`)
+)
+
+// builtinFormatKinds lists the kind of the built-in date and time number
+// formats, as they are not stored in the workbook.
+var builtinFormatKinds = map[int]cellKind{
+	14: cellDate,
+	15: cellDate,
+	16: cellDate,
+	17: cellDate,
+	18: cellDuration,
+	19: cellDuration,
+	20: cellDuration,
+	21: cellDuration,
+	22: cellDateTime,
+	45: cellDuration,
+	46: cellDuration,
+	47: cellDuration,
+}
+
+type xmlRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xmlRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var text strings.Builder
+	for _, run := range t.Runs {
+		text.WriteString(run.Text)
+	}
+
+	return text.String()
+}
+
+type xmlWorkbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xmlRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xmlSharedStrings struct {
+	Items []xmlRichText `xml:"si"`
+}
+
+type xmlStyles struct {
+	NumberFormats []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellFormats []struct {
+		NumberFormatID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xmlWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Reference string      `xml:"r,attr"`
+			Style     int         `xml:"s,attr"`
+			Type      string      `xml:"t,attr"`
+			Value     string      `xml:"v"`
+			Inline    xmlRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// workbook represents the parts of the workbook needed to read the values of
+// a sheet.
+type workbook struct {
+	archive       *zip.ReadCloser
+	date1904      bool
+	sharedStrings []string
+	styleKinds    []cellKind
+}
+
+// formatKind returns the kind of the values formatted by the custom number
+// format code. The codes are matched by their date and time placeholders,
+// after removing the literals.
+func formatKind(code string) cellKind {
+	code = elapsedTimePattern.ReplaceAllString(code, "h")
+	code = strings.ToLower(formatLiteralPattern.ReplaceAllString(code, ""))
+
+	hasDate := strings.ContainsAny(code, "yd")
+	hasTime := strings.ContainsAny(code, "hs")
+
+	switch {
+	case hasDate && hasTime:
+		return cellDateTime
+	case hasDate:
+		return cellDate
+	case hasTime:
+		return cellDuration
+	default:
+		return cellNumber
+	}
+}
+
+// columnIndex returns the zero-based index of the column of the cell
+// reference, like 27 for "AB3".
+func columnIndex(reference string) int {
+	index := 0
+	for _, letter := range cellReferencePattern.FindString(strings.ToUpper(reference)) {
+		index = index*26 + int(letter-'A') + 1
+	}
+
+	return index - 1
+}
+
+func (w *workbook) decode(name string, v any) error {
+	file, err := w.archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWorkbook, err)
+	}
+	defer file.Close()
+
+	if err = xml.NewDecoder(file).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidWorkbook, name, err)
+	}
+
+	return nil
+}
+
+// hasPart returns if the part exists in the workbook. The shared strings and
+// styles parts are optional.
+func (w *workbook) hasPart(name string) bool {
+	for _, file := range w.archive.File {
+		if file.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *workbook) readSharedStrings() error {
+	if !w.hasPart("xl/sharedStrings.xml") {
+		return nil
+	}
+
+	var sharedStrings xmlSharedStrings
+	if err := w.decode("xl/sharedStrings.xml", &sharedStrings); err != nil {
+		return err
+	}
+
+	for _, item := range sharedStrings.Items {
+		w.sharedStrings = append(w.sharedStrings, item.String())
+	}
+
+	return nil
+}
+
+func (w *workbook) readStyles() error {
+	if !w.hasPart("xl/styles.xml") {
+		return nil
+	}
+
+	var styles xmlStyles
+	if err := w.decode("xl/styles.xml", &styles); err != nil {
+		return err
+	}
+
+	kinds := map[int]cellKind{}
+	for id, kind := range builtinFormatKinds {
+		kinds[id] = kind
+	}
+
+	for _, format := range styles.NumberFormats {
+		kinds[format.ID] = formatKind(format.Code)
+	}
+
+	for _, cellFormat := range styles.CellFormats {
+		w.styleKinds = append(w.styleKinds, kinds[cellFormat.NumberFormatID])
+	}
+
+	return nil
+}
+
+// sheetPath returns the path of the sheet in the archive. If the name is
+// empty, the path of the first sheet is returned.
+func (w *workbook) sheetPath(name string) (string, error) {
+	var book xmlWorkbook
+	if err := w.decode("xl/workbook.xml", &book); err != nil {
+		return "", err
+	}
+
+	w.date1904 = book.Properties.Date1904
+
+	var relationships xmlRelationships
+	if err := w.decode("xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return "", err
+	}
+
+	for _, sheet := range book.Sheets {
+		if name != "" && !strings.EqualFold(sheet.Name, name) {
+			continue
+		}
+
+		for _, relationship := range relationships.Relationships {
+			if relationship.ID != sheet.ID {
+				continue
+			}
+
+			// The targets are relative to the workbook, unless absolute
+			if strings.HasPrefix(relationship.Target, "/") {
+				return strings.TrimPrefix(relationship.Target, "/"), nil
+			}
+
+			return path.Join("xl", relationship.Target), nil
+		}
+
+		return "", fmt.Errorf("%w: no relationship of sheet %s", ErrInvalidWorkbook, sheet.Name)
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("%w: no sheets", ErrInvalidWorkbook)
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrSheetNotFound, name)
+}
+
+// formatSerial returns the date, date time or duration of the serial number,
+// formatted the way the CSV fetcher parses them.
+func (w *workbook) formatSerial(serial float64, kind cellKind) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.Local)
+	if w.date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.Local)
+	}
+
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 24 * 60 * 60)
+
+	switch kind {
+	case cellDate:
+		return epoch.AddDate(0, 0, int(days)).Format("2006-01-02")
+	case cellDateTime:
+		return epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second).Format("2006-01-02 15:04:05")
+	default:
+		elapsed := time.Duration(math.Round(serial*24*60*60)) * time.Second
+		return fmt.Sprintf("%d:%02d:%02d", int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60)
+	}
+}
+
+// cellValue returns the text value of the cell.
+func (w *workbook) cellValue(cellType string, style int, value string, inline xmlRichText) (string, error) {
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(value)
+		if err != nil || index < 0 || index >= len(w.sharedStrings) {
+			return "", fmt.Errorf("%w: invalid shared string %q", ErrInvalidWorkbook, value)
+		}
+
+		return w.sharedStrings[index], nil
+	case "inlineStr":
+		return inline.String(), nil
+	case "b":
+		if value == "1" {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	case "str", "e", "d":
+		return value, nil
+	}
+
+	if value == "" {
+		return "", nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid number %q", ErrInvalidWorkbook, value)
+	}
+
+	if style >= 0 && style < len(w.styleKinds) && w.styleKinds[style] != cellNumber {
+		return w.formatSerial(number, w.styleKinds[style]), nil
+	}
+
+	return strconv.FormatFloat(number, 'f', -1, 64), nil
+}
+
+// readRecords returns the non-empty rows of the sheet.
+func (w *workbook) readRecords(sheet string) ([]csvfile.Record, error) {
+	if err := w.readSharedStrings(); err != nil {
+		return nil, err
+	}
+
+	if err := w.readStyles(); err != nil {
+		return nil, err
+	}
+
+	sheetPath, err := w.sheetPath(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var worksheet xmlWorksheet
+	if err = w.decode(sheetPath, &worksheet); err != nil {
+		return nil, err
+	}
+
+	var records []csvfile.Record
+
+	for i, row := range worksheet.Rows {
+		record := csvfile.Record{Line: row.Number}
+		if record.Line == 0 {
+			record.Line = i + 1
+		}
+
+		for j, cell := range row.Cells {
+			value, err := w.cellValue(cell.Type, cell.Style, cell.Value, cell.Inline)
+			if err != nil {
+				return nil, err
+			}
+
+			// The cells without reference follow the previous cell
+			index := j
+			if cell.Reference != "" {
+				index = columnIndex(cell.Reference)
+			} else if len(record.Values) > 0 {
+				index = len(record.Values)
+			}
+
+			for len(record.Values) <= index {
+				record.Values = append(record.Values, "")
+			}
+
+			record.Values[index] = strings.TrimSpace(value)
+		}
+
+		if strings.Join(record.Values, "") != "" {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// recordReader returns a csvfile.RecordReader reading the sheet of the XLSX
+// file. If the sheet is empty, the first sheet is read.
+func recordReader(filePath string, sheet string) csvfile.RecordReader {
+	return func() ([]csvfile.Record, error) {
+		archive, err := zip.OpenReader(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+
+		w := &workbook{archive: archive}
+		return w.readRecords(sheet)
+	}
+}
+
+// mappingOpts returns the CSV options used to parse the records of the sheet.
+func mappingOpts(opts *ClientOpts) *csvfile.ClientOpts {
+	mapping := opts.Mapping
+	mapping.BaseClientOpts = opts.BaseClientOpts
+	mapping.Path = opts.Path
+
+	if mapping.Locale == nil {
+		mapping.Locale = opts.Locale
+	}
+
+	return &mapping
+}
+
+// InferColumns proposes the columns of the sheet the same way as
+// csvfile.InferColumns.
+func InferColumns(opts *ClientOpts) ([]csvfile.Column, error) {
+	return csvfile.InferRecordColumns(mappingOpts(opts), recordReader(opts.Path, opts.Sheet))
+}
+
+// NewFetcher returns a new XLSX file client for reading entries. The rows of
+// the sheet are parsed the same way as the rows of a CSV file, using the
+// columns and formats of the mapping.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no XLSX file path provided")
+	}
+
+	return csvfile.NewRecordFetcher(mappingOpts(opts), recordReader(opts.Path, opts.Sheet))
+}
@@ -0,0 +1,365 @@
+package xlsxfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// NoProjectName is the row name of the entries without project.
+	NoProjectName string = "(no project)"
+
+	// Style indices of the cellXfs defined by stylesXML
+	styleHeader int = 1
+	styleHours  int = 2
+	styleTotal  int = 3
+
+	// The daily columns are B to H, followed by the weekly total in I
+	firstDayColumn byte = 'B'
+	totalColumn    byte = 'I'
+)
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>%s</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>%s</sheets><calcPr fullCalcOnLoad="1"/></workbook>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">%s<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+// stylesXML defines the default, header, hours and total cell styles, and the
+// differential style used to highlight the days under the target hours.
+const stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="4"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="2" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/></cellXfs><dxfs count="1"><dxf><font><color rgb="FF9C0006"/></font><fill><patternFill><bgColor rgb="FFFFC7CE"/></patternFill></fill></dxf></dxfs></styleSheet>`
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+// Since the XLSX file is read and written locally, the timeout is not used.
+type ClientOpts struct {
+	client.BaseClientOpts
+	// Path is the path of the read or written XLSX file. The written file is
+	// overwritten if it exists.
+	Path string
+	// Sheet is the name of the read sheet. If empty, the first sheet is read.
+	Sheet string
+	// Mapping sets the columns and formats of the read sheet, the same way as
+	// for CSV files. Its path is not used. If its locale is not set, Locale is
+	// used.
+	Mapping csvfile.ClientOpts
+	// DailyTarget is the expected hours of a working day. The daily totals
+	// under the target are highlighted.
+	DailyTarget time.Duration
+	// WorkingDays lists the days of the week when the daily target applies. If
+	// empty, worklog.DefaultWorkingDays are used.
+	WorkingDays []time.Weekday
+	// WeekdayTargets overrides the daily target per day of the week, like less
+	// hours on Fridays. The days set have a target regardless of WorkingDays.
+	WeekdayTargets map[time.Weekday]time.Duration
+	// Holidays lists the dates in YYYY-MM-DD format when the daily target does
+	// not apply.
+	Holidays []string
+	// Locale sets the format of the dates in the header row. If not set, the
+	// default locale is used.
+	Locale *locale.Locale
+}
+
+type xlsxClient struct {
+	*client.BaseClientOpts
+	client.DefaultUploader
+	opts ClientOpts
+}
+
+// week represents the time spent per project and day of an ISO week.
+type week struct {
+	name     string
+	monday   time.Time
+	projects map[string][7]time.Duration
+}
+
+func escape(text string) string {
+	var escaped bytes.Buffer
+	// Writing to a bytes.Buffer cannot fail
+	_ = xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+func stringCell(ref string, style int, text string) string {
+	return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, style, escape(text))
+}
+
+func numberCell(ref string, style int, value float64) string {
+	return fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func formulaCell(ref string, style int, formula string) string {
+	return fmt.Sprintf(`<c r="%s" s="%d"><f>%s</f></c>`, ref, style, escape(formula))
+}
+
+// groupByWeek groups the time spent of the entries by ISO week, project and
+// day of the week, starting on Monday.
+func groupByWeek(entries worklog.Entries) []*week {
+	weeks := map[string]*week{}
+
+	for _, entry := range entries {
+		start := entry.Start.Local()
+		year, weekNumber := start.ISOWeek()
+		name := fmt.Sprintf("%d-W%02d", year, weekNumber)
+
+		// Days of the ISO week are indexed from Monday
+		dayIndex := (int(start.Weekday()) + 6) % 7
+
+		w, ok := weeks[name]
+		if !ok {
+			monday := time.Date(start.Year(), start.Month(), start.Day()-dayIndex, 0, 0, 0, 0, start.Location())
+			w = &week{name: name, monday: monday, projects: map[string][7]time.Duration{}}
+			weeks[name] = w
+		}
+
+		project := entry.Project.Name
+		if project == "" {
+			project = NoProjectName
+		}
+
+		timeSpent := entry.BillableDuration + entry.UnbillableDuration
+
+		days := w.projects[project]
+		days[dayIndex] += timeSpent
+		w.projects[project] = days
+	}
+
+	var sortedWeeks []*week
+	for _, w := range weeks {
+		sortedWeeks = append(sortedWeeks, w)
+	}
+
+	sort.Slice(sortedWeeks, func(i, j int) bool {
+		return sortedWeeks[i].monday.Before(sortedWeeks[j].monday)
+	})
+
+	return sortedWeeks
+}
+
+// getTarget returns the expected hours of the date. Zero returns if the daily
+// target does not apply for the date.
+func (c *xlsxClient) getTarget(date time.Time) time.Duration {
+	for _, holiday := range c.opts.Holidays {
+		if holiday == date.Format("2006-01-02") {
+			return 0
+		}
+	}
+
+	if target, ok := c.opts.WeekdayTargets[date.Weekday()]; ok {
+		return target
+	}
+
+	for _, weekday := range c.opts.WorkingDays {
+		if weekday == date.Weekday() {
+			return c.opts.DailyTarget
+		}
+	}
+
+	return 0
+}
+
+func (c *xlsxClient) renderSheet(w *week) string {
+	var rows strings.Builder
+
+	// Header row
+	rows.WriteString(`<row r="1">`)
+	rows.WriteString(stringCell("A1", styleHeader, "Project"))
+	for i := 0; i < 7; i++ {
+		day := w.monday.AddDate(0, 0, i)
+		header := fmt.Sprintf("%s %s", day.Weekday().String()[:3], c.opts.Locale.FormatDate(day))
+		rows.WriteString(stringCell(fmt.Sprintf("%c1", firstDayColumn+byte(i)), styleHeader, header))
+	}
+	rows.WriteString(stringCell(fmt.Sprintf("%c1", totalColumn), styleHeader, "Total"))
+	rows.WriteString(`</row>`)
+
+	var projects []string
+	for project := range w.projects {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	// Project rows
+	for i, project := range projects {
+		row := i + 2
+
+		rows.WriteString(fmt.Sprintf(`<row r="%d">`, row))
+		rows.WriteString(stringCell(fmt.Sprintf("A%d", row), 0, project))
+
+		for day, timeSpent := range w.projects[project] {
+			if timeSpent == 0 {
+				continue
+			}
+
+			rows.WriteString(numberCell(fmt.Sprintf("%c%d", firstDayColumn+byte(day), row), styleHours, math.Round(timeSpent.Hours()*100)/100))
+		}
+
+		rows.WriteString(formulaCell(fmt.Sprintf("%c%d", totalColumn, row), styleTotal, fmt.Sprintf("SUM(%c%d:%c%d)", firstDayColumn, row, firstDayColumn+6, row)))
+		rows.WriteString(`</row>`)
+	}
+
+	// Total row
+	totalRow := len(projects) + 2
+	lastProjectRow := totalRow - 1
+
+	rows.WriteString(fmt.Sprintf(`<row r="%d">`, totalRow))
+	rows.WriteString(stringCell(fmt.Sprintf("A%d", totalRow), styleHeader, "Total"))
+	for column := firstDayColumn; column <= totalColumn; column++ {
+		rows.WriteString(formulaCell(fmt.Sprintf("%c%d", column, totalRow), styleTotal, fmt.Sprintf("SUM(%c2:%c%d)", column, column, lastProjectRow)))
+	}
+	rows.WriteString(`</row>`)
+
+	// Highlight the daily totals under the target on the target days, grouping
+	// the days having the same target into one rule
+	var targets []time.Duration
+	targetCells := map[time.Duration][]string{}
+	for i := 0; i < 7; i++ {
+		target := c.getTarget(w.monday.AddDate(0, 0, i))
+		if target <= 0 {
+			continue
+		}
+
+		if _, ok := targetCells[target]; !ok {
+			targets = append(targets, target)
+		}
+
+		targetCells[target] = append(targetCells[target], fmt.Sprintf("%c%d", firstDayColumn+byte(i), totalRow))
+	}
+
+	var conditionalFormatting strings.Builder
+	for i, target := range targets {
+		conditionalFormatting.WriteString(fmt.Sprintf(
+			`<conditionalFormatting sqref="%s"><cfRule type="cellIs" dxfId="0" priority="%d" operator="lessThan"><formula>%s</formula></cfRule></conditionalFormatting>`,
+			strings.Join(targetCells[target], " "),
+			i+1,
+			strconv.FormatFloat(target.Hours(), 'f', -1, 64),
+		))
+	}
+
+	return fmt.Sprintf(
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><cols><col min="1" max="1" width="30" customWidth="1"/><col min="2" max="9" width="16" customWidth="1"/></cols><sheetData>%s</sheetData>%s</worksheet>`,
+		rows.String(),
+		conditionalFormatting.String(),
+	)
+}
+
+// writeWorkbook writes the workbook containing a sheet per ISO week.
+func (c *xlsxClient) writeWorkbook(w io.Writer, weeks []*week) error {
+	var contentTypes, sheets, rels strings.Builder
+	files := map[string]string{}
+
+	for i, wk := range weeks {
+		id := i + 1
+		sheetPath := fmt.Sprintf("worksheets/sheet%d.xml", id)
+
+		contentTypes.WriteString(fmt.Sprintf(`<Override PartName="/xl/%s" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, sheetPath))
+		sheets.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(wk.name), id, id))
+		rels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="%s"/>`, id, sheetPath))
+
+		files["xl/"+sheetPath] = c.renderSheet(wk)
+	}
+
+	files["[Content_Types].xml"] = fmt.Sprintf(contentTypesXML, contentTypes.String())
+	files["_rels/.rels"] = rootRelsXML
+	files["xl/workbook.xml"] = fmt.Sprintf(workbookXML, sheets.String())
+	files["xl/_rels/workbook.xml.rels"] = fmt.Sprintf(workbookRelsXML, rels.String())
+	files["xl/styles.xml"] = stylesXML
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.WriteString(file, files[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func (c *xlsxClient) UploadEntries(_ context.Context, entries worklog.Entries, errChan chan error, opts *client.UploadOpts) {
+	err := func() error {
+		// A workbook without sheets cannot be opened, hence no file is
+		// written if there are no entries
+		weeks := groupByWeek(entries)
+		if len(weeks) == 0 {
+			return nil
+		}
+
+		file, err := os.Create(c.opts.Path)
+		if err != nil {
+			return err
+		}
+
+		if err = c.writeWorkbook(file, weeks); err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+	}()
+
+	if err != nil {
+		err = fmt.Errorf("%w: %w", client.ErrUploadEntries, err)
+	}
+
+	// The entries are written at once, hence they succeed or fail together
+	for _, entry := range entries {
+		tracker := c.StartTracking(entry, opts.ProgressWriter)
+		c.StopTracking(tracker, err)
+		errChan <- err
+	}
+}
+
+// NewUploader returns a new XLSX file client for writing entries.
+func NewUploader(opts *ClientOpts) (client.Uploader, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no XLSX file path provided")
+	}
+
+	clientOpts := *opts
+
+	if len(clientOpts.WorkingDays) == 0 {
+		clientOpts.WorkingDays = worklog.DefaultWorkingDays
+	}
+
+	if clientOpts.Locale == nil {
+		clientOpts.Locale = locale.Default()
+	}
+
+	return &xlsxClient{
+		BaseClientOpts: &clientOpts.BaseClientOpts,
+		opts:           clientOpts,
+	}, nil
+}
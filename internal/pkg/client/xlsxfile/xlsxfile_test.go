@@ -0,0 +1,282 @@
+package xlsxfile_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/csvfile"
+	"github.com/gabor-boros/minutes/internal/pkg/client/xlsxfile"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func readZipFiles(t *testing.T, path string) map[string]string {
+	archive, err := zip.OpenReader(path)
+	require.Nil(t, err)
+	defer archive.Close()
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.Nil(t, err)
+
+		content, err := io.ReadAll(reader)
+		require.Nil(t, err)
+		require.Nil(t, reader.Close())
+
+		// Every part of the package must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err = decoder.Token(); err != nil {
+				require.ErrorIs(t, err, io.EOF, file.Name)
+				break
+			}
+		}
+
+		files[file.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestXLSXClient_UploadEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.xlsx")
+
+	uploader, err := xlsxfile.NewUploader(&xlsxfile.ClientOpts{
+		Path:        path,
+		DailyTarget: time.Hour * 8,
+		Holidays:    []string{"2021-10-05"},
+	})
+	require.Nil(t, err)
+
+	// 2021-10-04 is the Monday of the 40th ISO week
+	monday := time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local)
+
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{Name: "Internal projects"},
+			Summary:          "Write documentation",
+			Start:            monday,
+			BillableDuration: time.Hour * 2,
+		},
+		{
+			Project:            worklog.IDNameField{Name: "Internal projects"},
+			Summary:            "Fix the bug",
+			Start:              monday.Add(time.Hour * 3),
+			BillableDuration:   time.Hour,
+			UnbillableDuration: time.Minute * 30,
+		},
+		{
+			Project:          worklog.IDNameField{Name: "<ACME> & Co"},
+			Summary:          "Meeting",
+			Start:            monday.AddDate(0, 0, 2),
+			BillableDuration: time.Minute * 45,
+		},
+		{
+			Summary:          "Next week",
+			Start:            monday.AddDate(0, 0, 7),
+			BillableDuration: time.Hour,
+		},
+	}
+
+	errChan := make(chan error, len(entries))
+	uploader.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{})
+
+	for i := 0; i < len(entries); i++ {
+		require.Nil(t, <-errChan)
+	}
+
+	files := readZipFiles(t, path)
+
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="2021-W40" sheetId="1" r:id="rId1"/>`)
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="2021-W41" sheetId="2" r:id="rId2"/>`)
+	require.Contains(t, files["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<t>Mon 2021-10-04</t>`)
+	require.Contains(t, sheet, `<t>Sun 2021-10-10</t>`)
+
+	// Projects are sorted by name, the entries of the same day are summed up
+	require.Contains(t, sheet, `<c r="A2" s="0" t="inlineStr"><is><t>&lt;ACME&gt; &amp; Co</t></is></c><c r="D2" s="2"><v>0.75</v></c>`)
+	require.Contains(t, sheet, `<c r="A3" s="0" t="inlineStr"><is><t>Internal projects</t></is></c><c r="B3" s="2"><v>3.5</v></c>`)
+
+	// Formulas for the project and daily totals
+	require.Contains(t, sheet, `<c r="I2" s="3"><f>SUM(B2:H2)</f></c>`)
+	require.Contains(t, sheet, `<c r="B4" s="3"><f>SUM(B2:B3)</f></c>`)
+	require.Contains(t, sheet, `<c r="I4" s="3"><f>SUM(I2:I3)</f></c>`)
+
+	// Working days are highlighted under the target, except the holiday
+	require.Contains(t, sheet, `<conditionalFormatting sqref="B4 D4 E4 F4"><cfRule type="cellIs" dxfId="0" priority="1" operator="lessThan"><formula>8</formula></cfRule></conditionalFormatting>`)
+
+	require.Contains(t, files["xl/worksheets/sheet2.xml"], `<t>(no project)</t>`)
+}
+
+func TestXLSXClient_UploadEntries_WeekdayTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.xlsx")
+
+	uploader, err := xlsxfile.NewUploader(&xlsxfile.ClientOpts{
+		Path:        path,
+		DailyTarget: time.Hour * 8,
+		WeekdayTargets: map[time.Weekday]time.Duration{
+			time.Friday:   time.Hour * 4,
+			time.Saturday: time.Hour*2 + time.Minute*30,
+			time.Monday:   0,
+		},
+	})
+	require.Nil(t, err)
+
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{Name: "Internal projects"},
+			Summary:          "Write documentation",
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+	}
+
+	errChan := make(chan error, len(entries))
+	uploader.UploadEntries(context.Background(), entries, errChan, &client.UploadOpts{})
+	require.Nil(t, <-errChan)
+
+	sheet := readZipFiles(t, path)["xl/worksheets/sheet1.xml"]
+
+	// The days having the same target are highlighted by the same rule
+	require.Contains(t, sheet, `<conditionalFormatting sqref="C3 D3 E3"><cfRule type="cellIs" dxfId="0" priority="1" operator="lessThan"><formula>8</formula></cfRule></conditionalFormatting>`)
+	require.Contains(t, sheet, `<conditionalFormatting sqref="F3"><cfRule type="cellIs" dxfId="0" priority="2" operator="lessThan"><formula>4</formula></cfRule></conditionalFormatting>`)
+	require.Contains(t, sheet, `<conditionalFormatting sqref="G3"><cfRule type="cellIs" dxfId="0" priority="3" operator="lessThan"><formula>2.5</formula></cfRule></conditionalFormatting>`)
+	require.NotContains(t, sheet, `sqref="B3`)
+}
+
+func TestXLSXClient_UploadEntries_NoEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.xlsx")
+
+	uploader, err := xlsxfile.NewUploader(&xlsxfile.ClientOpts{Path: path})
+	require.Nil(t, err)
+
+	uploader.UploadEntries(context.Background(), worklog.Entries{}, make(chan error), &client.UploadOpts{})
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestNewUploader_NoPath(t *testing.T) {
+	_, err := xlsxfile.NewUploader(&xlsxfile.ClientOpts{})
+	require.Error(t, err)
+}
+
+func writeWorkbook(t *testing.T, path string, sheetXML string) {
+	file, err := os.Create(path)
+	require.Nil(t, err)
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Timesheet" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>Date</t></si><si><t>Start</t></si><si><t>Ticket</t></si><si><r><t>Time </t></r><r><t>spent</t></r></si><si><t>Description</t></si><si><t>TASK-123</t></si></sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="2"><numFmt numFmtId="164" formatCode="[h]:mm"/><numFmt numFmtId="165" formatCode="dd/mm/yyyy\ hh:mm"/></numFmts><cellXfs count="4"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`,
+		"xl/worksheets/sheet2.xml": sheetXML,
+	}
+
+	archive := zip.NewWriter(file)
+	for name, content := range parts {
+		writer, err := archive.Create(name)
+		require.Nil(t, err)
+
+		_, err = writer.Write([]byte(content))
+		require.Nil(t, err)
+	}
+
+	require.Nil(t, archive.Close())
+	require.Nil(t, file.Close())
+}
+
+// timesheetXML has a header row, followed by the rows of 2021-10-02, an empty
+// row and a row of 2021-10-01.
+const timesheetXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+	`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c></row>` +
+	`<row r="2"><c r="A2" s="1"><v>44471</v></c><c r="B2" s="3"><v>44471.375</v></c><c r="C2" t="s"><v>5</v></c><c r="D2" s="2"><v>0.0625</v></c><c r="E2" t="inlineStr"><is><t>Fix the bug</t></is></c></row>` +
+	`<row r="3"><c r="A3" s="1"><v>44471</v></c><c r="C3" t="str"><v>TASK-456</v></c><c r="D3"><v>0.75</v></c><c r="E3" t="inlineStr"><is><t>Write documentation</t></is></c></row>` +
+	`<row r="4"><c r="A4" t="inlineStr"><is><t> </t></is></c></row>` +
+	`<row r="5"><c r="A5" s="1"><v>44470</v></c><c r="C5" t="str"><v>TASK-789</v></c><c r="D5"><v>1</v></c><c r="E5" t="inlineStr"><is><t>Too early</t></is></c></row>` +
+	`</sheetData></worksheet>`
+
+func TestXLSXClient_FetchEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timesheet.xlsx")
+	writeWorkbook(t, path, timesheetXML)
+
+	columns, err := csvfile.ParseColumns([]string{"date:Date", "start:Start", "task:Ticket", "duration:Time spent", "summary:Description"})
+	require.Nil(t, err)
+
+	fetcher, err := xlsxfile.NewFetcher(&xlsxfile.ClientOpts{
+		Path:    path,
+		Sheet:   "timesheet",
+		Mapping: csvfile.ClientOpts{Columns: columns},
+	})
+	require.Nil(t, err)
+
+	entries, err := fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	})
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, "Fix the bug", entries[0].Summary)
+	require.Equal(t, time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local), entries[0].Start)
+	require.Equal(t, time.Minute*90, entries[0].BillableDuration)
+	require.Equal(t, []string{"line 2"}, entries[0].Provenance.SourceIDs)
+
+	require.Equal(t, "TASK-456", entries[1].Task.Name)
+	require.Equal(t, time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local), entries[1].Start)
+	require.Equal(t, time.Minute*45, entries[1].BillableDuration)
+}
+
+func TestXLSXClient_FetchEntries_SheetNotFound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timesheet.xlsx")
+	writeWorkbook(t, path, timesheetXML)
+
+	fetcher, err := xlsxfile.NewFetcher(&xlsxfile.ClientOpts{
+		Path:  path,
+		Sheet: "Invoices",
+	})
+	require.Nil(t, err)
+
+	_, err = fetcher.FetchEntries(context.Background(), &client.FetchOpts{
+		Start: time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		End:   time.Date(2021, 10, 3, 0, 0, 0, 0, time.Local),
+	})
+	require.ErrorIs(t, err, xlsxfile.ErrSheetNotFound)
+}
+
+func TestInferColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timesheet.xlsx")
+	writeWorkbook(t, path, timesheetXML)
+
+	columns, err := xlsxfile.InferColumns(&xlsxfile.ClientOpts{
+		Path:  path,
+		Sheet: "Timesheet",
+	})
+	require.Nil(t, err)
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+
+	require.Equal(t, []string{csvfile.ColumnDate, csvfile.ColumnStart, csvfile.ColumnTask, csvfile.ColumnDuration, csvfile.ColumnSummary}, names)
+}
@@ -0,0 +1,261 @@
+package youtrack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PathWorkItems is the endpoint used to search work items.
+	PathWorkItems string = "/api/workItems"
+	// PathIssue is the path of the issues on the web interface.
+	PathIssue string = "/issue/%s"
+	// MaxPageSize is the number of work items requested on a page.
+	MaxPageSize int = 100
+	// CurrentUser is the author referring to the owner of the token.
+	CurrentUser string = "me"
+
+	// AttributeWorkType is the name of the attribute set to the work type of
+	// the work items, like "Development".
+	AttributeWorkType string = "youtrack.work_type"
+
+	// workItemFields lists the fields of the work items returned.
+	workItemFields string = "id,date,created,text,duration(minutes),type(name),issue(idReadable,summary,project(shortName,name))"
+)
+
+// Project represents the project of an issue.
+type Project struct {
+	ShortName string `json:"shortName"`
+	Name      string `json:"name"`
+}
+
+// Issue represents the issue a work item is logged on.
+type Issue struct {
+	// IDReadable is the ID of the issue shown to the users, like "MIN-12".
+	IDReadable string  `json:"idReadable"`
+	Summary    string  `json:"summary"`
+	Project    Project `json:"project"`
+}
+
+// WorkItemType represents the work type of a work item, like "Development".
+type WorkItemType struct {
+	Name string `json:"name"`
+}
+
+// Duration represents the time spent on a work item.
+type Duration struct {
+	Minutes int `json:"minutes"`
+}
+
+// WorkItem represents the work item fetched from YouTrack. The date is the
+// midnight of the day the work was done, in UTC.
+type WorkItem struct {
+	ID       string          `json:"id"`
+	Date     utils.Timestamp `json:"date"`
+	Created  utils.Timestamp `json:"created"`
+	Text     string          `json:"text"`
+	Duration Duration        `json:"duration"`
+	Type     *WorkItemType   `json:"type"`
+	Issue    Issue           `json:"issue"`
+}
+
+// Start returns the start date created from the date of the work item and the
+// time of its creation, since YouTrack does not record when the work started.
+func (w *WorkItem) Start() time.Time {
+	date := w.Date.UTC()
+	created := w.Created.Local()
+
+	return time.Date(
+		date.Year(),
+		date.Month(),
+		date.Day(),
+		created.Hour(),
+		created.Minute(),
+		created.Second(),
+		0,
+		time.Local,
+	)
+}
+
+// WorkType returns the name of the work type, or an empty string if the work
+// item has no work type.
+func (w *WorkItem) WorkType() string {
+	if w.Type == nil {
+		return ""
+	}
+
+	return w.Type.Name
+}
+
+// ClientOpts is the client specific options, extending client.BaseClientOpts.
+type ClientOpts struct {
+	client.BaseClientOpts
+	BaseURL string
+	// Token is the permanent token used to authenticate.
+	Token string
+	// UnbillableWorkTypes lists the names of the work types, like "Meeting",
+	// the work items of which are unbillable. The names are matched case
+	// insensitively. The work items of other work types, or without work
+	// type, are billable.
+	UnbillableWorkTypes []string
+}
+
+type youTrackClient struct {
+	*client.BaseClientOpts
+	*client.HTTPClient
+	authenticator       client.Authenticator
+	unbillableWorkTypes map[string]bool
+}
+
+func (c *youTrackClient) isUnbillable(workType string) bool {
+	return c.unbillableWorkTypes[strings.ToLower(workType)]
+}
+
+func (c *youTrackClient) parseWorkItems(workItems []WorkItem, _ *client.FetchOpts) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	for _, workItem := range workItems {
+		issueURL, err := c.URL(fmt.Sprintf(PathIssue, url.PathEscape(workItem.Issue.IDReadable)), map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+
+		billableDuration := time.Duration(workItem.Duration.Minutes) * time.Minute
+		unbillableDuration := time.Duration(0)
+
+		if c.isUnbillable(workItem.WorkType()) {
+			unbillableDuration = billableDuration
+			billableDuration = 0
+		}
+
+		entry := worklog.Entry{
+			Project: worklog.IDNameField{
+				ID:   workItem.Issue.Project.ShortName,
+				Name: workItem.Issue.Project.Name,
+			},
+			Task: worklog.IDNameField{
+				ID:   workItem.Issue.IDReadable,
+				Name: workItem.Issue.IDReadable,
+			},
+			Summary:            workItem.Text,
+			Notes:              workItem.Text,
+			Start:              workItem.Start(),
+			BillableDuration:   billableDuration,
+			UnbillableDuration: unbillableDuration,
+			Links:              []string{issueURL},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{workItem.ID},
+				SourceURLs: []string{issueURL},
+			},
+		}
+
+		// The text of the work items is optional, hence the summary of the
+		// issue is used instead
+		if entry.Summary == "" {
+			entry.Summary = workItem.Issue.Summary
+		}
+
+		if workType := workItem.WorkType(); workType != "" {
+			entry.SetAttribute(AttributeWorkType, workType)
+		}
+
+		entry.TaskHints = &worklog.TaskHints{Texts: []string{workItem.Issue.Summary, workItem.Text}}
+		entry.AddLinks(utils.ExtractURLs(workItem.Text)...)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *youTrackClient) fetchWorkItems(ctx context.Context, reqURL string) ([]WorkItem, *client.PaginatedFetchResponse, error) {
+	resp, err := c.Call(ctx, &client.HTTPRequestOpts{
+		Method:  http.MethodGet,
+		Url:     reqURL,
+		Auth:    c.authenticator,
+		Timeout: c.Timeout,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var workItems []WorkItem
+	if err = json.Unmarshal(resp, &workItems); err != nil {
+		return nil, nil, err
+	}
+
+	// YouTrack does not return the total number of work items, hence the
+	// pages are fetched until an empty page returns
+	return workItems, &client.PaginatedFetchResponse{}, nil
+}
+
+func (c *youTrackClient) FetchEntries(ctx context.Context, opts *client.FetchOpts) (worklog.Entries, error) {
+	author := opts.User
+	if author == "" {
+		author = CurrentUser
+	}
+
+	fetchURL, err := c.URL(PathWorkItems, map[string]string{
+		"fields":    workItemFields,
+		"author":    author,
+		"startDate": utils.DateFormatISO8601.Format(opts.Start),
+		"endDate":   utils.DateFormatISO8601.Format(opts.LastDay()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", client.ErrFetchEntries, err)
+	}
+
+	return client.PaginatedFetch(ctx, c.HTTPClient, &client.PaginatedFetchOpts[WorkItem]{
+		BaseFetchOpts: opts,
+		URL:           fetchURL,
+		PageSize:      MaxPageSize,
+		PageSizeParam: "$top",
+		OffsetParam:   "$skip",
+		FetchFunc:     c.fetchWorkItems,
+		ParseFunc:     c.parseWorkItems,
+	})
+}
+
+// NewFetcher returns a new YouTrack client for fetching work items.
+func NewFetcher(opts *ClientOpts) (client.Fetcher, error) {
+	if opts.BaseURL == "" {
+		return nil, errors.New("no YouTrack URL provided")
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator, err := client.NewTokenAuth("", "Bearer", opts.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	unbillableWorkTypes := make(map[string]bool, len(opts.UnbillableWorkTypes))
+	for _, workType := range opts.UnbillableWorkTypes {
+		unbillableWorkTypes[strings.ToLower(strings.TrimSpace(workType))] = true
+	}
+
+	return &youTrackClient{
+		BaseClientOpts: &opts.BaseClientOpts,
+		HTTPClient: &client.HTTPClient{
+			BaseURL: baseURL,
+		},
+		authenticator:       authenticator,
+		unbillableWorkTypes: unbillableWorkTypes,
+	}, nil
+}
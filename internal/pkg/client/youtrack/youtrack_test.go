@@ -0,0 +1,188 @@
+package youtrack_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/client/youtrack"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day int, hour int, minute int) time.Time {
+	return time.Date(2021, 10, day, hour, minute, 0, 0, time.Local)
+}
+
+// milliseconds returns the timestamp in milliseconds, as returned by YouTrack.
+func milliseconds(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+// newMockServer returns a server responding the pages by the number of work
+// items skipped, and an empty page for any other number.
+func newMockServer(t *testing.T, pages map[int]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, youtrack.PathWorkItems, r.URL.Path)
+		require.Equal(t, "Bearer perm:s3cr3t", r.Header.Get("Authorization"))
+
+		query := r.URL.Query()
+		require.Equal(t, youtrack.CurrentUser, query.Get("author"))
+		require.Equal(t, "2021-10-01", query.Get("startDate"))
+		require.Equal(t, "2021-10-02", query.Get("endDate"))
+		require.Contains(t, query.Get("fields"), "idReadable")
+		require.Equal(t, strconv.Itoa(youtrack.MaxPageSize), query.Get("$top"))
+
+		skip, err := strconv.Atoi(query.Get("$skip"))
+		require.Nil(t, err)
+
+		page, ok := pages[skip]
+		if !ok {
+			page = "[]"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(page))
+		require.Nil(t, err)
+	}))
+}
+
+func newTestFetcher(t *testing.T, serverURL string) client.Fetcher {
+	fetcher, err := youtrack.NewFetcher(&youtrack.ClientOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: client.DefaultRequestTimeout,
+		},
+		BaseURL:             serverURL,
+		Token:               "perm:s3cr3t",
+		UnbillableWorkTypes: []string{"meeting"},
+	})
+	require.Nil(t, err)
+
+	return fetcher
+}
+
+func TestWorkItem_Start(t *testing.T) {
+	workItem := youtrack.WorkItem{}
+	workItem.Date.Time = time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	workItem.Created.Time = at(2, 10, 30)
+
+	require.Equal(t, at(1, 10, 30), workItem.Start())
+}
+
+func TestYouTrackClient_FetchEntries(t *testing.T) {
+	day1 := milliseconds(time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC))
+	day2 := milliseconds(time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC))
+
+	mockServer := newMockServer(t, map[int]string{
+		0: `[
+			{"id":"1-1","date":` + day1 + `,"created":` + milliseconds(at(1, 9, 0)) + `,"text":"Investigating","duration":{"minutes":90},"type":{"name":"Development"},"issue":{"idReadable":"MIN-12","summary":"CPT-123 Fix thing","project":{"shortName":"MIN","name":"Minutes"}}},
+			{"id":"1-2","date":` + day1 + `,"created":` + milliseconds(at(1, 14, 0)) + `,"text":"","duration":{"minutes":30},"type":null,"issue":{"idReadable":"MIN-12","summary":"CPT-123 Fix thing","project":{"shortName":"MIN","name":"Minutes"}}}
+		]`,
+		2: `[
+			{"id":"1-3","date":` + day2 + `,"created":` + milliseconds(at(2, 10, 0)) + `,"text":"Planning","duration":{"minutes":60},"type":{"name":"Meeting"},"issue":{"idReadable":"MIN-12","summary":"CPT-123 Fix thing","project":{"shortName":"MIN","name":"Minutes"}}}
+		]`,
+	})
+	defer mockServer.Close()
+
+	minutes := worklog.IDNameField{ID: "MIN", Name: "Minutes"}
+	task := worklog.IDNameField{ID: "MIN-12", Name: "MIN-12"}
+	issueURL := mockServer.URL + "/issue/MIN-12"
+
+	expectedEntries := worklog.Entries{
+		{
+			Project:          minutes,
+			Task:             task,
+			Summary:          "Investigating",
+			Notes:            "Investigating",
+			Start:            at(1, 9, 0),
+			BillableDuration: time.Minute * 90,
+			Links:            []string{issueURL},
+			Attributes:       map[string]string{youtrack.AttributeWorkType: "Development"},
+			TaskHints:        &worklog.TaskHints{Texts: []string{"CPT-123 Fix thing", "Investigating"}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"1-1"},
+				SourceURLs: []string{issueURL},
+			},
+		},
+		{
+			Project:          minutes,
+			Task:             task,
+			Summary:          "CPT-123 Fix thing",
+			Start:            at(1, 14, 0),
+			BillableDuration: time.Minute * 30,
+			Links:            []string{issueURL},
+			TaskHints:        &worklog.TaskHints{Texts: []string{"CPT-123 Fix thing", ""}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"1-2"},
+				SourceURLs: []string{issueURL},
+			},
+		},
+		{
+			Project:            minutes,
+			Task:               task,
+			Summary:            "Planning",
+			Notes:              "Planning",
+			Start:              at(2, 10, 0),
+			UnbillableDuration: time.Hour,
+			Links:              []string{issueURL},
+			Attributes:         map[string]string{youtrack.AttributeWorkType: "Meeting"},
+			TaskHints:          &worklog.TaskHints{Texts: []string{"CPT-123 Fix thing", "Planning"}},
+			Provenance: worklog.Provenance{
+				SourceIDs:  []string{"1-3"},
+				SourceURLs: []string{issueURL},
+			},
+		},
+	}
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, expectedEntries, entries)
+}
+
+func TestYouTrackClient_FetchEntries_TagsAsTasks(t *testing.T) {
+	mockServer := newMockServer(t, map[int]string{
+		0: `[{"id":"1-1","date":1633046400000,"created":1633078800000,"text":"Investigating","duration":{"minutes":90},"issue":{"idReadable":"MIN-12","summary":"CPT-123 Fix thing","project":{"shortName":"MIN","name":"Minutes"}}}]`,
+	})
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, err)
+	entries = worklog.ExtractTasks(entries, regexp.MustCompile(`CPT-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.Equal(t, worklog.IDNameField{ID: "CPT-123", Name: "CPT-123"}, entries[0].Task)
+	require.Equal(t, "Investigating", entries[0].Summary)
+}
+
+func TestYouTrackClient_FetchEntries_Unauthorized(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer mockServer.Close()
+
+	entries, err := newTestFetcher(t, mockServer.URL).FetchEntries(context.Background(), &client.FetchOpts{
+		Start: at(1, 0, 0),
+		End:   at(3, 0, 0),
+	})
+
+	require.Nil(t, entries)
+	require.ErrorIs(t, err, client.ErrFetchEntries)
+}
+
+func TestNewFetcher_NoURL(t *testing.T) {
+	_, err := youtrack.NewFetcher(&youtrack.ClientOpts{Token: "perm:s3cr3t"})
+	require.EqualError(t, err, "no YouTrack URL provided")
+}
@@ -0,0 +1,118 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+var (
+	// ErrCommandFailed wraps the error when the hook command failed.
+	ErrCommandFailed = errors.New("hook command failed")
+	// ErrWebhookFailed wraps the error when calling the webhook failed.
+	ErrWebhookFailed = errors.New("hook webhook failed")
+)
+
+// Opts represents the options of the post-run hook. Both the command and the
+// webhook can be set; in that case, both of them are called.
+type Opts struct {
+	// Command is executed by the shell, receiving the payload on its standard
+	// input, like `curl -d @- https://ntfy.sh/minutes`.
+	Command string
+	// URL is the webhook the payload is posted to as JSON.
+	URL string
+	// Output is used as the standard output and error of the command.
+	Output io.Writer
+	// Timeout limits the execution of the command and the webhook call.
+	Timeout time.Duration
+	// HTTPClient is used to call the webhook. If not set, the
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// IsEnabled returns true if either the command or the webhook is set.
+func (o *Opts) IsEnabled() bool {
+	return o.Command != "" || o.URL != ""
+}
+
+// shellCommand returns the command executing the command line by the shell of
+// the platform.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// runCommand executes the hook command, writing the payload to its standard
+// input.
+func runCommand(ctx context.Context, payload []byte, opts *Opts) error {
+	cmd := shellCommand(ctx, opts.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = opts.Output
+	cmd.Stderr = opts.Output
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCommandFailed, err)
+	}
+
+	return nil
+}
+
+// callWebhook posts the payload to the webhook.
+func callWebhook(ctx context.Context, payload []byte, opts *Opts) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebhookFailed, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebhookFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return fmt.Errorf("%w: %d", ErrWebhookFailed, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Run calls the command and the webhook set by the options with the payload,
+// like the JSON summary of the run. Both of them are called even if the other
+// one failed, and the errors are joined.
+func Run(ctx context.Context, payload []byte, opts *Opts) error {
+	if !opts.IsEnabled() {
+		return nil
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var errs []error
+
+	if opts.Command != "" {
+		errs = append(errs, runCommand(ctxWithTimeout, payload, opts))
+	}
+
+	if opts.URL != "" {
+		errs = append(errs, callWebhook(ctxWithTimeout, payload, opts))
+	}
+
+	return errors.Join(errs...)
+}
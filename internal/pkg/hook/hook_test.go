@@ -0,0 +1,77 @@
+package hook_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/hook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Disabled(t *testing.T) {
+	require.Nil(t, hook.Run(context.Background(), []byte(`{}`), &hook.Opts{Timeout: time.Second}))
+}
+
+func TestRun_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command relies on a POSIX shell")
+	}
+
+	output := new(bytes.Buffer)
+	err := hook.Run(context.Background(), []byte(`{"uploaded":2}`), &hook.Opts{
+		Command: "cat",
+		Output:  output,
+		Timeout: time.Second * 5,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, `{"uploaded":2}`, output.String())
+
+	err = hook.Run(context.Background(), []byte(`{}`), &hook.Opts{
+		Command: "exit 3",
+		Output:  io.Discard,
+		Timeout: time.Second * 5,
+	})
+
+	require.ErrorIs(t, err, hook.ErrCommandFailed)
+}
+
+func TestRun_Webhook(t *testing.T) {
+	var received []byte
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		received, _ = io.ReadAll(r.Body)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockServer.Close()
+
+	err := hook.Run(context.Background(), []byte(`{"uploaded":2}`), &hook.Opts{
+		URL:     mockServer.URL,
+		Timeout: time.Second * 5,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, `{"uploaded":2}`, string(received))
+
+	err = hook.Run(context.Background(), []byte(`{}`), &hook.Opts{
+		URL:     mockServer.URL + "/fail",
+		Timeout: time.Second * 5,
+	})
+
+	require.ErrorIs(t, err, hook.ErrWebhookFailed)
+}
@@ -0,0 +1,269 @@
+package i18n
+
+// german is the German translation of the messages.
+var german = map[string]string{
+	// Run of the sync
+	"Continue? [y/n]: ":                                            "Fortfahren? [y/n]: ",
+	"User interruption. Aborting.":                                 "Abbruch durch den Benutzer.",
+	"Use the inferred columns? [y/n]: ":                            "Die erkannten Spalten verwenden? [y/n]: ",
+	"Inferred columns of %s:\n\n  %s = [%s]\n\n":                   "Erkannte Spalten von %s:\n\n  %s = [%s]\n\n",
+	"Worklog entries (%s - %s)":                                    "Arbeitszeiteinträge (%s - %s)",
+	"\nUploading worklog entries:\n\n":                             "\nArbeitszeiteinträge werden hochgeladen:\n\n",
+	"\nSuccessfully uploaded %d worklog entries!\n":                "\n%d Arbeitszeiteinträge erfolgreich hochgeladen!\n",
+	"\nFailed to upload %d worklog entries!\n\n":                   "\n%d Arbeitszeiteinträge konnten nicht hochgeladen werden!\n\n",
+	"\nFailed to write the uploaded entries to the calendar: %v\n": "\nDie hochgeladenen Einträge konnten nicht in den Kalender geschrieben werden: %v\n",
+	"    review: %s\n":                                             "    prüfen: %s\n",
+	"The %s target does not support payload preview.\n\n":          "Das Ziel %s unterstützt keine Vorschau der Nutzdaten.\n\n",
+	"Payloads of the %s target:\n\n":                               "Nutzdaten des Ziels %s:\n\n",
+	"Continuing without the failed sources:\n%v\n\n":               "Fortsetzung ohne die fehlgeschlagenen Quellen:\n%v\n\n",
+	"Fetching from %s failed (%s), retrying in %s...\n":            "Abruf von %s fehlgeschlagen (%s), neuer Versuch in %s...\n",
+	"Skipped %d invalid rows:\n":                                   "%d ungültige Zeilen übersprungen:\n",
+	"The invalid rows are written to %s\n":                         "Die ungültigen Zeilen wurden nach %s geschrieben\n",
+	"Timesheet written to %s\n":                                    "Stundenzettel nach %s geschrieben\n",
+
+	// Verification of the uploads
+	"Upload verification": "Prüfung des Uploads",
+	"Worklog":             "Arbeitszeiteintrag",
+	"Field":               "Feld",
+	"Sent":                "Gesendet",
+	"Received":            "Empfangen",
+	"\nVerified %d of %d sampled worklogs against the target.\n": "\n%d von %d Stichproben der Arbeitszeiteinträge wurden mit dem Ziel abgeglichen.\n",
+	"%d sampled worklogs do not match the uploaded entries":      "%d Stichproben der Arbeitszeiteinträge stimmen nicht mit den hochgeladenen Einträgen überein",
+	"target does not support verifying the uploaded worklogs":    "Das Ziel unterstützt keine Prüfung der hochgeladenen Arbeitszeiteinträge",
+
+	// Table of the entries
+	"task":             "aufgabe",
+	"summary":          "beschreibung",
+	"project":          "projekt",
+	"client":           "kunde",
+	"start":            "beginn",
+	"end":              "ende",
+	"billable":         "abrechenbar",
+	"unbillable":       "nicht abrechenbar",
+	"attributes":       "attribute",
+	"total time spent": "gesamte erfasste zeit",
+	"You have %d complete and %d incomplete items. Before proceeding, please double-check them.\n": "Sie haben %d vollständige und %d unvollständige Einträge. Bitte prüfen Sie diese vor dem Fortfahren.\n",
+	"Entry provenance": "Herkunft der Einträge",
+	"Provenance":       "Herkunft",
+
+	// Timesheet
+	"Timesheet":          "Stundenzettel",
+	"Employee:":          "Mitarbeiter:",
+	"Client:":            "Kunde:",
+	"Period:":            "Zeitraum:",
+	"Date":               "Datum",
+	"Project":            "Projekt",
+	"Task":               "Aufgabe",
+	"Summary":            "Beschreibung",
+	"Hours":              "Stunden",
+	"Start":              "Beginn",
+	"End":                "Ende",
+	"Total":              "Gesamt",
+	"Date:":              "Datum:",
+	"Employee signature": "Unterschrift Mitarbeiter",
+	"Client signature":   "Unterschrift Kunde",
+	"Page %d of %d":      "Seite %d von %d",
+
+	// Quick add and timer
+	"the entry is incomplete, set its project and client or stage it":                   "Der Eintrag ist unvollständig, setzen Sie sein Projekt und seinen Kunden oder stellen Sie ihn bereit",
+	"Staged entry #%s: %s %s %s\nThe entry is uploaded by the next sync.\n":             "Eintrag #%s bereitgestellt: %s %s %s\nDer Eintrag wird bei der nächsten Synchronisation hochgeladen.\n",
+	"the timer of %s is already running since %s, stop it first":                        "Der Timer von %s läuft bereits seit %s, stoppen Sie ihn zuerst",
+	"Started the timer of %s at %s.\n":                                                  "Timer von %s um %s gestartet.\n",
+	"no timer is running":                                                               "Es läuft kein Timer",
+	"Stopped the timer of %s after %s.\nStaged entry #%s, uploaded by the next sync.\n": "Timer von %s nach %s gestoppt.\nEintrag #%s bereitgestellt, er wird bei der nächsten Synchronisation hochgeladen.\n",
+
+	// Backfill
+	"backfill start and end must be set":                                              "Beginn und Ende des Nachtrags müssen gesetzt sein",
+	"backfill chunk days must be positive":                                            "Die Tage je Abschnitt des Nachtrags müssen positiv sein",
+	"backfill chunk delay must not be negative":                                       "Die Pause zwischen den Abschnitten des Nachtrags darf nicht negativ sein",
+	"Fetching chunk %d of %d for validation...\n":                                     "Abschnitt %d von %d wird zur Prüfung abgerufen...\n",
+	"\nValidated %d issues, %d accounts and %d attributes against the %s target.\n\n": "\n%d Vorgänge, %d Konten und %d Attribute wurden mit dem Ziel %s geprüft.\n\n",
+	"Remote validation problems":                                                      "Probleme der Prüfung am Ziel",
+	"Kind":                                                                            "Art",
+	"Value":                                                                           "Wert",
+	"Entries":                                                                         "Einträge",
+	"Problem":                                                                         "Problem",
+	"GO: the target accepts every issue, account and attribute.\n\n":                                        "GO: Das Ziel akzeptiert alle Vorgänge, Konten und Attribute.\n\n",
+	"NO-GO: the target would reject %d values, fix them before the backfill.\n\n":                           "NO-GO: Das Ziel würde %d Werte ablehnen, korrigieren Sie diese vor dem Nachtrag.\n\n",
+	"Backfilling %s - %s in %d chunks, %d of them are already completed.\nThe progress is saved to %s.\n\n": "Nachtrag von %s - %s in %d Abschnitten, %d davon sind bereits abgeschlossen.\nDer Fortschritt wird in %s gespeichert.\n\n",
+	"The backfill is already completed.":                                                                    "Der Nachtrag ist bereits abgeschlossen.",
+	"failed\n\n%v\n\nRun the same command again to resume the backfill.\n":                                  "fehlgeschlagen\n\n%v\n\nFühren Sie denselben Befehl erneut aus, um den Nachtrag fortzusetzen.\n",
+	"%d entries synced, %d incomplete\n":                                                                    "%d Einträge synchronisiert, %d unvollständig\n",
+	"\nRun the same command again to resume the backfill.":                                                  "\nFühren Sie denselben Befehl erneut aus, um den Nachtrag fortzusetzen.",
+	"\nDry run completed, %d worklog entries would be synced.\n":                                            "\nTestlauf abgeschlossen, %d Arbeitszeiteinträge würden synchronisiert.\n",
+	"\nSuccessfully backfilled %d worklog entries!\n":                                                       "\n%d Arbeitszeiteinträge erfolgreich nachgetragen!\n",
+
+	// Cache of the raw entries
+	"Cached %d raw entries.\n":                  "%d Roheinträge zwischengespeichert.\n",
+	"Using %d entries fetched from %s at %s.\n": "%d Einträge verwendet, abgerufen von %s am %s.\n",
+
+	// Purge of the storage
+	"No data to remove.":                            "Keine Daten zu entfernen.",
+	"\n%d objects would be removed.\n":              "\n%d Objekte würden entfernt.\n",
+	"\nRemove %d objects from the storage? [y/n]: ": "\n%d Objekte aus dem Speicher entfernen? [y/n]: ",
+	"Removed %d objects.\n":                         "%d Objekte entfernt.\n",
+
+	// Receipts
+	"receipt secret key and public key must be set": "Der geheime und der öffentliche Schlüssel der Belege müssen gesetzt sein",
+	"%s already exists":                             "%s existiert bereits",
+	"Generated receipt key %s.\n":                   "Belegschlüssel %s erzeugt.\n",
+	"receipt public key must be set":                "Der öffentliche Schlüssel der Belege muss gesetzt sein",
+	"Receipt is signed by key %s at %s.\n":          "Der Beleg ist mit dem Schlüssel %s am %s signiert.\n",
+	"Receipt is signed by key %s.\n":                "Der Beleg ist mit dem Schlüssel %s signiert.\n",
+	"Uploaded %d entries to %s for %s - %s.\n":      "%d Einträge nach %s für %s - %s hochgeladen.\n",
+
+	// Overtime
+	"Overtime of the period: %s, balance: %s\n": "Überstunden des Zeitraums: %s, Saldo: %s\n",
+	"No overtime tracked yet.":                  "Noch keine Überstunden erfasst.",
+	"Overtime balance":                          "Überstundensaldo",
+	"Expected":                                  "Soll",
+	"Actual":                                    "Ist",
+	"Overtime":                                  "Überstunden",
+	"Balance":                                   "Saldo",
+	"Monthly targets":                           "Monatliche Sollstunden",
+	"Month":                                     "Monat",
+	"Target":                                    "Soll",
+	"Difference":                                "Differenz",
+
+	// Recharges
+	"legal entities must be set":    "Die juristischen Personen müssen gesetzt sein",
+	"No billable time to recharge.": "Keine abrechenbare Zeit weiterzuverrechnen.",
+	"Recharges (%s - %s)":           "Weiterverrechnungen (%s - %s)",
+	"Legal entity":                  "Juristische Person",
+	"Client":                        "Kunde",
+	"Billable hours":                "Abrechenbare Stunden",
+	"Rate":                          "Satz",
+	"Amount":                        "Betrag",
+	"No legal entity recharges the clients: %s\n": "Keine juristische Person verrechnet die Kunden weiter: %s\n",
+
+	// Mapping suggestions
+	"similarity must be between 0 and 1":         "Die Ähnlichkeit muss zwischen 0 und 1 liegen",
+	"mapping file must be set to write mappings": "Die Zuordnungsdatei muss gesetzt sein, um Zuordnungen zu schreiben",
+	"No unmapped entries found.":                 "Keine nicht zugeordneten Einträge gefunden.",
+	"\n# %d entries, e.g. %q\n":                  "\n# %d Einträge, z. B. %q\n",
+	"\nNo mappings to write; fill the client, project or task of the suggestions manually.": "\nKeine Zuordnungen zu schreiben; füllen Sie Kunde, Projekt oder Aufgabe der Vorschläge manuell aus.",
+	"\nWrite %d mappings to %s? [y/n]: ":                                                    "\n%d Zuordnungen nach %s schreiben? [y/n]: ",
+	"Mappings written to %s\n":                                                              "Zuordnungen nach %s geschrieben\n",
+
+	// Service
+	"Service installed: %s\n": "Dienst installiert: %s\n",
+	"Service uninstalled":     "Dienst deinstalliert",
+	"Service %s\n":            "Dienst %s\n",
+
+	// Status
+	"No source or target configured.":       "Keine Quelle oder kein Ziel konfiguriert.",
+	"Status":                                "Status",
+	"Name":                                  "Name",
+	"Last successful sync":                  "Letzte erfolgreiche Synchronisation",
+	"Last uploaded":                         "Zuletzt hochgeladen",
+	"Staged":                                "Bereitgestellt",
+	"Credentials":                           "Zugangsdaten",
+	"Circuit breaker":                       "Schutzschalter",
+	"never":                                 "nie",
+	"not expiring":                          "läuft nicht ab",
+	"unknown expiry":                        "Ablauf unbekannt",
+	"expires %s":                            "läuft ab am %s",
+	"error: %s":                             "Fehler: %s",
+	"error: cannot obtain credentials (%s)": "Fehler: Zugangsdaten können nicht abgerufen werden (%s)",
+
+	// Validation of the flags
+	"sync source must be set":                                                             "Die Quelle der Synchronisation muss gesetzt sein",
+	"sync target must be set":                                                             "Das Ziel der Synchronisation muss gesetzt sein",
+	"sync source cannot match the target":                                                 "Die Quelle der Synchronisation darf nicht dem Ziel entsprechen",
+	"sync source cannot match the route targets":                                          "Die Quelle der Synchronisation darf keinem der Weiterleitungsziele entsprechen",
+	"route tag prefix must be set":                                                        "Das Präfix der Weiterleitungs-Tags muss gesetzt sein",
+	"\"%s\" route target is set multiple times\n":                                         "Das Weiterleitungsziel \"%s\" ist mehrfach gesetzt\n",
+	"\"%s\" source is set multiple times\n":                                               "Die Quelle \"%s\" ist mehrfach gesetzt\n",
+	"\"%s\" is not part of the supported sources %v\n":                                    "\"%s\" gehört nicht zu den unterstützten Quellen %v\n",
+	"\"%s\" is not part of the supported targets %v\n":                                    "\"%s\" gehört nicht zu den unterstützten Zielen %v\n",
+	"\"%s\" is not part of the supported storages %v\n":                                   "\"%s\" gehört nicht zu den unterstützten Speichern %v\n",
+	"\"%s\" is not part of the supported limit policies %v\n":                             "\"%s\" gehört nicht zu den unterstützten Limit-Richtlinien %v\n",
+	"verify sample must not be negative":                                                  "Die Stichprobengröße der Prüfung darf nicht negativ sein",
+	"\"%s\" is not part of the supported range ends %v\n":                                 "\"%s\" gehört nicht zu den unterstützten Zeitraumenden %v\n",
+	"\"%s\" is not part of the supported task extraction modes %v\n":                      "\"%s\" gehört nicht zu den unterstützten Modi der Aufgabenerkennung %v\n",
+	"\"%s\" is not part of the supported future entries policies %v\n":                    "\"%s\" gehört nicht zu den unterstützten Richtlinien für zukünftige Einträge %v\n",
+	"\"%s\" is not part of the supported round modes %v\n":                                "\"%s\" gehört nicht zu den unterstützten Rundungsarten %v\n",
+	"\"%s\" is not part of the supported duration formats %v\n":                           "\"%s\" gehört nicht zu den unterstützten Dauerformaten %v\n",
+	"\"%s\" is not part of the supported duration units %v\n":                             "\"%s\" gehört nicht zu den unterstützten Dauereinheiten %v\n",
+	"\"%s\" is not part of the supported duration strategies %v\n":                        "\"%s\" gehört nicht zu den unterstützten Strategien der Dauerermittlung %v\n",
+	"\"%s\" is not part of the supported rescuetime granularities %v\n":                   "\"%s\" gehört nicht zu den unterstützten RescueTime-Granularitäten %v\n",
+	"\"%s\" is not part of the pipeline stages %v\n":                                      "\"%s\" gehört nicht zu den Pipeline-Stufen %v\n",
+	"\"%s\" is not part of the purgeable data %v\n":                                       "\"%s\" gehört nicht zu den löschbaren Daten %v\n",
+	"\"%s\" is not part of the sortable columns %v\n":                                     "\"%s\" gehört nicht zu den sortierbaren Spalten %v\n",
+	"\"%s\" is not part of the hideable columns %v\n":                                     "\"%s\" gehört nicht zu den ausblendbaren Spalten %v\n",
+	"\"%s\" is not a valid holiday date in YYYY-MM-DD format\n":                           "\"%s\" ist kein gültiges Feiertagsdatum im Format JJJJ-MM-TT\n",
+	"\"%s\" is not a valid employment date in YYYY-MM-DD format\n":                        "\"%s\" ist kein gültiges Beschäftigungsdatum im Format JJJJ-MM-TT\n",
+	"the %s author strategy is not supported by the %s target":                            "Die Autorenstrategie %s wird vom Ziel %s nicht unterstützt",
+	"impersonation is not supported by the %s target":                                     "Das Handeln im Namen anderer Benutzer wird vom Ziel %s nicht unterstützt",
+	"audit log must be set to impersonate users":                                          "Das Audit-Log muss gesetzt sein, um im Namen anderer Benutzer zu handeln",
+	"tempo impersonate users must be set to impersonate users":                            "tempo impersonate users muss gesetzt sein, um im Namen anderer Benutzer zu handeln",
+	"hook timeout must be positive":                                                       "Das Zeitlimit des Hooks muss positiv sein",
+	"history must be enabled to sign the upload receipts":                                 "Der Verlauf muss aktiviert sein, um die Upload-Belege zu signieren",
+	"tempo max comment length must be positive":                                           "Die maximale Kommentarlänge von Tempo muss positiv sein",
+	"tempo issue comment min duration must not be negative":                               "Die Mindestdauer der Tempo-Vorgangskommentare darf nicht negativ sein",
+	"audit log max size must be positive":                                                 "Die maximale Größe des Audit-Logs muss positiv sein",
+	"audit log max backups must not be negative":                                          "Die maximale Anzahl der Audit-Log-Sicherungen darf nicht negativ sein",
+	"anomaly factor must be greater than 1, or 0 to disable the warnings":                 "Der Anomaliefaktor muss größer als 1 sein, oder 0, um die Warnungen zu deaktivieren",
+	"anomaly min days must be positive":                                                   "Die Mindestanzahl der Tage für Anomalien muss positiv sein",
+	"anomaly history days must be positive":                                               "Die Anzahl der Verlaufstage für Anomalien muss positiv sein",
+	"overtime daily duration must be positive":                                            "Die tägliche Sollarbeitszeit muss positiv sein",
+	"overtime employment end must not be before the start":                                "Das Ende der Beschäftigung darf nicht vor deren Beginn liegen",
+	"SQLite command must be set":                                                          "Der SQLite-Befehl muss gesetzt sein",
+	"S3 bucket must be set":                                                               "Der S3-Bucket muss gesetzt sein",
+	"S3 access key and secret key must be set":                                            "Der S3-Zugriffsschlüssel und der geheime Schlüssel müssen gesetzt sein",
+	"retention days must not be negative":                                                 "Die Aufbewahrungsdauer in Tagen darf nicht negativ sein",
+	"future tolerance must not be negative":                                               "Die Toleranz für zukünftige Einträge darf nicht negativ sein",
+	"round increment must not be negative":                                                "Das Rundungsintervall darf nicht negativ sein",
+	"absence duration must be positive":                                                   "Die Dauer der Abwesenheit muss positiv sein",
+	"expected run duration must not be negative":                                          "Die erwartete Laufzeit darf nicht negativ sein",
+	"fetch deadline must not be negative":                                                 "Die Frist des Abrufs darf nicht negativ sein",
+	"resolve deadline must not be negative":                                               "Die Frist der Auflösung darf nicht negativ sein",
+	"upload deadline must not be negative":                                                "Die Frist des Hochladens darf nicht negativ sein",
+	"%s of %s exceeded":                                                                   "%s von %s überschritten",
+	"cost centers must be set to split the csvfile by cost center":                        "Die Kostenstellen müssen gesetzt sein, um die CSV-Datei nach Kostenstellen aufzuteilen",
+	"csvfile decimal precision must be positive":                                          "Die Dezimalstellen der CSV-Datei müssen positiv sein",
+	"csvfile path must be set":                                                            "Der Pfad der CSV-Datei muss gesetzt sein",
+	"xlsxfile path must be set":                                                           "Der Pfad der XLSX-Datei muss gesetzt sein",
+	"icsfile path must be set":                                                            "Der Pfad der ICS-Datei muss gesetzt sein",
+	"jsonfile path must be set":                                                           "Der Pfad der JSON-Datei muss gesetzt sein",
+	"jira URL must be set":                                                                "Die Jira-URL muss gesetzt sein",
+	"jira username and password must be set":                                              "Der Jira-Benutzername und das Passwort müssen gesetzt sein",
+	"activitywatch url must be set":                                                       "Die ActivityWatch-URL muss gesetzt sein",
+	"activitywatch min duration must not be negative":                                     "Die Mindestdauer von ActivityWatch darf nicht negativ sein",
+	"activitywatch merge gap must not be negative":                                        "Die Zusammenführungslücke von ActivityWatch darf nicht negativ sein",
+	"bamboohr company must be set":                                                        "Das BambooHR-Unternehmen muss gesetzt sein",
+	"git command must be set":                                                             "Der Git-Befehl muss gesetzt sein",
+	"git repositories must be set":                                                        "Die Git-Repositorys müssen gesetzt sein",
+	"git assumed duration must be positive":                                               "Die angenommene Dauer der Git-Commits muss positiv sein",
+	"git max gap must be positive":                                                        "Die maximale Lücke zwischen Git-Commits muss positiv sein",
+	"github token must be set":                                                            "Das GitHub-Token muss gesetzt sein",
+	"github commit duration must be positive":                                             "Die Dauer der GitHub-Commits muss positiv sein",
+	"github review duration must be positive":                                             "Die Dauer der GitHub-Reviews muss positiv sein",
+	"github comment duration must be positive":                                            "Die Dauer der GitHub-Kommentare muss positiv sein",
+	"gitlab token must be set":                                                            "Das GitLab-Token muss gesetzt sein",
+	"googlecalendar client id must be set":                                                "Die Client-ID von Google Kalender muss gesetzt sein",
+	"googlecalendar refresh token must be set; run authorize-googlecalendar to obtain it": "Das Aktualisierungstoken von Google Kalender muss gesetzt sein; führen Sie authorize-googlecalendar aus, um es zu erhalten",
+	"googlecalendar calendars must be set":                                                "Die Kalender von Google Kalender müssen gesetzt sein",
+	"hamster database must be set":                                                        "Die Hamster-Datenbank muss gesetzt sein",
+	"hamster sqlite command must be set":                                                  "Der SQLite-Befehl von Hamster muss gesetzt sein",
+	"outlook client id must be set":                                                       "Die Client-ID von Outlook muss gesetzt sein",
+	"outlook refresh token must be set; run authorize-outlook to obtain it":               "Das Aktualisierungstoken von Outlook muss gesetzt sein; führen Sie authorize-outlook aus, um es zu erhalten",
+	"redmine url must be set":                                                             "Die URL von Redmine muss gesetzt sein",
+	"redmine api key must be set":                                                         "Der API-Schlüssel von Redmine muss gesetzt sein",
+	"rescuetime api key must be set":                                                      "Der API-Schlüssel von RescueTime muss gesetzt sein",
+	"timecamp token must be set":                                                          "Das TimeCamp-Token muss gesetzt sein",
+	"timewarrior command must be set":                                                     "Der Timewarrior-Befehl muss gesetzt sein",
+	"timewarrior unbillable tag must be set":                                              "Das Timewarrior-Tag für nicht abrechenbare Zeit muss gesetzt sein",
+	"timewarrior client tag regex must be set":                                            "Der reguläre Ausdruck der Timewarrior-Kunden-Tags muss gesetzt sein",
+	"timewarrior project tag regex must be set":                                           "Der reguläre Ausdruck der Timewarrior-Projekt-Tags muss gesetzt sein",
+	"wakatime api key must be set":                                                        "Der API-Schlüssel von WakaTime muss gesetzt sein",
+	"wakatime user must be set":                                                           "Der WakaTime-Benutzer muss gesetzt sein",
+	"watson frames file must be set":                                                      "Die Frames-Datei von Watson muss gesetzt sein",
+	"youtrack url must be set":                                                            "Die URL von YouTrack muss gesetzt sein",
+	"youtrack token must be set":                                                          "Das YouTrack-Token muss gesetzt sein",
+	"kimai url must be set":                                                               "Die URL von Kimai muss gesetzt sein",
+	"kimai token must be set":                                                             "Das Kimai-Token muss gesetzt sein",
+	"hubstaff token must be set":                                                          "Das Hubstaff-Token muss gesetzt sein",
+	"hubstaff organization must be set":                                                   "Die Organisation von Hubstaff muss gesetzt sein",
+	"\"%s\" is not part of the supported hubstaff idle modes %v\n":                        "\"%s\" gehört nicht zu den unterstützten Hubstaff-Leerlaufmodi %v\n",
+}
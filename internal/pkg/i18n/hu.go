@@ -0,0 +1,271 @@
+package i18n
+
+// hungarian is the Hungarian translation of the messages. The labels of the
+// timesheet avoid the characters missing from the standard PDF fonts, like
+// "ő" and "ű".
+var hungarian = map[string]string{
+	// Run of the sync
+	"Continue? [y/n]: ":                                            "Folytatja? [y/n]: ",
+	"User interruption. Aborting.":                                 "Felhasználói megszakítás. A művelet leáll.",
+	"Use the inferred columns? [y/n]: ":                            "Használja a felismert oszlopokat? [y/n]: ",
+	"Inferred columns of %s:\n\n  %s = [%s]\n\n":                   "A(z) %s felismert oszlopai:\n\n  %s = [%s]\n\n",
+	"Worklog entries (%s - %s)":                                    "Munkaidő-bejegyzések (%s - %s)",
+	"\nUploading worklog entries:\n\n":                             "\nMunkaidő-bejegyzések feltöltése:\n\n",
+	"\nSuccessfully uploaded %d worklog entries!\n":                "\n%d munkaidő-bejegyzés sikeresen feltöltve!\n",
+	"\nFailed to upload %d worklog entries!\n\n":                   "\n%d munkaidő-bejegyzés feltöltése sikertelen!\n\n",
+	"\nFailed to write the uploaded entries to the calendar: %v\n": "\nA feltöltött bejegyzések naptárba írása sikertelen: %v\n",
+	"    review: %s\n":                                             "    ellenőrzés: %s\n",
+	"The %s target does not support payload preview.\n\n":          "A(z) %s cél nem támogatja a küldött adatok előnézetét.\n\n",
+	"Payloads of the %s target:\n\n":                               "A(z) %s cél küldött adatai:\n\n",
+	"Continuing without the failed sources:\n%v\n\n":               "Folytatás a sikertelen források nélkül:\n%v\n\n",
+	"Fetching from %s failed (%s), retrying in %s...\n":            "A(z) %s lekérdezése sikertelen (%s), újrapróbálás %s múlva...\n",
+	"Skipped %d invalid rows:\n":                                   "%d érvénytelen sor kihagyva:\n",
+	"The invalid rows are written to %s\n":                         "Az érvénytelen sorok a(z) %s fájlba kerültek\n",
+	"Timesheet written to %s\n":                                    "A jelenléti ív a(z) %s fájlba került\n",
+
+	// Verification of the uploads
+	"Upload verification": "Feltöltés ellenőrzése",
+	"Worklog":             "Munkanapló",
+	"Field":               "Mező",
+	"Sent":                "Elküldve",
+	"Received":            "Fogadva",
+	"\nVerified %d of %d sampled worklogs against the target.\n": "\n%d / %d mintavételezett munkanapló egyezik a céllal.\n",
+	"%d sampled worklogs do not match the uploaded entries":      "%d mintavételezett munkanapló nem egyezik a feltöltött bejegyzésekkel",
+	"target does not support verifying the uploaded worklogs":    "A cél nem támogatja a feltöltött munkanaplók ellenőrzését",
+
+	// Table of the entries
+	"task":             "feladat",
+	"summary":          "leírás",
+	"project":          "projekt",
+	"client":           "ügyfél",
+	"start":            "kezdés",
+	"end":              "befejezés",
+	"billable":         "számlázható",
+	"unbillable":       "nem számlázható",
+	"attributes":       "attribútumok",
+	"total time spent": "összes ráfordított idő",
+	"You have %d complete and %d incomplete items. Before proceeding, please double-check them.\n": "%d teljes és %d hiányos bejegyzés van. Folytatás előtt ellenőrizze őket.\n",
+	"Entry provenance": "A bejegyzések eredete",
+	"Provenance":       "Eredet",
+
+	// Timesheet
+	"Timesheet":          "Jelenléti ív",
+	"Employee:":          "Munkavállaló:",
+	"Client:":            "Ügyfél:",
+	"Period:":            "Periódus:",
+	"Date":               "Dátum",
+	"Project":            "Projekt",
+	"Task":               "Feladat",
+	"Summary":            "Leírás",
+	"Hours":              "Óra",
+	"Start":              "Kezdés",
+	"End":                "Befejezés",
+	"Total":              "Összesen",
+	"Date:":              "Dátum:",
+	"Employee signature": "Munkavállaló aláírása",
+	"Client signature":   "Ügyfél aláírása",
+	"Page %d of %d":      "%d. oldal / %d",
+
+	// Quick add and timer
+	"the entry is incomplete, set its project and client or stage it":                   "A bejegyzés hiányos, adja meg a projektjét és az ügyfelét, vagy tegye várólistára",
+	"Staged entry #%s: %s %s %s\nThe entry is uploaded by the next sync.\n":             "#%s bejegyzés várólistára került: %s %s %s\nA bejegyzést a következő szinkronizálás tölti fel.\n",
+	"the timer of %s is already running since %s, stop it first":                        "A(z) %s időmérője már fut %s óta, előbb állítsa le",
+	"Started the timer of %s at %s.\n":                                                  "A(z) %s időmérője elindult: %s.\n",
+	"no timer is running":                                                               "Nem fut időmérő",
+	"Stopped the timer of %s after %s.\nStaged entry #%s, uploaded by the next sync.\n": "A(z) %s időmérője leállt %s után.\nA #%s bejegyzés várólistára került, a következő szinkronizálás tölti fel.\n",
+
+	// Backfill
+	"backfill start and end must be set":                                              "A visszamenőleges feltöltés kezdetét és végét meg kell adni",
+	"backfill chunk days must be positive":                                            "A visszamenőleges feltöltés szakaszainak napjai pozitívak kell legyenek",
+	"backfill chunk delay must not be negative":                                       "A visszamenőleges feltöltés szakaszai közötti várakozás nem lehet negatív",
+	"Fetching chunk %d of %d for validation...\n":                                     "%d. szakasz lekérése ellenőrzéshez (összesen %d)...\n",
+	"\nValidated %d issues, %d accounts and %d attributes against the %s target.\n\n": "\n%d feladat, %d fiók és %d attribútum ellenőrizve a(z) %s célon.\n\n",
+	"Remote validation problems":                                                      "A célon végzett ellenőrzés problémái",
+	"Kind":                                                                            "Fajta",
+	"Value":                                                                           "Érték",
+	"Entries":                                                                         "Bejegyzések",
+	"Problem":                                                                         "Probléma",
+	"GO: the target accepts every issue, account and attribute.\n\n":                                        "GO: a cél minden feladatot, fiókot és attribútumot elfogad.\n\n",
+	"NO-GO: the target would reject %d values, fix them before the backfill.\n\n":                           "NO-GO: a cél %d értéket elutasítana, javítsa ki őket a visszamenőleges feltöltés előtt.\n\n",
+	"Backfilling %s - %s in %d chunks, %d of them are already completed.\nThe progress is saved to %s.\n\n": "%s - %s visszamenőleges feltöltése %d szakaszban, ebből %d már kész.\nAz előrehaladás a(z) %s fájlba kerül.\n\n",
+	"The backfill is already completed.":                                                                    "A visszamenőleges feltöltés már kész.",
+	"failed\n\n%v\n\nRun the same command again to resume the backfill.\n":                                  "sikertelen\n\n%v\n\nFuttassa újra ugyanazt a parancsot a visszamenőleges feltöltés folytatásához.\n",
+	"%d entries synced, %d incomplete\n":                                                                    "%d bejegyzés szinkronizálva, %d hiányos\n",
+	"\nRun the same command again to resume the backfill.":                                                  "\nFuttassa újra ugyanazt a parancsot a visszamenőleges feltöltés folytatásához.",
+	"\nDry run completed, %d worklog entries would be synced.\n":                                            "\nA próbafuttatás kész, %d munkanapló-bejegyzés szinkronizálódna.\n",
+	"\nSuccessfully backfilled %d worklog entries!\n":                                                       "\n%d munkanapló-bejegyzés sikeresen visszamenőleg feltöltve!\n",
+
+	// Cache of the raw entries
+	"Cached %d raw entries.\n":                  "%d nyers bejegyzés gyorsítótárazva.\n",
+	"Using %d entries fetched from %s at %s.\n": "%d bejegyzés használata, lekérve innen: %s, ekkor: %s.\n",
+
+	// Purge of the storage
+	"No data to remove.":                            "Nincs eltávolítandó adat.",
+	"\n%d objects would be removed.\n":              "\n%d objektum kerülne eltávolításra.\n",
+	"\nRemove %d objects from the storage? [y/n]: ": "\nEltávolít %d objektumot a tárolóból? [y/n]: ",
+	"Removed %d objects.\n":                         "%d objektum eltávolítva.\n",
+
+	// Receipts
+	"receipt secret key and public key must be set": "A nyugták titkos és nyilvános kulcsát meg kell adni",
+	"%s already exists":                             "%s már létezik",
+	"Generated receipt key %s.\n":                   "A(z) %s nyugtakulcs létrehozva.\n",
+	"receipt public key must be set":                "A nyugták nyilvános kulcsát meg kell adni",
+	"Receipt is signed by key %s at %s.\n":          "A nyugtát a(z) %s kulcs írta alá ekkor: %s.\n",
+	"Receipt is signed by key %s.\n":                "A nyugtát a(z) %s kulcs írta alá.\n",
+	"Uploaded %d entries to %s for %s - %s.\n":      "%d bejegyzés feltöltve ide: %s, erre az időszakra: %s - %s.\n",
+
+	// Overtime
+	"Overtime of the period: %s, balance: %s\n": "Az időszak túlórája: %s, egyenleg: %s\n",
+	"No overtime tracked yet.":                  "Még nincs rögzített túlóra.",
+	"Overtime balance":                          "Túlóra-egyenleg",
+	"Expected":                                  "Elvárt",
+	"Actual":                                    "Tényleges",
+	"Overtime":                                  "Túlóra",
+	"Balance":                                   "Egyenleg",
+	"Monthly targets":                           "Havi célórák",
+	"Month":                                     "Hónap",
+	"Target":                                    "Cél",
+	"Difference":                                "Különbség",
+
+	// Recharges
+	"legal entities must be set":    "A jogi személyeket meg kell adni",
+	"No billable time to recharge.": "Nincs továbbszámlázandó számlázható idő.",
+	"Recharges (%s - %s)":           "Továbbszámlázások (%s - %s)",
+	"Legal entity":                  "Jogi személy",
+	"Client":                        "Ügyfél",
+	"Billable hours":                "Számlázható órák",
+	"Rate":                          "Díj",
+	"Amount":                        "Összeg",
+	"No legal entity recharges the clients: %s\n": "Egy jogi személy sem számlázza tovább az ügyfeleket: %s\n",
+
+	// Mapping suggestions
+	"similarity must be between 0 and 1":         "A hasonlóságnak 0 és 1 között kell lennie",
+	"mapping file must be set to write mappings": "A leképezések írásához meg kell adni a leképezési fájlt",
+	"No unmapped entries found.":                 "Nem található leképezetlen bejegyzés.",
+	"\n# %d entries, e.g. %q\n":                  "\n# %d bejegyzés, pl. %q\n",
+	"\nNo mappings to write; fill the client, project or task of the suggestions manually.": "\nNincs írandó leképezés; töltse ki kézzel a javaslatok ügyfelét, projektjét vagy feladatát.",
+	"\nWrite %d mappings to %s? [y/n]: ":                                                    "\n%d leképezés írása ide: %s? [y/n]: ",
+	"Mappings written to %s\n":                                                              "A leképezések a(z) %s fájlba kerültek\n",
+
+	// Service
+	"Service installed: %s\n": "Szolgáltatás telepítve: %s\n",
+	"Service uninstalled":     "Szolgáltatás eltávolítva",
+	"Service %s\n":            "Szolgáltatás: %s\n",
+
+	// Status
+	"No source or target configured.":       "Nincs beállított forrás vagy cél.",
+	"Status":                                "Állapot",
+	"Name":                                  "Név",
+	"Last successful sync":                  "Utolsó sikeres szinkronizálás",
+	"Last uploaded":                         "Utoljára feltöltve",
+	"Staged":                                "Várólistán",
+	"Credentials":                           "Hitelesítő adatok",
+	"Circuit breaker":                       "Megszakító",
+	"never":                                 "soha",
+	"not expiring":                          "nem jár le",
+	"unknown expiry":                        "ismeretlen lejárat",
+	"expires %s":                            "lejár: %s",
+	"error: %s":                             "hiba: %s",
+	"error: cannot obtain credentials (%s)": "hiba: a hitelesítő adatok nem szerezhetők be (%s)",
+
+	// Validation of the flags
+	"sync source must be set":                                                             "A szinkronizálás forrását meg kell adni",
+	"sync target must be set":                                                             "A szinkronizálás célját meg kell adni",
+	"sync source cannot match the target":                                                 "A szinkronizálás forrása nem egyezhet meg a céllal",
+	"sync source cannot match the route targets":                                          "A szinkronizálás forrása nem egyezhet meg a továbbítási célokkal",
+	"route tag prefix must be set":                                                        "A továbbítási címkék előtagját meg kell adni",
+	"\"%s\" route target is set multiple times\n":                                         "A(z) \"%s\" továbbítási cél többször van megadva\n",
+	"\"%s\" source is set multiple times\n":                                               "A(z) \"%s\" forrás többször van megadva\n",
+	"\"%s\" is not part of the supported sources %v\n":                                    "\"%s\" nem szerepel a támogatott források között %v\n",
+	"\"%s\" is not part of the supported targets %v\n":                                    "\"%s\" nem szerepel a támogatott célok között %v\n",
+	"\"%s\" is not part of the supported storages %v\n":                                   "\"%s\" nem szerepel a támogatott tárolók között %v\n",
+	"\"%s\" is not part of the supported limit policies %v\n":                             "\"%s\" nem szerepel a támogatott korlátozási szabályok között %v\n",
+	"verify sample must not be negative":                                                  "Az ellenőrzési minta mérete nem lehet negatív",
+	"\"%s\" is not part of the supported range ends %v\n":                                 "\"%s\" nem szerepel a támogatott időszakvégek között %v\n",
+	"\"%s\" is not part of the supported task extraction modes %v\n":                      "\"%s\" nem szerepel a támogatott feladatfelismerési módok között %v\n",
+	"\"%s\" is not part of the supported future entries policies %v\n":                    "\"%s\" nem szerepel a jövőbeli bejegyzésekre vonatkozó támogatott szabályok között %v\n",
+	"\"%s\" is not part of the supported round modes %v\n":                                "\"%s\" nem szerepel a támogatott kerekítési módok között %v\n",
+	"\"%s\" is not part of the supported duration formats %v\n":                           "\"%s\" nem szerepel a támogatott időtartam-formátumok között %v\n",
+	"\"%s\" is not part of the supported duration units %v\n":                             "\"%s\" nem szerepel a támogatott időtartam-egységek között %v\n",
+	"\"%s\" is not part of the supported duration strategies %v\n":                        "\"%s\" nem szerepel a támogatott időtartam-stratégiák között %v\n",
+	"\"%s\" is not part of the supported rescuetime granularities %v\n":                   "\"%s\" nem szerepel a támogatott RescueTime-részletességek között %v\n",
+	"\"%s\" is not part of the pipeline stages %v\n":                                      "\"%s\" nem szerepel a feldolgozási lépések között %v\n",
+	"\"%s\" is not part of the purgeable data %v\n":                                       "\"%s\" nem szerepel a törölhető adatok között %v\n",
+	"\"%s\" is not part of the sortable columns %v\n":                                     "\"%s\" nem szerepel a rendezhető oszlopok között %v\n",
+	"\"%s\" is not part of the hideable columns %v\n":                                     "\"%s\" nem szerepel az elrejthető oszlopok között %v\n",
+	"\"%s\" is not a valid holiday date in YYYY-MM-DD format\n":                           "\"%s\" nem érvényes ünnepnap-dátum ÉÉÉÉ-HH-NN formátumban\n",
+	"\"%s\" is not a valid employment date in YYYY-MM-DD format\n":                        "\"%s\" nem érvényes foglalkoztatási dátum ÉÉÉÉ-HH-NN formátumban\n",
+	"the %s author strategy is not supported by the %s target":                            "A(z) %s szerzőstratégiát a(z) %s cél nem támogatja",
+	"impersonation is not supported by the %s target":                                     "A más felhasználók nevében történő feltöltést a(z) %s cél nem támogatja",
+	"audit log must be set to impersonate users":                                          "Más felhasználók nevében történő feltöltéshez meg kell adni az auditnaplót",
+	"tempo impersonate users must be set to impersonate users":                            "Más felhasználók nevében történő feltöltéshez be kell állítani a tempo impersonate users opciót",
+	"hook timeout must be positive":                                                       "A hook időkorlátjának pozitívnak kell lennie",
+	"history must be enabled to sign the upload receipts":                                 "A feltöltési nyugták aláírásához engedélyezni kell az előzményeket",
+	"tempo max comment length must be positive":                                           "A Tempo megjegyzések maximális hosszának pozitívnak kell lennie",
+	"tempo issue comment min duration must not be negative":                               "A Tempo feladatmegjegyzések minimális időtartama nem lehet negatív",
+	"audit log max size must be positive":                                                 "Az auditnapló maximális méretének pozitívnak kell lennie",
+	"audit log max backups must not be negative":                                          "Az auditnapló biztonsági másolatainak maximális száma nem lehet negatív",
+	"anomaly factor must be greater than 1, or 0 to disable the warnings":                 "Az anomáliatényezőnek 1-nél nagyobbnak kell lennie, vagy 0-nak a figyelmeztetések kikapcsolásához",
+	"anomaly min days must be positive":                                                   "Az anomáliák minimális napszámának pozitívnak kell lennie",
+	"anomaly history days must be positive":                                               "Az anomáliák előzménynapjainak számának pozitívnak kell lennie",
+	"overtime daily duration must be positive":                                            "A napi munkaidőnek pozitívnak kell lennie",
+	"overtime employment end must not be before the start":                                "A foglalkoztatás vége nem lehet korábbi a kezdeténél",
+	"SQLite command must be set":                                                          "Az SQLite parancsot meg kell adni",
+	"S3 bucket must be set":                                                               "Az S3 bucketet meg kell adni",
+	"S3 access key and secret key must be set":                                            "Az S3 hozzáférési kulcsot és titkos kulcsot meg kell adni",
+	"retention days must not be negative":                                                 "A megőrzési napok száma nem lehet negatív",
+	"future tolerance must not be negative":                                               "A jövőbeli bejegyzések tűréshatára nem lehet negatív",
+	"round increment must not be negative":                                                "A kerekítési lépték nem lehet negatív",
+	"absence duration must be positive":                                                   "A távollét időtartamának pozitívnak kell lennie",
+	"expected run duration must not be negative":                                          "A várt futási idő nem lehet negatív",
+	"fetch deadline must not be negative":                                                 "A lekérdezés határideje nem lehet negatív",
+	"resolve deadline must not be negative":                                               "A feloldás határideje nem lehet negatív",
+	"upload deadline must not be negative":                                                "A feltöltés határideje nem lehet negatív",
+	"%s of %s exceeded":                                                                   "%s (%s) túllépve",
+	"cost centers must be set to split the csvfile by cost center":                        "A CSV-fájl költséghelyenkénti felosztásához meg kell adni a költséghelyeket",
+	"csvfile decimal precision must be positive":                                          "A CSV-fájl tizedesjegyeinek számának pozitívnak kell lennie",
+	"csvfile path must be set":                                                            "A CSV-fájl elérési útját meg kell adni",
+	"xlsxfile path must be set":                                                           "Az XLSX-fájl elérési útját meg kell adni",
+	"icsfile path must be set":                                                            "Az ICS-fájl elérési útját meg kell adni",
+	"jsonfile path must be set":                                                           "A JSON-fájl elérési útját meg kell adni",
+	"jira URL must be set":                                                                "A Jira URL-t meg kell adni",
+	"jira username and password must be set":                                              "A Jira felhasználónevet és jelszót meg kell adni",
+	"activitywatch url must be set":                                                       "Az ActivityWatch URL-t meg kell adni",
+	"activitywatch min duration must not be negative":                                     "Az ActivityWatch minimális időtartama nem lehet negatív",
+	"activitywatch merge gap must not be negative":                                        "Az ActivityWatch összevonási szünete nem lehet negatív",
+	"bamboohr company must be set":                                                        "A BambooHR céget meg kell adni",
+	"git command must be set":                                                             "A git parancsot meg kell adni",
+	"git repositories must be set":                                                        "A git tárolókat meg kell adni",
+	"git assumed duration must be positive":                                               "A git commitok feltételezett időtartamának pozitívnak kell lennie",
+	"git max gap must be positive":                                                        "A git commitok közötti maximális szünetnek pozitívnak kell lennie",
+	"github token must be set":                                                            "A GitHub tokent meg kell adni",
+	"github commit duration must be positive":                                             "A GitHub commitok időtartamának pozitívnak kell lennie",
+	"github review duration must be positive":                                             "A GitHub review-k időtartamának pozitívnak kell lennie",
+	"github comment duration must be positive":                                            "A GitHub hozzászólások időtartamának pozitívnak kell lennie",
+	"gitlab token must be set":                                                            "A GitLab tokent meg kell adni",
+	"googlecalendar client id must be set":                                                "A Google Naptár kliensazonosítóját meg kell adni",
+	"googlecalendar refresh token must be set; run authorize-googlecalendar to obtain it": "A Google Naptár frissítési tokenjét meg kell adni; a megszerzéséhez futtassa az authorize-googlecalendar parancsot",
+	"googlecalendar calendars must be set":                                                "A Google Naptár naptárait meg kell adni",
+	"hamster database must be set":                                                        "A Hamster adatbázist meg kell adni",
+	"hamster sqlite command must be set":                                                  "A Hamster SQLite parancsát meg kell adni",
+	"outlook client id must be set":                                                       "Az Outlook kliensazonosítóját meg kell adni",
+	"outlook refresh token must be set; run authorize-outlook to obtain it":               "Az Outlook frissítési tokenjét meg kell adni; a megszerzéséhez futtassa az authorize-outlook parancsot",
+	"redmine url must be set":                                                             "A Redmine URL-jét meg kell adni",
+	"redmine api key must be set":                                                         "A Redmine API-kulcsát meg kell adni",
+	"rescuetime api key must be set":                                                      "A RescueTime API-kulcsát meg kell adni",
+	"timecamp token must be set":                                                          "A TimeCamp tokent meg kell adni",
+	"timewarrior command must be set":                                                     "A Timewarrior parancsot meg kell adni",
+	"timewarrior unbillable tag must be set":                                              "A Timewarrior nem számlázható címkéjét meg kell adni",
+	"timewarrior client tag regex must be set":                                            "A Timewarrior ügyfélcímkéinek reguláris kifejezését meg kell adni",
+	"timewarrior project tag regex must be set":                                           "A Timewarrior projektcímkéinek reguláris kifejezését meg kell adni",
+	"wakatime api key must be set":                                                        "A WakaTime API-kulcsát meg kell adni",
+	"wakatime user must be set":                                                           "A WakaTime felhasználót meg kell adni",
+	"watson frames file must be set":                                                      "A Watson frames fájlt meg kell adni",
+	"youtrack url must be set":                                                            "A YouTrack URL-jét meg kell adni",
+	"youtrack token must be set":                                                          "A YouTrack tokent meg kell adni",
+	"kimai url must be set":                                                               "A Kimai URL-jét meg kell adni",
+	"kimai token must be set":                                                             "A Kimai tokent meg kell adni",
+	"hubstaff token must be set":                                                          "A Hubstaff tokent meg kell adni",
+	"hubstaff organization must be set":                                                   "A Hubstaff szervezetet meg kell adni",
+	"\"%s\" is not part of the supported hubstaff idle modes %v\n":                        "\"%s\" nem szerepel a támogatott Hubstaff-tétlenségi módok között %v\n",
+}
@@ -0,0 +1,151 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+const (
+	// DefaultLanguage is the language of the messages if no language is set
+	// or detected. The messages are written in English in the source code,
+	// hence the default language has no translations.
+	DefaultLanguage string = "en"
+)
+
+// ErrUnknownLanguage returns when the requested language is not supported.
+var ErrUnknownLanguage = errors.New("unknown language")
+
+// translations maps the supported languages to their translations. The
+// translations are keyed by the English message, which is used as format
+// string if a message has no translation.
+var translations = map[string]map[string]string{
+	DefaultLanguage: nil,
+	"de":            german,
+	"hu":            hungarian,
+}
+
+var messageCatalog = newCatalog()
+
+// newCatalog returns the catalog of the translations.
+func newCatalog() catalog.Catalog {
+	builder := catalog.NewBuilder(catalog.Fallback(language.English))
+
+	for name, messages := range translations {
+		tag := language.MustParse(name)
+
+		for key, msg := range messages {
+			if err := builder.SetString(tag, key, msg); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return builder
+}
+
+// Translator translates the user-facing messages to a language. The messages
+// of the default language are formatted by fmt, so their numbers are not
+// grouped.
+type Translator struct {
+	language string
+	printer  *message.Printer
+}
+
+// Language returns the name of the language the messages are translated to.
+func (t *Translator) Language() string {
+	return t.language
+}
+
+// Sprintf translates the message and formats it according to the format
+// specifier, like fmt.Sprintf. The message is formatted as is, if it has no
+// translation.
+func (t *Translator) Sprintf(key string, args ...interface{}) string {
+	if t.printer == nil {
+		return fmt.Sprintf(key, args...)
+	}
+
+	return t.printer.Sprintf(key, args...)
+}
+
+// Default returns the translator of the default language.
+func Default() *Translator {
+	translator, _ := Get(DefaultLanguage)
+	return translator
+}
+
+// Languages returns the names of the supported languages in alphabetical
+// order.
+func Languages() []string {
+	var names []string
+	for name := range translations {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// baseLanguage returns the language part of the name, like "de" for "de-DE",
+// "de_DE" or "de_DE.UTF-8".
+func baseLanguage(name string) string {
+	if i := strings.IndexAny(name, "-_.@"); i >= 0 {
+		name = name[:i]
+	}
+
+	return strings.ToLower(name)
+}
+
+// isSupported returns true if the language has translations or it is the
+// default language.
+func isSupported(lang string) bool {
+	_, ok := translations[lang]
+	return ok
+}
+
+// Get returns the translator of the language by its name. The name may
+// contain the region and encoding too, like "de_DE.UTF-8". If the name is
+// empty, the translator of the default language returns.
+func Get(name string) (*Translator, error) {
+	if name == "" {
+		name = DefaultLanguage
+	}
+
+	lang := baseLanguage(name)
+	if !isSupported(lang) {
+		return nil, fmt.Errorf("%v: %s", ErrUnknownLanguage, name)
+	}
+
+	translator := &Translator{language: lang}
+	if lang != DefaultLanguage {
+		translator.printer = message.NewPrinter(language.MustParse(lang), message.Catalog(messageCatalog))
+	}
+
+	return translator, nil
+}
+
+// Detect returns the language set by the LC_ALL, LC_MESSAGES and LANG
+// environment variables, checked in this order. If the language is not
+// supported or not set, the default language returns.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if lang := baseLanguage(value); isSupported(lang) {
+			return lang
+		}
+
+		return DefaultLanguage
+	}
+
+	return DefaultLanguage
+}
@@ -0,0 +1,73 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	translator, err := i18n.Get("de_DE.UTF-8")
+	require.Nil(t, err)
+	require.Equal(t, "de", translator.Language())
+
+	translator, err = i18n.Get("HU")
+	require.Nil(t, err)
+	require.Equal(t, "hu", translator.Language())
+
+	translator, err = i18n.Get("")
+	require.Nil(t, err)
+	require.Equal(t, i18n.DefaultLanguage, translator.Language())
+
+	_, err = i18n.Get("xx")
+	require.ErrorContains(t, err, i18n.ErrUnknownLanguage.Error())
+}
+
+func TestTranslator_Sprintf(t *testing.T) {
+	tests := []struct {
+		language string
+		expected string
+	}{
+		{language: "en", expected: "Page 1 of 2"},
+		{language: "de", expected: "Seite 1 von 2"},
+		{language: "hu", expected: "1. oldal / 2"},
+	}
+
+	for _, test := range tests {
+		translator, err := i18n.Get(test.language)
+		require.Nil(t, err)
+		require.Equal(t, test.expected, translator.Sprintf("Page %d of %d", 1, 2))
+	}
+}
+
+func TestTranslator_SprintfUntranslated(t *testing.T) {
+	translator, err := i18n.Get("de")
+	require.Nil(t, err)
+
+	require.Equal(t, "not translated: 42", translator.Sprintf("not translated: %d", 42))
+}
+
+func TestTranslator_SprintfDefaultLanguage(t *testing.T) {
+	// The numbers of the default language are not grouped
+	require.Equal(t, "\nSuccessfully uploaded 1234 worklog entries!\n", i18n.Default().Sprintf("\nSuccessfully uploaded %d worklog entries!\n", 1234))
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "hu_HU.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	require.Equal(t, "hu", i18n.Detect())
+
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	require.Equal(t, i18n.DefaultLanguage, i18n.Detect())
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	require.Equal(t, i18n.DefaultLanguage, i18n.Detect())
+}
+
+func TestLanguages(t *testing.T) {
+	require.Equal(t, []string{"de", "en", "hu"}, i18n.Languages())
+}
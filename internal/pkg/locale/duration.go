@@ -0,0 +1,198 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrInvalidDuration returns when a duration cannot be parsed.
+	ErrInvalidDuration = errors.New("invalid duration")
+	// ErrInvalidNumber returns when a number cannot be parsed.
+	ErrInvalidNumber = errors.New("invalid number")
+)
+
+// durationUnits maps the accepted unit names, including the names used by the
+// supported locales, to their durations.
+var durationUnits = map[string]time.Duration{
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"std":     time.Hour,
+	"stunde":  time.Hour,
+	"stunden": time.Hour,
+	"heure":   time.Hour,
+	"heures":  time.Hour,
+	"ó":       time.Hour,
+	"óra":     time.Hour,
+	"u":       time.Hour,
+	"uur":     time.Hour,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"minuten": time.Minute,
+	"p":       time.Minute,
+	"perc":    time.Minute,
+	"s":       time.Second,
+	"sec":     time.Second,
+	"secs":    time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"sek":     time.Second,
+	"mp":      time.Second,
+}
+
+// ParseNumber parses a decimal number written using the locale's separators,
+// like "1.234,5" for the "de-DE" locale. If the number contains only one
+// separator, it is used as decimal separator regardless of the locale, so
+// "1.5" and "1,5" are parsed the same way.
+func (l *Locale) ParseNumber(value string) (float64, error) {
+	number := strings.TrimSpace(value)
+
+	if strings.Contains(number, ".") && strings.Contains(number, ",") {
+		if l.GroupSeparator != "" {
+			number = strings.ReplaceAll(number, l.GroupSeparator, "")
+		}
+
+		number = strings.ReplaceAll(number, l.DecimalSeparator, ".")
+	} else {
+		number = strings.ReplaceAll(number, ",", ".")
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || strings.Count(number, ".") > 1 || parsed < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, value)
+	}
+
+	return parsed, nil
+}
+
+// parseClock parses the durations given as "h:mm" or "h:mm:ss".
+func parseClock(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidDuration
+	}
+
+	var duration time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+
+	for i, part := range parts {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || number < 0 || (i > 0 && (number > 59 || len(strings.TrimSpace(part)) != 2)) {
+			return 0, ErrInvalidDuration
+		}
+
+		duration += time.Duration(number) * units[i]
+	}
+
+	return duration, nil
+}
+
+// durationComponent represents a number and its unit in a duration, like
+// "1,5h" or "30 min".
+type durationComponent struct {
+	number string
+	unit   string
+}
+
+// splitDuration splits the duration into its components.
+func splitDuration(value string) ([]durationComponent, error) {
+	var components []durationComponent
+	runes := []rune(value)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		var component durationComponent
+
+		start := i
+		for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == ',') {
+			i++
+		}
+
+		if start == i {
+			return nil, ErrInvalidDuration
+		}
+
+		component.number = string(runes[start:i])
+
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+
+		start = i
+		for i < len(runes) && unicode.IsLetter(runes[i]) {
+			i++
+		}
+
+		component.unit = string(runes[start:i])
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// ParseDuration parses the durations written by humans or exported by other
+// tools. The following formats are accepted:
+//
+//   - clock format, like "1:30" or "1:30:15"
+//   - decimal hours, like "1.5" or "1,5", using the locale's separators
+//   - numbers with units, like "1.5h", "90m", "1h30m", "1h 30min" or "1,5 Std"
+//   - hours followed by minutes without unit, like "1h30"
+//
+// Negative durations are not accepted.
+func (l *Locale) ParseDuration(value string) (time.Duration, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(value))
+	if trimmed == "" {
+		return 0, fmt.Errorf("%w: empty value", ErrInvalidDuration)
+	}
+
+	if strings.Contains(trimmed, ":") {
+		duration, err := parseClock(trimmed)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, value)
+		}
+
+		return duration, nil
+	}
+
+	components, err := splitDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, value)
+	}
+
+	var duration time.Duration
+	for i, component := range components {
+		number, err := l.ParseNumber(component.number)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, value)
+		}
+
+		unit, ok := durationUnits[component.unit]
+
+		switch {
+		case component.unit == "" && len(components) == 1:
+			unit = time.Hour
+		case component.unit == "" && i == len(components)-1 && durationUnits[components[i-1].unit] == time.Hour:
+			unit = time.Minute
+		case !ok:
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, value)
+		}
+
+		duration += time.Duration(number * float64(unit))
+	}
+
+	return duration.Round(time.Second), nil
+}
@@ -0,0 +1,205 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultName is the name of the default locale. The default locale uses
+	// ISO 8601 dates and a decimal point without digit grouping, so it is
+	// safe to process by machines.
+	DefaultName string = "iso"
+)
+
+// ErrUnknownLocale returns when the requested locale is not supported.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locale represents the formatting rules of numbers and dates used by the
+// reports and exports.
+type Locale struct {
+	// Name is the BCP 47 language tag of the locale, like "de-DE".
+	Name string
+	// DecimalSeparator separates the integer and fractional part of numbers.
+	DecimalSeparator string
+	// GroupSeparator separates the thousands of the integer part. If empty,
+	// digits are not grouped.
+	GroupSeparator string
+	// DateFormat is the Go layout of dates.
+	DateFormat string
+	// DateTimeFormat is the Go layout of dates with time of the day.
+	DateTimeFormat string
+	// TimeFormat is the Go layout of the time of the day, without seconds.
+	TimeFormat string
+	// FirstDayOfWeek is the day the weeks are starting with.
+	FirstDayOfWeek time.Weekday
+}
+
+var locales = map[string]*Locale{
+	DefaultName: {
+		Name:             DefaultName,
+		DecimalSeparator: ".",
+		DateFormat:       "2006-01-02",
+		DateTimeFormat:   "2006-01-02 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+	"en-US": {
+		Name:             "en-US",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		DateFormat:       "01/02/2006",
+		DateTimeFormat:   "01/02/2006 3:04:05 PM",
+		TimeFormat:       "3:04 PM",
+		FirstDayOfWeek:   time.Sunday,
+	},
+	"en-GB": {
+		Name:             "en-GB",
+		DecimalSeparator: ".",
+		GroupSeparator:   ",",
+		DateFormat:       "02/01/2006",
+		DateTimeFormat:   "02/01/2006 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+	"de-DE": {
+		Name:             "de-DE",
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		DateFormat:       "02.01.2006",
+		DateTimeFormat:   "02.01.2006 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+	"fr-FR": {
+		Name:             "fr-FR",
+		DecimalSeparator: ",",
+		GroupSeparator:   " ",
+		DateFormat:       "02/01/2006",
+		DateTimeFormat:   "02/01/2006 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+	"hu-HU": {
+		Name:             "hu-HU",
+		DecimalSeparator: ",",
+		GroupSeparator:   " ",
+		DateFormat:       "2006. 01. 02.",
+		DateTimeFormat:   "2006. 01. 02. 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+	"nl-NL": {
+		Name:             "nl-NL",
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		DateFormat:       "02-01-2006",
+		DateTimeFormat:   "02-01-2006 15:04:05",
+		TimeFormat:       "15:04",
+		FirstDayOfWeek:   time.Monday,
+	},
+}
+
+// Default returns the default locale.
+func Default() *Locale {
+	locale := *locales[DefaultName]
+	return &locale
+}
+
+// Names returns the names of the supported locales in alphabetical order.
+func Names() []string {
+	var names []string
+	for name := range locales {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the locale by its name. The name is case-insensitive and
+// underscores are accepted as separator too, like "de_DE". If the name is
+// empty, the default locale returns.
+func Get(name string) (*Locale, error) {
+	if name == "" {
+		return Default(), nil
+	}
+
+	for key, locale := range locales {
+		if strings.EqualFold(key, strings.ReplaceAll(name, "_", "-")) {
+			l := *locale
+			return &l, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%v: %s", ErrUnknownLocale, name)
+}
+
+// FormatNumber returns the number formatted with the given precision, using
+// the decimal and group separators of the locale.
+func (l *Locale) FormatNumber(value float64, precision int) string {
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', precision, 64)
+
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+
+	if l.GroupSeparator != "" {
+		var grouped strings.Builder
+		for i, digit := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				grouped.WriteString(l.GroupSeparator)
+			}
+
+			grouped.WriteRune(digit)
+		}
+
+		integer = grouped.String()
+	}
+
+	if hasFraction {
+		integer += l.DecimalSeparator + fraction
+	}
+
+	// Do not print negative zero, like "-0.00"
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		return "-" + integer
+	}
+
+	return integer
+}
+
+// FormatHours returns the duration in decimal hours, formatted with the given
+// precision, like "1.234,50" for the "de-DE" locale.
+func (l *Locale) FormatHours(d time.Duration, precision int) string {
+	return l.FormatNumber(d.Hours(), precision)
+}
+
+// FormatDate returns the date formatted by the locale's date format.
+func (l *Locale) FormatDate(t time.Time) string {
+	return t.Format(l.DateFormat)
+}
+
+// FormatDateTime returns the date and time formatted by the locale's date
+// time format.
+func (l *Locale) FormatDateTime(t time.Time) string {
+	return t.Format(l.DateTimeFormat)
+}
+
+// FormatTime returns the time of the day formatted by the locale's time
+// format.
+func (l *Locale) FormatTime(t time.Time) string {
+	return t.Format(l.TimeFormat)
+}
+
+// StartOfWeek returns the midnight of the first day of the week containing t.
+func (l *Locale) StartOfWeek(t time.Time) time.Time {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+
+	offset := (int(t.Weekday()) - int(l.FirstDayOfWeek) + 7) % 7
+	return midnight.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,136 @@
+package locale_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	l, err := locale.Get("de_de")
+	require.Nil(t, err)
+	require.Equal(t, "de-DE", l.Name)
+
+	l, err = locale.Get("")
+	require.Nil(t, err)
+	require.Equal(t, locale.DefaultName, l.Name)
+
+	_, err = locale.Get("xx-XX")
+	require.ErrorContains(t, err, locale.ErrUnknownLocale.Error())
+}
+
+func TestGetReturnsCopy(t *testing.T) {
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	l.DecimalSeparator = "."
+
+	l, err = locale.Get("de-DE")
+	require.Nil(t, err)
+	require.Equal(t, ",", l.DecimalSeparator)
+}
+
+func TestLocale_FormatNumber(t *testing.T) {
+	tests := []struct {
+		locale    string
+		value     float64
+		precision int
+		expected  string
+	}{
+		{locale: locale.DefaultName, value: 1234.5, precision: 2, expected: "1234.50"},
+		{locale: "en-US", value: 1234.5, precision: 1, expected: "1,234.5"},
+		{locale: "en-US", value: 1234567.891, precision: 2, expected: "1,234,567.89"},
+		{locale: "de-DE", value: 1234.5, precision: 2, expected: "1.234,50"},
+		{locale: "de-DE", value: -1234.5, precision: 1, expected: "-1.234,5"},
+		{locale: "de-DE", value: 123, precision: 0, expected: "123"},
+		{locale: "fr-FR", value: 1234.5, precision: 2, expected: "1 234,50"},
+		{locale: "en-US", value: -0.001, precision: 2, expected: "0.00"},
+	}
+
+	for _, test := range tests {
+		l, err := locale.Get(test.locale)
+		require.Nil(t, err)
+		require.Equal(t, test.expected, l.FormatNumber(test.value, test.precision), test.locale)
+	}
+}
+
+func TestLocale_FormatHours(t *testing.T) {
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+	require.Equal(t, "1,50", l.FormatHours(time.Hour+time.Minute*30, 2))
+}
+
+func TestLocale_FormatDate(t *testing.T) {
+	date := time.Date(2021, 10, 2, 14, 5, 6, 0, time.UTC)
+
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+	require.Equal(t, "02.10.2021", l.FormatDate(date))
+	require.Equal(t, "02.10.2021 14:05:06", l.FormatDateTime(date))
+	require.Equal(t, "14:05", l.FormatTime(date))
+
+	l, err = locale.Get("en-US")
+	require.Nil(t, err)
+	require.Equal(t, "10/02/2021", l.FormatDate(date))
+	require.Equal(t, "10/02/2021 2:05:06 PM", l.FormatDateTime(date))
+	require.Equal(t, "2:05 PM", l.FormatTime(date))
+}
+
+func TestLocale_StartOfWeek(t *testing.T) {
+	// 2021-10-06 is a Wednesday
+	date := time.Date(2021, 10, 6, 14, 5, 6, 0, time.UTC)
+
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC), l.StartOfWeek(date))
+
+	l, err = locale.Get("en-US")
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC), l.StartOfWeek(date))
+
+	// 2021-10-03 is a Sunday
+	sunday := time.Date(2021, 10, 3, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, sunday, l.StartOfWeek(sunday))
+}
+
+func TestLocale_ParseDuration(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    string
+		expected time.Duration
+	}{
+		{locale: locale.DefaultName, value: "1:30", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "0:45:30", expected: time.Minute*45 + time.Second*30},
+		{locale: locale.DefaultName, value: "1.5h", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "90m", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "1h30m", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "1h 30 min", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "1h30", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: " 1.5 ", expected: time.Minute * 90},
+		{locale: locale.DefaultName, value: "2 Hours", expected: time.Hour * 2},
+		{locale: "de-DE", value: "1,5", expected: time.Minute * 90},
+		{locale: "de-DE", value: "1,5 Std", expected: time.Minute * 90},
+		{locale: "de-DE", value: "1.000,5", expected: time.Hour*1000 + time.Minute*30},
+		{locale: "en-US", value: "1,000.5", expected: time.Hour*1000 + time.Minute*30},
+		{locale: "hu-HU", value: "1 óra 30 perc", expected: time.Minute * 90},
+		{locale: "fr-FR", value: "1h30", expected: time.Minute * 90},
+	}
+
+	for _, test := range tests {
+		l, err := locale.Get(test.locale)
+		require.Nil(t, err)
+
+		duration, err := l.ParseDuration(test.value)
+		require.Nil(t, err, test.value)
+		require.Equal(t, test.expected, duration, test.value)
+	}
+}
+
+func TestLocale_ParseDuration_Invalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "1:75", "1:3", "-1h", "1.5.5h", "1x", "30 1h", "h"} {
+		_, err := locale.Default().ParseDuration(value)
+		require.ErrorIs(t, err, locale.ErrInvalidDuration, value)
+	}
+}
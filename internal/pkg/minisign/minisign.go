@@ -0,0 +1,278 @@
+package minisign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// algorithmEd25519 signs the message itself; used by legacy signatures.
+	algorithmEd25519 string = "Ed"
+	// algorithmHashedEd25519 signs the BLAKE2b-512 hash of the message.
+	algorithmHashedEd25519 string = "ED"
+	// kdfNone marks the secret keys not encrypted by a password.
+	kdfNone string = "\x00\x00"
+	// checksumBlake2b marks the secret keys checksummed by BLAKE2b-256.
+	checksumBlake2b string = "B2"
+
+	keyIDSize        int    = 8
+	kdfParamsSize    int    = 32 + 8 + 8
+	publicKeySize    int    = 2 + keyIDSize + ed25519.PublicKeySize
+	secretKeySize    int    = 2 + 2 + 2 + kdfParamsSize + keyIDSize + ed25519.PrivateKeySize + 32
+	signatureSize    int    = 2 + keyIDSize + ed25519.SignatureSize
+	untrustedPrefix  string = "untrusted comment: "
+	trustedPrefix    string = "trusted comment: "
+	publicKeyComment string = "minisign public key %s"
+	secretKeyComment string = "minisign secret key"
+	signatureComment string = "signature from minisign secret key"
+	maxLineCount     int    = 3
+)
+
+var (
+	// ErrInvalidKey returns when a key cannot be parsed.
+	ErrInvalidKey = errors.New("invalid minisign key")
+	// ErrEncryptedKey returns when the secret key is encrypted by a password.
+	ErrEncryptedKey = errors.New("encrypted minisign secret keys are not supported")
+	// ErrInvalidSignature returns when a signature cannot be parsed.
+	ErrInvalidSignature = errors.New("invalid minisign signature")
+	// ErrKeyMismatch returns when the signature was created by another key.
+	ErrKeyMismatch = errors.New("signature was created by another key")
+	// ErrVerificationFailed returns when the signature does not match the
+	// message or the trusted comment.
+	ErrVerificationFailed = errors.New("signature verification failed")
+)
+
+// formatKeyID returns the key ID in the format printed by minisign.
+func formatKeyID(id [keyIDSize]byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+// decodeLines returns the data lines of a minisign file, skipping the
+// untrusted comment. The trusted comment of signatures is returned as is.
+func decodeLines(data []byte) []string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, untrustedPrefix) {
+			continue
+		}
+
+		// The trusted comment is signed, hence it must be kept as is
+		if !strings.HasPrefix(line, trustedPrefix) {
+			line = strings.TrimSpace(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLineCount {
+		return nil
+	}
+
+	return lines
+}
+
+// PublicKey represents a minisign public key, used to verify the signatures
+// created by the matching SecretKey.
+type PublicKey struct {
+	ID  [keyIDSize]byte
+	Key ed25519.PublicKey
+}
+
+// KeyID returns the ID of the key as printed by minisign.
+func (k *PublicKey) KeyID() string {
+	return formatKeyID(k.ID)
+}
+
+// MarshalText returns the public key in the minisign public key file format.
+func (k *PublicKey) MarshalText() ([]byte, error) {
+	raw := make([]byte, 0, publicKeySize)
+	raw = append(raw, algorithmEd25519...)
+	raw = append(raw, k.ID[:]...)
+	raw = append(raw, k.Key...)
+
+	return []byte(fmt.Sprintf("%s"+publicKeyComment+"\n%s\n", untrustedPrefix, k.KeyID(), base64.StdEncoding.EncodeToString(raw))), nil
+}
+
+// ParsePublicKey parses the content of a minisign public key file. The base64
+// encoded key without the comment line is accepted too.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	lines := decodeLines(data)
+	if len(lines) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(raw) != publicKeySize || string(raw[:2]) != algorithmEd25519 {
+		return nil, ErrInvalidKey
+	}
+
+	key := &PublicKey{Key: ed25519.PublicKey(raw[2+keyIDSize:])}
+	copy(key.ID[:], raw[2:])
+
+	return key, nil
+}
+
+// SecretKey represents a minisign secret key, used to sign messages. Only the
+// secret keys not encrypted by a password are supported, like the keys
+// created by `minisign -G -W` or GenerateKey.
+type SecretKey struct {
+	ID  [keyIDSize]byte
+	Key ed25519.PrivateKey
+}
+
+// checksum returns the checksum of the key stored in the secret key file.
+func (k *SecretKey) checksum() []byte {
+	data := make([]byte, 0, 2+keyIDSize+ed25519.PrivateKeySize)
+	data = append(data, algorithmEd25519...)
+	data = append(data, k.ID[:]...)
+	data = append(data, k.Key...)
+
+	checksum := blake2b.Sum256(data)
+	return checksum[:]
+}
+
+// Public returns the public key of the secret key.
+func (k *SecretKey) Public() *PublicKey {
+	return &PublicKey{ID: k.ID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// MarshalText returns the secret key in the minisign secret key file format,
+// without encryption.
+func (k *SecretKey) MarshalText() ([]byte, error) {
+	raw := make([]byte, 0, secretKeySize)
+	raw = append(raw, algorithmEd25519...)
+	raw = append(raw, kdfNone...)
+	raw = append(raw, checksumBlake2b...)
+	raw = append(raw, make([]byte, kdfParamsSize)...)
+	raw = append(raw, k.ID[:]...)
+	raw = append(raw, k.Key...)
+	raw = append(raw, k.checksum()...)
+
+	return []byte(fmt.Sprintf("%s%s\n%s\n", untrustedPrefix, secretKeyComment, base64.StdEncoding.EncodeToString(raw))), nil
+}
+
+// ParseSecretKey parses the content of a minisign secret key file. If the key
+// is encrypted by a password, ErrEncryptedKey returns.
+func ParseSecretKey(data []byte) (*SecretKey, error) {
+	lines := decodeLines(data)
+	if len(lines) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(raw) != secretKeySize || string(raw[:2]) != algorithmEd25519 || string(raw[4:6]) != checksumBlake2b {
+		return nil, ErrInvalidKey
+	}
+
+	if string(raw[2:4]) != kdfNone {
+		return nil, ErrEncryptedKey
+	}
+
+	keyNum := raw[6+kdfParamsSize:]
+
+	key := &SecretKey{Key: ed25519.PrivateKey(keyNum[keyIDSize : keyIDSize+ed25519.PrivateKeySize])}
+	copy(key.ID[:], keyNum)
+
+	if subtle.ConstantTimeCompare(key.checksum(), keyNum[keyIDSize+ed25519.PrivateKeySize:]) != 1 {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidKey)
+	}
+
+	return key, nil
+}
+
+// GenerateKey returns a new secret key using the random source.
+func GenerateKey(random io.Reader) (*SecretKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(random)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SecretKey{Key: privateKey}
+	if _, err = io.ReadFull(random, key.ID[:]); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Sign returns the signature of the message in the minisign signature file
+// format. The message is prehashed, like by minisign, and the trusted comment
+// is signed together with the signature, so it cannot be changed either.
+func Sign(key *SecretKey, message []byte, trustedComment string) ([]byte, error) {
+	if strings.ContainsAny(trustedComment, "\r\n") {
+		return nil, fmt.Errorf("%w: the trusted comment must be a single line", ErrInvalidSignature)
+	}
+
+	hash := blake2b.Sum512(message)
+	signature := ed25519.Sign(key.Key, hash[:])
+	globalSignature := ed25519.Sign(key.Key, append(append([]byte{}, signature...), trustedComment...))
+
+	raw := make([]byte, 0, signatureSize)
+	raw = append(raw, algorithmHashedEd25519...)
+	raw = append(raw, key.ID[:]...)
+	raw = append(raw, signature...)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%s %s\n", untrustedPrefix, signatureComment, formatKeyID(key.ID))
+	fmt.Fprintf(&buf, "%s\n", base64.StdEncoding.EncodeToString(raw))
+	fmt.Fprintf(&buf, "%s%s\n", trustedPrefix, trustedComment)
+	fmt.Fprintf(&buf, "%s\n", base64.StdEncoding.EncodeToString(globalSignature))
+
+	return buf.Bytes(), nil
+}
+
+// Verify verifies the minisign signature of the message, including its trusted
+// comment, and returns the trusted comment. Both prehashed and legacy
+// signatures are accepted.
+func Verify(key *PublicKey, message []byte, signatureFile []byte) (string, error) {
+	lines := decodeLines(signatureFile)
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], trustedPrefix) {
+		return "", ErrInvalidSignature
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(raw) != signatureSize {
+		return "", ErrInvalidSignature
+	}
+
+	globalSignature, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || len(globalSignature) != ed25519.SignatureSize {
+		return "", ErrInvalidSignature
+	}
+
+	if !bytes.Equal(raw[2:2+keyIDSize], key.ID[:]) {
+		return "", fmt.Errorf("%w: %s", ErrKeyMismatch, formatKeyID(*(*[keyIDSize]byte)(raw[2 : 2+keyIDSize])))
+	}
+
+	signature := raw[2+keyIDSize:]
+
+	switch string(raw[:2]) {
+	case algorithmHashedEd25519:
+		hash := blake2b.Sum512(message)
+		message = hash[:]
+	case algorithmEd25519:
+	default:
+		return "", ErrInvalidSignature
+	}
+
+	if !ed25519.Verify(key.Key, message, signature) {
+		return "", ErrVerificationFailed
+	}
+
+	trustedComment := strings.TrimPrefix(lines[1], trustedPrefix)
+	if !ed25519.Verify(key.Key, append(append([]byte{}, signature...), trustedComment...), globalSignature) {
+		return "", fmt.Errorf("%w: the trusted comment was changed", ErrVerificationFailed)
+	}
+
+	return trustedComment, nil
+}
@@ -0,0 +1,101 @@
+package minisign_test
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/minisign"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	secretKey, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	message := []byte(`{"target":"tempo","uploaded":2}`)
+
+	signature, err := minisign.Sign(secretKey, message, "timestamp:1633165200\tfile:20211002T090000Z.json")
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(signature), "untrusted comment: signature from minisign secret key "+secretKey.Public().KeyID()+"\n"))
+
+	trustedComment, err := minisign.Verify(secretKey.Public(), message, signature)
+	require.Nil(t, err)
+	require.Equal(t, "timestamp:1633165200\tfile:20211002T090000Z.json", trustedComment)
+
+	_, err = minisign.Verify(secretKey.Public(), []byte(`{"target":"tempo","uploaded":3}`), signature)
+	require.ErrorIs(t, err, minisign.ErrVerificationFailed)
+
+	changedComment := strings.Replace(string(signature), "timestamp:1633165200", "timestamp:1633165201", 1)
+	_, err = minisign.Verify(secretKey.Public(), message, []byte(changedComment))
+	require.ErrorIs(t, err, minisign.ErrVerificationFailed)
+
+	otherKey, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	_, err = minisign.Verify(otherKey.Public(), message, signature)
+	require.ErrorIs(t, err, minisign.ErrKeyMismatch)
+
+	_, err = minisign.Verify(secretKey.Public(), message, []byte("not a signature"))
+	require.ErrorIs(t, err, minisign.ErrInvalidSignature)
+
+	_, err = minisign.Sign(secretKey, message, "multi\nline")
+	require.ErrorIs(t, err, minisign.ErrInvalidSignature)
+}
+
+func TestVerify_Minisign(t *testing.T) {
+	// Created by another minisign implementation
+	publicKey, err := minisign.ParsePublicKey([]byte("untrusted comment: minisign public key: D00C93158B7693F2\n" +
+		"RWTyk3aLFZMM0JHRtoFmqmbllyB2euPuDJICD8RVYhdT6PkWGcBOkjXk\n"))
+	require.Nil(t, err)
+	require.Equal(t, "D00C93158B7693F2", publicKey.KeyID())
+
+	trustedComment, err := minisign.Verify(publicKey, []byte("test"), []byte("untrusted comment: signature from minisign secret key\n"+
+		"RWTyk3aLFZMM0ABEIPs2Tdyg/cQlNn9Ct6xc+5MXsdqBctF6W7wobfTdZtk3sqZbgKlihLlc0ybQCRcwkZs2oIzSsh9PTKoZaAQ=\n"+
+		"trusted comment: timestamp:1556193335\tfile:test\n"+
+		"nRGlt3dNple24n/URlAsygBRSi4dDl37NjTKZHxyByztiQs8AjLWwrKDpocSW3lkLhfG908UriUreWz97qVFBg==\n"))
+	require.Nil(t, err)
+	require.Equal(t, "timestamp:1556193335\tfile:test", trustedComment)
+}
+
+func TestKeys_MarshalText(t *testing.T) {
+	secretKey, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	data, err := secretKey.MarshalText()
+	require.Nil(t, err)
+
+	parsedSecretKey, err := minisign.ParseSecretKey(data)
+	require.Nil(t, err)
+	require.Equal(t, secretKey, parsedSecretKey)
+
+	data, err = secretKey.Public().MarshalText()
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(data), "untrusted comment: minisign public key "+secretKey.Public().KeyID()+"\n"))
+
+	parsedPublicKey, err := minisign.ParsePublicKey(data)
+	require.Nil(t, err)
+	require.Equal(t, secretKey.Public(), parsedPublicKey)
+
+	_, err = minisign.ParsePublicKey([]byte("untrusted comment: nothing\n"))
+	require.ErrorIs(t, err, minisign.ErrInvalidKey)
+}
+
+func TestParseSecretKey_Invalid(t *testing.T) {
+	secretKey, err := minisign.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	data, err := secretKey.MarshalText()
+	require.Nil(t, err)
+
+	lines := strings.Split(string(data), "\n")
+
+	// Encrypted keys use the "Sc" key derivation function
+	encrypted := []byte(lines[1])
+	copy(encrypted[:8], "RWRTY0Iy")
+	_, err = minisign.ParseSecretKey(encrypted)
+	require.ErrorIs(t, err, minisign.ErrEncryptedKey)
+
+	_, err = minisign.ParseSecretKey([]byte("RWQ="))
+	require.ErrorIs(t, err, minisign.ErrInvalidKey)
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"strings"
+	"time"
+)
+
+// DiscordMessage represents the message posted to a Discord webhook, carrying
+// an embed.
+type DiscordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []DiscordEmbed `json:"embeds"`
+}
+
+// DiscordEmbed represents the embed of the summary.
+type DiscordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []DiscordEmbedField `json:"fields"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+// DiscordEmbedField represents a name-value pair of the embed.
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// NewDiscordMessage returns the message of the summary embed, listing the
+// details of the sync as inline fields and the errors as the description.
+func NewDiscordMessage(summary *Summary) *DiscordMessage {
+	var fields []DiscordEmbedField
+	for _, f := range summary.facts() {
+		// Discord rejects fields having empty values
+		value := f.value
+		if value == "" {
+			value = "-"
+		}
+
+		fields = append(fields, DiscordEmbedField{Name: f.name, Value: value, Inline: true})
+	}
+
+	var description string
+	if errs := summary.listedErrors(); len(errs) != 0 {
+		description = "- " + strings.Join(errs, "\n- ")
+	}
+
+	return &DiscordMessage{
+		Username: DiscordUsername,
+		Embeds: []DiscordEmbed{
+			{
+				Title:       summary.Title(),
+				Description: description,
+				Color:       summary.Color(),
+				Fields:      fields,
+				Timestamp:   time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+	}
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// ColorSuccess is the color of the notifications of successful syncs.
+	ColorSuccess int = 0x2EB67D
+	// ColorFailure is the color of the notifications of syncs having failed
+	// uploads.
+	ColorFailure int = 0xE01E5A
+	// ColorDryRun is the color of the notifications of dry runs.
+	ColorDryRun int = 0x9E9E9E
+
+	// AdaptiveCardSchema is the schema of the adaptive cards sent to Teams.
+	AdaptiveCardSchema string = "http://adaptivecards.io/schemas/adaptive-card.json"
+	// AdaptiveCardContentType is the content type of the adaptive card
+	// attachments.
+	AdaptiveCardContentType string = "application/vnd.microsoft.card.adaptive"
+	// AdaptiveCardVersion is the version of the adaptive cards sent to Teams.
+	AdaptiveCardVersion string = "1.4"
+
+	// DiscordUsername is the name the Discord messages are sent by.
+	DiscordUsername string = "minutes"
+
+	// periodFormat is the format of the start and end of the synced period.
+	periodFormat string = "2006-01-02 15:04"
+	// maxListedErrors is the maximum number of errors listed in the
+	// notifications, so the messages do not exceed the limits of the services.
+	maxListedErrors int = 5
+	// maxErrorLength is the maximum number of characters of a listed error.
+	maxErrorLength int = 300
+)
+
+var (
+	// ErrNotifyFailed wraps the error when sending a notification failed.
+	ErrNotifyFailed = errors.New("failed to send notification")
+)
+
+// Summary represents the outcome of a sync to notify about.
+type Summary struct {
+	Source    string
+	Target    string
+	Start     time.Time
+	End       time.Time
+	DryRun    bool
+	Entries   int
+	Uploaded  int
+	Failed    int
+	TimeSpent time.Duration
+	Errors    []string
+}
+
+// Title returns the title of the notification.
+func (s *Summary) Title() string {
+	switch {
+	case s.DryRun:
+		return fmt.Sprintf("Dry run of syncing %d entries to %s", s.Entries, s.Target)
+	case s.Failed > 0:
+		return fmt.Sprintf("Failed to upload %d of %d entries to %s", s.Failed, s.Entries, s.Target)
+	default:
+		return fmt.Sprintf("Uploaded %d entries to %s", s.Uploaded, s.Target)
+	}
+}
+
+// Color returns the color of the notification, based on the outcome.
+func (s *Summary) Color() int {
+	switch {
+	case s.DryRun:
+		return ColorDryRun
+	case s.Failed > 0:
+		return ColorFailure
+	default:
+		return ColorSuccess
+	}
+}
+
+// fact represents a name-value pair of the summary.
+type fact struct {
+	name  string
+	value string
+}
+
+// facts returns the details of the summary as name-value pairs.
+func (s *Summary) facts() []fact {
+	return []fact{
+		{"Source", s.Source},
+		{"Target", s.Target},
+		{"Period", s.Start.Local().Format(periodFormat) + " - " + s.End.Local().Format(periodFormat)},
+		{"Uploaded", strconv.Itoa(s.Uploaded)},
+		{"Failed", strconv.Itoa(s.Failed)},
+		{"Time spent", s.TimeSpent.String()},
+	}
+}
+
+// listedErrors returns the errors listed in the notification, truncating the
+// long ones. If there are more errors than listed, the number of the
+// remaining errors is appended.
+func (s *Summary) listedErrors() []string {
+	var listed []string
+
+	for i, err := range s.Errors {
+		if i == maxListedErrors {
+			listed = append(listed, fmt.Sprintf("and %d more errors", len(s.Errors)-maxListedErrors))
+			break
+		}
+
+		if runes := []rune(err); len(runes) > maxErrorLength {
+			err = string(runes[:maxErrorLength]) + "…"
+		}
+
+		listed = append(listed, err)
+	}
+
+	return listed
+}
+
+// Opts represents the options of sending the notifications. Every set webhook
+// is notified.
+type Opts struct {
+	// TeamsURL is the URL of the Microsoft Teams incoming webhook.
+	TeamsURL string
+	// DiscordURL is the URL of the Discord webhook.
+	DiscordURL string
+	Timeout    time.Duration
+	// HTTPClient is used to call the webhooks. If not set, the
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// IsEnabled returns true if any webhook is set.
+func (o *Opts) IsEnabled() bool {
+	return o.TeamsURL != "" || o.DiscordURL != ""
+}
+
+// post posts the payload to the webhook as JSON.
+func post(ctx context.Context, url string, payload interface{}, opts *Opts) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Send posts the summary card to every webhook set by the options. Every
+// webhook is called even if another one failed, and the errors are joined.
+func Send(ctx context.Context, summary *Summary, opts *Opts) error {
+	var errs []error
+
+	if opts.TeamsURL != "" {
+		if err := post(ctx, opts.TeamsURL, NewTeamsMessage(summary), opts); err != nil {
+			errs = append(errs, fmt.Errorf("%w: teams: %w", ErrNotifyFailed, err))
+		}
+	}
+
+	if opts.DiscordURL != "" {
+		if err := post(ctx, opts.DiscordURL, NewDiscordMessage(summary), opts); err != nil {
+			errs = append(errs, fmt.Errorf("%w: discord: %w", ErrNotifyFailed, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/notify"
+	"github.com/stretchr/testify/require"
+)
+
+func newSummary() *notify.Summary {
+	return &notify.Summary{
+		Source:    "clockify",
+		Target:    "tempo",
+		Start:     time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local),
+		End:       time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local),
+		Entries:   3,
+		Uploaded:  3,
+		TimeSpent: time.Hour * 5,
+	}
+}
+
+func TestSummary_Title(t *testing.T) {
+	summary := newSummary()
+	require.Equal(t, "Uploaded 3 entries to tempo", summary.Title())
+	require.Equal(t, notify.ColorSuccess, summary.Color())
+
+	summary.Uploaded = 1
+	summary.Failed = 2
+	require.Equal(t, "Failed to upload 2 of 3 entries to tempo", summary.Title())
+	require.Equal(t, notify.ColorFailure, summary.Color())
+
+	summary.DryRun = true
+	require.Equal(t, "Dry run of syncing 3 entries to tempo", summary.Title())
+	require.Equal(t, notify.ColorDryRun, summary.Color())
+}
+
+func TestNewTeamsMessage(t *testing.T) {
+	summary := newSummary()
+	summary.Entries = 10
+	summary.Failed = 7
+	for i := 0; i < 7; i++ {
+		summary.Errors = append(summary.Errors, fmt.Sprintf("failed to upload entries: CPT-%d", i))
+	}
+
+	message := notify.NewTeamsMessage(summary)
+	require.Equal(t, "message", message.Type)
+	require.Len(t, message.Attachments, 1)
+	require.Equal(t, notify.AdaptiveCardContentType, message.Attachments[0].ContentType)
+
+	body := message.Attachments[0].Content.Body
+	require.Len(t, body, 3)
+	require.Equal(t, "Failed to upload 7 of 10 entries to tempo", body[0].Text)
+	require.Equal(t, "Attention", body[0].Color)
+	require.Contains(t, body[1].Facts, notify.AdaptiveCardFact{Title: "Period", Value: "2021-10-01 00:00 - 2021-10-02 00:00"})
+	require.Contains(t, body[1].Facts, notify.AdaptiveCardFact{Title: "Time spent", Value: "5h0m0s"})
+	require.Contains(t, body[2].Text, "- failed to upload entries: CPT-4\n- and 2 more errors")
+}
+
+func TestNewDiscordMessage(t *testing.T) {
+	summary := newSummary()
+	summary.Source = ""
+	summary.Errors = []string{strings.Repeat("x", 500)}
+
+	message := notify.NewDiscordMessage(summary)
+	require.Equal(t, notify.DiscordUsername, message.Username)
+	require.Len(t, message.Embeds, 1)
+
+	embed := message.Embeds[0]
+	require.Equal(t, "Uploaded 3 entries to tempo", embed.Title)
+	require.Equal(t, notify.ColorSuccess, embed.Color)
+	require.Equal(t, notify.DiscordEmbedField{Name: "Source", Value: "-", Inline: true}, embed.Fields[0])
+	require.Equal(t, "- "+strings.Repeat("x", 300)+"…", embed.Description)
+}
+
+func TestSend(t *testing.T) {
+	received := map[string]map[string]interface{}{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload map[string]interface{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&payload))
+		received[r.URL.Path] = payload
+
+		if r.URL.Path == "/discord" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer mockServer.Close()
+
+	err := notify.Send(context.Background(), newSummary(), &notify.Opts{
+		TeamsURL:   mockServer.URL + "/teams",
+		DiscordURL: mockServer.URL + "/discord",
+		Timeout:    time.Second * 5,
+	})
+
+	require.ErrorIs(t, err, notify.ErrNotifyFailed)
+	require.Contains(t, err.Error(), "discord: unexpected status code: 429")
+	require.NotContains(t, err.Error(), "teams")
+
+	require.Equal(t, "message", received["/teams"]["type"])
+	require.Equal(t, notify.DiscordUsername, received["/discord"]["username"])
+}
@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TeamsMessage represents the message posted to a Microsoft Teams incoming
+// webhook, carrying an adaptive card.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment represents the adaptive card attachment of the message.
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard represents the adaptive card of the summary.
+type AdaptiveCard struct {
+	Schema  string                `json:"$schema"`
+	Type    string                `json:"type"`
+	Version string                `json:"version"`
+	Body    []AdaptiveCardElement `json:"body"`
+}
+
+// AdaptiveCardElement represents a text block or a fact set of the card.
+type AdaptiveCardElement struct {
+	Type   string             `json:"type"`
+	Text   string             `json:"text,omitempty"`
+	Size   string             `json:"size,omitempty"`
+	Weight string             `json:"weight,omitempty"`
+	Color  string             `json:"color,omitempty"`
+	Wrap   bool               `json:"wrap,omitempty"`
+	Facts  []AdaptiveCardFact `json:"facts,omitempty"`
+}
+
+// AdaptiveCardFact represents a name-value pair of a fact set.
+type AdaptiveCardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsColor returns the adaptive card color of the summary's outcome.
+func teamsColor(summary *Summary) string {
+	switch summary.Color() {
+	case ColorFailure:
+		return "Attention"
+	case ColorSuccess:
+		return "Good"
+	default:
+		return "Default"
+	}
+}
+
+// NewTeamsMessage returns the message of the summary card, listing the
+// details of the sync as facts, followed by the errors if any.
+func NewTeamsMessage(summary *Summary) *TeamsMessage {
+	var facts []AdaptiveCardFact
+	for _, f := range summary.facts() {
+		facts = append(facts, AdaptiveCardFact{Title: f.name, Value: f.value})
+	}
+
+	body := []AdaptiveCardElement{
+		{
+			Type:   "TextBlock",
+			Text:   summary.Title(),
+			Size:   "Medium",
+			Weight: "Bolder",
+			Color:  teamsColor(summary),
+			Wrap:   true,
+		},
+		{
+			Type:  "FactSet",
+			Facts: facts,
+		},
+	}
+
+	if errs := summary.listedErrors(); len(errs) != 0 {
+		body = append(body, AdaptiveCardElement{
+			Type:  "TextBlock",
+			Text:  fmt.Sprintf("- %s", strings.Join(errs, "\n- ")),
+			Color: "Attention",
+			Wrap:  true,
+		})
+	}
+
+	return &TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{
+				ContentType: AdaptiveCardContentType,
+				Content: AdaptiveCard{
+					Schema:  AdaptiveCardSchema,
+					Type:    "AdaptiveCard",
+					Version: AdaptiveCardVersion,
+					Body:    body,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package pdf
+
+// Widths of the printable ASCII characters (from space to tilde) of the
+// standard Helvetica fonts, in thousandths of the font size, as defined by
+// the Adobe Font Metrics of the fonts.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// defaultCharWidth is used for the characters outside the printable ASCII
+// range, as most of the accented letters have the width of a digit.
+const defaultCharWidth int = 556
+
+// winAnsiSpecials maps the characters of the 0x80-0x9F range of the
+// WinAnsiEncoding. Every other character below 0x100 is mapped as in Latin-1.
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeWinAnsi converts the text to WinAnsiEncoding used by the standard
+// fonts. Characters that cannot be encoded are replaced by a question mark.
+func encodeWinAnsi(text string) []byte {
+	var encoded []byte
+
+	for _, char := range text {
+		switch {
+		case char < 0x80 || (char >= 0xA0 && char <= 0xFF):
+			encoded = append(encoded, byte(char))
+		case winAnsiSpecials[char] != 0:
+			encoded = append(encoded, winAnsiSpecials[char])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+
+	return encoded
+}
+
+// TextWidth returns the width of the text in points when printed with the
+// given font size.
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+
+	total := 0
+	for _, char := range encodeWinAnsi(text) {
+		if char >= ' ' && char <= '~' {
+			total += widths[char-' ']
+		} else {
+			total += defaultCharWidth
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+// TruncateText truncates the text to fit into the given width, replacing the
+// truncated part by an ellipsis.
+func TruncateText(text string, width float64, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+
+		if truncated := string(runes) + "..."; TextWidth(truncated, size, bold) <= width {
+			return truncated
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,244 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	// Register the supported image formats
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	// A4Width is the width of an A4 page in points.
+	A4Width float64 = 595.28
+	// A4Height is the height of an A4 page in points.
+	A4Height float64 = 841.89
+)
+
+// Image represents an image embedded in the document. The same image can be
+// drawn multiple times, while it is embedded only once.
+type Image struct {
+	id     int
+	Width  int
+	Height int
+	data   []byte
+}
+
+// Page represents a page of the document. The coordinates are measured in
+// points from the top-left corner of the page.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	images  map[int]*Image
+}
+
+func (p *Page) y(y float64) float64 {
+	return p.doc.height - y
+}
+
+// Text draws the text with its baseline starting at the given position.
+func (p *Page) Text(x float64, y float64, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	var escaped strings.Builder
+	for _, char := range encodeWinAnsi(text) {
+		switch char {
+		case '\\', '(', ')':
+			escaped.WriteByte('\\')
+			escaped.WriteByte(char)
+		default:
+			escaped.WriteByte(char)
+		}
+	}
+
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.y(y), escaped.String())
+}
+
+// TextRight draws the text with its baseline ending at the given position.
+func (p *Page) TextRight(x float64, y float64, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+// Line draws a black line between the given positions.
+func (p *Page) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, p.y(y1), x2, p.y(y2))
+}
+
+// FillRect fills the rectangle with the given gray level between 0 (black)
+// and 1 (white).
+func (p *Page) FillRect(x float64, y float64, width float64, height float64, gray float64) {
+	fmt.Fprintf(&p.content, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, p.y(y+height), width, height)
+}
+
+// Image draws the image into the given rectangle.
+func (p *Page) Image(img *Image, x float64, y float64, width float64, height float64) {
+	p.images[img.id] = img
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, x, p.y(y+height), img.id)
+}
+
+// Document represents a PDF document using the standard Helvetica fonts.
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+	images []*Image
+}
+
+// AddPage appends a new, empty page to the document and returns it.
+func (d *Document) AddPage() *Page {
+	page := &Page{
+		doc:    d,
+		images: map[int]*Image{},
+	}
+
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages returns the pages of the document.
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// AddImage decodes the JPEG or PNG image and embeds it in the document.
+// Transparent pixels are blended with white, since the alpha channel is not
+// embedded.
+func (d *Document) AddImage(r io.Reader) (*Image, error) {
+	decoded, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := decoded.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+			alpha := uint32(c.A)
+
+			for _, channel := range []uint8{c.R, c.G, c.B} {
+				pixels = append(pixels, byte((uint32(channel)*alpha+255*(255-alpha))/255))
+			}
+		}
+	}
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err = writer.Write(pixels); err != nil {
+		return nil, err
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	img := &Image{
+		id:     len(d.images) + 1,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		data:   compressed.Bytes(),
+	}
+
+	d.images = append(d.images, img)
+	return img, nil
+}
+
+// WriteTo writes the document to the writer.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string, stream []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\n", len(offsets), body)
+
+		if stream != nil {
+			buf.WriteString("stream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream\n")
+		}
+
+		buf.WriteString("endobj\n")
+	}
+
+	// The object numbers are calculated upfront, since the pages are
+	// referring to each other and to the fonts and images
+	const catalogID, pagesID, fontID, boldFontID = 1, 2, 3, 4
+	imageID := func(img *Image) int {
+		return boldFontID + img.id
+	}
+	pageID := func(i int) int {
+		return boldFontID + len(d.images) + 1 + i*2
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	writeObject(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID), nil)
+
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID(i)))
+	}
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)), nil)
+
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+
+	for _, img := range d.images {
+		writeObject(fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>",
+			img.Width,
+			img.Height,
+			len(img.data),
+		), img.data)
+	}
+
+	for i, page := range d.pages {
+		var xObjects []string
+		for _, img := range d.images {
+			if _, ok := page.images[img.id]; ok {
+				xObjects = append(xObjects, fmt.Sprintf("/Im%d %d 0 R", img.id, imageID(img)))
+			}
+		}
+
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> /XObject << %s >> >> /Contents %d 0 R >>",
+			pagesID,
+			d.width,
+			d.height,
+			fontID,
+			boldFontID,
+			strings.Join(xObjects, " "),
+			pageID(i)+1,
+		), nil)
+
+		writeObject(fmt.Sprintf("<< /Length %d >>", page.content.Len()), page.content.Bytes())
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogID, xrefOffset)
+
+	return buf.WriteTo(w)
+}
+
+// New returns a new, empty document with the given page size in points.
+func New(width float64, height float64) *Document {
+	return &Document{
+		width:  width,
+		height: height,
+	}
+}
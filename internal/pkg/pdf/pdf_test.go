@@ -0,0 +1,110 @@
+package pdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/pdf"
+	"github.com/stretchr/testify/require"
+)
+
+// requireValidXref checks that every object is at the offset listed in the
+// cross-reference table.
+func requireValidXref(t *testing.T, content []byte) {
+	startXref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(content)
+	require.NotNil(t, startXref)
+
+	xrefOffset, err := strconv.Atoi(string(startXref[1]))
+	require.Nil(t, err)
+	require.True(t, bytes.HasPrefix(content[xrefOffset:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(content[xrefOffset:], -1)
+	require.NotEmpty(t, entries)
+
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.Nil(t, err)
+		require.True(t, bytes.HasPrefix(content[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))))
+	}
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+
+	page := doc.AddPage()
+	page.Text(50, 50, 12, true, "Timesheet (draft)")
+	page.TextRight(545, 50, 10, false, "Größe €")
+	page.Line(50, 60, 545, 60, 0.5)
+	page.FillRect(50, 70, 100, 20, 0.9)
+
+	doc.AddPage().Text(50, 50, 10, false, "Second page")
+
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+	require.Nil(t, err)
+
+	content := buf.Bytes()
+	require.True(t, bytes.HasPrefix(content, []byte("%PDF-1.4\n")))
+	require.Contains(t, string(content), `(Timesheet \(draft\)) Tj`)
+	require.Contains(t, string(content), "(Gr\xf6\xdfe \x80) Tj")
+	require.Contains(t, string(content), "/Count 2")
+	require.Contains(t, string(content), "0 0 595.28 841.89")
+
+	requireValidXref(t, content)
+}
+
+func TestDocument_AddImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{A: 0})
+
+	var encoded bytes.Buffer
+	require.Nil(t, png.Encode(&encoded, img))
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+
+	logo, err := doc.AddImage(&encoded)
+	require.Nil(t, err)
+	require.Equal(t, 2, logo.Width)
+	require.Equal(t, 1, logo.Height)
+
+	doc.AddPage().Image(logo, 50, 50, 20, 10)
+	doc.AddPage()
+
+	var buf bytes.Buffer
+	_, err = doc.WriteTo(&buf)
+	require.Nil(t, err)
+
+	content := buf.String()
+	require.Contains(t, content, "/Subtype /Image /Width 2 /Height 1")
+	require.Contains(t, content, "/Im1 Do")
+	require.Equal(t, 1, strings.Count(content, "/XObject << /Im1 5 0 R >>"))
+
+	requireValidXref(t, buf.Bytes())
+}
+
+func TestDocument_AddImage_Invalid(t *testing.T) {
+	_, err := pdf.New(pdf.A4Width, pdf.A4Height).AddImage(strings.NewReader("not an image"))
+	require.Error(t, err)
+}
+
+func TestTextWidth(t *testing.T) {
+	require.InDelta(t, 5.56, pdf.TextWidth("0", 10, false), 0.001)
+	require.InDelta(t, 6.11, pdf.TextWidth("b", 10, true), 0.001)
+	require.InDelta(t, 2.22, pdf.TextWidth("i", 10, false), 0.001)
+}
+
+func TestTruncateText(t *testing.T) {
+	require.Equal(t, "short", pdf.TruncateText("short", 100, 10, false))
+
+	truncated := pdf.TruncateText("a very long summary of the entry", 60, 10, false)
+	require.True(t, strings.HasSuffix(truncated, "..."))
+	require.LessOrEqual(t, pdf.TextWidth(truncated, 10, false), 60.0)
+}
@@ -0,0 +1,228 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// StageFilter is the name of the stage dropping the entries not matching
+	// the filters.
+	StageFilter string = "filter"
+	// StageExtract is the name of the stage extracting the tasks of the
+	// entries by the task pattern, like from their tags.
+	StageExtract string = "extract"
+	// StageEnrich is the name of the stage looking up additional data of the
+	// entries in external services, like Jira.
+	StageEnrich string = "enrich"
+	// StageMap is the name of the stage applying the mappings on the entries.
+	StageMap string = "map"
+	// StageSplit is the name of the stage splitting the entries, like the
+	// reallocations.
+	StageSplit string = "split"
+	// StageAggregate is the name of the stage combining the consecutive short
+	// entries, like pomodoros.
+	StageAggregate string = "aggregate"
+	// StageMerge is the name of the stage merging the entries having the same
+	// key.
+	StageMerge string = "merge"
+	// StageDistribute is the name of the stage synthesizing the start time of
+	// the entries having only a daily total.
+	StageDistribute string = "distribute"
+	// StageRound is the name of the stage rounding the durations of the
+	// entries.
+	StageRound string = "round"
+	// StageValidate is the name of the stage validating the entries before
+	// uploading them.
+	StageValidate string = "validate"
+)
+
+var (
+	// ErrTransform wraps the errors returned by the stages.
+	ErrTransform = errors.New("failed to transform entries")
+	// ErrUnknownStage returns when the referenced stage is not part of the
+	// pipeline.
+	ErrUnknownStage = errors.New("unknown stage")
+	// ErrDuplicateStage returns when a stage with the same name is already
+	// part of the pipeline.
+	ErrDuplicateStage = errors.New("duplicate stage")
+)
+
+// Transformer specifies the functions of a pipeline stage transforming the
+// entries between fetching and uploading them.
+type Transformer interface {
+	// Name returns the unique name of the stage, used to reference the stage
+	// when extending or reordering the pipeline.
+	Name() string
+	// Transform returns the transformed entries. The transformer must not
+	// modify the passed entries in place.
+	Transform(ctx context.Context, entries worklog.Entries) (worklog.Entries, error)
+}
+
+// TransformFunc is the signature of the function transforming the entries.
+type TransformFunc func(ctx context.Context, entries worklog.Entries) (worklog.Entries, error)
+
+type funcTransformer struct {
+	name      string
+	transform TransformFunc
+}
+
+func (t *funcTransformer) Name() string {
+	return t.name
+}
+
+func (t *funcTransformer) Transform(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	return t.transform(ctx, entries)
+}
+
+// NewTransformer returns a new transformer named name using the transform
+// function.
+func NewTransformer(name string, transform TransformFunc) Transformer {
+	return &funcTransformer{
+		name:      name,
+		transform: transform,
+	}
+}
+
+// Pipeline is the ordered list of stages transforming the entries.
+type Pipeline struct {
+	stages []Transformer
+}
+
+// indexOf returns the index of the stage named name or -1 if the stage is
+// not part of the pipeline.
+func (p *Pipeline) indexOf(name string) int {
+	for i, stage := range p.stages {
+		if stage.Name() == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// insert inserts the stage at the given index if no stage with the same name
+// is part of the pipeline.
+func (p *Pipeline) insert(index int, stage Transformer) error {
+	if p.indexOf(stage.Name()) != -1 {
+		return fmt.Errorf("%v: %s", ErrDuplicateStage, stage.Name())
+	}
+
+	p.stages = append(p.stages, nil)
+	copy(p.stages[index+1:], p.stages[index:])
+	p.stages[index] = stage
+
+	return nil
+}
+
+// Names returns the names of the stages in the order of running them.
+func (p *Pipeline) Names() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, stage := range p.stages {
+		names = append(names, stage.Name())
+	}
+
+	return names
+}
+
+// Append adds the stage to the end of the pipeline.
+func (p *Pipeline) Append(stage Transformer) error {
+	return p.insert(len(p.stages), stage)
+}
+
+// InsertBefore adds the stage before the stage named name.
+func (p *Pipeline) InsertBefore(name string, stage Transformer) error {
+	index := p.indexOf(name)
+	if index == -1 {
+		return fmt.Errorf("%v: %s", ErrUnknownStage, name)
+	}
+
+	return p.insert(index, stage)
+}
+
+// InsertAfter adds the stage after the stage named name.
+func (p *Pipeline) InsertAfter(name string, stage Transformer) error {
+	index := p.indexOf(name)
+	if index == -1 {
+		return fmt.Errorf("%v: %s", ErrUnknownStage, name)
+	}
+
+	return p.insert(index+1, stage)
+}
+
+// Replace replaces the stage named name by the stage.
+func (p *Pipeline) Replace(name string, stage Transformer) error {
+	index := p.indexOf(name)
+	if index == -1 {
+		return fmt.Errorf("%v: %s", ErrUnknownStage, name)
+	}
+
+	if other := p.indexOf(stage.Name()); other != -1 && other != index {
+		return fmt.Errorf("%v: %s", ErrDuplicateStage, stage.Name())
+	}
+
+	p.stages[index] = stage
+	return nil
+}
+
+// Remove removes the stage named name. Removing a stage which is not part of
+// the pipeline is not an error.
+func (p *Pipeline) Remove(name string) {
+	if index := p.indexOf(name); index != -1 {
+		p.stages = append(p.stages[:index], p.stages[index+1:]...)
+	}
+}
+
+// Reorder sets the order of the stages to the order of the names. The stages
+// not listed are removed from the pipeline.
+func (p *Pipeline) Reorder(names []string) error {
+	stages := make([]Transformer, 0, len(names))
+
+	for _, name := range names {
+		index := p.indexOf(name)
+		if index == -1 {
+			return fmt.Errorf("%v: %s", ErrUnknownStage, name)
+		}
+
+		for _, stage := range stages {
+			if stage.Name() == name {
+				return fmt.Errorf("%v: %s", ErrDuplicateStage, name)
+			}
+		}
+
+		stages = append(stages, p.stages[index])
+	}
+
+	p.stages = stages
+	return nil
+}
+
+// Run runs the stages in order, passing the entries returned by a stage to the
+// next one, and returns the entries returned by the last stage.
+func (p *Pipeline) Run(ctx context.Context, entries worklog.Entries) (worklog.Entries, error) {
+	var err error
+
+	for _, stage := range p.stages {
+		if entries, err = stage.Transform(ctx, entries); err != nil {
+			return nil, fmt.Errorf("%v: %s: %v", ErrTransform, stage.Name(), err)
+		}
+	}
+
+	return entries, nil
+}
+
+// New returns a new pipeline running the stages in the given order.
+func New(stages ...Transformer) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+
+	for _, stage := range stages {
+		if err := pipeline.Append(stage); err != nil {
+			return nil, err
+		}
+	}
+
+	return pipeline, nil
+}
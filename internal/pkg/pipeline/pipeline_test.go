@@ -0,0 +1,322 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/pipeline"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingTransformer returns a transformer appending its name to the
+// summary of every entry.
+func newRecordingTransformer(name string) pipeline.Transformer {
+	return pipeline.NewTransformer(name, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		transformed := make(worklog.Entries, 0, len(entries))
+		for _, entry := range entries {
+			entry.Summary += name
+			transformed = append(transformed, entry)
+		}
+
+		return transformed, nil
+	})
+}
+
+func TestPipeline_Run(t *testing.T) {
+	p, err := pipeline.New(newRecordingTransformer("a"), newRecordingTransformer("b"))
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{{Summary: "-"}})
+	require.Nil(t, err)
+	require.Equal(t, "-ab", entries[0].Summary)
+}
+
+func TestPipeline_Run_Error(t *testing.T) {
+	failing := pipeline.NewTransformer("failing", func(_ context.Context, _ worklog.Entries) (worklog.Entries, error) {
+		return nil, errors.New("boom")
+	})
+
+	p, err := pipeline.New(newRecordingTransformer("a"), failing)
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{{Summary: "-"}})
+	require.Nil(t, entries)
+	require.ErrorContains(t, err, pipeline.ErrTransform.Error())
+	require.ErrorContains(t, err, "failing: boom")
+}
+
+func TestPipeline_Extend(t *testing.T) {
+	p, err := pipeline.New(newRecordingTransformer("a"), newRecordingTransformer("c"))
+	require.Nil(t, err)
+
+	require.Nil(t, p.InsertBefore("a", newRecordingTransformer("0")))
+	require.Nil(t, p.InsertAfter("a", newRecordingTransformer("b")))
+	require.Nil(t, p.Append(newRecordingTransformer("d")))
+	require.Equal(t, []string{"0", "a", "b", "c", "d"}, p.Names())
+
+	require.Nil(t, p.Replace("0", newRecordingTransformer("x")))
+	p.Remove("d")
+	p.Remove("missing")
+	require.Equal(t, []string{"x", "a", "b", "c"}, p.Names())
+
+	require.ErrorContains(t, p.Append(newRecordingTransformer("a")), pipeline.ErrDuplicateStage.Error())
+	require.ErrorContains(t, p.Replace("x", newRecordingTransformer("a")), pipeline.ErrDuplicateStage.Error())
+	require.ErrorContains(t, p.InsertBefore("missing", newRecordingTransformer("y")), pipeline.ErrUnknownStage.Error())
+	require.ErrorContains(t, p.InsertAfter("missing", newRecordingTransformer("y")), pipeline.ErrUnknownStage.Error())
+}
+
+func TestPipeline_Reorder(t *testing.T) {
+	p, err := pipeline.New(newRecordingTransformer("a"), newRecordingTransformer("b"), newRecordingTransformer("c"))
+	require.Nil(t, err)
+
+	require.ErrorContains(t, p.Reorder([]string{"c", "missing"}), pipeline.ErrUnknownStage.Error())
+	require.ErrorContains(t, p.Reorder([]string{"c", "c"}), pipeline.ErrDuplicateStage.Error())
+	require.Equal(t, []string{"a", "b", "c"}, p.Names())
+
+	require.Nil(t, p.Reorder([]string{"c", "a"}))
+
+	entries, err := p.Run(context.Background(), worklog.Entries{{Summary: "-"}})
+	require.Nil(t, err)
+	require.Equal(t, "-ca", entries[0].Summary)
+}
+
+func TestStages(t *testing.T) {
+	start := time.Date(2021, 10, 2, 10, 0, 0, 0, time.UTC)
+
+	mapping := worklog.Mapping{Summary: "standup", Project: "Internal"}
+	require.Nil(t, mapping.Compile())
+
+	reallocation := worklog.Reallocation{Project: "^Internal$", Percentage: 50, ToProject: "Overhead"}
+	require.Nil(t, reallocation.Compile())
+
+	p, err := pipeline.New(
+		pipeline.Map([]worklog.Mapping{mapping}),
+		pipeline.Split([]worklog.Reallocation{reallocation}),
+		pipeline.Filter(&worklog.FilterOpts{Project: regexp.MustCompile("^Overhead$")}),
+		pipeline.Validate(&pipeline.ValidateOpts{}),
+	)
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{
+		{Summary: "standup", Start: start, BillableDuration: time.Hour},
+		{Summary: "coding", Start: start, BillableDuration: time.Hour},
+	})
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "Overhead", entries[0].Project.Name)
+	require.Equal(t, time.Minute*30, entries[0].BillableDuration)
+}
+
+func TestExtract_BeforeMap(t *testing.T) {
+	mapping := worklog.Mapping{Summary: "review", Task: "TASK-1"}
+	require.Nil(t, mapping.Compile())
+
+	p, err := pipeline.New(
+		pipeline.Extract(regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionTags),
+		pipeline.Map([]worklog.Mapping{mapping}),
+	)
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{
+		{Summary: "review", TaskHints: &worklog.TaskHints{Texts: []string{"feature/TASK-123"}}},
+		{Summary: "review", TaskHints: &worklog.TaskHints{Texts: []string{"main"}}},
+	})
+	require.Nil(t, err)
+
+	// The extracted tasks are not overridden by the mappings
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, "TASK-1", entries[1].Task.Name)
+}
+
+func TestValidate(t *testing.T) {
+	_, err := pipeline.Validate(&pipeline.ValidateOpts{}).Transform(context.Background(), worklog.Entries{
+		{Summary: "negative", BillableDuration: -time.Minute, UnbillableDuration: time.Minute * 2},
+	})
+
+	require.ErrorContains(t, err, pipeline.ErrInvalidEntry.Error())
+}
+
+func TestValidate_Corrections(t *testing.T) {
+	validate := pipeline.Validate(&pipeline.ValidateOpts{})
+
+	entries := worklog.Entries{
+		{Summary: "negative", BillableDuration: -time.Minute},
+		{Summary: "replacing", BillableDuration: time.Minute, Replaces: "1"},
+		{Summary: "deleting", Replaces: "2"},
+	}
+
+	validEntries, err := validate.Transform(context.Background(), entries)
+	require.Nil(t, err)
+	require.Equal(t, entries, validEntries)
+
+	_, err = validate.Transform(context.Background(), worklog.Entries{
+		{Summary: "mixed", BillableDuration: -time.Hour, UnbillableDuration: time.Minute},
+	})
+	require.ErrorContains(t, err, "positive duration of negative correction")
+
+	_, err = validate.Transform(context.Background(), worklog.Entries{
+		{Summary: "replacing", UnbillableDuration: -time.Minute, Replaces: "1"},
+	})
+	require.ErrorContains(t, err, "negative duration replacing 1")
+}
+
+func TestValidate_FutureEntries(t *testing.T) {
+	now := time.Date(2021, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	entries := worklog.Entries{
+		{Summary: "past", Start: now.Add(-time.Hour), BillableDuration: time.Hour},
+		{Summary: "skewed", Start: now.Add(time.Minute), BillableDuration: time.Hour},
+		{Summary: "future", Start: now.Add(time.Hour * 2), BillableDuration: time.Hour},
+		{Summary: "vacation", Start: now.AddDate(0, 0, 7), UnbillableDuration: time.Hour * 8, Absence: worklog.AbsenceVacation},
+	}
+
+	opts := &pipeline.ValidateOpts{
+		FutureEntries:   pipeline.FutureEntriesBlock,
+		FutureTolerance: time.Minute * 5,
+		Now:             func() time.Time { return now },
+	}
+
+	_, err := pipeline.Validate(opts).Transform(context.Background(), entries)
+	require.ErrorContains(t, err, "future:2021-10-02: starts in the future at 2021-10-02T14:00:00Z")
+
+	opts.FutureEntries = pipeline.FutureEntriesClamp
+	validEntries, err := pipeline.Validate(opts).Transform(context.Background(), entries)
+	require.Nil(t, err)
+	require.Len(t, validEntries, 4)
+	require.Equal(t, now.Add(time.Minute), validEntries[1].Start)
+	require.Equal(t, now, validEntries[2].Start)
+	require.Equal(t, []string{"start clamped from 2021-10-02T14:00:00Z"}, validEntries[2].Provenance.Transformations)
+	require.Equal(t, now.AddDate(0, 0, 7), validEntries[3].Start)
+	require.Equal(t, now.Add(time.Hour*2), entries[2].Start)
+
+	opts.FutureEntries = pipeline.FutureEntriesAllow
+	validEntries, err = pipeline.Validate(opts).Transform(context.Background(), entries)
+	require.Nil(t, err)
+	require.Equal(t, entries, validEntries)
+}
+
+func TestRound(t *testing.T) {
+	tests := map[string]struct {
+		opts               pipeline.RoundOpts
+		billable           time.Duration
+		unbillable         time.Duration
+		expectedBillable   time.Duration
+		expectedUnbillable time.Duration
+	}{
+		"not rounding": {
+			billable:           time.Second * 30,
+			unbillable:         time.Second * 29,
+			expectedBillable:   time.Second * 30,
+			expectedUnbillable: time.Second * 29,
+		},
+		"rounding up": {
+			opts:               pipeline.RoundOpts{RoundToClosestMinute: true},
+			unbillable:         time.Second * 30,
+			expectedUnbillable: time.Minute,
+		},
+		"rounding down": {
+			opts:       pipeline.RoundOpts{RoundToClosestMinute: true},
+			unbillable: time.Second * 29,
+		},
+		"rounding separately": {
+			opts:             pipeline.RoundOpts{RoundToClosestMinute: true},
+			billable:         time.Second * 30,
+			unbillable:       time.Second * 29,
+			expectedBillable: time.Minute,
+		},
+		"treating as billed": {
+			opts:             pipeline.RoundOpts{TreatDurationAsBilled: true},
+			billable:         time.Second * 30,
+			unbillable:       time.Second * 29,
+			expectedBillable: time.Second * 59,
+		},
+		"treating as billed before rounding": {
+			opts:             pipeline.RoundOpts{RoundToClosestMinute: true, TreatDurationAsBilled: true},
+			billable:         time.Second * 29,
+			unbillable:       time.Second * 29,
+			expectedBillable: time.Minute,
+		},
+		"rounding to nearest increment": {
+			opts:               pipeline.RoundOpts{Increment: time.Minute * 15},
+			billable:           time.Minute * 22,
+			unbillable:         time.Minute*7 + time.Second*30,
+			expectedBillable:   time.Minute * 15,
+			expectedUnbillable: time.Minute * 15,
+		},
+		"rounding up to increment": {
+			opts:               pipeline.RoundOpts{Increment: time.Minute * 15, Mode: pipeline.RoundUp},
+			billable:           time.Minute * 16,
+			unbillable:         time.Minute * 30,
+			expectedBillable:   time.Minute * 30,
+			expectedUnbillable: time.Minute * 30,
+		},
+		"rounding down to increment": {
+			opts:               pipeline.RoundOpts{Increment: time.Minute * 6, Mode: pipeline.RoundDown},
+			billable:           time.Minute * 11,
+			unbillable:         time.Minute * 5,
+			expectedBillable:   time.Minute * 6,
+			expectedUnbillable: 0,
+		},
+		"rounding correction up to increment": {
+			opts:             pipeline.RoundOpts{Increment: time.Minute * 15, Mode: pipeline.RoundUp},
+			billable:         -time.Minute * 10,
+			expectedBillable: -time.Minute * 15,
+		},
+		"treating as billed before rounding to increment": {
+			opts:             pipeline.RoundOpts{TreatDurationAsBilled: true, Increment: time.Minute * 15},
+			billable:         time.Minute * 5,
+			unbillable:       time.Minute * 5,
+			expectedBillable: time.Minute * 15,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := test.opts
+			entries := worklog.Entries{{BillableDuration: test.billable, UnbillableDuration: test.unbillable}}
+
+			rounded, err := pipeline.Round(&opts).Transform(context.Background(), entries)
+			require.Nil(t, err)
+			require.Equal(t, test.expectedBillable, rounded[0].BillableDuration)
+			require.Equal(t, test.expectedUnbillable, rounded[0].UnbillableDuration)
+			require.Equal(t, test.billable, entries[0].BillableDuration)
+		})
+	}
+}
+
+func TestMerge_BeforeRound(t *testing.T) {
+	entry := worklog.Entry{Summary: "standup", UnbillableDuration: time.Second * 20}
+
+	p, err := pipeline.New(pipeline.Merge(), pipeline.Round(&pipeline.RoundOpts{RoundToClosestMinute: true}))
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{entry, entry})
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, time.Minute, entries[0].UnbillableDuration)
+}
+
+func TestAggregate_BeforeMerge(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+	entry := worklog.Entry{Summary: "coding", Start: start, BillableDuration: time.Minute * 25}
+
+	next := entry
+	next.Start = start.Add(time.Minute * 30)
+
+	p, err := pipeline.New(pipeline.Aggregate(&worklog.AggregationOpts{
+		Mode:                worklog.AggregationPomodoro,
+		PomodoroMaxDuration: worklog.DefaultPomodoroMaxDuration,
+		PomodoroMaxBreak:    worklog.DefaultPomodoroMaxBreak,
+	}), pipeline.Merge())
+	require.Nil(t, err)
+
+	entries, err := p.Run(context.Background(), worklog.Entries{entry, next})
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "coding (2 pomodoros)", entries[0].Summary)
+	require.Equal(t, time.Minute*50, entries[0].BillableDuration)
+}
@@ -0,0 +1,258 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// FutureEntriesBlock makes the validation fail if any entry starts in the
+	// future.
+	FutureEntriesBlock string = "block"
+	// FutureEntriesClamp moves the start of the entries starting in the future
+	// to the current time.
+	FutureEntriesClamp string = "clamp"
+	// FutureEntriesAllow leaves the entries starting in the future intact.
+	FutureEntriesAllow string = "allow"
+
+	// RoundNearest rounds the durations to the nearest multiple of the
+	// increment; halves are rounded up.
+	RoundNearest string = "nearest"
+	// RoundUp rounds the durations up to the next multiple of the increment.
+	RoundUp string = "up"
+	// RoundDown rounds the durations down to the previous multiple of the
+	// increment.
+	RoundDown string = "down"
+)
+
+var (
+	// ErrInvalidEntry returns when an entry cannot be uploaded.
+	ErrInvalidEntry = errors.New("invalid entry")
+)
+
+// FutureEntriesPolicies lists the policies of handling the entries starting
+// in the future.
+var FutureEntriesPolicies = []string{FutureEntriesBlock, FutureEntriesClamp, FutureEntriesAllow}
+
+// RoundModes lists the directions of rounding the durations to the increment.
+var RoundModes = []string{RoundNearest, RoundUp, RoundDown}
+
+// Filter returns the stage dropping the entries not matching the filter
+// options.
+func Filter(opts *worklog.FilterOpts) Transformer {
+	return NewTransformer(StageFilter, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.FilterEntries(entries, opts), nil
+	})
+}
+
+// Extract returns the stage extracting the task of the entries from their
+// task hints by the regex, as set by the task extraction mode. The entries
+// having multiple tasks are split.
+func Extract(regex *regexp.Regexp, mode string) Transformer {
+	return NewTransformer(StageExtract, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.ExtractTasks(entries, regex, mode), nil
+	})
+}
+
+// Map returns the stage applying the first matching mapping on every entry.
+// The mappings must be compiled before calling it.
+func Map(mappings []worklog.Mapping) Transformer {
+	return NewTransformer(StageMap, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.ApplyMappings(entries, mappings), nil
+	})
+}
+
+// Split returns the stage applying the first matching reallocation on every
+// entry. The reallocations must be compiled before calling it.
+func Split(reallocations []worklog.Reallocation) Transformer {
+	return NewTransformer(StageSplit, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.ApplyReallocations(entries, reallocations), nil
+	})
+}
+
+// RoundOpts represents the options of the rounding stage.
+type RoundOpts struct {
+	// RoundToClosestMinute indicates to round the billed and unbilled duration
+	// separately to the closest minute.
+	// If the elapsed time is 30 seconds or more, the closest minute is the
+	// next minute, otherwise the previous one. In case the previous minute is
+	// 0 (zero), then 0 (zero) will be used for the billed and/or unbilled
+	// duration.
+	RoundToClosestMinute bool
+	// TreatDurationAsBilled indicates to use every time spent as billed.
+	TreatDurationAsBilled bool
+	// Increment indicates to round the billed and unbilled duration
+	// separately to a multiple of the increment, like 15 minutes, after
+	// rounding to the closest minute. If 0, the durations are not rounded to
+	// an increment.
+	Increment time.Duration
+	// Mode sets the direction of rounding to the increment. If not set,
+	// RoundNearest is used.
+	Mode string
+}
+
+// roundToClosestMinute returns the duration rounded to the closest minute.
+func roundToClosestMinute(d time.Duration) time.Duration {
+	return time.Second * time.Duration(math.Round(d.Minutes())*60)
+}
+
+// roundToIncrement returns the duration rounded to a multiple of the
+// increment in the direction of the mode. The negative durations of the
+// corrections are rounded by their absolute value, like the durations they
+// correct.
+func roundToIncrement(d time.Duration, increment time.Duration, mode string) time.Duration {
+	if d < 0 {
+		return -roundToIncrement(-d, increment, mode)
+	}
+
+	switch mode {
+	case RoundUp:
+		return (d + increment - 1) / increment * increment
+	case RoundDown:
+		return d / increment * increment
+	default:
+		return (d + increment/2) / increment * increment
+	}
+}
+
+// Merge returns the stage merging the entries having the same key. Since the
+// durations are summed by merging, the entries must be merged before rounding.
+func Merge() Transformer {
+	return NewTransformer(StageMerge, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.MergeEntries(entries), nil
+	})
+}
+
+// Aggregate returns the stage combining the consecutive pomodoros of the same
+// task, as set by the options. The entries must be aggregated before merging
+// them, otherwise the pomodoros would be merged without counting them.
+func Aggregate(opts *worklog.AggregationOpts) Transformer {
+	return NewTransformer(StageAggregate, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.AggregateEntries(entries, opts), nil
+	})
+}
+
+// Distribute returns the stage distributing the entries having only a daily
+// total across the working hours, as set by the options. The entries must be
+// merged before distributing them, otherwise the merged entries would overlap.
+func Distribute(opts *worklog.DistributionOpts) Transformer {
+	return NewTransformer(StageDistribute, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		return worklog.DistributeEntries(entries, opts), nil
+	})
+}
+
+// Round returns the stage treating the whole duration as billed and rounding
+// the durations to the closest minute and to the increment, as set by the
+// options. Running it before uploading ensures that every target uploads the
+// same durations.
+func Round(opts *RoundOpts) Transformer {
+	return NewTransformer(StageRound, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		roundedEntries := make(worklog.Entries, 0, len(entries))
+
+		for _, entry := range entries {
+			if opts.TreatDurationAsBilled && entry.UnbillableDuration != 0 {
+				entry.BillableDuration += entry.UnbillableDuration
+				entry.UnbillableDuration = 0
+				entry.AddTransformation("treated as billed")
+			}
+
+			if opts.RoundToClosestMinute {
+				billableDuration := roundToClosestMinute(entry.BillableDuration)
+				unbillableDuration := roundToClosestMinute(entry.UnbillableDuration)
+
+				if billableDuration != entry.BillableDuration || unbillableDuration != entry.UnbillableDuration {
+					entry.BillableDuration = billableDuration
+					entry.UnbillableDuration = unbillableDuration
+					entry.AddTransformation("rounded to closest minute")
+				}
+			}
+
+			if opts.Increment > 0 {
+				mode := opts.Mode
+				if mode == "" {
+					mode = RoundNearest
+				}
+
+				billableDuration := roundToIncrement(entry.BillableDuration, opts.Increment, mode)
+				unbillableDuration := roundToIncrement(entry.UnbillableDuration, opts.Increment, mode)
+
+				if billableDuration != entry.BillableDuration || unbillableDuration != entry.UnbillableDuration {
+					entry.BillableDuration = billableDuration
+					entry.UnbillableDuration = unbillableDuration
+					entry.AddTransformation(fmt.Sprintf("rounded %s to %s", mode, opts.Increment))
+				}
+			}
+
+			roundedEntries = append(roundedEntries, entry)
+		}
+
+		return roundedEntries, nil
+	})
+}
+
+// ValidateOpts represents the options of the validation stage.
+type ValidateOpts struct {
+	// FutureEntries sets how the entries starting in the future are handled,
+	// which usually indicates a misconfigured timezone of the source. If not
+	// set, the entries are allowed.
+	FutureEntries string
+	// FutureTolerance is the tolerated clock skew between the source and the
+	// local clock. Entries starting within the tolerance are not in the future.
+	FutureTolerance time.Duration
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Validate returns the stage returning ErrInvalidEntry if any entry has
+// negative billable or total duration, which cannot be uploaded. Corrections
+// are invalid if the durations of a negative correction are not all negative
+// or zero, or the replacement of a worklog has a negative duration. Incomplete
+// entries are not invalid, those are reported separately by the worklog.
+// Entries starting in the future are blocked or clamped as set by the options,
+// except the absences, which can be planned ahead.
+func Validate(opts *ValidateOpts) Transformer {
+	return NewTransformer(StageValidate, func(_ context.Context, entries worklog.Entries) (worklog.Entries, error) {
+		now := time.Now
+		if opts.Now != nil {
+			now = opts.Now
+		}
+
+		currentTime := now()
+		validEntries := make(worklog.Entries, 0, len(entries))
+
+		for _, entry := range entries {
+			switch {
+			case entry.Replaces != "":
+				if entry.BillableDuration < 0 || entry.UnbillableDuration < 0 {
+					return nil, fmt.Errorf("%v: %s: negative duration replacing %s", ErrInvalidEntry, entry.Key(), entry.Replaces)
+				}
+			case entry.IsCorrection():
+				if entry.BillableDuration > 0 || entry.UnbillableDuration > 0 {
+					return nil, fmt.Errorf("%v: %s: positive duration of negative correction", ErrInvalidEntry, entry.Key())
+				}
+			case entry.BillableDuration < 0 || entry.BillableDuration+entry.UnbillableDuration < 0:
+				return nil, fmt.Errorf("%v: %s: negative duration", ErrInvalidEntry, entry.Key())
+			}
+
+			if !entry.IsAbsence() && entry.Start.After(currentTime.Add(opts.FutureTolerance)) {
+				switch opts.FutureEntries {
+				case FutureEntriesBlock:
+					return nil, fmt.Errorf("%v: %s: starts in the future at %s", ErrInvalidEntry, entry.Key(), entry.Start.Format(time.RFC3339))
+				case FutureEntriesClamp:
+					entry.AddTransformation("start clamped from %s", entry.Start.Format(time.RFC3339))
+					entry.Start = currentTime.In(entry.Start.Location())
+				}
+			}
+
+			validEntries = append(validEntries, entry)
+		}
+
+		return validEntries, nil
+	})
+}
@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// Draft is the JSON Schema draft the generated schemas are conforming.
+	Draft string = "https://json-schema.org/draft/2020-12/schema"
+	// DurationDescription is set as the description of time.Duration fields,
+	// as those are serialized as nanoseconds.
+	DurationDescription string = "duration in nanoseconds"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Schema represents a JSON Schema document or a subschema of it.
+// Only the keywords used by minutes are defined.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+// Document returns the schema as a standalone document, setting the draft,
+// ID and title.
+func (s *Schema) Document(id string, title string) *Schema {
+	s.Schema = Draft
+	s.ID = id
+	s.Title = title
+	return s
+}
+
+// Reflect generates the schema of the given value's type. The JSON field names
+// are taken from the `json` struct tags; fields without `omitempty` are marked
+// as required.
+func Reflect(v interface{}) *Schema {
+	return reflectType(reflect.TypeOf(v), map[reflect.Type]bool{})
+}
+
+func reflectType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Description: DurationDescription}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reflectType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reflectType(t.Elem(), visiting)}
+	case reflect.Struct:
+		// Recursive types are not expanded to avoid infinite recursion
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{},
+		}
+
+		reflectFields(t, schema, visiting)
+		sort.Strings(schema.Required)
+
+		return schema
+	default:
+		// Interfaces and other types can hold any value
+		return &Schema{}
+	}
+}
+
+func reflectFields(t reflect.Type, schema *Schema, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without explicit name are flattened like
+		// encoding/json does
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct {
+				reflectFields(fieldType, schema, visiting)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = reflectType(field.Type, visiting)
+
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// FromFlags generates the schema of a config file that can set the given
+// flags. Additional properties are allowed, as some config options are not
+// covered by flags.
+func FromFlags(flags *pflag.FlagSet) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: true,
+	}
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+
+		property := &Schema{
+			Description: flag.Usage,
+		}
+
+		switch flag.Value.Type() {
+		case "bool":
+			property.Type = "boolean"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			property.Type = "integer"
+		case "float32", "float64":
+			property.Type = "number"
+		case "stringSlice", "stringArray", "intSlice", "boolSlice", "durationSlice":
+			property.Type = "array"
+			property.Items = &Schema{Type: "string"}
+		case "stringToString", "stringToInt":
+			property.Type = "object"
+		default:
+			property.Type = "string"
+		}
+
+		if flag.DefValue != "" && flag.DefValue != "[]" && flag.DefValue != "0" && flag.DefValue != "false" {
+			property.Default = flag.DefValue
+		}
+
+		schema.Properties[flag.Name] = property
+	})
+
+	return schema
+}
@@ -0,0 +1,78 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/schema"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+type embedded struct {
+	ID string `json:"id"`
+}
+
+type testStruct struct {
+	embedded
+	Name     string         `json:"name"`
+	Tags     []string       `json:"tags,omitempty"`
+	Labels   map[string]int `json:"labels,omitempty"`
+	Start    time.Time      `json:"start"`
+	Duration time.Duration  `json:"duration"`
+	Ratio    float64        `json:"ratio,omitempty"`
+	Enabled  bool           `json:"enabled"`
+	Next     *testStruct    `json:"next,omitempty"`
+	Ignored  string         `json:"-"`
+	Any      interface{}    `json:"any,omitempty"`
+	Untagged map[string]string
+}
+
+func TestReflect(t *testing.T) {
+	s := schema.Reflect(&testStruct{})
+
+	require.Equal(t, "object", s.Type)
+	require.Equal(t, []string{"Untagged", "duration", "enabled", "id", "name", "start"}, s.Required)
+
+	require.Equal(t, &schema.Schema{Type: "string"}, s.Properties["id"])
+	require.Equal(t, &schema.Schema{Type: "string"}, s.Properties["name"])
+	require.Equal(t, &schema.Schema{Type: "array", Items: &schema.Schema{Type: "string"}}, s.Properties["tags"])
+	require.Equal(t, &schema.Schema{Type: "object", AdditionalProperties: &schema.Schema{Type: "integer"}}, s.Properties["labels"])
+	require.Equal(t, &schema.Schema{Type: "string", Format: "date-time"}, s.Properties["start"])
+	require.Equal(t, &schema.Schema{Type: "integer", Description: schema.DurationDescription}, s.Properties["duration"])
+	require.Equal(t, &schema.Schema{Type: "number"}, s.Properties["ratio"])
+	require.Equal(t, &schema.Schema{Type: "boolean"}, s.Properties["enabled"])
+	require.Equal(t, &schema.Schema{Type: "object"}, s.Properties["next"])
+	require.Equal(t, &schema.Schema{}, s.Properties["any"])
+
+	require.NotContains(t, s.Properties, "Ignored")
+}
+
+func TestSchema_Document(t *testing.T) {
+	s := schema.Reflect("").Document("https://example.com/test.schema.json", "test")
+
+	require.Equal(t, &schema.Schema{
+		Schema: schema.Draft,
+		ID:     "https://example.com/test.schema.json",
+		Title:  "test",
+		Type:   "string",
+	}, s)
+}
+
+func TestFromFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("source", "", "set the source")
+	flags.String("url", "https://example.com", "set the URL")
+	flags.Int("workspace", 0, "set the workspace")
+	flags.Bool("dry-run", false, "do not sync")
+	flags.StringSlice("columns", []string{}, "set the columns")
+
+	s := schema.FromFlags(flags)
+
+	require.Equal(t, true, s.AdditionalProperties)
+	require.Equal(t, &schema.Schema{Type: "string", Description: "set the source"}, s.Properties["source"])
+	require.Equal(t, &schema.Schema{Type: "string", Description: "set the URL", Default: "https://example.com"}, s.Properties["url"])
+	require.Equal(t, &schema.Schema{Type: "integer", Description: "set the workspace"}, s.Properties["workspace"])
+	require.Equal(t, &schema.Schema{Type: "boolean", Description: "do not sync"}, s.Properties["dry-run"])
+	require.Equal(t, &schema.Schema{Type: "array", Description: "set the columns", Items: &schema.Schema{Type: "string"}}, s.Properties["columns"])
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const plistHeader string = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+`
+
+// launchdManager registers the service as a launchd user agent.
+type launchdManager struct {
+	baseManager
+}
+
+func escapeXML(s string) string {
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
+
+func (m *launchdManager) Path() string {
+	return filepath.Join(m.opts.HomeDir, "Library", "LaunchAgents", m.opts.Label+".plist")
+}
+
+func (m *launchdManager) Definition() ([]byte, error) {
+	var definition bytes.Buffer
+
+	definition.WriteString(plistHeader)
+	definition.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	fmt.Fprintf(&definition, "\t<key>Label</key>\n\t<string>%s</string>\n", escapeXML(m.opts.Label))
+
+	definition.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{m.opts.Executable}, m.opts.Arguments...) {
+		fmt.Fprintf(&definition, "\t\t<string>%s</string>\n", escapeXML(arg))
+	}
+	definition.WriteString("\t</array>\n")
+
+	definition.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	definition.WriteString("\t<key>KeepAlive</key>\n\t<true/>\n")
+
+	logPath := escapeXML(filepath.Join(m.opts.LogDir, m.opts.Name+".log"))
+	fmt.Fprintf(&definition, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", logPath)
+	fmt.Fprintf(&definition, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", logPath)
+
+	definition.WriteString("</dict>\n</plist>\n")
+
+	return definition.Bytes(), nil
+}
+
+func (m *launchdManager) Install(ctx context.Context) error {
+	definition, err := m.Definition()
+	if err != nil {
+		return err
+	}
+
+	if err = m.write(m.Path(), definition); err != nil {
+		return err
+	}
+
+	_, err = m.run(ctx, "launchctl", "load", "-w", m.Path())
+	return err
+}
+
+func (m *launchdManager) Uninstall(ctx context.Context) error {
+	if m.isInstalled(m.Path()) {
+		if _, err := m.run(ctx, "launchctl", "unload", "-w", m.Path()); err != nil {
+			return err
+		}
+	}
+
+	return m.remove(m.Path())
+}
+
+func (m *launchdManager) Status(ctx context.Context) (string, error) {
+	if !m.isInstalled(m.Path()) {
+		return StatusNotInstalled, nil
+	}
+
+	out, err := m.run(ctx, "launchctl", "list", m.opts.Label)
+	if err != nil {
+		return "not loaded", nil
+	}
+
+	if strings.Contains(string(out), `"PID" =`) {
+		return "running", nil
+	}
+
+	return "loaded", nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// StatusNotInstalled is returned when the service definition does not
+	// exist.
+	StatusNotInstalled string = "not installed"
+)
+
+var (
+	// ErrUnsupportedPlatform returns when no service manager is implemented
+	// for the operating system.
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+	// ErrNoExecutable returns when the executable of the service is not set.
+	ErrNoExecutable = errors.New("no executable provided")
+)
+
+// Opts represents the options of the service managers.
+type Opts struct {
+	// Name is the name of the service, like "minutes".
+	Name        string
+	Description string
+	// Label is the reverse-DNS label used by launchd. Defaults to
+	// "io.github.gabor-boros.<name>".
+	Label      string
+	Executable string
+	Arguments  []string
+	// HomeDir is the home directory of the user, the service definitions are
+	// written relative to it.
+	HomeDir string
+	// LogDir is the directory of the log files, if the service manager is not
+	// collecting the logs.
+	LogDir             string
+	Timeout            time.Duration
+	CommandCtxExecutor func(ctx context.Context, name string, arg ...string) *exec.Cmd
+}
+
+// Manager specifies the functions used to register minutes as a background
+// service using the operating system's service manager.
+type Manager interface {
+	// Path returns the path of the service definition file.
+	Path() string
+	// Definition returns the content of the service definition file.
+	Definition() ([]byte, error)
+	// Install writes the service definition, then registers and starts the
+	// service.
+	Install(ctx context.Context) error
+	// Uninstall stops and unregisters the service, then removes the service
+	// definition.
+	Uninstall(ctx context.Context) error
+	// Status returns the status of the service as reported by the service
+	// manager.
+	Status(ctx context.Context) (string, error)
+}
+
+type baseManager struct {
+	opts *Opts
+}
+
+// run executes the service manager command and returns its combined output.
+func (m *baseManager) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+
+	out, err := m.opts.CommandCtxExecutor(ctxWithTimeout, name, args...).CombinedOutput() // #nosec G204
+	if err != nil {
+		return out, fmt.Errorf("%s failed: %v: %s", name, err, out)
+	}
+
+	return out, nil
+}
+
+// write writes the definition to the path, creating the parent directories.
+func (m *baseManager) write(path string, definition []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, definition, 0600)
+}
+
+// remove removes the definition. Removing a not existing file is not an
+// error.
+func (m *baseManager) remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// isInstalled returns true if the definition file exists.
+func (m *baseManager) isInstalled(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// NewManager returns the service manager of the given operating system, using
+// the runtime.GOOS naming.
+func NewManager(goos string, opts *Opts) (Manager, error) {
+	if opts.Executable == "" {
+		return nil, ErrNoExecutable
+	}
+
+	if opts.Label == "" {
+		opts.Label = "io.github.gabor-boros." + opts.Name
+	}
+
+	if opts.Description == "" {
+		opts.Description = opts.Name
+	}
+
+	if opts.LogDir == "" {
+		opts.LogDir = opts.HomeDir
+	}
+
+	base := baseManager{opts: opts}
+
+	switch goos {
+	case "linux":
+		return &systemdManager{baseManager: base}, nil
+	case "darwin":
+		return &launchdManager{baseManager: base}, nil
+	case "windows":
+		return &windowsManager{baseManager: base}, nil
+	default:
+		return nil, fmt.Errorf("%v: %s", ErrUnsupportedPlatform, goos)
+	}
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/service"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockedExitCode int
+	mockedStdout   string
+	mockedCommands []string
+)
+
+func mockedExecCommand(_ context.Context, command string, args ...string) *exec.Cmd {
+	mockedCommands = append(mockedCommands, strings.Join(append([]string{command}, args...), " "))
+
+	arguments := []string{"-test.run=TestExecCommandHelper", "--", command}
+	arguments = append(arguments, args...)
+	cmd := exec.Command(os.Args[0], arguments...)
+
+	cmd.Env = []string{"GO_TEST_HELPER_PROCESS=1",
+		"STDOUT=" + mockedStdout,
+		"EXIT_CODE=" + strconv.Itoa(mockedExitCode),
+	}
+
+	return cmd
+}
+
+// TestExecCommandHelper is a helper test case that will be called by `mockedExecCommand`.
+// This workaround is needed to be able to "mock" system calls.
+func TestExecCommandHelper(t *testing.T) {
+	// Not executed by the mocked command function, so return
+	if os.Getenv("GO_TEST_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	_, _ = os.Stdout.WriteString(os.Getenv("STDOUT"))
+	exitCode, _ := strconv.Atoi(os.Getenv("EXIT_CODE"))
+	os.Exit(exitCode)
+}
+
+func newTestManager(t *testing.T, goos string) (service.Manager, string) {
+	mockedExitCode = 0
+	mockedStdout = ""
+	mockedCommands = nil
+
+	homeDir := t.TempDir()
+
+	manager, err := service.NewManager(goos, &service.Opts{
+		Name:               "minutes",
+		Description:        "Minutes server",
+		Executable:         "/usr/local/bin/minutes",
+		Arguments:          []string{"serve", "--config", "/home/user/my config.toml"},
+		HomeDir:            homeDir,
+		Timeout:            time.Second * 10,
+		CommandCtxExecutor: mockedExecCommand,
+	})
+	require.Nil(t, err)
+
+	return manager, homeDir
+}
+
+func TestNewManager(t *testing.T) {
+	_, err := service.NewManager("plan9", &service.Opts{Executable: "minutes"})
+	require.ErrorContains(t, err, service.ErrUnsupportedPlatform.Error())
+
+	_, err = service.NewManager("linux", &service.Opts{})
+	require.ErrorIs(t, err, service.ErrNoExecutable)
+}
+
+func TestSystemdManager(t *testing.T) {
+	manager, homeDir := newTestManager(t, "linux")
+
+	require.Equal(t, filepath.Join(homeDir, ".config", "systemd", "user", "minutes.service"), manager.Path())
+
+	definition, err := manager.Definition()
+	require.Nil(t, err)
+	require.Contains(t, string(definition), "Description=Minutes server\n")
+	require.Contains(t, string(definition), `ExecStart="/usr/local/bin/minutes" "serve" "--config" "/home/user/my config.toml"`+"\n")
+	require.Contains(t, string(definition), "WantedBy=default.target\n")
+
+	status, err := manager.Status(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, service.StatusNotInstalled, status)
+
+	require.Nil(t, manager.Install(context.Background()))
+	require.FileExists(t, manager.Path())
+	require.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now minutes.service",
+	}, mockedCommands)
+
+	mockedStdout = "active\n"
+	status, err = manager.Status(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "active", status)
+
+	mockedExitCode = 3
+	mockedStdout = "inactive\n"
+	status, err = manager.Status(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "inactive", status)
+
+	mockedExitCode = 0
+	mockedCommands = nil
+	require.Nil(t, manager.Uninstall(context.Background()))
+	require.NoFileExists(t, manager.Path())
+	require.Equal(t, []string{
+		"systemctl --user disable --now minutes.service",
+		"systemctl --user daemon-reload",
+	}, mockedCommands)
+}
+
+func TestLaunchdManager(t *testing.T) {
+	manager, homeDir := newTestManager(t, "darwin")
+
+	require.Equal(t, filepath.Join(homeDir, "Library", "LaunchAgents", "io.github.gabor-boros.minutes.plist"), manager.Path())
+
+	definition, err := manager.Definition()
+	require.Nil(t, err)
+	require.Contains(t, string(definition), "<string>io.github.gabor-boros.minutes</string>")
+	require.Contains(t, string(definition), "\t\t<string>/usr/local/bin/minutes</string>\n\t\t<string>serve</string>\n")
+	require.Contains(t, string(definition), "<string>"+filepath.Join(homeDir, "minutes.log")+"</string>")
+
+	require.Nil(t, manager.Install(context.Background()))
+	require.FileExists(t, manager.Path())
+	require.Equal(t, []string{"launchctl load -w " + manager.Path()}, mockedCommands)
+
+	mockedStdout = "{\n\t\"PID\" = 1234;\n};"
+	status, err := manager.Status(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "running", status)
+
+	mockedCommands = nil
+	require.Nil(t, manager.Uninstall(context.Background()))
+	require.NoFileExists(t, manager.Path())
+	require.Equal(t, []string{"launchctl unload -w " + manager.Path()}, mockedCommands)
+}
+
+func TestWindowsManager(t *testing.T) {
+	manager, homeDir := newTestManager(t, "windows")
+
+	require.Equal(t, filepath.Join(homeDir, "AppData", "Roaming", "minutes", "minutes-service.cmd"), manager.Path())
+
+	definition, err := manager.Definition()
+	require.Nil(t, err)
+	require.Contains(t, string(definition), `"/usr/local/bin/minutes" "serve" "--config" "/home/user/my config.toml" >> `)
+
+	require.Nil(t, manager.Install(context.Background()))
+	require.FileExists(t, manager.Path())
+	require.Equal(t, []string{
+		`schtasks /Create /TN minutes /TR "` + manager.Path() + `" /SC ONLOGON /RL LIMITED /F`,
+		"schtasks /Run /TN minutes",
+	}, mockedCommands)
+
+	mockedStdout = "TaskName: \\minutes\r\nStatus:   Running\r\n"
+	status, err := manager.Status(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "running", status)
+
+	mockedCommands = nil
+	require.Nil(t, manager.Uninstall(context.Background()))
+	require.NoFileExists(t, manager.Path())
+	require.Equal(t, []string{
+		"schtasks /End /TN minutes",
+		"schtasks /Delete /TN minutes /F",
+	}, mockedCommands)
+}
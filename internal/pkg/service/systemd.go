@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// systemdManager registers the service as a systemd user unit.
+type systemdManager struct {
+	baseManager
+}
+
+// quoteSystemd quotes the argument as required by the ExecStart directive.
+func quoteSystemd(arg string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`, `$`, `$$`)
+	return `"` + replacer.Replace(arg) + `"`
+}
+
+func (m *systemdManager) unit() string {
+	return m.opts.Name + ".service"
+}
+
+func (m *systemdManager) Path() string {
+	return filepath.Join(m.opts.HomeDir, ".config", "systemd", "user", m.unit())
+}
+
+func (m *systemdManager) Definition() ([]byte, error) {
+	execStart := []string{quoteSystemd(m.opts.Executable)}
+	for _, arg := range m.opts.Arguments {
+		execStart = append(execStart, quoteSystemd(arg))
+	}
+
+	var definition bytes.Buffer
+
+	fmt.Fprintf(&definition, "[Unit]\n")
+	fmt.Fprintf(&definition, "Description=%s\n", m.opts.Description)
+	fmt.Fprintf(&definition, "After=network-online.target\n")
+	fmt.Fprintf(&definition, "Wants=network-online.target\n\n")
+	fmt.Fprintf(&definition, "[Service]\n")
+	fmt.Fprintf(&definition, "Type=simple\n")
+	fmt.Fprintf(&definition, "ExecStart=%s\n", strings.Join(execStart, " "))
+	fmt.Fprintf(&definition, "Restart=on-failure\n")
+	fmt.Fprintf(&definition, "RestartSec=10\n\n")
+	fmt.Fprintf(&definition, "[Install]\n")
+	fmt.Fprintf(&definition, "WantedBy=default.target\n")
+
+	return definition.Bytes(), nil
+}
+
+func (m *systemdManager) Install(ctx context.Context) error {
+	definition, err := m.Definition()
+	if err != nil {
+		return err
+	}
+
+	if err = m.write(m.Path(), definition); err != nil {
+		return err
+	}
+
+	if _, err = m.run(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+
+	_, err = m.run(ctx, "systemctl", "--user", "enable", "--now", m.unit())
+	return err
+}
+
+func (m *systemdManager) Uninstall(ctx context.Context) error {
+	if m.isInstalled(m.Path()) {
+		if _, err := m.run(ctx, "systemctl", "--user", "disable", "--now", m.unit()); err != nil {
+			return err
+		}
+	}
+
+	if err := m.remove(m.Path()); err != nil {
+		return err
+	}
+
+	_, err := m.run(ctx, "systemctl", "--user", "daemon-reload")
+	return err
+}
+
+func (m *systemdManager) Status(ctx context.Context) (string, error) {
+	if !m.isInstalled(m.Path()) {
+		return StatusNotInstalled, nil
+	}
+
+	// is-active exits with non-zero code if the unit is not active, but still
+	// prints the state
+	out, err := m.run(ctx, "systemctl", "--user", "is-active", m.unit())
+	if status := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0]); status != "" {
+		return status, nil
+	}
+
+	return "", err
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// windowsManager registers the service as a Task Scheduler task started at
+// logon. The task runs a wrapper script, which starts minutes and redirects
+// its output to the log file.
+type windowsManager struct {
+	baseManager
+}
+
+// quoteCmd quotes the argument for the cmd.exe command line.
+func quoteCmd(arg string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(arg, `"`, `""`), `%`, `%%`) + `"`
+}
+
+func (m *windowsManager) Path() string {
+	return filepath.Join(m.opts.HomeDir, "AppData", "Roaming", m.opts.Name, m.opts.Name+"-service.cmd")
+}
+
+func (m *windowsManager) Definition() ([]byte, error) {
+	command := []string{quoteCmd(m.opts.Executable)}
+	for _, arg := range m.opts.Arguments {
+		command = append(command, quoteCmd(arg))
+	}
+
+	logPath := filepath.Join(m.opts.LogDir, m.opts.Name+".log")
+
+	var definition bytes.Buffer
+
+	fmt.Fprintf(&definition, "@echo off\r\n")
+	fmt.Fprintf(&definition, "rem %s\r\n", m.opts.Description)
+	fmt.Fprintf(&definition, ":loop\r\n")
+	fmt.Fprintf(&definition, "%s >> %s 2>&1\r\n", strings.Join(command, " "), quoteCmd(logPath))
+	// Restart the server after a short delay if it exits, like other service
+	// managers do
+	fmt.Fprintf(&definition, "timeout /t 10 /nobreak > nul\r\n")
+	fmt.Fprintf(&definition, "goto loop\r\n")
+
+	return definition.Bytes(), nil
+}
+
+func (m *windowsManager) Install(ctx context.Context) error {
+	definition, err := m.Definition()
+	if err != nil {
+		return err
+	}
+
+	if err = m.write(m.Path(), definition); err != nil {
+		return err
+	}
+
+	if _, err = m.run(ctx, "schtasks", "/Create", "/TN", m.opts.Name, "/TR", quoteCmd(m.Path()), "/SC", "ONLOGON", "/RL", "LIMITED", "/F"); err != nil {
+		return err
+	}
+
+	_, err = m.run(ctx, "schtasks", "/Run", "/TN", m.opts.Name)
+	return err
+}
+
+func (m *windowsManager) Uninstall(ctx context.Context) error {
+	if m.isInstalled(m.Path()) {
+		// The task may not be running, so the error is ignored
+		_, _ = m.run(ctx, "schtasks", "/End", "/TN", m.opts.Name)
+
+		if _, err := m.run(ctx, "schtasks", "/Delete", "/TN", m.opts.Name, "/F"); err != nil {
+			return err
+		}
+	}
+
+	return m.remove(m.Path())
+}
+
+func (m *windowsManager) Status(ctx context.Context) (string, error) {
+	if !m.isInstalled(m.Path()) {
+		return StatusNotInstalled, nil
+	}
+
+	out, err := m.run(ctx, "schtasks", "/Query", "/TN", m.opts.Name, "/FO", "LIST")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if status, found := strings.CutPrefix(strings.TrimSpace(line), "Status:"); found {
+			return strings.ToLower(strings.TrimSpace(status)), nil
+		}
+	}
+
+	return "unknown", nil
+}
@@ -0,0 +1,280 @@
+package staging
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// Source is the source name of the staged entries.
+	Source string = "companion"
+	// MaxBodySize is the maximum accepted size of a staging request body.
+	MaxBodySize int64 = 1 << 16
+)
+
+var (
+	// ErrUnauthorized returns when the staging request's bearer token is
+	// missing or not matching the configured one.
+	ErrUnauthorized = errors.New("invalid staging token")
+	// ErrInvalidEntry returns when the staged entry is missing a field or has
+	// an invalid value.
+	ErrInvalidEntry = errors.New("invalid staged entry")
+)
+
+// Request represents a single entry sent by a companion, like a browser
+// extension or a launcher script.
+type Request struct {
+	Task string `json:"task"`
+	// Duration is the spent time, like "1h30m".
+	Duration string `json:"duration"`
+	Note     string `json:"note"`
+	// Start is the start time of the entry. If not set, the entry ends at the
+	// time of staging.
+	Start   time.Time `json:"start"`
+	Project string    `json:"project"`
+	Client  string    `json:"client"`
+}
+
+// ParseOneLiner parses the one-liner description of an entry, like
+// "CPT-2014 1h30m fixed the flaky test", into a request. The task and the
+// duration are the first two words in any order, followed by the note.
+func ParseOneLiner(line string) (*Request, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: task and duration must be set: %q", ErrInvalidEntry, line)
+	}
+
+	request := &Request{Note: strings.Join(fields[2:], " ")}
+
+	if _, err := time.ParseDuration(fields[0]); err == nil {
+		request.Duration, request.Task = fields[0], fields[1]
+	} else {
+		request.Task, request.Duration = fields[0], fields[1]
+	}
+
+	return request, nil
+}
+
+// Entry validates the request and returns the entry staged at the given time.
+// The note is used as summary; if not set, the task is used instead.
+func (r *Request) Entry(stagedAt time.Time) (*worklog.Entry, error) {
+	task := strings.TrimSpace(r.Task)
+	if task == "" {
+		return nil, fmt.Errorf("%w: task must be set", ErrInvalidEntry)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(r.Duration))
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("%w: invalid duration %q", ErrInvalidEntry, r.Duration)
+	}
+
+	start := r.Start
+	if start.IsZero() {
+		start = stagedAt.Add(-duration)
+	}
+
+	summary := strings.TrimSpace(r.Note)
+	if summary == "" {
+		summary = task
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &worklog.Entry{
+		Client:           worklog.IDNameField{ID: r.Client, Name: r.Client},
+		Project:          worklog.IDNameField{ID: r.Project, Name: r.Project},
+		Task:             worklog.IDNameField{ID: task, Name: task},
+		Summary:          summary,
+		Notes:            r.Note,
+		Start:            start.Local(),
+		BillableDuration: duration,
+		Provenance: worklog.Provenance{
+			Source:    Source,
+			SourceIDs: []string{id},
+			FetchedAt: stagedAt,
+		},
+	}, nil
+}
+
+// newID returns a random ID for the staged entry.
+func newID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// Stage keeps the staged entries in the store until they are uploaded, so the
+// staged entries are not lost between the runs or when the server restarts.
+type Stage struct {
+	mu    sync.Mutex
+	store storage.Store
+	key   string
+}
+
+func (s *Stage) load(ctx context.Context) (worklog.Entries, error) {
+	var entries worklog.Entries
+
+	data, err := s.store.Get(ctx, s.key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entries, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *Stage) save(ctx context.Context, entries worklog.Entries) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, s.key, data)
+}
+
+// Add stages the entry.
+func (s *Stage) Add(ctx context.Context, entry worklog.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.save(ctx, append(entries, entry))
+}
+
+// Entries returns the staged entries.
+func (s *Stage) Entries(ctx context.Context) (worklog.Entries, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(ctx)
+}
+
+// Remove removes the staged entries having any of the given source IDs, like
+// the IDs of the uploaded entries.
+func (s *Stage) Remove(ctx context.Context, sourceIDs []string) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	removedIDs := make(map[string]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		removedIDs[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var kept worklog.Entries
+	for _, entry := range entries {
+		isRemoved := false
+		for _, id := range entry.Provenance.SourceIDs {
+			isRemoved = isRemoved || removedIDs[id]
+		}
+
+		if !isRemoved {
+			kept = append(kept, entry)
+		}
+	}
+
+	return s.save(ctx, kept)
+}
+
+// NewStage returns a new stage persisting the staged entries in the store
+// under the key.
+func NewStage(store storage.Store, key string) *Stage {
+	return &Stage{
+		store: store,
+		key:   key,
+	}
+}
+
+// verifyToken returns ErrUnauthorized if the request's bearer token is not
+// matching the token.
+func verifyToken(header http.Header, token string) error {
+	requestToken, found := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(requestToken), []byte(token)) != 1 {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+// NewHandler returns an HTTP handler that accepts single entries sent by
+// companions, authenticated by the token sent as bearer token in the
+// "Authorization" header. The parsed entries are passed to stage, and the ID
+// of the staged entry is returned.
+func NewHandler(token string, stage func(entry worklog.Entry) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := verifyToken(r.Header, token); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var request Request
+		if err = json.Unmarshal(body, &request); err != nil {
+			http.Error(w, fmt.Sprintf("%v: %v", ErrInvalidEntry, err), http.StatusBadRequest)
+			return
+		}
+
+		entry, err := request.Entry(time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err = stage(*entry); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"id": entry.Provenance.SourceIDs[0],
+		})
+	})
+}
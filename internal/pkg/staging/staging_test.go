@@ -0,0 +1,168 @@
+package staging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func sendEntry(t *testing.T, token string, body []byte) (*httptest.ResponseRecorder, *worklog.Entry) {
+	var stagedEntry *worklog.Entry
+
+	handler := staging.NewHandler("secret", func(entry worklog.Entry) error {
+		stagedEntry = &entry
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/entries", bytes.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder, stagedEntry
+}
+
+func TestRequest_Entry(t *testing.T) {
+	stagedAt := time.Date(2021, 10, 2, 12, 0, 0, 0, time.Local)
+
+	request := &staging.Request{
+		Task:     "CPT-2014",
+		Duration: "1h30m",
+		Note:     "Review the login redirect",
+		Project:  "CPT",
+	}
+
+	entry, err := request.Entry(stagedAt)
+	require.Nil(t, err)
+
+	require.Equal(t, worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"}, entry.Task)
+	require.Equal(t, worklog.IDNameField{ID: "CPT", Name: "CPT"}, entry.Project)
+	require.Equal(t, "Review the login redirect", entry.Summary)
+	require.Equal(t, time.Date(2021, 10, 2, 10, 30, 0, 0, time.Local), entry.Start)
+	require.Equal(t, time.Hour+time.Minute*30, entry.BillableDuration)
+	require.Equal(t, staging.Source, entry.Provenance.Source)
+	require.Len(t, entry.Provenance.SourceIDs, 1)
+}
+
+func TestRequest_Entry_Start(t *testing.T) {
+	start := time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local)
+	request := &staging.Request{Task: "CPT-2014", Duration: "15m", Start: start}
+
+	entry, err := request.Entry(time.Now())
+	require.Nil(t, err)
+	require.Equal(t, start, entry.Start)
+	require.Equal(t, "CPT-2014", entry.Summary)
+}
+
+func TestRequest_Entry_Invalid(t *testing.T) {
+	for _, request := range []staging.Request{
+		{Duration: "1h"},
+		{Task: "CPT-2014"},
+		{Task: "CPT-2014", Duration: "one hour"},
+		{Task: "CPT-2014", Duration: "-1h"},
+	} {
+		_, err := request.Entry(time.Now())
+		require.ErrorIs(t, err, staging.ErrInvalidEntry)
+	}
+}
+
+func TestParseOneLiner(t *testing.T) {
+	request, err := staging.ParseOneLiner("CPT-2014 1h30m fixed the  flaky test")
+	require.Nil(t, err)
+	require.Equal(t, &staging.Request{Task: "CPT-2014", Duration: "1h30m", Note: "fixed the flaky test"}, request)
+
+	request, err = staging.ParseOneLiner("45m CPT-2014")
+	require.Nil(t, err)
+	require.Equal(t, &staging.Request{Task: "CPT-2014", Duration: "45m"}, request)
+
+	_, err = staging.ParseOneLiner("CPT-2014")
+	require.ErrorIs(t, err, staging.ErrInvalidEntry)
+
+	// The duration is validated when the entry is created
+	request, err = staging.ParseOneLiner("CPT-2014 fixed the flaky test")
+	require.Nil(t, err)
+
+	_, err = request.Entry(time.Now())
+	require.ErrorIs(t, err, staging.ErrInvalidEntry)
+}
+
+func TestStage(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewFileStore(t.TempDir())
+	require.Nil(t, err)
+
+	stage := staging.NewStage(store, storage.PrefixState+"staged.json")
+
+	entries, err := stage.Entries(ctx)
+	require.Nil(t, err)
+	require.Empty(t, entries)
+
+	first := worklog.Entry{Summary: "first", Provenance: worklog.Provenance{SourceIDs: []string{"1"}}}
+	second := worklog.Entry{Summary: "second", Provenance: worklog.Provenance{SourceIDs: []string{"2"}}}
+
+	require.Nil(t, stage.Add(ctx, first))
+	require.Nil(t, stage.Add(ctx, second))
+
+	entries, err = stage.Entries(ctx)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+
+	require.Nil(t, stage.Remove(ctx, []string{"1", "3"}))
+
+	entries, err = stage.Entries(ctx)
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "second", entries[0].Summary)
+}
+
+func TestNewHandler(t *testing.T) {
+	recorder, entry := sendEntry(t, "secret", []byte(`{"task":"CPT-2014","duration":"45m","note":"Pairing"}`))
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.NotNil(t, entry)
+	require.Equal(t, "Pairing", entry.Summary)
+
+	var response map[string]string
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, entry.Provenance.SourceIDs[0], response["id"])
+}
+
+func TestNewHandler_Unauthorized(t *testing.T) {
+	for _, token := range []string{"", "other"} {
+		recorder, entry := sendEntry(t, token, []byte(`{"task":"CPT-2014","duration":"45m"}`))
+
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Nil(t, entry)
+	}
+}
+
+func TestNewHandler_InvalidEntry(t *testing.T) {
+	for _, body := range []string{`{"task":"CPT-2014"}`, `not json`} {
+		recorder, entry := sendEntry(t, "secret", []byte(body))
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Nil(t, entry)
+	}
+}
+
+func TestNewHandler_MethodNotAllowed(t *testing.T) {
+	handler := staging.NewHandler("secret", func(worklog.Entry) error { return nil })
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/entries", nil))
+
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
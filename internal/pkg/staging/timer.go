@@ -0,0 +1,38 @@
+package staging
+
+import (
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+// Timer represents a running stopwatch, which results in a staged entry when
+// it is stopped.
+type Timer struct {
+	Task    string    `json:"task"`
+	Note    string    `json:"note,omitempty"`
+	Project string    `json:"project,omitempty"`
+	Client  string    `json:"client,omitempty"`
+	Start   time.Time `json:"start"`
+}
+
+// Elapsed returns the time elapsed since starting the timer, rounded to
+// seconds.
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	return now.Sub(t.Start).Round(time.Second)
+}
+
+// Entry returns the entry of the time elapsed until the timer is stopped.
+// If no time elapsed, ErrInvalidEntry returns.
+func (t *Timer) Entry(stoppedAt time.Time) (*worklog.Entry, error) {
+	request := &Request{
+		Task:     t.Task,
+		Duration: t.Elapsed(stoppedAt).String(),
+		Note:     t.Note,
+		Start:    t.Start,
+		Project:  t.Project,
+		Client:   t.Client,
+	}
+
+	return request.Entry(stoppedAt)
+}
@@ -0,0 +1,41 @@
+package staging_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/staging"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimer_Entry(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local)
+
+	timer := &staging.Timer{
+		Task:    "CPT-2014",
+		Note:    "Fix the flaky test",
+		Project: "CPT",
+		Start:   start,
+	}
+
+	stoppedAt := start.Add(time.Hour + time.Minute*30 + time.Millisecond*400)
+	require.Equal(t, time.Hour+time.Minute*30, timer.Elapsed(stoppedAt))
+
+	entry, err := timer.Entry(stoppedAt)
+	require.Nil(t, err)
+
+	require.Equal(t, worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"}, entry.Task)
+	require.Equal(t, worklog.IDNameField{ID: "CPT", Name: "CPT"}, entry.Project)
+	require.Equal(t, "Fix the flaky test", entry.Summary)
+	require.Equal(t, start, entry.Start)
+	require.Equal(t, time.Hour+time.Minute*30, entry.BillableDuration)
+}
+
+func TestTimer_Entry_NoTimeElapsed(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.Local)
+	timer := &staging.Timer{Task: "CPT-2014", Start: start}
+
+	_, err := timer.Entry(start.Add(time.Millisecond * 100))
+	require.ErrorIs(t, err, staging.ErrInvalidEntry)
+}
@@ -0,0 +1,140 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore runs the common Store behavior tests against the given store.
+func testStore(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "state/ledger.json")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.Nil(t, store.Put(ctx, "state/ledger.json", []byte(`{"key":"value"}`)))
+	require.Nil(t, store.Put(ctx, "state/empty.json", []byte{}))
+	require.Nil(t, store.Put(ctx, "history/2021-10-07.json", []byte("it's history")))
+
+	data, err := store.Get(ctx, "state/ledger.json")
+	require.Nil(t, err)
+	require.Equal(t, []byte(`{"key":"value"}`), data)
+
+	data, err = store.Get(ctx, "state/empty.json")
+	require.Nil(t, err)
+	require.Empty(t, data)
+
+	require.Nil(t, store.Put(ctx, "state/ledger.json", []byte("{}")))
+	data, err = store.Get(ctx, "state/ledger.json")
+	require.Nil(t, err)
+	require.Equal(t, []byte("{}"), data)
+
+	objects, err := store.List(ctx, storage.PrefixState)
+	require.Nil(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, "state/empty.json", objects[0].Key)
+	require.Equal(t, "state/ledger.json", objects[1].Key)
+	require.False(t, objects[0].ModTime.IsZero())
+
+	objects, err = store.List(ctx, "")
+	require.Nil(t, err)
+	require.Len(t, objects, 3)
+
+	require.Nil(t, store.Delete(ctx, "state/ledger.json"))
+	require.Nil(t, store.Delete(ctx, "state/ledger.json"))
+
+	_, err = store.Get(ctx, "state/ledger.json")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = store.Put(ctx, "../escape.json", []byte("{}"))
+	require.ErrorContains(t, err, storage.ErrInvalidKey.Error())
+}
+
+func TestValidateKey(t *testing.T) {
+	require.Nil(t, storage.ValidateKey("state/ledger.json"))
+	require.ErrorContains(t, storage.ValidateKey(""), storage.ErrInvalidKey.Error())
+	require.ErrorContains(t, storage.ValidateKey("/state/ledger.json"), storage.ErrInvalidKey.Error())
+	require.ErrorContains(t, storage.ValidateKey("state/../../ledger.json"), storage.ErrInvalidKey.Error())
+	require.ErrorContains(t, storage.ValidateKey("state//ledger.json"), storage.ErrInvalidKey.Error())
+	require.ErrorContains(t, storage.ValidateKey("state\\ledger.json"), storage.ErrInvalidKey.Error())
+}
+
+func TestPurge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := storage.NewFileStore(dir)
+	require.Nil(t, err)
+
+	for _, key := range []string{"state/ledger.json", "history/old.json", "history/new.json", "receipts/old.json", "cache/raw.json"} {
+		require.Nil(t, store.Put(ctx, key, []byte("{}")))
+	}
+
+	old := time.Now().AddDate(0, 0, -60)
+	require.Nil(t, os.Chtimes(filepath.Join(dir, "history", "old.json"), old, old))
+	require.Nil(t, os.Chtimes(filepath.Join(dir, "receipts", "old.json"), old, old))
+
+	opts := &storage.PurgeOpts{
+		Prefixes: []string{storage.PrefixHistory, storage.PrefixReceipts},
+		Before:   time.Now().AddDate(0, 0, -30),
+		DryRun:   true,
+	}
+
+	purged, err := storage.Purge(ctx, store, opts)
+	require.Nil(t, err)
+	require.Len(t, purged, 2)
+
+	_, err = store.Get(ctx, "history/old.json")
+	require.Nil(t, err)
+
+	opts.DryRun = false
+	purged, err = storage.Purge(ctx, store, opts)
+	require.Nil(t, err)
+	require.Equal(t, "history/old.json", purged[0].Key)
+	require.Equal(t, "receipts/old.json", purged[1].Key)
+
+	_, err = store.Get(ctx, "history/old.json")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	purged, err = storage.Purge(ctx, store, &storage.PurgeOpts{Prefixes: storage.Prefixes})
+	require.Nil(t, err)
+	require.Len(t, purged, 3)
+
+	objects, err := store.List(ctx, "")
+	require.Nil(t, err)
+	require.Empty(t, objects)
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := storage.NewFileStore(dir)
+	require.Nil(t, err)
+
+	testStore(t, store)
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(filepath.Join(dir, "state"))
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestFileStore_ListMissingDir(t *testing.T) {
+	store, err := storage.NewFileStore(filepath.Join(t.TempDir(), "missing"))
+	require.Nil(t, err)
+
+	objects, err := store.List(context.Background(), "")
+	require.Nil(t, err)
+	require.Empty(t, objects)
+}
+
+func TestNewFileStore_NoDir(t *testing.T) {
+	_, err := storage.NewFileStore("")
+	require.Error(t, err)
+}
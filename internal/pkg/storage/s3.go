@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	netURL "net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3Endpoint is the endpoint of AWS S3.
+	DefaultS3Endpoint string = "https://s3.amazonaws.com"
+	// GCSEndpoint is the S3 compatible endpoint of Google Cloud Storage. GCS
+	// requires HMAC keys to be used as access and secret keys.
+	GCSEndpoint string = "https://storage.googleapis.com"
+
+	amzDateFormat string = "20060102T150405Z"
+)
+
+// S3Opts represents the options of the S3 compatible object store.
+type S3Opts struct {
+	// Endpoint is the base URL of the S3 compatible API. Buckets are
+	// addressed in path-style, like "<endpoint>/<bucket>/<key>".
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// Prefix is prepended to every key, so multiple users can share a bucket.
+	Prefix  string
+	Timeout time.Duration
+	// HTTPClient is used to send the requests. If not set, the
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Now returns the current time used for signing. If not set, time.Now is
+	// used.
+	Now func() time.Time
+}
+
+type s3Store struct {
+	opts     S3Opts
+	endpoint *netURL.URL
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// uriEncode encodes the string as required by the AWS signature. Unreserved
+// characters are kept, and slashes are kept if encodeSlash is false.
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(s) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			encoded.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignV4 signs the request using AWS Signature Version 4 for the S3 service.
+// Every header set on the request before signing is signed, besides the host,
+// "x-amz-date" and "x-amz-content-sha256" headers which are set by SignV4.
+func SignV4(req *http.Request, payload []byte, region string, accessKey string, secretKey string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	scopeDate := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	var headerNames []string
+	for key := range headers {
+		headerNames = append(headerNames, key)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, key := range headerNames {
+		canonicalHeaders.WriteString(key + ":" + headers[key] + "\n")
+	}
+
+	signedHeaders := strings.Join(headerNames, ";")
+
+	query := req.URL.Query()
+	var queryKeys []string
+	for key := range query {
+		queryKeys = append(queryKeys, key)
+	}
+	sort.Strings(queryKeys)
+
+	var canonicalQuery []string
+	for _, key := range queryKeys {
+		values := query[key]
+		sort.Strings(values)
+
+		for _, value := range values {
+			canonicalQuery = append(canonicalQuery, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	canonicalURI := req.URL.EscapedPath()
+	if unescapedPath, err := netURL.PathUnescape(canonicalURI); err == nil {
+		canonicalURI = uriEncode(unescapedPath, false)
+	}
+
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		strings.Join(canonicalQuery, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := scopeDate + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), scopeDate)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func (s *s3Store) do(ctx context.Context, method string, key string, query netURL.Values, payload []byte) ([]byte, int, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	url := *s.endpoint
+	url.Path = strings.TrimSuffix(url.Path, "/") + "/" + s.opts.Bucket
+	if key != "" {
+		url.Path += "/" + s.opts.Prefix + key
+	}
+	url.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, method, url.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now
+	if s.opts.Now != nil {
+		now = s.opts.Now
+	}
+
+	SignV4(req, payload, s.opts.Region, s.opts.AccessKey, s.opts.SecretKey, now())
+
+	httpClient := s.opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resp.StatusCode, ErrNotFound
+	}
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return nil, resp.StatusCode, fmt.Errorf("%d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	body, _, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	return body, err
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	_, _, err := s.do(ctx, http.MethodPut, key, nil, data)
+	return err
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	_, _, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	continuationToken := ""
+
+	for {
+		query := netURL.Values{
+			"list-type": {"2"},
+			"prefix":    {s.opts.Prefix + prefix},
+		}
+
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		body, _, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, Object{
+				Key:     strings.TrimPrefix(content.Key, s.opts.Prefix),
+				ModTime: content.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+
+		continuationToken = result.NextContinuationToken
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+// NewS3Store returns a new Store that persists the objects in an S3 compatible
+// object storage, like AWS S3, Google Cloud Storage or MinIO.
+func NewS3Store(opts *S3Opts) (Store, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("no bucket provided")
+	}
+
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("no access key or secret key provided")
+	}
+
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultS3Endpoint
+	}
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	endpoint, err := netURL.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Store{
+		opts:     *opts,
+		endpoint: endpoint,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+)
+
+const sqliteSchema string = `CREATE TABLE IF NOT EXISTS objects (
+	key TEXT PRIMARY KEY,
+	data BLOB NOT NULL,
+	mod_time INTEGER NOT NULL
+);
+`
+
+// SQLiteOpts represents the options of the SQLite based store. Since the store
+// is using the `sqlite3` command line tool, the CLIClient must be set.
+type SQLiteOpts struct {
+	client.BaseClientOpts
+	client.CLIClient
+	Path string
+}
+
+type sqliteStore struct {
+	*client.BaseClientOpts
+	*client.CLIClient
+	path string
+}
+
+// quote returns the string as an SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// query executes the SQL statements after ensuring the schema exists. The
+// statements are passed on the standard input, so the data size is not limited
+// by the maximum argument length.
+func (s *sqliteStore) query(ctx context.Context, statements string) ([]byte, error) {
+	out, err := s.Execute(ctx, []string{"-batch", "-noheader", "-list", s.path}, &client.CLIExecuteOpts{
+		Timeout: s.Timeout,
+		Stdin:   strings.NewReader(sqliteSchema + statements),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("sqlite query failed: %v", err)
+	}
+
+	return out, nil
+}
+
+func (s *sqliteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	// The "x" prefix distinguishes the empty data from the missing key
+	out, err := s.query(ctx, fmt.Sprintf("SELECT 'x' || hex(data) FROM objects WHERE key = %s;\n", quote(key)))
+	if err != nil {
+		return nil, err
+	}
+
+	row := strings.TrimSpace(string(out))
+	if row == "" {
+		return nil, ErrNotFound
+	}
+
+	return hex.DecodeString(strings.TrimPrefix(row, "x"))
+}
+
+func (s *sqliteStore) Put(ctx context.Context, key string, data []byte) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.query(ctx, fmt.Sprintf(
+		"INSERT OR REPLACE INTO objects (key, data, mod_time) VALUES (%s, X'%s', %d);\n",
+		quote(key),
+		hex.EncodeToString(data),
+		time.Now().UnixNano(),
+	))
+
+	return err
+}
+
+func (s *sqliteStore) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.query(ctx, fmt.Sprintf("DELETE FROM objects WHERE key = %s;\n", quote(key)))
+	return err
+}
+
+func (s *sqliteStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	// Keys are hex encoded to not break the output by special characters
+	out, err := s.query(ctx, fmt.Sprintf(
+		"SELECT hex(key), mod_time FROM objects WHERE substr(key, 1, %d) = %s ORDER BY key;\n",
+		len(prefix),
+		quote(prefix),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		rawKey, rawModTime, found := strings.Cut(scanner.Text(), "|")
+		if !found {
+			return nil, errors.New("unexpected sqlite output")
+		}
+
+		key, err := hex.DecodeString(rawKey)
+		if err != nil {
+			return nil, err
+		}
+
+		modTime, err := strconv.ParseInt(rawModTime, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, Object{
+			Key:     string(key),
+			ModTime: time.Unix(0, modTime),
+		})
+	}
+
+	return objects, scanner.Err()
+}
+
+// NewSQLiteStore returns a new Store that persists the objects in an SQLite
+// database using the `sqlite3` command line tool.
+func NewSQLiteStore(opts *SQLiteOpts) (Store, error) {
+	if opts.Path == "" {
+		return nil, errors.New("no SQLite database path provided")
+	}
+
+	return &sqliteStore{
+		BaseClientOpts: &opts.BaseClientOpts,
+		CLIClient:      &opts.CLIClient,
+		path:           opts.Path,
+	}, nil
+}
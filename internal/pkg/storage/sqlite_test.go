@@ -0,0 +1,37 @@
+package storage_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+
+	store, err := storage.NewSQLiteStore(&storage.SQLiteOpts{
+		BaseClientOpts: client.BaseClientOpts{
+			Timeout: time.Second * 10,
+		},
+		CLIClient: client.CLIClient{
+			Command:            "sqlite3",
+			CommandCtxExecutor: exec.CommandContext,
+		},
+		Path: filepath.Join(t.TempDir(), "minutes.db"),
+	})
+	require.Nil(t, err)
+
+	testStore(t, store)
+}
+
+func TestNewSQLiteStore_NoPath(t *testing.T) {
+	_, err := storage.NewSQLiteStore(&storage.SQLiteOpts{})
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// PrefixState is the key prefix of the state persisted between runs.
+	PrefixState string = "state/"
+	// PrefixHistory is the key prefix of the run history.
+	PrefixHistory string = "history/"
+	// PrefixReceipts is the key prefix of the upload receipts.
+	PrefixReceipts string = "receipts/"
+	// PrefixCache is the key prefix of the cached data.
+	PrefixCache string = "cache/"
+)
+
+var (
+	// ErrNotFound returns when the requested key does not exist.
+	ErrNotFound = errors.New("key not found")
+	// ErrInvalidKey returns when the key is empty or would escape the store.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// Object represents a stored object's metadata.
+type Object struct {
+	Key     string
+	ModTime time.Time
+}
+
+// Store specifies the functions used to persist the state, history and
+// receipts of minutes. Keys are slash-separated paths, like
+// "state/ledger.json".
+type Store interface {
+	// Get returns the data stored for the key. If the key does not exist,
+	// ErrNotFound returns.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores the data for the key, overriding the existing data.
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete removes the key. Deleting a not existing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the objects which key starts with the prefix, sorted by
+	// their key.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Prefixes lists the key prefixes of the persisted data.
+var Prefixes = []string{PrefixCache, PrefixHistory, PrefixReceipts, PrefixState}
+
+// PurgeOpts specifies the objects removed by Purge.
+type PurgeOpts struct {
+	// Prefixes lists the key prefixes of the removed objects, like
+	// PrefixHistory.
+	Prefixes []string
+	// Before removes only the objects modified before the time. If not set,
+	// every object is removed.
+	Before time.Time
+	// DryRun returns the objects that would be removed, without removing them.
+	DryRun bool
+}
+
+// Purge removes the objects of the store matching the options and returns the
+// removed objects. Purge is used to remove the personal data accumulated in
+// the store, like the history of the syncs.
+func Purge(ctx context.Context, store Store, opts *PurgeOpts) ([]Object, error) {
+	var purged []Object
+
+	for _, prefix := range opts.Prefixes {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, object := range objects {
+			if !opts.Before.IsZero() && !object.ModTime.Before(opts.Before) {
+				continue
+			}
+
+			if !opts.DryRun {
+				if err = store.Delete(ctx, object.Key); err != nil {
+					return purged, err
+				}
+			}
+
+			purged = append(purged, object)
+		}
+	}
+
+	return purged, nil
+}
+
+// ValidateKey returns ErrInvalidKey if the key is empty, absolute or contains
+// relative path elements.
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%v: %q", ErrInvalidKey, key)
+	}
+
+	for _, element := range strings.Split(key, "/") {
+		if element == "" || element == "." || element == ".." {
+			return fmt.Errorf("%v: %q", ErrInvalidKey, key)
+		}
+	}
+
+	return nil
+}
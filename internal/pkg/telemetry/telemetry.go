@@ -0,0 +1,184 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+)
+
+const (
+	// ModeOff disables the usage reporting. This is the default.
+	ModeOff string = "off"
+	// ModePreview prints the report that would be sent, without sending it.
+	ModePreview string = "preview"
+	// ModeOn sends the report to the configured endpoint.
+	ModeOn string = "on"
+
+	// DefaultEndpoint is the endpoint receiving the usage reports, unless
+	// another endpoint is set.
+	DefaultEndpoint string = "https://telemetry.minutes.dev/v1/reports"
+
+	// PhaseFetch is the phase of errors occurred while fetching entries.
+	PhaseFetch string = "fetch"
+	// PhaseUpload is the phase of errors occurred while uploading entries.
+	PhaseUpload string = "upload"
+	// PhaseOther is the phase of every other error.
+	PhaseOther string = "other"
+)
+
+var (
+	// Modes lists the available telemetry modes.
+	Modes = []string{ModeOff, ModePreview, ModeOn}
+
+	// ErrNoEndpoint returns when the telemetry is turned on, but no endpoint
+	// is set.
+	ErrNoEndpoint = errors.New("no telemetry endpoint provided")
+	// ErrUnknownMode returns when the telemetry mode is not known.
+	ErrUnknownMode = errors.New("unknown telemetry mode")
+)
+
+// Report represents the anonymous usage statistics of a run. The report never
+// contains entries, credentials, user IDs, URLs or error messages; errors are
+// counted by their class only.
+type Report struct {
+	Version string         `json:"version"`
+	OS      string         `json:"os"`
+	Arch    string         `json:"arch"`
+	Command string         `json:"command"`
+	Source  string         `json:"source,omitempty"`
+	Target  string         `json:"target,omitempty"`
+	Entries int            `json:"entries"`
+	Errors  map[string]int `json:"errors,omitempty"`
+}
+
+// AddError counts the error by its class.
+func (r *Report) AddError(err error) {
+	if err == nil {
+		return
+	}
+
+	if r.Errors == nil {
+		r.Errors = map[string]int{}
+	}
+
+	r.Errors[Classify(err)]++
+}
+
+// Classify returns the class of the error in "<phase>/<kind>" format, like
+// "upload/rate limited". The kind is the kind of the typed client error in the
+// error's chain, or the status code of the unsuccessful response if the client
+// did not classify the error, like "fetch/http 502". The error message is
+// never part of the class.
+func Classify(err error) string {
+	phase := PhaseOther
+	if errors.Is(err, client.ErrFetchEntries) {
+		phase = PhaseFetch
+	} else if errors.Is(err, client.ErrUploadEntries) {
+		phase = PhaseUpload
+	}
+
+	kind := string(client.KindOf(err))
+
+	var httpErr *client.HTTPError
+	if client.KindOf(err) == client.ErrorKindUnknown && errors.As(err, &httpErr) {
+		kind = fmt.Sprintf("http %d", httpErr.StatusCode)
+	}
+
+	return phase + "/" + kind
+}
+
+// ReporterOpts represents the options used to report the usage statistics.
+type ReporterOpts struct {
+	Mode     string
+	Endpoint string
+	// Output is used to print the report in preview mode.
+	Output  io.Writer
+	Timeout time.Duration
+	// HTTPClient is used to send the report. If not set, the
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Validate returns an error if the mode is not known or the endpoint is
+// missing when the telemetry is turned on.
+func (o *ReporterOpts) Validate() error {
+	switch o.Mode {
+	case ModeOff, ModePreview:
+		return nil
+	case ModeOn:
+		if o.Endpoint == "" {
+			return ErrNoEndpoint
+		}
+		return nil
+	default:
+		return fmt.Errorf("%v: %s", ErrUnknownMode, o.Mode)
+	}
+}
+
+// NewReport returns a new report for the command, filling the version and
+// platform information.
+func NewReport(version string, command string) *Report {
+	return &Report{
+		Version: version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Command: command,
+	}
+}
+
+// Send reports the usage statistics according to the mode. In off mode, Send
+// does nothing.
+func Send(ctx context.Context, report *Report, opts *ReporterOpts) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if opts.Mode == ModeOff {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if opts.Mode == ModePreview {
+		_, err = fmt.Fprintf(opts.Output, "\nTelemetry preview, nothing was sent:\n%s\n", string(data))
+		return err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodPost, opts.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return fmt.Errorf("failed to send telemetry: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/client"
+	"github.com/gabor-boros/minutes/internal/pkg/telemetry"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestReport() *telemetry.Report {
+	report := telemetry.NewReport("1.0.0", "minutes")
+	report.Source = "clockify"
+	report.Target = "tempo"
+	report.Entries = 3
+	report.AddError(fmt.Errorf("%w: %w", client.ErrFetchEntries, &client.AuthError{
+		Err: &client.HTTPError{StatusCode: http.StatusUnauthorized, Body: []byte("secret details")},
+	}))
+	report.AddError(fmt.Errorf("%w: %w", client.ErrUploadEntries, &client.RateLimitError{
+		Err: &client.HTTPError{StatusCode: http.StatusTooManyRequests, Body: []byte("secret details")},
+	}))
+	report.AddError(fmt.Errorf("%w: %w", client.ErrUploadEntries, &client.RateLimitError{
+		Err: &client.HTTPError{StatusCode: http.StatusTooManyRequests, Body: []byte("secret details")},
+	}))
+	report.AddError(fmt.Errorf("%w: %w", client.ErrFetchEntries, &client.HTTPError{
+		StatusCode: http.StatusTeapot,
+		Body:       []byte("secret details"),
+	}))
+	report.AddError(fmt.Errorf("%v: secret details", client.ErrUploadEntries))
+	report.AddError(errors.New("something went wrong"))
+	report.AddError(nil)
+
+	return report
+}
+
+func TestNewReport(t *testing.T) {
+	report := telemetry.NewReport("1.0.0", "minutes")
+
+	require.Equal(t, &telemetry.Report{
+		Version: "1.0.0",
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Command: "minutes",
+	}, report)
+}
+
+func TestReport_AddError(t *testing.T) {
+	require.Equal(t, map[string]int{
+		"fetch/auth":          1,
+		"upload/rate limited": 2,
+		"fetch/http 418":      1,
+		"other/unknown":       2,
+	}, getTestReport().Errors)
+}
+
+func TestReporterOpts_Validate(t *testing.T) {
+	require.Nil(t, (&telemetry.ReporterOpts{Mode: telemetry.ModeOff}).Validate())
+	require.Nil(t, (&telemetry.ReporterOpts{Mode: telemetry.ModePreview}).Validate())
+	require.Nil(t, (&telemetry.ReporterOpts{Mode: telemetry.ModeOn, Endpoint: "https://example.com"}).Validate())
+	require.ErrorIs(t, (&telemetry.ReporterOpts{Mode: telemetry.ModeOn}).Validate(), telemetry.ErrNoEndpoint)
+	require.Error(t, (&telemetry.ReporterOpts{Mode: "always"}).Validate())
+}
+
+func TestSend_Off(t *testing.T) {
+	var output bytes.Buffer
+
+	err := telemetry.Send(context.Background(), getTestReport(), &telemetry.ReporterOpts{
+		Mode:     telemetry.ModeOff,
+		Endpoint: "http://127.0.0.1:0",
+		Output:   &output,
+	})
+
+	require.Nil(t, err)
+	require.Empty(t, output.String())
+}
+
+func TestSend_Preview(t *testing.T) {
+	var output bytes.Buffer
+
+	err := telemetry.Send(context.Background(), getTestReport(), &telemetry.ReporterOpts{
+		Mode:   telemetry.ModePreview,
+		Output: &output,
+	})
+
+	require.Nil(t, err)
+	require.Contains(t, output.String(), "nothing was sent")
+	require.Contains(t, output.String(), `"source": "clockify"`)
+	require.NotContains(t, output.String(), "secret details")
+}
+
+func TestSend_On(t *testing.T) {
+	var received telemetry.Report
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer mockServer.Close()
+
+	report := getTestReport()
+
+	err := telemetry.Send(context.Background(), report, &telemetry.ReporterOpts{
+		Mode:     telemetry.ModeOn,
+		Endpoint: mockServer.URL,
+		Timeout:  time.Second * 10,
+	})
+
+	require.Nil(t, err)
+	require.Equal(t, *report, received)
+}
+
+func TestSend_OnFailure(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	err := telemetry.Send(context.Background(), getTestReport(), &telemetry.ReporterOpts{
+		Mode:     telemetry.ModeOn,
+		Endpoint: mockServer.URL,
+		Timeout:  time.Second * 10,
+	})
+
+	require.Error(t, err)
+}
@@ -0,0 +1,394 @@
+package timesheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/pdf"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+)
+
+const (
+	// PeriodWeek is the week containing the date, starting on the first day
+	// of the week of the locale.
+	PeriodWeek string = "week"
+	// PeriodMonth is the calendar month containing the date.
+	PeriodMonth string = "month"
+
+	// DetailEntries lists every entry with its project, task and summary.
+	DetailEntries string = "entries"
+	// DetailDaily lists only the start, end and total of every day, without
+	// the details of the activities. It suits the approvals, like the ones of
+	// works councils, where the activities must not be shared.
+	DetailDaily string = "daily"
+
+	// DefaultTitle is the title of the timesheet if not set otherwise.
+	DefaultTitle string = "Timesheet"
+
+	margin      float64 = 50
+	logoHeight  float64 = 40
+	fontSize    float64 = 9
+	rowHeight   float64 = 16
+	cellPadding float64 = 4
+	// signatureHeight is the space required by the signature lines
+	signatureHeight float64 = 90
+)
+
+var (
+	// Periods lists the available timesheet periods.
+	Periods = []string{PeriodWeek, PeriodMonth}
+	// Details lists the available timesheet detail levels.
+	Details = []string{DetailEntries, DetailDaily}
+
+	// ErrUnknownPeriod returns when the period is not part of Periods.
+	ErrUnknownPeriod = errors.New("unknown timesheet period")
+	// ErrUnknownDetail returns when the detail level is not part of Details.
+	ErrUnknownDetail = errors.New("unknown timesheet detail")
+)
+
+// PeriodRange returns the start and end of the period containing the date.
+// The end is the midnight after the last day of the period.
+func PeriodRange(period string, date time.Time, l *locale.Locale) (time.Time, time.Time, error) {
+	switch period {
+	case PeriodWeek:
+		start := l.StartOfWeek(date)
+		return start, start.AddDate(0, 0, 7), nil
+	case PeriodMonth:
+		start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%v: %s", ErrUnknownPeriod, period)
+	}
+}
+
+// DailyTotal represents the time spent on a day. The start is the start of
+// the first entry, while the end is the end of the last entry of the day. The
+// duration excludes the gaps between the entries.
+type DailyTotal struct {
+	Date     time.Time
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+}
+
+// DailyTotals returns the totals of the days having entries, ordered by date.
+// The entries are assigned to the day of their local start.
+func DailyTotals(entries worklog.Entries) []DailyTotal {
+	var totals []DailyTotal
+	indexByDate := map[string]int{}
+
+	for _, entry := range entries {
+		start := entry.Start.Local()
+		timeSpent := entry.BillableDuration + entry.UnbillableDuration
+		end := start.Add(timeSpent)
+
+		key := start.Format("2006-01-02")
+		i, ok := indexByDate[key]
+		if !ok {
+			year, month, day := start.Date()
+			totals = append(totals, DailyTotal{
+				Date:  time.Date(year, month, day, 0, 0, 0, 0, time.Local),
+				Start: start,
+				End:   end,
+			})
+
+			i = len(totals) - 1
+			indexByDate[key] = i
+		}
+
+		if start.Before(totals[i].Start) {
+			totals[i].Start = start
+		}
+
+		if end.After(totals[i].End) {
+			totals[i].End = end
+		}
+
+		totals[i].Duration += timeSpent
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Date.Before(totals[j].Date)
+	})
+
+	return totals
+}
+
+// PDFOpts represents the options of the PDF timesheet.
+type PDFOpts struct {
+	// Title is printed on the top of the first page. If not set, DefaultTitle
+	// is used.
+	Title string
+	// Employee is the name of the person the timesheet belongs to.
+	Employee string
+	// Client is the name of the client the timesheet is made for. If not set,
+	// the client is not printed.
+	Client string
+	// Logo is a JPEG or PNG image printed on the top-left corner of the first
+	// page. If not set, no logo is printed.
+	Logo io.Reader
+	// Detail sets what is listed on the timesheet, like DetailDaily. If not
+	// set, DetailEntries is used.
+	Detail string
+	// Start and End sets the period of the timesheet.
+	Start time.Time
+	End   time.Time
+	// Locale sets the format of the dates and hours. If not set, the default
+	// locale is used.
+	Locale *locale.Locale
+	// Translator translates the labels of the timesheet. If not set, the
+	// labels are printed in the default language.
+	Translator *i18n.Translator
+}
+
+type column struct {
+	header     string
+	width      float64
+	alignRight bool
+}
+
+type pdfRenderer struct {
+	doc     *pdf.Document
+	page    *pdf.Page
+	y       float64
+	columns []column
+	tr      *i18n.Translator
+}
+
+func (r *pdfRenderer) addPage() {
+	r.page = r.doc.AddPage()
+	r.y = margin
+}
+
+func (r *pdfRenderer) drawRow(cells []string, bold bool, shade bool) {
+	if shade {
+		r.page.FillRect(margin, r.y, pdf.A4Width-2*margin, rowHeight, 0.9)
+	}
+
+	x := margin
+	baseline := r.y + rowHeight - 5
+
+	for i, col := range r.columns {
+		text := pdf.TruncateText(cells[i], col.width-2*cellPadding, fontSize, bold)
+
+		if col.alignRight {
+			r.page.TextRight(x+col.width-cellPadding, baseline, fontSize, bold, text)
+		} else {
+			r.page.Text(x+cellPadding, baseline, fontSize, bold, text)
+		}
+
+		x += col.width
+	}
+
+	r.y += rowHeight
+	r.page.Line(margin, r.y, pdf.A4Width-margin, r.y, 0.25)
+}
+
+func (r *pdfRenderer) drawHeaderRow() {
+	var headers []string
+	for _, col := range r.columns {
+		headers = append(headers, col.header)
+	}
+
+	r.drawRow(headers, true, true)
+}
+
+// ensureSpace starts a new page if the height does not fit on the current
+// page. If the table header is needed, it is repeated on the new page.
+func (r *pdfRenderer) ensureSpace(height float64, repeatHeader bool) {
+	if r.y+height <= pdf.A4Height-margin {
+		return
+	}
+
+	r.addPage()
+
+	if repeatHeader {
+		r.drawHeaderRow()
+	}
+}
+
+func (r *pdfRenderer) drawSignatures() {
+	r.ensureSpace(signatureHeight, false)
+
+	lineWidth := (pdf.A4Width - 3*margin) / 2
+	dateY := r.y + 25
+	signatureY := r.y + 70
+
+	for i, label := range []string{r.tr.Sprintf("Employee signature"), r.tr.Sprintf("Client signature")} {
+		x := margin + float64(i)*(lineWidth+margin)
+
+		r.page.Text(x, dateY, fontSize, false, r.tr.Sprintf("Date:"))
+		r.page.Line(x+30, dateY, x+lineWidth, dateY, 0.5)
+
+		r.page.Line(x, signatureY, x+lineWidth, signatureY, 0.5)
+		r.page.Text(x, signatureY+12, fontSize, false, label)
+	}
+
+	r.y += signatureHeight
+}
+
+func (r *pdfRenderer) drawPageNumbers() {
+	pages := r.doc.Pages()
+	for i, page := range pages {
+		page.TextRight(pdf.A4Width-margin, pdf.A4Height-margin/2, fontSize, false, r.tr.Sprintf("Page %d of %d", i+1, len(pages)))
+	}
+}
+
+// drawEntries draws the rows of the entries, ordered by their start, and
+// returns the total time spent.
+func (r *pdfRenderer) drawEntries(entries worklog.Entries, l *locale.Locale) time.Duration {
+	sortedEntries := make(worklog.Entries, len(entries))
+	copy(sortedEntries, entries)
+	sort.SliceStable(sortedEntries, func(i, j int) bool {
+		return sortedEntries[i].Start.Before(sortedEntries[j].Start)
+	})
+
+	var total time.Duration
+	for _, entry := range sortedEntries {
+		timeSpent := entry.BillableDuration + entry.UnbillableDuration
+		total += timeSpent
+
+		r.ensureSpace(rowHeight, true)
+		r.drawRow([]string{
+			l.FormatDate(entry.Start.Local()),
+			entry.Project.Name,
+			entry.Task.Name,
+			entry.Summary,
+			l.FormatHours(timeSpent, 2),
+		}, false, false)
+	}
+
+	return total
+}
+
+// drawDailyTotals draws the rows of the daily totals and returns the total
+// time spent. Nothing is printed about the activities of the entries.
+func (r *pdfRenderer) drawDailyTotals(entries worklog.Entries, l *locale.Locale) time.Duration {
+	var total time.Duration
+	for _, daily := range DailyTotals(entries) {
+		total += daily.Duration
+
+		r.ensureSpace(rowHeight, true)
+		r.drawRow([]string{
+			l.FormatDate(daily.Date),
+			l.FormatTime(daily.Start),
+			l.FormatTime(daily.End),
+			l.FormatHours(daily.Duration, 2),
+		}, false, false)
+	}
+
+	return total
+}
+
+// RenderPDF renders the entries of the period as a PDF timesheet, including
+// the total hours and the signature lines of the employee and the client. The
+// entries or only the daily totals are listed, depending on the detail level.
+func RenderPDF(w io.Writer, entries worklog.Entries, opts *PDFOpts) error {
+	l := opts.Locale
+	if l == nil {
+		l = locale.Default()
+	}
+
+	tr := opts.Translator
+	if tr == nil {
+		tr = i18n.Default()
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = tr.Sprintf(DefaultTitle)
+	}
+
+	detailLevel := opts.Detail
+	if detailLevel == "" {
+		detailLevel = DetailEntries
+	}
+
+	renderer := &pdfRenderer{
+		doc: pdf.New(pdf.A4Width, pdf.A4Height),
+		tr:  tr,
+	}
+
+	switch detailLevel {
+	case DetailEntries:
+		renderer.columns = []column{
+			{header: tr.Sprintf("Date"), width: 70},
+			{header: tr.Sprintf("Project"), width: 95},
+			{header: tr.Sprintf("Task"), width: 75},
+			{header: tr.Sprintf("Summary"), width: 200},
+			{header: tr.Sprintf("Hours"), width: 55, alignRight: true},
+		}
+	case DetailDaily:
+		renderer.columns = []column{
+			{header: tr.Sprintf("Date"), width: 135},
+			{header: tr.Sprintf("Start"), width: 120},
+			{header: tr.Sprintf("End"), width: 120},
+			{header: tr.Sprintf("Hours"), width: 120, alignRight: true},
+		}
+	default:
+		return fmt.Errorf("%v: %s", ErrUnknownDetail, detailLevel)
+	}
+
+	renderer.addPage()
+
+	if opts.Logo != nil {
+		logo, err := renderer.doc.AddImage(opts.Logo)
+		if err != nil {
+			return err
+		}
+
+		width := logoHeight * float64(logo.Width) / float64(logo.Height)
+		renderer.page.Image(logo, margin, renderer.y, width, logoHeight)
+		renderer.y += logoHeight + 20
+	}
+
+	renderer.page.Text(margin, renderer.y+18, 18, true, title)
+	renderer.y += 36
+
+	details := [][2]string{
+		{tr.Sprintf("Employee:"), opts.Employee},
+		{tr.Sprintf("Client:"), opts.Client},
+		// The end of the period is exclusive, hence the previous day is printed
+		{tr.Sprintf("Period:"), fmt.Sprintf("%s - %s", l.FormatDate(opts.Start), l.FormatDate(opts.End.AddDate(0, 0, -1)))},
+	}
+
+	for _, detail := range details {
+		if detail[1] == "" {
+			continue
+		}
+
+		renderer.page.Text(margin, renderer.y+fontSize, fontSize+1, true, detail[0])
+		renderer.page.Text(margin+70, renderer.y+fontSize, fontSize+1, false, detail[1])
+		renderer.y += 16
+	}
+
+	renderer.y += 10
+	renderer.drawHeaderRow()
+
+	var total time.Duration
+	if detailLevel == DetailDaily {
+		total = renderer.drawDailyTotals(entries, l)
+	} else {
+		total = renderer.drawEntries(entries, l)
+	}
+
+	// The total is in the last column, the others are left empty
+	totalRow := make([]string, len(renderer.columns))
+	totalRow[0] = tr.Sprintf("Total")
+	totalRow[len(totalRow)-1] = l.FormatHours(total, 2)
+
+	renderer.ensureSpace(rowHeight, true)
+	renderer.drawRow(totalRow, true, true)
+
+	renderer.y += 20
+	renderer.drawSignatures()
+	renderer.drawPageNumbers()
+
+	_, err := renderer.doc.WriteTo(w)
+	return err
+}
@@ -0,0 +1,235 @@
+package timesheet_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/i18n"
+	"github.com/gabor-boros/minutes/internal/pkg/locale"
+	"github.com/gabor-boros/minutes/internal/pkg/timesheet"
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodRange(t *testing.T) {
+	// 2021-10-06 is a Wednesday
+	date := time.Date(2021, 10, 6, 14, 0, 0, 0, time.UTC)
+
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	start, end, err := timesheet.PeriodRange(timesheet.PeriodWeek, date, l)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 10, 4, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2021, 10, 11, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = timesheet.PeriodRange(timesheet.PeriodMonth, date, l)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = timesheet.PeriodRange("year", date, l)
+	require.ErrorContains(t, err, timesheet.ErrUnknownPeriod.Error())
+}
+
+func TestRenderPDF(t *testing.T) {
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	var logo bytes.Buffer
+	require.Nil(t, png.Encode(&logo, image.NewGray(image.Rect(0, 0, 4, 2))))
+
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{Name: "Internal projects"},
+			Task:             worklog.IDNameField{Name: "TASK-456"},
+			Summary:          "Write documentation",
+			Start:            time.Date(2021, 10, 5, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+		{
+			Project:            worklog.IDNameField{Name: "Internal projects"},
+			Task:               worklog.IDNameField{Name: "TASK-123"},
+			Summary:            "Fix the bug",
+			Start:              time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration:   time.Hour,
+			UnbillableDuration: time.Minute * 30,
+		},
+	}
+
+	var buf bytes.Buffer
+	err = timesheet.RenderPDF(&buf, entries, &timesheet.PDFOpts{
+		Employee: "Gabor Boros",
+		Client:   "ACME Inc.",
+		Logo:     &logo,
+		Start:    time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local),
+		End:      time.Date(2021, 10, 11, 0, 0, 0, 0, time.Local),
+		Locale:   l,
+	})
+	require.Nil(t, err)
+
+	content := buf.String()
+	require.True(t, strings.HasPrefix(content, "%PDF-1.4"))
+	require.Contains(t, content, "(Timesheet) Tj")
+	require.Contains(t, content, "(Gabor Boros) Tj")
+	require.Contains(t, content, "(ACME Inc.) Tj")
+	require.Contains(t, content, "(04.10.2021 - 10.10.2021) Tj")
+	require.Contains(t, content, "(1,50) Tj")
+	require.Contains(t, content, "(3,50) Tj")
+	require.Contains(t, content, "(Employee signature) Tj")
+	require.Contains(t, content, "(Client signature) Tj")
+	require.Contains(t, content, "(Page 1 of 1) Tj")
+	require.Contains(t, content, "/Im1 Do")
+
+	// Entries are printed in the order of their start
+	require.Less(t, strings.Index(content, "(TASK-123) Tj"), strings.Index(content, "(TASK-456) Tj"))
+}
+
+func TestRenderPDF_MultiplePages(t *testing.T) {
+	var entries worklog.Entries
+	for i := 0; i < 100; i++ {
+		entries = append(entries, worklog.Entry{
+			Task:             worklog.IDNameField{Name: fmt.Sprintf("TASK-%d", i)},
+			Summary:          "Work",
+			Start:            time.Date(2021, 10, 1, 9, 0, 0, 0, time.Local).Add(time.Hour * time.Duration(i)),
+			BillableDuration: time.Hour,
+		})
+	}
+
+	var buf bytes.Buffer
+	err := timesheet.RenderPDF(&buf, entries, &timesheet.PDFOpts{
+		Employee: "Gabor Boros",
+		Start:    time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local),
+		End:      time.Date(2021, 11, 1, 0, 0, 0, 0, time.Local),
+	})
+	require.Nil(t, err)
+
+	content := buf.String()
+	require.Contains(t, content, "/Count 3")
+	require.Contains(t, content, "(Page 3 of 3) Tj")
+	require.Contains(t, content, "(100.00) Tj")
+	// The table header is repeated on every page
+	require.Equal(t, 3, strings.Count(content, "(Summary) Tj"))
+}
+
+func TestDailyTotals(t *testing.T) {
+	entries := worklog.Entries{
+		{
+			Start:            time.Date(2021, 10, 5, 13, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 3,
+		},
+		{
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+		{
+			Start:              time.Date(2021, 10, 5, 8, 30, 0, 0, time.Local),
+			BillableDuration:   time.Hour * 3,
+			UnbillableDuration: time.Minute * 30,
+		},
+	}
+
+	require.Equal(t, []timesheet.DailyTotal{
+		{
+			Date:     time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local),
+			Start:    time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			End:      time.Date(2021, 10, 4, 11, 0, 0, 0, time.Local),
+			Duration: time.Hour * 2,
+		},
+		{
+			// The lunch break between the entries is not counted
+			Date:     time.Date(2021, 10, 5, 0, 0, 0, 0, time.Local),
+			Start:    time.Date(2021, 10, 5, 8, 30, 0, 0, time.Local),
+			End:      time.Date(2021, 10, 5, 16, 0, 0, 0, time.Local),
+			Duration: time.Hour*6 + time.Minute*30,
+		},
+	}, timesheet.DailyTotals(entries))
+}
+
+func TestRenderPDF_DailyDetail(t *testing.T) {
+	l, err := locale.Get("de-DE")
+	require.Nil(t, err)
+
+	entries := worklog.Entries{
+		{
+			Project:          worklog.IDNameField{Name: "Internal projects"},
+			Task:             worklog.IDNameField{Name: "TASK-456"},
+			Summary:          "Write documentation",
+			Start:            time.Date(2021, 10, 4, 13, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+		{
+			Project:          worklog.IDNameField{Name: "Internal projects"},
+			Task:             worklog.IDNameField{Name: "TASK-123"},
+			Summary:          "Fix the bug",
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 3,
+		},
+	}
+
+	var buf bytes.Buffer
+	err = timesheet.RenderPDF(&buf, entries, &timesheet.PDFOpts{
+		Employee: "Gabor Boros",
+		Detail:   timesheet.DetailDaily,
+		Start:    time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local),
+		End:      time.Date(2021, 10, 11, 0, 0, 0, 0, time.Local),
+		Locale:   l,
+	})
+	require.Nil(t, err)
+
+	content := buf.String()
+	require.Contains(t, content, "(04.10.2021) Tj")
+	require.Contains(t, content, "(09:00) Tj")
+	require.Contains(t, content, "(15:00) Tj")
+	require.Contains(t, content, "(5,00) Tj")
+	require.Contains(t, content, "(Employee signature) Tj")
+
+	// The activities are not shared
+	for _, detail := range []string{"Project", "Internal projects", "TASK-123", "TASK-456", "Fix the bug", "Write documentation"} {
+		require.NotContains(t, content, "("+detail+") Tj")
+	}
+}
+
+func TestRenderPDF_Translated(t *testing.T) {
+	translator, err := i18n.Get("de")
+	require.Nil(t, err)
+
+	entries := worklog.Entries{
+		{
+			Summary:          "Write documentation",
+			Start:            time.Date(2021, 10, 5, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+	}
+
+	var buf bytes.Buffer
+	err = timesheet.RenderPDF(&buf, entries, &timesheet.PDFOpts{
+		Start:      time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local),
+		End:        time.Date(2021, 10, 11, 0, 0, 0, 0, time.Local),
+		Translator: translator,
+	})
+	require.Nil(t, err)
+
+	content := buf.String()
+	require.Contains(t, content, "(Stundenzettel) Tj")
+	require.Contains(t, content, "(Beschreibung) Tj")
+	require.Contains(t, content, "(Gesamt) Tj")
+	require.Contains(t, content, "(Unterschrift Mitarbeiter) Tj")
+	require.Contains(t, content, "(Seite 1 von 1) Tj")
+	require.NotContains(t, content, "(Timesheet) Tj")
+}
+
+func TestRenderPDF_UnknownDetail(t *testing.T) {
+	var buf bytes.Buffer
+	err := timesheet.RenderPDF(&buf, worklog.Entries{}, &timesheet.PDFOpts{
+		Detail: "weekly",
+		Start:  time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local),
+		End:    time.Date(2021, 10, 11, 0, 0, 0, 0, time.Local),
+	})
+	require.ErrorContains(t, err, timesheet.ErrUnknownDetail.Error())
+}
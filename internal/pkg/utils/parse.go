@@ -0,0 +1,279 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTimestamp wraps the errors of parsing timestamps.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrInvalidDuration wraps the errors of parsing durations.
+	ErrInvalidDuration = errors.New("invalid duration")
+
+	// TimestampLayouts lists the layouts of the timestamps accepted from the
+	// remote APIs, in the order of trying them. The fractional seconds are
+	// optional in every layout. The layouts without offset are parsed in the
+	// given location.
+	TimestampLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999Z0700",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999Z0700",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		DateFormatISO8601.String(),
+		time.RFC1123Z,
+		time.RFC1123,
+	}
+
+	// isoDurationRegex matches the ISO 8601 durations of time, like "PT1H30M"
+	// or "P1DT2H". The years and months are not supported, as their length
+	// varies.
+	isoDurationRegex = regexp.MustCompile(`^P(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+	// clockDurationRegex matches the durations in clock format, like "1:30" or
+	// "01:30:15.5".
+	clockDurationRegex = regexp.MustCompile(`^(\d+):([0-5]?\d)(?::([0-5]?\d(?:\.\d+)?))?$`)
+)
+
+// ParseError represents a value that cannot be parsed as a timestamp or
+// duration, describing the accepted formats.
+type ParseError struct {
+	// Err is ErrInvalidTimestamp or ErrInvalidDuration.
+	Err     error
+	Value   string
+	Formats []string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v %q, expected one of %s", e.Err, e.Value, strings.Join(e.Formats, ", "))
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseUnixTimestamp returns the time of the UNIX timestamp. The timestamps
+// greater than the seconds of year 9999 are treated as milliseconds, since
+// some APIs return the timestamps in milliseconds.
+func ParseUnixTimestamp(timestamp float64) time.Time {
+	const maxSeconds = 253402300799
+
+	if math.Abs(timestamp) > maxSeconds {
+		timestamp /= 1000
+	}
+
+	seconds, fraction := math.Modf(timestamp)
+	return time.Unix(int64(seconds), int64(math.Round(fraction*float64(time.Second))))
+}
+
+// ParseTimestamp parses the timestamp returned by a remote API, trying the
+// TimestampLayouts and UNIX timestamps in seconds or milliseconds. The
+// timestamps without offset are parsed in the location. If the value cannot be
+// parsed, a ParseError returns.
+func ParseTimestamp(value string, loc *time.Location) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+
+	for _, layout := range TimestampLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if timestamp, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsInf(timestamp, 0) && !math.IsNaN(timestamp) {
+		return ParseUnixTimestamp(timestamp), nil
+	}
+
+	return time.Time{}, &ParseError{
+		Err:     ErrInvalidTimestamp,
+		Value:   value,
+		Formats: []string{"RFC 3339", "ISO 8601 date", "RFC 1123", "UNIX timestamp"},
+	}
+}
+
+// parseDecimal parses the number having decimal point or comma.
+func parseDecimal(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// parseISODuration parses the ISO 8601 duration, like "PT1H30M".
+func parseISODuration(value string) (time.Duration, bool) {
+	matches := isoDurationRegex.FindStringSubmatch(value)
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, false
+	}
+
+	units := []time.Duration{time.Hour * 24 * 7, time.Hour * 24, time.Hour, time.Minute, time.Second}
+
+	var duration float64
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+
+		amount, err := parseDecimal(matches[i+1])
+		if err != nil {
+			return 0, false
+		}
+
+		duration += amount * float64(unit)
+	}
+
+	return time.Duration(math.Round(duration)), true
+}
+
+// parseClockDuration parses the duration in clock format, like "1:30".
+func parseClockDuration(value string) (time.Duration, bool) {
+	matches := clockDurationRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, false
+	}
+
+	hours, _ := strconv.Atoi(matches[1])
+	minutes, _ := strconv.Atoi(matches[2])
+
+	var seconds float64
+	if matches[3] != "" {
+		seconds, _ = strconv.ParseFloat(matches[3], 64)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(math.Round(seconds*float64(time.Second))), true
+}
+
+// unitName returns the name of the duration unit used in the errors.
+func unitName(unit time.Duration) string {
+	switch unit {
+	case time.Hour:
+		return "hours"
+	case time.Minute:
+		return "minutes"
+	case time.Second:
+		return "seconds"
+	case time.Millisecond:
+		return "milliseconds"
+	default:
+		return unit.String() + " units"
+	}
+}
+
+// ParseDuration parses the duration returned by a remote API. The plain
+// numbers, like "5400" or "1.5", are multiplied by the unit. Besides, Go
+// durations, like "1h30m", ISO 8601 durations, like "PT1H30M", and clock
+// durations, like "1:30" or "01:30:00", are accepted. If the value cannot be
+// parsed, a ParseError returns.
+func ParseDuration(value string, unit time.Duration) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	negative := strings.HasPrefix(trimmed, "-")
+	unsigned := strings.TrimPrefix(trimmed, "-")
+
+	if amount, err := parseDecimal(unsigned); err == nil && !math.IsInf(amount, 0) && !math.IsNaN(amount) {
+		duration := time.Duration(math.Round(amount * float64(unit)))
+		if negative {
+			duration = -duration
+		}
+
+		return duration, nil
+	}
+
+	if duration, err := time.ParseDuration(trimmed); err == nil {
+		return duration, nil
+	}
+
+	duration, ok := parseISODuration(strings.ToUpper(unsigned))
+	if !ok {
+		duration, ok = parseClockDuration(unsigned)
+	}
+
+	if ok {
+		if negative {
+			duration = -duration
+		}
+
+		return duration, nil
+	}
+
+	return 0, &ParseError{
+		Err:     ErrInvalidDuration,
+		Value:   value,
+		Formats: []string{"number of " + unitName(unit), "Go duration", "ISO 8601 duration", "clock duration"},
+	}
+}
+
+// unquote returns the string of the JSON value, or the number as string. If
+// the value is null or an empty string, an empty string returns.
+func unquote(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	return string(data), nil
+}
+
+// Timestamp is a time decoded from JSON tolerantly, accepting the timestamps
+// parsed by ParseTimestamp as strings or numbers. The timestamps without offset
+// are in the local time zone. Null and empty strings are decoded as the zero
+// time. It is encoded as RFC 3339 time.
+type Timestamp struct {
+	time.Time
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTimestamp, err)
+	}
+
+	if value == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	t.Time, err = ParseTimestamp(value, time.Local)
+	return err
+}
+
+// Duration is a duration decoded from JSON tolerantly, accepting the durations
+// parsed by ParseDuration as strings or numbers. The plain numbers are in
+// seconds. Null and empty strings are decoded as zero duration. It is encoded
+// as the number of seconds.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDuration, err)
+	}
+
+	if value == "" {
+		d.Duration = 0
+		return nil
+	}
+
+	d.Duration, err = ParseDuration(value, time.Second)
+	return err
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Seconds())
+}
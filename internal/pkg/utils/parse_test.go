@@ -0,0 +1,139 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	expected := time.Date(2021, 10, 2, 9, 30, 15, 0, time.UTC)
+
+	for _, value := range []string{
+		"2021-10-02T09:30:15Z",
+		"2021-10-02T09:30:15.000Z",
+		"2021-10-02T11:30:15+02:00",
+		"2021-10-02T11:30:15.0000000+0200",
+		"2021-10-02 09:30:15Z",
+		"2021-10-02 10:30:15",
+		"2021-10-02T10:30:15.000",
+		"  2021-10-02T09:30:15Z ",
+		"Sat, 02 Oct 2021 09:30:15 GMT",
+		"1633167015",
+		"1633167015000",
+	} {
+		timestamp, err := utils.ParseTimestamp(value, loc)
+		require.Nil(t, err, value)
+		require.True(t, expected.Equal(timestamp), "%s parsed as %s", value, timestamp)
+	}
+}
+
+func TestParseTimestamp_Date(t *testing.T) {
+	timestamp, err := utils.ParseTimestamp("2021-10-02", time.Local)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 10, 2, 0, 0, 0, 0, time.Local), timestamp)
+}
+
+func TestParseTimestamp_Invalid(t *testing.T) {
+	_, err := utils.ParseTimestamp("02/10/2021", time.Local)
+	require.ErrorIs(t, err, utils.ErrInvalidTimestamp)
+	require.EqualError(t, err, `invalid timestamp "02/10/2021", expected one of RFC 3339, ISO 8601 date, RFC 1123, UNIX timestamp`)
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"5400":     time.Minute * 90,
+		"5400.0":   time.Minute * 90,
+		"-60":      -time.Minute,
+		"1h30m":    time.Minute * 90,
+		"PT1H30M":  time.Minute * 90,
+		"pt90m":    time.Minute * 90,
+		"P1DT1H":   time.Hour * 25,
+		"PT0.5H":   time.Minute * 30,
+		"1:30:00":  time.Minute * 90,
+		"01:30":    time.Minute * 90,
+		"0:00:1.5": time.Millisecond * 1500,
+	} {
+		duration, err := utils.ParseDuration(value, time.Second)
+		require.Nil(t, err, value)
+		require.Equal(t, expected, duration, value)
+	}
+}
+
+func TestParseDuration_Unit(t *testing.T) {
+	duration, err := utils.ParseDuration("1,5", time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, time.Minute*90, duration)
+}
+
+func TestParseDuration_Invalid(t *testing.T) {
+	for _, value := range []string{"", "P", "PT", "1:75", "an hour"} {
+		_, err := utils.ParseDuration(value, time.Hour)
+		require.ErrorIs(t, err, utils.ErrInvalidDuration, value)
+	}
+
+	_, err := utils.ParseDuration("an hour", time.Hour)
+	require.EqualError(t, err, `invalid duration "an hour", expected one of number of hours, Go duration, ISO 8601 duration, clock duration`)
+}
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	var value struct {
+		String  utils.Timestamp `json:"string"`
+		Number  utils.Timestamp `json:"number"`
+		Null    utils.Timestamp `json:"null"`
+		Empty   utils.Timestamp `json:"empty"`
+		Omitted utils.Timestamp `json:"omitted"`
+	}
+
+	err := json.Unmarshal([]byte(`{"string":"2021-10-02T09:30:15.123+0000","number":1633167015,"null":null,"empty":""}`), &value)
+	require.Nil(t, err)
+
+	require.True(t, time.Date(2021, 10, 2, 9, 30, 15, 123000000, time.UTC).Equal(value.String.Time))
+	require.True(t, time.Date(2021, 10, 2, 9, 30, 15, 0, time.UTC).Equal(value.Number.Time))
+	require.True(t, value.Null.IsZero())
+	require.True(t, value.Empty.IsZero())
+	require.True(t, value.Omitted.IsZero())
+}
+
+func TestTimestamp_UnmarshalJSON_Invalid(t *testing.T) {
+	var value struct {
+		Start utils.Timestamp `json:"start"`
+	}
+
+	err := json.Unmarshal([]byte(`{"start":"yesterday"}`), &value)
+	require.ErrorIs(t, err, utils.ErrInvalidTimestamp)
+
+	err = json.Unmarshal([]byte(`{"start":true}`), &value)
+	require.ErrorIs(t, err, utils.ErrInvalidTimestamp)
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(utils.Timestamp{Time: time.Date(2021, 10, 2, 9, 30, 15, 0, time.UTC)})
+	require.Nil(t, err)
+	require.Equal(t, `"2021-10-02T09:30:15Z"`, string(data))
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var value struct {
+		Number utils.Duration `json:"number"`
+		String utils.Duration `json:"string"`
+		Null   utils.Duration `json:"null"`
+	}
+
+	err := json.Unmarshal([]byte(`{"number":5400,"string":"PT30M","null":null}`), &value)
+	require.Nil(t, err)
+	require.Equal(t, time.Minute*90, value.Number.Duration)
+	require.Equal(t, time.Minute*30, value.String.Duration)
+	require.Equal(t, time.Duration(0), value.Null.Duration)
+
+	data, err := json.Marshal(value.Number)
+	require.Nil(t, err)
+	require.Equal(t, "5400", string(data))
+
+	err = json.Unmarshal([]byte(`{"number":"soon"}`), &value)
+	require.ErrorIs(t, err, utils.ErrInvalidDuration)
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var urlRegex = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// ExtractURLs returns the HTTP and HTTPS URLs found in the text. Trailing
+// punctuation is not considered as part of the URL.
+func ExtractURLs(text string) []string {
+	var urls []string
+
+	for _, url := range urlRegex.FindAllString(text, -1) {
+		urls = append(urls, strings.TrimRight(url, ".,;:!?)]}"))
+	}
+
+	return urls
+}
@@ -0,0 +1,17 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractURLs(t *testing.T) {
+	urls := utils.ExtractURLs("Reviewed https://github.com/gabor-boros/minutes/pull/1, then (http://example.com/a?b=c).")
+	require.Equal(t, []string{"https://github.com/gabor-boros/minutes/pull/1", "http://example.com/a?b=c"}, urls)
+}
+
+func TestExtractURLs_NoURL(t *testing.T) {
+	require.Nil(t, utils.ExtractURLs("No links here"))
+}
@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxBodySize is the maximum accepted size of a webhook request body.
+	MaxBodySize int64 = 1 << 20
+)
+
+var (
+	// ErrInvalidSignature returns when the webhook request's signature is
+	// missing or not matching the one calculated using the secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload returns when the webhook request's payload cannot be
+	// parsed.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// Event represents a change reported by a source through a webhook.
+type Event struct {
+	// User is the source user ID who's entries changed. It can be empty if the
+	// provider does not report the user.
+	User string
+	// Date is the time of the changed entry. The sync is triggered for the
+	// day of the Date.
+	Date time.Time
+	// ValidationCode is set when the provider is validating the webhook
+	// endpoint. Validation requests shall not trigger a sync.
+	ValidationCode string
+}
+
+// Provider specifies the functions used to verify and parse webhook requests.
+type Provider interface {
+	// Verify returns ErrInvalidSignature if the request is not signed using
+	// the provider's secret.
+	Verify(header http.Header, body []byte) error
+	// Parse parses the request body and returns the event. If the request is
+	// not related to entry changes, nil is returned without error.
+	Parse(header http.Header, body []byte) (*Event, error)
+}
+
+// verifyHMAC verifies the hex-encoded, SHA256 based HMAC signature prefixed by
+// "sha256=".
+func verifyHMAC(secret string, signature string, body []byte) error {
+	rawSignature, found := strings.CutPrefix(signature, "sha256=")
+	if !found {
+		return ErrInvalidSignature
+	}
+
+	decodedSignature, err := hex.DecodeString(rawSignature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), decodedSignature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ClockifyProvider handles the webhooks sent by Clockify. Clockify sends the
+// webhook's token in the "Clockify-Signature" header.
+type ClockifyProvider struct {
+	Secret string
+}
+
+func (p *ClockifyProvider) Verify(header http.Header, _ []byte) error {
+	signature := header.Get("Clockify-Signature")
+	if signature == "" || subtle.ConstantTimeCompare([]byte(signature), []byte(p.Secret)) != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (p *ClockifyProvider) Parse(_ http.Header, body []byte) (*Event, error) {
+	var payload struct {
+		UserID       string `json:"userId"`
+		TimeInterval struct {
+			Start time.Time `json:"start"`
+		} `json:"timeInterval"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidPayload, err)
+	}
+
+	if payload.TimeInterval.Start.IsZero() {
+		return nil, nil
+	}
+
+	return &Event{
+		User: payload.UserID,
+		Date: payload.TimeInterval.Start,
+	}, nil
+}
+
+// TogglProvider handles the webhooks sent by Toggl Track. Toggl Track signs
+// the payload using HMAC and sends the signature in the
+// "X-Webhook-Signature-256" header.
+type TogglProvider struct {
+	Secret string
+}
+
+func (p *TogglProvider) Verify(header http.Header, body []byte) error {
+	return verifyHMAC(p.Secret, header.Get("X-Webhook-Signature-256"), body)
+}
+
+func (p *TogglProvider) Parse(_ http.Header, body []byte) (*Event, error) {
+	var payload struct {
+		ValidationCode string          `json:"validation_code"`
+		Payload        json.RawMessage `json:"payload"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidPayload, err)
+	}
+
+	if payload.ValidationCode != "" {
+		return &Event{ValidationCode: payload.ValidationCode}, nil
+	}
+
+	var entry struct {
+		UserID int       `json:"user_id"`
+		Start  time.Time `json:"start"`
+	}
+
+	// Besides time entries, the payload can be a string (e.g. "ping") or
+	// other kind of objects, which are not relevant
+	if err := json.Unmarshal(payload.Payload, &entry); err != nil || entry.Start.IsZero() {
+		return nil, nil
+	}
+
+	event := &Event{Date: entry.Start}
+	if entry.UserID != 0 {
+		event.User = fmt.Sprint(entry.UserID)
+	}
+
+	return event, nil
+}
+
+// GitHubProvider handles the webhooks sent by GitHub. GitHub signs the
+// payload using HMAC and sends the signature in the "X-Hub-Signature-256"
+// header.
+type GitHubProvider struct {
+	Secret string
+}
+
+func (p *GitHubProvider) Verify(header http.Header, body []byte) error {
+	return verifyHMAC(p.Secret, header.Get("X-Hub-Signature-256"), body)
+}
+
+func (p *GitHubProvider) Parse(header http.Header, body []byte) (*Event, error) {
+	if header.Get("X-GitHub-Event") == "ping" {
+		return nil, nil
+	}
+
+	var payload struct {
+		Sender struct {
+			Login string `json:"login"`
+		} `json:"sender"`
+		HeadCommit *struct {
+			Timestamp time.Time `json:"timestamp"`
+		} `json:"head_commit"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidPayload, err)
+	}
+
+	// Only push events are carrying the time of the change, for other events
+	// the time of delivery is used
+	date := time.Now()
+	if payload.HeadCommit != nil && !payload.HeadCommit.Timestamp.IsZero() {
+		date = payload.HeadCommit.Timestamp
+	}
+
+	return &Event{
+		User: payload.Sender.Login,
+		Date: date,
+	}, nil
+}
+
+// NewHandler returns an HTTP handler that verifies and parses the webhook
+// requests of the provider, then calls handle with the parsed event.
+// Validation requests are answered without calling handle.
+func NewHandler(provider Provider, handle func(event *Event)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err = provider.Verify(r.Header, body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		event, err := provider.Parse(r.Header, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if event != nil && event.ValidationCode != "" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"validation_code": event.ValidationCode,
+			})
+			return
+		}
+
+		if event != nil {
+			handle(event)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
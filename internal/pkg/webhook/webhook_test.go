@@ -0,0 +1,132 @@
+package webhook_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sendWebhook(t *testing.T, provider webhook.Provider, header http.Header, body []byte) (*httptest.ResponseRecorder, *webhook.Event) {
+	var handledEvent *webhook.Event
+
+	handler := webhook.NewHandler(provider, func(event *webhook.Event) {
+		handledEvent = event
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder, handledEvent
+}
+
+func TestClockifyProvider(t *testing.T) {
+	body := []byte(`{"id":"1","userId":"steve-rogers","timeInterval":{"start":"2021-10-02T05:00:00Z"}}`)
+	header := http.Header{"Clockify-Signature": {"secret"}}
+
+	recorder, event := sendWebhook(t, &webhook.ClockifyProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, &webhook.Event{
+		User: "steve-rogers",
+		Date: time.Date(2021, 10, 2, 5, 0, 0, 0, time.UTC),
+	}, event)
+}
+
+func TestClockifyProvider_InvalidSignature(t *testing.T) {
+	body := []byte(`{}`)
+	header := http.Header{"Clockify-Signature": {"other"}}
+
+	recorder, event := sendWebhook(t, &webhook.ClockifyProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Nil(t, event)
+}
+
+func TestTogglProvider(t *testing.T) {
+	body := []byte(`{"event_id":1,"payload":{"id":2,"user_id":123,"start":"2021-10-02T05:00:00Z"}}`)
+	header := http.Header{"X-Webhook-Signature-256": {sign("secret", body)}}
+
+	recorder, event := sendWebhook(t, &webhook.TogglProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, &webhook.Event{
+		User: "123",
+		Date: time.Date(2021, 10, 2, 5, 0, 0, 0, time.UTC),
+	}, event)
+}
+
+func TestTogglProvider_Validation(t *testing.T) {
+	body := []byte(`{"payload":"ping","validation_code":"abc"}`)
+	header := http.Header{"X-Webhook-Signature-256": {sign("secret", body)}}
+
+	recorder, event := sendWebhook(t, &webhook.TogglProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"validation_code":"abc"}`, recorder.Body.String())
+	require.Nil(t, event)
+}
+
+func TestTogglProvider_InvalidSignature(t *testing.T) {
+	body := []byte(`{"payload":"ping"}`)
+	header := http.Header{"X-Webhook-Signature-256": {sign("other", body)}}
+
+	recorder, _ := sendWebhook(t, &webhook.TogglProvider{Secret: "secret"}, header, body)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestGitHubProvider(t *testing.T) {
+	body := []byte(`{"sender":{"login":"steve-rogers"},"head_commit":{"timestamp":"2021-10-02T05:00:00Z"}}`)
+	header := http.Header{
+		"X-Hub-Signature-256": {sign("secret", body)},
+		"X-Github-Event":      {"push"},
+	}
+
+	recorder, event := sendWebhook(t, &webhook.GitHubProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, &webhook.Event{
+		User: "steve-rogers",
+		Date: time.Date(2021, 10, 2, 5, 0, 0, 0, time.UTC),
+	}, event)
+}
+
+func TestGitHubProvider_Ping(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	header := http.Header{
+		"X-Hub-Signature-256": {sign("secret", body)},
+		"X-Github-Event":      {"ping"},
+	}
+
+	recorder, event := sendWebhook(t, &webhook.GitHubProvider{Secret: "secret"}, header, body)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Nil(t, event)
+}
+
+func TestNewHandler_MethodNotAllowed(t *testing.T) {
+	handler := webhook.NewHandler(&webhook.GitHubProvider{Secret: "secret"}, func(*webhook.Event) {})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
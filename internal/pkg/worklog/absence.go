@@ -0,0 +1,98 @@
+package worklog
+
+import (
+	"regexp"
+	"time"
+)
+
+// Absence represents the kind of absence an entry stands for.
+type Absence string
+
+const (
+	// AbsenceVacation stands for paid or unpaid leave.
+	AbsenceVacation Absence = "vacation"
+	// AbsenceSick stands for sick leave.
+	AbsenceSick Absence = "sick"
+
+	// DefaultAbsenceDuration is the duration of a full day absence, if not
+	// configured otherwise.
+	DefaultAbsenceDuration time.Duration = time.Hour * 8
+)
+
+var (
+	// Absences lists the known absence kinds.
+	Absences = []Absence{AbsenceVacation, AbsenceSick}
+
+	sickAbsenceRegex = regexp.MustCompile(`(?i)\b(sick|sickness|illness|ill)\b`)
+)
+
+// ParseAbsence returns the absence kind of a time off policy or type name, like
+// "Sick leave" or "Annual leave". Every absence that is not a sick leave is
+// treated as vacation.
+func ParseAbsence(name string) Absence {
+	if sickAbsenceRegex.MatchString(name) {
+		return AbsenceSick
+	}
+
+	return AbsenceVacation
+}
+
+// AbsenceOpts represents the options used to create absence entries.
+type AbsenceOpts struct {
+	Absence Absence
+	Summary string
+	Notes   string
+	// Start and End are the boundaries of the absence period. Every working
+	// day starting within the period gets an entry.
+	Start time.Time
+	End   time.Time
+	// DayDuration is the duration of a full day absence.
+	DayDuration time.Duration
+	// HalfDay indicates that only half of the DayDuration is absence per day.
+	HalfDay bool
+	Links   []string
+	// SourceID is the ID of the absence at the source.
+	SourceID string
+}
+
+// NewAbsenceEntries creates an unbillable absence entry for every working day
+// of the absence period. Weekends are skipped.
+func NewAbsenceEntries(opts *AbsenceOpts) Entries {
+	var entries Entries
+
+	duration := opts.DayDuration
+	if duration == 0 {
+		duration = DefaultAbsenceDuration
+	}
+
+	if opts.HalfDay {
+		duration /= 2
+	}
+
+	year, month, day := opts.Start.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, opts.Start.Location())
+
+	for ; date.Before(opts.End); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+
+		entry := Entry{
+			Summary:            opts.Summary,
+			Notes:              opts.Notes,
+			Start:              date,
+			UnbillableDuration: duration,
+			Absence:            opts.Absence,
+		}
+
+		entry.AddLinks(opts.Links...)
+
+		if opts.SourceID != "" {
+			entry.Provenance.SourceIDs = []string{opts.SourceID}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -0,0 +1,66 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAbsence(t *testing.T) {
+	require.Equal(t, worklog.AbsenceSick, worklog.ParseAbsence("Sick leave"))
+	require.Equal(t, worklog.AbsenceSick, worklog.ParseAbsence("ILLNESS"))
+	require.Equal(t, worklog.AbsenceVacation, worklog.ParseAbsence("Annual leave"))
+	require.Equal(t, worklog.AbsenceVacation, worklog.ParseAbsence("Billing"))
+}
+
+func TestNewAbsenceEntries(t *testing.T) {
+	// Friday to Monday, the weekend must be skipped
+	start := time.Date(2021, 10, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 10, 11, 23, 59, 59, 0, time.UTC)
+
+	entries := worklog.NewAbsenceEntries(&worklog.AbsenceOpts{
+		Absence: worklog.AbsenceVacation,
+		Summary: "Annual leave",
+		Start:   start,
+		End:     end,
+		Links:   []string{"https://example.com/leave"},
+	})
+
+	require.Equal(t, worklog.Entries{
+		{
+			Summary:            "Annual leave",
+			Start:              start,
+			UnbillableDuration: worklog.DefaultAbsenceDuration,
+			Links:              []string{"https://example.com/leave"},
+			Absence:            worklog.AbsenceVacation,
+		},
+		{
+			Summary:            "Annual leave",
+			Start:              start.AddDate(0, 0, 3),
+			UnbillableDuration: worklog.DefaultAbsenceDuration,
+			Links:              []string{"https://example.com/leave"},
+			Absence:            worklog.AbsenceVacation,
+		},
+	}, entries)
+	require.True(t, entries[0].IsAbsence())
+}
+
+func TestNewAbsenceEntries_HalfDay(t *testing.T) {
+	start := time.Date(2021, 10, 8, 13, 0, 0, 0, time.UTC)
+
+	entries := worklog.NewAbsenceEntries(&worklog.AbsenceOpts{
+		Absence:     worklog.AbsenceSick,
+		Summary:     "Sick leave",
+		Start:       start,
+		End:         start.Add(time.Hour * 4),
+		DayDuration: time.Hour * 6,
+		HalfDay:     true,
+	})
+
+	require.Len(t, entries, 1)
+	require.Equal(t, time.Date(2021, 10, 8, 0, 0, 0, 0, time.UTC), entries[0].Start)
+	require.Equal(t, time.Hour*3, entries[0].UnbillableDuration)
+	require.Equal(t, worklog.AbsenceSick, entries[0].Absence)
+}
@@ -0,0 +1,166 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// AggregationNone keeps the entries as fetched.
+	AggregationNone string = "none"
+	// AggregationPomodoro combines the consecutive pomodoro-length entries of
+	// the same task into one entry.
+	AggregationPomodoro string = "pomodoro"
+
+	// DefaultPomodoroMaxDuration is the longest entry considered a pomodoro.
+	DefaultPomodoroMaxDuration = time.Minute * 30
+	// DefaultPomodoroMaxBreak is the longest break between two consecutive
+	// pomodoros.
+	DefaultPomodoroMaxBreak = time.Minute * 15
+)
+
+var (
+	// AggregationModes lists the supported aggregation modes.
+	AggregationModes = []string{AggregationNone, AggregationPomodoro}
+
+	// ErrUnknownAggregationMode returns when the aggregation mode is not
+	// supported.
+	ErrUnknownAggregationMode = errors.New("unknown aggregation mode")
+
+	// ErrInvalidPomodoroDurations returns when the max duration of a pomodoro is
+	// not positive or the max break is negative.
+	ErrInvalidPomodoroDurations = errors.New("invalid pomodoro durations")
+)
+
+// AggregationOpts represents the options of aggregating the entries.
+type AggregationOpts struct {
+	Mode string
+	// PomodoroMaxDuration is the longest entry considered a pomodoro.
+	PomodoroMaxDuration time.Duration
+	// PomodoroMaxBreak is the longest break between the end of a pomodoro and
+	// the start of the next one, so they are considered consecutive.
+	PomodoroMaxBreak time.Duration
+}
+
+// Validate returns an error if the mode is unknown or the durations are
+// invalid.
+func (o *AggregationOpts) Validate() error {
+	isKnown := false
+	for _, mode := range AggregationModes {
+		if o.Mode == mode {
+			isKnown = true
+			break
+		}
+	}
+
+	if !isKnown {
+		return fmt.Errorf("%w: %s", ErrUnknownAggregationMode, o.Mode)
+	}
+
+	if o.PomodoroMaxDuration <= 0 || o.PomodoroMaxBreak < 0 {
+		return ErrInvalidPomodoroDurations
+	}
+
+	return nil
+}
+
+// isPomodoro returns true if the entry has a time of day and it is not longer
+// than a pomodoro.
+func isPomodoro(entry *Entry, opts *AggregationOpts) bool {
+	duration := entry.BillableDuration + entry.UnbillableDuration
+	return !entry.Start.IsZero() && !entry.IsAbsence() && duration > 0 && duration <= opts.PomodoroMaxDuration
+}
+
+// isNextPomodoro returns true if the next entry is a pomodoro of the same task
+// as the previous pomodoro, started within the max break after it.
+func isNextPomodoro(previous *Entry, next *Entry, opts *AggregationOpts) bool {
+	if !isPomodoro(previous, opts) || !isPomodoro(next, opts) {
+		return false
+	}
+
+	isSameTask := previous.Client == next.Client && previous.Project == next.Project && previous.Task == next.Task
+	previousEnd := previous.Start.Add(previous.BillableDuration + previous.UnbillableDuration)
+
+	return isSameTask && next.Start.Sub(previousEnd) <= opts.PomodoroMaxBreak
+}
+
+// aggregatePomodoros combines the pomodoros into one entry, starting at the
+// first pomodoro. The number of the pomodoros is added to the summary.
+func aggregatePomodoros(pomodoros []Entry) Entry {
+	aggregated := pomodoros[0]
+	aggregated.Links = append([]string(nil), aggregated.Links...)
+
+	for i := 1; i < len(pomodoros); i++ {
+		pomodoro := pomodoros[i]
+
+		aggregated.BillableDuration += pomodoro.BillableDuration
+		aggregated.UnbillableDuration += pomodoro.UnbillableDuration
+		aggregated.Notes = mergeNotes(aggregated.Notes, pomodoro.Notes)
+		aggregated.AddLinks(pomodoro.Links...)
+		aggregated.mergeProvenance(&pomodoro)
+	}
+
+	aggregated.Summary = fmt.Sprintf("%s (%d pomodoros)", aggregated.Summary, len(pomodoros))
+	aggregated.AddTransformation("aggregated %d pomodoros", len(pomodoros))
+
+	return aggregated
+}
+
+// AggregateEntries combines the entries as set by the aggregation mode. In
+// pomodoro mode, the consecutive pomodoros of the same task, like the ones
+// tracked by a stopwatch, are combined into one entry, so the target is not
+// flooded by short entries. Any other entry between the pomodoros in time
+// breaks the sequence. The aggregated entries keep the order of their first
+// pomodoro.
+func AggregateEntries(entries Entries, opts *AggregationOpts) Entries {
+	aggregatedEntries := make(Entries, len(entries))
+	copy(aggregatedEntries, entries)
+
+	if opts.Mode != AggregationPomodoro {
+		return aggregatedEntries
+	}
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].Start.Before(entries[order[j]].Start)
+	})
+
+	// sequenceStart maps the entries to the first entry of their sequence
+	sequenceStart := make([]int, len(entries))
+	sequences := map[int][]Entry{}
+
+	for position, i := range order {
+		if position > 0 {
+			previous := order[position-1]
+			if isNextPomodoro(&entries[previous], &entries[i], opts) {
+				sequenceStart[i] = sequenceStart[previous]
+				sequences[sequenceStart[i]] = append(sequences[sequenceStart[i]], entries[i])
+				continue
+			}
+		}
+
+		sequenceStart[i] = i
+		sequences[i] = []Entry{entries[i]}
+	}
+
+	aggregatedEntries = aggregatedEntries[:0]
+	for i := range entries {
+		if sequenceStart[i] != i {
+			continue
+		}
+
+		if sequence := sequences[i]; len(sequence) > 1 {
+			aggregatedEntries = append(aggregatedEntries, aggregatePomodoros(sequence))
+		} else {
+			aggregatedEntries = append(aggregatedEntries, entries[i])
+		}
+	}
+
+	return aggregatedEntries
+}
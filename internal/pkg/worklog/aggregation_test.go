@@ -0,0 +1,93 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateEntries(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+	task := worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"}
+	otherTask := worklog.IDNameField{ID: "CPT-2015", Name: "CPT-2015"}
+
+	pomodoro := func(task worklog.IDNameField, offset time.Duration, notes string, id string) worklog.Entry {
+		return worklog.Entry{
+			Task:             task,
+			Summary:          "Fix the flaky test",
+			Notes:            notes,
+			Start:            start.Add(offset),
+			BillableDuration: time.Minute * 25,
+			Provenance:       worklog.Provenance{Source: "staged", SourceIDs: []string{id}},
+		}
+	}
+
+	entries := worklog.Entries{
+		pomodoro(task, 0, "reproduce", "1"),
+		pomodoro(otherTask, time.Hour*3, "", "5"),
+		pomodoro(task, time.Minute*30, "fix", "2"),
+		pomodoro(task, time.Minute*60, "", "3"),
+		// The break is too long
+		pomodoro(task, time.Hour*2, "", "4"),
+	}
+
+	aggregated := worklog.AggregateEntries(entries, &worklog.AggregationOpts{
+		Mode:                worklog.AggregationPomodoro,
+		PomodoroMaxDuration: worklog.DefaultPomodoroMaxDuration,
+		PomodoroMaxBreak:    worklog.DefaultPomodoroMaxBreak,
+	})
+
+	require.Len(t, aggregated, 3)
+
+	require.Equal(t, "Fix the flaky test (3 pomodoros)", aggregated[0].Summary)
+	require.Equal(t, "reproduce; fix", aggregated[0].Notes)
+	require.Equal(t, start, aggregated[0].Start)
+	require.Equal(t, time.Minute*75, aggregated[0].BillableDuration)
+	require.Equal(t, []string{"1", "2", "3"}, aggregated[0].Provenance.SourceIDs)
+	require.Equal(t, []string{"aggregated 3 pomodoros"}, aggregated[0].Provenance.Transformations)
+
+	require.Equal(t, entries[1], aggregated[1])
+	require.Equal(t, entries[4], aggregated[2])
+
+	// The original entries are kept untouched
+	require.Equal(t, "Fix the flaky test", entries[0].Summary)
+}
+
+func TestAggregateEntries_InterruptedByOtherEntry(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+	task := worklog.IDNameField{ID: "CPT-2014", Name: "CPT-2014"}
+
+	entries := worklog.Entries{
+		{Task: task, Start: start, BillableDuration: time.Minute * 25},
+		{Task: task, Start: start.Add(time.Minute * 25), BillableDuration: time.Hour},
+		{Task: task, Start: start.Add(time.Minute * 85), BillableDuration: time.Minute * 25},
+	}
+
+	aggregated := worklog.AggregateEntries(entries, &worklog.AggregationOpts{
+		Mode:                worklog.AggregationPomodoro,
+		PomodoroMaxDuration: worklog.DefaultPomodoroMaxDuration,
+		PomodoroMaxBreak:    worklog.DefaultPomodoroMaxBreak,
+	})
+
+	require.Equal(t, entries, aggregated)
+}
+
+func TestAggregateEntries_None(t *testing.T) {
+	start := time.Date(2021, 10, 2, 9, 0, 0, 0, time.UTC)
+
+	entries := worklog.Entries{
+		{Summary: "first", Start: start, BillableDuration: time.Minute * 25},
+		{Summary: "first", Start: start.Add(time.Minute * 30), BillableDuration: time.Minute * 25},
+	}
+
+	aggregated := worklog.AggregateEntries(entries, &worklog.AggregationOpts{Mode: worklog.AggregationNone})
+	require.Equal(t, entries, aggregated)
+}
+
+func TestAggregationOpts_Validate(t *testing.T) {
+	require.Nil(t, (&worklog.AggregationOpts{Mode: worklog.AggregationPomodoro, PomodoroMaxDuration: time.Minute * 30}).Validate())
+	require.ErrorIs(t, (&worklog.AggregationOpts{Mode: "daily", PomodoroMaxDuration: time.Minute * 30}).Validate(), worklog.ErrUnknownAggregationMode)
+	require.ErrorIs(t, (&worklog.AggregationOpts{Mode: worklog.AggregationPomodoro}).Validate(), worklog.ErrInvalidPomodoroDurations)
+}
@@ -0,0 +1,139 @@
+package worklog
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultAnomalyFactor is how many times the total of a day may differ
+	// from the typical total, if not configured otherwise.
+	DefaultAnomalyFactor float64 = 2
+	// DefaultAnomalyMinDays is the number of days needed to tell the typical
+	// total, if not configured otherwise.
+	DefaultAnomalyMinDays int = 10
+)
+
+// DailyTotals keeps track of the time spent per day, keyed by the date in
+// YYYY-MM-DD format.
+type DailyTotals map[string]time.Duration
+
+// Update replaces the days between start and end with the time spent of the
+// entries. The days without entries are removed, since a day not tracked cannot
+// be told from a day off. The entries outside the period are ignored.
+func (t DailyTotals) Update(entries Entries, start time.Time, end time.Time) {
+	year, month, day := start.Local().Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+
+	days := map[string]bool{}
+	for ; date.Before(end); date = date.AddDate(0, 0, 1) {
+		key := date.Format(overtimeDateFormat)
+		days[key] = true
+		delete(t, key)
+	}
+
+	for _, entry := range entries {
+		key := entry.Start.Local().Format(overtimeDateFormat)
+		if duration := entry.BillableDuration + entry.UnbillableDuration; days[key] && duration > 0 {
+			t[key] += duration
+		}
+	}
+}
+
+// Since returns the totals of the days starting from the date.
+func (t DailyTotals) Since(date time.Time) DailyTotals {
+	from := date.Local().Format(overtimeDateFormat)
+	totals := DailyTotals{}
+
+	for day, total := range t {
+		if day >= from {
+			totals[day] = total
+		}
+	}
+
+	return totals
+}
+
+// Typical returns the median of the totals, so a few outliers do not change
+// what is typical. If there are no totals, zero returns.
+func (t DailyTotals) Typical() time.Duration {
+	if len(t) == 0 {
+		return 0
+	}
+
+	var totals []time.Duration
+	for _, total := range t {
+		totals = append(totals, total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i] < totals[j]
+	})
+
+	middle := len(totals) / 2
+	if len(totals)%2 == 0 {
+		return (totals[middle-1] + totals[middle]) / 2
+	}
+
+	return totals[middle]
+}
+
+// AnomalyOpts represents the options of detecting the days differing from the
+// typical total.
+type AnomalyOpts struct {
+	// Factor is how many times the total of a day may be more or less than the
+	// typical total. If not greater than 1, no anomalies are detected.
+	Factor float64
+	// MinDays is the number of days needed to tell the typical total.
+	MinDays int
+	// Today is the day which is not over yet, so its total is compared only
+	// if it is more than typical.
+	Today time.Time
+}
+
+// Anomaly represents a day which total differs wildly from the typical total.
+type Anomaly struct {
+	Date    string
+	Total   time.Duration
+	Typical time.Duration
+}
+
+// IsHigh returns true if the total is more than typical, like when a timer was
+// left running. Otherwise, the total is less than typical, like when the
+// tracking was forgotten.
+func (a *Anomaly) IsHigh() bool {
+	return a.Total > a.Typical
+}
+
+// DetectAnomalies returns the days of the totals differing wildly from the
+// typical total of the history, ordered by date. If the history has not enough
+// days, no anomalies are detected.
+func DetectAnomalies(totals DailyTotals, history DailyTotals, opts *AnomalyOpts) []Anomaly {
+	if opts.Factor <= 1 || len(history) == 0 || len(history) < opts.MinDays {
+		return nil
+	}
+
+	typical := history.Typical()
+	today := opts.Today.Local().Format(overtimeDateFormat)
+
+	var anomalies []Anomaly
+
+	for date, total := range totals {
+		isHigh := float64(total) > float64(typical)*opts.Factor
+		isLow := float64(total)*opts.Factor < float64(typical) && date < today
+
+		if isHigh || isLow {
+			anomalies = append(anomalies, Anomaly{
+				Date:    date,
+				Total:   total,
+				Typical: typical,
+			})
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].Date < anomalies[j].Date
+	})
+
+	return anomalies
+}
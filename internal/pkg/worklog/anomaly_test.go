@@ -0,0 +1,109 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func getAnomalyTestHistory() worklog.DailyTotals {
+	return worklog.DailyTotals{
+		"2021-09-27": time.Hour * 8,
+		"2021-09-28": time.Hour * 7,
+		"2021-09-29": time.Hour * 9,
+		"2021-09-30": time.Hour * 8,
+		// An outlier does not change the typical total
+		"2021-10-01": time.Hour * 20,
+	}
+}
+
+func TestDailyTotals_Update(t *testing.T) {
+	totals := worklog.DailyTotals{
+		"2021-10-03": time.Hour,
+		"2021-10-04": time.Hour * 2,
+		"2021-10-05": time.Hour * 3,
+	}
+
+	entries := worklog.Entries{
+		{
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 6,
+		},
+		{
+			Start:              time.Date(2021, 10, 4, 15, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour * 3,
+		},
+		{
+			// Outside the period
+			Start:            time.Date(2021, 10, 6, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+		},
+	}
+
+	totals.Update(entries, time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local), time.Date(2021, 10, 6, 0, 0, 0, 0, time.Local))
+
+	require.Equal(t, worklog.DailyTotals{
+		"2021-10-03": time.Hour,
+		"2021-10-04": time.Hour * 9,
+	}, totals)
+}
+
+func TestDailyTotals_Since(t *testing.T) {
+	require.Equal(t, worklog.DailyTotals{
+		"2021-09-30": time.Hour * 8,
+		"2021-10-01": time.Hour * 20,
+	}, getAnomalyTestHistory().Since(time.Date(2021, 9, 30, 12, 0, 0, 0, time.Local)))
+}
+
+func TestDailyTotals_Typical(t *testing.T) {
+	require.Equal(t, time.Duration(0), worklog.DailyTotals{}.Typical())
+	require.Equal(t, time.Hour*8, getAnomalyTestHistory().Typical())
+
+	history := getAnomalyTestHistory()
+	delete(history, "2021-10-01")
+	require.Equal(t, time.Hour*8, history.Typical())
+
+	delete(history, "2021-09-29")
+	delete(history, "2021-09-30")
+	require.Equal(t, time.Hour*7+time.Minute*30, history.Typical())
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	totals := worklog.DailyTotals{
+		"2021-10-04": time.Minute * 30,
+		"2021-10-05": time.Hour * 17,
+		"2021-10-06": time.Hour * 6,
+		// Today is not over yet
+		"2021-10-07": time.Hour,
+	}
+
+	anomalies := worklog.DetectAnomalies(totals, getAnomalyTestHistory(), &worklog.AnomalyOpts{
+		Factor:  2,
+		MinDays: 5,
+		Today:   time.Date(2021, 10, 7, 10, 0, 0, 0, time.Local),
+	})
+
+	require.Equal(t, []worklog.Anomaly{
+		{Date: "2021-10-04", Total: time.Minute * 30, Typical: time.Hour * 8},
+		{Date: "2021-10-05", Total: time.Hour * 17, Typical: time.Hour * 8},
+	}, anomalies)
+
+	require.False(t, anomalies[0].IsHigh())
+	require.True(t, anomalies[1].IsHigh())
+}
+
+func TestDetectAnomalies_NotEnoughHistory(t *testing.T) {
+	totals := worklog.DailyTotals{"2021-10-04": time.Minute * 30}
+
+	require.Nil(t, worklog.DetectAnomalies(totals, getAnomalyTestHistory(), &worklog.AnomalyOpts{
+		Factor:  2,
+		MinDays: 6,
+	}))
+
+	require.Nil(t, worklog.DetectAnomalies(totals, getAnomalyTestHistory(), &worklog.AnomalyOpts{
+		Factor:  0,
+		MinDays: 5,
+	}))
+}
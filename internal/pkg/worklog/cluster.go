@@ -0,0 +1,204 @@
+package worklog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultClusterSimilarity is the minimum Jaccard similarity of two summaries
+// to put them into the same cluster.
+const DefaultClusterSimilarity float64 = 0.5
+
+// Cluster represents a group of entries with similar summaries.
+type Cluster struct {
+	Entries Entries
+	// Tokens lists the words shared by every summary in the cluster, in the
+	// order they appear in the first summary.
+	Tokens []string
+}
+
+// tokenize splits the summary into lower-cased words, dropping punctuation
+// and single character words.
+func tokenize(summary string) []string {
+	var tokens []string
+
+	words := strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if len([]rune(word)) > 1 {
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+// similarity returns the Jaccard similarity of the two token sets.
+func similarity(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := map[string]bool{}
+	for _, token := range a {
+		set[token] = true
+	}
+
+	union := len(set)
+	intersection := 0
+	seen := map[string]bool{}
+
+	for _, token := range b {
+		if seen[token] {
+			continue
+		}
+
+		seen[token] = true
+
+		if set[token] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+
+	return float64(intersection) / float64(union)
+}
+
+// intersect returns the tokens of a which are present in b, keeping the order
+// of a.
+func intersect(a []string, b []string) []string {
+	var tokens []string
+
+	for _, token := range a {
+		for _, other := range b {
+			if token == other {
+				tokens = append(tokens, token)
+				break
+			}
+		}
+	}
+
+	return tokens
+}
+
+// ClusterBySummary groups the entries by the similarity of their summary.
+// Entries without summary are skipped. An entry joins the first cluster which
+// first entry is at least as similar as the given threshold. The returned
+// clusters are sorted by their size in descending order.
+func ClusterBySummary(entries Entries, threshold float64) []Cluster {
+	var clusters []Cluster
+	var clusterTokens [][]string
+
+	for _, entry := range entries {
+		tokens := tokenize(entry.Summary)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		isClustered := false
+		for i := range clusters {
+			if similarity(clusterTokens[i], tokens) >= threshold {
+				clusters[i].Entries = append(clusters[i].Entries, entry)
+				clusters[i].Tokens = intersect(clusters[i].Tokens, tokens)
+				isClustered = true
+				break
+			}
+		}
+
+		if !isClustered {
+			clusters = append(clusters, Cluster{
+				Entries: Entries{entry},
+				Tokens:  tokens,
+			})
+			clusterTokens = append(clusterTokens, tokens)
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Entries) > len(clusters[j].Entries)
+	})
+
+	return clusters
+}
+
+// Pattern returns a case-insensitive regex matching every summary of the
+// cluster. If the shared tokens are not enough to match all summaries, the
+// pattern falls back to the alternation of the summaries.
+func (c *Cluster) Pattern() string {
+	if len(c.Tokens) > 0 {
+		var parts []string
+		for _, token := range c.Tokens {
+			parts = append(parts, `\b`+regexp.QuoteMeta(token)+`\b`)
+		}
+
+		pattern := "(?i)" + strings.Join(parts, ".*")
+		if c.matchesAll(regexp.MustCompile(pattern)) {
+			return pattern
+		}
+	}
+
+	var summaries []string
+	seen := map[string]bool{}
+
+	for _, entry := range c.Entries {
+		if !seen[entry.Summary] {
+			seen[entry.Summary] = true
+			summaries = append(summaries, regexp.QuoteMeta(entry.Summary))
+		}
+	}
+
+	return "(?i)^(" + strings.Join(summaries, "|") + ")$"
+}
+
+func (c *Cluster) matchesAll(pattern *regexp.Regexp) bool {
+	for _, entry := range c.Entries {
+		if !pattern.MatchString(entry.Summary) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SuggestMapping returns a mapping for the cluster. The client, project and
+// task of the mapping is taken from the most frequent task of the reference
+// entries matching the cluster's pattern. If no reference entry is matching,
+// only the summary pattern is set.
+func (c *Cluster) SuggestMapping(reference Entries) Mapping {
+	mapping := Mapping{
+		Summary: c.Pattern(),
+	}
+
+	pattern := regexp.MustCompile(mapping.Summary)
+	counts := map[string]int{}
+	candidates := map[string]Entry{}
+
+	for _, entry := range reference {
+		if !entry.Task.IsComplete() || !pattern.MatchString(entry.Summary) {
+			continue
+		}
+
+		counts[entry.Task.Name]++
+		candidates[entry.Task.Name] = entry
+	}
+
+	bestTask := ""
+	for task, count := range counts {
+		if count > counts[bestTask] || (count == counts[bestTask] && task < bestTask) {
+			bestTask = task
+		}
+	}
+
+	if candidate, ok := candidates[bestTask]; ok {
+		mapping.Client = candidate.Client.Name
+		mapping.Project = candidate.Project.Name
+		mapping.Task = candidate.Task.Name
+	}
+
+	return mapping
+}
@@ -0,0 +1,84 @@
+package worklog_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterBySummary(t *testing.T) {
+	var entries worklog.Entries
+
+	for _, summary := range []string{
+		"Daily standup meeting",
+		"Code review",
+		"daily standup",
+		"",
+		"Standup meeting (daily)",
+	} {
+		entry := getIncompleteTestEntry()
+		entry.Summary = summary
+		entries = append(entries, entry)
+	}
+
+	clusters := worklog.ClusterBySummary(entries, worklog.DefaultClusterSimilarity)
+
+	require.Len(t, clusters, 2)
+	require.Len(t, clusters[0].Entries, 3)
+	require.Equal(t, []string{"daily", "standup"}, clusters[0].Tokens)
+	require.Len(t, clusters[1].Entries, 1)
+}
+
+func TestCluster_Pattern(t *testing.T) {
+	first := getIncompleteTestEntry()
+	first.Summary = "Daily standup meeting"
+
+	second := getIncompleteTestEntry()
+	second.Summary = "Standup meeting (daily)"
+
+	cluster := worklog.Cluster{
+		Entries: worklog.Entries{first, second},
+		Tokens:  []string{"daily", "standup", "meeting"},
+	}
+
+	pattern := regexp.MustCompile(cluster.Pattern())
+	require.Equal(t, `(?i)^(Daily standup meeting|Standup meeting \(daily\))$`, pattern.String())
+	require.True(t, pattern.MatchString(first.Summary))
+	require.True(t, pattern.MatchString(second.Summary))
+}
+
+func TestCluster_SuggestMapping(t *testing.T) {
+	unmapped := getIncompleteTestEntry()
+	unmapped.Summary = "Daily standup"
+
+	reference := getCompleteTestEntry()
+	reference.Summary = "daily standup with the team"
+
+	cluster := worklog.Cluster{
+		Entries: worklog.Entries{unmapped},
+		Tokens:  []string{"daily", "standup"},
+	}
+
+	mapping := cluster.SuggestMapping(worklog.Entries{reference})
+	require.Equal(t, worklog.Mapping{
+		Summary: `(?i)\bdaily\b.*\bstandup\b`,
+		Client:  reference.Client.Name,
+		Project: reference.Project.Name,
+		Task:    reference.Task.Name,
+	}, mapping)
+}
+
+func TestCluster_SuggestMapping_NoReference(t *testing.T) {
+	unmapped := getIncompleteTestEntry()
+	unmapped.Summary = "Daily standup"
+
+	cluster := worklog.Cluster{
+		Entries: worklog.Entries{unmapped},
+		Tokens:  []string{"daily", "standup"},
+	}
+
+	mapping := cluster.SuggestMapping(worklog.Entries{})
+	require.Equal(t, worklog.Mapping{Summary: `(?i)\bdaily\b.*\bstandup\b`}, mapping)
+}
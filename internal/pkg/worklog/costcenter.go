@@ -0,0 +1,52 @@
+package worklog
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrNoCostCenterName returns when a cost center does not set its name.
+var ErrNoCostCenterName = errors.New("cost center name must be set")
+
+// CostCenter represents a rule assigning the entries of the projects matching
+// the Project regex to the cost center named Name, like "CC-1000".
+type CostCenter struct {
+	Name    string `mapstructure:"name" json:"name"`
+	Project string `mapstructure:"project" json:"project,omitempty"`
+
+	projectRegex *regexp.Regexp
+}
+
+// Compile validates the cost center and compiles its project regex. Compile
+// must be called before calling Matches.
+func (c *CostCenter) Compile() error {
+	if c.Name == "" {
+		return ErrNoCostCenterName
+	}
+
+	projectRegex, err := regexp.Compile(c.Project)
+	if err != nil {
+		return err
+	}
+
+	c.projectRegex = projectRegex
+	return nil
+}
+
+// Matches returns true if the entry's project belongs to the cost center.
+func (c *CostCenter) Matches(entry *Entry) bool {
+	return c.projectRegex != nil && c.projectRegex.MatchString(entry.Project.Name)
+}
+
+// CostCenterOf returns the name of the first cost center the entry's project
+// belongs to. If no cost center matches, an empty string returns. The cost
+// centers must be compiled before calling it.
+func CostCenterOf(entry *Entry, costCenters []CostCenter) string {
+	for i := range costCenters {
+		if costCenters[i].Matches(entry) {
+			return costCenters[i].Name
+		}
+	}
+
+	return ""
+}
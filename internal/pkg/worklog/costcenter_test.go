@@ -0,0 +1,37 @@
+package worklog_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostCenterOf(t *testing.T) {
+	costCenters := []worklog.CostCenter{
+		{Name: "CC-1000", Project: "^Product"},
+		{Name: "CC-2000", Project: "Maintenance|Support"},
+		{Name: "CC-9000", Project: "Product"},
+	}
+
+	for i := range costCenters {
+		require.Nil(t, costCenters[i].Compile())
+	}
+
+	entry := func(project string) *worklog.Entry {
+		return &worklog.Entry{Project: worklog.IDNameField{ID: project, Name: project}}
+	}
+
+	require.Equal(t, "CC-1000", worklog.CostCenterOf(entry("Product website"), costCenters))
+	require.Equal(t, "CC-2000", worklog.CostCenterOf(entry("Customer Support"), costCenters))
+	require.Equal(t, "CC-9000", worklog.CostCenterOf(entry("New Product"), costCenters))
+	require.Equal(t, "", worklog.CostCenterOf(entry("Internal"), costCenters))
+}
+
+func TestCostCenter_Compile(t *testing.T) {
+	costCenter := worklog.CostCenter{Project: "Product"}
+	require.ErrorIs(t, costCenter.Compile(), worklog.ErrNoCostCenterName)
+
+	costCenter = worklog.CostCenter{Name: "CC-1000", Project: "["}
+	require.Error(t, costCenter.Compile())
+}
@@ -0,0 +1,157 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DistributionNone keeps the start of the entries as fetched.
+	DistributionNone string = "none"
+	// DistributionStack places the entries of the day back to back, starting
+	// at the start of the working day.
+	DistributionStack string = "stack"
+	// DistributionSpread splits the working hours into equal slots and starts
+	// every entry of the day at the start of its slot.
+	DistributionSpread string = "spread"
+	// DistributionProportional splits the working hours into slots
+	// proportional to the duration of the entries and starts every entry of
+	// the day at the start of its slot.
+	DistributionProportional string = "proportional"
+)
+
+var (
+	// DistributionStrategies lists the supported distribution strategies.
+	DistributionStrategies = []string{DistributionNone, DistributionStack, DistributionSpread, DistributionProportional}
+
+	// ErrInvalidWorkingHours returns when the working day does not start
+	// before it ends, or it is not within a day.
+	ErrInvalidWorkingHours = errors.New("working day must start before it ends within a day")
+	// ErrUnknownDistributionStrategy returns when the distribution strategy is
+	// not supported.
+	ErrUnknownDistributionStrategy = errors.New("unknown distribution strategy")
+)
+
+// DistributionOpts represents the options of distributing the entries having
+// only a daily total across the day.
+type DistributionOpts struct {
+	Strategy string
+	// DayStart and DayEnd set the working hours as the time elapsed since
+	// midnight, like 9 hours for 09:00.
+	DayStart time.Duration
+	DayEnd   time.Duration
+}
+
+// ParseWorkingHour returns the time of day given in "15:04" format as the time
+// elapsed since midnight, like 9 hours and 30 minutes for "09:30".
+func ParseWorkingHour(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// Validate returns an error if the strategy is unknown or the working hours
+// are invalid.
+func (o *DistributionOpts) Validate() error {
+	isKnown := false
+	for _, strategy := range DistributionStrategies {
+		if o.Strategy == strategy {
+			isKnown = true
+			break
+		}
+	}
+
+	if !isKnown {
+		return fmt.Errorf("%w: %s", ErrUnknownDistributionStrategy, o.Strategy)
+	}
+
+	if o.DayStart < 0 || o.DayStart >= o.DayEnd || o.DayEnd > time.Hour*24 {
+		return ErrInvalidWorkingHours
+	}
+
+	return nil
+}
+
+// isDailyTotal returns true if the entry has no time of day, hence it starts
+// at midnight. Sources providing daily totals only, like spreadsheet exports,
+// set the start of the entries to the start of the day.
+func isDailyTotal(entry *Entry) bool {
+	hour, minute, second := entry.Start.Clock()
+	return hour == 0 && minute == 0 && second == 0 && entry.Start.Nanosecond() == 0
+}
+
+// distributeDay sets the start of the entries of a day as set by the options.
+// If the entries do not fit in the working hours, the entries are stacked.
+func distributeDay(entries []*Entry, opts *DistributionOpts) {
+	var total time.Duration
+	for _, entry := range entries {
+		total += entry.BillableDuration + entry.UnbillableDuration
+	}
+
+	strategy := opts.Strategy
+	workingHours := opts.DayEnd - opts.DayStart
+
+	if total > workingHours {
+		strategy = DistributionStack
+	}
+
+	offset := opts.DayStart
+	for _, entry := range entries {
+		duration := entry.BillableDuration + entry.UnbillableDuration
+
+		entry.Start = entry.Start.Add(offset)
+		entry.AddTransformation("start distributed by %s strategy", opts.Strategy)
+
+		switch strategy {
+		case DistributionSpread:
+			offset += workingHours / time.Duration(len(entries))
+		case DistributionProportional:
+			if total > 0 {
+				offset += time.Duration(float64(workingHours) * float64(duration) / float64(total))
+			}
+		default:
+			offset += duration
+		}
+	}
+}
+
+// DistributeEntries synthesizes the start time of the entries having only a
+// daily total, as some targets require the time of day. The entries of the
+// same day are distributed within the working hours in the order they are
+// given; the rest of the entries, including the corrections, are returned
+// unchanged.
+func DistributeEntries(entries Entries, opts *DistributionOpts) Entries {
+	distributedEntries := make(Entries, len(entries))
+	copy(distributedEntries, entries)
+
+	if opts.Strategy == "" || opts.Strategy == DistributionNone {
+		return distributedEntries
+	}
+
+	var days []string
+	dayEntries := map[string][]*Entry{}
+
+	for i := range distributedEntries {
+		entry := &distributedEntries[i]
+		if !isDailyTotal(entry) || entry.IsCorrection() {
+			continue
+		}
+
+		day := entry.Start.Format("2006-01-02 MST")
+		if _, ok := dayEntries[day]; !ok {
+			days = append(days, day)
+		}
+
+		dayEntries[day] = append(dayEntries[day], entry)
+	}
+
+	for _, day := range days {
+		distributeDay(dayEntries[day], opts)
+	}
+
+	return distributedEntries
+}
@@ -0,0 +1,126 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistributeEntries(t *testing.T) {
+	day := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+	timed := day.Add(time.Hour * 7)
+
+	tests := map[string]struct {
+		strategy string
+		starts   []time.Time
+	}{
+		"none": {
+			strategy: worklog.DistributionNone,
+			starts:   []time.Time{day, day, timed, day.AddDate(0, 0, 1)},
+		},
+		"stack": {
+			strategy: worklog.DistributionStack,
+			starts:   []time.Time{day.Add(time.Hour * 9), day.Add(time.Hour * 12), timed, day.AddDate(0, 0, 1).Add(time.Hour * 9)},
+		},
+		"spread": {
+			strategy: worklog.DistributionSpread,
+			starts:   []time.Time{day.Add(time.Hour * 9), day.Add(time.Hour * 13), timed, day.AddDate(0, 0, 1).Add(time.Hour * 9)},
+		},
+		"proportional": {
+			strategy: worklog.DistributionProportional,
+			starts:   []time.Time{day.Add(time.Hour * 9), day.Add(time.Hour * 15), timed, day.AddDate(0, 0, 1).Add(time.Hour * 9)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries := worklog.Entries{
+				{Summary: "design", Start: day, BillableDuration: time.Hour * 2, UnbillableDuration: time.Hour},
+				{Summary: "review", Start: day, BillableDuration: time.Hour},
+				{Summary: "standup", Start: timed, UnbillableDuration: time.Minute * 15},
+				{Summary: "coding", Start: day.AddDate(0, 0, 1), BillableDuration: time.Hour * 4},
+			}
+
+			opts := &worklog.DistributionOpts{
+				Strategy: test.strategy,
+				DayStart: time.Hour * 9,
+				DayEnd:   time.Hour * 17,
+			}
+			require.Nil(t, opts.Validate())
+
+			distributed := worklog.DistributeEntries(entries, opts)
+
+			var starts []time.Time
+			for _, entry := range distributed {
+				starts = append(starts, entry.Start)
+			}
+
+			require.Equal(t, test.starts, starts)
+			require.Equal(t, day, entries[0].Start)
+			require.Empty(t, distributed[2].Provenance.Transformations)
+		})
+	}
+}
+
+func TestDistributeEntries_Overflow(t *testing.T) {
+	day := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	distributed := worklog.DistributeEntries(worklog.Entries{
+		{Summary: "design", Start: day, BillableDuration: time.Hour * 6},
+		{Summary: "review", Start: day, BillableDuration: time.Hour * 3},
+	}, &worklog.DistributionOpts{
+		Strategy: worklog.DistributionSpread,
+		DayStart: time.Hour * 9,
+		DayEnd:   time.Hour * 17,
+	})
+
+	require.Equal(t, day.Add(time.Hour*15), distributed[1].Start)
+	require.Equal(t, []string{"start distributed by spread strategy"}, distributed[1].Provenance.Transformations)
+}
+
+func TestDistributeEntries_Correction(t *testing.T) {
+	day := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	distributed := worklog.DistributeEntries(worklog.Entries{
+		{Summary: "design", Start: day, BillableDuration: -time.Hour},
+		{Summary: "review", Start: day, BillableDuration: time.Hour * 3},
+	}, &worklog.DistributionOpts{
+		Strategy: worklog.DistributionStack,
+		DayStart: time.Hour * 9,
+		DayEnd:   time.Hour * 17,
+	})
+
+	require.Equal(t, day, distributed[0].Start)
+	require.Equal(t, day.Add(time.Hour*9), distributed[1].Start)
+}
+
+func TestParseWorkingHour(t *testing.T) {
+	dayStart, err := worklog.ParseWorkingHour("09:30")
+	require.Nil(t, err)
+	require.Equal(t, time.Hour*9+time.Minute*30, dayStart)
+
+	dayEnd, err := worklog.ParseWorkingHour("17:00")
+	require.Nil(t, err)
+
+	// The parsed working hours start the entries on their own day
+	day := time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC)
+	distributed := worklog.DistributeEntries(worklog.Entries{
+		{Summary: "design", Start: day, BillableDuration: time.Hour},
+	}, &worklog.DistributionOpts{
+		Strategy: worklog.DistributionStack,
+		DayStart: dayStart,
+		DayEnd:   dayEnd,
+	})
+
+	require.Equal(t, time.Date(2021, 10, 2, 9, 30, 0, 0, time.UTC), distributed[0].Start)
+
+	_, err = worklog.ParseWorkingHour("9am")
+	require.Error(t, err)
+}
+
+func TestDistributionOpts_Validate(t *testing.T) {
+	require.ErrorIs(t, (&worklog.DistributionOpts{Strategy: "random", DayEnd: time.Hour}).Validate(), worklog.ErrUnknownDistributionStrategy)
+	require.ErrorIs(t, (&worklog.DistributionOpts{Strategy: worklog.DistributionStack, DayStart: time.Hour, DayEnd: time.Hour}).Validate(), worklog.ErrInvalidWorkingHours)
+}
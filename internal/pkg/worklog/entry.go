@@ -6,8 +6,20 @@ import (
 	"regexp"
 	"strconv"
 	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
 )
 
+// AttributeClassification is the entry attribute of the cost classification,
+// like "CAPEX" or "OPEX".
+const AttributeClassification string = "classification"
+
+// AttributeWorker is the entry attribute of the user who did the work, like
+// the author set by the source. Depending on the author strategy, the entry is
+// uploaded in the name of the worker. Without the attribute, the entry is
+// uploaded in the name of the target user.
+const AttributeWorker string = "worker"
+
 // IDNameField stands for every field that has an ID and Name.
 type IDNameField struct {
 	ID   string `json:"id"`
@@ -56,19 +68,45 @@ func (e *Entries) GroupByTask() map[string]Entries {
 
 // Entry represents the worklog entry and contains all the necessary data.
 type Entry struct {
-	Client             IDNameField
-	Project            IDNameField
-	Task               IDNameField
-	Summary            string
-	Notes              string
-	Start              time.Time
-	BillableDuration   time.Duration
-	UnbillableDuration time.Duration
+	Client             IDNameField   `json:"client"`
+	Project            IDNameField   `json:"project"`
+	Task               IDNameField   `json:"task"`
+	Summary            string        `json:"summary"`
+	Notes              string        `json:"notes"`
+	Start              time.Time     `json:"start"`
+	BillableDuration   time.Duration `json:"billable_duration"`
+	UnbillableDuration time.Duration `json:"unbillable_duration"`
+	// Links lists the URLs pointing to the origin of the entry, like the
+	// source entry, the related calendar event or pull request.
+	Links []string `json:"links,omitempty"`
+	// Absence is set if the entry stands for an absence, like vacation or
+	// sick leave, instead of work.
+	Absence Absence `json:"absence,omitempty"`
+	// Attributes holds additional metadata of the entry, like the details of
+	// the related issue, keyed by the attribute name.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Provenance describes where the entry comes from and how it was
+	// transformed.
+	Provenance Provenance `json:"provenance"`
+	// TaskHints holds the texts and tags of the source entry which the task
+	// is extracted from by the extract stage of the pipeline.
+	TaskHints *TaskHints `json:"task_hints,omitempty"`
+	// Replaces is the ID of the worklog uploaded before, like the ID of the
+	// Tempo worklog, the entry replaces. If the entry has no duration, the
+	// worklog is deleted.
+	Replaces string `json:"replaces,omitempty"`
 }
 
-// Key returns a unique, per entry key used for grouping similar entries.
+// Key returns a unique, per entry key used for grouping similar entries. The
+// corrections have their own keys, so they are not merged with the entries
+// they correct.
 func (e *Entry) Key() string {
-	return fmt.Sprintf("%s:%s:%s:%s", e.Project.Name, e.Task.Name, e.Summary, e.Start.Format("2006-01-02"))
+	key := fmt.Sprintf("%s:%s:%s:%s", e.Project.Name, e.Task.Name, e.Summary, e.Start.Format("2006-01-02"))
+	if e.IsCorrection() {
+		key += ":correction:" + e.Replaces
+	}
+
+	return key
 }
 
 // IsComplete indicates if the entry has all the necessary fields filled.
@@ -79,11 +117,74 @@ func (e *Entry) IsComplete() bool {
 	hasTask := e.Task.IsComplete()
 
 	isMetadataFilled := hasProject && hasClient && hasTask && e.Summary != ""
-	isTimeFilled := !e.Start.IsZero() && (e.BillableDuration.Seconds() > 0 || e.UnbillableDuration.Seconds() > 0)
+	isTimeFilled := !e.Start.IsZero() && (e.BillableDuration.Seconds() > 0 || e.UnbillableDuration.Seconds() > 0 || e.IsCorrection())
 
 	return isMetadataFilled && isTimeFilled
 }
 
+// IsCorrection indicates if the entry corrects the worklogs uploaded before,
+// either by replacing a worklog or by having a negative duration, which is
+// subtracted from the time logged for the same task on the same day.
+func (e *Entry) IsCorrection() bool {
+	return e.Replaces != "" || e.BillableDuration+e.UnbillableDuration < 0
+}
+
+// IsDeletion indicates if the entry replaces a worklog without any duration,
+// hence the worklog should be deleted.
+func (e *Entry) IsDeletion() bool {
+	return e.Replaces != "" && e.BillableDuration == 0 && e.UnbillableDuration == 0
+}
+
+// IsAbsence indicates if the entry stands for an absence instead of work.
+func (e *Entry) IsAbsence() bool {
+	return e.Absence != ""
+}
+
+// SetAttribute sets the attribute of the entry. Since copies of an entry are
+// sharing the attributes, the attributes are copied before setting the value.
+func (e *Entry) SetAttribute(name string, value string) {
+	attributes := make(map[string]string, len(e.Attributes)+1)
+	for key, val := range e.Attributes {
+		attributes[key] = val
+	}
+
+	attributes[name] = value
+	e.Attributes = attributes
+}
+
+// AddLinks appends the given links to the entry, skipping empty and already
+// present links. Since copies of an entry are sharing the links, the links are
+// copied before appending the new ones.
+func (e *Entry) AddLinks(links ...string) {
+	allLinks := make([]string, len(e.Links), len(e.Links)+len(links))
+	copy(allLinks, e.Links)
+
+	for _, link := range links {
+		isPresent := false
+		for _, existing := range allLinks {
+			if existing == link {
+				isPresent = true
+				break
+			}
+		}
+
+		if link != "" && !isPresent {
+			allLinks = append(allLinks, link)
+		}
+	}
+
+	if len(allLinks) != len(e.Links) {
+		e.Links = allLinks
+	}
+}
+
+// AddMentionedLinks adds the links mentioned by the summary or the notes of
+// the entry to its links, regardless of the source the entry comes from.
+func (e *Entry) AddMentionedLinks() {
+	e.AddLinks(utils.ExtractURLs(e.Summary)...)
+	e.AddLinks(utils.ExtractURLs(e.Notes)...)
+}
+
 // SplitDuration splits the billable and unbillable duration to N parts.
 func (e *Entry) SplitDuration(parts int) (splitBillableDuration time.Duration, splitUnbillableDuration time.Duration) {
 	splitBillableDuration = time.Duration(math.Round(float64(e.BillableDuration.Nanoseconds()) / float64(parts)))
@@ -91,6 +192,24 @@ func (e *Entry) SplitDuration(parts int) (splitBillableDuration time.Duration, s
 	return splitBillableDuration, splitUnbillableDuration
 }
 
+// ExtractTask sets the task of the entry to the first match of the regex in
+// the text, like the description of the entry, and sets the summary. If the
+// regex does not match, the entry is left unchanged and false returns.
+func (e *Entry) ExtractTask(summary string, text string, regex *regexp.Regexp) bool {
+	task := regex.FindString(text)
+	if task == "" {
+		return false
+	}
+
+	e.Task = IDNameField{
+		ID:   task,
+		Name: task,
+	}
+	e.Summary = summary
+
+	return true
+}
+
 // SplitByTagsAsTasks splits the entry into pieces treating tags as tasks.
 // Not matching tags won't be treated as a new entry should be created,
 // therefore that tag will be skipped and the returned entries will lack that.
@@ -114,16 +233,17 @@ func (e *Entry) SplitByTagsAsTasks(summary string, regex *regexp.Regexp, tags []
 	for _, task := range tasks {
 		splitBillable, splitUnbillable := e.SplitDuration(totalTasks)
 
-		entries = append(entries, Entry{
-			Client:             e.Client,
-			Project:            e.Project,
-			Task:               task,
-			Summary:            summary,
-			Notes:              e.Notes,
-			Start:              e.Start,
-			BillableDuration:   splitBillable,
-			UnbillableDuration: splitUnbillable,
-		})
+		entry := *e
+		entry.Task = task
+		entry.Summary = summary
+		entry.BillableDuration = splitBillable
+		entry.UnbillableDuration = splitUnbillable
+
+		if totalTasks > 1 {
+			entry.AddTransformation("split by tags")
+		}
+
+		entries = append(entries, entry)
 	}
 
 	return entries
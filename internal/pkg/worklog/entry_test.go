@@ -90,6 +90,16 @@ func TestEntryKey(t *testing.T) {
 	assert.Equal(t, "Internal projects:TASK-0123:Write worklog transfer CLI tool:2021-10-02", entry.Key())
 }
 
+func TestEntryKey_Correction(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.BillableDuration = -time.Hour
+	assert.Equal(t, "Internal projects:TASK-0123:Write worklog transfer CLI tool:2021-10-02:correction:", entry.Key())
+
+	entry = getCompleteTestEntry()
+	entry.Replaces = "123"
+	assert.Equal(t, "Internal projects:TASK-0123:Write worklog transfer CLI tool:2021-10-02:correction:123", entry.Key())
+}
+
 func TestEntryIsComplete(t *testing.T) {
 	entry := getCompleteTestEntry()
 	assert.True(t, entry.IsComplete())
@@ -124,6 +134,36 @@ func TestEntryIsCompleteIncomplete(t *testing.T) {
 	assert.False(t, entry.IsComplete())
 }
 
+func TestEntryIsComplete_Correction(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.BillableDuration = -time.Hour
+	assert.True(t, entry.IsComplete())
+
+	entry = getCompleteTestEntry()
+	entry.BillableDuration = 0
+	entry.Replaces = "123"
+	assert.True(t, entry.IsComplete())
+}
+
+func TestEntry_IsCorrection(t *testing.T) {
+	entry := getCompleteTestEntry()
+	assert.False(t, entry.IsCorrection())
+	assert.False(t, entry.IsDeletion())
+
+	entry.BillableDuration = time.Hour
+	entry.UnbillableDuration = -time.Hour * 2
+	assert.True(t, entry.IsCorrection())
+	assert.False(t, entry.IsDeletion())
+
+	entry = getCompleteTestEntry()
+	entry.Replaces = "123"
+	assert.True(t, entry.IsCorrection())
+	assert.False(t, entry.IsDeletion())
+
+	entry.BillableDuration = 0
+	assert.True(t, entry.IsDeletion())
+}
+
 func TestEntry_SplitDuration(t *testing.T) {
 	var splitBillable time.Duration
 	var splitUnbillable time.Duration
@@ -158,6 +198,7 @@ func TestEntry_SplitByTag(t *testing.T) {
 			Start:              entry.Start,
 			BillableDuration:   entry.BillableDuration / 2,
 			UnbillableDuration: entry.UnbillableDuration / 2,
+			Provenance:         worklog.Provenance{Transformations: []string{"split by tags"}},
 		},
 		{
 			Client:  entry.Client,
@@ -171,6 +212,7 @@ func TestEntry_SplitByTag(t *testing.T) {
 			Start:              entry.Start,
 			BillableDuration:   entry.BillableDuration / 2,
 			UnbillableDuration: entry.UnbillableDuration / 2,
+			Provenance:         worklog.Provenance{Transformations: []string{"split by tags"}},
 		},
 	}
 
@@ -191,3 +233,68 @@ func TestEntry_SplitByTag(t *testing.T) {
 
 	assert.ElementsMatch(t, expectedEntries, entries)
 }
+
+func TestEntry_ExtractTask(t *testing.T) {
+	regex := regexp.MustCompile(`TASK-\d+`)
+
+	entry := getCompleteTestEntry()
+	expectedEntry := entry
+	expectedEntry.Task = worklog.IDNameField{
+		ID:   "TASK-123",
+		Name: "TASK-123",
+	}
+	expectedEntry.Summary = "Fixing TASK-123 and TASK-456"
+
+	assert.True(t, entry.ExtractTask("Fixing TASK-123 and TASK-456", "Fixing TASK-123 and TASK-456", regex))
+	assert.Equal(t, expectedEntry, entry)
+
+	entry = getCompleteTestEntry()
+	unchangedEntry := entry
+
+	assert.False(t, entry.ExtractTask("Fixing the build", "Fixing the build", regex))
+	assert.Equal(t, unchangedEntry, entry)
+}
+
+func TestEntry_AddLinks(t *testing.T) {
+	entry := getCompleteTestEntry()
+
+	entry.AddLinks("https://example.com/1", "", "https://example.com/2")
+	entry.AddLinks("https://example.com/1")
+
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, entry.Links)
+}
+
+func TestEntry_AddLinks_Copy(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.Links = make([]string, 0, 4)
+	entry.AddLinks("https://example.com/1")
+
+	entryCopy := entry
+	entryCopy.AddLinks("https://example.com/2")
+	entry.AddLinks("https://example.com/3")
+
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/3"}, entry.Links)
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, entryCopy.Links)
+}
+
+func TestEntry_AddMentionedLinks(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.Summary = "Review https://github.com/gabor-boros/minutes/pull/1"
+	entry.Notes = "See https://example.com/notes and https://github.com/gabor-boros/minutes/pull/1."
+
+	entry.AddMentionedLinks()
+
+	assert.Equal(t, []string{"https://github.com/gabor-boros/minutes/pull/1", "https://example.com/notes"}, entry.Links)
+}
+
+func TestEntry_SetAttribute(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.SetAttribute("jsm.status", "Open")
+
+	entryCopy := entry
+	entryCopy.SetAttribute("jsm.status", "Resolved")
+	entryCopy.SetAttribute("jsm.request_type", "Get IT help")
+
+	assert.Equal(t, map[string]string{"jsm.status": "Open"}, entry.Attributes)
+	assert.Equal(t, map[string]string{"jsm.status": "Resolved", "jsm.request_type": "Get IT help"}, entryCopy.Attributes)
+}
@@ -0,0 +1,90 @@
+package worklog
+
+import (
+	"regexp"
+
+	"github.com/gabor-boros/minutes/internal/pkg/utils"
+)
+
+const (
+	// TaskExtractionTags extracts the tasks from the tags of the entries,
+	// splitting the entries by their matching tags.
+	TaskExtractionTags string = "tags"
+	// TaskExtractionDescription extracts the task from the description of the
+	// entries, using the first match.
+	TaskExtractionDescription string = "description"
+)
+
+// TaskExtractionModes lists the supported task extraction modes.
+var TaskExtractionModes = []string{TaskExtractionTags, TaskExtractionDescription}
+
+// TaskHints holds the texts and tags of the source entry which the task of the
+// entry is extracted from by the task pattern. The sources are setting the
+// hints instead of extracting the tasks, so the tasks can be extracted again
+// from the cached entries.
+type TaskHints struct {
+	// Summary is set as the summary of the entry if its task is extracted, like
+	// the description of the source entry. If not set, the summary is kept.
+	Summary string `json:"summary,omitempty"`
+	// Description is the description of the source entry, used instead of the
+	// texts and tags if the tasks are extracted from the descriptions.
+	Description string `json:"description,omitempty"`
+	// Texts are searched for the task in order, like the subject of a commit
+	// then its branch name, and the first match is used.
+	Texts []string `json:"texts,omitempty"`
+	// Tags are the tags of the source entry. If any tags are matching the task
+	// pattern, the entry is split by the matching tags, one entry per task.
+	Tags []IDNameField `json:"tags,omitempty"`
+}
+
+// extractTasks returns the entry with the task extracted by the regex from the
+// hints, as set by the mode. If the entry is split by its tags, the split
+// entries return. The task is extracted from the description only if the
+// source entry has a description.
+func (h *TaskHints) extractTasks(entry Entry, regex *regexp.Regexp, mode string) Entries {
+	summary := entry.Summary
+	if h.Summary != "" {
+		summary = h.Summary
+	}
+
+	if mode == TaskExtractionDescription && h.Description != "" {
+		entry.ExtractTask(summary, h.Description, regex)
+		return Entries{entry}
+	}
+
+	for _, text := range h.Texts {
+		if entry.ExtractTask(summary, text, regex) {
+			break
+		}
+	}
+
+	if len(h.Tags) > 0 {
+		if splitEntries := entry.SplitByTagsAsTasks(summary, regex, h.Tags); len(splitEntries) > 0 {
+			return splitEntries
+		}
+	}
+
+	return Entries{entry}
+}
+
+// ExtractTasks extracts the task of every entry from its task hints by the
+// regex, as set by the mode, and returns the entries. The entries having
+// multiple tags matching the regex are split by the tags. Since the hints are
+// not needed after the extraction, they are cleared.
+func ExtractTasks(entries Entries, regex *regexp.Regexp, mode string) Entries {
+	extractedEntries := make(Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		hints := entry.TaskHints
+		entry.TaskHints = nil
+
+		if hints == nil || !utils.IsRegexSet(regex) {
+			extractedEntries = append(extractedEntries, entry)
+			continue
+		}
+
+		extractedEntries = append(extractedEntries, hints.extractTasks(entry, regex, mode)...)
+	}
+
+	return extractedEntries
+}
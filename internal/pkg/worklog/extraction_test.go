@@ -0,0 +1,114 @@
+package worklog_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTasks_Texts(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{Texts: []string{"Fix the build", "feature/TASK-123", "TASK-456"}}
+
+	entries := worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+
+	// The first text matching the regex is used
+	require.Equal(t, worklog.IDNameField{ID: "TASK-123", Name: "TASK-123"}, entries[0].Task)
+	require.Equal(t, entry.Summary, entries[0].Summary)
+	require.Nil(t, entries[0].TaskHints)
+}
+
+func TestExtractTasks_Tags(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{
+		Summary: "Pairing",
+		Texts:   []string{"TASK-1"},
+		Tags: []worklog.IDNameField{
+			{ID: "TASK-123", Name: "TASK-123"},
+			{ID: "coffee", Name: "coffee"},
+			{ID: "TASK-456", Name: "TASK-456"},
+		},
+	}
+
+	entries := worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(`^TASK-\d+$`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 2)
+
+	// The matching tags take precedence over the texts
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+	require.Equal(t, "TASK-456", entries[1].Task.Name)
+
+	for _, splitEntry := range entries {
+		require.Equal(t, "Pairing", splitEntry.Summary)
+		require.Equal(t, entry.BillableDuration/2, splitEntry.BillableDuration)
+		require.Nil(t, splitEntry.TaskHints)
+	}
+}
+
+func TestExtractTasks_NoMatchingTags(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{
+		Texts: []string{"TASK-1 Fix the build"},
+		Tags:  []worklog.IDNameField{{ID: "coffee", Name: "coffee"}},
+	}
+
+	// The entry is kept with the task of the texts, if any
+	entries := worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.Equal(t, "TASK-1", entries[0].Task.Name)
+}
+
+func TestExtractTasks_Description(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{
+		Summary:     "Fixing TASK-789",
+		Description: "Fixing TASK-789",
+		Tags:        []worklog.IDNameField{{ID: "TASK-123", Name: "TASK-123"}},
+	}
+
+	entries := worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionDescription)
+	require.Len(t, entries, 1)
+	require.Equal(t, "TASK-789", entries[0].Task.Name)
+	require.Equal(t, "Fixing TASK-789", entries[0].Summary)
+
+	// The entries without description are extracted as in the tags mode
+	entry.TaskHints = &worklog.TaskHints{Tags: []worklog.IDNameField{{ID: "TASK-123", Name: "TASK-123"}}}
+
+	entries = worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionDescription)
+	require.Len(t, entries, 1)
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+}
+
+func TestExtractTasks_RegexNotSet(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{Texts: []string{"TASK-123"}}
+
+	entries := worklog.ExtractTasks(worklog.Entries{entry}, regexp.MustCompile(""), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.False(t, entries[0].Task.IsComplete())
+	require.Nil(t, entries[0].TaskHints)
+}
+
+func TestExtractTasks_Cached(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.TaskHints = &worklog.TaskHints{Tags: []worklog.IDNameField{{ID: "TASK-123", Name: "TASK-123"}}}
+
+	data, err := json.Marshal(worklog.Entries{entry})
+	require.Nil(t, err)
+
+	// The tasks can be extracted again from the cached entries, like by a
+	// different regex
+	var cachedEntries worklog.Entries
+	require.Nil(t, json.Unmarshal(data, &cachedEntries))
+
+	entries := worklog.ExtractTasks(cachedEntries, regexp.MustCompile(`TASK-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.Equal(t, "TASK-123", entries[0].Task.Name)
+
+	entries = worklog.ExtractTasks(cachedEntries, regexp.MustCompile(`OTHER-\d+`), worklog.TaskExtractionTags)
+	require.Len(t, entries, 1)
+	require.False(t, entries[0].Task.IsComplete())
+}
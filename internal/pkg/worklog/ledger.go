@@ -0,0 +1,105 @@
+package worklog
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// LedgerRecord represents the durations uploaded for an entry key.
+type LedgerRecord struct {
+	BillableDuration   time.Duration `json:"billable_duration"`
+	UnbillableDuration time.Duration `json:"unbillable_duration"`
+}
+
+// Ledger keeps track of the durations already uploaded per entry key. It is
+// used by incremental syncs to upload only the duration that was not uploaded
+// yet. Ledger is safe for concurrent use.
+type Ledger struct {
+	mu      sync.Mutex
+	records map[string]LedgerRecord
+}
+
+// Pending returns the entries with their not yet uploaded durations. Entries
+// that are fully uploaded are dropped. Since uploaded durations cannot be
+// decreased by uploading new entries, decreased durations are treated as zero.
+// The corrections are applied only once, hence they are pending as is until
+// they are recorded.
+func (l *Ledger) Pending(entries Entries) Entries {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var pending Entries
+
+	for _, entry := range entries {
+		record, isRecorded := l.records[entry.Key()]
+
+		if entry.IsCorrection() {
+			if !isRecorded {
+				pending = append(pending, entry)
+			}
+
+			continue
+		}
+
+		entry.BillableDuration -= record.BillableDuration
+		if entry.BillableDuration < 0 {
+			entry.BillableDuration = 0
+		}
+
+		entry.UnbillableDuration -= record.UnbillableDuration
+		if entry.UnbillableDuration < 0 {
+			entry.UnbillableDuration = 0
+		}
+
+		if entry.BillableDuration > 0 || entry.UnbillableDuration > 0 {
+			pending = append(pending, entry)
+		}
+	}
+
+	return pending
+}
+
+// Record adds the durations of the uploaded entry to the ledger.
+func (l *Ledger) Record(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := entry.Key()
+	record := l.records[key]
+
+	record.BillableDuration += entry.BillableDuration
+	record.UnbillableDuration += entry.UnbillableDuration
+
+	l.records[key] = record
+}
+
+// MarshalJSON returns the ledger records keyed by the entry keys.
+func (l *Ledger) MarshalJSON() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return json.Marshal(l.records)
+}
+
+// UnmarshalJSON replaces the ledger records with the decoded ones.
+func (l *Ledger) UnmarshalJSON(data []byte) error {
+	records := map[string]LedgerRecord{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.records = records
+
+	return nil
+}
+
+// NewLedger returns an empty Ledger.
+func NewLedger() *Ledger {
+	return &Ledger{
+		records: map[string]LedgerRecord{},
+	}
+}
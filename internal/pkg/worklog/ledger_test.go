@@ -0,0 +1,77 @@
+package worklog_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger_Pending(t *testing.T) {
+	ledger := worklog.NewLedger()
+
+	entry := getCompleteTestEntry()
+	require.Equal(t, worklog.Entries{entry}, ledger.Pending(worklog.Entries{entry}))
+
+	ledger.Record(entry)
+	require.Nil(t, ledger.Pending(worklog.Entries{entry}))
+
+	// The entry was extended at the source
+	extendedEntry := entry
+	extendedEntry.BillableDuration += time.Hour
+	extendedEntry.UnbillableDuration = time.Minute
+
+	expectedEntry := entry
+	expectedEntry.BillableDuration = time.Hour
+	expectedEntry.UnbillableDuration = time.Minute
+
+	require.Equal(t, worklog.Entries{expectedEntry}, ledger.Pending(worklog.Entries{extendedEntry}))
+}
+
+func TestLedger_Pending_Decreased(t *testing.T) {
+	ledger := worklog.NewLedger()
+
+	entry := getCompleteTestEntry()
+	ledger.Record(entry)
+
+	entry.BillableDuration -= time.Minute
+	require.Nil(t, ledger.Pending(worklog.Entries{entry}))
+}
+
+func TestLedger_Pending_Correction(t *testing.T) {
+	ledger := worklog.NewLedger()
+
+	entry := getCompleteTestEntry()
+	ledger.Record(entry)
+
+	correction := getCompleteTestEntry()
+	correction.BillableDuration = -time.Hour
+	require.Equal(t, worklog.Entries{correction}, ledger.Pending(worklog.Entries{entry, correction}))
+
+	ledger.Record(correction)
+	require.Nil(t, ledger.Pending(worklog.Entries{entry, correction}))
+
+	deletion := getCompleteTestEntry()
+	deletion.BillableDuration = 0
+	deletion.Replaces = "123"
+	require.Equal(t, worklog.Entries{deletion}, ledger.Pending(worklog.Entries{deletion}))
+
+	ledger.Record(deletion)
+	require.Nil(t, ledger.Pending(worklog.Entries{deletion}))
+}
+
+func TestLedger_JSON(t *testing.T) {
+	ledger := worklog.NewLedger()
+
+	entry := getCompleteTestEntry()
+	ledger.Record(entry)
+
+	data, err := json.Marshal(ledger)
+	require.Nil(t, err)
+
+	restoredLedger := worklog.NewLedger()
+	require.Nil(t, json.Unmarshal(data, restoredLedger))
+	require.Nil(t, restoredLedger.Pending(worklog.Entries{entry}))
+}
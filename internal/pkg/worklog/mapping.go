@@ -0,0 +1,96 @@
+package worklog
+
+import (
+	"regexp"
+)
+
+// Mapping represents a rule that fills the client, project and task of those
+// entries which summary is matching the Summary regex. Mappings are used to
+// complete entries that are not assigned to any task at the source, like daily
+// meetings or code reviews. If Absence is set, the mapping is applied on the
+// absence entries of the given kind only, and the Summary regex is optional.
+type Mapping struct {
+	Summary string  `mapstructure:"summary" json:"summary,omitempty"`
+	Absence Absence `mapstructure:"absence" json:"absence,omitempty"`
+	Client  string  `mapstructure:"client" json:"client,omitempty"`
+	Project string  `mapstructure:"project" json:"project,omitempty"`
+	Task    string  `mapstructure:"task" json:"task,omitempty"`
+
+	summaryRegex *regexp.Regexp
+}
+
+// Compile compiles the summary regex of the mapping. Compile must be called
+// before calling Apply.
+func (m *Mapping) Compile() error {
+	summaryRegex, err := regexp.Compile(m.Summary)
+	if err != nil {
+		return err
+	}
+
+	m.summaryRegex = summaryRegex
+	return nil
+}
+
+// IsEmpty returns true if the mapping fills neither the client, the project
+// nor the task. Empty mappings are matching the entries without completing
+// them, hence they would shadow the mappings after them.
+func (m *Mapping) IsEmpty() bool {
+	return m.Client == "" && m.Project == "" && m.Task == ""
+}
+
+// matches returns true if the entry is matching the absence kind and the
+// summary regex of the mapping.
+func (m *Mapping) matches(entry *Entry) bool {
+	if m.summaryRegex == nil {
+		return false
+	}
+
+	if m.Absence != "" {
+		return entry.Absence == m.Absence && m.summaryRegex.MatchString(entry.Summary)
+	}
+
+	return m.Summary != "" && m.summaryRegex.MatchString(entry.Summary)
+}
+
+// Apply fills the missing client, project and task fields of the entry if the
+// summary is matching the mapping. Fields that are already complete are not
+// overridden. It returns true if the mapping was applied.
+func (m *Mapping) Apply(entry *Entry) bool {
+	if !m.matches(entry) {
+		return false
+	}
+
+	if !entry.Client.IsComplete() && m.Client != "" {
+		entry.Client = IDNameField{ID: m.Client, Name: m.Client}
+	}
+
+	if !entry.Project.IsComplete() && m.Project != "" {
+		entry.Project = IDNameField{ID: m.Project, Name: m.Project}
+	}
+
+	if !entry.Task.IsComplete() && m.Task != "" {
+		entry.Task = IDNameField{ID: m.Task, Name: m.Task}
+	}
+
+	entry.AddTransformation("mapped by %q", m.Summary)
+
+	return true
+}
+
+// ApplyMappings applies the first matching mapping on every entry and returns
+// the mapped entries. The mappings must be compiled before calling it.
+func ApplyMappings(entries Entries, mappings []Mapping) Entries {
+	mappedEntries := make(Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		for i := range mappings {
+			if mappings[i].Apply(&entry) {
+				break
+			}
+		}
+
+		mappedEntries = append(mappedEntries, entry)
+	}
+
+	return mappedEntries
+}
@@ -0,0 +1,92 @@
+package worklog_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapping_Apply(t *testing.T) {
+	entry := getIncompleteTestEntry()
+	entry.Summary = "Daily standup"
+
+	mapping := worklog.Mapping{
+		Summary: "(?i)standup",
+		Project: "Other project",
+		Task:    "TASK-0001",
+	}
+
+	require.Nil(t, mapping.Compile())
+	require.True(t, mapping.Apply(&entry))
+	require.Equal(t, worklog.IDNameField{ID: "TASK-0001", Name: "TASK-0001"}, entry.Task)
+	// The project was already complete, hence it must not be overridden
+	require.Equal(t, "Internal projects", entry.Project.Name)
+}
+
+func TestMapping_Apply_NotMatching(t *testing.T) {
+	entry := getIncompleteTestEntry()
+
+	mapping := worklog.Mapping{
+		Summary: "standup",
+		Task:    "TASK-0001",
+	}
+
+	require.Nil(t, mapping.Compile())
+	require.False(t, mapping.Apply(&entry))
+	require.False(t, entry.Task.IsComplete())
+}
+
+func TestMapping_IsEmpty(t *testing.T) {
+	require.True(t, (&worklog.Mapping{Summary: "standup"}).IsEmpty())
+	require.False(t, (&worklog.Mapping{Summary: "standup", Task: "TASK-0001"}).IsEmpty())
+}
+
+func TestMapping_Compile_Invalid(t *testing.T) {
+	mapping := worklog.Mapping{Summary: "[a-"}
+	require.NotNil(t, mapping.Compile())
+}
+
+func TestApplyMappings(t *testing.T) {
+	standup := getIncompleteTestEntry()
+	standup.Summary = "Standup"
+
+	review := getIncompleteTestEntry()
+	review.Summary = "Code review"
+
+	mappings := []worklog.Mapping{
+		{Summary: "(?i)standup", Task: "TASK-0001"},
+		{Summary: "(?i)standup|review", Task: "TASK-0002"},
+	}
+
+	for i := range mappings {
+		require.Nil(t, mappings[i].Compile())
+	}
+
+	entries := worklog.ApplyMappings(worklog.Entries{standup, review}, mappings)
+	require.Equal(t, "TASK-0001", entries[0].Task.Name)
+	require.Equal(t, "TASK-0002", entries[1].Task.Name)
+}
+
+func TestMapping_Apply_Absence(t *testing.T) {
+	vacation := getIncompleteTestEntry()
+	vacation.Summary = "Annual leave"
+	vacation.Absence = worklog.AbsenceVacation
+
+	sick := getIncompleteTestEntry()
+	sick.Summary = "Sick leave"
+	sick.Absence = worklog.AbsenceSick
+
+	work := getIncompleteTestEntry()
+
+	mapping := worklog.Mapping{
+		Absence: worklog.AbsenceVacation,
+		Task:    "ABS-1",
+	}
+
+	require.Nil(t, mapping.Compile())
+	require.True(t, mapping.Apply(&vacation))
+	require.Equal(t, "ABS-1", vacation.Task.Name)
+	require.False(t, mapping.Apply(&sick))
+	require.False(t, mapping.Apply(&work))
+}
@@ -0,0 +1,271 @@
+package worklog
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultOvertimeDailyDuration is the expected time spent on a working
+	// day, if not configured otherwise.
+	DefaultOvertimeDailyDuration time.Duration = time.Hour * 8
+
+	overtimeDateFormat string = "2006-01-02"
+	overtimeTimeFormat string = "15:04:05"
+	// overtimeEndOfDay is greater than any time of the day in
+	// overtimeTimeFormat.
+	overtimeEndOfDay string = "24:00:00"
+)
+
+// DefaultWorkingDays lists the working days of the week, if not configured
+// otherwise.
+var DefaultWorkingDays = []time.Weekday{
+	time.Monday,
+	time.Tuesday,
+	time.Wednesday,
+	time.Thursday,
+	time.Friday,
+}
+
+// OvertimeDay represents the expected and actual time spent on a day.
+type OvertimeDay struct {
+	Expected time.Duration `json:"expected"`
+	Actual   time.Duration `json:"actual"`
+	// Spent holds the time spent by the start time of the entries in
+	// "15:04:05" format, so updating a part of the day replaces the time spent
+	// in that part only.
+	Spent map[string]time.Duration `json:"spent,omitempty"`
+}
+
+// Balance returns the overtime of the day. Undertime is returned as negative
+// duration.
+func (d OvertimeDay) Balance() time.Duration {
+	return d.Actual - d.Expected
+}
+
+// PartTimePeriod represents the part of the full-time hours expected from a
+// date, until the next period starts.
+type PartTimePeriod struct {
+	// From is the first date of the period in YYYY-MM-DD format.
+	From string `mapstructure:"from"`
+	// Percentage is the part of the full-time hours expected, like 50 for
+	// working half-time.
+	Percentage float64 `mapstructure:"percentage"`
+}
+
+// OvertimeOpts represents the options used to calculate the expected time
+// spent per day.
+type OvertimeOpts struct {
+	// DailyDuration is the expected time spent on a working day.
+	DailyDuration time.Duration
+	// WorkingDays lists the days of the week when time spent is expected.
+	WorkingDays []time.Weekday
+	// WeekdayDurations sets the expected time spent per day of the week, like
+	// less on Fridays. The days set are expected regardless of WorkingDays,
+	// and zero duration marks a day off.
+	WeekdayDurations map[time.Weekday]time.Duration
+	// Holidays lists the dates in YYYY-MM-DD format when no time spent is
+	// expected.
+	Holidays []string
+	// EmploymentStart and EmploymentEnd are the first and last dates of the
+	// employment in YYYY-MM-DD format. No time spent is expected outside the
+	// employment, so the targets of the months joining or leaving are
+	// prorated. If empty, the employment is not bounded.
+	EmploymentStart string
+	EmploymentEnd   string
+	// PartTime lists the part-time percentages applied from their dates. The
+	// days before the first period are full-time.
+	PartTime []PartTimePeriod
+}
+
+// Expected returns the expected time spent on the day of the date.
+func (o *OvertimeOpts) Expected(date time.Time) time.Duration {
+	day := date.Format(overtimeDateFormat)
+	if (o.EmploymentStart != "" && day < o.EmploymentStart) || (o.EmploymentEnd != "" && day > o.EmploymentEnd) {
+		return 0
+	}
+
+	for _, holiday := range o.Holidays {
+		if holiday == day {
+			return 0
+		}
+	}
+
+	return time.Duration(math.Round(float64(o.WeekdayDuration(date.Weekday())) * o.PartTimeRatio(date)))
+}
+
+// PartTimeRatio returns the part of the full-time hours expected on the date,
+// set by the latest part-time period started by then.
+func (o *OvertimeOpts) PartTimeRatio(date time.Time) float64 {
+	day := date.Format(overtimeDateFormat)
+	ratio := 1.0
+
+	var latest string
+	for _, period := range o.PartTime {
+		if period.From <= day && period.From >= latest {
+			latest = period.From
+			ratio = period.Percentage / 100
+		}
+	}
+
+	return ratio
+}
+
+// Target returns the expected time spent on the days between start and end,
+// like the target hours of a month.
+func (o *OvertimeOpts) Target(start time.Time, end time.Time) time.Duration {
+	var target time.Duration
+
+	year, month, day := start.Local().Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+
+	for ; date.Before(end); date = date.AddDate(0, 0, 1) {
+		target += o.Expected(date)
+	}
+
+	return target
+}
+
+// WeekdayDuration returns the expected time spent on the day of the week,
+// without considering the holidays.
+func (o *OvertimeOpts) WeekdayDuration(weekday time.Weekday) time.Duration {
+	if duration, ok := o.WeekdayDurations[weekday]; ok {
+		return duration
+	}
+
+	for _, workingDay := range o.WorkingDays {
+		if workingDay == weekday {
+			return o.DailyDuration
+		}
+	}
+
+	return 0
+}
+
+// OvertimeBalance keeps track of the expected and actual time spent per day,
+// keyed by the date in YYYY-MM-DD format. Since every day is stored
+// separately, updating the balance with the same period multiple times does
+// not count the time spent twice.
+type OvertimeBalance map[string]OvertimeDay
+
+// Update replaces the time spent between start and end with the time spent of
+// the entries, which are expected to start in the period. If the period starts
+// or ends in the middle of a day, the time spent on the rest of the day is
+// kept. Absence entries are counted as time spent, as those are covering the
+// expected time.
+func (b OvertimeBalance) Update(entries Entries, start time.Time, end time.Time, opts *OvertimeOpts) {
+	spent := map[string]map[string]time.Duration{}
+	for _, entry := range entries {
+		day := entry.Start.Local().Format(overtimeDateFormat)
+		if spent[day] == nil {
+			spent[day] = map[string]time.Duration{}
+		}
+
+		spent[day][entry.Start.Local().Format(overtimeTimeFormat)] += entry.BillableDuration + entry.UnbillableDuration
+	}
+
+	startDay := start.Local().Format(overtimeDateFormat)
+	endDay := end.Local().Format(overtimeDateFormat)
+
+	year, month, day := start.Local().Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+
+	for ; date.Before(end); date = date.AddDate(0, 0, 1) {
+		key := date.Format(overtimeDateFormat)
+
+		// The part of the day replaced by the entries
+		from, to := "", overtimeEndOfDay
+		if key == startDay {
+			from = start.Local().Format(overtimeTimeFormat)
+		}
+		if key == endDay {
+			to = end.Local().Format(overtimeTimeFormat)
+		}
+
+		updated := OvertimeDay{
+			Expected: opts.Expected(date),
+			Spent:    map[string]time.Duration{},
+		}
+
+		for startTime, duration := range b[key].Spent {
+			if startTime < from || startTime >= to {
+				updated.Spent[startTime] += duration
+			}
+		}
+
+		for startTime, duration := range spent[key] {
+			updated.Spent[startTime] += duration
+		}
+
+		for _, duration := range updated.Spent {
+			updated.Actual += duration
+		}
+
+		if len(updated.Spent) == 0 {
+			updated.Spent = nil
+		}
+
+		b[key] = updated
+	}
+}
+
+// Dates returns the tracked dates in ascending order.
+func (b OvertimeBalance) Dates() []string {
+	var dates []string
+	for date := range b {
+		dates = append(dates, date)
+	}
+
+	sort.Strings(dates)
+	return dates
+}
+
+// isDayBetween returns true if the day of the date in YYYY-MM-DD format
+// overlaps the period between start and end, including the days the period
+// starts or ends in the middle of.
+func isDayBetween(date string, start time.Time, end time.Time) bool {
+	dayStart, err := time.ParseInLocation(overtimeDateFormat, date, time.Local)
+	if err != nil {
+		return false
+	}
+
+	return dayStart.Before(end) && dayStart.AddDate(0, 0, 1).After(start)
+}
+
+// Between returns the overtime of the days between start and end.
+func (b OvertimeBalance) Between(start time.Time, end time.Time) time.Duration {
+	var balance time.Duration
+
+	for date, day := range b {
+		if isDayBetween(date, start, end) {
+			balance += day.Balance()
+		}
+	}
+
+	return balance
+}
+
+// Actual returns the time spent on the tracked days between start and end.
+func (b OvertimeBalance) Actual(start time.Time, end time.Time) time.Duration {
+	var actual time.Duration
+
+	for date, day := range b {
+		if isDayBetween(date, start, end) {
+			actual += day.Actual
+		}
+	}
+
+	return actual
+}
+
+// Total returns the overtime carried over all tracked days.
+func (b OvertimeBalance) Total() time.Duration {
+	var balance time.Duration
+
+	for _, day := range b {
+		balance += day.Balance()
+	}
+
+	return balance
+}
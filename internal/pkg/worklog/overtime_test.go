@@ -0,0 +1,186 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func getOvertimeTestOpts() *worklog.OvertimeOpts {
+	return &worklog.OvertimeOpts{
+		DailyDuration: time.Hour * 8,
+		WorkingDays:   worklog.DefaultWorkingDays,
+		Holidays:      []string{"2021-10-05"},
+	}
+}
+
+func TestOvertimeOpts_Expected(t *testing.T) {
+	opts := getOvertimeTestOpts()
+
+	require.Equal(t, time.Hour*8, opts.Expected(time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 5, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 9, 0, 0, 0, 0, time.Local)))
+}
+
+func TestOvertimeOpts_Expected_WeekdayDurations(t *testing.T) {
+	opts := getOvertimeTestOpts()
+	opts.WeekdayDurations = map[time.Weekday]time.Duration{
+		time.Tuesday:  time.Hour * 6,
+		time.Friday:   time.Hour * 4,
+		time.Saturday: time.Hour * 2,
+		time.Monday:   0,
+	}
+
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local)))
+	// Holidays are not expected, even if the weekday is
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 5, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*8, opts.Expected(time.Date(2021, 10, 6, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*4, opts.Expected(time.Date(2021, 10, 8, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*2, opts.Expected(time.Date(2021, 10, 9, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 10, 0, 0, 0, 0, time.Local)))
+}
+
+func TestOvertimeOpts_Expected_PartTime(t *testing.T) {
+	opts := getOvertimeTestOpts()
+	opts.EmploymentStart = "2021-10-04"
+	opts.EmploymentEnd = "2021-10-07"
+	opts.PartTime = []worklog.PartTimePeriod{
+		{From: "2021-10-07", Percentage: 50},
+		{From: "2021-01-01", Percentage: 80},
+	}
+
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*6+time.Minute*24, opts.Expected(time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*4, opts.Expected(time.Date(2021, 10, 7, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Duration(0), opts.Expected(time.Date(2021, 10, 8, 0, 0, 0, 0, time.Local)))
+
+	require.Equal(t, 1.0, opts.PartTimeRatio(time.Date(2020, 12, 31, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, 0.8, opts.PartTimeRatio(time.Date(2021, 10, 6, 0, 0, 0, 0, time.Local)))
+}
+
+func TestOvertimeOpts_Target(t *testing.T) {
+	opts := getOvertimeTestOpts()
+	start := time.Date(2021, 10, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, 0)
+
+	// 21 working days, except the holiday
+	require.Equal(t, time.Hour*160, opts.Target(start, end))
+
+	// Joining in the middle of the month and going half-time later
+	opts.EmploymentStart = "2021-10-18"
+	opts.PartTime = []worklog.PartTimePeriod{{From: "2021-10-25", Percentage: 50}}
+	require.Equal(t, time.Hour*60, opts.Target(start, end))
+}
+
+func TestOvertimeBalance_Update(t *testing.T) {
+	start := time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local)
+	end := time.Date(2021, 10, 7, 0, 0, 0, 0, time.Local)
+
+	entries := worklog.Entries{
+		{
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 6,
+		},
+		{
+			Start:              time.Date(2021, 10, 4, 15, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour * 3,
+		},
+		{
+			// Working on a holiday counts as overtime
+			Start:            time.Date(2021, 10, 5, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour,
+		},
+		{
+			Start:              time.Date(2021, 10, 6, 0, 0, 0, 0, time.Local),
+			UnbillableDuration: time.Hour * 4,
+			Absence:            worklog.AbsenceSick,
+		},
+	}
+
+	balance := worklog.OvertimeBalance{}
+	balance.Update(entries, start, end, getOvertimeTestOpts())
+
+	require.Equal(t, worklog.OvertimeBalance{
+		"2021-10-04": {
+			Expected: time.Hour * 8,
+			Actual:   time.Hour * 9,
+			Spent:    map[string]time.Duration{"09:00:00": time.Hour * 6, "15:00:00": time.Hour * 3},
+		},
+		"2021-10-05": {
+			Expected: 0,
+			Actual:   time.Hour,
+			Spent:    map[string]time.Duration{"09:00:00": time.Hour},
+		},
+		"2021-10-06": {
+			Expected: time.Hour * 8,
+			Actual:   time.Hour * 4,
+			Spent:    map[string]time.Duration{"00:00:00": time.Hour * 4},
+		},
+	}, balance)
+
+	require.Equal(t, []string{"2021-10-04", "2021-10-05", "2021-10-06"}, balance.Dates())
+	require.Equal(t, -time.Hour*2, balance.Total())
+	require.Equal(t, time.Hour*2, balance.Between(start, start.AddDate(0, 0, 2)))
+	require.Equal(t, time.Hour*10, balance.Actual(start, start.AddDate(0, 0, 2)))
+
+	// Updating the same period again must not count the time spent twice
+	balance.Update(entries[:2], start, start.AddDate(0, 0, 1), getOvertimeTestOpts())
+	require.Equal(t, -time.Hour*2, balance.Total())
+}
+
+func TestOvertimeBalance_Update_PartialDay(t *testing.T) {
+	morning := worklog.Entries{
+		{
+			Start:            time.Date(2021, 10, 4, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 3,
+		},
+	}
+
+	afternoon := worklog.Entries{
+		{
+			Start:            time.Date(2021, 10, 4, 13, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 4,
+		},
+		{
+			Start:            time.Date(2021, 10, 5, 9, 0, 0, 0, time.Local),
+			BillableDuration: time.Hour * 2,
+		},
+	}
+
+	balance := worklog.OvertimeBalance{}
+	balance.Update(morning, time.Date(2021, 10, 4, 0, 0, 0, 0, time.Local), time.Date(2021, 10, 4, 12, 0, 0, 0, time.Local), getOvertimeTestOpts())
+
+	// Syncing the afternoon keeps the time spent in the morning
+	start := time.Date(2021, 10, 4, 12, 0, 0, 0, time.Local)
+	end := time.Date(2021, 10, 5, 12, 0, 0, 0, time.Local)
+	balance.Update(afternoon, start, end, getOvertimeTestOpts())
+
+	require.Equal(t, time.Hour*7, balance["2021-10-04"].Actual)
+	require.Equal(t, time.Hour*2, balance["2021-10-05"].Actual)
+
+	// Syncing the afternoon again replaces the time spent in the afternoon only
+	afternoon[0].BillableDuration = time.Hour * 5
+	balance.Update(afternoon, start, end, getOvertimeTestOpts())
+
+	require.Equal(t, time.Hour*8, balance["2021-10-04"].Actual)
+	require.Equal(t, time.Hour*2, balance["2021-10-05"].Actual)
+}
+
+func TestOvertimeBalance_Between_PartialDay(t *testing.T) {
+	balance := worklog.OvertimeBalance{
+		"2021-10-03": {Expected: time.Hour * 8, Actual: time.Hour * 4},
+		"2021-10-04": {Expected: time.Hour * 8, Actual: time.Hour * 9},
+		"2021-10-05": {Expected: time.Hour * 8, Actual: time.Hour * 6},
+		"2021-10-06": {Expected: time.Hour * 8, Actual: time.Hour * 5},
+	}
+
+	// The days the period starts or ends in the middle of are included, while
+	// the day the period ends at midnight is not
+	start := time.Date(2021, 10, 4, 12, 0, 0, 0, time.Local)
+	require.Equal(t, -time.Hour, balance.Between(start, time.Date(2021, 10, 5, 18, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour*15, balance.Actual(start, time.Date(2021, 10, 5, 18, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour, balance.Between(start, time.Date(2021, 10, 5, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, time.Hour, balance.Between(start, start.Add(time.Hour)))
+}
@@ -0,0 +1,109 @@
+package worklog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provenance describes where an entry comes from and how it was transformed
+// before uploading, so every uploaded duration can be explained.
+type Provenance struct {
+	// Source is the name of the source the entry was fetched from.
+	Source string `json:"source,omitempty"`
+	// SourceIDs lists the IDs of the source entries. Merged entries have
+	// multiple source IDs.
+	SourceIDs []string `json:"source_ids,omitempty"`
+	// SourceURLs lists the links to review the source entries, like the page
+	// of the report showing the entry. Merged entries have the links of every
+	// source entry.
+	SourceURLs []string `json:"source_urls,omitempty"`
+	// FetchedAt is the time when the entry was fetched.
+	FetchedAt time.Time `json:"fetched_at"`
+	// Transformations lists the transformations applied on the entry, in the
+	// order of applying them.
+	Transformations []string `json:"transformations,omitempty"`
+}
+
+// String returns the human-readable description of the provenance, like
+// "clockify #1, #2 fetched at 2021-10-02T10:00:00Z; mapped by standup".
+func (p Provenance) String() string {
+	var parts []string
+
+	origin := p.Source
+	if origin == "" {
+		origin = "unknown source"
+	}
+
+	if len(p.SourceIDs) > 0 {
+		origin += " #" + strings.Join(p.SourceIDs, ", #")
+	}
+
+	if !p.FetchedAt.IsZero() {
+		origin += " fetched at " + p.FetchedAt.Format(time.RFC3339)
+	}
+
+	parts = append(parts, origin)
+	parts = append(parts, p.Transformations...)
+
+	return strings.Join(parts, "; ")
+}
+
+// AddTransformation records a transformation applied on the entry. Since
+// copies of an entry are sharing the transformations, the transformations are
+// copied before adding the new one.
+func (e *Entry) AddTransformation(format string, args ...interface{}) {
+	transformations := make([]string, len(e.Provenance.Transformations), len(e.Provenance.Transformations)+1)
+	copy(transformations, e.Provenance.Transformations)
+
+	e.Provenance.Transformations = append(transformations, fmt.Sprintf(format, args...))
+}
+
+// AddSourceURL records the link to review a source entry of the entry, unless
+// it is already recorded. The link is added to the links of the entry too, so
+// it is available for the comment templates and exports. Since copies of an
+// entry are sharing the links, the links are copied before adding the new one.
+func (e *Entry) AddSourceURL(sourceURL string) {
+	if sourceURL == "" {
+		return
+	}
+
+	e.AddLinks(sourceURL)
+
+	for _, existing := range e.Provenance.SourceURLs {
+		if existing == sourceURL {
+			return
+		}
+	}
+
+	sourceURLs := make([]string, len(e.Provenance.SourceURLs), len(e.Provenance.SourceURLs)+1)
+	copy(sourceURLs, e.Provenance.SourceURLs)
+
+	e.Provenance.SourceURLs = append(sourceURLs, sourceURL)
+}
+
+// mergeProvenance adds the source IDs, links and transformations of the other
+// entry to the provenance of the entry, when the entries are merged.
+func (e *Entry) mergeProvenance(other *Entry) {
+	sourceIDs := make([]string, 0, len(e.Provenance.SourceIDs)+len(other.Provenance.SourceIDs))
+	sourceIDs = append(sourceIDs, e.Provenance.SourceIDs...)
+	e.Provenance.SourceIDs = append(sourceIDs, other.Provenance.SourceIDs...)
+
+	for _, sourceURL := range other.Provenance.SourceURLs {
+		e.AddSourceURL(sourceURL)
+	}
+
+	for _, transformation := range other.Provenance.Transformations {
+		isPresent := false
+		for _, existing := range e.Provenance.Transformations {
+			if existing == transformation {
+				isPresent = true
+				break
+			}
+		}
+
+		if !isPresent {
+			e.AddTransformation("%s", transformation)
+		}
+	}
+}
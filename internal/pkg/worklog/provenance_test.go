@@ -0,0 +1,64 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance_String(t *testing.T) {
+	fetchedAt := time.Date(2021, 10, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		provenance worklog.Provenance
+		expected   string
+	}{
+		"empty": {
+			provenance: worklog.Provenance{},
+			expected:   "unknown source",
+		},
+		"complete": {
+			provenance: worklog.Provenance{
+				Source:          "clockify",
+				SourceIDs:       []string{"1", "2"},
+				FetchedAt:       fetchedAt,
+				Transformations: []string{"mapped by \"standup\"", "split by tags"},
+			},
+			expected: "clockify #1, #2 fetched at 2021-10-02T10:00:00Z; mapped by \"standup\"; split by tags",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.provenance.String())
+		})
+	}
+}
+
+func TestEntry_AddTransformation(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.AddTransformation("mapped by %q", "standup")
+
+	entryCopy := entry
+	entryCopy.AddTransformation("split by tags")
+
+	require.Equal(t, []string{"mapped by \"standup\""}, entry.Provenance.Transformations)
+	require.Equal(t, []string{"mapped by \"standup\"", "split by tags"}, entryCopy.Provenance.Transformations)
+}
+
+func TestEntry_AddSourceURL(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.AddSourceURL("https://example.com/1")
+	entry.AddSourceURL("")
+	entry.AddSourceURL("https://example.com/1")
+
+	entryCopy := entry
+	entryCopy.AddSourceURL("https://example.com/2")
+
+	require.Equal(t, []string{"https://example.com/1"}, entry.Provenance.SourceURLs)
+	require.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, entryCopy.Provenance.SourceURLs)
+	require.Equal(t, []string{"https://example.com/1"}, entry.Links)
+	require.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, entryCopy.Links)
+}
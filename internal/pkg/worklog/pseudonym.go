@@ -0,0 +1,73 @@
+package worklog
+
+import "fmt"
+
+const (
+	// pseudonymClient is the prefix of the pseudonyms of the clients.
+	pseudonymClient string = "Client"
+	// pseudonymProject is the prefix of the pseudonyms of the projects.
+	pseudonymProject string = "Project"
+)
+
+// Pseudonyms maps the names of the clients and projects to pseudonyms, like
+// "Client 1". The pseudonyms are numbered in the order the names are seen
+// first, hence persisting the pseudonyms keeps them stable between reports.
+type Pseudonyms struct {
+	Clients  map[string]string `json:"clients"`
+	Projects map[string]string `json:"projects"`
+}
+
+// NewPseudonyms returns pseudonyms without any name mapped.
+func NewPseudonyms() *Pseudonyms {
+	return &Pseudonyms{
+		Clients:  map[string]string{},
+		Projects: map[string]string{},
+	}
+}
+
+// pseudonymize returns the field having the pseudonym of its name, or the ID
+// if the name is not set. If the name has no pseudonym yet, the next one is
+// assigned. Empty fields are kept empty.
+func pseudonymize(pseudonyms map[string]string, prefix string, field IDNameField) IDNameField {
+	name := field.Name
+	if name == "" {
+		name = field.ID
+	}
+
+	if name == "" {
+		return field
+	}
+
+	pseudonym, ok := pseudonyms[name]
+	if !ok {
+		pseudonym = fmt.Sprintf("%s %d", prefix, len(pseudonyms)+1)
+		pseudonyms[name] = pseudonym
+	}
+
+	return IDNameField{
+		ID:   pseudonym,
+		Name: pseudonym,
+	}
+}
+
+// Pseudonymize returns the copy of the entries, having the clients and
+// projects replaced by their pseudonyms. The other fields are not changed,
+// therefore the names mentioned by the summaries and notes are kept.
+func (p *Pseudonyms) Pseudonymize(entries Entries) Entries {
+	if p.Clients == nil {
+		p.Clients = map[string]string{}
+	}
+
+	if p.Projects == nil {
+		p.Projects = map[string]string{}
+	}
+
+	pseudonymized := make(Entries, 0, len(entries))
+	for _, entry := range entries {
+		entry.Client = pseudonymize(p.Clients, pseudonymClient, entry.Client)
+		entry.Project = pseudonymize(p.Projects, pseudonymProject, entry.Project)
+		pseudonymized = append(pseudonymized, entry)
+	}
+
+	return pseudonymized
+}
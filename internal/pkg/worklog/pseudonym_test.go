@@ -0,0 +1,65 @@
+package worklog_test
+
+import (
+	"testing"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudonyms_Pseudonymize(t *testing.T) {
+	entries := worklog.Entries{
+		{
+			Client:  worklog.IDNameField{ID: "acme", Name: "ACME Inc."},
+			Project: worklog.IDNameField{ID: "web", Name: "Website"},
+			Summary: "Fix the contact form",
+		},
+		{
+			Client:  worklog.IDNameField{ID: "globex", Name: "Globex"},
+			Project: worklog.IDNameField{ID: "app", Name: "Mobile app"},
+		},
+		{
+			Client:  worklog.IDNameField{ID: "acme", Name: "ACME Inc."},
+			Project: worklog.IDNameField{ID: "internal"},
+		},
+		{
+			Summary: "Daily standup",
+		},
+	}
+
+	pseudonyms := worklog.NewPseudonyms()
+	pseudonymized := pseudonyms.Pseudonymize(entries)
+
+	require.Equal(t, worklog.Entries{
+		{
+			Client:  worklog.IDNameField{ID: "Client 1", Name: "Client 1"},
+			Project: worklog.IDNameField{ID: "Project 1", Name: "Project 1"},
+			Summary: "Fix the contact form",
+		},
+		{
+			Client:  worklog.IDNameField{ID: "Client 2", Name: "Client 2"},
+			Project: worklog.IDNameField{ID: "Project 2", Name: "Project 2"},
+		},
+		{
+			Client:  worklog.IDNameField{ID: "Client 1", Name: "Client 1"},
+			Project: worklog.IDNameField{ID: "Project 3", Name: "Project 3"},
+		},
+		{
+			Summary: "Daily standup",
+		},
+	}, pseudonymized)
+
+	// The original entries are not changed
+	require.Equal(t, "ACME Inc.", entries[0].Client.Name)
+
+	// Known names keep their pseudonyms, new names get the next ones
+	pseudonymized = pseudonyms.Pseudonymize(worklog.Entries{
+		{
+			Client:  worklog.IDNameField{ID: "initech", Name: "Initech"},
+			Project: worklog.IDNameField{ID: "app", Name: "Mobile app"},
+		},
+	})
+
+	require.Equal(t, worklog.IDNameField{ID: "Client 3", Name: "Client 3"}, pseudonymized[0].Client)
+	require.Equal(t, worklog.IDNameField{ID: "Project 2", Name: "Project 2"}, pseudonymized[0].Project)
+}
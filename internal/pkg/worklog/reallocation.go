@@ -0,0 +1,138 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+)
+
+// AttributeReallocation is the entry attribute describing the origin of the
+// time moved by a reallocation, like "10% of Product".
+const AttributeReallocation string = "reallocation"
+
+var (
+	// ErrInvalidPercentage returns when the percentage of a reallocation is
+	// not between 0 (exclusive) and 100 (inclusive).
+	ErrInvalidPercentage = errors.New("reallocation percentage must be between 0 and 100")
+	// ErrNoReallocationTarget returns when a reallocation does not set where
+	// to move the time.
+	ErrNoReallocationTarget = errors.New("reallocation target client, project, task or summary must be set")
+)
+
+// Reallocation represents a rule that moves the Percentage of the time spent
+// on the projects matching the Project regex into another bucket, like logging
+// 10% of the feature work to maintenance. The moved time becomes a new entry,
+// which fields are set by ToClient, ToProject, ToTask and ToSummary; the
+// rest of the fields are copied from the original entry.
+type Reallocation struct {
+	Project    string  `mapstructure:"project" json:"project,omitempty"`
+	Percentage float64 `mapstructure:"percentage" json:"percentage"`
+	ToClient   string  `mapstructure:"to-client" json:"to-client,omitempty"`
+	ToProject  string  `mapstructure:"to-project" json:"to-project,omitempty"`
+	ToTask     string  `mapstructure:"to-task" json:"to-task,omitempty"`
+	ToSummary  string  `mapstructure:"to-summary" json:"to-summary,omitempty"`
+
+	projectRegex *regexp.Regexp
+}
+
+// Compile validates the reallocation and compiles its project regex. Compile
+// must be called before calling Apply.
+func (r *Reallocation) Compile() error {
+	if r.Percentage <= 0 || r.Percentage > 100 {
+		return fmt.Errorf("%v: %v", ErrInvalidPercentage, r.Percentage)
+	}
+
+	if r.ToClient == "" && r.ToProject == "" && r.ToTask == "" && r.ToSummary == "" {
+		return ErrNoReallocationTarget
+	}
+
+	projectRegex, err := regexp.Compile(r.Project)
+	if err != nil {
+		return err
+	}
+
+	r.projectRegex = projectRegex
+	return nil
+}
+
+// matches returns true if the reallocation applies for the entry. Absences
+// and the entries replacing a worklog are never reallocated, since a worklog
+// can be replaced only once.
+func (r *Reallocation) matches(entry *Entry) bool {
+	return r.projectRegex != nil && !entry.IsAbsence() && entry.Replaces == "" && r.projectRegex.MatchString(entry.Project.Name)
+}
+
+// split returns the reallocated part of the duration.
+func (r *Reallocation) split(duration time.Duration) time.Duration {
+	return time.Duration(math.Round(float64(duration) * r.Percentage / 100))
+}
+
+// Apply moves the percentage of the entry's time into a new entry if the
+// project is matching the reallocation. It returns the reallocated entry and
+// true if the reallocation was applied.
+func (r *Reallocation) Apply(entry *Entry) (Entry, bool) {
+	if !r.matches(entry) {
+		return Entry{}, false
+	}
+
+	reallocated := *entry
+	reallocated.BillableDuration = r.split(entry.BillableDuration)
+	reallocated.UnbillableDuration = r.split(entry.UnbillableDuration)
+	reallocated.SetAttribute(AttributeReallocation, fmt.Sprintf("%v%% of %s", r.Percentage, entry.Project.Name))
+
+	if r.ToClient != "" {
+		reallocated.Client = IDNameField{ID: r.ToClient, Name: r.ToClient}
+	}
+
+	if r.ToProject != "" {
+		reallocated.Project = IDNameField{ID: r.ToProject, Name: r.ToProject}
+	}
+
+	if r.ToTask != "" {
+		reallocated.Task = IDNameField{ID: r.ToTask, Name: r.ToTask}
+	}
+
+	if r.ToSummary != "" {
+		reallocated.Summary = r.ToSummary
+	}
+
+	entry.BillableDuration -= reallocated.BillableDuration
+	entry.UnbillableDuration -= reallocated.UnbillableDuration
+
+	reallocated.AddTransformation("reallocated %v%% from %s", r.Percentage, entry.Project.Name)
+	entry.AddTransformation("reallocated %v%% to %s", r.Percentage, reallocated.Project.Name)
+
+	return reallocated, true
+}
+
+// ApplyReallocations applies the first matching reallocation on every entry
+// and returns the remaining and the reallocated entries. If the whole time of
+// an entry is reallocated, only the reallocated entry returns, since the
+// remaining entry has no time left. The reallocations must be compiled before
+// calling it.
+func ApplyReallocations(entries Entries, reallocations []Reallocation) Entries {
+	reallocatedEntries := make(Entries, 0, len(entries))
+
+	for _, entry := range entries {
+		var reallocated Entry
+		isReallocated := false
+
+		for i := range reallocations {
+			if reallocated, isReallocated = reallocations[i].Apply(&entry); isReallocated {
+				break
+			}
+		}
+
+		if !isReallocated || entry.BillableDuration != 0 || entry.UnbillableDuration != 0 {
+			reallocatedEntries = append(reallocatedEntries, entry)
+		}
+
+		if isReallocated {
+			reallocatedEntries = append(reallocatedEntries, reallocated)
+		}
+	}
+
+	return reallocatedEntries
+}
@@ -0,0 +1,152 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReallocation_Compile(t *testing.T) {
+	tests := map[string]struct {
+		reallocation worklog.Reallocation
+		err          error
+	}{
+		"valid": {
+			reallocation: worklog.Reallocation{Project: ".*", Percentage: 10, ToTask: "TASK-1"},
+		},
+		"zero percentage": {
+			reallocation: worklog.Reallocation{Project: ".*", ToTask: "TASK-1"},
+			err:          worklog.ErrInvalidPercentage,
+		},
+		"too high percentage": {
+			reallocation: worklog.Reallocation{Project: ".*", Percentage: 101, ToTask: "TASK-1"},
+			err:          worklog.ErrInvalidPercentage,
+		},
+		"no target": {
+			reallocation: worklog.Reallocation{Project: ".*", Percentage: 10},
+			err:          worklog.ErrNoReallocationTarget,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.reallocation.Compile()
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.ErrorContains(t, err, test.err.Error())
+			}
+		})
+	}
+
+	invalidRegex := worklog.Reallocation{Project: "[a-", Percentage: 10, ToTask: "TASK-1"}
+	require.NotNil(t, invalidRegex.Compile())
+}
+
+func TestReallocation_Apply(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.BillableDuration = time.Hour
+	entry.UnbillableDuration = time.Minute * 30
+
+	reallocation := worklog.Reallocation{
+		Project:    "^Internal",
+		Percentage: 10,
+		ToProject:  "Maintenance",
+		ToTask:     "MAINT-1",
+	}
+
+	require.Nil(t, reallocation.Compile())
+
+	reallocated, ok := reallocation.Apply(&entry)
+	require.True(t, ok)
+
+	require.Equal(t, time.Minute*54, entry.BillableDuration)
+	require.Equal(t, time.Minute*27, entry.UnbillableDuration)
+	require.Equal(t, "Internal projects", entry.Project.Name)
+	require.Nil(t, entry.Attributes)
+
+	require.Equal(t, time.Minute*6, reallocated.BillableDuration)
+	require.Equal(t, time.Minute*3, reallocated.UnbillableDuration)
+	require.Equal(t, worklog.IDNameField{ID: "Maintenance", Name: "Maintenance"}, reallocated.Project)
+	require.Equal(t, worklog.IDNameField{ID: "MAINT-1", Name: "MAINT-1"}, reallocated.Task)
+	require.Equal(t, entry.Client, reallocated.Client)
+	require.Equal(t, entry.Summary, reallocated.Summary)
+	require.Equal(t, entry.Start, reallocated.Start)
+	require.Equal(t, "10% of Internal projects", reallocated.Attributes[worklog.AttributeReallocation])
+}
+
+func TestReallocation_Apply_NotMatching(t *testing.T) {
+	entry := getCompleteTestEntry()
+	absence := getCompleteTestEntry()
+	absence.Absence = worklog.AbsenceVacation
+
+	reallocation := worklog.Reallocation{Project: "^Customer", Percentage: 10, ToTask: "MAINT-1"}
+	require.Nil(t, reallocation.Compile())
+
+	_, ok := reallocation.Apply(&entry)
+	require.False(t, ok)
+
+	reallocation = worklog.Reallocation{Project: ".*", Percentage: 10, ToTask: "MAINT-1"}
+	require.Nil(t, reallocation.Compile())
+
+	_, ok = reallocation.Apply(&absence)
+	require.False(t, ok)
+	require.Equal(t, getCompleteTestEntry().BillableDuration, absence.BillableDuration)
+
+	replacement := getCompleteTestEntry()
+	replacement.Replaces = "123"
+
+	_, ok = reallocation.Apply(&replacement)
+	require.False(t, ok)
+}
+
+func TestApplyReallocations(t *testing.T) {
+	feature := getCompleteTestEntry()
+	feature.Project = worklog.IDNameField{ID: "product", Name: "Product"}
+
+	other := getCompleteTestEntry()
+
+	reallocations := []worklog.Reallocation{
+		{Project: "^Product$", Percentage: 25, ToTask: "MAINT-1", ToSummary: "Maintenance"},
+		{Project: ".*", Percentage: 50, ToTask: "MAINT-2"},
+	}
+
+	for i := range reallocations {
+		require.Nil(t, reallocations[i].Compile())
+	}
+
+	entries := worklog.ApplyReallocations(worklog.Entries{feature, other}, reallocations)
+	require.Len(t, entries, 4)
+
+	require.Equal(t, "TASK-0123", entries[0].Task.Name)
+	require.Equal(t, time.Minute*90, entries[0].BillableDuration)
+	require.Equal(t, "MAINT-1", entries[1].Task.Name)
+	require.Equal(t, "Maintenance", entries[1].Summary)
+	require.Equal(t, time.Minute*30, entries[1].BillableDuration)
+
+	require.Equal(t, time.Hour, entries[2].BillableDuration)
+	require.Equal(t, "MAINT-2", entries[3].Task.Name)
+	require.Equal(t, time.Hour, entries[3].BillableDuration)
+}
+
+func TestApplyReallocations_Whole(t *testing.T) {
+	support := getCompleteTestEntry()
+	support.Project = worklog.IDNameField{ID: "support", Name: "Support"}
+	support.UnbillableDuration = time.Minute * 30
+
+	reallocations := []worklog.Reallocation{
+		{Project: "^Support$", Percentage: 100, ToProject: "Operations"},
+	}
+
+	require.Nil(t, reallocations[0].Compile())
+
+	// The original entry has no time left, so it is not kept as incomplete
+	entries := worklog.ApplyReallocations(worklog.Entries{support}, reallocations)
+	require.Len(t, entries, 1)
+
+	require.Equal(t, "Operations", entries[0].Project.Name)
+	require.Equal(t, support.BillableDuration, entries[0].BillableDuration)
+	require.Equal(t, support.UnbillableDuration, entries[0].UnbillableDuration)
+}
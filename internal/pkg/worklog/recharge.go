@@ -0,0 +1,123 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrNoLegalEntityName returns when a legal entity does not set its name.
+	ErrNoLegalEntityName = errors.New("legal entity name must be set")
+	// ErrInvalidRate returns when the rate of a legal entity is negative.
+	ErrInvalidRate = errors.New("legal entity rate must not be negative")
+)
+
+// LegalEntity represents a legal entity of the company, recharging the
+// billable time spent on the clients matching the Client regex at the hourly
+// Rate, like "ACME UK Ltd" charging 95 GBP per hour.
+type LegalEntity struct {
+	Name     string  `mapstructure:"name" json:"name"`
+	Client   string  `mapstructure:"client" json:"client,omitempty"`
+	Rate     float64 `mapstructure:"rate" json:"rate"`
+	Currency string  `mapstructure:"currency" json:"currency,omitempty"`
+
+	clientRegex *regexp.Regexp
+}
+
+// Compile validates the legal entity and compiles its client regex. Compile
+// must be called before calling Matches.
+func (l *LegalEntity) Compile() error {
+	if l.Name == "" {
+		return ErrNoLegalEntityName
+	}
+
+	if l.Rate < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidRate, l.Name)
+	}
+
+	clientRegex, err := regexp.Compile(l.Client)
+	if err != nil {
+		return err
+	}
+
+	l.clientRegex = clientRegex
+	return nil
+}
+
+// Matches returns true if the entry's client is recharged by the legal entity.
+func (l *LegalEntity) Matches(entry *Entry) bool {
+	return l.clientRegex != nil && l.clientRegex.MatchString(entry.Client.Name)
+}
+
+// Recharge represents the billable time spent on a client, recharged by a
+// legal entity. If no legal entity recharges the client, the LegalEntity is
+// empty and the Amount is zero.
+type Recharge struct {
+	Client      string
+	LegalEntity string
+	Currency    string
+	Rate        float64
+	Billable    time.Duration
+	Amount      float64
+}
+
+// Recharges groups the billable time of the entries by client and the first
+// legal entity recharging the client, then calculates the recharged amounts
+// by the rates of the legal entities. The recharges are ordered by legal
+// entity and client; the clients not recharged by any legal entity are the
+// first. Absences and entries without billable time are skipped. The legal
+// entities must be compiled before calling it.
+func Recharges(entries Entries, legalEntities []LegalEntity) []Recharge {
+	var recharges []Recharge
+	indexes := map[[2]string]int{}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if entry.IsAbsence() || entry.BillableDuration <= 0 {
+			continue
+		}
+
+		var legalEntity *LegalEntity
+		for j := range legalEntities {
+			if legalEntities[j].Matches(entry) {
+				legalEntity = &legalEntities[j]
+				break
+			}
+		}
+
+		recharge := Recharge{Client: entry.Client.Name}
+		if legalEntity != nil {
+			recharge.LegalEntity = legalEntity.Name
+			recharge.Currency = legalEntity.Currency
+			recharge.Rate = legalEntity.Rate
+		}
+
+		key := [2]string{recharge.LegalEntity, recharge.Client}
+		index, ok := indexes[key]
+		if !ok {
+			index = len(recharges)
+			indexes[key] = index
+			recharges = append(recharges, recharge)
+		}
+
+		recharges[index].Billable += entry.BillableDuration
+	}
+
+	for i := range recharges {
+		recharges[i].Amount = recharges[i].Billable.Hours() * recharges[i].Rate
+	}
+
+	sort.SliceStable(recharges, func(i, j int) bool {
+		if recharges[i].LegalEntity != recharges[j].LegalEntity {
+			return recharges[i].LegalEntity < recharges[j].LegalEntity
+		}
+
+		return recharges[i].Client < recharges[j].Client
+	})
+
+	return recharges
+}
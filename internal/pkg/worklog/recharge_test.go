@@ -0,0 +1,59 @@
+package worklog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabor-boros/minutes/internal/pkg/worklog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecharges(t *testing.T) {
+	legalEntities := []worklog.LegalEntity{
+		{Name: "ACME UK Ltd", Client: "^(Globex|Initech)", Rate: 95, Currency: "GBP"},
+		{Name: "ACME GmbH", Client: "GmbH$", Rate: 110, Currency: "EUR"},
+	}
+
+	for i := range legalEntities {
+		require.Nil(t, legalEntities[i].Compile())
+	}
+
+	entry := func(client string, billable time.Duration, unbillable time.Duration) worklog.Entry {
+		return worklog.Entry{
+			Client:             worklog.IDNameField{ID: client, Name: client},
+			BillableDuration:   billable,
+			UnbillableDuration: unbillable,
+		}
+	}
+
+	vacation := entry("Globex", time.Hour*8, 0)
+	vacation.Absence = worklog.AbsenceVacation
+
+	recharges := worklog.Recharges(worklog.Entries{
+		entry("Globex", time.Hour, time.Hour),
+		entry("Umbrella GmbH", time.Minute*30, 0),
+		entry("Initech", time.Hour*2, 0),
+		entry("Globex", time.Minute*30, 0),
+		entry("Internal", time.Hour, 0),
+		entry("Initech", 0, time.Hour),
+		vacation,
+	}, legalEntities)
+
+	require.Equal(t, []worklog.Recharge{
+		{Client: "Internal", Billable: time.Hour},
+		{Client: "Umbrella GmbH", LegalEntity: "ACME GmbH", Currency: "EUR", Rate: 110, Billable: time.Minute * 30, Amount: 55},
+		{Client: "Globex", LegalEntity: "ACME UK Ltd", Currency: "GBP", Rate: 95, Billable: time.Minute * 90, Amount: 142.5},
+		{Client: "Initech", LegalEntity: "ACME UK Ltd", Currency: "GBP", Rate: 95, Billable: time.Hour * 2, Amount: 190},
+	}, recharges)
+}
+
+func TestLegalEntity_Compile(t *testing.T) {
+	legalEntity := worklog.LegalEntity{Client: "Globex", Rate: 95}
+	require.ErrorIs(t, legalEntity.Compile(), worklog.ErrNoLegalEntityName)
+
+	legalEntity = worklog.LegalEntity{Name: "ACME UK Ltd", Client: "Globex", Rate: -1}
+	require.ErrorIs(t, legalEntity.Compile(), worklog.ErrInvalidRate)
+
+	legalEntity = worklog.LegalEntity{Name: "ACME UK Ltd", Client: "["}
+	require.Error(t, legalEntity.Compile())
+}
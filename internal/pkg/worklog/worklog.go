@@ -37,44 +37,72 @@ func isEntryMatching(entry Entry, opts *FilterOpts) bool {
 	return isClientMatching && isProjectMatching
 }
 
-// NewWorklog creates a worklog from the given set of entries and merges them.
-func NewWorklog(entries Entries, opts *FilterOpts) Worklog {
+// FilterEntries returns the entries matching the filter options.
+func FilterEntries(entries Entries, opts *FilterOpts) Entries {
 	var filteredEntries Entries
 
-	worklog := Worklog{}
-	mergedEntries := map[string]Entry{}
-
 	for _, entry := range entries {
 		if isEntryMatching(entry, opts) {
 			filteredEntries = append(filteredEntries, entry)
 		}
 	}
 
-	for _, entry := range filteredEntries {
+	return filteredEntries
+}
+
+// mergeNotes joins the notes of two entries, unless they are the same.
+func mergeNotes(notes string, other string) string {
+	noteSeparator := ""
+	if notes != "" && other != notes {
+		if other != "" {
+			noteSeparator = "; "
+		}
+
+		notes = notes + noteSeparator + other
+	}
+
+	return notes
+}
+
+// MergeEntries merges the entries having the same key by summing their
+// durations and joining their notes, links and provenance. The order of the
+// entries is kept.
+func MergeEntries(entries Entries) Entries {
+	var keys []string
+	mergedEntries := map[string]Entry{}
+
+	for _, entry := range entries {
 		key := entry.Key()
 		storedEntry, isStored := mergedEntries[key]
 
 		if !isStored {
+			keys = append(keys, key)
 			mergedEntries[key] = entry
 			continue
 		}
 
 		storedEntry.BillableDuration += entry.BillableDuration
 		storedEntry.UnbillableDuration += entry.UnbillableDuration
-
-		noteSeparator := ""
-		if storedEntry.Notes != "" && entry.Notes != storedEntry.Notes {
-			if entry.Notes != "" {
-				noteSeparator = "; "
-			}
-
-			storedEntry.Notes = storedEntry.Notes + noteSeparator + entry.Notes
-		}
+		storedEntry.AddLinks(entry.Links...)
+		storedEntry.mergeProvenance(&entry)
+		storedEntry.Notes = mergeNotes(storedEntry.Notes, entry.Notes)
 
 		mergedEntries[key] = storedEntry
 	}
 
-	for _, entry := range mergedEntries {
+	merged := make(Entries, 0, len(keys))
+	for _, key := range keys {
+		merged = append(merged, mergedEntries[key])
+	}
+
+	return merged
+}
+
+// NewWorklog creates a worklog from the given set of entries and merges them.
+func NewWorklog(entries Entries, opts *FilterOpts) Worklog {
+	worklog := Worklog{}
+
+	for _, entry := range MergeEntries(FilterEntries(entries, opts)) {
 		if entry.IsComplete() {
 			worklog.completeEntries = append(worklog.completeEntries, entry)
 		} else {
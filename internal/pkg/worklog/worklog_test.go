@@ -179,3 +179,44 @@ func TestWorklogFilterEntries(t *testing.T) {
 
 	assert.ElementsMatch(t, worklog.Entries{entry1, entry2}, wl.CompleteEntries())
 }
+
+func TestWorklogMergeLinks(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.Links = []string{"https://example.com/1"}
+
+	otherEntry := getCompleteTestEntry()
+	otherEntry.Links = []string{"https://example.com/1", "https://example.com/2"}
+
+	wl := worklog.NewWorklog(worklog.Entries{entry, otherEntry}, &worklog.FilterOpts{})
+
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, wl.CompleteEntries()[0].Links)
+}
+
+func TestWorklogMergeProvenance(t *testing.T) {
+	entry := getCompleteTestEntry()
+	entry.Provenance = worklog.Provenance{SourceIDs: []string{"1"}, SourceURLs: []string{"https://example.com/report"}, Transformations: []string{"mapped by \"standup\""}}
+
+	otherEntry := getCompleteTestEntry()
+	otherEntry.Provenance = worklog.Provenance{SourceIDs: []string{"2"}, SourceURLs: []string{"https://example.com/report", "https://example.com/other"}, Transformations: []string{"mapped by \"standup\"", "split by tags"}}
+
+	wl := worklog.NewWorklog(worklog.Entries{entry, otherEntry}, &worklog.FilterOpts{})
+
+	provenance := wl.CompleteEntries()[0].Provenance
+	assert.Equal(t, []string{"1", "2"}, provenance.SourceIDs)
+	assert.Equal(t, []string{"https://example.com/report", "https://example.com/other"}, provenance.SourceURLs)
+	assert.Equal(t, []string{"mapped by \"standup\"", "split by tags"}, provenance.Transformations)
+	assert.Equal(t, []string{"mapped by \"standup\""}, entry.Provenance.Transformations)
+}
+
+func TestMergeEntries(t *testing.T) {
+	entry := getCompleteTestEntry()
+
+	otherEntry := getCompleteTestEntry()
+	otherEntry.Summary = "other summary"
+
+	merged := worklog.MergeEntries(worklog.Entries{otherEntry, entry, entry})
+
+	assert.Len(t, merged, 2)
+	assert.Equal(t, otherEntry, merged[0])
+	assert.Equal(t, entry.BillableDuration*2, merged[1].BillableDuration)
+}